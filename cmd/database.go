@@ -69,6 +69,46 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] two-factor recovery code table maintained successfully")
 
+	err = datastore.Container.UserStore.SyncStructs(new(models.UserExternalAuth))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user external auth table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.UserWebAuthnCredential))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user passkey table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.UserWebAuthnChallenge))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user passkey challenge table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.AuditEvent))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] audit event table maintained successfully")
+
+	err = datastore.Container.UserStore.SyncStructs(new(models.ExchangeRateHistory))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] exchange rates history table maintained successfully")
+
 	err = datastore.Container.TokenStore.SyncStructs(new(models.TokenRecord))
 
 	if err != nil {
@@ -93,6 +133,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncFullTextIndex(new(models.Transaction), "comment")
+
+	if err != nil {
+		log.BootWarnf(c, "[database.updateAllDatabaseTablesStructure] transaction comment full-text index cannot be maintained, keyword search will fall back to fuzzy matching, because %s", err.Error())
+	} else {
+		log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction comment full-text index maintained successfully")
+	}
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionCategory))
 
 	if err != nil {
@@ -125,6 +173,38 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction template table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionRule))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction rule table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSuggestionModelInfo))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction suggestion model table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSuggestionFeature))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction suggestion feature table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSuggestionTrainedSample))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction suggestion trained sample table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionPictureInfo))
 
 	if err != nil {
@@ -133,6 +213,22 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction picture table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.TransactionSplit))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] transaction split table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.Payee))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] payee table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.UserCustomExchangeRate))
 
 	if err != nil {
@@ -141,6 +237,14 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user custom exchange rate table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.UserDatedCustomExchangeRate))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] user dated custom exchange rate table maintained successfully")
+
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.UserApplicationCloudSetting))
 
 	if err != nil {
@@ -155,6 +259,12 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 		return err
 	}
 
+	err = datastore.Container.UserDataStore.WidenDecimalColumns(new(models.Investment))
+
+	if err != nil {
+		return err
+	}
+
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investment table maintained successfully")
 
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.InvestmentTransaction))
@@ -163,6 +273,12 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 		return err
 	}
 
+	err = datastore.Container.UserDataStore.WidenDecimalColumns(new(models.InvestmentTransaction))
+
+	if err != nil {
+		return err
+	}
+
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] investment transaction table maintained successfully")
 
 	err = datastore.Container.UserDataStore.SyncStructs(new(models.StockPrice))
@@ -173,5 +289,21 @@ func updateAllDatabaseTablesStructure(c *core.CliContext) error {
 
 	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] stock price table maintained successfully")
 
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.FixedIncomeTerm))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] fixed income term table maintained successfully")
+
+	err = datastore.Container.UserDataStore.SyncStructs(new(models.LoanTerm))
+
+	if err != nil {
+		return err
+	}
+
+	log.BootInfof(c, "[database.updateAllDatabaseTablesStructure] loan term table maintained successfully")
+
 	return nil
 }
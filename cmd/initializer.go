@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 
+	"github.com/mayswind/ezbookkeeping/pkg/auth"
 	"github.com/mayswind/ezbookkeeping/pkg/avatars"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
@@ -117,6 +118,15 @@ func initializeSystem(c *core.CliContext) (*settings.Config, error) {
 		return nil, err
 	}
 
+	err = auth.InitializeAuthProviders(config)
+
+	if err != nil {
+		if !isDisableBootLog {
+			log.BootErrorf(c, "[initializer.initializeSystem] initializes external authentication providers failed, because %s", err.Error())
+		}
+		return nil, err
+	}
+
 	err = mail.InitializeMailer(config)
 
 	if err != nil {
@@ -157,6 +167,8 @@ func getConfigWithoutSensitiveData(config *settings.Config) *settings.Config {
 	clonedConfig.MinIOConfig.SecretAccessKey = "****"
 	clonedConfig.SecretKey = "****"
 	clonedConfig.AmapApplicationSecret = "****"
+	clonedConfig.OIDCClientSecret = "****"
+	clonedConfig.LDAPBindPassword = "****"
 
 	if clonedConfig.WebDAVConfig != nil {
 		clonedConfig.WebDAVConfig.Password = "****"
@@ -236,6 +236,44 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-passkey-clear",
+			Usage:  "Delete all passkeys of user",
+			Action: bindAction(clearUserPasskeys),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+			},
+		},
+		{
+			Name:   "user-audit-log",
+			Usage:  "List security audit log of user",
+			Action: bindAction(listUserAuditEvents),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.IntFlag{
+					Name:     "page",
+					Aliases:  []string{"p"},
+					Required: false,
+					Usage:    "Specific page number, default is 1",
+				},
+				&cli.IntFlag{
+					Name:     "count",
+					Aliases:  []string{"c"},
+					Required: false,
+					Usage:    "Specific count of events per page, default is 50",
+				},
+			},
+		},
 		{
 			Name:   "user-session-list",
 			Usage:  "List all user sessions",
@@ -264,7 +302,27 @@ var UserData = &cli.Command{
 					Name:     "type",
 					Aliases:  []string{"t"},
 					Required: false,
-					Usage:    "Specific token type, supports \"normal\" and \"mcp\", default is \"normal\"",
+					Usage:    "Specific token type, supports \"normal\", \"mcp\" and \"personal_access\", default is \"normal\"",
+				},
+				&cli.BoolFlag{
+					Name:     "read-only",
+					Required: false,
+					Usage:    "Create read-only token (only for \"mcp\" token type)",
+				},
+				&cli.StringFlag{
+					Name:     "name",
+					Required: false,
+					Usage:    "Specific token name (only for \"personal_access\" token type)",
+				},
+				&cli.StringFlag{
+					Name:     "permissions",
+					Required: false,
+					Usage:    "Specific token permissions separated by commas, e.g. \"transactions:read,accounts:read\" (only for \"personal_access\" token type)",
+				},
+				&cli.IntFlag{
+					Name:     "expired-in-days",
+					Required: false,
+					Usage:    "Specific token expiration days, 0 means never expired (only for \"personal_access\" token type)",
 				},
 			},
 		},
@@ -379,7 +437,7 @@ var UserData = &cli.Command{
 					Name:     "type",
 					Aliases:  []string{"t"},
 					Required: false,
-					Usage:    "Export file type, support csv or tsv, default is csv",
+					Usage:    "Export file type, support csv, tsv or ledger, default is csv",
 				},
 			},
 		},
@@ -687,6 +745,63 @@ func disableUser2FA(c *core.CliContext) error {
 	return nil
 }
 
+func clearUserPasskeys(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	err = clis.UserData.ClearUserWebAuthnCredentials(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.clearUserPasskeys] error occurs when deleting user passkeys")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.clearUserPasskeys] all passkeys of user \"%s\" have been deleted", username)
+
+	return nil
+}
+
+func listUserAuditEvents(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	page := c.Int("page")
+	count := c.Int("count")
+
+	if page < 1 {
+		page = 1
+	}
+
+	if count < 1 {
+		count = 50
+	}
+
+	events, err := clis.UserData.ListUserAuditEvents(c, username, int32(page), int32(count))
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.listUserAuditEvents] error occurs when getting user audit events")
+		return err
+	}
+
+	for i := 0; i < len(events); i++ {
+		printAuditEventInfo(events[i])
+
+		if i < len(events)-1 {
+			fmt.Printf("---\n")
+		}
+	}
+
+	return nil
+}
+
 func listUserTokens(c *core.CliContext) error {
 	_, err := initializeSystem(c)
 
@@ -722,17 +837,31 @@ func createNewUserToken(c *core.CliContext) error {
 
 	username := c.String("username")
 	tokenType := c.String("type")
+	readOnly := c.Bool("read-only")
+	name := c.String("name")
+	permissions := c.String("permissions")
+	expiredInDays := c.Int("expired-in-days")
 
 	if tokenType == "" {
 		tokenType = "normal"
 	}
 
-	if tokenType != "normal" && tokenType != "mcp" {
+	if tokenType != "normal" && tokenType != "mcp" && tokenType != "personal_access" {
 		log.CliErrorf(c, "[user_data.createNewUserToken] token type is invalid")
 		return nil
 	}
 
-	token, tokenString, err := clis.UserData.CreateNewUserToken(c, username, tokenType)
+	if readOnly && tokenType != "mcp" {
+		log.CliErrorf(c, "[user_data.createNewUserToken] only mcp token supports read-only scope")
+		return nil
+	}
+
+	if (name != "" || permissions != "" || expiredInDays != 0) && tokenType != "personal_access" {
+		log.CliErrorf(c, "[user_data.createNewUserToken] only personal access token supports name, permissions and expiration days")
+		return nil
+	}
+
+	token, tokenString, err := clis.UserData.CreateNewUserToken(c, username, tokenType, readOnly, name, permissions, expiredInDays)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.createNewUserToken] error occurs when creating user token")
@@ -846,7 +975,7 @@ func exportUserTransaction(c *core.CliContext) error {
 		fileType = "csv"
 	}
 
-	if fileType != "csv" && fileType != "tsv" {
+	if fileType != "csv" && fileType != "tsv" && fileType != "ledger" {
 		log.CliErrorf(c, "[user_data.exportUserTransaction] export file type is not supported")
 		return errs.ErrNotSupported
 	}
@@ -979,9 +1108,30 @@ func printUserInfo(user *models.User) {
 	}
 }
 
+func printAuditEventInfo(event *models.AuditEvent) {
+	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(event.CreatedUnixTime), event.CreatedUnixTime)
+	fmt.Printf("[EventType] %s\n", event.EventType)
+	fmt.Printf("[IpAddress] %s\n", event.IpAddress)
+	fmt.Printf("[UserAgent] %s\n", event.UserAgent)
+	fmt.Printf("[RequestId] %s\n", event.RequestId)
+
+	if event.Detail != "" {
+		fmt.Printf("[Detail] %s\n", event.Detail)
+	}
+}
+
 func printTokenInfo(token *models.TokenRecord) {
 	fmt.Printf("[CreatedAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.CreatedUnixTime), token.CreatedUnixTime)
 	fmt.Printf("[ExpiredAt] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.ExpiredUnixTime), token.ExpiredUnixTime)
 	fmt.Printf("[LastSeen] %s (%d)\n", utils.FormatUnixTimeToLongDateTimeInServerTimezone(token.LastSeenUnixTime), token.LastSeenUnixTime)
 	fmt.Printf("[UserAgent] %s\n", token.UserAgent)
+
+	if token.Scope == core.USER_TOKEN_SCOPE_READ_ONLY {
+		fmt.Printf("[Scope] Read Only\n")
+	}
+
+	if token.TokenType == core.USER_TOKEN_TYPE_PERSONAL_ACCESS {
+		fmt.Printf("[Name] %s\n", token.Name)
+		fmt.Printf("[Permissions] %s\n", token.Permissions.String())
+	}
 }
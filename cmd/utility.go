@@ -4,13 +4,17 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/mail"
 	"github.com/mayswind/ezbookkeeping/pkg/requestid"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
@@ -43,6 +47,25 @@ var Utilities = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "exchange-rates-history-backfill",
+			Usage:  "Request and save the historical exchange rates of the current exchange rates data source in the specified date range",
+			Action: bindAction(backfillExchangeRatesHistory),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "start-date",
+					Aliases:  []string{"s"},
+					Required: true,
+					Usage:    "Start date (YYYY-MM-DD)",
+				},
+				&cli.StringFlag{
+					Name:     "end-date",
+					Aliases:  []string{"e"},
+					Required: false,
+					Usage:    "End date (YYYY-MM-DD), default is today",
+				},
+			},
+		},
 	},
 }
 
@@ -102,6 +125,71 @@ func sendTestMail(c *core.CliContext) error {
 	return nil
 }
 
+func backfillExchangeRatesHistory(c *core.CliContext) error {
+	config, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	if !exchangerates.Container.IsHistoricalExchangeRatesSupported() {
+		log.CliErrorf(c, "[utility.backfillExchangeRatesHistory] exchange rates data source \"%s\" does not support historical exchange rates", config.ExchangeRatesDataSource)
+		return errs.ErrExchangeRatesHistoryNotSupported
+	}
+
+	startDate, err := time.ParseInLocation(time.DateOnly, c.String("start-date"), time.UTC)
+
+	if err != nil {
+		log.CliErrorf(c, "[utility.backfillExchangeRatesHistory] start date is invalid")
+		return errs.ErrExchangeRatesDateInvalid
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	endDate := today
+
+	if c.String("end-date") != "" {
+		endDate, err = time.ParseInLocation(time.DateOnly, c.String("end-date"), time.UTC)
+
+		if err != nil {
+			log.CliErrorf(c, "[utility.backfillExchangeRatesHistory] end date is invalid")
+			return errs.ErrExchangeRatesDateInvalid
+		}
+	}
+
+	if endDate.After(today) {
+		endDate = today
+	}
+
+	if startDate.After(endDate) {
+		log.CliErrorf(c, "[utility.backfillExchangeRatesHistory] start date must not be later than end date")
+		return errs.ErrExchangeRatesDateInvalid
+	}
+
+	exchangeRateResps, err := exchangerates.Container.GetExchangeRatesByDateRange(c, 0, config, startDate, endDate)
+
+	if err != nil {
+		log.CliErrorf(c, "[utility.backfillExchangeRatesHistory] failed to get exchange rates from %s to %s, because %s", startDate.Format(time.DateOnly), endDate.Format(time.DateOnly), err.Error())
+		return err
+	}
+
+	savedDates := make(map[string]bool)
+
+	for i := 0; i < len(exchangeRateResps); i++ {
+		exchangeRateHistoryResp, err := services.ExchangeRatesHistory.SaveExchangeRatesResponse(c, config.ExchangeRatesDataSource, exchangeRateResps[i])
+
+		if err != nil {
+			log.CliErrorf(c, "[utility.backfillExchangeRatesHistory] failed to save exchange rates, because %s", err.Error())
+			return err
+		}
+
+		savedDates[exchangeRateHistoryResp.Date] = true
+	}
+
+	log.CliInfof(c, "[utility.backfillExchangeRatesHistory] exchange rates of %d days have been saved", len(savedDates))
+
+	return nil
+}
+
 func printRequestIdInfo(requestId string, requestIdInfo *requestid.RequestIdInfo, newRequestIdInfo *requestid.RequestIdInfo) {
 	fmt.Printf("[RequestId] %s\n", requestId)
 	fmt.Printf("[ServerUniqId] %d (Current Server %d)\n", requestIdInfo.ServerUniqId, newRequestIdInfo.ServerUniqId)
@@ -227,18 +227,35 @@ func startWebServer(c *core.CliContext) error {
 		mcpRoute.Use(bindMiddleware(middlewares.RequestLog))
 		mcpRoute.Use(bindMiddleware(middlewares.MCPServerIpLimit(config)))
 		mcpRoute.Use(bindMiddleware(middlewares.JWTMCPAuthorization))
+		mcpRoute.Use(bindMiddleware(middlewares.MCPSession))
 		{
 			mcpRoute.POST("", bindJSONRPCApi(map[string]core.JSONRPCApiHandlerFunc{
-				"initialize":     api.ModelContextProtocols.InitializeHandler,
-				"resources/list": api.ModelContextProtocols.ListResourcesHandler,
-				"resources/read": api.ModelContextProtocols.ReadResourceHandler,
-				"tools/list":     api.ModelContextProtocols.ListToolsHandler,
-				"tools/call":     api.ModelContextProtocols.CallToolHandler,
-				"ping":           api.ModelContextProtocols.PingHandler,
+				"initialize":               api.ModelContextProtocols.InitializeHandler,
+				"resources/list":           api.ModelContextProtocols.ListResourcesHandler,
+				"resources/templates/list": api.ModelContextProtocols.ListResourceTemplatesHandler,
+				"resources/read":           api.ModelContextProtocols.ReadResourceHandler,
+				"resources/subscribe":      api.ModelContextProtocols.SubscribeResourceHandler,
+				"resources/unsubscribe":    api.ModelContextProtocols.UnsubscribeResourceHandler,
+				"tools/list":               api.ModelContextProtocols.ListToolsHandler,
+				"tools/call":               api.ModelContextProtocols.CallToolHandler,
+				"prompts/list":             api.ModelContextProtocols.ListPromptsHandler,
+				"prompts/get":              api.ModelContextProtocols.GetPromptHandler,
+				"ping":                     api.ModelContextProtocols.PingHandler,
 			}, map[string]int{
 				"notifications/initialized": http.StatusAccepted,
 			}))
-			mcpRoute.GET("", bindApi(api.Default.MethodNotAllowed))
+			mcpRoute.GET("", bindJSONRPCEventStreamApi(api.ModelContextProtocols.NotificationStreamHandler))
+			mcpRoute.DELETE("", bindApi(api.ModelContextProtocols.TerminateSessionHandler))
+		}
+	}
+
+	if config.EnableOIDCAuth {
+		oauth2Route := router.Group("/oauth2")
+		oauth2Route.Use(bindMiddleware(middlewares.RequestId(config)))
+		oauth2Route.Use(bindMiddleware(middlewares.RequestLog))
+		{
+			oauth2Route.GET("/login", bindRedirect(api.OAuth2Authorizations.OIDCLoginHandler))
+			oauth2Route.GET("/callback", bindRedirect(api.OAuth2Authorizations.OIDCCallbackHandler))
 		}
 	}
 
@@ -249,12 +266,32 @@ func startWebServer(c *core.CliContext) error {
 	{
 		apiRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.AuthorizeHandler, config))
 
-		if config.EnableTwoFactor {
+		if config.EnableTwoFactor || config.EnableWebAuthn {
 			twoFactorRoute := apiRoute.Group("/2fa")
 			twoFactorRoute.Use(bindMiddleware(middlewares.JWTTwoFactorAuthorization))
 			{
-				twoFactorRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeHandler, config))
-				twoFactorRoute.POST("/recovery.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeByRecoveryCodeHandler, config))
+				if config.EnableTwoFactor {
+					twoFactorRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeHandler, config))
+					twoFactorRoute.POST("/recovery.json", bindApiWithTokenUpdate(api.Authorizations.TwoFactorAuthorizeByRecoveryCodeHandler, config))
+				}
+
+				if config.EnableWebAuthn {
+					twoFactorRoute.POST("/webauthn/begin.json", bindApi(api.WebAuthnAuthorizations.WebAuthnTwoFactorBeginHandler))
+					twoFactorRoute.POST("/webauthn/authorize.json", bindApiWithTokenUpdate(api.WebAuthnAuthorizations.WebAuthnTwoFactorAuthorizeHandler, config))
+				}
+			}
+		}
+
+		if config.EnableWebAuthn && config.EnableWebAuthnPasswordlessLogin {
+			apiRoute.POST("/webauthn/begin.json", bindApi(api.WebAuthnAuthorizations.WebAuthnLoginBeginHandler))
+			apiRoute.POST("/webauthn/authorize.json", bindApiWithTokenUpdate(api.WebAuthnAuthorizations.WebAuthnAuthorizeHandler, config))
+		}
+
+		if config.EnableOIDCAuth {
+			oauth2AuthorizeRoute := apiRoute.Group("/oauth2")
+			oauth2AuthorizeRoute.Use(bindMiddleware(middlewares.JWTOAuth2CallbackAuthorization))
+			{
+				oauth2AuthorizeRoute.POST("/authorize.json", bindApiWithTokenUpdate(api.OAuth2Authorizations.OIDCAuthorizeHandler, config))
 			}
 		}
 
@@ -290,6 +327,7 @@ func startWebServer(c *core.CliContext) error {
 			// Tokens
 			apiV1Route.GET("/tokens/list.json", bindApi(api.Tokens.TokenListHandler))
 			apiV1Route.POST("/tokens/generate/mcp.json", bindApi(api.Tokens.TokenGenerateMCPHandler))
+			apiV1Route.POST("/tokens/generate/personal_access.json", bindApi(api.Tokens.TokenGeneratePersonalAccessHandler))
 			apiV1Route.POST("/tokens/revoke.json", bindApi(api.Tokens.TokenRevokeHandler))
 			apiV1Route.POST("/tokens/revoke_all.json", bindApi(api.Tokens.TokenRevokeAllHandler))
 			apiV1Route.POST("/tokens/refresh.json", bindApiWithTokenUpdate(api.Tokens.TokenRefreshHandler, config))
@@ -312,6 +350,14 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/users/settings/cloud/update.json", bindApi(api.UserApplicationCloudSettings.ApplicationSettingsUpdateHandler))
 			apiV1Route.POST("/users/settings/cloud/disable.json", bindApi(api.UserApplicationCloudSettings.ApplicationSettingsDisableHandler))
 
+			// External Authentication
+			apiV1Route.GET("/users/external_auth/list.json", bindApi(api.OAuth2Authorizations.UserExternalAuthListHandler))
+
+			if config.EnableOIDCAuth {
+				apiV1Route.POST("/users/external_auth/oidc/link.json", bindApi(api.OAuth2Authorizations.UserOIDCLinkRequestHandler))
+				apiV1Route.POST("/users/external_auth/oidc/unlink.json", bindApi(api.OAuth2Authorizations.UserOIDCUnlinkHandler))
+			}
+
 			// Two-Factor Authorization
 			if config.EnableTwoFactor {
 				apiV1Route.GET("/users/2fa/status.json", bindApi(api.TwoFactorAuthorizations.TwoFactorStatusHandler))
@@ -321,6 +367,18 @@ func startWebServer(c *core.CliContext) error {
 				apiV1Route.POST("/users/2fa/recovery/regenerate.json", bindApi(api.TwoFactorAuthorizations.TwoFactorRecoveryCodeRegenerateHandler))
 			}
 
+			// Passkeys
+			if config.EnableWebAuthn {
+				apiV1Route.GET("/users/webauthn/list.json", bindApi(api.WebAuthnAuthorizations.WebAuthnCredentialListHandler))
+				apiV1Route.POST("/users/webauthn/register/begin.json", bindApi(api.WebAuthnAuthorizations.WebAuthnRegisterBeginHandler))
+				apiV1Route.POST("/users/webauthn/register/finish.json", bindApi(api.WebAuthnAuthorizations.WebAuthnRegisterFinishHandler))
+				apiV1Route.POST("/users/webauthn/modify.json", bindApi(api.WebAuthnAuthorizations.WebAuthnCredentialModifyHandler))
+				apiV1Route.POST("/users/webauthn/delete.json", bindApi(api.WebAuthnAuthorizations.WebAuthnCredentialDeleteHandler))
+			}
+
+			// Security Audit Events
+			apiV1Route.GET("/users/audit_events/list.json", bindApi(api.AuditEvents.AuditEventListHandler))
+
 			// Data
 			apiV1Route.GET("/data/statistics.json", bindApi(api.DataManagements.DataStatisticsHandler))
 			apiV1Route.POST("/data/clear/all.json", bindApi(api.DataManagements.ClearAllDataHandler))
@@ -329,6 +387,7 @@ func startWebServer(c *core.CliContext) error {
 			if config.EnableDataExport {
 				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler))
 				apiV1Route.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler))
+				apiV1Route.GET("/data/export.ledger", bindPlainText(api.DataManagements.ExportDataToLedgerHandler))
 			}
 
 			// Accounts
@@ -341,6 +400,20 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/accounts/delete.json", bindApi(api.Accounts.AccountDeleteHandler))
 			apiV1Route.POST("/accounts/sub_account/delete.json", bindApi(api.Accounts.SubAccountDeleteHandler))
 
+			// Fixed Income Terms
+			apiV1Route.GET("/accounts/fixed_income/get.json", bindApi(api.FixedIncomes.FixedIncomeTermGetHandler))
+			apiV1Route.POST("/accounts/fixed_income/set.json", bindApi(api.FixedIncomes.FixedIncomeTermSetHandler))
+			apiV1Route.POST("/accounts/fixed_income/delete.json", bindApi(api.FixedIncomes.FixedIncomeTermDeleteHandler))
+			apiV1Route.GET("/accounts/fixed_income/projections.json", bindApi(api.FixedIncomes.FixedIncomeProjectionsHandler))
+
+			// Loan Terms
+			apiV1Route.GET("/accounts/loan/get.json", bindApi(api.Loans.LoanTermGetHandler))
+			apiV1Route.POST("/accounts/loan/set.json", bindApi(api.Loans.LoanTermSetHandler))
+			apiV1Route.POST("/accounts/loan/delete.json", bindApi(api.Loans.LoanTermDeleteHandler))
+			apiV1Route.GET("/accounts/loan/schedule.json", bindApi(api.Loans.LoanAmortizationScheduleHandler))
+			apiV1Route.POST("/accounts/loan/what_if.json", bindApi(api.Loans.LoanWhatIfHandler))
+			apiV1Route.POST("/accounts/loan/payment.json", bindApi(api.Loans.LoanPaymentCreateHandler))
+
 			// Transactions
 			apiV1Route.GET("/transactions/count.json", bindApi(api.Transactions.TransactionCountHandler))
 			apiV1Route.GET("/transactions/list.json", bindApi(api.Transactions.TransactionListHandler))
@@ -350,6 +423,7 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.GET("/transactions/statistics/trends.json", bindApi(api.Transactions.TransactionStatisticsTrendsHandler))
 			apiV1Route.GET("/transactions/amounts.json", bindApi(api.Transactions.TransactionAmountsHandler))
 			apiV1Route.GET("/transactions/get.json", bindApi(api.Transactions.TransactionGetHandler))
+			apiV1Route.GET("/transactions/suggestions.json", bindApi(api.TransactionSuggestions.TransactionSuggestionHandler))
 			apiV1Route.POST("/transactions/add.json", bindApi(api.Transactions.TransactionCreateHandler))
 			apiV1Route.POST("/transactions/modify.json", bindApi(api.Transactions.TransactionModifyHandler))
 			apiV1Route.POST("/transactions/delete.json", bindApi(api.Transactions.TransactionDeleteHandler))
@@ -387,6 +461,15 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/transaction/tags/move.json", bindApi(api.TransactionTags.TagMoveHandler))
 			apiV1Route.POST("/transaction/tags/delete.json", bindApi(api.TransactionTags.TagDeleteHandler))
 
+			// Payees
+			apiV1Route.GET("/payees/list.json", bindApi(api.Payees.PayeeListHandler))
+			apiV1Route.GET("/payees/get.json", bindApi(api.Payees.PayeeGetHandler))
+			apiV1Route.POST("/payees/add.json", bindApi(api.Payees.PayeeCreateHandler))
+			apiV1Route.POST("/payees/modify.json", bindApi(api.Payees.PayeeModifyHandler))
+			apiV1Route.POST("/payees/hide.json", bindApi(api.Payees.PayeeHideHandler))
+			apiV1Route.POST("/payees/merge.json", bindApi(api.Payees.PayeeMergeHandler))
+			apiV1Route.POST("/payees/delete.json", bindApi(api.Payees.PayeeDeleteHandler))
+
 			// Transaction Templates
 			apiV1Route.GET("/transaction/templates/list.json", bindApi(api.TransactionTemplates.TemplateListHandler))
 			apiV1Route.GET("/transaction/templates/get.json", bindApi(api.TransactionTemplates.TemplateGetHandler))
@@ -396,10 +479,24 @@ func startWebServer(c *core.CliContext) error {
 			apiV1Route.POST("/transaction/templates/move.json", bindApi(api.TransactionTemplates.TemplateMoveHandler))
 			apiV1Route.POST("/transaction/templates/delete.json", bindApi(api.TransactionTemplates.TemplateDeleteHandler))
 
+			// Transaction Rules
+			apiV1Route.GET("/transaction/rules/list.json", bindApi(api.TransactionRules.RuleListHandler))
+			apiV1Route.GET("/transaction/rules/get.json", bindApi(api.TransactionRules.RuleGetHandler))
+			apiV1Route.POST("/transaction/rules/add.json", bindApi(api.TransactionRules.RuleCreateHandler))
+			apiV1Route.POST("/transaction/rules/modify.json", bindApi(api.TransactionRules.RuleModifyHandler))
+			apiV1Route.POST("/transaction/rules/disable.json", bindApi(api.TransactionRules.RuleDisableHandler))
+			apiV1Route.POST("/transaction/rules/move.json", bindApi(api.TransactionRules.RuleMoveHandler))
+			apiV1Route.POST("/transaction/rules/delete.json", bindApi(api.TransactionRules.RuleDeleteHandler))
+			apiV1Route.POST("/transaction/rules/test.json", bindApi(api.TransactionRules.RuleTestHandler))
+
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/history.json", bindApi(api.ExchangeRates.HistoricalExchangeRateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/update.json", bindApi(api.ExchangeRates.UserCustomExchangeRateUpdateHandler))
 			apiV1Route.POST("/exchange_rates/user_custom/delete.json", bindApi(api.ExchangeRates.UserCustomExchangeRateDeleteHandler))
+			apiV1Route.GET("/exchange_rates/user_custom/dated/list.json", bindApi(api.ExchangeRates.UserDatedCustomExchangeRateListHandler))
+			apiV1Route.POST("/exchange_rates/user_custom/dated/update.json", bindApi(api.ExchangeRates.UserDatedCustomExchangeRateUpdateHandler))
+			apiV1Route.POST("/exchange_rates/user_custom/dated/delete.json", bindApi(api.ExchangeRates.UserDatedCustomExchangeRateDeleteHandler))
 
 			// System
 			apiV1Route.GET("/systems/version.json", bindApi(api.Systems.VersionHandler))
@@ -519,6 +616,30 @@ func bindEventStreamApi(fn core.EventStreamApiHandlerFunc) gin.HandlerFunc {
 	}
 }
 
+func bindJSONRPCEventStreamApi(fn core.EventStreamApiHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		err := fn(c)
+
+		if err != nil && !c.Writer.Written() {
+			utils.PrintJsonErrorResult(c, err)
+		}
+	}
+}
+
+func bindRedirect(fn core.RedirectHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, err := fn(c)
+
+		if err != nil {
+			utils.PrintJsonErrorResult(c, err)
+		} else {
+			c.Redirect(http.StatusFound, result)
+		}
+	}
+}
+
 func bindCachedJs(fn core.DataHandlerFunc, store persistence.CacheStore) gin.HandlerFunc {
 	return cache.CachePage(store, time.Minute, func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
@@ -558,6 +679,19 @@ func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 	}
 }
 
+func bindPlainText(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "text/plain", fileName, result)
+		}
+	}
+}
+
 func bindImage(fn core.ImageHandlerFunc) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// AuditEventsApi represents security audit event api
+type AuditEventsApi struct {
+	auditEvents *services.AuditEventService
+}
+
+// Initialize a security audit event api singleton instance
+var (
+	AuditEvents = &AuditEventsApi{
+		auditEvents: services.AuditEvents,
+	}
+)
+
+// AuditEventListHandler returns security audit event list of current user
+func (a *AuditEventsApi) AuditEventListHandler(c *core.WebContext) (any, *errs.Error) {
+	var auditEventListReq models.AuditEventListRequest
+	err := c.ShouldBindQuery(&auditEventListReq)
+
+	if err != nil {
+		log.Warnf(c, "[audit_events.AuditEventListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if auditEventListReq.Page < 1 {
+		auditEventListReq.Page = 1
+	}
+
+	uid := c.GetCurrentUid()
+	totalCount, err := a.auditEvents.GetAuditEventCountByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[audit_events.AuditEventListHandler] failed to get audit event count for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	events, err := a.auditEvents.GetAuditEventsByUid(c, uid, auditEventListReq.Page, auditEventListReq.Count)
+
+	if err != nil {
+		log.Errorf(c, "[audit_events.AuditEventListHandler] failed to get audit events for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	eventResps := make([]*models.AuditEventInfoResponse, len(events))
+
+	for i := 0; i < len(events); i++ {
+		eventResps[i] = events[i].ToAuditEventInfoResponse()
+	}
+
+	return &models.AuditEventInfoPageWrapperResponse{
+		Items:      eventResps,
+		TotalCount: totalCount,
+	}, nil
+}
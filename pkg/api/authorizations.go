@@ -22,6 +22,8 @@ type AuthorizationsApi struct {
 	userAppCloudSettings    *services.UserApplicationCloudSettingsService
 	tokens                  *services.TokenService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
+	userWebAuthnCredentials *services.UserWebAuthnCredentialService
+	auditEvents             *services.AuditEventService
 }
 
 // Initialize a authorization api singleton instance
@@ -48,6 +50,8 @@ var (
 		userAppCloudSettings:    services.UserApplicationCloudSettings,
 		tokens:                  services.Tokens,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
+		userWebAuthnCredentials: services.UserWebAuthnCredentials,
+		auditEvents:             services.AuditEvents,
 	}
 )
 
@@ -81,6 +85,7 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.WebContext) (any, *errs.Err
 
 	if err != nil {
 		log.Warnf(c, "[authorizations.AuthorizeHandler] login failed for user \"%s\", because %s", credential.LoginName, err.Error())
+		a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_LOGIN_FAILED, "password")
 		return nil, errs.ErrLoginNameOrPasswordWrong
 	}
 
@@ -111,17 +116,15 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.WebContext) (any, *errs.Err
 		log.Warnf(c, "[authorizations.AuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
 	}
 
-	twoFactorEnable := a.tokens.CurrentConfig().EnableTwoFactor
+	twoFactorMethods, err := getUserTwoFactorMethods(c, a.CurrentConfig(), a.twoFactorAuthorizations, a.userWebAuthnCredentials, user.Uid)
 
-	if twoFactorEnable {
-		twoFactorEnable, err = a.twoFactorAuthorizations.ExistsTwoFactorSetting(c, user.Uid)
-
-		if err != nil {
-			log.Errorf(c, "[authorizations.AuthorizeHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
-			return nil, errs.Or(err, errs.ErrSystemError)
-		}
+	if err != nil {
+		log.Errorf(c, "[authorizations.AuthorizeHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrSystemError)
 	}
 
+	twoFactorEnable := len(twoFactorMethods) > 0
+
 	var token string
 	var claims *core.UserTokenClaims
 
@@ -153,7 +156,13 @@ func (a *AuthorizationsApi) AuthorizeHandler(c *core.WebContext) (any, *errs.Err
 
 	log.Infof(c, "[authorizations.AuthorizeHandler] user \"uid:%d\" has logined, token type is %d, token will be expired at %d", user.Uid, claims.Type, claims.ExpiresAt)
 
-	authResp := a.getAuthResponse(c, token, twoFactorEnable, user, applicationCloudSettingSlice)
+	if twoFactorEnable {
+		a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_LOGIN, "password, two-factor required")
+	} else {
+		a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_LOGIN, "password")
+	}
+
+	authResp := a.getAuthResponse(c, token, twoFactorMethods, user, applicationCloudSettingSlice)
 	return authResp, nil
 }
 
@@ -184,6 +193,7 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeHandler(c *core.WebContext) (any,
 
 	if !totp.Validate(credential.Passcode, twoFactorSetting.Secret) {
 		log.Warnf(c, "[authorizations.TwoFactorAuthorizeHandler] passcode is invalid for user \"uid:%d\"", uid)
+		a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFY_FAILED, "passcode")
 
 		err = a.CheckAndIncreaseFailureCount(c, uid)
 
@@ -239,8 +249,9 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeHandler(c *core.WebContext) (any,
 	}
 
 	log.Infof(c, "[authorizations.TwoFactorAuthorizeHandler] user \"uid:%d\" has authorized two-factor via passcode, token will be expired at %d", user.Uid, claims.ExpiresAt)
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFIED, "passcode")
 
-	authResp := a.getAuthResponse(c, token, false, user, applicationCloudSettingSlice)
+	authResp := a.getAuthResponse(c, token, nil, user, applicationCloudSettingSlice)
 	return authResp, nil
 }
 
@@ -303,6 +314,7 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.WebC
 
 	if err != nil {
 		log.Warnf(c, "[authorizations.TwoFactorAuthorizeByRecoveryCodeHandler] failed to get two-factor recovery code for user \"uid:%d\", because %s", uid, err.Error())
+		a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFY_FAILED, "recovery code")
 		return nil, errs.Or(err, errs.ErrTwoFactorRecoveryCodeNotExist)
 	}
 
@@ -333,17 +345,45 @@ func (a *AuthorizationsApi) TwoFactorAuthorizeByRecoveryCodeHandler(c *core.WebC
 	}
 
 	log.Infof(c, "[authorizations.TwoFactorAuthorizeByRecoveryCodeHandler] user \"uid:%d\" has authorized two-factor via recovery code \"%s\", token will be expired at %d", user.Uid, credential.RecoveryCode, claims.ExpiresAt)
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFIED, "recovery code")
 
-	authResp := a.getAuthResponse(c, token, false, user, applicationCloudSettingSlice)
+	authResp := a.getAuthResponse(c, token, nil, user, applicationCloudSettingSlice)
 	return authResp, nil
 }
 
-func (a *AuthorizationsApi) getAuthResponse(c *core.WebContext, token string, need2FA bool, user *models.User, applicationCloudSettings *models.ApplicationCloudSettingSlice) *models.AuthResponse {
+func (a *AuthorizationsApi) getAuthResponse(c *core.WebContext, token string, twoFactorMethods []string, user *models.User, applicationCloudSettings *models.ApplicationCloudSettingSlice) *models.AuthResponse {
 	return &models.AuthResponse{
 		Token:                    token,
-		Need2FA:                  need2FA,
+		Need2FA:                  len(twoFactorMethods) > 0,
+		TwoFactorMethods:         twoFactorMethods,
 		User:                     a.GetUserBasicInfo(user),
 		ApplicationCloudSettings: applicationCloudSettings,
 		NotificationContent:      a.GetAfterLoginNotificationContent(user.Language, c.GetClientLocale()),
 	}
 }
+
+func getUserTwoFactorMethods(c *core.WebContext, config *settings.Config, twoFactorAuthorizations *services.TwoFactorAuthorizationService, userWebAuthnCredentials *services.UserWebAuthnCredentialService, uid int64) ([]string, error) {
+	var twoFactorMethods []string
+
+	if config.EnableTwoFactor {
+		exists, err := twoFactorAuthorizations.ExistsTwoFactorSetting(c, uid)
+
+		if err != nil {
+			return nil, err
+		} else if exists {
+			twoFactorMethods = append(twoFactorMethods, models.TWO_FACTOR_METHOD_PASSCODE)
+		}
+	}
+
+	if config.EnableWebAuthn {
+		exists, err := userWebAuthnCredentials.ExistsCredential(c, uid)
+
+		if err != nil {
+			return nil, err
+		} else if exists {
+			twoFactorMethods = append(twoFactorMethods, models.TWO_FACTOR_METHOD_WEBAUTHN)
+		}
+	}
+
+	return twoFactorMethods, nil
+}
@@ -26,9 +26,13 @@ type DataManagementsApi struct {
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	payees                  *services.PayeeService
 	pictures                *services.TransactionPictureService
 	templates               *services.TransactionTemplateService
+	rules                   *services.TransactionRuleService
+	suggestions             *services.TransactionSuggestionService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
+	auditEvents             *services.AuditEventService
 }
 
 // Initialize a data management api singleton instance
@@ -43,9 +47,13 @@ var (
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		payees:                  services.Payees,
 		pictures:                services.TransactionPictures,
 		templates:               services.TransactionTemplates,
+		rules:                   services.TransactionRules,
+		suggestions:             services.TransactionSuggestions,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
+		auditEvents:             services.AuditEvents,
 	}
 )
 
@@ -59,6 +67,11 @@ func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebCont
 	return a.getExportedFileContent(c, "tsv")
 }
 
+// ExportDataToLedgerHandler returns exported data in ledger journal format
+func (a *DataManagementsApi) ExportDataToLedgerHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ledger")
+}
+
 // DataStatisticsHandler returns user data statistics
 func (a *DataManagementsApi) DataStatisticsHandler(c *core.WebContext) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
@@ -160,6 +173,20 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.rules.DeleteAllRules(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all transaction rules, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.suggestions.DeleteModel(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete transaction suggestion model, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	err = a.transactions.DeleteAllTransactions(c, uid, true)
 
 	if err != nil {
@@ -181,6 +208,13 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.payees.DeleteAllPayees(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearAllDataHandler] failed to delete all payees, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	err = a.userCustomExchangeRates.DeleteAllCustomExchangeRates(c, uid)
 
 	if err != nil {
@@ -189,6 +223,7 @@ func (a *DataManagementsApi) ClearAllDataHandler(c *core.WebContext) (any, *errs
 	}
 
 	log.Infof(c, "[data_managements.ClearAllDataHandler] user \"uid:%d\" has cleared all data", uid)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_ALL_DATA_CLEARED, "")
 	return true, nil
 }
 
@@ -229,6 +264,7 @@ func (a *DataManagementsApi) ClearAllTransactionsHandler(c *core.WebContext) (an
 	}
 
 	log.Infof(c, "[data_managements.ClearAllTransactionsHandler] user \"uid:%d\" has cleared all transactions", uid)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_ALL_TRANSACTIONS_CLEARED, "")
 	return true, nil
 }
 
@@ -327,6 +363,13 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		}
 	}
 
+	allPayeeIds, err := a.payees.GetPayeeIds(exportTransactionDataReq.PayeeIds)
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.ExportDataHandler] get payee ids error, because %s", err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	minTransactionTime := int64(0)
 
@@ -338,13 +381,20 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 		minTransactionTime = utils.GetMinTransactionTimeFromUnixTime(exportTransactionDataReq.MinTime)
 	}
 
-	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, exportTransactionDataReq.TagFilterType, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, true)
+	allTransactions, err := a.transactions.GetAllSpecifiedTransactions(c, uid, maxTransactionTime, minTransactionTime, exportTransactionDataReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, exportTransactionDataReq.TagFilterType, allPayeeIds, exportTransactionDataReq.AmountFilter, exportTransactionDataReq.Keyword, pageCountForDataExport, true)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ExportDataHandler] failed to all transactions user \"uid:%d\", because %s", uid, err.Error())
 		return nil, "", errs.ErrOperationFailed
 	}
 
+	err = a.transactions.FillTransactionSplits(c, uid, allTransactions)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get transaction split lines for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
 	dataExporter := converters.GetTransactionDataExporter(fileType)
 
 	if dataExporter == nil {
@@ -359,6 +409,7 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 	}
 
 	fileName := a.getFileName(user, timezone, fileType)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_DATA_EXPORTED, fileType)
 
 	return result, fileName, nil
 }
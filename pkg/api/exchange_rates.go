@@ -1,7 +1,12 @@
 package api
 
 import (
+	"fmt"
+	"sort"
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
@@ -10,11 +15,16 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
+const historicalExchangeRatesMaxRequestsPerUserPerMinute = 30
+const historicalExchangeRatesMaxStaleDays = 7
+
 // ExchangeRatesApi represents exchange rate api
 type ExchangeRatesApi struct {
 	ApiUsingConfig
+	ApiUsingDuplicateChecker
 	users                   *services.UserService
 	userCustomExchangeRates *services.UserCustomExchangeRatesService
+	exchangeRatesHistory    *services.ExchangeRatesHistoryService
 }
 
 // Initialize a exchange rate api singleton instance
@@ -23,8 +33,15 @@ var (
 		ApiUsingConfig: ApiUsingConfig{
 			container: settings.Container,
 		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
 		users:                   services.Users,
 		userCustomExchangeRates: services.UserCustomExchangeRates,
+		exchangeRatesHistory:    services.ExchangeRatesHistory,
 	}
 )
 
@@ -39,6 +56,56 @@ func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *
 	return exchangeRateResponse, nil
 }
 
+// HistoricalExchangeRateHandler returns the stored exchange rate data published on or before the specified date
+func (a *ExchangeRatesApi) HistoricalExchangeRateHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	requestCountKey := fmt.Sprintf("exchange_rates_history|%d", uid)
+	requestCount := a.ApiUsingDuplicateChecker.container.GetFailureCount(requestCountKey)
+
+	if requestCount >= historicalExchangeRatesMaxRequestsPerUserPerMinute {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] request count of user \"uid:%d\" has reached the limit", uid)
+		return nil, errs.ErrTooManyExchangeRatesHistoryRequests
+	}
+
+	a.ApiUsingDuplicateChecker.container.IncreaseFailureCount(requestCountKey)
+
+	var historicalExchangeRateReq models.HistoricalExchangeRateRequest
+	err := c.ShouldBindQuery(&historicalExchangeRateReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, historicalExchangeRateReq.Date, time.UTC)
+
+	if err != nil || date.After(time.Now()) {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] date \"%s\" is invalid", historicalExchangeRateReq.Date)
+		return nil, errs.ErrExchangeRatesDateInvalid
+	}
+
+	dataSource := a.CurrentConfig().ExchangeRatesDataSource
+
+	if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return nil, errs.ErrExchangeRatesHistoryNotSupported
+	}
+
+	exchangeRates, err := a.exchangeRatesHistory.GetExchangeRatesByDate(c, dataSource, date.Format(time.DateOnly))
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.HistoricalExchangeRateHandler] failed to get stored exchange rates of %s for user \"uid:%d\", because %s", date.Format(time.DateOnly), uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	exchangeRateResponse := models.ToHistoricalExchangeRateResponse(exchangeRates)
+
+	if exchangeRateResponse == nil || exchangeRateResponse.Date < date.AddDate(0, 0, -historicalExchangeRatesMaxStaleDays).Format(time.DateOnly) {
+		return nil, errs.ErrExchangeRatesHistoryNotFound
+	}
+
+	return exchangeRateResponse, nil
+}
+
 // UserCustomExchangeRateUpdateHandler updates user custom exchange rates data by request parameters for current user
 func (a *ExchangeRatesApi) UserCustomExchangeRateUpdateHandler(c *core.WebContext) (any, *errs.Error) {
 	var customExchangeRateUpdateReq models.UserCustomExchangeRateUpdateRequest
@@ -104,3 +171,92 @@ func (a *ExchangeRatesApi) UserCustomExchangeRateDeleteHandler(c *core.WebContex
 	log.Infof(c, "[exchange_rates.UserCustomExchangeRateDeleteHandler] user \"uid:%d\" has deleted user custom exchange rate \"currency:%s\"", uid, customExchangeRateDeleteReq.Currency)
 	return true, nil
 }
+
+// UserDatedCustomExchangeRateListHandler returns user dated custom exchange rates list of current user
+func (a *ExchangeRatesApi) UserDatedCustomExchangeRateListHandler(c *core.WebContext) (any, *errs.Error) {
+	var datedCustomExchangeRateListReq models.UserDatedCustomExchangeRateListRequest
+	err := c.ShouldBindQuery(&datedCustomExchangeRateListReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.UserDatedCustomExchangeRateListHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	datedCustomExchangeRates, err := a.userCustomExchangeRates.GetAllDatedCustomExchangeRatesByUid(c, uid, datedCustomExchangeRateListReq.Currency)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserDatedCustomExchangeRateListHandler] failed to get user dated custom exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	datedCustomExchangeRateResps := make(models.UserDatedCustomExchangeRateInfoResponseSlice, len(datedCustomExchangeRates))
+
+	for i := 0; i < len(datedCustomExchangeRates); i++ {
+		datedCustomExchangeRateResps[i] = datedCustomExchangeRates[i].ToUserDatedCustomExchangeRateInfoResponse()
+	}
+
+	sort.Sort(datedCustomExchangeRateResps)
+
+	return datedCustomExchangeRateResps, nil
+}
+
+// UserDatedCustomExchangeRateUpdateHandler updates user dated custom exchange rate data of the specified effective date by request parameters for current user
+func (a *ExchangeRatesApi) UserDatedCustomExchangeRateUpdateHandler(c *core.WebContext) (any, *errs.Error) {
+	var datedCustomExchangeRateUpdateReq models.UserDatedCustomExchangeRateUpdateRequest
+	err := c.ShouldBindJSON(&datedCustomExchangeRateUpdateReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.UserDatedCustomExchangeRateUpdateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if _, err := time.Parse(time.DateOnly, datedCustomExchangeRateUpdateReq.EffectiveDate); err != nil {
+		log.Warnf(c, "[exchange_rates.UserDatedCustomExchangeRateUpdateHandler] effective date \"%s\" is invalid", datedCustomExchangeRateUpdateReq.EffectiveDate)
+		return nil, errs.ErrUserCustomExchangeRateEffectiveDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserDatedCustomExchangeRateUpdateHandler] failed to get user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if datedCustomExchangeRateUpdateReq.Currency == user.DefaultCurrency {
+		return nil, errs.ErrCannotUpdateExchangeRateForDefaultCurrency
+	}
+
+	datedCustomExchangeRate, err := a.userCustomExchangeRates.UpdateDatedCustomExchangeRate(c, uid, datedCustomExchangeRateUpdateReq.Currency, datedCustomExchangeRateUpdateReq.EffectiveDate, datedCustomExchangeRateUpdateReq.Rate, user.DefaultCurrency)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserDatedCustomExchangeRateUpdateHandler] failed to update user dated custom exchange rate \"currency:%s\" of %s for user \"uid:%d\", because %s", datedCustomExchangeRateUpdateReq.Currency, datedCustomExchangeRateUpdateReq.EffectiveDate, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[exchange_rates.UserDatedCustomExchangeRateUpdateHandler] user \"uid:%d\" has updated user dated custom exchange rate \"currency:%s\" of %s successfully", uid, datedCustomExchangeRateUpdateReq.Currency, datedCustomExchangeRateUpdateReq.EffectiveDate)
+	return datedCustomExchangeRate.ToUserDatedCustomExchangeRateInfoResponse(), nil
+}
+
+// UserDatedCustomExchangeRateDeleteHandler deletes an existed user dated custom exchange rate data by request parameters for current user
+func (a *ExchangeRatesApi) UserDatedCustomExchangeRateDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var datedCustomExchangeRateDeleteReq models.UserDatedCustomExchangeRateDeleteRequest
+	err := c.ShouldBindJSON(&datedCustomExchangeRateDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.UserDatedCustomExchangeRateDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.userCustomExchangeRates.DeleteDatedCustomExchangeRate(c, uid, datedCustomExchangeRateDeleteReq.Currency, datedCustomExchangeRateDeleteReq.EffectiveDate)
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.UserDatedCustomExchangeRateDeleteHandler] failed to delete user dated custom exchange rate \"currency:%s\" of %s for user \"uid:%d\", because %s", datedCustomExchangeRateDeleteReq.Currency, datedCustomExchangeRateDeleteReq.EffectiveDate, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[exchange_rates.UserDatedCustomExchangeRateDeleteHandler] user \"uid:%d\" has deleted user dated custom exchange rate \"currency:%s\" of %s", uid, datedCustomExchangeRateDeleteReq.Currency, datedCustomExchangeRateDeleteReq.EffectiveDate)
+	return true, nil
+}
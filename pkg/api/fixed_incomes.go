@@ -0,0 +1,424 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// FixedIncomesApi represents fixed income term api
+type FixedIncomesApi struct {
+	fixedIncomes          *services.FixedIncomeService
+	accounts              *services.AccountService
+	investments           *services.InvestmentService
+	transactionCategories *services.TransactionCategoryService
+}
+
+// Initialize a fixed income term api singleton instance
+var (
+	FixedIncomes = &FixedIncomesApi{
+		fixedIncomes:          services.FixedIncomes,
+		accounts:              services.Accounts,
+		investments:           services.Investments,
+		transactionCategories: services.TransactionCategories,
+	}
+)
+
+// FixedIncomeTermGetHandler returns the fixed income term of the specified account or bond holding of current user
+func (a *FixedIncomesApi) FixedIncomeTermGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var termGetReq models.FixedIncomeTermGetRequest
+	err := c.ShouldBindQuery(&termGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	termId := termGetReq.AccountId
+
+	if termGetReq.InvestmentId > 0 {
+		termId = termGetReq.InvestmentId
+	}
+
+	if termId <= 0 {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermGetHandler] neither account id nor investment id is specified")
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	term, err := a.fixedIncomes.GetFixedIncomeTermByAccountId(c, uid, termId)
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeTermGetHandler] failed to get fixed income term \"id:%d\" for user \"uid:%d\", because %s", termId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	termResp, err := term.ToFixedIncomeTermInfoResponse()
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeTermGetHandler] failed to calculate fixed income term \"id:%d\" for user \"uid:%d\", because %s", termId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return termResp, nil
+}
+
+// FixedIncomeTermSetHandler saves the fixed income term of the specified account by request parameters for current user
+func (a *FixedIncomesApi) FixedIncomeTermSetHandler(c *core.WebContext) (any, *errs.Error) {
+	var termSetReq models.FixedIncomeTermSetRequest
+	err := c.ShouldBindJSON(&termSetReq)
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	interestRate, err := models.ParseFixedIncomeInterestRate(termSetReq.InterestRate)
+
+	if err != nil || interestRate < 0 || interestRate > models.FixedIncomeMaxInterestRate*models.FixedIncomeInterestRateFactorInDatabase {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] interest rate \"%s\" is invalid", termSetReq.InterestRate)
+		return nil, errs.ErrFixedIncomeInterestRateInvalid
+	}
+
+	if !termSetReq.CompoundingFrequency.IsValid() {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] compounding frequency \"%d\" is invalid", termSetReq.CompoundingFrequency)
+		return nil, errs.ErrFixedIncomeCompoundingFrequencyInvalid
+	}
+
+	if !termSetReq.PayoutFrequency.IsValid() {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] payout frequency \"%d\" is invalid", termSetReq.PayoutFrequency)
+		return nil, errs.ErrFixedIncomePayoutFrequencyInvalid
+	}
+
+	startDate, err := time.Parse(time.DateOnly, termSetReq.StartDate)
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] start date \"%s\" is invalid", termSetReq.StartDate)
+		return nil, errs.ErrFixedIncomeStartDateInvalid
+	}
+
+	maturityDate, err := time.Parse(time.DateOnly, termSetReq.MaturityDate)
+
+	if err != nil || !maturityDate.After(startDate) {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] maturity date \"%s\" is invalid", termSetReq.MaturityDate)
+		return nil, errs.ErrFixedIncomeMaturityDateInvalid
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	termId := int64(0)
+	currency := ""
+	payoutAccountId := int64(0)
+
+	if termSetReq.InvestmentId > 0 {
+		// the interest of bond holdings (coupons) is always paid out to another account
+		if termSetReq.PayoutAccountId <= 0 {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] payout account is not specified for investment \"id:%d\"", termSetReq.InvestmentId)
+			return nil, errs.ErrFixedIncomePayoutAccountRequired
+		}
+
+		investmentMap, err := a.investments.GetInvestmentsByInvestmentIds(c, uid, []int64{termSetReq.InvestmentId})
+
+		if err != nil {
+			log.Errorf(c, "[fixed_incomes.FixedIncomeTermSetHandler] failed to get investment for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		investment, exists := investmentMap[termSetReq.InvestmentId]
+
+		if !exists {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] investment \"id:%d\" does not exist for user \"uid:%d\"", termSetReq.InvestmentId, uid)
+			return nil, errs.ErrInvestmentNotFound
+		}
+
+		if investment.AssetType != models.INVESTMENT_ASSET_TYPE_BOND {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] investment \"id:%d\" asset type \"%d\" does not support fixed income term", termSetReq.InvestmentId, investment.AssetType)
+			return nil, errs.ErrFixedIncomeInvestmentAssetTypeInvalid
+		}
+
+		termId = investment.InvestmentId
+		currency = investment.Currency
+	} else if termSetReq.AccountId > 0 {
+		accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{termSetReq.AccountId})
+
+		if err != nil {
+			log.Errorf(c, "[fixed_incomes.FixedIncomeTermSetHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		account, exists := accountMap[termSetReq.AccountId]
+
+		if !exists {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] account \"id:%d\" does not exist for user \"uid:%d\"", termSetReq.AccountId, uid)
+			return nil, errs.ErrAccountNotFound
+		}
+
+		if account.Category != models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT && account.Category != models.ACCOUNT_CATEGORY_INVESTMENT {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] account \"id:%d\" category \"%d\" does not support fixed income term", termSetReq.AccountId, account.Category)
+			return nil, errs.ErrFixedIncomeAccountCategoryInvalid
+		}
+
+		if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] account \"id:%d\" is not a single account", termSetReq.AccountId)
+			return nil, errs.ErrAccountTypeInvalid
+		}
+
+		termId = account.AccountId
+		currency = account.Currency
+	} else {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] neither account id nor investment id is specified")
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	if termSetReq.PayoutAccountId > 0 && termSetReq.PayoutAccountId != termId {
+		accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{termSetReq.PayoutAccountId})
+
+		if err != nil {
+			log.Errorf(c, "[fixed_incomes.FixedIncomeTermSetHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		payoutAccount, exists := accountMap[termSetReq.PayoutAccountId]
+
+		if !exists {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] payout account \"id:%d\" does not exist for user \"uid:%d\"", termSetReq.PayoutAccountId, uid)
+			return nil, errs.ErrAccountNotFound
+		}
+
+		if payoutAccount.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] payout account \"id:%d\" is not a single account", termSetReq.PayoutAccountId)
+			return nil, errs.ErrAccountTypeInvalid
+		}
+
+		if payoutAccount.Currency != currency {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] payout account \"id:%d\" currency \"%s\" does not match the currency \"%s\"", termSetReq.PayoutAccountId, payoutAccount.Currency, currency)
+			return nil, errs.ErrFixedIncomePayoutAccountCurrencyInvalid
+		}
+
+		payoutAccountId = payoutAccount.AccountId
+	}
+
+	category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, termSetReq.InterestCategoryId)
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] failed to get interest category \"id:%d\" for user \"uid:%d\", because %s", termSetReq.InterestCategoryId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if category.Type != models.CATEGORY_TYPE_INCOME || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermSetHandler] interest category \"id:%d\" is not a secondary income category", termSetReq.InterestCategoryId)
+		return nil, errs.ErrFixedIncomeInterestCategoryInvalid
+	}
+
+	term := &models.FixedIncomeTerm{
+		AccountId:            termId,
+		InvestmentId:         termSetReq.InvestmentId,
+		Uid:                  uid,
+		Principal:            termSetReq.Principal,
+		InterestRate:         interestRate,
+		CompoundingFrequency: termSetReq.CompoundingFrequency,
+		PayoutFrequency:      termSetReq.PayoutFrequency,
+		StartDate:            termSetReq.StartDate,
+		MaturityDate:         termSetReq.MaturityDate,
+		InterestCategoryId:   category.CategoryId,
+		PayoutAccountId:      payoutAccountId,
+		TimezoneUtcOffset:    utcOffset,
+	}
+
+	// interests which were due before the term is created are assumed to be already included in the account balance
+	today := utils.FormatUnixTimeToLongDate(time.Now().Unix(), time.FixedZone("Client Timezone", int(utcOffset)*60))
+	duePayouts, err := term.GetDueInterestPayouts(today)
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeTermSetHandler] failed to calculate due interests of fixed income term \"id:%d\" for user \"uid:%d\", because %s", termId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if len(duePayouts) > 0 {
+		term.LastPayoutDate = duePayouts[len(duePayouts)-1].PayoutDate
+	}
+
+	err = a.fixedIncomes.SetFixedIncomeTerm(c, term)
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeTermSetHandler] failed to save fixed income term \"id:%d\" for user \"uid:%d\", because %s", termId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[fixed_incomes.FixedIncomeTermSetHandler] user \"uid:%d\" has saved fixed income term \"id:%d\" successfully", uid, termId)
+
+	termResp, err := term.ToFixedIncomeTermInfoResponse()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return termResp, nil
+}
+
+// FixedIncomeTermDeleteHandler deletes the fixed income term of the specified account for current user
+func (a *FixedIncomesApi) FixedIncomeTermDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var termDeleteReq models.FixedIncomeTermDeleteRequest
+	err := c.ShouldBindJSON(&termDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	termId := termDeleteReq.AccountId
+
+	if termDeleteReq.InvestmentId > 0 {
+		termId = termDeleteReq.InvestmentId
+	}
+
+	if termId <= 0 {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeTermDeleteHandler] neither account id nor investment id is specified")
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.fixedIncomes.DeleteFixedIncomeTerm(c, uid, termId)
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeTermDeleteHandler] failed to delete fixed income term \"id:%d\" for user \"uid:%d\", because %s", termId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[fixed_incomes.FixedIncomeTermDeleteHandler] user \"uid:%d\" has deleted fixed income term \"id:%d\"", uid, termId)
+	return true, nil
+}
+
+// FixedIncomeProjectionsHandler returns the projected maturity values and upcoming maturities of all fixed income terms of current user
+func (a *FixedIncomesApi) FixedIncomeProjectionsHandler(c *core.WebContext) (any, *errs.Error) {
+	var maturityListReq models.FixedIncomeMaturityListRequest
+	err := c.ShouldBindQuery(&maturityListReq)
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeProjectionsHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	terms, err := a.fixedIncomes.GetAllFixedIncomeTermsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeProjectionsHandler] failed to get fixed income terms for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountIds := make([]int64, 0, len(terms))
+	investmentIds := make([]int64, 0)
+
+	for i := 0; i < len(terms); i++ {
+		if terms[i].IsBondHolding() {
+			investmentIds = append(investmentIds, terms[i].InvestmentId)
+		} else {
+			accountIds = append(accountIds, terms[i].AccountId)
+		}
+	}
+
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, accountIds)
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeProjectionsHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	investmentMap, err := a.investments.GetInvestmentsByInvestmentIds(c, uid, investmentIds)
+
+	if err != nil {
+		log.Errorf(c, "[fixed_incomes.FixedIncomeProjectionsHandler] failed to get investments for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[fixed_incomes.FixedIncomeProjectionsHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	today, _ := time.Parse(time.DateOnly, utils.FormatUnixTimeToLongDate(time.Now().Unix(), time.FixedZone("Client Timezone", int(utcOffset)*60)))
+	maturities := make(models.FixedIncomeMaturityInfoResponseSlice, 0, len(terms))
+
+	for i := 0; i < len(terms); i++ {
+		term := terms[i]
+		name := ""
+		currency := ""
+
+		if term.IsBondHolding() {
+			investment, exists := investmentMap[term.InvestmentId]
+
+			if !exists {
+				continue
+			}
+
+			name = investment.CompanyName
+			currency = investment.Currency
+
+			if name == "" {
+				name = investment.TickerSymbol
+			}
+		} else {
+			account, exists := accountMap[term.AccountId]
+
+			if !exists {
+				continue
+			}
+
+			name = account.Name
+			currency = account.Currency
+		}
+
+		maturityDate, err := time.Parse(time.DateOnly, term.MaturityDate)
+
+		if err != nil || maturityDate.Before(today) {
+			continue
+		}
+
+		daysToMaturity := int32(maturityDate.Sub(today).Hours() / 24)
+
+		if maturityListReq.Days > 0 && daysToMaturity > maturityListReq.Days {
+			continue
+		}
+
+		maturityValue, err := term.GetProjectedMaturityValue()
+
+		if err != nil {
+			log.Warnf(c, "[fixed_incomes.FixedIncomeProjectionsHandler] failed to calculate maturity value of fixed income term \"id:%d\" for user \"uid:%d\", because %s", term.AccountId, uid, err.Error())
+			continue
+		}
+
+		maturities = append(maturities, &models.FixedIncomeMaturityInfoResponse{
+			AccountId:              term.AccountId,
+			InvestmentId:           term.InvestmentId,
+			AccountName:            name,
+			Currency:               currency,
+			Principal:              term.Principal,
+			InterestRate:           utils.Float64ToString(term.GetInterestRate()),
+			MaturityDate:           term.MaturityDate,
+			DaysToMaturity:         daysToMaturity,
+			ProjectedMaturityValue: maturityValue,
+			ProjectedTotalInterest: maturityValue - term.Principal,
+		})
+	}
+
+	sort.Sort(maturities)
+
+	return &models.FixedIncomeProjectionsResponse{
+		Maturities: maturities,
+	}, nil
+}
@@ -17,6 +17,7 @@ type ForgetPasswordsApi struct {
 	users           *services.UserService
 	tokens          *services.TokenService
 	forgetPasswords *services.ForgetPasswordService
+	auditEvents     *services.AuditEventService
 }
 
 // Initialize a user api singleton instance
@@ -28,6 +29,7 @@ var (
 		users:           services.Users,
 		tokens:          services.Tokens,
 		forgetPasswords: services.ForgetPasswords,
+		auditEvents:     services.AuditEvents,
 	}
 )
 
@@ -76,6 +78,8 @@ func (a *ForgetPasswordsApi) UserForgetPasswordRequestHandler(c *core.WebContext
 		return nil, errs.ErrTokenGenerating
 	}
 
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_PASSWORD_RESET_REQUESTED, "")
+
 	go func() {
 		err = a.forgetPasswords.SendPasswordResetEmail(c, user, token, c.GetClientLocale())
 
@@ -151,6 +155,8 @@ func (a *ForgetPasswordsApi) UserResetPasswordHandler(c *core.WebContext) (any,
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_PASSWORD_RESET, "")
+
 	now := time.Now().Unix()
 	err = a.tokens.DeleteTokensBeforeTime(c, uid, now)
 
@@ -0,0 +1,387 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// LoansApi represents loan term api
+type LoansApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	loans                 *services.LoanService
+	users                 *services.UserService
+	accounts              *services.AccountService
+	transactions          *services.TransactionService
+	transactionCategories *services.TransactionCategoryService
+}
+
+// Initialize a loan term api singleton instance
+var (
+	Loans = &LoansApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		loans:                 services.Loans,
+		users:                 services.Users,
+		accounts:              services.Accounts,
+		transactions:          services.Transactions,
+		transactionCategories: services.TransactionCategories,
+	}
+)
+
+// LoanTermGetHandler returns the loan term of the specified account of current user
+func (a *LoansApi) LoanTermGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var termGetReq models.LoanTermGetRequest
+	err := c.ShouldBindQuery(&termGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanTermGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	term, err := a.loans.GetLoanTermByAccountId(c, uid, termGetReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanTermGetHandler] failed to get loan term of account \"id:%d\" for user \"uid:%d\", because %s", termGetReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return term.ToLoanTermInfoResponse(), nil
+}
+
+// LoanTermSetHandler saves the loan term of the specified account by request parameters for current user
+func (a *LoansApi) LoanTermSetHandler(c *core.WebContext) (any, *errs.Error) {
+	var termSetReq models.LoanTermSetRequest
+	err := c.ShouldBindJSON(&termSetReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanTermSetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	interestRate, err := models.ParseLoanInterestRate(termSetReq.InterestRate)
+
+	if err != nil || interestRate < 0 || interestRate > models.LoanMaxInterestRate*models.LoanInterestRateFactorInDatabase {
+		log.Warnf(c, "[loans.LoanTermSetHandler] interest rate \"%s\" is invalid", termSetReq.InterestRate)
+		return nil, errs.ErrLoanInterestRateInvalid
+	}
+
+	if _, err := time.Parse(time.DateOnly, termSetReq.StartDate); err != nil {
+		log.Warnf(c, "[loans.LoanTermSetHandler] start date \"%s\" is invalid", termSetReq.StartDate)
+		return nil, errs.ErrLoanStartDateInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	account, err := a.accounts.GetAccountByAccountId(c, uid, termSetReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanTermSetHandler] failed to get account \"id:%d\" for user \"uid:%d\", because %s", termSetReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if account.Category != models.ACCOUNT_CATEGORY_DEBT {
+		log.Warnf(c, "[loans.LoanTermSetHandler] account \"id:%d\" category \"%d\" does not support loan term", termSetReq.AccountId, account.Category)
+		return nil, errs.ErrLoanAccountCategoryInvalid
+	}
+
+	if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		log.Warnf(c, "[loans.LoanTermSetHandler] account \"id:%d\" is not a single account", termSetReq.AccountId)
+		return nil, errs.ErrAccountTypeInvalid
+	}
+
+	category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, termSetReq.InterestCategoryId)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanTermSetHandler] failed to get interest category \"id:%d\" for user \"uid:%d\", because %s", termSetReq.InterestCategoryId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if category.Type != models.CATEGORY_TYPE_EXPENSE || category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		log.Warnf(c, "[loans.LoanTermSetHandler] interest category \"id:%d\" is not a secondary expense category", termSetReq.InterestCategoryId)
+		return nil, errs.ErrLoanInterestCategoryInvalid
+	}
+
+	term := &models.LoanTerm{
+		AccountId:           account.AccountId,
+		Uid:                 uid,
+		Principal:           termSetReq.Principal,
+		InterestRate:        interestRate,
+		TermMonths:          termSetReq.TermMonths,
+		StartDate:           termSetReq.StartDate,
+		PaymentDay:          termSetReq.PaymentDay,
+		ExtraMonthlyPayment: termSetReq.ExtraMonthlyPayment,
+		InterestCategoryId:  category.CategoryId,
+	}
+
+	err = a.loans.SetLoanTerm(c, term)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanTermSetHandler] failed to save loan term of account \"id:%d\" for user \"uid:%d\", because %s", termSetReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[loans.LoanTermSetHandler] user \"uid:%d\" has saved loan term of account \"id:%d\" successfully", uid, termSetReq.AccountId)
+
+	return term.ToLoanTermInfoResponse(), nil
+}
+
+// LoanTermDeleteHandler deletes the loan term of the specified account for current user
+func (a *LoansApi) LoanTermDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var termDeleteReq models.LoanTermDeleteRequest
+	err := c.ShouldBindJSON(&termDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanTermDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.loans.DeleteLoanTerm(c, uid, termDeleteReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanTermDeleteHandler] failed to delete loan term of account \"id:%d\" for user \"uid:%d\", because %s", termDeleteReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[loans.LoanTermDeleteHandler] user \"uid:%d\" has deleted loan term of account \"id:%d\"", uid, termDeleteReq.AccountId)
+	return true, nil
+}
+
+// LoanAmortizationScheduleHandler returns the amortization schedule of the loan term of the specified account of current user
+func (a *LoansApi) LoanAmortizationScheduleHandler(c *core.WebContext) (any, *errs.Error) {
+	var termGetReq models.LoanTermGetRequest
+	err := c.ShouldBindQuery(&termGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanAmortizationScheduleHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	term, err := a.loans.GetLoanTermByAccountId(c, uid, termGetReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanAmortizationScheduleHandler] failed to get loan term of account \"id:%d\" for user \"uid:%d\", because %s", termGetReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	schedule, summary, err := term.GetAmortizationSchedule(nil)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanAmortizationScheduleHandler] failed to calculate amortization schedule of account \"id:%d\" for user \"uid:%d\", because %s", termGetReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return &models.LoanAmortizationScheduleResponse{
+		Term:     term.ToLoanTermInfoResponse(),
+		Summary:  summary,
+		Schedule: schedule,
+	}, nil
+}
+
+// LoanWhatIfHandler returns the payoff date and interest saved of the loan term of the specified account with additional extra payments
+func (a *LoansApi) LoanWhatIfHandler(c *core.WebContext) (any, *errs.Error) {
+	var whatIfReq models.LoanWhatIfRequest
+	err := c.ShouldBindJSON(&whatIfReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanWhatIfHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	for i := 0; i < len(whatIfReq.ExtraPayments); i++ {
+		if _, err := time.Parse(time.DateOnly, whatIfReq.ExtraPayments[i].Date); err != nil {
+			log.Warnf(c, "[loans.LoanWhatIfHandler] extra payment date \"%s\" is invalid", whatIfReq.ExtraPayments[i].Date)
+			return nil, errs.ErrLoanExtraPaymentDateInvalid
+		}
+	}
+
+	uid := c.GetCurrentUid()
+	term, err := a.loans.GetLoanTermByAccountId(c, uid, whatIfReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanWhatIfHandler] failed to get loan term of account \"id:%d\" for user \"uid:%d\", because %s", whatIfReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	_, baseline, err := term.GetAmortizationSchedule(nil)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanWhatIfHandler] failed to calculate baseline amortization schedule of account \"id:%d\" for user \"uid:%d\", because %s", whatIfReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	// the extra monthly payment in request is paid in addition to the extra monthly payment of the loan term
+	term.ExtraMonthlyPayment += whatIfReq.ExtraMonthlyPayment
+	_, scenario, err := term.GetAmortizationSchedule(whatIfReq.ExtraPayments)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanWhatIfHandler] failed to calculate what-if amortization schedule of account \"id:%d\" for user \"uid:%d\", because %s", whatIfReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return &models.LoanWhatIfResponse{
+		Baseline:      baseline,
+		Scenario:      scenario,
+		InterestSaved: baseline.TotalInterest - scenario.TotalInterest,
+		PaymentsSaved: baseline.PaymentCount - scenario.PaymentCount,
+	}, nil
+}
+
+// LoanPaymentCreateHandler saves a loan payment as a principal transfer and an interest expense by request parameters for current user
+func (a *LoansApi) LoanPaymentCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var paymentCreateReq models.LoanPaymentCreateRequest
+	err := c.ShouldBindJSON(&paymentCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[loans.LoanPaymentCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[loans.LoanPaymentCreateHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if !user.CanEditTransactionByTransactionTime(utils.GetMinTransactionTimeFromUnixTime(paymentCreateReq.Time), paymentCreateReq.UtcOffset) {
+		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
+	}
+
+	term, err := a.loans.GetLoanTermByAccountId(c, uid, paymentCreateReq.AccountId)
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanPaymentCreateHandler] failed to get loan term of account \"id:%d\" for user \"uid:%d\", because %s", paymentCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accountMap, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{paymentCreateReq.AccountId, paymentCreateReq.SourceAccountId})
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanPaymentCreateHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	loanAccount, exists := accountMap[paymentCreateReq.AccountId]
+
+	if !exists {
+		log.Warnf(c, "[loans.LoanPaymentCreateHandler] loan account \"id:%d\" does not exist for user \"uid:%d\"", paymentCreateReq.AccountId, uid)
+		return nil, errs.ErrAccountNotFound
+	}
+
+	sourceAccount, exists := accountMap[paymentCreateReq.SourceAccountId]
+
+	if !exists {
+		log.Warnf(c, "[loans.LoanPaymentCreateHandler] source account \"id:%d\" does not exist for user \"uid:%d\"", paymentCreateReq.SourceAccountId, uid)
+		return nil, errs.ErrSourceAccountNotFound
+	}
+
+	if loanAccount.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT || sourceAccount.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+		log.Warnf(c, "[loans.LoanPaymentCreateHandler] loan account \"id:%d\" or source account \"id:%d\" is a parent account", paymentCreateReq.AccountId, paymentCreateReq.SourceAccountId)
+		return nil, errs.ErrCannotAddTransactionToParentAccount
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && paymentCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_LOAN_PAYMENT, uid, paymentCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[loans.LoanPaymentCreateHandler] another loan payment \"%s\" has been created for user \"uid:%d\"", remark, uid)
+			paymentResp, err := a.getExistedLoanPaymentResponse(c, uid, remark)
+
+			if err != nil {
+				log.Errorf(c, "[loans.LoanPaymentCreateHandler] failed to get existed loan payment \"%s\" for user \"uid:%d\", because %s", remark, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			return paymentResp, nil
+		}
+	}
+
+	transferTransaction, interestTransaction, err := a.loans.CreateLoanPayment(c, term, loanAccount, sourceAccount, paymentCreateReq.TransferCategoryId, paymentCreateReq.Amount, paymentCreateReq.Time, paymentCreateReq.UtcOffset, paymentCreateReq.Comment, c.ClientIP())
+
+	if err != nil {
+		log.Errorf(c, "[loans.LoanPaymentCreateHandler] failed to create loan payment of account \"id:%d\" for user \"uid:%d\", because %s", paymentCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	paymentResp := &models.LoanPaymentCreateResponse{}
+
+	if transferTransaction != nil {
+		paymentResp.Principal = transferTransaction.Amount
+		paymentResp.TransferTransactionId = transferTransaction.TransactionId
+	}
+
+	if interestTransaction != nil {
+		paymentResp.Interest = interestTransaction.Amount
+		paymentResp.InterestTransactionId = interestTransaction.TransactionId
+	}
+
+	log.Infof(c, "[loans.LoanPaymentCreateHandler] user \"uid:%d\" has created loan payment of account \"id:%d\" successfully, principal is %d and interest is %d", uid, paymentCreateReq.AccountId, paymentResp.Principal, paymentResp.Interest)
+	mcp.Sessions.NotifyTransactionsUpdated(uid, utils.GetMinTransactionTimeFromUnixTime(paymentCreateReq.Time))
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_LOAN_PAYMENT, uid, paymentCreateReq.ClientSessionId, fmt.Sprintf("%d,%d", paymentResp.TransferTransactionId, paymentResp.InterestTransactionId))
+
+	return paymentResp, nil
+}
+
+func (a *LoansApi) getExistedLoanPaymentResponse(c *core.WebContext, uid int64, remark string) (*models.LoanPaymentCreateResponse, error) {
+	transactionIds := strings.Split(remark, ",")
+
+	if len(transactionIds) != 2 {
+		return nil, errs.ErrOperationFailed
+	}
+
+	paymentResp := &models.LoanPaymentCreateResponse{}
+
+	for i := 0; i < len(transactionIds); i++ {
+		transactionId, err := utils.StringToInt64(transactionIds[i])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if transactionId <= 0 {
+			continue
+		}
+
+		transaction, err := a.transactions.GetTransactionByTransactionId(c, uid, transactionId)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			paymentResp.Principal = transaction.Amount
+			paymentResp.TransferTransactionId = transaction.TransactionId
+		} else {
+			paymentResp.Interest = transaction.Amount
+			paymentResp.InterestTransactionId = transaction.TransactionId
+		}
+	}
+
+	return paymentResp, nil
+}
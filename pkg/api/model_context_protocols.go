@@ -2,6 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -16,6 +19,7 @@ import (
 )
 
 const mcpServerName = "ezBookkeeping-mcp"
+const mcpEventStreamKeepAliveInterval = 30 * time.Second
 
 // ModelContextProtocolAPI represents model context protocol api
 type ModelContextProtocolAPI struct {
@@ -23,9 +27,13 @@ type ModelContextProtocolAPI struct {
 	transactions          *services.TransactionService
 	transactionCategories *services.TransactionCategoryService
 	transactionTags       *services.TransactionTagService
+	payees                *services.PayeeService
 	accounts              *services.AccountService
 	users                 *services.UserService
 	tokens                *services.TokenService
+	investments           *services.InvestmentService
+	stockPrices           *services.StockPriceService
+	exchangeRatesHistory  *services.ExchangeRatesHistoryService
 }
 
 // Initialize a model context protocol api singleton instance
@@ -37,9 +45,13 @@ var (
 		transactions:          services.Transactions,
 		transactionCategories: services.TransactionCategories,
 		transactionTags:       services.TransactionTags,
+		payees:                services.Payees,
 		accounts:              services.Accounts,
 		users:                 services.Users,
 		tokens:                services.Tokens,
+		investments:           services.Investments,
+		stockPrices:           services.StockPrices,
+		exchangeRatesHistory:  services.ExchangeRatesHistory,
 	}
 )
 
@@ -95,10 +107,26 @@ func (a *ModelContextProtocolAPI) InitializeHandler(c *core.WebContext, jsonRPCR
 		protocolVersion = mcp.LatestSupportedMCPVersion
 	}
 
+	session, err := mcp.Sessions.CreateSession(uid, tokenClaims.UserTokenId, string(protocolVersion))
+
+	if err != nil {
+		log.Errorf(c, "[model_context_protocols.InitializeHandler] failed to create mcp session for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	c.Header(mcp.MCPSessionIdHeaderName, session.Id)
+
 	initResp := mcp.MCPInitializeResponse{
 		ProtocolVersion: string(protocolVersion),
 		Capabilities: &mcp.MCPCapabilities{
+			Resources: &mcp.MCPResourceCapabilities{
+				Subscribe:   true,
+				ListChanged: true,
+			},
 			Tools: &mcp.MCPToolCapabilities{
+				ListChanged: true,
+			},
+			Prompts: &mcp.MCPPromptCapabilities{
 				ListChanged: false,
 			},
 		},
@@ -126,13 +154,37 @@ func (a *ModelContextProtocolAPI) ListResourcesHandler(c *core.WebContext, jsonR
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
+	resources := mcp.Container.GetMCPResources()
+	resources = append(resources, mcp.MCPMonthlySummaryResourceHandler.GetCurrentMonthResource())
+
 	listResourcesResp := mcp.MCPListResourcesResponse{
-		Resources: make([]*mcp.MCPResource, 0),
+		Resources: resources,
 	}
 
 	return listResourcesResp, nil
 }
 
+// ListResourceTemplatesHandler returns the list of resource templates for model context protocol
+func (a *ModelContextProtocolAPI) ListResourceTemplatesHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.ListResourceTemplatesHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	listResourceTemplatesResp := mcp.MCPListResourceTemplatesResponse{
+		ResourceTemplates: mcp.Container.GetMCPResourceTemplates(),
+	}
+
+	return listResourceTemplatesResp, nil
+}
+
 // ReadResourceHandler returns the resource details for a specific resource in model context protocol
 func (a *ModelContextProtocolAPI) ReadResourceHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
 	var readResourceReq mcp.MCPReadResourceRequest
@@ -157,7 +209,97 @@ func (a *ModelContextProtocolAPI) ReadResourceHandler(c *core.WebContext, jsonRP
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
-	return nil, errs.ErrApiNotFound
+	result, err := mcp.Container.ReadResource(c, readResourceReq.URI, user, a.CurrentConfig(), a)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.ReadResourceHandler] failed to read resource \"%s\" for user \"uid:%d\", because %s", readResourceReq.URI, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
+}
+
+// SubscribeResourceHandler subscribes the updated notifications of a specific resource in model context protocol
+func (a *ModelContextProtocolAPI) SubscribeResourceHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	session, subscribeReq, err := a.getSubscribeResourceRequest(c, jsonRPCRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	session.SubscribeResource(subscribeReq.URI)
+	log.Infof(c, "[model_context_protocols.SubscribeResourceHandler] mcp session \"%s\" of user \"uid:%d\" has subscribed resource \"%s\"", session.Id, session.Uid, subscribeReq.URI)
+
+	return gin.H{}, nil
+}
+
+// UnsubscribeResourceHandler unsubscribes the updated notifications of a specific resource in model context protocol
+func (a *ModelContextProtocolAPI) UnsubscribeResourceHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	session, subscribeReq, err := a.getSubscribeResourceRequest(c, jsonRPCRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	session.UnsubscribeResource(subscribeReq.URI)
+	log.Infof(c, "[model_context_protocols.UnsubscribeResourceHandler] mcp session \"%s\" of user \"uid:%d\" has unsubscribed resource \"%s\"", session.Id, session.Uid, subscribeReq.URI)
+
+	return gin.H{}, nil
+}
+
+// ListPromptsHandler returns the list of prompts for model context protocol
+func (a *ModelContextProtocolAPI) ListPromptsHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.ListPromptsHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	listPromptsResp := mcp.MCPListPromptsResponse{
+		Prompts: mcp.Container.GetMCPPrompts(),
+	}
+
+	return listPromptsResp, nil
+}
+
+// GetPromptHandler returns the messages of a specific prompt for model context protocol
+func (a *ModelContextProtocolAPI) GetPromptHandler(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (any, *errs.Error) {
+	var getPromptReq mcp.MCPGetPromptRequest
+
+	if jsonRPCRequest.Params != nil {
+		if err := json.Unmarshal(jsonRPCRequest.Params, &getPromptReq); err != nil {
+			return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.GetPromptHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	result, err := mcp.Container.GetPrompt(c, &getPromptReq, user, a.CurrentConfig(), a)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.GetPromptHandler] failed to get prompt \"%s\" for user \"uid:%d\", because %s", getPromptReq.Name, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return result, nil
 }
 
 // ListToolsHandler returns the list of tools for model context protocol
@@ -175,11 +317,16 @@ func (a *ModelContextProtocolAPI) ListToolsHandler(c *core.WebContext, jsonRPCRe
 	}
 
 	mcpVersion := a.getMCPVersion(c)
+	tokenScope := c.GetTokenClaims().Scope
 	toolsInfo := mcp.Container.GetMCPTools()
-	finalToolsInfos := make([]*mcp.MCPTool, len(toolsInfo))
+	finalToolsInfos := make([]*mcp.MCPTool, 0, len(toolsInfo))
 
 	for i := 0; i < len(toolsInfo); i++ {
-		finalToolsInfos[i] = &mcp.MCPTool{
+		if !mcp.Container.IsToolAvailable(toolsInfo[i].Name, user, tokenScope) {
+			continue
+		}
+
+		toolInfo := &mcp.MCPTool{
 			Name:        toolsInfo[i].Name,
 			InputSchema: toolsInfo[i].InputSchema,
 			Title:       toolsInfo[i].Title,
@@ -187,8 +334,14 @@ func (a *ModelContextProtocolAPI) ListToolsHandler(c *core.WebContext, jsonRPCRe
 		}
 
 		if mcpVersion >= string(mcp.ToolResultStructuredContentMinVersion) {
-			finalToolsInfos[i].OutputSchema = toolsInfo[i].OutputSchema
+			toolInfo.OutputSchema = toolsInfo[i].OutputSchema
 		}
+
+		if mcpVersion >= string(mcp.ToolAnnotationsMinVersion) {
+			toolInfo.Annotations = toolsInfo[i].Annotations
+		}
+
+		finalToolsInfos = append(finalToolsInfos, toolInfo)
 	}
 
 	listToolsResp := mcp.MCPListToolsResponse{
@@ -222,6 +375,11 @@ func (a *ModelContextProtocolAPI) CallToolHandler(c *core.WebContext, jsonRPCReq
 		return nil, errs.ErrIncompleteOrIncorrectSubmission
 	}
 
+	if !mcp.Container.IsToolAvailable(callToolReq.Name, user, c.GetTokenClaims().Scope) {
+		log.Warnf(c, "[model_context_protocols.CallToolHandler] tool \"%s\" is not available for user \"uid:%d\" with current token", callToolReq.Name, uid)
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
 	result, err := mcp.Container.HandleTool(c, &callToolReq, user, a.CurrentConfig(), a)
 
 	if err != nil {
@@ -236,6 +394,96 @@ func (a *ModelContextProtocolAPI) PingHandler(c *core.WebContext, jsonRPCRequest
 	return gin.H{}, nil
 }
 
+// NotificationStreamHandler streams the server notifications of current session for model context protocol
+func (a *ModelContextProtocolAPI) NotificationStreamHandler(c *core.WebContext) *errs.Error {
+	if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		return errs.ErrMCPEventStreamNotAccepted
+	}
+
+	session, err := a.getCurrentSession(c)
+
+	if err != nil {
+		return err
+	}
+
+	user, err2 := a.users.GetUserById(c, session.Uid)
+
+	if err2 != nil {
+		log.Warnf(c, "[model_context_protocols.NotificationStreamHandler] failed to get user \"uid:%d\" info, because %s", session.Uid, err2.Error())
+		return errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return errs.ErrNotPermittedToPerformThisAction
+	}
+
+	notifications, err2 := session.OpenNotificationStream()
+
+	if err2 != nil {
+		log.Warnf(c, "[model_context_protocols.NotificationStreamHandler] failed to open notification stream of mcp session \"%s\" for user \"uid:%d\", because %s", session.Id, session.Uid, err2.Error())
+		return errs.Or(err2, errs.ErrOperationFailed)
+	}
+
+	defer session.CloseNotificationStream()
+
+	utils.SetEventStreamHeader(c)
+	c.Header(mcp.MCPSessionIdHeaderName, session.Id)
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	log.Infof(c, "[model_context_protocols.NotificationStreamHandler] notification stream of mcp session \"%s\" for user \"uid:%d\" has been opened", session.Id, session.Uid)
+
+	keepAliveTicker := time.NewTicker(mcpEventStreamKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+
+	currentYearMonth := time.Now().Format("2006-01")
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			log.Infof(c, "[model_context_protocols.NotificationStreamHandler] notification stream of mcp session \"%s\" for user \"uid:%d\" has been closed by client", session.Id, session.Uid)
+			return nil
+		case notification, ok := <-notifications:
+			if !ok {
+				return nil
+			}
+
+			if !a.writeEventStreamMessage(c, notification) {
+				return nil
+			}
+		case <-keepAliveTicker.C:
+			// the current month summary in resource list changes when a new month begins
+			if yearMonth := time.Now().Format("2006-01"); yearMonth != currentYearMonth {
+				currentYearMonth = yearMonth
+				session.SendNotification(&mcp.MCPNotification{
+					JSONRPC: core.JSONRPCVersion,
+					Method:  mcp.MCPNotificationMethodResourceListChanged,
+				})
+			}
+
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return nil
+			}
+
+			c.Writer.Flush()
+		}
+	}
+}
+
+// TerminateSessionHandler terminates current session for model context protocol
+func (a *ModelContextProtocolAPI) TerminateSessionHandler(c *core.WebContext) (any, *errs.Error) {
+	session, err := a.getCurrentSession(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mcp.Sessions.RemoveSession(session)
+	log.Infof(c, "[model_context_protocols.TerminateSessionHandler] mcp session \"%s\" of user \"uid:%d\" has been terminated", session.Id, session.Uid)
+
+	return true, nil
+}
+
 // GetTransactionService implements the MCPAvailableServices interface
 func (a *ModelContextProtocolAPI) GetTransactionService() *services.TransactionService {
 	return a.transactions
@@ -251,6 +499,11 @@ func (a *ModelContextProtocolAPI) GetTransactionTagService() *services.Transacti
 	return a.transactionTags
 }
 
+// GetPayeeService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetPayeeService() *services.PayeeService {
+	return a.payees
+}
+
 // GetAccountService implements the MCPAvailableServices interface
 func (a *ModelContextProtocolAPI) GetAccountService() *services.AccountService {
 	return a.accounts
@@ -261,7 +514,91 @@ func (a *ModelContextProtocolAPI) GetUserService() *services.UserService {
 	return a.users
 }
 
+// GetInvestmentService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetInvestmentService() *services.InvestmentService {
+	return a.investments
+}
+
+// GetStockPriceService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetStockPriceService() *services.StockPriceService {
+	return a.stockPrices
+}
+
+// GetExchangeRatesHistoryService implements the MCPAvailableServices interface
+func (a *ModelContextProtocolAPI) GetExchangeRatesHistoryService() *services.ExchangeRatesHistoryService {
+	return a.exchangeRatesHistory
+}
+
 // getMCPVersion returns the MCP protocol version from the request header
 func (a *ModelContextProtocolAPI) getMCPVersion(c *core.WebContext) string {
 	return c.GetHeader(mcp.MCPProtocolVersionHeaderName)
 }
+
+func (a *ModelContextProtocolAPI) getCurrentSession(c *core.WebContext) (*mcp.MCPSession, *errs.Error) {
+	sessionId := c.GetHeader(mcp.MCPSessionIdHeaderName)
+
+	if sessionId == "" {
+		return nil, errs.ErrMCPSessionIdIsEmpty
+	}
+
+	claims := c.GetTokenClaims()
+	session := mcp.Sessions.GetSession(sessionId, claims.Uid, claims.UserTokenId)
+
+	if session == nil {
+		return nil, errs.ErrMCPSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (a *ModelContextProtocolAPI) getSubscribeResourceRequest(c *core.WebContext, jsonRPCRequest *core.JSONRPCRequest) (*mcp.MCPSession, *mcp.MCPSubscribeResourceRequest, *errs.Error) {
+	var subscribeReq mcp.MCPSubscribeResourceRequest
+
+	if jsonRPCRequest.Params != nil {
+		if err := json.Unmarshal(jsonRPCRequest.Params, &subscribeReq); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	session, err := a.getCurrentSession(c)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err2 := a.users.GetUserById(c, session.Uid)
+
+	if err2 != nil {
+		log.Warnf(c, "[model_context_protocols.getSubscribeResourceRequest] failed to get user \"uid:%d\" info, because %s", session.Uid, err2.Error())
+		return nil, nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_MCP_ACCESS) {
+		return nil, nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	if !mcp.Container.IsResourceExists(subscribeReq.URI) {
+		return nil, nil, errs.ErrMCPResourceNotFound
+	}
+
+	return session, &subscribeReq, nil
+}
+
+func (a *ModelContextProtocolAPI) writeEventStreamMessage(c *core.WebContext, notification *mcp.MCPNotification) bool {
+	data, err := json.Marshal(notification)
+
+	if err != nil {
+		log.Warnf(c, "[model_context_protocols.writeEventStreamMessage] failed to marshal notification \"%s\", because %s", notification.Method, err.Error())
+		return true
+	}
+
+	if _, err = c.Writer.WriteString("event: message\ndata: " + string(data) + "\n\n"); err != nil {
+		return false
+	}
+
+	c.Writer.Flush()
+
+	return true
+}
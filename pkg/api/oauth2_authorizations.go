@@ -0,0 +1,629 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/auth"
+	"github.com/mayswind/ezbookkeeping/pkg/avatars"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const oauth2StateCookieName = "ebk_oauth2_state"
+const oauth2StateCookiePath = "/oauth2"
+const oauth2LinkCookieName = "ebk_oauth2_link"
+const oauth2StateLength = 32
+const oauth2NonceLength = 32
+const oauth2LinkSecretLength = 32
+const oauth2RandomPasswordLength = 32
+
+const oauth2DesktopCallbackUrlFormat = "%sdesktop/#/login?%s"
+const oauth2MobileCallbackUrlFormat = "%smobile#%s"
+const oauth2DesktopLinkCallbackUrlFormat = "%sdesktop/#/user/settings?tab=securitySetting&%s"
+
+const oauth2PlatformDesktop = "desktop"
+const oauth2PlatformMobile = "mobile"
+
+// oauth2LoginState represents the login state which is stored in the encrypted cookie between login request and callback
+type oauth2LoginState struct {
+	State      string `json:"state"`
+	Nonce      string `json:"nonce"`
+	Verifier   string `json:"verifier"`
+	Platform   string `json:"platform"`
+	LinkUid    int64  `json:"linkUid,omitempty"`
+	LinkSecret string `json:"linkSecret,omitempty"`
+	ExpiredAt  int64  `json:"expiredAt"`
+}
+
+// oauth2LinkRequest represents the encrypted link request which binds the external account to current user,
+// the secret is also stored in the cookie of the browser which creates the link request, so that the link url cannot be used by others
+type oauth2LinkRequest struct {
+	Uid       int64  `json:"uid"`
+	Secret    string `json:"secret"`
+	ExpiredAt int64  `json:"expiredAt"`
+}
+
+// OAuth2AuthorizationsApi represents oauth2 authorization api
+type OAuth2AuthorizationsApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	ApiWithUserInfo
+	users                   *services.UserService
+	userExternalAuths       *services.UserExternalAuthService
+	userAppCloudSettings    *services.UserApplicationCloudSettingsService
+	tokens                  *services.TokenService
+	twoFactorAuthorizations *services.TwoFactorAuthorizationService
+	userWebAuthnCredentials *services.UserWebAuthnCredentialService
+	auditEvents             *services.AuditEventService
+}
+
+// Initialize a oauth2 authorization api singleton instance
+var (
+	OAuth2Authorizations = &OAuth2AuthorizationsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		ApiWithUserInfo: ApiWithUserInfo{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			ApiUsingAvatarProvider: ApiUsingAvatarProvider{
+				container: avatars.Container,
+			},
+		},
+		users:                   services.Users,
+		userExternalAuths:       services.UserExternalAuths,
+		userAppCloudSettings:    services.UserApplicationCloudSettings,
+		tokens:                  services.Tokens,
+		twoFactorAuthorizations: services.TwoFactorAuthorizations,
+		userWebAuthnCredentials: services.UserWebAuthnCredentials,
+		auditEvents:             services.AuditEvents,
+	}
+)
+
+// OIDCLoginHandler redirects current request to the authorization url of OpenID Connect identity provider
+func (a *OAuth2AuthorizationsApi) OIDCLoginHandler(c *core.WebContext) (string, *errs.Error) {
+	if !auth.Container.IsOIDCEnabled() {
+		return "", errs.ErrOIDCAuthNotEnabled
+	}
+
+	platform := c.Query("platform")
+
+	if platform != oauth2PlatformMobile {
+		platform = oauth2PlatformDesktop
+	}
+
+	linkUid := int64(0)
+	linkSecret := ""
+	encryptedLinkRequest := c.Query("link")
+
+	if encryptedLinkRequest != "" {
+		linkRequest, err := a.decryptLinkRequest(encryptedLinkRequest)
+
+		if err != nil {
+			log.Warnf(c, "[oauth2_authorizations.OIDCLoginHandler] failed to parse link request, because %s", err.Error())
+			return "", errs.ErrOIDCLoginStateInvalid
+		}
+
+		if !a.isLinkSecretCookieMatched(c, linkRequest.Secret) {
+			log.Warnf(c, "[oauth2_authorizations.OIDCLoginHandler] link request of user \"uid:%d\" is not created by current browser", linkRequest.Uid)
+			return "", errs.ErrOIDCLoginStateInvalid
+		}
+
+		linkUid = linkRequest.Uid
+		linkSecret = linkRequest.Secret
+	}
+
+	state, err := utils.GetRandomNumberOrLetter(oauth2StateLength)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.OIDCLoginHandler] failed to generate state, because %s", err.Error())
+		return "", errs.ErrSystemError
+	}
+
+	nonce, err := utils.GetRandomNumberOrLetter(oauth2NonceLength)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.OIDCLoginHandler] failed to generate nonce, because %s", err.Error())
+		return "", errs.ErrSystemError
+	}
+
+	loginState := &oauth2LoginState{
+		State:      state,
+		Nonce:      nonce,
+		Verifier:   oauth2.GenerateVerifier(),
+		Platform:   platform,
+		LinkUid:    linkUid,
+		LinkSecret: linkSecret,
+		ExpiredAt:  time.Now().Add(a.CurrentConfig().TemporaryTokenExpiredTimeDuration).Unix(),
+	}
+
+	authUrl, err := auth.Container.GetOIDCAuthUrl(c, loginState.State, loginState.Nonce, loginState.Verifier)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.OIDCLoginHandler] failed to get authorization url, because %s", err.Error())
+		return "", errs.Or(err, errs.ErrOIDCAuthFailed)
+	}
+
+	err = a.setLoginStateCookie(c, loginState)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.OIDCLoginHandler] failed to save login state, because %s", err.Error())
+		return "", errs.ErrSystemError
+	}
+
+	return authUrl, nil
+}
+
+// OIDCCallbackHandler verifies the authorization code returned by OpenID Connect identity provider and redirects to the login page with temporary token
+func (a *OAuth2AuthorizationsApi) OIDCCallbackHandler(c *core.WebContext) (string, *errs.Error) {
+	loginState, err := a.getLoginStateCookie(c)
+	c.SetCookie(oauth2StateCookieName, "", -1, oauth2StateCookiePath, "", false, true)
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.OIDCCallbackHandler] failed to get login state, because %s", err.Error())
+		return a.getCallbackErrorUrl(oauth2PlatformDesktop, errs.ErrOIDCLoginStateInvalid), nil
+	}
+
+	if loginState.State != c.Query("state") || loginState.ExpiredAt < time.Now().Unix() {
+		log.Warnf(c, "[oauth2_authorizations.OIDCCallbackHandler] login state is not matched or has been expired")
+		return a.getCallbackErrorUrl(loginState.Platform, errs.ErrOIDCLoginStateInvalid), nil
+	}
+
+	if c.Query("error") != "" {
+		log.Warnf(c, "[oauth2_authorizations.OIDCCallbackHandler] identity provider returns error \"%s\", description is \"%s\"", c.Query("error"), c.Query("error_description"))
+		return a.getCallbackErrorUrl(loginState.Platform, errs.ErrOIDCAuthFailed), nil
+	}
+
+	userInfo, err := auth.Container.GetOIDCUserInfo(c, c.Query("code"), loginState.Nonce, loginState.Verifier)
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.OIDCCallbackHandler] failed to get user info, because %s", err.Error())
+		return a.getCallbackErrorUrl(loginState.Platform, errs.Or(err, errs.ErrOIDCAuthFailed)), nil
+	}
+
+	if loginState.LinkUid > 0 {
+		linkSecretMatched := a.isLinkSecretCookieMatched(c, loginState.LinkSecret)
+		c.SetCookie(oauth2LinkCookieName, "", -1, oauth2StateCookiePath, "", false, true)
+
+		if !linkSecretMatched {
+			log.Warnf(c, "[oauth2_authorizations.OIDCCallbackHandler] link request of user \"uid:%d\" is not created by current browser", loginState.LinkUid)
+			return a.getLinkCallbackUrl(loginState.Platform, url.Values{"oidcError": []string{errs.ErrOIDCLoginStateInvalid.Message}}), nil
+		}
+
+		err = a.linkExternalAccount(c, loginState.LinkUid, userInfo)
+
+		if err != nil {
+			return a.getLinkCallbackUrl(loginState.Platform, url.Values{"oidcError": []string{errs.Or(err, errs.ErrOperationFailed).Message}}), nil
+		}
+
+		log.Infof(c, "[oauth2_authorizations.OIDCCallbackHandler] user \"uid:%d\" has linked external account \"%s\"", loginState.LinkUid, userInfo.Subject)
+		a.auditEvents.CreateAuditEvent(c, loginState.LinkUid, models.AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_LINKED, "oidc")
+
+		return a.getLinkCallbackUrl(loginState.Platform, url.Values{"oidcLinked": []string{"true"}}), nil
+	}
+
+	user, err := a.getOrCreateUserByExternalAccount(c, userInfo)
+
+	if err != nil {
+		return a.getCallbackErrorUrl(loginState.Platform, errs.Or(err, errs.ErrOIDCAuthFailed)), nil
+	}
+
+	token, _, err := a.tokens.CreateOAuth2CallbackToken(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.OIDCCallbackHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return a.getCallbackErrorUrl(loginState.Platform, errs.ErrTokenGenerating), nil
+	}
+
+	return a.getCallbackUrl(loginState.Platform, url.Values{"oidcToken": []string{token}}), nil
+}
+
+// OIDCAuthorizeHandler authorizes current login request by the temporary token issued in oidc callback
+func (a *OAuth2AuthorizationsApi) OIDCAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.Disabled {
+		log.Warnf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] user \"uid:%d\" is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if a.CurrentConfig().EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.Warnf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] user \"uid:%d\" has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	oldTokenClaims := c.GetTokenClaims()
+	err = a.tokens.DeleteTokenByClaims(c, oldTokenClaims)
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] failed to revoke temporary token \"utid:%s\" for user \"uid:%d\", because %s", oldTokenClaims.UserTokenId, user.Uid, err.Error())
+	}
+
+	err = a.users.UpdateUserLastLoginTime(c, user.Uid)
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	twoFactorMethods, err := getUserTwoFactorMethods(c, a.CurrentConfig(), a.twoFactorAuthorizations, a.userWebAuthnCredentials, user.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] failed to check two-factor setting for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrSystemError)
+	}
+
+	twoFactorEnable := len(twoFactorMethods) > 0
+
+	var token string
+	var claims *core.UserTokenClaims
+
+	if twoFactorEnable {
+		token, claims, err = a.tokens.CreateRequire2FAToken(c, user)
+	} else {
+		token, claims, err = a.tokens.CreateToken(c, user)
+	}
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	if !twoFactorEnable {
+		c.SetTextualToken(token)
+	}
+
+	c.SetTokenClaims(claims)
+
+	userApplicationCloudSettings, err := a.userAppCloudSettings.GetUserApplicationCloudSettingsByUid(c, user.Uid)
+	var applicationCloudSettingSlice *models.ApplicationCloudSettingSlice = nil
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.OIDCAuthorizeHandler] failed to get latest user application cloud settings for user \"uid:%d\", because %s", user.Uid, err.Error())
+	} else if userApplicationCloudSettings != nil && len(userApplicationCloudSettings.Settings) > 0 {
+		applicationCloudSettingSlice = &userApplicationCloudSettings.Settings
+	}
+
+	log.Infof(c, "[oauth2_authorizations.OIDCAuthorizeHandler] user \"uid:%d\" has logined via oidc, token type is %d, token will be expired at %d", user.Uid, claims.Type, claims.ExpiresAt)
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_LOGIN, "oidc")
+
+	authResp := &models.AuthResponse{
+		Token:                    token,
+		Need2FA:                  twoFactorEnable,
+		TwoFactorMethods:         twoFactorMethods,
+		User:                     a.GetUserBasicInfo(user),
+		ApplicationCloudSettings: applicationCloudSettingSlice,
+		NotificationContent:      a.GetAfterLoginNotificationContent(user.Language, c.GetClientLocale()),
+	}
+
+	return authResp, nil
+}
+
+// UserExternalAuthListHandler returns all external accounts linked to current user
+func (a *OAuth2AuthorizationsApi) UserExternalAuthListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	userExternalAuths, err := a.userExternalAuths.GetAllUserExternalAuthsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.UserExternalAuthListHandler] failed to get external accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	userExternalAuthResps := make([]*models.UserExternalAuthInfoResponse, len(userExternalAuths))
+
+	for i := 0; i < len(userExternalAuths); i++ {
+		userExternalAuthResps[i] = userExternalAuths[i].ToUserExternalAuthInfoResponse()
+	}
+
+	return userExternalAuthResps, nil
+}
+
+// UserOIDCLinkRequestHandler returns the url which current user can visit to link the external account
+func (a *OAuth2AuthorizationsApi) UserOIDCLinkRequestHandler(c *core.WebContext) (any, *errs.Error) {
+	if !auth.Container.IsOIDCEnabled() {
+		return nil, errs.ErrOIDCAuthNotEnabled
+	}
+
+	var linkReq models.UserOIDCLinkRequest
+	err := c.ShouldBindJSON(&linkReq)
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.UserOIDCLinkRequestHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	linkSecret, err := utils.GetRandomNumberOrLetter(oauth2LinkSecretLength)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.UserOIDCLinkRequestHandler] failed to generate link secret for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	linkRequest := &oauth2LinkRequest{
+		Uid:       uid,
+		Secret:    linkSecret,
+		ExpiredAt: time.Now().Add(a.CurrentConfig().TemporaryTokenExpiredTimeDuration).Unix(),
+	}
+
+	encryptedLinkRequest, err := a.encryptData(linkRequest)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.UserOIDCLinkRequestHandler] failed to create link request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauth2LinkCookieName, linkSecret, int(a.CurrentConfig().TemporaryTokenExpiredTime), oauth2StateCookiePath, "", strings.HasPrefix(a.CurrentConfig().RootUrl, "https://"), true)
+
+	params := url.Values{}
+	params.Set("platform", linkReq.Platform)
+	params.Set("link", encryptedLinkRequest)
+
+	return &models.UserOIDCLinkResponse{
+		Url: a.CurrentConfig().RootUrl + "oauth2/login?" + params.Encode(),
+	}, nil
+}
+
+// UserOIDCUnlinkHandler unlinks the oidc external account from current user
+func (a *OAuth2AuthorizationsApi) UserOIDCUnlinkHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	err := a.userExternalAuths.DeleteUserExternalAuth(c, uid, models.USER_EXTERNAL_AUTH_TYPE_OIDC)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.UserOIDCUnlinkHandler] failed to unlink external account for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[oauth2_authorizations.UserOIDCUnlinkHandler] user \"uid:%d\" has unlinked external account", uid)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_UNLINKED, "oidc")
+
+	return true, nil
+}
+
+func (a *OAuth2AuthorizationsApi) linkExternalAccount(c *core.WebContext, uid int64, userInfo *auth.OIDCUserInfo) error {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.linkExternalAccount] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return errs.ErrUserNotFound
+	}
+
+	err = a.userExternalAuths.CreateUserExternalAuth(c, a.getUserExternalAuth(user.Uid, userInfo))
+
+	if err != nil {
+		log.Warnf(c, "[oauth2_authorizations.linkExternalAccount] failed to link external account \"%s\" to user \"uid:%d\", because %s", userInfo.Subject, user.Uid, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (a *OAuth2AuthorizationsApi) getOrCreateUserByExternalAccount(c *core.WebContext, userInfo *auth.OIDCUserInfo) (*models.User, error) {
+	userExternalAuth, err := a.userExternalAuths.GetUserExternalAuthByExternalUserId(c, models.USER_EXTERNAL_AUTH_TYPE_OIDC, userInfo.Subject)
+
+	if err == nil {
+		user, err := a.users.GetUserById(c, userExternalAuth.Uid)
+
+		if err != nil {
+			log.Warnf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] failed to get user \"uid:%d\" linked to external account \"%s\", because %s", userExternalAuth.Uid, userInfo.Subject, err.Error())
+			return nil, errs.ErrUserNotFound
+		}
+
+		return user, nil
+	} else if err != errs.ErrExternalAuthUserNotLinked {
+		log.Errorf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] failed to get linked user of external account \"%s\", because %s", userInfo.Subject, err.Error())
+		return nil, err
+	}
+
+	if a.CurrentConfig().OIDCLinkUserByEmail && userInfo.Email != "" && userInfo.EmailVerified {
+		user, err := a.users.GetUserByEmail(c, userInfo.Email)
+
+		if err != nil && err != errs.ErrUserNotFound {
+			log.Errorf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] failed to get user by email \"%s\", because %s", userInfo.Email, err.Error())
+			return nil, err
+		}
+
+		if user != nil {
+			err = a.userExternalAuths.CreateUserExternalAuth(c, a.getUserExternalAuth(user.Uid, userInfo))
+
+			if err != nil {
+				log.Warnf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] failed to link external account \"%s\" to user \"uid:%d\", because %s", userInfo.Subject, user.Uid, err.Error())
+				return nil, err
+			}
+
+			log.Infof(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] external account \"%s\" has been linked to user \"uid:%d\" by email", userInfo.Subject, user.Uid)
+			a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_LINKED, "oidc by email")
+
+			return user, nil
+		}
+	}
+
+	if !a.CurrentConfig().OIDCEnableAutoCreateUser {
+		log.Warnf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] external account \"%s\" is not linked to any user", userInfo.Subject)
+		return nil, errs.ErrExternalAuthUserNotLinked
+	}
+
+	username := strings.TrimSpace(userInfo.Username)
+	email := strings.TrimSpace(userInfo.Email)
+	nickname := strings.TrimSpace(userInfo.Nickname)
+
+	if username == "" || len(username) > 32 || !utils.IsValidUsername(username) || email == "" || len(email) > 100 || !utils.IsValidEmail(email) {
+		log.Warnf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] cannot create user for external account \"%s\", because username \"%s\" or email \"%s\" is invalid", userInfo.Subject, username, email)
+		return nil, errs.ErrOIDCUserInfoInvalid
+	}
+
+	if nickname == "" || len(nickname) > 64 {
+		nickname = username
+	}
+
+	password, err := utils.GetRandomString(oauth2RandomPasswordLength)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] failed to generate random password, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	user := &models.User{
+		Username:             username,
+		Email:                email,
+		Nickname:             nickname,
+		Password:             password,
+		Language:             a.CurrentConfig().OIDCDefaultLanguage,
+		DefaultCurrency:      a.CurrentConfig().OIDCDefaultCurrency,
+		FirstDayOfWeek:       core.WEEKDAY_SUNDAY,
+		FiscalYearStart:      core.FISCAL_YEAR_START_DEFAULT,
+		TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
+		FeatureRestriction:   a.CurrentConfig().DefaultFeatureRestrictions,
+		EmailVerified:        userInfo.EmailVerified,
+	}
+
+	err = a.users.CreateUser(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] failed to create user \"%s\" for external account \"%s\", because %s", username, userInfo.Subject, err.Error())
+		return nil, err
+	}
+
+	log.Infof(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] user \"%s\" has been created for external account \"%s\", uid is %d", user.Username, userInfo.Subject, user.Uid)
+
+	err = a.userExternalAuths.CreateUserExternalAuth(c, a.getUserExternalAuth(user.Uid, userInfo))
+
+	if err != nil {
+		log.Errorf(c, "[oauth2_authorizations.getOrCreateUserByExternalAccount] failed to link external account \"%s\" to new user \"uid:%d\", because %s", userInfo.Subject, user.Uid, err.Error())
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (a *OAuth2AuthorizationsApi) getUserExternalAuth(uid int64, userInfo *auth.OIDCUserInfo) *models.UserExternalAuth {
+	return &models.UserExternalAuth{
+		Uid:              uid,
+		ExternalAuthType: models.USER_EXTERNAL_AUTH_TYPE_OIDC,
+		ExternalUserId:   userInfo.Subject,
+		ExternalUsername: utils.SubString(userInfo.Username, 0, 255),
+		ExternalEmail:    utils.SubString(userInfo.Email, 0, 100),
+	}
+}
+
+func (a *OAuth2AuthorizationsApi) getCallbackUrl(platform string, params url.Values) string {
+	if platform == oauth2PlatformMobile {
+		return fmt.Sprintf(oauth2MobileCallbackUrlFormat, a.CurrentConfig().RootUrl, params.Encode())
+	}
+
+	return fmt.Sprintf(oauth2DesktopCallbackUrlFormat, a.CurrentConfig().RootUrl, params.Encode())
+}
+
+func (a *OAuth2AuthorizationsApi) getLinkCallbackUrl(platform string, params url.Values) string {
+	if platform == oauth2PlatformMobile {
+		return fmt.Sprintf(oauth2MobileCallbackUrlFormat, a.CurrentConfig().RootUrl, params.Encode())
+	}
+
+	return fmt.Sprintf(oauth2DesktopLinkCallbackUrlFormat, a.CurrentConfig().RootUrl, params.Encode())
+}
+
+func (a *OAuth2AuthorizationsApi) getCallbackErrorUrl(platform string, err *errs.Error) string {
+	return a.getCallbackUrl(platform, url.Values{"oidcError": []string{err.Message}})
+}
+
+func (a *OAuth2AuthorizationsApi) setLoginStateCookie(c *core.WebContext, loginState *oauth2LoginState) error {
+	encryptedLoginState, err := a.encryptData(loginState)
+
+	if err != nil {
+		return err
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauth2StateCookieName, encryptedLoginState, int(a.CurrentConfig().TemporaryTokenExpiredTime), oauth2StateCookiePath, "", strings.HasPrefix(a.CurrentConfig().RootUrl, "https://"), true)
+
+	return nil
+}
+
+func (a *OAuth2AuthorizationsApi) getLoginStateCookie(c *core.WebContext) (*oauth2LoginState, error) {
+	encryptedLoginState, err := c.Cookie(oauth2StateCookieName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	loginState := &oauth2LoginState{}
+	err = a.decryptData(encryptedLoginState, loginState)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return loginState, nil
+}
+
+func (a *OAuth2AuthorizationsApi) isLinkSecretCookieMatched(c *core.WebContext, linkSecret string) bool {
+	cookieLinkSecret, err := c.Cookie(oauth2LinkCookieName)
+
+	if err != nil || cookieLinkSecret == "" || linkSecret == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(cookieLinkSecret), []byte(linkSecret)) == 1
+}
+
+func (a *OAuth2AuthorizationsApi) decryptLinkRequest(encryptedLinkRequest string) (*oauth2LinkRequest, error) {
+	linkRequest := &oauth2LinkRequest{}
+	err := a.decryptData(encryptedLinkRequest, linkRequest)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if linkRequest.Uid <= 0 || linkRequest.Secret == "" || linkRequest.ExpiredAt < time.Now().Unix() {
+		return nil, errs.ErrOIDCLoginStateInvalid
+	}
+
+	return linkRequest, nil
+}
+
+func (a *OAuth2AuthorizationsApi) encryptData(data any) (string, error) {
+	content, err := json.Marshal(data)
+
+	if err != nil {
+		return "", err
+	}
+
+	return utils.EncryptSecret(string(content), a.CurrentConfig().SecretKey)
+}
+
+func (a *OAuth2AuthorizationsApi) decryptData(encryptedData string, data any) error {
+	content, err := utils.DecryptSecret(encryptedData, a.CurrentConfig().SecretKey)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(content), data)
+}
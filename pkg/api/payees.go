@@ -0,0 +1,233 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// PayeesApi represents payee api
+type PayeesApi struct {
+	payees *services.PayeeService
+}
+
+// Initialize a payee api singleton instance
+var (
+	Payees = &PayeesApi{
+		payees: services.Payees,
+	}
+)
+
+// PayeeListHandler returns payee list of current user
+func (a *PayeesApi) PayeeListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	payees, err := a.payees.GetAllPayeesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeListHandler] failed to get payees for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payeeResps := make(models.PayeeInfoResponseSlice, len(payees))
+
+	for i := 0; i < len(payees); i++ {
+		payeeResps[i] = payees[i].ToPayeeInfoResponse()
+	}
+
+	sort.Sort(payeeResps)
+
+	return payeeResps, nil
+}
+
+// PayeeGetHandler returns one specific payee of current user
+func (a *PayeesApi) PayeeGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeGetReq models.PayeeGetRequest
+	err := c.ShouldBindQuery(&payeeGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	payee, err := a.payees.GetPayeeByPayeeId(c, uid, payeeGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeGetHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", payeeGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payeeResp := payee.ToPayeeInfoResponse()
+
+	return payeeResp, nil
+}
+
+// PayeeCreateHandler saves a new payee by request parameters for current user
+func (a *PayeesApi) PayeeCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeCreateReq models.PayeeCreateRequest
+	err := c.ShouldBindJSON(&payeeCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	payee := &models.Payee{
+		Uid:  uid,
+		Name: payeeCreateReq.Name,
+	}
+
+	err = payee.SetAliases(payeeCreateReq.Aliases)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeCreateHandler] payee aliases are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrPayeeAliasInvalid)
+	}
+
+	err = a.payees.CreatePayee(c, payee)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeCreateHandler] failed to create payee \"id:%d\" for user \"uid:%d\", because %s", payee.PayeeId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeCreateHandler] user \"uid:%d\" has created a new payee \"id:%d\" successfully", uid, payee.PayeeId)
+
+	payeeResp := payee.ToPayeeInfoResponse()
+
+	return payeeResp, nil
+}
+
+// PayeeModifyHandler saves an existed payee by request parameters for current user
+func (a *PayeesApi) PayeeModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeModifyReq models.PayeeModifyRequest
+	err := c.ShouldBindJSON(&payeeModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	payee, err := a.payees.GetPayeeByPayeeId(c, uid, payeeModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeModifyHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", payeeModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newPayee := &models.Payee{
+		PayeeId: payee.PayeeId,
+		Uid:     uid,
+		Name:    payeeModifyReq.Name,
+	}
+
+	err = newPayee.SetAliases(payeeModifyReq.Aliases)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeModifyHandler] payee aliases are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrPayeeAliasInvalid)
+	}
+
+	if newPayee.Name == payee.Name && newPayee.Aliases == payee.Aliases {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.payees.ModifyPayee(c, newPayee)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeModifyHandler] failed to update payee \"id:%d\" for user \"uid:%d\", because %s", payeeModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeModifyHandler] user \"uid:%d\" has updated payee \"id:%d\" successfully", uid, payeeModifyReq.Id)
+
+	payee.Name = newPayee.Name
+	payee.Aliases = newPayee.Aliases
+	payeeResp := payee.ToPayeeInfoResponse()
+
+	return payeeResp, nil
+}
+
+// PayeeHideHandler hides a payee by request parameters for current user
+func (a *PayeesApi) PayeeHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeHideReq models.PayeeHideRequest
+	err := c.ShouldBindJSON(&payeeHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.payees.HidePayee(c, uid, []int64{payeeHideReq.Id}, payeeHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeHideHandler] failed to hide payee \"id:%d\" for user \"uid:%d\", because %s", payeeHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeHideHandler] user \"uid:%d\" has hidden payee \"id:%d\"", uid, payeeHideReq.Id)
+	return true, nil
+}
+
+// PayeeMergeHandler merges some payees into an existed payee by request parameters for current user
+func (a *PayeesApi) PayeeMergeHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeMergeReq models.PayeeMergeRequest
+	err := c.ShouldBindJSON(&payeeMergeReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeMergeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	fromPayeeIds, err := utils.StringArrayToInt64Array(payeeMergeReq.FromIds)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeMergeHandler] parse payee ids failed, because %s", err.Error())
+		return nil, errs.ErrPayeeIdInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	payee, err := a.payees.MergePayees(c, uid, payeeMergeReq.Id, fromPayeeIds, payeeMergeReq.KeepAliases)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeMergeHandler] failed to merge payees into payee \"id:%d\" for user \"uid:%d\", because %s", payeeMergeReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeMergeHandler] user \"uid:%d\" has merged %d payees into payee \"id:%d\"", uid, len(fromPayeeIds), payeeMergeReq.Id)
+
+	payeeResp := payee.ToPayeeInfoResponse()
+
+	return payeeResp, nil
+}
+
+// PayeeDeleteHandler deletes an existed payee by request parameters for current user
+func (a *PayeesApi) PayeeDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var payeeDeleteReq models.PayeeDeleteRequest
+	err := c.ShouldBindJSON(&payeeDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[payees.PayeeDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.payees.DeletePayee(c, uid, payeeDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[payees.PayeeDeleteHandler] failed to delete payee \"id:%d\" for user \"uid:%d\", because %s", payeeDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[payees.PayeeDeleteHandler] user \"uid:%d\" has deleted payee \"id:%d\"", uid, payeeDeleteReq.Id)
+	return true, nil
+}
@@ -43,6 +43,15 @@ func (a *ServerSettingsApi) ServerSettingsJavascriptHandler(c *core.WebContext)
 	a.appendBooleanSetting(builder, "e", config.EnableDataExport)
 	a.appendBooleanSetting(builder, "i", config.EnableDataImport)
 
+	if config.EnableOIDCAuth {
+		a.appendStringSetting(builder, "oidc", config.OIDCProviderName)
+	}
+
+	if config.EnableWebAuthn {
+		a.appendBooleanSetting(builder, "wa", config.EnableWebAuthn)
+		a.appendBooleanSetting(builder, "wapl", config.EnableWebAuthnPasswordlessLogin)
+	}
+
 	if config.EnableMCPServer {
 		a.appendBooleanSetting(builder, "mcp", config.EnableMCPServer)
 	}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
@@ -21,6 +22,7 @@ type TokensApi struct {
 	tokens               *services.TokenService
 	users                *services.UserService
 	userAppCloudSettings *services.UserApplicationCloudSettingsService
+	auditEvents          *services.AuditEventService
 }
 
 // Initialize a token api singleton instance
@@ -40,13 +42,14 @@ var (
 		tokens:               services.Tokens,
 		users:                services.Users,
 		userAppCloudSettings: services.UserApplicationCloudSettings,
+		auditEvents:          services.AuditEvents,
 	}
 )
 
 // TokenListHandler returns available token list of current user
 func (a *TokensApi) TokenListHandler(c *core.WebContext) (any, *errs.Error) {
 	uid := c.GetCurrentUid()
-	tokens, err := a.tokens.GetAllUnexpiredNormalAndMCPTokensByUid(c, uid)
+	tokens, err := a.tokens.GetAllUnexpiredAccessTokensByUid(c, uid)
 
 	if err != nil {
 		log.Errorf(c, "[tokens.TokenListHandler] failed to get all tokens for user \"uid:%d\", because %s", uid, err.Error())
@@ -62,6 +65,7 @@ func (a *TokensApi) TokenListHandler(c *core.WebContext) (any, *errs.Error) {
 			TokenId:   a.tokens.GenerateTokenId(token),
 			TokenType: token.TokenType,
 			UserAgent: token.UserAgent,
+			ReadOnly:  token.Scope == core.USER_TOKEN_SCOPE_READ_ONLY,
 			LastSeen:  token.LastSeenUnixTime,
 		}
 
@@ -73,6 +77,11 @@ func (a *TokensApi) TokenListHandler(c *core.WebContext) (any, *errs.Error) {
 			tokenResp.UserAgent = services.TokenUserAgentForMCP
 		}
 
+		if token.TokenType == core.USER_TOKEN_TYPE_PERSONAL_ACCESS {
+			tokenResp.Name = token.Name
+			tokenResp.Permissions = token.Permissions.Names()
+		}
+
 		tokenResps[i] = tokenResp
 	}
 
@@ -111,14 +120,21 @@ func (a *TokensApi) TokenGenerateMCPHandler(c *core.WebContext) (any, *errs.Erro
 		return nil, errs.ErrUserPasswordWrong
 	}
 
-	token, claims, err := a.tokens.CreateMCPToken(c, user)
+	tokenScope := core.USER_TOKEN_SCOPE_FULL_ACCESS
+
+	if generateMCPTokenReq.ReadOnly {
+		tokenScope = core.USER_TOKEN_SCOPE_READ_ONLY
+	}
+
+	token, claims, err := a.tokens.CreateMCPToken(c, user, tokenScope)
 
 	if err != nil {
 		log.Errorf(c, "[tokens.TokenGenerateMCPHandler] failed to create mcp token for user \"uid:%d\", because %s", user.Uid, err.Error())
 		return nil, errs.Or(err, errs.ErrTokenGenerating)
 	}
 
-	log.Infof(c, "[tokens.TokenGenerateMCPHandler] user \"uid:%d\" has generated mcp token, new token will be expired at %d", user.Uid, claims.ExpiresAt)
+	log.Infof(c, "[tokens.TokenGenerateMCPHandler] user \"uid:%d\" has generated mcp token (read only: %t), new token will be expired at %d", user.Uid, generateMCPTokenReq.ReadOnly, claims.ExpiresAt)
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_TOKEN_GENERATED, fmt.Sprintf("mcp token (read only: %t)", generateMCPTokenReq.ReadOnly))
 
 	generateMCPTokenResp := &models.TokenGenerateMCPResponse{
 		Token:  token,
@@ -128,6 +144,53 @@ func (a *TokensApi) TokenGenerateMCPHandler(c *core.WebContext) (any, *errs.Erro
 	return generateMCPTokenResp, nil
 }
 
+// TokenGeneratePersonalAccessHandler generates a new personal access token for current user
+func (a *TokensApi) TokenGeneratePersonalAccessHandler(c *core.WebContext) (any, *errs.Error) {
+	var generatePersonalAccessTokenReq models.TokenGeneratePersonalAccessRequest
+	err := c.ShouldBindJSON(&generatePersonalAccessTokenReq)
+
+	if err != nil {
+		log.Warnf(c, "[tokens.TokenGeneratePersonalAccessHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	permissions, err := core.ParseTokenPermissionNames(generatePersonalAccessTokenReq.Permissions)
+
+	if err != nil {
+		log.Warnf(c, "[tokens.TokenGeneratePersonalAccessHandler] parse token permissions failed, because %s", err.Error())
+		return nil, errs.ErrTokenPermissionInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[tokens.TokenGeneratePersonalAccessHandler] failed to get user \"uid:%d\" info, because %s", uid, err.Error())
+		return nil, errs.ErrUserNotFound
+	}
+
+	if !a.users.IsPasswordEqualsUserPassword(generatePersonalAccessTokenReq.Password, user) {
+		return nil, errs.ErrUserPasswordWrong
+	}
+
+	token, claims, err := a.tokens.CreatePersonalAccessToken(c, user, generatePersonalAccessTokenReq.Name, permissions, generatePersonalAccessTokenReq.ExpiredInDays)
+
+	if err != nil {
+		log.Errorf(c, "[tokens.TokenGeneratePersonalAccessHandler] failed to create personal access token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrTokenGenerating)
+	}
+
+	log.Infof(c, "[tokens.TokenGeneratePersonalAccessHandler] user \"uid:%d\" has generated personal access token (permissions: %s), new token will be expired at %d", user.Uid, permissions.String(), claims.ExpiresAt)
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_TOKEN_GENERATED, fmt.Sprintf("personal access token (permissions: %s)", permissions.String()))
+
+	generatePersonalAccessTokenResp := &models.TokenGeneratePersonalAccessResponse{
+		Token:     token,
+		ExpiredAt: claims.ExpiresAt,
+	}
+
+	return generatePersonalAccessTokenResp, nil
+}
+
 // TokenRevokeCurrentHandler revokes current token of current user
 func (a *TokensApi) TokenRevokeCurrentHandler(c *core.WebContext) (any, *errs.Error) {
 	tokenString := c.GetTokenStringFromHeader()
@@ -164,6 +227,7 @@ func (a *TokensApi) TokenRevokeCurrentHandler(c *core.WebContext) (any, *errs.Er
 	}
 
 	log.Infof(c, "[tokens.TokenRevokeCurrentHandler] user \"uid:%d\" has revoked token \"id:%s\"", claims.Uid, tokenId)
+	a.auditEvents.CreateAuditEvent(c, claims.Uid, models.AUDIT_EVENT_TYPE_LOGOUT, fmt.Sprintf("token id: %s", tokenId))
 	return true, nil
 }
 
@@ -218,6 +282,7 @@ func (a *TokensApi) TokenRevokeHandler(c *core.WebContext) (any, *errs.Error) {
 	}
 
 	log.Infof(c, "[tokens.TokenRevokeHandler] user \"uid:%d\" has revoked token \"id:%s\"", uid, tokenRevokeReq.TokenId)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_TOKEN_REVOKED, fmt.Sprintf("token id: %s", tokenRevokeReq.TokenId))
 	return true, nil
 }
 
@@ -271,6 +336,7 @@ func (a *TokensApi) TokenRevokeAllHandler(c *core.WebContext) (any, *errs.Error)
 	}
 
 	log.Infof(c, "[tokens.TokenRevokeAllHandler] user \"uid:%d\" has revoked all tokens", uid)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_ALL_TOKENS_REVOKED, "")
 	return true, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -163,6 +164,7 @@ func (a *TransactionCategoriesApi) CategoryCreateHandler(c *core.WebContext) (an
 	}
 
 	log.Infof(c, "[transaction_categories.CategoryCreateHandler] user \"uid:%d\" has created a new category \"id:%d\" successfully", uid, category.CategoryId)
+	mcp.Sessions.NotifyResourceUpdated(uid, mcp.MCPTransactionCategoriesResourceHandler.URI())
 
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_CATEGORY, uid, categoryCreateReq.ClientSessionId, utils.Int64ToString(category.CategoryId))
 	categoryResp := category.ToTransactionCategoryInfoResponse()
@@ -269,6 +271,7 @@ func (a *TransactionCategoriesApi) CategoryModifyHandler(c *core.WebContext) (an
 	}
 
 	log.Infof(c, "[transaction_categories.CategoryModifyHandler] user \"uid:%d\" has updated category \"id:%d\" successfully", uid, categoryModifyReq.Id)
+	mcp.Sessions.NotifyResourceUpdated(uid, mcp.MCPTransactionCategoriesResourceHandler.URI())
 
 	newCategory.Type = category.Type
 	newCategory.DisplayOrder = category.DisplayOrder
@@ -296,6 +299,7 @@ func (a *TransactionCategoriesApi) CategoryHideHandler(c *core.WebContext) (any,
 	}
 
 	log.Infof(c, "[transaction_categories.CategoryHideHandler] user \"uid:%d\" has hidden category \"id:%d\"", uid, categoryHideReq.Id)
+	mcp.Sessions.NotifyResourceUpdated(uid, mcp.MCPTransactionCategoriesResourceHandler.URI())
 	return true, nil
 }
 
@@ -353,6 +357,7 @@ func (a *TransactionCategoriesApi) CategoryDeleteHandler(c *core.WebContext) (an
 	}
 
 	log.Infof(c, "[transaction_categories.CategoryDeleteHandler] user \"uid:%d\" has deleted category \"id:%d\"", uid, categoryDeleteReq.Id)
+	mcp.Sessions.NotifyResourceUpdated(uid, mcp.MCPTransactionCategoriesResourceHandler.URI())
 	return true, nil
 }
 
@@ -404,6 +409,7 @@ func (a *TransactionCategoriesApi) createBatchCategories(c *core.WebContext, uid
 	}
 
 	log.Infof(c, "[transaction_categories.createBatchCategories] user \"uid:%d\" has created categories successfully", uid)
+	mcp.Sessions.NotifyResourceUpdated(uid, mcp.MCPTransactionCategoriesResourceHandler.URI())
 
 	return categories, nil
 }
@@ -0,0 +1,511 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const defaultTransactionCountOfRuleTest = 100
+
+// TransactionRulesApi represents transaction rule api
+type TransactionRulesApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	rules        *services.TransactionRuleService
+	transactions *services.TransactionService
+	tags         *services.TransactionTagService
+	payees       *services.PayeeService
+}
+
+// Initialize a transaction rule api singleton instance
+var (
+	TransactionRules = &TransactionRulesApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		rules:        services.TransactionRules,
+		transactions: services.Transactions,
+		tags:         services.TransactionTags,
+		payees:       services.Payees,
+	}
+)
+
+// RuleListHandler returns transaction rule list of current user
+func (a *TransactionRulesApi) RuleListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	rules, err := a.rules.GetAllRulesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleListHandler] failed to get rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResps := make(models.TransactionRuleInfoResponseSlice, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		ruleResps[i] = rules[i].ToTransactionRuleInfoResponse()
+	}
+
+	sort.Sort(ruleResps)
+
+	return ruleResps, nil
+}
+
+// RuleGetHandler returns one specific transaction rule of current user
+func (a *TransactionRulesApi) RuleGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleGetReq models.TransactionRuleGetRequest
+	err := c.ShouldBindQuery(&ruleGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleGetHandler] failed to get rule \"id:%d\" for user \"uid:%d\", because %s", ruleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleCreateHandler saves a new transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleCreateReq models.TransactionRuleCreateRequest
+	err := c.ShouldBindJSON(&ruleCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+
+	maxOrderId, err := a.rules.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	rule := &models.TransactionRule{
+		Uid:                 uid,
+		Name:                ruleCreateReq.Name,
+		TransactionType:     ruleCreateReq.TransactionType,
+		AccountId:           ruleCreateReq.AccountId,
+		CommentPattern:      ruleCreateReq.CommentPattern,
+		CounterpartyPattern: ruleCreateReq.CounterpartyPattern,
+		MinAmount:           ruleCreateReq.MinAmount,
+		MaxAmount:           ruleCreateReq.MaxAmount,
+		CategoryId:          ruleCreateReq.CategoryId,
+		TagIds:              strings.Join(ruleCreateReq.TagIds, ","),
+		Splits:              ruleCreateReq.Splits,
+		RewriteComment:      ruleCreateReq.RewriteComment,
+		NewComment:          ruleCreateReq.NewComment,
+		StopProcessing:      ruleCreateReq.StopProcessing,
+		DisplayOrder:        maxOrderId + 1,
+	}
+
+	err = a.checkRule(rule, len(ruleCreateReq.TagIds))
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] rule is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && ruleCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_RULE, uid, ruleCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[transaction_rules.RuleCreateHandler] another rule \"id:%s\" has been created for user \"uid:%d\"", remark, uid)
+			ruleId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				rule, err = a.rules.GetRuleByRuleId(c, uid, ruleId)
+
+				if err != nil {
+					log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get existed rule \"id:%d\" for user \"uid:%d\", because %s", ruleId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				return rule.ToTransactionRuleInfoResponse(), nil
+			}
+		}
+	}
+
+	err = a.rules.CreateRule(c, rule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to create rule \"id:%d\" for user \"uid:%d\", because %s", rule.RuleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleCreateHandler] user \"uid:%d\" has created a new rule \"id:%d\" successfully", uid, rule.RuleId)
+
+	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_RULE, uid, ruleCreateReq.ClientSessionId, utils.Int64ToString(rule.RuleId))
+
+	return rule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleModifyHandler saves an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleModifyReq models.TransactionRuleModifyRequest
+	err := c.ShouldBindJSON(&ruleModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to get rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newRule := &models.TransactionRule{
+		RuleId:              rule.RuleId,
+		Uid:                 uid,
+		Name:                ruleModifyReq.Name,
+		TransactionType:     ruleModifyReq.TransactionType,
+		AccountId:           ruleModifyReq.AccountId,
+		CommentPattern:      ruleModifyReq.CommentPattern,
+		CounterpartyPattern: ruleModifyReq.CounterpartyPattern,
+		MinAmount:           ruleModifyReq.MinAmount,
+		MaxAmount:           ruleModifyReq.MaxAmount,
+		CategoryId:          ruleModifyReq.CategoryId,
+		TagIds:              strings.Join(ruleModifyReq.TagIds, ","),
+		Splits:              ruleModifyReq.Splits,
+		RewriteComment:      ruleModifyReq.RewriteComment,
+		NewComment:          ruleModifyReq.NewComment,
+		StopProcessing:      ruleModifyReq.StopProcessing,
+	}
+
+	err = a.checkRule(newRule, len(ruleModifyReq.TagIds))
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] rule is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if newRule.Name == rule.Name &&
+		newRule.TransactionType == rule.TransactionType &&
+		newRule.AccountId == rule.AccountId &&
+		newRule.CommentPattern == rule.CommentPattern &&
+		newRule.CounterpartyPattern == rule.CounterpartyPattern &&
+		a.isAmountEquals(newRule.MinAmount, rule.MinAmount) &&
+		a.isAmountEquals(newRule.MaxAmount, rule.MaxAmount) &&
+		newRule.CategoryId == rule.CategoryId &&
+		newRule.TagIds == rule.TagIds &&
+		models.IsTransactionRuleSplitsEqual(newRule.Splits, rule.Splits) &&
+		newRule.RewriteComment == rule.RewriteComment &&
+		newRule.NewComment == rule.NewComment &&
+		newRule.StopProcessing == rule.StopProcessing {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.rules.ModifyRule(c, newRule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to update rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleModifyHandler] user \"uid:%d\" has updated rule \"id:%d\" successfully", uid, ruleModifyReq.Id)
+
+	newRule.DisplayOrder = rule.DisplayOrder
+	newRule.Disabled = rule.Disabled
+
+	return newRule.ToTransactionRuleInfoResponse(), nil
+}
+
+// RuleDisableHandler enables or disables a transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleDisableHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleDisableReq models.TransactionRuleDisableRequest
+	err := c.ShouldBindJSON(&ruleDisableReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleDisableHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.rules.DisableRule(c, uid, []int64{ruleDisableReq.Id}, ruleDisableReq.Disabled)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDisableHandler] failed to disable rule \"id:%d\" for user \"uid:%d\", because %s", ruleDisableReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleDisableHandler] user \"uid:%d\" has set rule \"id:%d\" disabled to %t", uid, ruleDisableReq.Id, ruleDisableReq.Disabled)
+	return true, nil
+}
+
+// RuleMoveHandler moves display order (priority) of existed transaction rules by request parameters for current user
+func (a *TransactionRulesApi) RuleMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleMoveReq models.TransactionRuleMoveRequest
+	err := c.ShouldBindJSON(&ruleMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rules := make([]*models.TransactionRule, len(ruleMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(ruleMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := ruleMoveReq.NewDisplayOrders[i]
+		rule := &models.TransactionRule{
+			Uid:          uid,
+			RuleId:       newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		rules[i] = rule
+	}
+
+	err = a.rules.ModifyRuleDisplayOrders(c, uid, rules)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleMoveHandler] failed to move rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleMoveHandler] user \"uid:%d\" has moved rules", uid)
+	return true, nil
+}
+
+// RuleDeleteHandler deletes an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleDeleteReq models.TransactionRuleDeleteRequest
+	err := c.ShouldBindJSON(&ruleDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.rules.DeleteRule(c, uid, ruleDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDeleteHandler] failed to delete rule \"id:%d\" for user \"uid:%d\", because %s", ruleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleDeleteHandler] user \"uid:%d\" has deleted rule \"id:%d\"", uid, ruleDeleteReq.Id)
+	return true, nil
+}
+
+// RuleTestHandler returns the latest transactions of current user which match the specified rule and how they would be changed
+func (a *TransactionRulesApi) RuleTestHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleTestReq models.TransactionRuleTestRequest
+	err := c.ShouldBindJSON(&ruleTestReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleTestHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rule := &models.TransactionRule{
+		Uid:                 uid,
+		TransactionType:     ruleTestReq.TransactionType,
+		AccountId:           ruleTestReq.AccountId,
+		CommentPattern:      ruleTestReq.CommentPattern,
+		CounterpartyPattern: ruleTestReq.CounterpartyPattern,
+		MinAmount:           ruleTestReq.MinAmount,
+		MaxAmount:           ruleTestReq.MaxAmount,
+		CategoryId:          ruleTestReq.CategoryId,
+		TagIds:              strings.Join(ruleTestReq.TagIds, ","),
+		Splits:              ruleTestReq.Splits,
+		RewriteComment:      ruleTestReq.RewriteComment,
+		NewComment:          ruleTestReq.NewComment,
+	}
+
+	err = a.checkRule(rule, len(ruleTestReq.TagIds))
+
+	if err != nil && err != errs.ErrTransactionRuleHasNoAction {
+		log.Warnf(c, "[transaction_rules.RuleTestHandler] rule is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	matcher, err := rule.CreateMatcher()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	count := ruleTestReq.Count
+
+	if count < 1 {
+		count = defaultTransactionCountOfRuleTest
+	}
+
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
+	transactions, err := a.transactions.GetAllTransactionsByMaxTime(c, uid, maxTransactionTime, count, true)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleTestHandler] failed to get transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allTransactionTagIds, err := a.tags.GetAllTagIdsOfTransactions(c, uid, a.transactions.GetTransactionIds(transactions))
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleTestHandler] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	payeeIds := make([]int64, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].PayeeId > 0 {
+			payeeIds = append(payeeIds, transactions[i].PayeeId)
+		}
+	}
+
+	var payeeMap map[int64]*models.Payee
+
+	if len(payeeIds) > 0 {
+		payeeMap, err = a.payees.GetPayeesByPayeeIds(c, uid, utils.ToUniqueInt64Slice(payeeIds))
+
+		if err != nil {
+			log.Errorf(c, "[transaction_rules.RuleTestHandler] failed to get payees of transactions for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	matchers := []*models.TransactionRuleMatcher{matcher}
+	result := &models.TransactionRuleTestResponse{
+		TotalCount: int64(len(transactions)),
+		Items:      make([]*models.TransactionRuleTestResponseItem, 0),
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionType, err := transaction.Type.ToTransactionType()
+
+		if err != nil {
+			continue
+		}
+
+		originalCategoryId := transaction.CategoryId
+		originalComment := transaction.Comment
+		originalTagIds := allTransactionTagIds[transaction.TransactionId]
+		counterparty := ""
+
+		if payee, exists := payeeMap[transaction.PayeeId]; exists {
+			counterparty = payee.Name
+		}
+
+		newTagIds, matchedRuleIds := a.rules.ApplyRulesToTransaction(matchers, transaction, append([]int64{}, originalTagIds...), counterparty)
+
+		if len(matchedRuleIds) < 1 {
+			continue
+		}
+
+		item := &models.TransactionRuleTestResponseItem{
+			TransactionId: transaction.TransactionId,
+			Time:          utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime),
+			Type:          transactionType,
+			AccountId:     transaction.AccountId,
+			Amount:        transaction.Amount,
+			CategoryId:    originalCategoryId,
+			Comment:       originalComment,
+		}
+
+		if transaction.CategoryId != originalCategoryId {
+			item.NewCategoryId = transaction.CategoryId
+		}
+
+		if len(transaction.Splits) > 0 {
+			item.NewSplits = models.GetTransactionSplitInfoResponses(transaction.Splits)
+		}
+
+		if transaction.Comment != originalComment {
+			newComment := transaction.Comment
+			item.NewComment = &newComment
+		}
+
+		if len(newTagIds) > len(originalTagIds) {
+			item.AddedTagIds = utils.Int64ArrayToStringArray(newTagIds[len(originalTagIds):])
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	result.MatchedCount = int64(len(result.Items))
+
+	return result, nil
+}
+
+func (a *TransactionRulesApi) checkRule(rule *models.TransactionRule, tagCount int) error {
+	if tagCount > models.MaximumTagsCountOfTransactionRule {
+		return errs.ErrTransactionRuleHasTooManyTags
+	}
+
+	if rule.TransactionType == models.TRANSACTION_TYPE_MODIFY_BALANCE {
+		return errs.ErrTransactionRuleTypeInvalid
+	}
+
+	if rule.MinAmount != nil && rule.MaxAmount != nil && *rule.MinAmount > *rule.MaxAmount {
+		return errs.ErrTransactionRuleAmountRangeInvalid
+	}
+
+	if (rule.CategoryId > 0 || len(rule.Splits) > 0) && rule.TransactionType == 0 {
+		return errs.ErrTransactionRuleTypeRequiredForCategory
+	}
+
+	if len(rule.Splits) > 0 && rule.TransactionType != models.TRANSACTION_TYPE_INCOME && rule.TransactionType != models.TRANSACTION_TYPE_EXPENSE {
+		return errs.ErrTransactionSplitsNotSupported
+	}
+
+	if err := rule.ValidateSplits(); err != nil {
+		return err
+	}
+
+	if _, err := rule.CreateMatcher(); err != nil {
+		return err
+	}
+
+	if !rule.HasCondition() {
+		return errs.ErrTransactionRuleHasNoCondition
+	}
+
+	if !rule.HasAction() {
+		return errs.ErrTransactionRuleHasNoAction
+	}
+
+	return nil
+}
+
+func (a *TransactionRulesApi) isAmountEquals(amount1 *int64, amount2 *int64) bool {
+	if amount1 == nil || amount2 == nil {
+		return amount1 == amount2
+	}
+
+	return *amount1 == *amount2
+}
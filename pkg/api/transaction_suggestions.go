@@ -0,0 +1,76 @@
+package api
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+const defaultSuggestedCategoryCount = 3
+
+// TransactionSuggestionsApi represents transaction suggestion api
+type TransactionSuggestionsApi struct {
+	suggestions           *services.TransactionSuggestionService
+	transactionCategories *services.TransactionCategoryService
+	transactionTags       *services.TransactionTagService
+}
+
+// Initialize a transaction suggestion api singleton instance
+var (
+	TransactionSuggestions = &TransactionSuggestionsApi{
+		suggestions:           services.TransactionSuggestions,
+		transactionCategories: services.TransactionCategories,
+		transactionTags:       services.TransactionTags,
+	}
+)
+
+// TransactionSuggestionHandler returns the suggested categories and tags learned from transaction history of current user
+func (a *TransactionSuggestionsApi) TransactionSuggestionHandler(c *core.WebContext) (any, *errs.Error) {
+	var suggestionReq models.TransactionSuggestionRequest
+	err := c.ShouldBindQuery(&suggestionReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_suggestions.TransactionSuggestionHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	features := models.GetTransactionSuggestionFeatures(suggestionReq.Type, suggestionReq.AccountId, suggestionReq.Amount, suggestionReq.Comment)
+	model, err := a.suggestions.GetModelByUid(c, uid, features)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_suggestions.TransactionSuggestionHandler] failed to get suggestion model for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_suggestions.TransactionSuggestionHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	tags, err := a.transactionTags.GetAllTagsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_suggestions.TransactionSuggestionHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	categoryCount := int(suggestionReq.Count)
+
+	if categoryCount < 1 {
+		categoryCount = defaultSuggestedCategoryCount
+	}
+
+	candidateCategoryIds := a.suggestions.GetCandidateCategoryIds(categories, suggestionReq.Type)
+	candidateTagIds := a.suggestions.GetCandidateTagIds(tags)
+	suggestedCategories, suggestedTags := a.suggestions.GetSuggestions(model, suggestionReq.Type, suggestionReq.AccountId, suggestionReq.Amount, suggestionReq.Comment, candidateCategoryIds, candidateTagIds, categoryCount, models.MaximumTagsCountOfTransaction)
+
+	return &models.TransactionSuggestionResponse{
+		Categories: models.ToTransactionSuggestionItemResponseList(suggestedCategories),
+		Tags:       models.ToTransactionSuggestionItemResponseList(suggestedTags),
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
@@ -15,7 +16,9 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -28,12 +31,16 @@ const pageCountForAccountStatement = 1000
 type TransactionsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	transactions          *services.TransactionService
-	transactionCategories *services.TransactionCategoryService
-	transactionTags       *services.TransactionTagService
-	transactionPictures   *services.TransactionPictureService
-	accounts              *services.AccountService
-	users                 *services.UserService
+	transactions           *services.TransactionService
+	transactionCategories  *services.TransactionCategoryService
+	transactionTags        *services.TransactionTagService
+	payees                 *services.PayeeService
+	transactionPictures    *services.TransactionPictureService
+	transactionRules       *services.TransactionRuleService
+	transactionSuggestions *services.TransactionSuggestionService
+	accounts               *services.AccountService
+	users                  *services.UserService
+	exchangeRatesHistory   *services.ExchangeRatesHistoryService
 }
 
 // Initialize a transaction api singleton instance
@@ -48,12 +55,16 @@ var (
 			},
 			container: duplicatechecker.Container,
 		},
-		transactions:          services.Transactions,
-		transactionCategories: services.TransactionCategories,
-		transactionTags:       services.TransactionTags,
-		transactionPictures:   services.TransactionPictures,
-		accounts:              services.Accounts,
-		users:                 services.Users,
+		transactions:           services.Transactions,
+		transactionCategories:  services.TransactionCategories,
+		transactionTags:        services.TransactionTags,
+		payees:                 services.Payees,
+		transactionPictures:    services.TransactionPictures,
+		transactionRules:       services.TransactionRules,
+		transactionSuggestions: services.TransactionSuggestions,
+		accounts:               services.Accounts,
+		users:                  services.Users,
+		exchangeRatesHistory:   services.ExchangeRatesHistory,
 	}
 )
 
@@ -95,7 +106,21 @@ func (a *TransactionsApi) TransactionCountHandler(c *core.WebContext) (any, *err
 		}
 	}
 
-	totalCount, err := a.transactions.GetTransactionCount(c, uid, transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionCountReq.TagFilterType, transactionCountReq.AmountFilter, transactionCountReq.Keyword)
+	allPayeeIds, err := a.payees.GetPayeeIds(transactionCountReq.PayeeIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCountHandler] get payee ids error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	searchQuery, err := a.parseTransactionSearchQuery(c, uid, transactionCountReq.Query)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCountHandler] parse search query \"%s\" failed, because %s", transactionCountReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionSearchQueryInvalid)
+	}
+
+	totalCount, err := a.transactions.GetTransactionCount(c, uid, transactionCountReq.MaxTime, transactionCountReq.MinTime, transactionCountReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionCountReq.TagFilterType, allPayeeIds, transactionCountReq.AmountFilter, transactionCountReq.Keyword, searchQuery)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCountHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -163,10 +188,24 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		}
 	}
 
+	allPayeeIds, err := a.payees.GetPayeeIds(transactionListReq.PayeeIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionListHandler] get payee ids error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	searchQuery, err := a.parseTransactionSearchQuery(c, uid, transactionListReq.Query)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionListHandler] parse search query \"%s\" failed, because %s", transactionListReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionSearchQueryInvalid)
+	}
+
 	var totalCount int64
 
 	if transactionListReq.WithCount {
-		totalCount, err = a.transactions.GetTransactionCount(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword)
+		totalCount, err = a.transactions.GetTransactionCount(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, allPayeeIds, transactionListReq.AmountFilter, transactionListReq.Keyword, searchQuery)
 
 		if err != nil {
 			log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
@@ -174,7 +213,7 @@ func (a *TransactionsApi) TransactionListHandler(c *core.WebContext) (any, *errs
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword, transactionListReq.Page, transactionListReq.Count, true, true)
+	transactions, err := a.transactions.GetTransactionsByMaxTime(c, uid, transactionListReq.MaxTime, transactionListReq.MinTime, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, allPayeeIds, transactionListReq.AmountFilter, transactionListReq.Keyword, searchQuery, transactionListReq.Page, transactionListReq.Count, true, true)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get transactions earlier than \"%d\" for user \"uid:%d\", because %s", transactionListReq.MaxTime, uid, err.Error())
@@ -266,7 +305,21 @@ func (a *TransactionsApi) TransactionMonthListHandler(c *core.WebContext) (any,
 		}
 	}
 
-	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, transactionListReq.AmountFilter, transactionListReq.Keyword)
+	allPayeeIds, err := a.payees.GetPayeeIds(transactionListReq.PayeeIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionMonthListHandler] get payee ids error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	searchQuery, err := a.parseTransactionSearchQuery(c, uid, transactionListReq.Query)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionMonthListHandler] parse search query \"%s\" failed, because %s", transactionListReq.Query, err.Error())
+		return nil, errs.Or(err, errs.ErrTransactionSearchQueryInvalid)
+	}
+
+	transactions, err := a.transactions.GetTransactionsInMonthByPage(c, uid, transactionListReq.Year, transactionListReq.Month, transactionListReq.Type, allCategoryIds, allAccountIds, allTagIds, noTags, transactionListReq.TagFilterType, allPayeeIds, transactionListReq.AmountFilter, transactionListReq.Keyword, searchQuery)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionMonthListHandler] failed to get transactions in month \"%d-%d\" for user \"uid:%d\", because %s", transactionListReq.Year, transactionListReq.Month, uid, err.Error())
@@ -425,30 +478,46 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 		}
 	}
 
+	allPayeeIds, err := a.payees.GetPayeeIds(statisticReq.PayeeIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsHandler] get payee ids error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, allTagIds, noTags, statisticReq.TagFilterType, statisticReq.Keyword, utcOffset, statisticReq.UseTransactionTimezone)
+	exchangeRatesConverter, accountMap, err := a.getReportingCurrencyExchangeRatesConverter(c, uid, statisticReq.ReportingCurrency, statisticReq.StartTime, statisticReq.EndTime)
 
 	if err != nil {
-		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get exchange rates converter for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	statisticResp := &models.TransactionStatisticResponse{
-		StartTime: statisticReq.StartTime,
-		EndTime:   statisticReq.EndTime,
+	var totalAmounts []*models.Transaction
+
+	// the amounts need to be exchanged with the exchange rate of every transaction date or the exchange rate stored in every transaction
+	if exchangeRatesConverter != nil {
+		totalAmounts, err = a.transactions.GetAccountsAndCategoriesDailyIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, allTagIds, noTags, statisticReq.TagFilterType, allPayeeIds, statisticReq.Keyword, utcOffset, statisticReq.UseTransactionTimezone)
+	} else {
+		totalAmounts, err = a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, allTagIds, noTags, statisticReq.TagFilterType, allPayeeIds, statisticReq.Keyword, utcOffset, statisticReq.UseTransactionTimezone)
 	}
 
-	statisticResp.Items = make([]*models.TransactionStatisticResponseItem, len(totalAmounts))
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
 
-	for i := 0; i < len(totalAmounts); i++ {
-		totalAmountItem := totalAmounts[i]
-		statisticResp.Items[i] = &models.TransactionStatisticResponseItem{
-			CategoryId:  totalAmountItem.CategoryId,
-			AccountId:   totalAmountItem.AccountId,
-			TotalAmount: totalAmountItem.Amount,
-		}
+	unconvertedCurrencies := make(map[string]bool)
+
+	statisticResp := &models.TransactionStatisticResponse{
+		StartTime:         statisticReq.StartTime,
+		EndTime:           statisticReq.EndTime,
+		Items:             a.getTransactionStatisticResponseItems(totalAmounts, accountMap, exchangeRatesConverter, statisticReq.ReportingCurrency, statisticReq.GroupByPayee, unconvertedCurrencies),
+		ReportingCurrency: statisticReq.ReportingCurrency,
 	}
 
+	statisticResp.UnconvertedCurrencies = getSortedCurrencies(unconvertedCurrencies)
+
 	return statisticResp, nil
 }
 
@@ -488,8 +557,31 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 		}
 	}
 
+	allPayeeIds, err := a.payees.GetPayeeIds(statisticTrendsReq.PayeeIds)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionStatisticsTrendsHandler] get payee ids error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, allTagIds, noTags, statisticTrendsReq.TagFilterType, statisticTrendsReq.Keyword, utcOffset, statisticTrendsReq.UseTransactionTimezone)
+	startUnixTime := time.Date(int(startYear), time.Month(startMonth), 1, 0, 0, 0, 0, time.UTC).Unix()
+	endUnixTime := time.Date(int(endYear), time.Month(endMonth)+1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	exchangeRatesConverter, accountMap, err := a.getReportingCurrencyExchangeRatesConverter(c, uid, statisticTrendsReq.ReportingCurrency, startUnixTime, endUnixTime)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get exchange rates converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var allMonthlyTotalAmounts map[int32][]*models.Transaction
+
+	// the amounts need to be exchanged with the exchange rate of every transaction date or the exchange rate stored in every transaction
+	if exchangeRatesConverter != nil {
+		allMonthlyTotalAmounts, err = a.getAccountsAndCategoriesMonthlyDailyIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, allTagIds, noTags, statisticTrendsReq.TagFilterType, allPayeeIds, statisticTrendsReq.Keyword, utcOffset, statisticTrendsReq.UseTransactionTimezone)
+	} else {
+		allMonthlyTotalAmounts, err = a.transactions.GetAccountsAndCategoriesMonthlyIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, allTagIds, noTags, statisticTrendsReq.TagFilterType, allPayeeIds, statisticTrendsReq.Keyword, utcOffset, statisticTrendsReq.UseTransactionTimezone)
+	}
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -499,21 +591,16 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 	statisticTrendsResp := make(models.TransactionStatisticTrendsResponseItemSlice, 0, len(allMonthlyTotalAmounts))
 
 	for yearMonth, monthlyTotalAmounts := range allMonthlyTotalAmounts {
-		monthlyStatisticResp := &models.TransactionStatisticTrendsResponseItem{
-			Year:  yearMonth / 100,
-			Month: yearMonth % 100,
-			Items: make([]*models.TransactionStatisticResponseItem, len(monthlyTotalAmounts)),
-		}
+		unconvertedCurrencies := make(map[string]bool)
 
-		for i := 0; i < len(monthlyTotalAmounts); i++ {
-			totalAmountItem := monthlyTotalAmounts[i]
-			monthlyStatisticResp.Items[i] = &models.TransactionStatisticResponseItem{
-				CategoryId:  totalAmountItem.CategoryId,
-				AccountId:   totalAmountItem.AccountId,
-				TotalAmount: totalAmountItem.Amount,
-			}
+		monthlyStatisticResp := &models.TransactionStatisticTrendsResponseItem{
+			Year:              yearMonth / 100,
+			Month:             yearMonth % 100,
+			Items:             a.getTransactionStatisticResponseItems(monthlyTotalAmounts, accountMap, exchangeRatesConverter, statisticTrendsReq.ReportingCurrency, statisticTrendsReq.GroupByPayee, unconvertedCurrencies),
+			ReportingCurrency: statisticTrendsReq.ReportingCurrency,
 		}
 
+		monthlyStatisticResp.UnconvertedCurrencies = getSortedCurrencies(unconvertedCurrencies)
 		statisticTrendsResp = append(statisticTrendsResp, monthlyStatisticResp)
 	}
 
@@ -566,6 +653,25 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	var exchangeRatesConverter *exchangerates.ExchangeRatesConverter
+
+	if transactionAmountsReq.ReportingCurrency != "" {
+		startUnixTime := requestItems[0].StartTime
+		endUnixTime := requestItems[0].EndTime
+
+		for i := 1; i < len(requestItems); i++ {
+			startUnixTime = min(startUnixTime, requestItems[i].StartTime)
+			endUnixTime = max(endUnixTime, requestItems[i].EndTime)
+		}
+
+		exchangeRatesConverter, err = a.getExchangeRatesConverter(c, uid, startUnixTime, endUnixTime)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionAmountsHandler] failed to get exchange rates converter for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	amountsResp := orderedmap.New[string, *models.TransactionAmountsResponseItem]()
 
 	for i := 0; i < len(requestItems); i++ {
@@ -632,11 +738,22 @@ func (a *TransactionsApi) TransactionAmountsHandler(c *core.WebContext) (any, *e
 
 		sort.Sort(allTotalAmounts)
 
-		amountsResp.Set(requestItem.Name, &models.TransactionAmountsResponseItem{
+		amountsRespItem := &models.TransactionAmountsResponseItem{
 			StartTime: requestItem.StartTime,
 			EndTime:   requestItem.EndTime,
 			Amounts:   allTotalAmounts,
-		})
+		}
+
+		if exchangeRatesConverter != nil {
+			err = a.fillTransactionAmountsResponseItemConvertedAmounts(c, uid, amountsRespItem, accountMap, exchangeRatesConverter, transactionAmountsReq.ReportingCurrency, utcOffset, transactionAmountsReq.UseTransactionTimezone)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.TransactionAmountsHandler] failed to get converted transaction amounts item for user \"uid:%d\", because %s", uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+		}
+
+		amountsResp.Set(requestItem.Name, amountsRespItem)
 	}
 
 	return amountsResp, nil
@@ -710,6 +827,13 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	err = a.transactions.FillTransactionSplits(c, uid, []*models.Transaction{transaction})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transaction split lines for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	var category *models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfos []*models.TransactionPictureInfo
@@ -804,6 +928,13 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	splits, err := models.ToTransactionSplits(transactionCreateReq.Splits)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] parse split lines failed, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
+	}
+
 	if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 		log.Warnf(c, "[transactions.TransactionCreateHandler] transaction type is invalid")
 		return nil, errs.ErrTransactionTypeInvalid
@@ -839,12 +970,37 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	}
 
 	transaction := a.createNewTransactionModel(uid, &transactionCreateReq, c.ClientIP())
+	transaction.Splits = splits
 	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionCreateReq.UtcOffset)
 
 	if !transactionEditable {
 		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
 	}
 
+	if transactionCreateReq.ApplyRules {
+		ruleMatchers, err := a.transactionRules.GetEnabledRuleMatchersByUid(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		counterparty := transactionCreateReq.PayeeName
+
+		if transactionCreateReq.PayeeId > 0 {
+			payee, err := a.payees.GetPayeeByPayeeId(c, uid, transactionCreateReq.PayeeId)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get payee \"id:%d\" for user \"uid:%d\", because %s", transactionCreateReq.PayeeId, uid, err.Error())
+				return nil, errs.Or(err, errs.ErrOperationFailed)
+			}
+
+			counterparty = payee.Name
+		}
+
+		tagIds, _ = a.transactionRules.ApplyRulesToTransaction(ruleMatchers, transaction, tagIds, counterparty)
+	}
+
 	var pictureInfos []*models.TransactionPictureInfo
 
 	if len(pictureIds) > 0 {
@@ -886,6 +1042,8 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		}
 	}
 
+	a.fillTransactionExchangeRate(c, user, transaction, transaction.Type)
+
 	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds)
 
 	if err != nil {
@@ -894,6 +1052,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	}
 
 	log.Infof(c, "[transactions.TransactionCreateHandler] user \"uid:%d\" has created a new transaction \"id:%d\" successfully", uid, transaction.TransactionId)
+	mcp.Sessions.NotifyTransactionsUpdated(uid, transaction.TransactionTime)
 
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, transactionCreateReq.ClientSessionId, utils.Int64ToString(transaction.TransactionId))
 	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
@@ -934,6 +1093,22 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionHasTooManyPictures
 	}
 
+	splits, err := models.ToTransactionSplits(transactionModifyReq.Splits)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionModifyHandler] parse split lines failed, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
+	}
+
+	// Existing split lines are kept if the request does not contain split lines, and removed if the request contains empty split lines
+	if transactionModifyReq.Splits != nil && splits == nil {
+		splits = make([]*models.TransactionSplit, 0)
+	}
+
+	if len(splits) > 0 {
+		transactionModifyReq.CategoryId = splits[0].CategoryId
+	}
+
 	uid := c.GetCurrentUid()
 	user, err := a.users.GetUserById(c, uid)
 
@@ -987,6 +1162,13 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	transactionPictureIds := a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos)
 
+	err = a.transactions.FillTransactionSplits(c, uid, []*models.Transaction{transaction})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction split lines for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	newTransaction := &models.Transaction{
 		TransactionId:     transaction.TransactionId,
 		Uid:               uid,
@@ -996,7 +1178,13 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		AccountId:         transactionModifyReq.SourceAccountId,
 		Amount:            transactionModifyReq.SourceAmount,
 		HideAmount:        transactionModifyReq.HideAmount,
+		PayeeId:           transactionModifyReq.PayeeId,
 		Comment:           transactionModifyReq.Comment,
+		Splits:            splits,
+	}
+
+	if splits == nil && len(transaction.Splits) > 0 {
+		newTransaction.CategoryId = transaction.Splits[0].CategoryId
 	}
 
 	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
@@ -1017,11 +1205,13 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountId == transaction.RelatedAccountId) &&
 		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountAmount == transaction.RelatedAccountAmount) &&
 		newTransaction.HideAmount == transaction.HideAmount &&
+		newTransaction.PayeeId == transaction.PayeeId &&
 		newTransaction.Comment == transaction.Comment &&
 		newTransaction.GeoLongitude == transaction.GeoLongitude &&
 		newTransaction.GeoLatitude == transaction.GeoLatitude &&
 		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
-		utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
+		utils.Int64SliceEquals(pictureIds, transactionPictureIds) &&
+		(newTransaction.Splits == nil || models.IsTransactionSplitsEqual(newTransaction.Splits, transaction.Splits)) {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
@@ -1079,6 +1269,17 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		}
 	}
 
+	// the stored exchange rate would be kept unless the account or the transaction date is changed
+	if newTransaction.AccountId == transaction.AccountId &&
+		utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime) == utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) &&
+		newTransaction.TimezoneUtcOffset == transaction.TimezoneUtcOffset &&
+		transaction.ExchangeRateCurrency == user.DefaultCurrency {
+		newTransaction.ExchangeRateCurrency = transaction.ExchangeRateCurrency
+		newTransaction.ExchangeRate = transaction.ExchangeRate
+	} else {
+		a.fillTransactionExchangeRate(c, user, newTransaction, transaction.Type)
+	}
+
 	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds)
 
 	if err != nil {
@@ -1087,6 +1288,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 	}
 
 	log.Infof(c, "[transactions.TransactionModifyHandler] user \"uid:%d\" has updated transaction \"id:%d\" successfully", uid, transactionModifyReq.Id)
+	mcp.Sessions.NotifyTransactionsUpdated(uid, transaction.TransactionTime, newTransaction.TransactionTime)
 
 	newTransaction.Type = transaction.Type
 	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
@@ -1149,6 +1351,7 @@ func (a *TransactionsApi) TransactionDeleteHandler(c *core.WebContext) (any, *er
 	}
 
 	log.Infof(c, "[transactions.TransactionDeleteHandler] user \"uid:%d\" has deleted transaction \"id:%d\"", uid, transactionDeleteReq.Id)
+	mcp.Sessions.NotifyTransactionsUpdated(uid, transaction.TransactionTime)
 	return true, nil
 }
 
@@ -1437,6 +1640,39 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	ruleMatchers, err := a.transactionRules.GetEnabledRuleMatchersByUid(c, user.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get transaction rules for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	a.transactionRules.ApplyRulesToImportTransactions(ruleMatchers, parsedTransactions, a.transactionCategories.GetCategoryMapByList(categories))
+
+	payees, err := a.payees.GetAllPayeesByUid(c, user.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get payees for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	for i := 0; i < len(parsedTransactions); i++ {
+		payee := a.payees.GetPayeeByName(payees, parsedTransactions[i].OriginalPayeeName)
+
+		if payee != nil && !payee.Hidden {
+			parsedTransactions[i].PayeeId = payee.PayeeId
+		}
+	}
+
+	suggestionModel, err := a.transactionSuggestions.GetModelByUid(c, user.Uid, nil)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to get suggestion model for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	a.transactionSuggestions.ApplySuggestionsToImportTransactions(suggestionModel, parsedTransactions, categories, tags)
+
 	parsedTransactionRespsList := parsedTransactions.ToImportTransactionResponseList()
 
 	if len(parsedTransactionRespsList) < 1 {
@@ -1487,6 +1723,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 	}
 
 	newTransactionTagIdsMap := make(map[int][]int64, len(transactionImportReq.Transactions))
+	newTransactionSplitsMap := make(map[int][]*models.TransactionSplit)
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
@@ -1501,6 +1738,17 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 			return nil, errs.ErrTransactionHasTooManyTags
 		}
 
+		splits, err := models.ToTransactionSplits(transactionCreateReq.Splits)
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionImportHandler] parse split lines failed of transaction \"index:%d\", because %s", i, err.Error())
+			return nil, errs.Or(err, errs.ErrIncompleteOrIncorrectSubmission)
+		}
+
+		if len(splits) > 0 {
+			newTransactionSplitsMap[i] = splits
+		}
+
 		if transactionCreateReq.Type < models.TRANSACTION_TYPE_MODIFY_BALANCE || transactionCreateReq.Type > models.TRANSACTION_TYPE_TRANSFER {
 			log.Warnf(c, "[transactions.TransactionImportHandler] transaction type of transaction \"index:%d\" is invalid", i)
 			return nil, errs.ErrTransactionTypeInvalid
@@ -1541,11 +1789,40 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
+	newPayeeNames := make([]string, 0)
+
+	for i := 0; i < len(transactionImportReq.Transactions); i++ {
+		transactionCreateReq := transactionImportReq.Transactions[i]
+
+		if transactionCreateReq.PayeeId == 0 && transactionCreateReq.PayeeName != "" && transactionCreateReq.Type != models.TRANSACTION_TYPE_MODIFY_BALANCE {
+			newPayeeNames = append(newPayeeNames, transactionCreateReq.PayeeName)
+		}
+	}
+
+	var payeeNameMap map[string]*models.Payee
+	var newPayees []*models.Payee
+
+	// new payees are saved in the same database transaction with the imported transactions
+	if len(newPayeeNames) > 0 {
+		payeeNameMap, newPayees, err = a.payees.PreparePayeesByNames(c, uid, newPayeeNames)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionImportHandler] failed to prepare payees for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
 	newTransactions := make([]*models.Transaction, len(transactionImportReq.Transactions))
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
 		transaction := a.createNewTransactionModel(uid, transactionCreateReq, c.ClientIP())
+		transaction.Splits = newTransactionSplitsMap[i]
+
+		if payee, exists := payeeNameMap[strings.TrimSpace(transactionCreateReq.PayeeName)]; exists && transaction.PayeeId == 0 && transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			transaction.PayeeId = payee.PayeeId
+		}
+
 		transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionCreateReq.UtcOffset)
 
 		if !transactionEditable {
@@ -1555,7 +1832,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		newTransactions[i] = transaction
 	}
 
-	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, func(currentProcess float64) {
+	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, newPayees, func(currentProcess float64) {
 		a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("processing:%.2f", currentProcess))
 	})
 	count := len(newTransactions)
@@ -1568,6 +1845,14 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 
 	log.Infof(c, "[transactions.TransactionImportHandler] user \"uid:%d\" has imported %d transactions successfully", uid, count)
 
+	importedTransactionTimes := make([]int64, count)
+
+	for i := 0; i < count; i++ {
+		importedTransactionTimes[i] = newTransactions[i].TransactionTime
+	}
+
+	mcp.Sessions.NotifyTransactionsUpdated(uid, importedTransactionTimes...)
+
 	a.SetSubmissionRemarkIfEnable(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, fmt.Sprintf("finished:%d", count))
 
 	return count, nil
@@ -1702,6 +1987,13 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		return nil, err
 	}
 
+	err = a.transactions.FillTransactionSplits(c, uid, transactions)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getTransactionResponseListResult] failed to get transactions split lines for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
 	var categoryMap map[int64]*models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfoMap map[int64][]*models.TransactionPictureInfo
@@ -1780,6 +2072,55 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 	return result, nil
 }
 
+func (a *TransactionsApi) parseTransactionSearchQuery(c *core.WebContext, uid int64, query string) (*models.TransactionSearchQueryNode, error) {
+	searchQuery, err := models.ParseTransactionSearchQuery(query)
+
+	if err != nil || searchQuery == nil {
+		return nil, err
+	}
+
+	var accounts []*models.Account
+	var categories []*models.TransactionCategory
+	var tags []*models.TransactionTag
+	var payees []*models.Payee
+
+	if searchQuery.HasField(models.TRANSACTION_SEARCH_QUERY_FIELD_ACCOUNT) {
+		accounts, err = a.accounts.GetAllAccountsByUid(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if searchQuery.HasField(models.TRANSACTION_SEARCH_QUERY_FIELD_CATEGORY) {
+		categories, err = a.transactionCategories.GetAllCategoriesByUid(c, uid, 0, -1)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if searchQuery.HasField(models.TRANSACTION_SEARCH_QUERY_FIELD_TAG) {
+		tags, err = a.transactionTags.GetAllTagsByUid(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if searchQuery.HasField(models.TRANSACTION_SEARCH_QUERY_FIELD_PAYEE) {
+		payees, err = a.payees.GetAllPayeesByUid(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	searchQuery.ResolveNames(accounts, categories, tags, payees)
+
+	return searchQuery, nil
+}
+
 func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
@@ -1802,6 +2143,7 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 		AccountId:         transactionCreateReq.SourceAccountId,
 		Amount:            transactionCreateReq.SourceAmount,
 		HideAmount:        transactionCreateReq.HideAmount,
+		PayeeId:           transactionCreateReq.PayeeId,
 		Comment:           transactionCreateReq.Comment,
 		CreatedIp:         clientIp,
 	}
@@ -1818,3 +2160,228 @@ func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreate
 
 	return transaction
 }
+
+// fillTransactionExchangeRate stores the exchange rate from the account currency to the default currency of user effective on the transaction date in the income or expense transaction,
+// so that the exchanged amount of the transaction would not be changed by the exchange rates updated later
+func (a *TransactionsApi) fillTransactionExchangeRate(c *core.WebContext, user *models.User, transaction *models.Transaction, transactionDbType models.TransactionDbType) {
+	transaction.ExchangeRateCurrency = ""
+	transaction.ExchangeRate = 0
+
+	if transactionDbType != models.TRANSACTION_DB_TYPE_INCOME && transactionDbType != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return
+	}
+
+	account, err := a.accounts.GetAccountByAccountId(c, user.Uid, transaction.AccountId)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.fillTransactionExchangeRate] failed to get account \"id:%d\" for user \"uid:%d\", because %s", transaction.AccountId, user.Uid, err.Error())
+		return
+	}
+
+	if user.DefaultCurrency == "" || account.Currency == user.DefaultCurrency {
+		return
+	}
+
+	unixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	exchangeRatesConverter, err := a.getExchangeRatesConverter(c, user.Uid, unixTime, unixTime)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.fillTransactionExchangeRate] failed to get exchange rates converter for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return
+	}
+
+	exchangeRate, exists := exchangeRatesConverter.GetTransactionExchangeRate(transaction, account.Currency, user.DefaultCurrency)
+
+	if !exists || exchangeRate <= 0 {
+		return
+	}
+
+	transaction.ExchangeRateCurrency = user.DefaultCurrency
+	transaction.ExchangeRate = exchangeRate
+}
+
+func (a *TransactionsApi) getReportingCurrencyExchangeRatesConverter(c *core.WebContext, uid int64, reportingCurrency string, startUnixTime int64, endUnixTime int64) (*exchangerates.ExchangeRatesConverter, map[int64]*models.Account, error) {
+	if reportingCurrency == "" {
+		return nil, nil, nil
+	}
+
+	exchangeRatesConverter, err := a.getExchangeRatesConverter(c, uid, startUnixTime, endUnixTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return exchangeRatesConverter, a.accounts.GetAccountMapByList(accounts), nil
+}
+
+func (a *TransactionsApi) getExchangeRatesConverter(c *core.WebContext, uid int64, startUnixTime int64, endUnixTime int64) (*exchangerates.ExchangeRatesConverter, error) {
+	exchangeRateHistories, err := a.exchangeRatesHistory.GetExchangeRatesByTimeRange(c, a.CurrentConfig().ExchangeRatesDataSource, startUnixTime, endUnixTime)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getExchangeRatesConverter] failed to get historical exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	return exchangerates.Container.NewExchangeRatesConverter(c, uid, a.CurrentConfig(), exchangeRateHistories)
+}
+
+func (a *TransactionsApi) getAccountsAndCategoriesMonthlyDailyIncomeAndExpense(c *core.WebContext, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, keyword string, utcOffset int16, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	var startUnixTime, endUnixTime int64
+
+	if startYear > 0 && startMonth > 0 {
+		startUnixTime = time.Date(int(startYear), time.Month(startMonth), 1, 0, 0, 0, 0, clientLocation).Unix()
+	}
+
+	if endYear > 0 && endMonth > 0 {
+		endUnixTime = time.Date(int(endYear), time.Month(endMonth)+1, 1, 0, 0, 0, 0, clientLocation).Unix() - 1
+	}
+
+	dailyAmounts, err := a.transactions.GetAccountsAndCategoriesDailyIncomeAndExpense(c, uid, startUnixTime, endUnixTime, tagIds, noTags, tagFilterType, payeeIds, keyword, utcOffset, useTransactionTimezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	monthlyDailyAmounts := make(map[int32][]*models.Transaction)
+
+	for i := 0; i < len(dailyAmounts); i++ {
+		dailyAmount := dailyAmounts[i]
+		timeZone := clientLocation
+
+		if useTransactionTimezone {
+			timeZone = time.FixedZone("Transaction Timezone", int(dailyAmount.TimezoneUtcOffset)*60)
+		}
+
+		yearMonth := utils.FormatUnixTimeToNumericYearMonth(utils.GetUnixTimeFromTransactionTime(dailyAmount.TransactionTime), timeZone)
+		monthlyDailyAmounts[yearMonth] = append(monthlyDailyAmounts[yearMonth], dailyAmount)
+	}
+
+	return monthlyDailyAmounts, nil
+}
+
+func (a *TransactionsApi) getTransactionStatisticResponseItems(totalAmounts []*models.Transaction, accountMap map[int64]*models.Account, exchangeRatesConverter *exchangerates.ExchangeRatesConverter, reportingCurrency string, groupByPayee bool, unconvertedCurrencies map[string]bool) []*models.TransactionStatisticResponseItem {
+	items := make([]*models.TransactionStatisticResponseItem, 0, len(totalAmounts))
+	itemsMap := make(map[string]*models.TransactionStatisticResponseItem, len(totalAmounts))
+	unconvertedItems := make(map[string]bool)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmountItem := totalAmounts[i]
+		payeeId := int64(0)
+
+		if groupByPayee {
+			payeeId = totalAmountItem.PayeeId
+		}
+
+		groupKey := fmt.Sprintf("%d_%d_%d", totalAmountItem.CategoryId, totalAmountItem.AccountId, payeeId)
+		item, exists := itemsMap[groupKey]
+
+		if !exists {
+			item = &models.TransactionStatisticResponseItem{
+				CategoryId:  totalAmountItem.CategoryId,
+				AccountId:   totalAmountItem.AccountId,
+				PayeeId:     payeeId,
+				TotalAmount: 0,
+			}
+
+			itemsMap[groupKey] = item
+			items = append(items, item)
+		}
+
+		item.TotalAmount += totalAmountItem.Amount
+
+		if exchangeRatesConverter == nil || unconvertedItems[groupKey] {
+			continue
+		}
+
+		account, exists := accountMap[totalAmountItem.AccountId]
+
+		if !exists {
+			unconvertedItems[groupKey] = true
+			continue
+		}
+
+		convertedAmount, converted := exchangeRatesConverter.GetTransactionExchangedAmount(totalAmountItem, account.Currency, "", reportingCurrency)
+
+		if !converted {
+			unconvertedCurrencies[account.Currency] = true
+			unconvertedItems[groupKey] = true
+			continue
+		}
+
+		if item.ConvertedAmount == nil {
+			item.ConvertedAmount = new(int64)
+		}
+
+		*item.ConvertedAmount += convertedAmount
+	}
+
+	for groupKey := range unconvertedItems {
+		itemsMap[groupKey].ConvertedAmount = nil
+	}
+
+	return items
+}
+
+func (a *TransactionsApi) fillTransactionAmountsResponseItemConvertedAmounts(c *core.WebContext, uid int64, amountsRespItem *models.TransactionAmountsResponseItem, accountMap map[int64]*models.Account, exchangeRatesConverter *exchangerates.ExchangeRatesConverter, reportingCurrency string, utcOffset int16, useTransactionTimezone bool) error {
+	convertedIncomeAmount := int64(0)
+	convertedExpenseAmount := int64(0)
+	unconvertedCurrencies := make(map[string]bool)
+
+	dailyAmounts, err := a.transactions.GetAccountsAndCategoriesDailyIncomeAndExpense(c, uid, amountsRespItem.StartTime, amountsRespItem.EndTime, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, "", utcOffset, useTransactionTimezone)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(dailyAmounts); i++ {
+		dailyAmount := dailyAmounts[i]
+		account, exists := accountMap[dailyAmount.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		convertedAmount, converted := exchangeRatesConverter.GetTransactionExchangedAmount(dailyAmount, account.Currency, "", reportingCurrency)
+
+		if !converted {
+			unconvertedCurrencies[account.Currency] = true
+			continue
+		}
+
+		if dailyAmount.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			convertedIncomeAmount += convertedAmount
+		} else if dailyAmount.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			convertedExpenseAmount += convertedAmount
+		}
+	}
+
+	amountsRespItem.ReportingCurrency = reportingCurrency
+	amountsRespItem.ConvertedIncomeAmount = &convertedIncomeAmount
+	amountsRespItem.ConvertedExpenseAmount = &convertedExpenseAmount
+	amountsRespItem.UnconvertedCurrencies = getSortedCurrencies(unconvertedCurrencies)
+
+	return nil
+}
+
+func getSortedCurrencies(currencies map[string]bool) []string {
+	if len(currencies) == 0 {
+		return nil
+	}
+
+	sortedCurrencies := make([]string, 0, len(currencies))
+
+	for currency := range currencies {
+		sortedCurrencies = append(sortedCurrencies, currency)
+	}
+
+	sort.Strings(sortedCurrencies)
+
+	return sortedCurrencies
+}
@@ -20,6 +20,7 @@ type TwoFactorAuthorizationsApi struct {
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	users                   *services.UserService
 	tokens                  *services.TokenService
+	auditEvents             *services.AuditEventService
 }
 
 // Initialize a 2fa api singleton instance
@@ -28,6 +29,7 @@ var (
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		users:                   services.Users,
 		tokens:                  services.Tokens,
+		auditEvents:             services.AuditEvents,
 	}
 )
 
@@ -181,6 +183,7 @@ func (a *TwoFactorAuthorizationsApi) TwoFactorEnableConfirmHandler(c *core.WebCo
 	}
 
 	log.Infof(c, "[twofactor_authorizations.TwoFactorEnableConfirmHandler] user \"uid:%d\" has enabled two-factor authorization", uid)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_ENABLED, "")
 
 	now := time.Now().Unix()
 	err = a.tokens.DeleteTokensBeforeTime(c, uid, now)
@@ -271,6 +274,7 @@ func (a *TwoFactorAuthorizationsApi) TwoFactorDisableHandler(c *core.WebContext)
 	}
 
 	log.Infof(c, "[twofactor_authorizations.TwoFactorDisableHandler] user \"uid:%d\" has disabled two-factor authorization", uid)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_DISABLED, "")
 
 	return true, nil
 }
@@ -330,6 +334,7 @@ func (a *TwoFactorAuthorizationsApi) TwoFactorRecoveryCodeRegenerateHandler(c *c
 	}
 
 	log.Infof(c, "[twofactor_authorizations.TwoFactorRecoveryCodeRegenerateHandler] user \"uid:%d\" has regenerated two-factor recovery codes", uid)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_RECOVERY_CODES_REGENERATED, "")
 
 	return recoveryCodesResp, nil
 }
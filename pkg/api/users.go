@@ -11,6 +11,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/locales"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -315,7 +316,10 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 		anythingUpdate = true
 	}
 
+	mcpToolListChanged := false
+
 	if userUpdateReq.TransactionEditScope != nil && *userUpdateReq.TransactionEditScope != user.TransactionEditScope {
+		mcpToolListChanged = (user.TransactionEditScope == models.TRANSACTION_EDIT_SCOPE_NONE) != (*userUpdateReq.TransactionEditScope == models.TRANSACTION_EDIT_SCOPE_NONE)
 		user.TransactionEditScope = *userUpdateReq.TransactionEditScope
 		userNew.TransactionEditScope = *userUpdateReq.TransactionEditScope
 		modifyProfileBasicInfo = true
@@ -542,6 +546,10 @@ func (a *UsersApi) UserUpdateProfileHandler(c *core.WebContext) (any, *errs.Erro
 
 	log.Infof(c, "[users.UserUpdateProfileHandler] user \"uid:%d\" has updated successfully", user.Uid)
 
+	if mcpToolListChanged {
+		mcp.Sessions.NotifyToolListChanged(user.Uid)
+	}
+
 	resp := &models.UserProfileUpdateResponse{
 		User: a.GetUserBasicInfo(user),
 	}
@@ -0,0 +1,570 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/auth"
+	"github.com/mayswind/ezbookkeeping/pkg/avatars"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// WebAuthnAuthorizationsApi represents passkey (webauthn) authorization api
+type WebAuthnAuthorizationsApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	ApiWithUserInfo
+	users                   *services.UserService
+	userAppCloudSettings    *services.UserApplicationCloudSettingsService
+	userWebAuthnCredentials *services.UserWebAuthnCredentialService
+	tokens                  *services.TokenService
+	auditEvents             *services.AuditEventService
+}
+
+// Initialize a passkey authorization api singleton instance
+var (
+	WebAuthnAuthorizations = &WebAuthnAuthorizationsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			container: duplicatechecker.Container,
+		},
+		ApiWithUserInfo: ApiWithUserInfo{
+			ApiUsingConfig: ApiUsingConfig{
+				container: settings.Container,
+			},
+			ApiUsingAvatarProvider: ApiUsingAvatarProvider{
+				container: avatars.Container,
+			},
+		},
+		users:                   services.Users,
+		userAppCloudSettings:    services.UserApplicationCloudSettings,
+		userWebAuthnCredentials: services.UserWebAuthnCredentials,
+		tokens:                  services.Tokens,
+		auditEvents:             services.AuditEvents,
+	}
+)
+
+// WebAuthnCredentialListHandler returns all passkeys of current user
+func (a *WebAuthnAuthorizationsApi) WebAuthnCredentialListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	credentials, err := a.userWebAuthnCredentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnCredentialListHandler] failed to get passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	credentialResps := make([]*models.WebAuthnCredentialInfoResponse, len(credentials))
+
+	for i := 0; i < len(credentials); i++ {
+		credentialResps[i] = credentials[i].ToWebAuthnCredentialInfoResponse()
+	}
+
+	return credentialResps, nil
+}
+
+// WebAuthnRegisterBeginHandler returns the credential creation options for current user to register a new passkey
+func (a *WebAuthnAuthorizationsApi) WebAuthnRegisterBeginHandler(c *core.WebContext) (any, *errs.Error) {
+	relyingParty, err := auth.Container.GetWebAuthnRelyingParty()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrWebAuthnNotEnabled)
+	}
+
+	uid := c.GetCurrentUid()
+	user, credentials, err := a.getUserAndCredentials(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnRegisterBeginHandler] failed to get user and passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_ENABLE_2FA) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	if len(credentials) >= models.WebAuthnCredentialMaxCountPerUser {
+		return nil, errs.ErrWebAuthnCredentialCountLimitReached
+	}
+
+	options, session, err := relyingParty.BeginRegistration(c, user, credentials)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	encryptedSession, err := a.createSession(c, session)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnRegisterBeginHandler] failed to create session for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return &models.WebAuthnRegisterBeginResponse{
+		Options: options,
+		Session: encryptedSession,
+	}, nil
+}
+
+// WebAuthnRegisterFinishHandler verifies the attestation response and saves the new passkey for current user
+func (a *WebAuthnAuthorizationsApi) WebAuthnRegisterFinishHandler(c *core.WebContext) (any, *errs.Error) {
+	relyingParty, err := auth.Container.GetWebAuthnRelyingParty()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrWebAuthnNotEnabled)
+	}
+
+	var registerReq models.WebAuthnRegisterFinishRequest
+	err = c.ShouldBindJSON(&registerReq)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnRegisterFinishHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	session, err := a.consumeSession(c, registerReq.Session)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnRegisterFinishHandler] failed to consume session, because %s", err.Error())
+		return nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, credentials, err := a.getUserAndCredentials(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnRegisterFinishHandler] failed to get user and passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_ENABLE_2FA) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	credential, err := relyingParty.FinishRegistration(c, user, credentials, session, registerReq.Credential)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrWebAuthnCredentialInvalid)
+	}
+
+	credential.Name = registerReq.Name
+	err = a.userWebAuthnCredentials.CreateCredential(c, credential)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnRegisterFinishHandler] failed to save passkey for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[webauthn_authorizations.WebAuthnRegisterFinishHandler] user \"uid:%d\" has registered new passkey \"id:%d\", attestation format is \"%s\"", uid, credential.CredentialId, credential.AttestationFormat)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_PASSKEY_REGISTERED, credential.Name)
+
+	return credential.ToWebAuthnCredentialInfoResponse(), nil
+}
+
+// WebAuthnCredentialModifyHandler saves the new name of passkey for current user
+func (a *WebAuthnAuthorizationsApi) WebAuthnCredentialModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var modifyReq models.WebAuthnCredentialModifyRequest
+	err := c.ShouldBindJSON(&modifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnCredentialModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.userWebAuthnCredentials.ModifyCredentialName(c, uid, modifyReq.Id, modifyReq.Name)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnCredentialModifyHandler] failed to update passkey \"id:%d\" for user \"uid:%d\", because %s", modifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[webauthn_authorizations.WebAuthnCredentialModifyHandler] user \"uid:%d\" has updated passkey \"id:%d\"", uid, modifyReq.Id)
+
+	return true, nil
+}
+
+// WebAuthnCredentialDeleteHandler deletes the passkey for current user
+func (a *WebAuthnAuthorizationsApi) WebAuthnCredentialDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var deleteReq models.WebAuthnCredentialDeleteRequest
+	err := c.ShouldBindJSON(&deleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnCredentialDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[webauthn_authorizations.WebAuthnCredentialDeleteHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_DISABLE_2FA) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	if !a.users.IsPasswordEqualsUserPassword(deleteReq.Password, user) {
+		return nil, errs.ErrUserPasswordWrong
+	}
+
+	err = a.userWebAuthnCredentials.DeleteCredential(c, uid, deleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnCredentialDeleteHandler] failed to delete passkey \"id:%d\" for user \"uid:%d\", because %s", deleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[webauthn_authorizations.WebAuthnCredentialDeleteHandler] user \"uid:%d\" has deleted passkey \"id:%d\"", uid, deleteReq.Id)
+	a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_PASSKEY_DELETED, "")
+
+	return true, nil
+}
+
+// WebAuthnTwoFactorBeginHandler returns the credential request options for current 2fa login
+func (a *WebAuthnAuthorizationsApi) WebAuthnTwoFactorBeginHandler(c *core.WebContext) (any, *errs.Error) {
+	relyingParty, err := auth.Container.GetWebAuthnRelyingParty()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrWebAuthnNotEnabled)
+	}
+
+	uid := c.GetCurrentUid()
+	user, credentials, err := a.getUserAndCredentials(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorBeginHandler] failed to get user and passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	options, session, err := relyingParty.BeginLogin(c, user, credentials)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	encryptedSession, err := a.createSession(c, session)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnTwoFactorBeginHandler] failed to create session for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return &models.WebAuthnLoginBeginResponse{
+		Options: options,
+		Session: encryptedSession,
+	}, nil
+}
+
+// WebAuthnTwoFactorAuthorizeHandler verifies and authorizes current 2fa login by passkey
+func (a *WebAuthnAuthorizationsApi) WebAuthnTwoFactorAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
+	relyingParty, err := auth.Container.GetWebAuthnRelyingParty()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrWebAuthnNotEnabled)
+	}
+
+	var loginReq models.WebAuthnLoginRequest
+	err = c.ShouldBindJSON(&loginReq)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.CheckFailureCount(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] cannot auth for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrFailureCountLimitReached)
+	}
+
+	session, err := a.consumeSession(c, loginReq.Session)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] failed to consume session, because %s", err.Error())
+		return nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	user, credentials, err := a.getUserAndCredentials(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] failed to get user and passkeys for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	credential, err := relyingParty.FinishLogin(c, user, credentials, session, loginReq.Credential)
+
+	if err != nil {
+		a.auditEvents.CreateAuditEvent(c, uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFY_FAILED, "passkey")
+
+		failureCheckErr := a.CheckAndIncreaseFailureCount(c, uid)
+
+		if failureCheckErr != nil {
+			log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] cannot auth for user \"uid:%d\", because %s", uid, failureCheckErr.Error())
+			return nil, errs.Or(failureCheckErr, errs.ErrFailureCountLimitReached)
+		}
+
+		return nil, errs.Or(err, errs.ErrWebAuthnAssertionInvalid)
+	}
+
+	if user.Disabled {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] user \"uid:%d\" is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if a.CurrentConfig().EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] user \"uid:%d\" has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	err = a.userWebAuthnCredentials.UpdateCredentialLastUsed(c, credential)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] failed to update sign counter of passkey \"id:%d\" for user \"uid:%d\", because %s", credential.CredentialId, user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	oldTokenClaims := c.GetTokenClaims()
+	err = a.tokens.DeleteTokenByClaims(c, oldTokenClaims)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] failed to revoke temporary token \"utid:%s\" for user \"uid:%d\", because %s", oldTokenClaims.UserTokenId, user.Uid, err.Error())
+	}
+
+	token, claims, err := a.tokens.CreateToken(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	c.SetTextualToken(token)
+	c.SetTokenClaims(claims)
+
+	log.Infof(c, "[webauthn_authorizations.WebAuthnTwoFactorAuthorizeHandler] user \"uid:%d\" has authorized two-factor via passkey \"id:%d\", token will be expired at %d", user.Uid, credential.CredentialId, claims.ExpiresAt)
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFIED, "passkey")
+
+	return a.getAuthResponse(c, token, user), nil
+}
+
+// WebAuthnLoginBeginHandler returns the credential request options for passwordless login
+func (a *WebAuthnAuthorizationsApi) WebAuthnLoginBeginHandler(c *core.WebContext) (any, *errs.Error) {
+	relyingParty, err := auth.Container.GetWebAuthnRelyingParty()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrWebAuthnNotEnabled)
+	}
+
+	if !a.CurrentConfig().EnableWebAuthnPasswordlessLogin {
+		return nil, errs.ErrWebAuthnPasswordlessLoginNotEnabled
+	}
+
+	options, session, err := relyingParty.BeginDiscoverableLogin(c)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	encryptedSession, err := a.createSession(c, session)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnLoginBeginHandler] failed to create session, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return &models.WebAuthnLoginBeginResponse{
+		Options: options,
+		Session: encryptedSession,
+	}, nil
+}
+
+// WebAuthnAuthorizeHandler verifies and authorizes current passwordless login by passkey
+func (a *WebAuthnAuthorizationsApi) WebAuthnAuthorizeHandler(c *core.WebContext) (any, *errs.Error) {
+	relyingParty, err := auth.Container.GetWebAuthnRelyingParty()
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrWebAuthnNotEnabled)
+	}
+
+	if !a.CurrentConfig().EnableWebAuthnPasswordlessLogin {
+		return nil, errs.ErrWebAuthnPasswordlessLoginNotEnabled
+	}
+
+	var loginReq models.WebAuthnLoginRequest
+	err = c.ShouldBindJSON(&loginReq)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] parse request failed, because %s", err.Error())
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	err = a.CheckFailureCount(c, 0)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] cannot login, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrFailureCountLimitReached)
+	}
+
+	session, err := a.consumeSession(c, loginReq.Session)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] failed to consume session, because %s", err.Error())
+		return nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	var loginUid int64
+
+	user, credential, err := relyingParty.FinishDiscoverableLogin(c, session, loginReq.Credential, func(uid int64) (*models.User, []*models.UserWebAuthnCredential, error) {
+		user, credentials, err := a.getUserAndCredentials(c, uid)
+
+		if err == nil {
+			loginUid = user.Uid
+		}
+
+		return user, credentials, err
+	})
+
+	if err != nil {
+		a.auditEvents.CreateAuditEvent(c, loginUid, models.AUDIT_EVENT_TYPE_LOGIN_FAILED, "passkey")
+
+		failureCheckErr := a.CheckAndIncreaseFailureCount(c, 0)
+
+		if failureCheckErr != nil {
+			log.Warnf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] cannot login, because %s", failureCheckErr.Error())
+			return nil, errs.Or(failureCheckErr, errs.ErrFailureCountLimitReached)
+		}
+
+		return nil, errs.Or(err, errs.ErrWebAuthnAssertionInvalid)
+	}
+
+	if user.Disabled {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] user \"uid:%d\" is disabled", user.Uid)
+		return nil, errs.ErrUserIsDisabled
+	}
+
+	if a.CurrentConfig().EnableUserForceVerifyEmail && !user.EmailVerified {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] user \"uid:%d\" has not verified email", user.Uid)
+		return nil, errs.ErrEmailIsNotVerified
+	}
+
+	err = a.userWebAuthnCredentials.UpdateCredentialLastUsed(c, credential)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] failed to update sign counter of passkey \"id:%d\" for user \"uid:%d\", because %s", credential.CredentialId, user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.users.UpdateUserLastLoginTime(c, user.Uid)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] failed to update last login time for user \"uid:%d\", because %s", user.Uid, err.Error())
+	}
+
+	token, claims, err := a.tokens.CreateToken(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] failed to create token for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.ErrTokenGenerating
+	}
+
+	c.SetTextualToken(token)
+	c.SetTokenClaims(claims)
+
+	log.Infof(c, "[webauthn_authorizations.WebAuthnAuthorizeHandler] user \"uid:%d\" has logined via passkey \"id:%d\", token will be expired at %d", user.Uid, credential.CredentialId, claims.ExpiresAt)
+	a.auditEvents.CreateAuditEvent(c, user.Uid, models.AUDIT_EVENT_TYPE_LOGIN, "passkey")
+
+	return a.getAuthResponse(c, token, user), nil
+}
+
+func (a *WebAuthnAuthorizationsApi) getUserAndCredentials(c *core.WebContext, uid int64) (*models.User, []*models.UserWebAuthnCredential, error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	credentials, err := a.userWebAuthnCredentials.GetAllCredentialsByUid(c, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, credentials, nil
+}
+
+func (a *WebAuthnAuthorizationsApi) getAuthResponse(c *core.WebContext, token string, user *models.User) *models.AuthResponse {
+	userApplicationCloudSettings, err := a.userAppCloudSettings.GetUserApplicationCloudSettingsByUid(c, user.Uid)
+	var applicationCloudSettingSlice *models.ApplicationCloudSettingSlice = nil
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_authorizations.getAuthResponse] failed to get latest user application cloud settings for user \"uid:%d\", because %s", user.Uid, err.Error())
+	} else if userApplicationCloudSettings != nil && len(userApplicationCloudSettings.Settings) > 0 {
+		applicationCloudSettingSlice = &userApplicationCloudSettings.Settings
+	}
+
+	return &models.AuthResponse{
+		Token:                    token,
+		Need2FA:                  false,
+		User:                     a.GetUserBasicInfo(user),
+		ApplicationCloudSettings: applicationCloudSettingSlice,
+		NotificationContent:      a.GetAfterLoginNotificationContent(user.Language, c.GetClientLocale()),
+	}
+}
+
+func (a *WebAuthnAuthorizationsApi) createSession(c *core.WebContext, session *auth.WebAuthnSession) (string, error) {
+	err := a.userWebAuthnCredentials.CreateChallenge(c, session.Uid, string(session.Purpose), session.Data.Challenge, session.Data.Expires.Unix())
+
+	if err != nil {
+		return "", err
+	}
+
+	content, err := json.Marshal(session)
+
+	if err != nil {
+		return "", err
+	}
+
+	return utils.EncryptSecret(string(content), a.CurrentConfig().SecretKey)
+}
+
+func (a *WebAuthnAuthorizationsApi) consumeSession(c *core.WebContext, encryptedSession string) (*auth.WebAuthnSession, error) {
+	content, err := utils.DecryptSecret(encryptedSession, a.CurrentConfig().SecretKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	session := &auth.WebAuthnSession{}
+	err = json.Unmarshal([]byte(content), session)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the challenge is deleted before verifying the response, so each session can only be used once even if the verification fails
+	err = a.userWebAuthnCredentials.ConsumeChallenge(c, session.Uid, string(session.Purpose), session.Data.Challenge)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// AuthProviderContainer contains the current external authentication providers and passkey relying party
+type AuthProviderContainer struct {
+	oidcProvider *OIDCProvider
+	ldapProvider *LDAPProvider
+	webAuthn     *WebAuthnRelyingParty
+}
+
+// Initialize an external authentication provider container singleton instance
+var (
+	Container = &AuthProviderContainer{}
+)
+
+// InitializeAuthProviders initializes the current external authentication providers according to the config
+func InitializeAuthProviders(config *settings.Config) error {
+	if config.EnableOIDCAuth {
+		Container.oidcProvider = NewOIDCProvider(config)
+	} else {
+		Container.oidcProvider = nil
+	}
+
+	if config.EnableLDAPAuth {
+		Container.ldapProvider = NewLDAPProvider(config)
+	} else {
+		Container.ldapProvider = nil
+	}
+
+	if config.EnableWebAuthn {
+		webAuthn, err := NewWebAuthnRelyingParty(config)
+
+		if err != nil {
+			return err
+		}
+
+		Container.webAuthn = webAuthn
+	} else {
+		Container.webAuthn = nil
+	}
+
+	return nil
+}
+
+// IsOIDCEnabled returns whether the OpenID Connect identity provider is enabled
+func (p *AuthProviderContainer) IsOIDCEnabled() bool {
+	return p.oidcProvider != nil
+}
+
+// GetOIDCAuthUrl returns the authorization url of the OpenID Connect identity provider
+func (p *AuthProviderContainer) GetOIDCAuthUrl(c core.Context, state string, nonce string, verifier string) (string, error) {
+	if p.oidcProvider == nil {
+		return "", errs.ErrOIDCAuthNotEnabled
+	}
+
+	return p.oidcProvider.GetAuthUrl(c, state, nonce, verifier)
+}
+
+// GetOIDCUserInfo returns the verified user info from the OpenID Connect identity provider
+func (p *AuthProviderContainer) GetOIDCUserInfo(c core.Context, code string, nonce string, verifier string) (*OIDCUserInfo, error) {
+	if p.oidcProvider == nil {
+		return nil, errs.ErrOIDCAuthNotEnabled
+	}
+
+	return p.oidcProvider.GetUserInfo(c, code, nonce, verifier)
+}
+
+// IsLDAPEnabled returns whether the LDAP authentication provider is enabled
+func (p *AuthProviderContainer) IsLDAPEnabled() bool {
+	return p.ldapProvider != nil
+}
+
+// AuthenticateLDAPUser verifies the login name and password by the LDAP authentication provider and returns the user info
+func (p *AuthProviderContainer) AuthenticateLDAPUser(c core.Context, loginName string, password string) (*LDAPUserInfo, error) {
+	if p.ldapProvider == nil {
+		return nil, errs.ErrLDAPUserNotFound
+	}
+
+	return p.ldapProvider.Authenticate(c, loginName, password)
+}
+
+// CanFallbackToLocalPasswordAuth returns whether the local password authentication should be tried after the LDAP authentication failed with the specified error,
+// it returns true if the ldap server is unavailable or the ldap user is not linked to any user, or the ldap user is not found and the local user is not linked to any ldap user
+func (p *AuthProviderContainer) CanFallbackToLocalPasswordAuth(err error, userLinkedToLDAP bool) bool {
+	if err == errs.ErrLDAPUserNotFound {
+		return !userLinkedToLDAP
+	}
+
+	return err == errs.ErrLDAPAuthFailed || err == errs.ErrExternalAuthUserNotLinked
+}
+
+// GetLDAPUserFeatureRestrictions returns the feature restrictions of LDAP user according to the groups of user,
+// the second returned value is false if the feature restrictions should not be changed
+func (p *AuthProviderContainer) GetLDAPUserFeatureRestrictions(userInfo *LDAPUserInfo) (core.UserFeatureRestrictions, bool) {
+	if p.ldapProvider == nil || userInfo == nil {
+		return 0, false
+	}
+
+	return p.ldapProvider.GetFeatureRestrictions(userInfo.Groups)
+}
+
+// IsWebAuthnEnabled returns whether the webauthn relying party is enabled
+func (p *AuthProviderContainer) IsWebAuthnEnabled() bool {
+	return p.webAuthn != nil
+}
+
+// GetWebAuthnRelyingParty returns the webauthn relying party
+func (p *AuthProviderContainer) GetWebAuthnRelyingParty() (*WebAuthnRelyingParty, error) {
+	if p.webAuthn == nil {
+		return nil, errs.ErrWebAuthnNotEnabled
+	}
+
+	return p.webAuthn, nil
+}
@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// LDAPUserInfo represents the user info returned by the LDAP server
+type LDAPUserInfo struct {
+	DN       string
+	Username string
+	Email    string
+	Nickname string
+	Groups   []string
+}
+
+// LDAPProvider represents LDAP / Active Directory authentication provider
+type LDAPProvider struct {
+	serverUrl                 string
+	startTLS                  bool
+	skipTLSVerify             bool
+	bindDN                    string
+	bindPassword              string
+	baseDN                    string
+	userFilter                string
+	usernameAttribute         string
+	emailAttribute            string
+	nicknameAttribute         string
+	groupAttribute            string
+	groupFeatureRestrictions  map[string]core.UserFeatureRestrictions
+	defaultFeatureRestriction core.UserFeatureRestrictions
+	timeout                   time.Duration
+}
+
+// NewLDAPProvider returns a new LDAP authentication provider according to the config
+func NewLDAPProvider(config *settings.Config) *LDAPProvider {
+	return &LDAPProvider{
+		serverUrl:                 config.LDAPServerUrl,
+		startTLS:                  config.LDAPStartTLS,
+		skipTLSVerify:             config.LDAPSkipTLSVerify,
+		bindDN:                    config.LDAPBindDN,
+		bindPassword:              config.LDAPBindPassword,
+		baseDN:                    config.LDAPBaseDN,
+		userFilter:                config.LDAPUserFilter,
+		usernameAttribute:         config.LDAPUsernameAttribute,
+		emailAttribute:            config.LDAPEmailAttribute,
+		nicknameAttribute:         config.LDAPNicknameAttribute,
+		groupAttribute:            config.LDAPGroupAttribute,
+		groupFeatureRestrictions:  config.LDAPGroupFeatureRestrictions,
+		defaultFeatureRestriction: config.DefaultFeatureRestrictions,
+		timeout:                   time.Duration(config.LDAPRequestTimeout) * time.Millisecond,
+	}
+}
+
+// Authenticate searches the user entry by login name and verifies the password by binding as the user entry
+func (p *LDAPProvider) Authenticate(c core.Context, loginName string, password string) (*LDAPUserInfo, error) {
+	if loginName == "" || password == "" {
+		return nil, errs.ErrLoginNameOrPasswordInvalid
+	}
+
+	conn, err := p.connect()
+
+	if err != nil {
+		log.Errorf(c, "[ldap_provider.Authenticate] failed to connect to ldap server, because %s", err.Error())
+		return nil, errs.ErrLDAPAuthFailed
+	}
+
+	defer conn.Close()
+
+	if p.bindDN != "" {
+		err = conn.Bind(p.bindDN, p.bindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+
+	if err != nil {
+		log.Errorf(c, "[ldap_provider.Authenticate] failed to bind search user, because %s", err.Error())
+		return nil, errs.ErrLDAPAuthFailed
+	}
+
+	attributes := []string{p.usernameAttribute, p.emailAttribute, p.nicknameAttribute}
+
+	if p.groupAttribute != "" {
+		attributes = append(attributes, p.groupAttribute)
+	}
+
+	searchRequest := ldap.NewSearchRequest(
+		p.baseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(p.timeout/time.Second),
+		false,
+		p.getUserFilter(loginName),
+		attributes,
+		nil,
+	)
+
+	searchResult, err := conn.Search(searchRequest)
+
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		log.Warnf(c, "[ldap_provider.Authenticate] more than one entries match login name \"%s\"", loginName)
+		return nil, errs.ErrLDAPUserInfoInvalid
+	} else if err != nil {
+		log.Errorf(c, "[ldap_provider.Authenticate] failed to search user \"%s\", because %s", loginName, err.Error())
+		return nil, errs.ErrLDAPAuthFailed
+	}
+
+	if len(searchResult.Entries) < 1 {
+		return nil, errs.ErrLDAPUserNotFound
+	} else if len(searchResult.Entries) > 1 {
+		log.Warnf(c, "[ldap_provider.Authenticate] more than one entries match login name \"%s\"", loginName)
+		return nil, errs.ErrLDAPUserInfoInvalid
+	}
+
+	entry := searchResult.Entries[0]
+	err = conn.Bind(entry.DN, password)
+
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil, errs.ErrUserPasswordWrong
+	} else if err != nil {
+		log.Errorf(c, "[ldap_provider.Authenticate] failed to bind user \"%s\", because %s", entry.DN, err.Error())
+		return nil, errs.ErrLDAPAuthFailed
+	}
+
+	userInfo := &LDAPUserInfo{
+		DN:       entry.DN,
+		Username: strings.TrimSpace(entry.GetAttributeValue(p.usernameAttribute)),
+		Email:    strings.TrimSpace(entry.GetAttributeValue(p.emailAttribute)),
+		Nickname: strings.TrimSpace(entry.GetAttributeValue(p.nicknameAttribute)),
+	}
+
+	if p.groupAttribute != "" {
+		userInfo.Groups = entry.GetAttributeValues(p.groupAttribute)
+	}
+
+	return userInfo, nil
+}
+
+// GetFeatureRestrictions returns the feature restrictions of user according to the groups of user,
+// the second returned value is false if group mapping is not configured
+func (p *LDAPProvider) GetFeatureRestrictions(groups []string) (core.UserFeatureRestrictions, bool) {
+	if len(p.groupFeatureRestrictions) < 1 {
+		return 0, false
+	}
+
+	restrictions := core.UserFeatureRestrictions(0)
+	matched := false
+
+	for i := 0; i < len(groups); i++ {
+		groupDN := strings.ToLower(strings.TrimSpace(groups[i]))
+
+		if groupRestrictions, exists := p.groupFeatureRestrictions[groupDN]; exists {
+			restrictions = restrictions | groupRestrictions
+			matched = true
+			continue
+		}
+
+		if groupName := getLDAPGroupCommonName(groupDN); groupName != "" {
+			if groupRestrictions, exists := p.groupFeatureRestrictions[groupName]; exists {
+				restrictions = restrictions | groupRestrictions
+				matched = true
+			}
+		}
+	}
+
+	if !matched {
+		return p.defaultFeatureRestriction, true
+	}
+
+	return restrictions, true
+}
+
+func (p *LDAPProvider) connect() (*ldap.Conn, error) {
+	serverUrl, err := url.Parse(p.serverUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serverUrl.Hostname(),
+		InsecureSkipVerify: p.skipTLSVerify,
+	}
+
+	conn, err := ldap.DialURL(p.serverUrl, ldap.DialWithDialer(&net.Dialer{Timeout: p.timeout}), ldap.DialWithTLSConfig(tlsConfig))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.timeout > 0 {
+		conn.SetTimeout(p.timeout)
+	}
+
+	if p.startTLS && serverUrl.Scheme == "ldap" {
+		err = conn.StartTLS(tlsConfig)
+
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (p *LDAPProvider) getUserFilter(loginName string) string {
+	return strings.ReplaceAll(p.userFilter, "%s", ldap.EscapeFilter(loginName))
+}
+
+func getLDAPGroupCommonName(groupDN string) string {
+	dn, err := ldap.ParseDN(groupDN)
+
+	if err != nil || len(dn.RDNs) < 1 {
+		return ""
+	}
+
+	for _, attribute := range dn.RDNs[0].Attributes {
+		if strings.EqualFold(attribute.Type, "cn") {
+			return strings.ToLower(attribute.Value)
+		}
+	}
+
+	return ""
+}
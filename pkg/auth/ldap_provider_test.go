@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func newTestLDAPProvider(groupFeatureRestrictions map[string]core.UserFeatureRestrictions) *LDAPProvider {
+	return NewLDAPProvider(&settings.Config{
+		LDAPServerUrl:                "ldap://127.0.0.1:389",
+		LDAPBaseDN:                   "dc=example,dc=com",
+		LDAPUserFilter:               "(&(objectClass=person)(uid=%s))",
+		LDAPGroupFeatureRestrictions: groupFeatureRestrictions,
+		DefaultFeatureRestrictions:   core.ParseUserFeatureRestrictions("1"),
+	})
+}
+
+func TestLDAPProvider_GetUserFilter(t *testing.T) {
+	provider := newTestLDAPProvider(nil)
+
+	assert.Equal(t, "(&(objectClass=person)(uid=testuser))", provider.getUserFilter("testuser"))
+	assert.Equal(t, "(&(objectClass=person)(uid=\\2a\\29\\28uid=\\2a))", provider.getUserFilter("*)(uid=*"))
+}
+
+func TestLDAPProvider_GetFeatureRestrictions_NoGroupMapping(t *testing.T) {
+	provider := newTestLDAPProvider(map[string]core.UserFeatureRestrictions{})
+
+	_, needUpdate := provider.GetFeatureRestrictions([]string{"cn=guests,ou=groups,dc=example,dc=com"})
+	assert.Equal(t, false, needUpdate)
+}
+
+func TestLDAPProvider_GetFeatureRestrictions_MatchGroupDNAndCommonName(t *testing.T) {
+	provider := newTestLDAPProvider(map[string]core.UserFeatureRestrictions{
+		"cn=guests,ou=groups,dc=example,dc=com": core.ParseUserFeatureRestrictions("2,3"),
+		"readonly":                              core.ParseUserFeatureRestrictions("9,11"),
+	})
+
+	restrictions, needUpdate := provider.GetFeatureRestrictions([]string{"CN=Guests,OU=Groups,DC=example,DC=com"})
+	assert.Equal(t, true, needUpdate)
+	assert.Equal(t, core.ParseUserFeatureRestrictions("2,3"), restrictions)
+
+	restrictions, needUpdate = provider.GetFeatureRestrictions([]string{"cn=ReadOnly,ou=groups,dc=example,dc=com"})
+	assert.Equal(t, true, needUpdate)
+	assert.Equal(t, core.ParseUserFeatureRestrictions("9,11"), restrictions)
+
+	restrictions, needUpdate = provider.GetFeatureRestrictions([]string{"cn=guests,ou=groups,dc=example,dc=com", "cn=readonly,ou=groups,dc=example,dc=com"})
+	assert.Equal(t, true, needUpdate)
+	assert.Equal(t, core.ParseUserFeatureRestrictions("2,3,9,11"), restrictions)
+}
+
+func TestLDAPProvider_GetFeatureRestrictions_NoGroupMatched(t *testing.T) {
+	provider := newTestLDAPProvider(map[string]core.UserFeatureRestrictions{
+		"readonly": core.ParseUserFeatureRestrictions("9,11"),
+	})
+
+	restrictions, needUpdate := provider.GetFeatureRestrictions([]string{"cn=users,ou=groups,dc=example,dc=com"})
+	assert.Equal(t, true, needUpdate)
+	assert.Equal(t, core.ParseUserFeatureRestrictions("1"), restrictions)
+
+	restrictions, needUpdate = provider.GetFeatureRestrictions(nil)
+	assert.Equal(t, true, needUpdate)
+	assert.Equal(t, core.ParseUserFeatureRestrictions("1"), restrictions)
+}
+
+func TestLDAPProvider_Authenticate_ServerUnavailable(t *testing.T) {
+	provider := NewLDAPProvider(&settings.Config{
+		LDAPServerUrl:      "ldap://127.0.0.1:1",
+		LDAPBaseDN:         "dc=example,dc=com",
+		LDAPUserFilter:     "(&(objectClass=person)(uid=%s))",
+		LDAPRequestTimeout: 1000,
+	})
+
+	_, err := provider.Authenticate(core.NewNullContext(), "testuser", "password")
+	assert.Equal(t, errs.ErrLDAPAuthFailed, err)
+
+	container := &AuthProviderContainer{ldapProvider: provider}
+	assert.True(t, container.CanFallbackToLocalPasswordAuth(err, true))
+}
+
+func TestAuthProviderContainer_CanFallbackToLocalPasswordAuth(t *testing.T) {
+	container := &AuthProviderContainer{}
+
+	assert.True(t, container.CanFallbackToLocalPasswordAuth(errs.ErrLDAPUserNotFound, false))
+	assert.True(t, container.CanFallbackToLocalPasswordAuth(errs.ErrLDAPAuthFailed, false))
+	assert.True(t, container.CanFallbackToLocalPasswordAuth(errs.ErrLDAPAuthFailed, true))
+	assert.True(t, container.CanFallbackToLocalPasswordAuth(errs.ErrExternalAuthUserNotLinked, false))
+	assert.False(t, container.CanFallbackToLocalPasswordAuth(errs.ErrUserPasswordWrong, false))
+	assert.False(t, container.CanFallbackToLocalPasswordAuth(errs.ErrLDAPUserInfoInvalid, false))
+	assert.False(t, container.CanFallbackToLocalPasswordAuth(errs.ErrLoginNameOrPasswordInvalid, false))
+}
+
+func TestAuthProviderContainer_CanFallbackToLocalPasswordAuth_LinkedUserRemovedFromDirectory(t *testing.T) {
+	container := &AuthProviderContainer{}
+
+	assert.False(t, container.CanFallbackToLocalPasswordAuth(errs.ErrLDAPUserNotFound, true))
+}
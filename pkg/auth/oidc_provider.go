@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const oidcCallbackPath = "oauth2/callback"
+
+// OIDCUserInfo represents the user info returned by the OpenID Connect identity provider
+type OIDCUserInfo struct {
+	Subject       string
+	Username      string
+	Email         string
+	EmailVerified bool
+	Nickname      string
+}
+
+// OIDCProvider represents OpenID Connect identity provider
+type OIDCProvider struct {
+	issuerUrl     string
+	clientId      string
+	clientSecret  string
+	redirectUrl   string
+	scopes        []string
+	usernameClaim string
+	httpClient    *http.Client
+
+	mutex    sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOIDCProvider returns a new OpenID Connect identity provider according to the config
+func NewOIDCProvider(config *settings.Config) *OIDCProvider {
+	httpClient := &http.Client{
+		Timeout: time.Duration(config.OIDCRequestTimeout) * time.Millisecond,
+	}
+
+	return &OIDCProvider{
+		issuerUrl:     config.OIDCIssuerUrl,
+		clientId:      config.OIDCClientId,
+		clientSecret:  config.OIDCClientSecret,
+		redirectUrl:   config.RootUrl + oidcCallbackPath,
+		scopes:        config.OIDCScopes,
+		usernameClaim: config.OIDCUsernameClaim,
+		httpClient:    httpClient,
+	}
+}
+
+// GetAuthUrl returns the authorization url of the identity provider with given state, nonce and pkce verifier
+func (p *OIDCProvider) GetAuthUrl(c core.Context, state string, nonce string, verifier string) (string, error) {
+	oauth2Config, _, err := p.getOAuth2Config(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2Config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// GetUserInfo exchanges the authorization code and returns the verified user info from the id token
+func (p *OIDCProvider) GetUserInfo(c core.Context, code string, nonce string, verifier string) (*OIDCUserInfo, error) {
+	oauth2Config, provider, err := p.getOAuth2Config(c)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := oidc.ClientContext(c, p.httpClient)
+	oauth2Token, err := oauth2Config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+
+	if err != nil {
+		log.Warnf(c, "[oidc_provider.GetUserInfo] failed to exchange token, because %s", err.Error())
+		return nil, errs.ErrOIDCAuthFailed
+	}
+
+	rawIdToken, ok := oauth2Token.Extra("id_token").(string)
+
+	if !ok || rawIdToken == "" {
+		log.Warnf(c, "[oidc_provider.GetUserInfo] id token is missing in token response")
+		return nil, errs.ErrOIDCAuthFailed
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.clientId}).Verify(ctx, rawIdToken)
+
+	if err != nil {
+		log.Warnf(c, "[oidc_provider.GetUserInfo] failed to verify id token, because %s", err.Error())
+		return nil, errs.ErrOIDCAuthFailed
+	}
+
+	if idToken.Nonce != nonce {
+		log.Warnf(c, "[oidc_provider.GetUserInfo] nonce of id token is not matched")
+		return nil, errs.ErrOIDCAuthFailed
+	}
+
+	claims := make(map[string]any)
+
+	if err := idToken.Claims(&claims); err != nil {
+		log.Warnf(c, "[oidc_provider.GetUserInfo] failed to parse claims of id token, because %s", err.Error())
+		return nil, errs.ErrOIDCUserInfoInvalid
+	}
+
+	userInfo := &OIDCUserInfo{
+		Subject:       idToken.Subject,
+		Username:      getStringClaim(claims, p.usernameClaim),
+		Email:         getStringClaim(claims, "email"),
+		EmailVerified: getBoolClaim(claims, "email_verified"),
+		Nickname:      getStringClaim(claims, "name"),
+	}
+
+	if userInfo.Subject == "" {
+		return nil, errs.ErrOIDCUserInfoInvalid
+	}
+
+	return userInfo, nil
+}
+
+func (p *OIDCProvider) getOAuth2Config(c core.Context) (*oauth2.Config, *oidc.Provider, error) {
+	provider, err := p.getProvider(c)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.clientId,
+		ClientSecret: p.clientSecret,
+		RedirectURL:  p.redirectUrl,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.scopes,
+	}, provider, nil
+}
+
+func (p *OIDCProvider) getProvider(c core.Context) (*oidc.Provider, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.provider != nil {
+		return p.provider, nil
+	}
+
+	ctx := oidc.ClientContext(context.Background(), p.httpClient)
+	provider, err := oidc.NewProvider(ctx, p.issuerUrl)
+
+	if err != nil {
+		log.Errorf(c, "[oidc_provider.getProvider] failed to discover identity provider \"%s\", because %s", p.issuerUrl, err.Error())
+		return nil, errs.ErrOIDCAuthFailed
+	}
+
+	p.provider = provider
+
+	return provider, nil
+}
+
+func getStringClaim(claims map[string]any, name string) string {
+	if value, ok := claims[name].(string); ok {
+		return value
+	}
+
+	return ""
+}
+
+func getBoolClaim(claims map[string]any, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	default:
+		return false
+	}
+}
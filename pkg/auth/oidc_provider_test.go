@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const testOIDCClientId = "ezbookkeeping"
+const testOIDCKeyId = "test-key"
+const testOIDCAuthCode = "test-code"
+
+type testOIDCIdentityProvider struct {
+	server        *httptest.Server
+	privateKey    *rsa.PrivateKey
+	nonce         string
+	codeChallenge string
+	claims        jwt.MapClaims
+}
+
+func newTestOIDCIdentityProvider(t *testing.T) *testOIDCIdentityProvider {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	idp := &testOIDCIdentityProvider{
+		privateKey: privateKey,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJson(w, map[string]any{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJson(w, map[string]any{
+			"keys": []map[string]any{
+				{
+					"kty": "RSA",
+					"alg": "RS256",
+					"use": "sig",
+					"kid": testOIDCKeyId,
+					"n":   base64.RawURLEncoding.EncodeToString(privateKey.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		verifierHash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+
+		if r.PostForm.Get("code") != testOIDCAuthCode || base64.RawURLEncoding.EncodeToString(verifierHash[:]) != idp.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			writeTestJson(w, map[string]any{"error": "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   testOIDCClientId,
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": idp.nonce,
+		}
+
+		for key, value := range idp.claims {
+			claims[key] = value
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = testOIDCKeyId
+		idToken, err := token.SignedString(privateKey)
+		assert.Nil(t, err)
+
+		writeTestJson(w, map[string]any{
+			"access_token": "test-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+
+	idp.server = httptest.NewServer(mux)
+
+	return idp
+}
+
+func (p *testOIDCIdentityProvider) authorize(t *testing.T, authUrl string) {
+	parsedUrl, err := url.Parse(authUrl)
+	assert.Nil(t, err)
+
+	query := parsedUrl.Query()
+	assert.Equal(t, p.server.URL+"/authorize", parsedUrl.Scheme+"://"+parsedUrl.Host+parsedUrl.Path)
+	assert.Equal(t, testOIDCClientId, query.Get("client_id"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	p.nonce = query.Get("nonce")
+	p.codeChallenge = query.Get("code_challenge")
+}
+
+func newTestOIDCProvider(issuerUrl string) *OIDCProvider {
+	return NewOIDCProvider(&settings.Config{
+		RootUrl:            "http://localhost:8080/",
+		OIDCIssuerUrl:      issuerUrl,
+		OIDCClientId:       testOIDCClientId,
+		OIDCClientSecret:   "secret",
+		OIDCScopes:         []string{"openid", "profile", "email"},
+		OIDCUsernameClaim:  "preferred_username",
+		OIDCRequestTimeout: 10000,
+	})
+}
+
+func writeTestJson(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func TestOIDCProvider_GetUserInfo(t *testing.T) {
+	idp := newTestOIDCIdentityProvider(t)
+	defer idp.server.Close()
+
+	idp.claims = jwt.MapClaims{
+		"sub":                "user-subject",
+		"preferred_username": "testuser",
+		"email":              "testuser@example.com",
+		"email_verified":     true,
+		"name":               "Test User",
+	}
+
+	provider := newTestOIDCProvider(idp.server.URL)
+	authUrl, err := provider.GetAuthUrl(core.NewNullContext(), "state", "nonce", "verifier-0123456789012345678901234567890123")
+	assert.Nil(t, err)
+	idp.authorize(t, authUrl)
+
+	userInfo, err := provider.GetUserInfo(core.NewNullContext(), testOIDCAuthCode, "nonce", "verifier-0123456789012345678901234567890123")
+	assert.Nil(t, err)
+	assert.Equal(t, "user-subject", userInfo.Subject)
+	assert.Equal(t, "testuser", userInfo.Username)
+	assert.Equal(t, "testuser@example.com", userInfo.Email)
+	assert.Equal(t, true, userInfo.EmailVerified)
+	assert.Equal(t, "Test User", userInfo.Nickname)
+}
+
+func TestOIDCProvider_GetUserInfo_InvalidVerifier(t *testing.T) {
+	idp := newTestOIDCIdentityProvider(t)
+	defer idp.server.Close()
+
+	idp.claims = jwt.MapClaims{
+		"sub": "user-subject",
+	}
+
+	provider := newTestOIDCProvider(idp.server.URL)
+	authUrl, err := provider.GetAuthUrl(core.NewNullContext(), "state", "nonce", "verifier-0123456789012345678901234567890123")
+	assert.Nil(t, err)
+	idp.authorize(t, authUrl)
+
+	_, err = provider.GetUserInfo(core.NewNullContext(), testOIDCAuthCode, "nonce", "another-verifier-012345678901234567890123456")
+	assert.Equal(t, errs.ErrOIDCAuthFailed, err)
+}
+
+func TestOIDCProvider_GetUserInfo_NonceNotMatched(t *testing.T) {
+	idp := newTestOIDCIdentityProvider(t)
+	defer idp.server.Close()
+
+	idp.claims = jwt.MapClaims{
+		"sub": "user-subject",
+	}
+
+	provider := newTestOIDCProvider(idp.server.URL)
+	authUrl, err := provider.GetAuthUrl(core.NewNullContext(), "state", "nonce", "verifier-0123456789012345678901234567890123")
+	assert.Nil(t, err)
+	idp.authorize(t, authUrl)
+
+	_, err = provider.GetUserInfo(core.NewNullContext(), testOIDCAuthCode, "another-nonce", "verifier-0123456789012345678901234567890123")
+	assert.Equal(t, errs.ErrOIDCAuthFailed, err)
+}
+
+func TestOIDCProvider_GetUserInfo_InvalidIssuer(t *testing.T) {
+	provider := newTestOIDCProvider("http://127.0.0.1:1")
+
+	_, err := provider.GetAuthUrl(core.NewNullContext(), "state", "nonce", "verifier-0123456789012345678901234567890123")
+	assert.Equal(t, errs.ErrOIDCAuthFailed, err)
+}
@@ -0,0 +1,370 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+const webAuthnCeremonyTimeout = 5 * time.Minute
+
+// WebAuthnSessionPurpose represents the purpose of webauthn ceremony session
+type WebAuthnSessionPurpose string
+
+// WebAuthn session purposes
+const (
+	WEBAUTHN_SESSION_PURPOSE_REGISTRATION WebAuthnSessionPurpose = "registration"
+	WEBAUTHN_SESSION_PURPOSE_TWO_FACTOR   WebAuthnSessionPurpose = "2fa"
+	WEBAUTHN_SESSION_PURPOSE_LOGIN        WebAuthnSessionPurpose = "login"
+)
+
+// WebAuthnSession represents the webauthn ceremony session which should be kept by relying party between begin and finish requests
+type WebAuthnSession struct {
+	Purpose WebAuthnSessionPurpose `json:"purpose"`
+	Uid     int64                  `json:"uid,omitempty"`
+	Data    webauthn.SessionData   `json:"data"`
+}
+
+// WebAuthnUserLoader returns the user and all passkeys of the user according to the user handle in discoverable login
+type WebAuthnUserLoader func(uid int64) (*models.User, []*models.UserWebAuthnCredential, error)
+
+// WebAuthnRelyingParty represents webauthn relying party which verifies passkeys
+type WebAuthnRelyingParty struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+// webAuthnUser represents the user entity used in webauthn ceremony
+type webAuthnUser struct {
+	user        *models.User
+	credentials []webauthn.Credential
+}
+
+// WebAuthnID returns the user handle of the user
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return getWebAuthnUserHandle(u.user.Uid)
+}
+
+// WebAuthnName returns the name of the user
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+// WebAuthnDisplayName returns the display name of the user
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Nickname
+}
+
+// WebAuthnCredentials returns all passkeys of the user
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// NewWebAuthnRelyingParty returns a new webauthn relying party according to the config
+func NewWebAuthnRelyingParty(config *settings.Config) (*WebAuthnRelyingParty, error) {
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          config.WebAuthnRPId,
+		RPDisplayName: config.WebAuthnRPDisplayName,
+		RPOrigins:     config.WebAuthnRPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login: webauthn.TimeoutConfig{
+				Enforce:    true,
+				Timeout:    webAuthnCeremonyTimeout,
+				TimeoutUVD: webAuthnCeremonyTimeout,
+			},
+			Registration: webauthn.TimeoutConfig{
+				Enforce:    true,
+				Timeout:    webAuthnCeremonyTimeout,
+				TimeoutUVD: webAuthnCeremonyTimeout,
+			},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnRelyingParty{
+		webAuthn: webAuthn,
+	}, nil
+}
+
+// BeginRegistration returns the credential creation options and the session of registration ceremony
+func (p *WebAuthnRelyingParty) BeginRegistration(c core.Context, user *models.User, credentials []*models.UserWebAuthnCredential) (any, *WebAuthnSession, error) {
+	webAuthnUser := getWebAuthnUser(user, credentials)
+
+	options, sessionData, err := p.webAuthn.BeginRegistration(webAuthnUser,
+		webauthn.WithExclusions(webauthn.Credentials(webAuthnUser.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+		webauthn.WithAttestationFormats([]protocol.AttestationFormat{protocol.AttestationFormatPacked, protocol.AttestationFormatNone}),
+	)
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_relying_party.BeginRegistration] failed to begin registration for user \"uid:%d\", because %s", user.Uid, getWebAuthnErrorMessage(err))
+		return nil, nil, errs.ErrOperationFailed
+	}
+
+	return options, &WebAuthnSession{
+		Purpose: WEBAUTHN_SESSION_PURPOSE_REGISTRATION,
+		Uid:     user.Uid,
+		Data:    *sessionData,
+	}, nil
+}
+
+// FinishRegistration verifies the attestation response and returns the new passkey
+func (p *WebAuthnRelyingParty) FinishRegistration(c core.Context, user *models.User, credentials []*models.UserWebAuthnCredential, session *WebAuthnSession, response []byte) (*models.UserWebAuthnCredential, error) {
+	if session.Purpose != WEBAUTHN_SESSION_PURPOSE_REGISTRATION || session.Uid != user.Uid {
+		return nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	parsedResponse, err := protocol.ParseCredentialCreationResponseBytes(response)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_relying_party.FinishRegistration] failed to parse attestation response for user \"uid:%d\", because %s", user.Uid, getWebAuthnErrorMessage(err))
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	attestationFormat := parsedResponse.Response.AttestationObject.Format
+
+	if attestationFormat != models.WEBAUTHN_ATTESTATION_FORMAT_NONE && attestationFormat != models.WEBAUTHN_ATTESTATION_FORMAT_PACKED {
+		log.Warnf(c, "[webauthn_relying_party.FinishRegistration] attestation format \"%s\" is not supported for user \"uid:%d\"", attestationFormat, user.Uid)
+		return nil, errs.ErrWebAuthnAttestationFormatNotSupported
+	}
+
+	credential, err := p.webAuthn.CreateCredential(getWebAuthnUser(user, credentials), session.Data, parsedResponse)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_relying_party.FinishRegistration] failed to verify attestation response for user \"uid:%d\", because %s", user.Uid, getWebAuthnErrorMessage(err))
+		return nil, errs.ErrWebAuthnCredentialInvalid
+	}
+
+	transports := make([]string, len(credential.Transport))
+
+	for i := 0; i < len(credential.Transport); i++ {
+		transports[i] = string(credential.Transport[i])
+	}
+
+	return &models.UserWebAuthnCredential{
+		Uid:               user.Uid,
+		RawCredentialId:   base64.RawURLEncoding.EncodeToString(credential.ID),
+		PublicKey:         credential.PublicKey,
+		AttestationFormat: credential.AttestationType,
+		Transports:        strings.Join(transports, ","),
+		Aaguid:            hex.EncodeToString(credential.Authenticator.AAGUID),
+		Flags:             getWebAuthnCredentialFlags(credential.Flags),
+		SignCount:         credential.Authenticator.SignCount,
+	}, nil
+}
+
+// BeginLogin returns the credential request options and the session of login ceremony which only allows the passkeys of given user
+func (p *WebAuthnRelyingParty) BeginLogin(c core.Context, user *models.User, credentials []*models.UserWebAuthnCredential) (any, *WebAuthnSession, error) {
+	if len(credentials) < 1 {
+		return nil, nil, errs.ErrWebAuthnCredentialNotFound
+	}
+
+	options, sessionData, err := p.webAuthn.BeginLogin(getWebAuthnUser(user, credentials))
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_relying_party.BeginLogin] failed to begin login for user \"uid:%d\", because %s", user.Uid, getWebAuthnErrorMessage(err))
+		return nil, nil, errs.ErrOperationFailed
+	}
+
+	return options, &WebAuthnSession{
+		Purpose: WEBAUTHN_SESSION_PURPOSE_TWO_FACTOR,
+		Uid:     user.Uid,
+		Data:    *sessionData,
+	}, nil
+}
+
+// FinishLogin verifies the assertion response of given user and returns the used passkey with latest sign counter
+func (p *WebAuthnRelyingParty) FinishLogin(c core.Context, user *models.User, credentials []*models.UserWebAuthnCredential, session *WebAuthnSession, response []byte) (*models.UserWebAuthnCredential, error) {
+	if session.Purpose != WEBAUTHN_SESSION_PURPOSE_TWO_FACTOR || session.Uid != user.Uid {
+		return nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBytes(response)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_relying_party.FinishLogin] failed to parse assertion response for user \"uid:%d\", because %s", user.Uid, getWebAuthnErrorMessage(err))
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	credential, err := p.webAuthn.ValidateLogin(getWebAuthnUser(user, credentials), session.Data, parsedResponse)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_relying_party.FinishLogin] failed to verify assertion response for user \"uid:%d\", because %s", user.Uid, getWebAuthnErrorMessage(err))
+		return nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	return getUsedUserWebAuthnCredential(c, user.Uid, credentials, credential)
+}
+
+// BeginDiscoverableLogin returns the credential request options and the session of passwordless login ceremony
+func (p *WebAuthnRelyingParty) BeginDiscoverableLogin(c core.Context) (any, *WebAuthnSession, error) {
+	options, sessionData, err := p.webAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+
+	if err != nil {
+		log.Errorf(c, "[webauthn_relying_party.BeginDiscoverableLogin] failed to begin discoverable login, because %s", getWebAuthnErrorMessage(err))
+		return nil, nil, errs.ErrOperationFailed
+	}
+
+	return options, &WebAuthnSession{
+		Purpose: WEBAUTHN_SESSION_PURPOSE_LOGIN,
+		Data:    *sessionData,
+	}, nil
+}
+
+// FinishDiscoverableLogin verifies the assertion response of passwordless login and returns the user and the used passkey with latest sign counter
+func (p *WebAuthnRelyingParty) FinishDiscoverableLogin(c core.Context, session *WebAuthnSession, response []byte, userLoader WebAuthnUserLoader) (*models.User, *models.UserWebAuthnCredential, error) {
+	if session.Purpose != WEBAUTHN_SESSION_PURPOSE_LOGIN {
+		return nil, nil, errs.ErrWebAuthnSessionInvalid
+	}
+
+	parsedResponse, err := protocol.ParseCredentialRequestResponseBytes(response)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_relying_party.FinishDiscoverableLogin] failed to parse assertion response, because %s", getWebAuthnErrorMessage(err))
+		return nil, nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	var user *models.User
+	var credentials []*models.UserWebAuthnCredential
+
+	credential, err := p.webAuthn.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		uid, err := strconv.ParseInt(string(userHandle), 10, 64)
+
+		if err != nil || uid <= 0 {
+			return nil, errs.ErrUserIdInvalid
+		}
+
+		user, credentials, err = userLoader(uid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return getWebAuthnUser(user, credentials), nil
+	}, session.Data, parsedResponse)
+
+	if err != nil {
+		log.Warnf(c, "[webauthn_relying_party.FinishDiscoverableLogin] failed to verify assertion response, because %s", getWebAuthnErrorMessage(err))
+		return nil, nil, errs.ErrWebAuthnAssertionInvalid
+	}
+
+	usedCredential, err := getUsedUserWebAuthnCredential(c, user.Uid, credentials, credential)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, usedCredential, nil
+}
+
+func getUsedUserWebAuthnCredential(c core.Context, uid int64, credentials []*models.UserWebAuthnCredential, credential *webauthn.Credential) (*models.UserWebAuthnCredential, error) {
+	rawCredentialId := base64.RawURLEncoding.EncodeToString(credential.ID)
+
+	for i := 0; i < len(credentials); i++ {
+		if credentials[i].RawCredentialId != rawCredentialId {
+			continue
+		}
+
+		if credential.Authenticator.CloneWarning {
+			log.Warnf(c, "[webauthn_relying_party.getUsedUserWebAuthnCredential] sign counter of passkey \"id:%d\" for user \"uid:%d\" is not increased, the authenticator may be cloned", credentials[i].CredentialId, uid)
+			return nil, errs.ErrWebAuthnSignCountInvalid
+		}
+
+		credentials[i].SignCount = credential.Authenticator.SignCount
+		credentials[i].Flags = getWebAuthnCredentialFlags(credential.Flags)
+
+		return credentials[i], nil
+	}
+
+	return nil, errs.ErrWebAuthnCredentialNotFound
+}
+
+func getWebAuthnUser(user *models.User, credentials []*models.UserWebAuthnCredential) *webAuthnUser {
+	webAuthnCredentials := make([]webauthn.Credential, 0, len(credentials))
+
+	for i := 0; i < len(credentials); i++ {
+		credentialId, err := base64.RawURLEncoding.DecodeString(credentials[i].RawCredentialId)
+
+		if err != nil {
+			continue
+		}
+
+		aaguid, _ := hex.DecodeString(credentials[i].Aaguid)
+		var transports []protocol.AuthenticatorTransport
+
+		if credentials[i].Transports != "" {
+			items := strings.Split(credentials[i].Transports, ",")
+
+			for j := 0; j < len(items); j++ {
+				transports = append(transports, protocol.AuthenticatorTransport(items[j]))
+			}
+		}
+
+		webAuthnCredentials = append(webAuthnCredentials, webauthn.Credential{
+			ID:              credentialId,
+			PublicKey:       credentials[i].PublicKey,
+			AttestationType: credentials[i].AttestationFormat,
+			Transport:       transports,
+			Flags:           webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(credentials[i].Flags)),
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    aaguid,
+				SignCount: credentials[i].SignCount,
+			},
+		})
+	}
+
+	return &webAuthnUser{
+		user:        user,
+		credentials: webAuthnCredentials,
+	}
+}
+
+func getWebAuthnUserHandle(uid int64) []byte {
+	return []byte(strconv.FormatInt(uid, 10))
+}
+
+func getWebAuthnCredentialFlags(flags webauthn.CredentialFlags) uint8 {
+	result := protocol.AuthenticatorFlags(0)
+
+	if flags.UserPresent {
+		result |= protocol.FlagUserPresent
+	}
+
+	if flags.UserVerified {
+		result |= protocol.FlagUserVerified
+	}
+
+	if flags.BackupEligible {
+		result |= protocol.FlagBackupEligible
+	}
+
+	if flags.BackupState {
+		result |= protocol.FlagBackupState
+	}
+
+	return uint8(result)
+}
+
+func getWebAuthnErrorMessage(err error) string {
+	var protocolErr *protocol.Error
+
+	if errors.As(err, &protocolErr) && protocolErr.DevInfo != "" {
+		return protocolErr.Details + " (" + protocolErr.DevInfo + ")"
+	}
+
+	return err.Error()
+}
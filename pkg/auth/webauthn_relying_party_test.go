@@ -0,0 +1,355 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestGetWebAuthnCredentialFlags(t *testing.T) {
+	assert.Equal(t, uint8(0x00), getWebAuthnCredentialFlags(webauthn.CredentialFlags{}))
+	assert.Equal(t, uint8(0x05), getWebAuthnCredentialFlags(webauthn.CredentialFlags{UserPresent: true, UserVerified: true}))
+	assert.Equal(t, uint8(0x1d), getWebAuthnCredentialFlags(webauthn.CredentialFlags{UserPresent: true, UserVerified: true, BackupEligible: true, BackupState: true}))
+}
+
+func TestGetWebAuthnCredentialFlags_BackupEligible(t *testing.T) {
+	credential := &models.UserWebAuthnCredential{
+		Flags: getWebAuthnCredentialFlags(webauthn.CredentialFlags{UserPresent: true, BackupEligible: true}),
+	}
+	assert.Equal(t, true, credential.ToWebAuthnCredentialInfoResponse().BackupEligible)
+
+	credential.Flags = getWebAuthnCredentialFlags(webauthn.CredentialFlags{UserPresent: true})
+	assert.Equal(t, false, credential.ToWebAuthnCredentialInfoResponse().BackupEligible)
+}
+
+func TestGetWebAuthnUserHandle(t *testing.T) {
+	assert.Equal(t, []byte("1234567890"), getWebAuthnUserHandle(1234567890))
+}
+
+const testWebAuthnRPId = "example.com"
+const testWebAuthnOrigin = "https://example.com"
+
+type testWebAuthnAuthenticator struct {
+	privateKey   *ecdsa.PrivateKey
+	credentialId []byte
+	signCount    uint32
+	flags        byte
+}
+
+func newTestWebAuthnAuthenticator(t *testing.T, credentialId string, flags byte) *testWebAuthnAuthenticator {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	return &testWebAuthnAuthenticator{
+		privateKey:   privateKey,
+		credentialId: []byte(credentialId),
+		flags:        flags,
+	}
+}
+
+func (a *testWebAuthnAuthenticator) getCOSEPublicKey(t *testing.T) []byte {
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: a.privateKey.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.privateKey.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	assert.Nil(t, err)
+
+	return publicKey
+}
+
+func (a *testWebAuthnAuthenticator) getAuthenticatorData(t *testing.T, flags byte, attestedCredentialData bool) []byte {
+	rpIdHash := sha256.Sum256([]byte(testWebAuthnRPId))
+	authData := append([]byte{}, rpIdHash[:]...)
+
+	if attestedCredentialData {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+
+	authData = append(authData, flags)
+	authData = binary.BigEndian.AppendUint32(authData, a.signCount)
+
+	if attestedCredentialData {
+		authData = append(authData, make([]byte, 16)...)
+		authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialId)))
+		authData = append(authData, a.credentialId...)
+		authData = append(authData, a.getCOSEPublicKey(t)...)
+	}
+
+	return authData
+}
+
+func (a *testWebAuthnAuthenticator) sign(t *testing.T, authData []byte, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.privateKey, digest[:])
+	assert.Nil(t, err)
+
+	return signature
+}
+
+func (a *testWebAuthnAuthenticator) createAttestationResponse(t *testing.T, challenge string, attestationFormat string) []byte {
+	clientDataJSON := getTestWebAuthnClientDataJSON(t, "webauthn.create", challenge)
+	authData := a.getAuthenticatorData(t, a.flags, true)
+	attestationStatement := map[string]any{}
+
+	if attestationFormat == models.WEBAUTHN_ATTESTATION_FORMAT_PACKED {
+		// self attestation signed by the private key of the credential
+		attestationStatement["alg"] = int64(webauthncose.AlgES256)
+		attestationStatement["sig"] = a.sign(t, authData, clientDataJSON)
+	}
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      attestationFormat,
+		"attStmt":  attestationStatement,
+		"authData": authData,
+	})
+	assert.Nil(t, err)
+
+	response, err := json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+		},
+	})
+	assert.Nil(t, err)
+
+	return response
+}
+
+func (a *testWebAuthnAuthenticator) createAssertionResponse(t *testing.T, challenge string, userHandle []byte) []byte {
+	clientDataJSON := getTestWebAuthnClientDataJSON(t, "webauthn.get", challenge)
+	authData := a.getAuthenticatorData(t, a.flags, false)
+
+	response, err := json.Marshal(map[string]any{
+		"id":    base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"rawId": base64.RawURLEncoding.EncodeToString(a.credentialId),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientDataJSON),
+			"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+			"signature":         base64.RawURLEncoding.EncodeToString(a.sign(t, authData, clientDataJSON)),
+			"userHandle":        base64.RawURLEncoding.EncodeToString(userHandle),
+		},
+	})
+	assert.Nil(t, err)
+
+	return response
+}
+
+func getTestWebAuthnClientDataJSON(t *testing.T, ceremonyType string, challenge string) []byte {
+	clientDataJSON, err := json.Marshal(map[string]string{
+		"type":      ceremonyType,
+		"challenge": challenge,
+		"origin":    testWebAuthnOrigin,
+	})
+	assert.Nil(t, err)
+
+	return clientDataJSON
+}
+
+func newTestWebAuthnRelyingParty(t *testing.T) *WebAuthnRelyingParty {
+	relyingParty, err := NewWebAuthnRelyingParty(&settings.Config{
+		WebAuthnRPId:          testWebAuthnRPId,
+		WebAuthnRPDisplayName: "ezBookkeeping",
+		WebAuthnRPOrigins:     []string{testWebAuthnOrigin},
+	})
+	assert.Nil(t, err)
+
+	return relyingParty
+}
+
+func registerTestWebAuthnCredential(t *testing.T, relyingParty *WebAuthnRelyingParty, user *models.User, authenticator *testWebAuthnAuthenticator, attestationFormat string) *models.UserWebAuthnCredential {
+	context := core.NewNullContext()
+	_, session, err := relyingParty.BeginRegistration(context, user, nil)
+	assert.Nil(t, err)
+
+	credential, err := relyingParty.FinishRegistration(context, user, nil, session, authenticator.createAttestationResponse(t, session.Data.Challenge, attestationFormat))
+	assert.Nil(t, err)
+
+	return credential
+}
+
+func TestWebAuthnRelyingPartyRegistration_NoneAttestation(t *testing.T) {
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-none", byte(protocol.FlagUserPresent|protocol.FlagUserVerified|protocol.FlagBackupEligible))
+	authenticator.signCount = 1
+
+	credential := registerTestWebAuthnCredential(t, relyingParty, user, authenticator, models.WEBAUTHN_ATTESTATION_FORMAT_NONE)
+
+	assert.Equal(t, user.Uid, credential.Uid)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString([]byte("credential-none")), credential.RawCredentialId)
+	assert.Equal(t, models.WEBAUTHN_ATTESTATION_FORMAT_NONE, credential.AttestationFormat)
+	assert.Equal(t, uint32(1), credential.SignCount)
+	assert.Equal(t, true, credential.ToWebAuthnCredentialInfoResponse().BackupEligible)
+}
+
+func TestWebAuthnRelyingPartyRegistration_PackedSelfAttestation(t *testing.T) {
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-packed", byte(protocol.FlagUserPresent|protocol.FlagUserVerified))
+
+	credential := registerTestWebAuthnCredential(t, relyingParty, user, authenticator, models.WEBAUTHN_ATTESTATION_FORMAT_PACKED)
+
+	assert.Equal(t, models.WEBAUTHN_ATTESTATION_FORMAT_PACKED, credential.AttestationFormat)
+	assert.Equal(t, false, credential.ToWebAuthnCredentialInfoResponse().BackupEligible)
+}
+
+func TestWebAuthnRelyingPartyRegistration_UnsupportedAttestationFormat(t *testing.T) {
+	context := core.NewNullContext()
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-u2f", byte(protocol.FlagUserPresent))
+
+	_, session, err := relyingParty.BeginRegistration(context, user, nil)
+	assert.Nil(t, err)
+
+	_, err = relyingParty.FinishRegistration(context, user, nil, session, authenticator.createAttestationResponse(t, session.Data.Challenge, "fido-u2f"))
+	assert.Equal(t, errs.ErrWebAuthnAttestationFormatNotSupported, err)
+}
+
+func TestWebAuthnRelyingPartyRegistration_InvalidSession(t *testing.T) {
+	context := core.NewNullContext()
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-none", byte(protocol.FlagUserPresent))
+
+	_, session, err := relyingParty.BeginRegistration(context, user, nil)
+	assert.Nil(t, err)
+
+	_, err = relyingParty.FinishRegistration(context, &models.User{Uid: 1234567891}, nil, session, authenticator.createAttestationResponse(t, session.Data.Challenge, models.WEBAUTHN_ATTESTATION_FORMAT_NONE))
+	assert.Equal(t, errs.ErrWebAuthnSessionInvalid, err)
+
+	_, err = relyingParty.FinishRegistration(context, user, nil, session, authenticator.createAttestationResponse(t, "invalid-challenge", models.WEBAUTHN_ATTESTATION_FORMAT_NONE))
+	assert.Equal(t, errs.ErrWebAuthnCredentialInvalid, err)
+}
+
+func TestWebAuthnRelyingPartyLogin(t *testing.T) {
+	context := core.NewNullContext()
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-login", byte(protocol.FlagUserPresent|protocol.FlagUserVerified))
+	authenticator.signCount = 1
+
+	credential := registerTestWebAuthnCredential(t, relyingParty, user, authenticator, models.WEBAUTHN_ATTESTATION_FORMAT_NONE)
+	credentials := []*models.UserWebAuthnCredential{credential}
+
+	_, session, err := relyingParty.BeginLogin(context, user, credentials)
+	assert.Nil(t, err)
+	assert.Equal(t, WEBAUTHN_SESSION_PURPOSE_TWO_FACTOR, session.Purpose)
+
+	authenticator.signCount = 2
+	usedCredential, err := relyingParty.FinishLogin(context, user, credentials, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(user.Uid)))
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), usedCredential.SignCount)
+
+	_, err = relyingParty.FinishLogin(context, user, credentials, session, authenticator.createAssertionResponse(t, "invalid-challenge", getWebAuthnUserHandle(user.Uid)))
+	assert.Equal(t, errs.ErrWebAuthnAssertionInvalid, err)
+
+	_, err = relyingParty.FinishLogin(context, &models.User{Uid: 1234567891}, credentials, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(user.Uid)))
+	assert.Equal(t, errs.ErrWebAuthnSessionInvalid, err)
+
+	_, _, err = relyingParty.BeginLogin(context, user, nil)
+	assert.Equal(t, errs.ErrWebAuthnCredentialNotFound, err)
+}
+
+func TestWebAuthnRelyingPartyLogin_SignCountRegression(t *testing.T) {
+	context := core.NewNullContext()
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-clone", byte(protocol.FlagUserPresent|protocol.FlagUserVerified))
+	authenticator.signCount = 5
+
+	credential := registerTestWebAuthnCredential(t, relyingParty, user, authenticator, models.WEBAUTHN_ATTESTATION_FORMAT_NONE)
+	credentials := []*models.UserWebAuthnCredential{credential}
+
+	_, session, err := relyingParty.BeginLogin(context, user, credentials)
+	assert.Nil(t, err)
+
+	// the sign counter of cloned authenticator is not greater than the stored one
+	authenticator.signCount = 5
+	_, err = relyingParty.FinishLogin(context, user, credentials, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(user.Uid)))
+	assert.Equal(t, errs.ErrWebAuthnSignCountInvalid, err)
+
+	authenticator.signCount = 3
+	_, err = relyingParty.FinishLogin(context, user, credentials, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(user.Uid)))
+	assert.Equal(t, errs.ErrWebAuthnSignCountInvalid, err)
+}
+
+func TestWebAuthnRelyingPartyLogin_ZeroSignCount(t *testing.T) {
+	context := core.NewNullContext()
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-synced", byte(protocol.FlagUserPresent|protocol.FlagUserVerified|protocol.FlagBackupEligible|protocol.FlagBackupState))
+
+	credential := registerTestWebAuthnCredential(t, relyingParty, user, authenticator, models.WEBAUTHN_ATTESTATION_FORMAT_NONE)
+	credentials := []*models.UserWebAuthnCredential{credential}
+
+	_, session, err := relyingParty.BeginLogin(context, user, credentials)
+	assert.Nil(t, err)
+
+	// synced passkeys do not support sign counter and always return zero
+	usedCredential, err := relyingParty.FinishLogin(context, user, credentials, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(user.Uid)))
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), usedCredential.SignCount)
+}
+
+func TestWebAuthnRelyingPartyDiscoverableLogin(t *testing.T) {
+	context := core.NewNullContext()
+	relyingParty := newTestWebAuthnRelyingParty(t)
+	user := &models.User{Uid: 1234567890, Username: "test", Nickname: "Test"}
+	authenticator := newTestWebAuthnAuthenticator(t, "credential-discoverable", byte(protocol.FlagUserPresent|protocol.FlagUserVerified))
+	authenticator.signCount = 1
+
+	credential := registerTestWebAuthnCredential(t, relyingParty, user, authenticator, models.WEBAUTHN_ATTESTATION_FORMAT_PACKED)
+	userLoader := func(uid int64) (*models.User, []*models.UserWebAuthnCredential, error) {
+		if uid != user.Uid {
+			return nil, nil, errs.ErrUserNotFound
+		}
+
+		return user, []*models.UserWebAuthnCredential{credential}, nil
+	}
+
+	_, session, err := relyingParty.BeginDiscoverableLogin(context)
+	assert.Nil(t, err)
+	assert.Equal(t, WEBAUTHN_SESSION_PURPOSE_LOGIN, session.Purpose)
+
+	authenticator.signCount = 2
+	loginUser, usedCredential, err := relyingParty.FinishDiscoverableLogin(context, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(user.Uid)), userLoader)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Uid, loginUser.Uid)
+	assert.Equal(t, credential.RawCredentialId, usedCredential.RawCredentialId)
+
+	_, _, err = relyingParty.FinishDiscoverableLogin(context, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(1234567891)), userLoader)
+	assert.Equal(t, errs.ErrWebAuthnAssertionInvalid, err)
+
+	// passwordless login requires user verification
+	authenticator.flags = byte(protocol.FlagUserPresent)
+	authenticator.signCount = 3
+	_, _, err = relyingParty.FinishDiscoverableLogin(context, session, authenticator.createAssertionResponse(t, session.Data.Challenge, getWebAuthnUserHandle(user.Uid)), userLoader)
+	assert.Equal(t, errs.ErrWebAuthnAssertionInvalid, err)
+}
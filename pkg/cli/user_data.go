@@ -27,8 +27,10 @@ type UserDataCli struct {
 	tags                    *services.TransactionTagService
 	users                   *services.UserService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
+	userWebAuthnCredentials *services.UserWebAuthnCredentialService
 	tokens                  *services.TokenService
 	forgetPasswords         *services.ForgetPasswordService
+	auditEvents             *services.AuditEventService
 }
 
 // Initialize a user data cli singleton instance
@@ -43,8 +45,10 @@ var (
 		tags:                    services.TransactionTags,
 		users:                   services.Users,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
+		userWebAuthnCredentials: services.UserWebAuthnCredentials,
 		tokens:                  services.Tokens,
 		forgetPasswords:         services.ForgetPasswords,
+		auditEvents:             services.AuditEvents,
 	}
 )
 
@@ -380,6 +384,30 @@ func (l *UserDataCli) DeleteUser(c *core.CliContext, username string) error {
 	return nil
 }
 
+// ListUserAuditEvents returns the security audit events of the specified user in the given page
+func (l *UserDataCli) ListUserAuditEvents(c *core.CliContext, username string, page int32, count int32) ([]*models.AuditEvent, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.ListUserAuditEvents] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ListUserAuditEvents] error occurs when getting user id by user name")
+		return nil, err
+	}
+
+	events, err := l.auditEvents.GetAuditEventsByUid(c, uid, page, count)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ListUserAuditEvents] failed to get audit events of user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return events, nil
+}
+
 // ListUserTokens returns all tokens of the specified user
 func (l *UserDataCli) ListUserTokens(c *core.CliContext, username string) ([]*models.TokenRecord, error) {
 	if username == "" {
@@ -394,7 +422,7 @@ func (l *UserDataCli) ListUserTokens(c *core.CliContext, username string) ([]*mo
 		return nil, err
 	}
 
-	tokens, err := l.tokens.GetAllUnexpiredNormalAndMCPTokensByUid(c, uid)
+	tokens, err := l.tokens.GetAllUnexpiredAccessTokensByUid(c, uid)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ListUserTokens] failed to get tokens of user \"%s\", because %s", username, err.Error())
@@ -405,7 +433,7 @@ func (l *UserDataCli) ListUserTokens(c *core.CliContext, username string) ([]*mo
 }
 
 // CreateNewUserToken returns a new token for the specified user
-func (l *UserDataCli) CreateNewUserToken(c *core.CliContext, username string, tokenType string) (*models.TokenRecord, string, error) {
+func (l *UserDataCli) CreateNewUserToken(c *core.CliContext, username string, tokenType string, readOnly bool, name string, permissionNames string, expiredInDays int) (*models.TokenRecord, string, error) {
 	if username == "" {
 		log.CliErrorf(c, "[user_data.CreateNewUserToken] user name is empty")
 		return nil, "", errs.ErrUsernameIsEmpty
@@ -430,7 +458,32 @@ func (l *UserDataCli) CreateNewUserToken(c *core.CliContext, username string, to
 			return nil, "", errs.ErrNotPermittedToPerformThisAction
 		}
 
-		token, tokenRecord, err = l.tokens.CreateMCPTokenViaCli(c, user)
+		tokenScope := core.USER_TOKEN_SCOPE_FULL_ACCESS
+
+		if readOnly {
+			tokenScope = core.USER_TOKEN_SCOPE_READ_ONLY
+		}
+
+		token, tokenRecord, err = l.tokens.CreateMCPTokenViaCli(c, user, tokenScope)
+	} else if tokenType == "personal_access" {
+		if name == "" {
+			log.CliErrorf(c, "[user_data.CreateNewUserToken] token name is empty")
+			return nil, "", errs.ErrParameterInvalid
+		}
+
+		permissions, parseErr := core.ParseTokenPermissions(permissionNames)
+
+		if parseErr != nil {
+			log.CliErrorf(c, "[user_data.CreateNewUserToken] failed to parse token permissions, because %s", parseErr.Error())
+			return nil, "", errs.ErrTokenPermissionInvalid
+		}
+
+		if expiredInDays < 0 {
+			log.CliErrorf(c, "[user_data.CreateNewUserToken] token expiration days is invalid")
+			return nil, "", errs.ErrParameterInvalid
+		}
+
+		token, tokenRecord, err = l.tokens.CreatePersonalAccessTokenViaCli(c, user, name, permissions, expiredInDays)
 	} else if tokenType == "normal" {
 		token, tokenRecord, err = l.tokens.CreateTokenViaCli(c, user)
 	} else {
@@ -545,6 +598,30 @@ func (l *UserDataCli) DisableUserTwoFactorAuthorization(c *core.CliContext, user
 	return nil
 }
 
+// ClearUserWebAuthnCredentials deletes all passkeys of the specified user
+func (l *UserDataCli) ClearUserWebAuthnCredentials(c *core.CliContext, username string) error {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.ClearUserWebAuthnCredentials] user name is empty")
+		return errs.ErrUsernameIsEmpty
+	}
+
+	uid, err := l.getUserIdByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ClearUserWebAuthnCredentials] error occurs when getting user id by user name")
+		return err
+	}
+
+	err = l.userWebAuthnCredentials.DeleteAllCredentials(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ClearUserWebAuthnCredentials] failed to delete passkeys for user \"%s\"", username)
+		return err
+	}
+
+	return nil
+}
+
 // CheckTransactionAndAccount checks whether all user transactions and all user accounts are correct
 func (l *UserDataCli) CheckTransactionAndAccount(c *core.CliContext, username string) (bool, error) {
 	if username == "" {
@@ -768,6 +845,13 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 		return nil, err
 	}
 
+	err = l.transactions.FillTransactionSplits(c, uid, allTransactions)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get transaction split lines for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
 	dataExporter := converters.GetTransactionDataExporter(fileType)
 
 	if dataExporter == nil {
@@ -860,7 +944,7 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 		return errs.ErrOperationFailed
 	}
 
-	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, nil)
+	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, nil, nil)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ImportTransaction] failed to create transaction, because %s", err.Error())
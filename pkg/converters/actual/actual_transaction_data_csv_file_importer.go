@@ -0,0 +1,278 @@
+package actual
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/csv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const actualTransactionAccountColumnName = "Account"
+const actualTransactionDateColumnName = "Date"
+const actualTransactionPayeeColumnName = "Payee"
+const actualTransactionNotesColumnName = "Notes"
+const actualTransactionCategoryColumnName = "Category"
+const actualTransactionAmountColumnName = "Amount"
+const actualTransactionSplitAmountColumnName = "Split_Amount"
+
+const actualTransactionTransferPayeePrefix = "Transfer : "
+const actualTransactionStartingBalancePayee = "Starting Balance"
+const actualTransactionTagPrefix = "#"
+const actualTransactionTagSeparator = " "
+
+var actualTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_MODIFY_BALANCE: "Starting Balance",
+	models.TRANSACTION_TYPE_INCOME:         "Income",
+	models.TRANSACTION_TYPE_EXPENSE:        "Expense",
+	models.TRANSACTION_TYPE_TRANSFER:       "Transfer",
+}
+
+// actualTransactionDataCsvFileImporter defines the structure of actual budget csv importer for transaction data
+type actualTransactionDataCsvFileImporter struct{}
+
+// actualTransactionData defines the structure of a parsed row of actual budget csv data
+type actualTransactionData struct {
+	rowId       string
+	date        string
+	accountName string
+	payee       string
+	category    string
+	notes       string
+	amount      int64
+	splitAmount int64
+}
+
+// Initialize an actual budget transaction data csv file importer singleton instance
+var (
+	ActualTransactionDataCsvFileImporter = &actualTransactionDataCsvFileImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing the actual budget transaction csv data
+func (c *actualTransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	fallback := unicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+
+	csvDataTable, err := csv.CreateNewCsvBasicDataTable(ctx, reader, true)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	commonDataTable := datatable.CreateNewCommonDataTableFromBasicDataTable(csvDataTable)
+
+	if !commonDataTable.HasColumn(actualTransactionAccountColumnName) ||
+		!commonDataTable.HasColumn(actualTransactionDateColumnName) ||
+		!commonDataTable.HasColumn(actualTransactionPayeeColumnName) ||
+		!commonDataTable.HasColumn(actualTransactionCategoryColumnName) ||
+		!commonDataTable.HasColumn(actualTransactionAmountColumnName) {
+		log.Errorf(ctx, "[actual_transaction_data_csv_file_importer.ParseImportedData] cannot parse import data, because missing essential columns in header row")
+		return nil, nil, nil, nil, nil, nil, errs.ErrMissingRequiredFieldInHeaderRow
+	}
+
+	allTransactions, err := c.parseAllTransactions(ctx, commonDataTable)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	transactionDataTable := c.createNewActualTransactionDataTable(allTransactions)
+	dataTableImporter := converter.CreateNewImporterWithTypeNameMapping(actualTransactionTypeNameMapping, "", "", actualTransactionTagSeparator)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
+
+func (c *actualTransactionDataCsvFileImporter) parseAllTransactions(ctx core.Context, commonDataTable datatable.CommonDataTable) ([]*actualTransactionData, error) {
+	allTransactions := make([]*actualTransactionData, 0, commonDataTable.DataRowCount())
+	lastParentTransactionIndex := -1
+	commonDataTableIterator := commonDataTable.DataRowIterator()
+
+	for commonDataTableIterator.HasNext() {
+		dataRow := commonDataTableIterator.Next()
+		rowId := commonDataTableIterator.CurrentRowId()
+
+		if dataRow.ColumnCount() < commonDataTable.HeaderColumnCount() {
+			log.Errorf(ctx, "[actual_transaction_data_csv_file_importer.parseAllTransactions] cannot parse row \"%s\", because may missing some columns (column count %d in data row is less than header column count %d)", rowId, dataRow.ColumnCount(), commonDataTable.HeaderColumnCount())
+			return nil, errs.ErrFewerFieldsInDataRowThanInHeaderRow
+		}
+
+		if !utils.IsValidLongDateFormat(dataRow.GetData(actualTransactionDateColumnName)) {
+			log.Errorf(ctx, "[actual_transaction_data_csv_file_importer.parseAllTransactions] cannot parse date \"%s\" in row \"%s\"", dataRow.GetData(actualTransactionDateColumnName), rowId)
+			return nil, errs.ErrTransactionTimeInvalid
+		}
+
+		amount, err := utils.ParseAmount(utils.TrimTrailingZerosInDecimal(dataRow.GetData(actualTransactionAmountColumnName)))
+
+		if err != nil {
+			log.Errorf(ctx, "[actual_transaction_data_csv_file_importer.parseAllTransactions] cannot parse amount \"%s\" in row \"%s\", because %s", dataRow.GetData(actualTransactionAmountColumnName), rowId, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		splitAmount := int64(0)
+
+		if dataRow.HasData(actualTransactionSplitAmountColumnName) {
+			splitAmount, err = utils.ParseAmount(utils.TrimTrailingZerosInDecimal(dataRow.GetData(actualTransactionSplitAmountColumnName)))
+
+			if err != nil {
+				log.Errorf(ctx, "[actual_transaction_data_csv_file_importer.parseAllTransactions] cannot parse split amount \"%s\" in row \"%s\", because %s", dataRow.GetData(actualTransactionSplitAmountColumnName), rowId, err.Error())
+				return nil, errs.ErrAmountInvalid
+			}
+		}
+
+		transaction := &actualTransactionData{
+			rowId:       rowId,
+			date:        dataRow.GetData(actualTransactionDateColumnName),
+			accountName: dataRow.GetData(actualTransactionAccountColumnName),
+			payee:       dataRow.GetData(actualTransactionPayeeColumnName),
+			category:    dataRow.GetData(actualTransactionCategoryColumnName),
+			notes:       dataRow.GetData(actualTransactionNotesColumnName),
+			amount:      amount,
+			splitAmount: splitAmount,
+		}
+
+		if splitAmount == 0 {
+			allTransactions = append(allTransactions, transaction)
+			lastParentTransactionIndex = len(allTransactions) - 1
+			continue
+		}
+
+		// the child lines of split transaction follow the parent line, and the parent line should be replaced by child lines
+		if lastParentTransactionIndex >= 0 {
+			parentTransaction := allTransactions[lastParentTransactionIndex]
+
+			if parentTransaction.accountName == transaction.accountName && parentTransaction.date == transaction.date && parentTransaction.amount == transaction.amount {
+				allTransactions = append(allTransactions[:lastParentTransactionIndex], allTransactions[lastParentTransactionIndex+1:]...)
+			}
+
+			lastParentTransactionIndex = -1
+		}
+
+		transaction.amount = splitAmount
+		allTransactions = append(allTransactions, transaction)
+	}
+
+	return allTransactions, nil
+}
+
+func (c *actualTransactionDataCsvFileImporter) createNewActualTransactionDataTable(allTransactions []*actualTransactionData) datatable.TransactionDataTable {
+	transactionDataTable := datatable.CreateNewWritableTransactionDataTable([]datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+		datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY,
+		datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+		datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_TAGS,
+		datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+		datatable.TRANSACTION_DATA_TABLE_PAYEE,
+	})
+
+	allAccountNames := make(map[string]bool)
+
+	for i := 0; i < len(allTransactions); i++ {
+		allAccountNames[allTransactions[i].accountName] = true
+	}
+
+	// both sides of a transfer appear in the export when both accounts are exported, so the second side should be skipped
+	unmatchedTransferCounts := make(map[string]int)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		amount := transaction.amount
+
+		data := make(map[datatable.TransactionDataTableColumn]string, 9)
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transaction.date + " 00:00:00"
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = transaction.accountName
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = transaction.category
+		data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(getActualTransactionTags(transaction.notes), actualTransactionTagSeparator)
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = transaction.notes
+
+		relatedAccountName := getActualTransactionTransferAccountName(transaction, allAccountNames)
+
+		if relatedAccountName != "" {
+			if amount > 0 {
+				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = relatedAccountName
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = transaction.accountName
+			} else {
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = relatedAccountName
+				amount = -amount
+			}
+
+			transferKey := strings.Join([]string{transaction.date, data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME], data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME], utils.Int64ToString(amount)}, "\n")
+
+			if unmatchedTransferCounts[transferKey] > 0 {
+				unmatchedTransferCounts[transferKey]--
+				continue
+			}
+
+			unmatchedTransferCounts[transferKey]++
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else if transaction.payee == actualTransactionStartingBalancePayee {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else if amount >= 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = transaction.payee
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = transaction.payee
+		}
+
+		transactionDataTable.Add(data)
+	}
+
+	return transactionDataTable
+}
+
+// getActualTransactionTransferAccountName returns the counterpart account name if the transaction is a transfer,
+// the payee of transfer in actual budget is the name of the counterpart account and the transfer has no category
+func getActualTransactionTransferAccountName(transaction *actualTransactionData, allAccountNames map[string]bool) string {
+	if strings.HasPrefix(transaction.payee, actualTransactionTransferPayeePrefix) {
+		return strings.TrimSpace(transaction.payee[len(actualTransactionTransferPayeePrefix):])
+	}
+
+	if transaction.category == "" && transaction.payee != "" && transaction.payee != transaction.accountName && allAccountNames[transaction.payee] {
+		return transaction.payee
+	}
+
+	return ""
+}
+
+// getActualTransactionTags returns the tags in the notes, the tags in actual budget are the words starting with "#"
+func getActualTransactionTags(notes string) []string {
+	tags := make([]string, 0)
+	tagNames := make(map[string]bool)
+	words := strings.Fields(notes)
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+
+		if len(word) <= len(actualTransactionTagPrefix) || !strings.HasPrefix(word, actualTransactionTagPrefix) || strings.HasPrefix(word, actualTransactionTagPrefix+actualTransactionTagPrefix) {
+			continue
+		}
+
+		tagName := word[len(actualTransactionTagPrefix):]
+
+		if tagNames[tagName] {
+			continue
+		}
+
+		tagNames[tagName] = true
+		tags = append(tags, tagName)
+	}
+
+	return tags
+}
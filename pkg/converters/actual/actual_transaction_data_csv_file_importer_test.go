@@ -0,0 +1,113 @@
+package actual
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestActualTransactionDataCsvFileImporterParseImportedData_MinimumValidData(t *testing.T) {
+	converter := ActualTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		"Account,Date,Payee,Notes,Category,Amount,Split_Amount,Cleared,Reconciled\n"+
+			"Checking,2024-09-01,Starting Balance,,Starting Balances,1000,0,true,false\n"+
+			"Checking,2024-09-02,Employer,Paycheck #work,Income,2500.5,0,true,false\n"+
+			"Checking,2024-09-03,Grocery Store,Weekly #food #family,Food,-45.67,0,false,false\n"+
+			"Checking,2024-09-04,Savings,,,-100,0,true,false\n"+
+			"Savings,2024-09-04,Checking,,,100,0,true,false\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 3, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(250050), allNewTransactions[1].Amount)
+	assert.Equal(t, "Income", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Employer", allNewTransactions[1].OriginalPayeeName)
+	assert.Equal(t, []string{"work"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "Paycheck #work", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(4567), allNewTransactions[2].Amount)
+	assert.Equal(t, "Food", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, []string{"food", "family"}, allNewTransactions[2].OriginalTagNames)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[3].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+}
+
+func TestActualTransactionDataCsvFileImporterParseImportedData_SplitTransaction(t *testing.T) {
+	converter := ActualTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"Account,Date,Payee,Notes,Category,Amount,Split_Amount,Cleared,Reconciled\n"+
+			"Checking,2024-09-03,Store,,,-30,0,false,false\n"+
+			"Checking,2024-09-03,Store,,Food,-30,-20,false,false\n"+
+			"Checking,2024-09-03,Store,,Household,-30,-10,false,false\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+
+	assert.Equal(t, int64(2000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Food", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, int64(1000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Household", allNewTransactions[1].OriginalCategoryName)
+}
+
+func TestActualTransactionDataCsvFileImporterParseImportedData_InvalidData(t *testing.T) {
+	converter := ActualTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"Account,Date,Payee,Notes,Amount\n"+
+			"Checking,2024-09-03,Store,,-30\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"Account,Date,Payee,Notes,Category,Amount\n"+
+			"Checking,09/03/2024,Store,,Food,-30\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"Account,Date,Payee,Notes,Category,Amount\n"+
+			"Checking,2024-09-03,Store,,Food,abc\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
@@ -23,6 +23,7 @@ var alipayTransactionSupportedColumns = map[datatable.TransactionDataTableColumn
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
 }
 
 var alipayTransactionTypeNameMapping = map[models.TransactionType]string{
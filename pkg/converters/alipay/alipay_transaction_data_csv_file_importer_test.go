@@ -221,6 +221,7 @@ func TestAlipayCsvFileImporterParseImportedData_ParseAccountName(t *testing.T) {
 
 	assert.Equal(t, 1, len(allNewTransactions))
 	assert.Equal(t, "Alipay", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "test", allNewTransactions[0].OriginalPayeeName)
 
 	// refund to other account
 	data2, err := simplifiedchinese.GB18030.NewEncoder().String("支付宝交易记录明细查询\n" +
@@ -1,6 +1,9 @@
 package camt
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strings"
+)
 
 type camtCreditDebitIndicator string
 
@@ -9,15 +12,37 @@ const (
 	CAMT_INDICATOR_DEBIT  camtCreditDebitIndicator = "DBIT"
 )
 
-type camt053File struct {
-	XMLName                 xml.Name                     `xml:"Document"`
-	BankToCustomerStatement *camtBankToCustomerStatement `xml:"BkToCstmrStmt"`
+const (
+	CAMT_ENTRY_STATUS_PENDING string = "PDNG"
+)
+
+type camtFileType byte
+
+const (
+	CAMT_FILE_TYPE_052 camtFileType = 52
+	CAMT_FILE_TYPE_053 camtFileType = 53
+	CAMT_FILE_TYPE_054 camtFileType = 54
+)
+
+type camtFile struct {
+	XMLName                               xml.Name                                   `xml:"Document"`
+	BankToCustomerAccountReport           *camtBankToCustomerAccountReport           `xml:"BkToCstmrAcctRpt"`
+	BankToCustomerStatement               *camtBankToCustomerStatement               `xml:"BkToCstmrStmt"`
+	BankToCustomerDebitCreditNotification *camtBankToCustomerDebitCreditNotification `xml:"BkToCstmrDbtCdtNtfctn"`
+}
+
+type camtBankToCustomerAccountReport struct {
+	Reports []*camtStatement `xml:"Rpt"`
 }
 
 type camtBankToCustomerStatement struct {
 	Statements []*camtStatement `xml:"Stmt"`
 }
 
+type camtBankToCustomerDebitCreditNotification struct {
+	Notifications []*camtStatement `xml:"Ntfctn"`
+}
+
 type camtStatement struct {
 	Account *camtAccount `xml:"Acct"`
 	Entries []*camtEntry `xml:"Ntry"`
@@ -30,9 +55,13 @@ type camtAccount struct {
 }
 
 type camtEntry struct {
+	EntryReference             string                   `xml:"NtryRef"`
 	Amount                     *camtAmount              `xml:"Amt"`
 	CreditDebitIndicator       camtCreditDebitIndicator `xml:"CdtDbtInd"`
+	Status                     *camtEntryStatus         `xml:"Sts"`
 	BookingDate                *camtDate                `xml:"BookgDt"`
+	ValueDate                  *camtDate                `xml:"ValDt"`
+	AccountServicerReference   string                   `xml:"AcctSvcrRef"`
 	EntryDetails               *camtEntryDetails        `xml:"NtryDtls"`
 	AdditionalEntryInformation string                   `xml:"AddtlNtryInf"`
 }
@@ -42,6 +71,11 @@ type camtAmount struct {
 	Currency string `xml:"Ccy,attr"`
 }
 
+type camtEntryStatus struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
 type camtDate struct {
 	Date     string `xml:"Dt"`
 	DateTime string `xml:"DtTm"`
@@ -53,6 +87,7 @@ type camtEntryDetails struct {
 
 type camtTransactionDetails struct {
 	AmountDetails                    *camtAmountDetails         `xml:"AmtDtls"`
+	RelatedParties                   *camtRelatedParties        `xml:"RltdPties"`
 	RemittanceInformation            *camtRemittanceInformation `xml:"RmtInf"`
 	AdditionalTransactionInformation string                     `xml:"AddtlTxInf"`
 }
@@ -62,6 +97,98 @@ type camtAmountDetails struct {
 	TransactionAmount *camtAmount `xml:"TxAmt>Amt"`
 }
 
+type camtRelatedParties struct {
+	Debtor   *camtParty `xml:"Dbtr"`
+	Creditor *camtParty `xml:"Cdtr"`
+}
+
+type camtParty struct {
+	Name  string                   `xml:"Nm"`
+	Party *camtPartyIdentification `xml:"Pty"`
+}
+
+type camtPartyIdentification struct {
+	Name string `xml:"Nm"`
+}
+
 type camtRemittanceInformation struct {
 	Unstructured []string `xml:"Ustrd"`
 }
+
+// GetName returns the name of the party, both the legacy structure and the structure in newer versions (party inside "Pty" element) are supported
+func (p *camtParty) GetName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	if p.Party != nil {
+		return p.Party.Name
+	}
+
+	return ""
+}
+
+// GetCounterpartyName returns the counterparty name of the transaction, which is the debtor for credit entry and the creditor for debit entry
+func (d *camtTransactionDetails) GetCounterpartyName(indicator camtCreditDebitIndicator) string {
+	if d.RelatedParties == nil {
+		return ""
+	}
+
+	if indicator == CAMT_INDICATOR_CREDIT && d.RelatedParties.Debtor != nil {
+		return d.RelatedParties.Debtor.GetName()
+	} else if indicator == CAMT_INDICATOR_DEBIT && d.RelatedParties.Creditor != nil {
+		return d.RelatedParties.Creditor.GetName()
+	}
+
+	return ""
+}
+
+// GetStatements returns all statements (or reports / notifications) in the camt file of the specified type
+func (f *camtFile) GetStatements(fileType camtFileType) []*camtStatement {
+	if fileType == CAMT_FILE_TYPE_052 && f.BankToCustomerAccountReport != nil {
+		return f.BankToCustomerAccountReport.Reports
+	} else if fileType == CAMT_FILE_TYPE_053 && f.BankToCustomerStatement != nil {
+		return f.BankToCustomerStatement.Statements
+	} else if fileType == CAMT_FILE_TYPE_054 && f.BankToCustomerDebitCreditNotification != nil {
+		return f.BankToCustomerDebitCreditNotification.Notifications
+	}
+
+	return nil
+}
+
+// GetAllStatements returns all statements, reports and notifications in the camt file
+func (f *camtFile) GetAllStatements() []*camtStatement {
+	allStatements := make([]*camtStatement, 0)
+	allStatements = append(allStatements, f.GetStatements(CAMT_FILE_TYPE_052)...)
+	allStatements = append(allStatements, f.GetStatements(CAMT_FILE_TYPE_053)...)
+	allStatements = append(allStatements, f.GetStatements(CAMT_FILE_TYPE_054)...)
+
+	return allStatements
+}
+
+// GetIdentification returns the identification of the account, which is the IBAN or the other identification
+func (a *camtAccount) GetIdentification() string {
+	if a.IBAN != "" {
+		return a.IBAN
+	}
+
+	return a.OtherIdentification
+}
+
+// GetReference returns the unique reference of the entry assigned by the account servicing institution, or the entry reference if it is absent
+func (e *camtEntry) GetReference() string {
+	if reference := strings.TrimSpace(e.AccountServicerReference); reference != "" {
+		return reference
+	}
+
+	return strings.TrimSpace(e.EntryReference)
+}
+
+// IsPending returns whether the entry is not booked yet
+func (e *camtEntry) IsPending() bool {
+	if e.Status == nil {
+		return false
+	}
+
+	return strings.TrimSpace(e.Status.Value) == CAMT_ENTRY_STATUS_PENDING || strings.TrimSpace(e.Status.Code) == CAMT_ENTRY_STATUS_PENDING
+}
@@ -10,15 +10,15 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 )
 
-// camt053FileReader defines the structure of camt.053 file reader
-type camt053FileReader struct {
+// camtFileReader defines the structure of camt.052 / camt.053 / camt.054 file reader
+type camtFileReader struct {
 	xmlDecoder *xml.Decoder
 }
 
-// read returns the imported camt.053 data
+// read returns the imported camt data
 // Reference: https://www.iso20022.org/message-set/1196/download
-func (r *camt053FileReader) read(ctx core.Context) (*camt053File, error) {
-	file := &camt053File{}
+func (r *camtFileReader) read(ctx core.Context) (*camtFile, error) {
+	file := &camtFile{}
 
 	err := r.xmlDecoder.Decode(&file)
 
@@ -29,12 +29,12 @@ func (r *camt053FileReader) read(ctx core.Context) (*camt053File, error) {
 	return file, nil
 }
 
-func createNewCamt053FileReader(data []byte) (*camt053FileReader, error) {
+func createNewCamtFileReader(data []byte) (*camtFileReader, error) {
 	if len(data) > 5 && data[0] == 0x3C && data[1] == 0x3F && data[2] == 0x78 && data[3] == 0x6D && data[4] == 0x6C { // <?xml
 		xmlDecoder := xml.NewDecoder(bytes.NewReader(data))
 		xmlDecoder.CharsetReader = charset.NewReaderLabel
 
-		return &camt053FileReader{
+		return &camtFileReader{
 			xmlDecoder: xmlDecoder,
 		}, nil
 	}
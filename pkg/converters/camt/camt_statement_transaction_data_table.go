@@ -22,6 +22,7 @@ var camtTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]b
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
 }
 
 // camtStatementTransactionDataTable defines the structure of camt statement transaction data table
@@ -223,8 +224,14 @@ func (t *camtStatementTransactionDataRowIterator) parseTransaction(ctx core.Cont
 		return nil, errs.ErrMissingAccountData
 	}
 
-	if entry.BookingDate != nil && entry.BookingDate.DateTime != "" {
-		dateTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(entry.BookingDate.DateTime)
+	transactionDate := entry.BookingDate
+
+	if transactionDate == nil || (transactionDate.DateTime == "" && transactionDate.Date == "") {
+		transactionDate = entry.ValueDate
+	}
+
+	if transactionDate != nil && transactionDate.DateTime != "" {
+		dateTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(transactionDate.DateTime)
 
 		if err != nil {
 			return nil, errs.ErrTransactionTimeInvalid
@@ -232,8 +239,8 @@ func (t *camtStatementTransactionDataRowIterator) parseTransaction(ctx core.Cont
 
 		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = utils.FormatUnixTimeToLongDateTime(dateTime.Unix(), dateTime.Location())
 		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE] = utils.FormatTimezoneOffset(dateTime.Location())
-	} else if entry.BookingDate != nil && entry.BookingDate.Date != "" {
-		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = fmt.Sprintf("%s 00:00:00", entry.BookingDate.Date)
+	} else if transactionDate != nil && transactionDate.Date != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = fmt.Sprintf("%s 00:00:00", transactionDate.Date)
 		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE] = datatable.TRANSACTION_DATA_TABLE_TIMEZONE_NOT_AVAILABLE
 	} else {
 		return nil, errs.ErrMissingTransactionTime
@@ -300,15 +307,39 @@ func (t *camtStatementTransactionDataRowIterator) parseTransaction(ctx core.Cont
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if transactionDetails != nil {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = transactionDetails.GetCounterpartyName(entry.CreditDebitIndicator)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	}
+
 	return data, nil
 }
 
-func createNewCamtStatementTransactionDataTable(file *camt053File) (*camtStatementTransactionDataTable, error) {
-	if file == nil || file.BankToCustomerStatement == nil || len(file.BankToCustomerStatement.Statements) == 0 {
+func createNewCamtStatementTransactionDataTable(statements []*camtStatement) (*camtStatementTransactionDataTable, error) {
+	if len(statements) == 0 {
 		return nil, errs.ErrNotFoundTransactionDataInFile
 	}
 
+	allStatements := make([]*camtStatement, 0, len(statements))
+
+	for i := 0; i < len(statements); i++ {
+		statement := statements[i]
+		bookedEntries := make([]*camtEntry, 0, len(statement.Entries))
+
+		for j := 0; j < len(statement.Entries); j++ {
+			if !statement.Entries[j].IsPending() { // pending entries in intraday reports would be booked and reported again later
+				bookedEntries = append(bookedEntries, statement.Entries[j])
+			}
+		}
+
+		allStatements = append(allStatements, &camtStatement{
+			Account: statement.Account,
+			Entries: bookedEntries,
+		})
+	}
+
 	return &camtStatementTransactionDataTable{
-		allStatements: file.BankToCustomerStatement.Statements,
+		allStatements: allStatements,
 	}, nil
 }
@@ -13,30 +13,39 @@ var camtTransactionTypeNameMapping = map[models.TransactionType]string{
 	models.TRANSACTION_TYPE_TRANSFER: utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER)),
 }
 
-// camt053TransactionDataImporter defines the structure of camt.053 file importer for transaction data
-type camt053TransactionDataImporter struct {
+// camtTransactionDataImporter defines the structure of camt.052 / camt.053 / camt.054 file importer for transaction data
+type camtTransactionDataImporter struct {
+	fileType camtFileType
 }
 
-// Initialize a camt.053 transaction data importer singleton instance
+// Initialize a camt.052, camt.053 and camt.054 transaction data importer singleton instance
 var (
-	Camt053TransactionDataImporter = &camt053TransactionDataImporter{}
+	Camt052TransactionDataImporter = &camtTransactionDataImporter{
+		fileType: CAMT_FILE_TYPE_052,
+	}
+	Camt053TransactionDataImporter = &camtTransactionDataImporter{
+		fileType: CAMT_FILE_TYPE_053,
+	}
+	Camt054TransactionDataImporter = &camtTransactionDataImporter{
+		fileType: CAMT_FILE_TYPE_054,
+	}
 )
 
-// ParseImportedData returns the imported data by parsing the camt.053 file transaction data
-func (c *camt053TransactionDataImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
-	camt053DataReader, err := createNewCamt053FileReader(data)
+// ParseImportedData returns the imported data by parsing the camt file transaction data
+func (c *camtTransactionDataImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	camtDataReader, err := createNewCamtFileReader(data)
 
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	camt053Data, err := camt053DataReader.read(ctx)
+	camtData, err := camtDataReader.read(ctx)
 
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	transactionDataTable, err := createNewCamtStatementTransactionDataTable(camt053Data)
+	transactionDataTable, err := createNewCamtStatementTransactionDataTable(camtData.GetStatements(c.fileType))
 
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, err
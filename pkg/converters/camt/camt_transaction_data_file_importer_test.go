@@ -1,6 +1,7 @@
 package camt
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -641,6 +642,96 @@ func TestCamt053TransactionDataFileParseImportedData_ParseDescription(t *testing
 	assert.Equal(t, "Test Entry", allNewTransactions[0].Comment)
 }
 
+func TestCamt053TransactionDataFileParseImportedData_ParsePayee(t *testing.T) {
+	converter := Camt053TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		`<?xml version="1.0" encoding="UTF-8"?>
+		<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+			<BkToCstmrStmt>
+				<Stmt>
+					<Acct>
+						<Id>
+							<IBAN>123</IBAN>
+						</Id>
+						<Ccy>CNY</Ccy>
+					</Acct>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>CRDT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+						<NtryDtls>
+							<TxDtls>
+								<RltdPties>
+									<Dbtr>
+										<Nm>Test Debtor</Nm>
+									</Dbtr>
+									<Cdtr>
+										<Pty>
+											<Nm>Test Creditor</Nm>
+										</Pty>
+									</Cdtr>
+								</RltdPties>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+				</Stmt>
+			</BkToCstmrStmt>
+		</Document>`), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Test Debtor", allNewTransactions[0].OriginalPayeeName)
+
+	allNewTransactions, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		`<?xml version="1.0" encoding="UTF-8"?>
+		<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+			<BkToCstmrStmt>
+				<Stmt>
+					<Acct>
+						<Id>
+							<IBAN>123</IBAN>
+						</Id>
+						<Ccy>CNY</Ccy>
+					</Acct>
+					<Ntry>
+						<BookgDt>
+							<DtTm>2024-09-01T12:34:56+08:00</DtTm>
+						</BookgDt>
+						<CdtDbtInd>DBIT</CdtDbtInd>
+						<Amt Ccy="CNY">123.45</Amt>
+						<NtryDtls>
+							<TxDtls>
+								<RltdPties>
+									<Dbtr>
+										<Nm>Test Debtor</Nm>
+									</Dbtr>
+									<Cdtr>
+										<Pty>
+											<Nm>Test Creditor</Nm>
+										</Pty>
+									</Cdtr>
+								</RltdPties>
+							</TxDtls>
+						</NtryDtls>
+					</Ntry>
+				</Stmt>
+			</BkToCstmrStmt>
+		</Document>`), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Test Creditor", allNewTransactions[0].OriginalPayeeName)
+}
+
 func TestCamt053TransactionDataFileParseImportedData_MissingAccountNode(t *testing.T) {
 	converter := Camt053TransactionDataImporter
 	context := core.NewNullContext()
@@ -763,3 +854,85 @@ func TestCamt053TransactionDataFileParseImportedData_MissingTransactionRequiredN
 		</Document>`), 0, nil, nil, nil, nil, nil)
 	assert.EqualError(t, err, errs.ErrAccountCurrencyInvalid.Message)
 }
+
+func TestCamt052TransactionDataFileParseImportedData_SkipPendingEntries(t *testing.T) {
+	converter := Camt052TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data, err := os.ReadFile("testdata/camt052_pending_entries.xml")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725125025), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "123", allNewTransactions[0].OriginalSourceAccountName)
+}
+
+func TestCamt052TransactionDataFileParseImportedData_OnlyPendingEntries(t *testing.T) {
+	converter := Camt052TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data, err := os.ReadFile("testdata/camt052_only_pending_entries.xml")
+	assert.Nil(t, err)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
+
+func TestCamt054TransactionDataFileParseImportedData_ParseValueDate(t *testing.T) {
+	converter := Camt054TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data, err := os.ReadFile("testdata/camt054_value_date.xml")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725125025), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(1234), allNewTransactions[0].Amount)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalSourceAccountCurrency)
+}
+
+func TestCamt053TransactionDataFileParseImportedData_WrongMessageType(t *testing.T) {
+	converter := Camt053TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data, err := os.ReadFile("testdata/camt054_booking_date.xml")
+	assert.Nil(t, err)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
@@ -0,0 +1,168 @@
+package camt
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const camtZipFileMaxUncompressedSize = 100 * 1024 * 1024
+
+// camtZipFileTypePriorities defines the order of reading statements in the zip file, the same entry (with the same reference) in
+// the camt.052 reports and camt.054 notifications is skipped if it has been read from the camt.053 statements, which are the final booked data
+var camtZipFileTypePriorities = []camtFileType{
+	CAMT_FILE_TYPE_053,
+	CAMT_FILE_TYPE_052,
+	CAMT_FILE_TYPE_054,
+}
+
+// camtZipTransactionDataImporter defines the structure of zip file (containing multiple camt files) importer for transaction data
+type camtZipTransactionDataImporter struct {
+}
+
+// Initialize a camt zip transaction data importer singleton instance
+var (
+	CamtZipTransactionDataImporter = &camtZipTransactionDataImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing all the camt.052 / camt.053 / camt.054 files in the zip file
+func (c *camtZipTransactionDataImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	allStatements, err := c.readAllStatements(ctx, data)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	transactionDataTable, err := createNewCamtStatementTransactionDataTable(allStatements)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	dataTableImporter := converter.CreateNewSimpleImporterWithTypeNameMapping(camtTransactionTypeNameMapping)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
+
+func (c *camtZipTransactionDataImporter) readAllStatements(ctx core.Context, data []byte) ([]*camtStatement, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		log.Errorf(ctx, "[camt_zip_transaction_data_file_importer.readAllStatements] cannot open zip file, because %s", err.Error())
+		return nil, errs.ErrInvalidZipFile
+	}
+
+	allStatementsByFileType := make(map[camtFileType][]*camtStatement, len(camtZipFileTypePriorities))
+	totalUncompressedSize := uint64(0)
+
+	for i := 0; i < len(zipReader.File); i++ {
+		file := zipReader.File[i]
+		fileName := path.Base(file.Name)
+
+		if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") || strings.HasPrefix(fileName, ".") || !strings.EqualFold(path.Ext(fileName), ".xml") {
+			continue
+		}
+
+		totalUncompressedSize += file.UncompressedSize64
+
+		if totalUncompressedSize > camtZipFileMaxUncompressedSize {
+			log.Errorf(ctx, "[camt_zip_transaction_data_file_importer.readAllStatements] the uncompressed size of zip file exceeds the limit")
+			return nil, errs.ErrInvalidZipFile
+		}
+
+		fileData, err := c.readFile(file)
+
+		if err != nil {
+			log.Errorf(ctx, "[camt_zip_transaction_data_file_importer.readAllStatements] cannot read file \"%s\" in zip file, because %s", file.Name, err.Error())
+			return nil, errs.ErrInvalidZipFile
+		}
+
+		camtDataReader, err := createNewCamtFileReader(fileData)
+
+		if err != nil {
+			log.Warnf(ctx, "[camt_zip_transaction_data_file_importer.readAllStatements] skip file \"%s\" in zip file, because %s", file.Name, err.Error())
+			continue
+		}
+
+		camtData, err := camtDataReader.read(ctx)
+
+		if err != nil {
+			log.Errorf(ctx, "[camt_zip_transaction_data_file_importer.readAllStatements] cannot parse file \"%s\" in zip file, because %s", file.Name, err.Error())
+			return nil, err
+		}
+
+		for _, fileType := range camtZipFileTypePriorities {
+			allStatementsByFileType[fileType] = append(allStatementsByFileType[fileType], camtData.GetStatements(fileType)...)
+		}
+	}
+
+	return c.mergeStatements(ctx, allStatementsByFileType), nil
+}
+
+// mergeStatements returns all statements in the order of file type priorities, and removes the entries which have been read from the statements of the higher priority file type
+func (c *camtZipTransactionDataImporter) mergeStatements(ctx core.Context, allStatementsByFileType map[camtFileType][]*camtStatement) []*camtStatement {
+	allStatements := make([]*camtStatement, 0)
+	readEntryKeys := make(map[string]bool)
+
+	for _, fileType := range camtZipFileTypePriorities {
+		currentFileTypeEntryKeys := make(map[string]bool)
+
+		for _, statement := range allStatementsByFileType[fileType] {
+			accountIdentification := ""
+
+			if statement.Account != nil {
+				accountIdentification = statement.Account.GetIdentification()
+			}
+
+			entries := make([]*camtEntry, 0, len(statement.Entries))
+
+			for _, entry := range statement.Entries {
+				reference := entry.GetReference()
+
+				if reference == "" {
+					entries = append(entries, entry)
+					continue
+				}
+
+				entryKey := accountIdentification + "\n" + reference
+
+				if readEntryKeys[entryKey] {
+					log.Infof(ctx, "[camt_zip_transaction_data_file_importer.mergeStatements] skip duplicate entry \"%s\" of account \"%s\" in camt.%03d file", reference, accountIdentification, fileType)
+					continue
+				}
+
+				currentFileTypeEntryKeys[entryKey] = true
+				entries = append(entries, entry)
+			}
+
+			statement.Entries = entries
+			allStatements = append(allStatements, statement)
+		}
+
+		for entryKey := range currentFileTypeEntryKeys {
+			readEntryKeys[entryKey] = true
+		}
+	}
+
+	return allStatements
+}
+
+func (c *camtZipTransactionDataImporter) readFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(io.LimitReader(reader, camtZipFileMaxUncompressedSize))
+}
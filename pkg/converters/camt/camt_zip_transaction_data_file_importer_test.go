@@ -0,0 +1,161 @@
+package camt
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestCamtZipTransactionDataFileParseImportedData_MultipleFiles(t *testing.T) {
+	converter := CamtZipTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data := createTestZipFileFromDirectory(t, "testdata/multiple_files")
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+
+	var incomeTransaction, expenseTransaction *models.ImportTransaction
+
+	for i := 0; i < len(allNewTransactions); i++ {
+		if allNewTransactions[i].Type == models.TRANSACTION_DB_TYPE_INCOME {
+			incomeTransaction = allNewTransactions[i]
+		} else if allNewTransactions[i].Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			expenseTransaction = allNewTransactions[i]
+		}
+	}
+
+	assert.NotNil(t, incomeTransaction)
+	assert.Equal(t, int64(12345), incomeTransaction.Amount)
+	assert.Equal(t, "123", incomeTransaction.OriginalSourceAccountName)
+	assert.Equal(t, "CNY", incomeTransaction.OriginalSourceAccountCurrency)
+
+	assert.NotNil(t, expenseTransaction)
+	assert.Equal(t, int64(123), expenseTransaction.Amount)
+	assert.Equal(t, "456", expenseTransaction.OriginalSourceAccountName)
+	assert.Equal(t, "USD", expenseTransaction.OriginalSourceAccountCurrency)
+}
+
+func TestCamtZipTransactionDataFileParseImportedData_SkipDuplicateEntries(t *testing.T) {
+	converter := CamtZipTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "EUR",
+	}
+
+	data := createTestZipFileFromDirectory(t, "testdata/duplicate_entries")
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	// the "Salary" entry in camt.054 file has the same account servicer reference with the entry in camt.053 file, and the "Refund" entry belongs to another account
+	assert.Equal(t, 5, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+
+	actualComments := make([]string, len(allNewTransactions))
+
+	for i := 0; i < len(allNewTransactions); i++ {
+		actualComments[i] = allNewTransactions[i].Comment
+	}
+
+	sort.Strings(actualComments)
+	assert.Equal(t, []string{"Bank fee", "Coffee", "Groceries", "Refund", "Salary"}, actualComments)
+}
+
+func TestCamtZipTransactionDataFileParseImportedData_NoCamtFile(t *testing.T) {
+	converter := CamtZipTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data := createTestZipFile(t, map[string]string{
+		"readme.txt": "not a camt file",
+	})
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
+
+func TestCamtZipTransactionDataFileParseImportedData_InvalidZipFile(t *testing.T) {
+	converter := CamtZipTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("not a zip file"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidZipFile.Message)
+}
+
+func createTestZipFileFromDirectory(t *testing.T, directory string) []byte {
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(directory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		content, err := os.ReadFile(filePath)
+
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(directory, filePath)
+
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(relativePath)] = string(content)
+		return nil
+	})
+
+	assert.Nil(t, err)
+
+	return createTestZipFile(t, files)
+}
+
+func createTestZipFile(t *testing.T, files map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+
+	for fileName, content := range files {
+		writer, err := zipWriter.Create(fileName)
+		assert.Nil(t, err)
+
+		_, err = writer.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, zipWriter.Close())
+
+	return buffer.Bytes()
+}
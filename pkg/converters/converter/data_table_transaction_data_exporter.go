@@ -49,6 +49,34 @@ func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTags(dataTableBuilder, transaction.TransactionId, allTagIndexes, tagMap)
 		dataRowMap[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(transaction.Comment)
 
+		if transaction.HasSplits && len(transaction.Splits) > 0 {
+			// each split line is exported as a separate row, so that the amounts of categories are still correct after importing
+			for j := 0; j < len(transaction.Splits); j++ {
+				split := transaction.Splits[j]
+				splitDataRowMap := make(map[datatable.TransactionDataTableColumn]string, len(dataRowMap))
+
+				for column, value := range dataRowMap {
+					splitDataRowMap[column] = value
+				}
+
+				splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = c.getExportedTransactionCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+				splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = c.getExportedTransactionSubCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+				splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(split.Amount)
+				splitTagIds := make([]int64, 0, len(allTagIndexes[transaction.TransactionId]))
+				splitTagIds = append(splitTagIds, allTagIndexes[transaction.TransactionId]...)
+				splitTagIds = append(splitTagIds, split.GetTagIds()...)
+				splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTagNames(dataTableBuilder, utils.ToUniqueInt64Slice(splitTagIds), tagMap)
+
+				if split.Comment != "" {
+					splitDataRowMap[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(split.Comment)
+				}
+
+				dataTableBuilder.AppendTransaction(splitDataRowMap)
+			}
+
+			continue
+		}
+
 		dataTableBuilder.AppendTransaction(dataRowMap)
 	}
 
@@ -136,6 +164,10 @@ func (c *DataTableTransactionDataExporter) getExportedTags(dataTableBuilder data
 		return ""
 	}
 
+	return c.getExportedTagNames(dataTableBuilder, tagIndexes, tagMap)
+}
+
+func (c *DataTableTransactionDataExporter) getExportedTagNames(dataTableBuilder datatable.TransactionDataTableBuilder, tagIndexes []int64, tagMap map[int64]*models.TransactionTag) string {
 	var ret strings.Builder
 
 	for i := 0; i < len(tagIndexes); i++ {
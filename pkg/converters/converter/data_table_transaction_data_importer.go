@@ -342,6 +342,18 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			description = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_DESCRIPTION)
 		}
 
+		payeeName := ""
+
+		if dataTable.HasColumn(datatable.TRANSACTION_DATA_TABLE_PAYEE) {
+			payeeName = dataRow.GetData(datatable.TRANSACTION_DATA_TABLE_PAYEE)
+		}
+
+		splits, err := c.getTransactionSplits(ctx, user, dataRow, dataRowIndex, transactionDbType, amount, expenseCategoryMap, incomeCategoryMap)
+
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, err
+		}
+
 		transaction := &models.ImportTransaction{
 			Transaction: &models.Transaction{
 				Uid:                  user.Uid,
@@ -355,6 +367,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 				RelatedAccountId:     relatedAccountId,
 				RelatedAccountAmount: relatedAccountAmount,
 				Comment:              description,
+				Splits:               splits,
 				GeoLongitude:         geoLongitude,
 				GeoLatitude:          geoLatitude,
 				CreatedIp:            "127.0.0.1",
@@ -366,6 +379,7 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			OriginalDestinationAccountName:     account2Name,
 			OriginalDestinationAccountCurrency: account2Currency,
 			OriginalTagNames:                   tagNames,
+			OriginalPayeeName:                  payeeName,
 		}
 
 		allNewTransactions = append(allNewTransactions, transaction)
@@ -459,6 +473,71 @@ func (c *DataTableTransactionDataImporter) getTransactionCategory(categories map
 	return subCategory, exists
 }
 
+func (c *DataTableTransactionDataImporter) getTransactionSplits(ctx core.Context, user *models.User, dataRow datatable.TransactionDataRow, dataRowIndex int, transactionDbType models.TransactionDbType, amount int64, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory) ([]*models.TransactionSplit, error) {
+	splitDataRow, ok := dataRow.(datatable.TransactionSplitDataRow)
+
+	if !ok {
+		return nil, nil
+	}
+
+	splitItems := splitDataRow.GetSplits()
+
+	if len(splitItems) < 1 {
+		return nil, nil
+	}
+
+	var categoryMap map[string]map[string]*models.TransactionCategory
+
+	if transactionDbType == models.TRANSACTION_DB_TYPE_EXPENSE {
+		categoryMap = expenseCategoryMap
+	} else if transactionDbType == models.TRANSACTION_DB_TYPE_INCOME {
+		categoryMap = incomeCategoryMap
+	} else {
+		log.Warnf(ctx, "[data_table_transaction_data_importer.getTransactionSplits] skip split lines in data row \"index:%d\" for user \"uid:%d\", because transaction type \"%d\" does not support split lines", dataRowIndex, user.Uid, transactionDbType)
+		return nil, nil
+	}
+
+	if len(splitItems) < models.TransactionSplitMinCount || len(splitItems) > models.TransactionSplitMaxCount {
+		log.Warnf(ctx, "[data_table_transaction_data_importer.getTransactionSplits] skip split lines in data row \"index:%d\" for user \"uid:%d\", because split count is %d", dataRowIndex, user.Uid, len(splitItems))
+		return nil, nil
+	}
+
+	splits := make([]*models.TransactionSplit, len(splitItems))
+
+	for i := 0; i < len(splitItems); i++ {
+		splitItem := splitItems[i]
+		splitAmount, err := utils.ParseAmount(splitItem.Amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[data_table_transaction_data_importer.getTransactionSplits] cannot parse split amount \"%s\" in data row \"index:%d\" for user \"uid:%d\", because %s", splitItem.Amount, dataRowIndex, user.Uid, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		category, exists := c.getTransactionCategory(categoryMap, splitItem.Category, splitItem.SubCategory)
+
+		// split lines can only refer to existed categories, the whole transaction would be imported to the category of data row if there is any new category
+		if !exists || category == nil || category.CategoryId == 0 {
+			log.Warnf(ctx, "[data_table_transaction_data_importer.getTransactionSplits] skip split lines in data row \"index:%d\" for user \"uid:%d\", because split category \"%s\" does not exist", dataRowIndex, user.Uid, splitItem.SubCategory)
+			return nil, nil
+		}
+
+		splits[i] = &models.TransactionSplit{
+			Uid:          user.Uid,
+			CategoryId:   category.CategoryId,
+			Amount:       splitAmount,
+			Comment:      splitItem.Description,
+			DisplayOrder: int32(i + 1),
+		}
+	}
+
+	if models.GetTransactionSplitsTotalAmount(splits) != amount {
+		log.Warnf(ctx, "[data_table_transaction_data_importer.getTransactionSplits] skip split lines in data row \"index:%d\" for user \"uid:%d\", because total split amount is not equal to transaction amount %d", dataRowIndex, user.Uid, amount)
+		return nil, nil
+	}
+
+	return splits, nil
+}
+
 func (c *DataTableTransactionDataImporter) createNewAccountModel(uid int64, accountName string, currency string) *models.Account {
 	return &models.Account{
 		Uid:      uid,
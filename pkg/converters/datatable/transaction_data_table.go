@@ -26,6 +26,22 @@ type TransactionDataRow interface {
 	GetData(column TransactionDataTableColumn) string
 }
 
+// TransactionSplitDataRow defines the structure of transaction data row which contains split lines
+type TransactionSplitDataRow interface {
+	TransactionDataRow
+
+	// GetSplits returns the split lines of the transaction data row
+	GetSplits() []*TransactionDataRowSplit
+}
+
+// TransactionDataRowSplit defines the structure of split line of transaction data row
+type TransactionDataRowSplit struct {
+	Category    string
+	SubCategory string
+	Amount      string
+	Description string
+}
+
 // TransactionDataRowIterator defines the structure of transaction data row iterator
 type TransactionDataRowIterator interface {
 	// HasNext returns whether the iterator does not reach the end
@@ -72,6 +88,7 @@ const (
 	TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION      TransactionDataTableColumn = 12
 	TRANSACTION_DATA_TABLE_TAGS                     TransactionDataTableColumn = 13
 	TRANSACTION_DATA_TABLE_DESCRIPTION              TransactionDataTableColumn = 14
+	TRANSACTION_DATA_TABLE_PAYEE                    TransactionDataTableColumn = 15
 )
 
 // TRANSACTION_DATA_TABLE_TIMEZONE_NOT_AVAILABLE represents the constant for timezone not available
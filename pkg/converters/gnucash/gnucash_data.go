@@ -84,4 +84,5 @@ type gnucashTransactionSplitData struct {
 	Value           string `xml:"value"`
 	Quantity        string `xml:"quantity"`
 	Account         string `xml:"account"`
+	Memo            string `xml:"memo"`
 }
@@ -759,6 +759,77 @@ func TestGnuCashTransactionDatabaseFileParseImportedData_SkipZeroAmountTransacti
 	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
 }
 
+func TestGnuCashTransactionDatabaseFileParseImportedData_ParseSplitTransaction(t *testing.T) {
+	converter := GnuCashTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Test Category2": {
+			"": &models.TransactionCategory{CategoryId: 1001, Name: "Test Category2"},
+		},
+		"Test Category3": {
+			"": &models.TransactionCategory{CategoryId: 1002, Name: "Test Category3"},
+		},
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		gnucashCommonValidDataCaseHeader+
+			"<gnc:account version=\"2.0.0\">\n"+
+			"  <act:name>Test Category2</act:name>\n"+
+			"  <act:id type=\"guid\">00000000000000000000000000000200</act:id>\n"+
+			"  <act:type>EXPENSE</act:type>\n"+
+			"  <act:parent type=\"guid\">00000000000000000000000000000001</act:parent>\n"+
+			"</gnc:account>\n"+
+			"<gnc:account version=\"2.0.0\">\n"+
+			"  <act:name>Test Category3</act:name>\n"+
+			"  <act:id type=\"guid\">00000000000000000000000000000300</act:id>\n"+
+			"  <act:type>EXPENSE</act:type>\n"+
+			"  <act:parent type=\"guid\">00000000000000000000000000000001</act:parent>\n"+
+			"</gnc:account>\n"+
+			"<gnc:transaction version=\"2.0.0\">\n"+
+			"  <trn:date-posted>\n"+
+			"    <ts:date>2024-09-01 12:34:56 +0000</ts:date>\n"+
+			"  </trn:date-posted>\n"+
+			"  <trn:splits>\n"+
+			"    <trn:split>\n"+
+			"      <split:memo>Part1</split:memo>\n"+
+			"      <split:quantity>100/100</split:quantity>\n"+
+			"      <split:account type=\"guid\">00000000000000000000000000000200</split:account>\n"+
+			"    </trn:split>\n"+
+			"    <trn:split>\n"+
+			"      <split:memo>Part2</split:memo>\n"+
+			"      <split:quantity>200/100</split:quantity>\n"+
+			"      <split:account type=\"guid\">00000000000000000000000000000300</split:account>\n"+
+			"    </trn:split>\n"+
+			"    <trn:split>\n"+
+			"      <split:quantity>-300/100</split:quantity>\n"+
+			"      <split:account type=\"guid\">00000000000000000000000000001000</split:account>\n"+
+			"    </trn:split>\n"+
+			"  </trn:splits>\n"+
+			"</gnc:transaction>\n"+
+			gnucashCommonValidDataCaseFooter), 0, nil, expenseCategoryMap, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(300), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, int64(1001), allNewTransactions[0].CategoryId)
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, int64(1001), allNewTransactions[0].Splits[0].CategoryId)
+	assert.Equal(t, int64(100), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, "Part1", allNewTransactions[0].Splits[0].Comment)
+	assert.Equal(t, int64(1002), allNewTransactions[0].Splits[1].CategoryId)
+	assert.Equal(t, int64(200), allNewTransactions[0].Splits[1].Amount)
+	assert.Equal(t, "Part2", allNewTransactions[0].Splits[1].Comment)
+}
+
 func TestGnuCashTransactionDatabaseFileParseImportedData_NotSupportedToParseSplitTransaction(t *testing.T) {
 	converter := GnuCashTransactionDataImporter
 	context := core.NewNullContext()
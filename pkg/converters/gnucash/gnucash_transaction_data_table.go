@@ -37,6 +37,7 @@ type gnucashTransactionDataRow struct {
 	dataTable  *gnucashTransactionDataTable
 	data       *gnucashTransactionData
 	finalItems map[datatable.TransactionDataTableColumn]string
+	splits     []*datatable.TransactionDataRowSplit
 	isValid    bool
 }
 
@@ -81,6 +82,11 @@ func (r *gnucashTransactionDataRow) GetData(column datatable.TransactionDataTabl
 	return ""
 }
 
+// GetSplits returns the split lines of the transaction data row
+func (r *gnucashTransactionDataRow) GetSplits() []*datatable.TransactionDataRowSplit {
+	return r.splits
+}
+
 // HasNext returns whether the iterator does not reach the end
 func (t *gnucashTransactionDataRowIterator) HasNext() bool {
 	return t.currentIndex+1 < len(t.dataTable.allData)
@@ -95,7 +101,7 @@ func (t *gnucashTransactionDataRowIterator) Next(ctx core.Context, user *models.
 	t.currentIndex++
 
 	data := t.dataTable.allData[t.currentIndex]
-	rowItems, isValid, err := t.parseTransaction(ctx, user, data)
+	rowItems, splits, isValid, err := t.parseTransaction(ctx, user, data)
 
 	if err != nil {
 		log.Errorf(ctx, "[gnucash_transaction_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
@@ -106,21 +112,23 @@ func (t *gnucashTransactionDataRowIterator) Next(ctx core.Context, user *models.
 		dataTable:  t.dataTable,
 		data:       data,
 		finalItems: rowItems,
+		splits:     splits,
 		isValid:    isValid,
 	}, nil
 }
 
-func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, gnucashTransaction *gnucashTransactionData) (map[datatable.TransactionDataTableColumn]string, bool, error) {
+func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, gnucashTransaction *gnucashTransactionData) (map[datatable.TransactionDataTableColumn]string, []*datatable.TransactionDataRowSplit, bool, error) {
 	data := make(map[datatable.TransactionDataTableColumn]string, len(gnucashTransactionSupportedColumns))
+	var splits []*datatable.TransactionDataRowSplit
 
 	if gnucashTransaction.PostedDate == "" {
-		return nil, false, errs.ErrMissingTransactionTime
+		return nil, nil, false, errs.ErrMissingTransactionTime
 	}
 
 	dateTime, err := utils.ParseFromLongDateTimeWithTimezone2(gnucashTransaction.PostedDate)
 
 	if err != nil {
-		return nil, false, errs.ErrTransactionTimeInvalid
+		return nil, nil, false, errs.ErrTransactionTimeInvalid
 	}
 
 	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = utils.FormatUnixTimeToLongDateTime(dateTime.Unix(), dateTime.Location())
@@ -134,23 +142,23 @@ func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, u
 		account2 := t.dataTable.accountMap[splitData2.Account]
 
 		if account1 == nil || account2 == nil {
-			return nil, false, errs.ErrMissingAccountData
+			return nil, nil, false, errs.ErrMissingAccountData
 		}
 
 		if splitData1.Quantity == "" || splitData2.Quantity == "" {
-			return nil, false, errs.ErrAmountInvalid
+			return nil, nil, false, errs.ErrAmountInvalid
 		}
 
 		amount1, err := t.parseAmount(splitData1.Quantity)
 
 		if err != nil {
-			return nil, false, err
+			return nil, nil, false, err
 		}
 
 		amount2, err := t.parseAmount(splitData2.Quantity)
 
 		if err != nil {
-			return nil, false, err
+			return nil, nil, false, err
 		}
 
 		if ((account1.AccountType == gnucashEquityAccountType || account1.AccountType == gnucashIncomeAccountType) && gnucashAssetOrLiabilityAccountTypes[account2.AccountType]) ||
@@ -178,7 +186,7 @@ func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, u
 			if toAccount.Commodity != nil && toAccount.Commodity.Space == gnucashCommodityCurrencySpace {
 				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = toAccount.Commodity.Id
 			} else {
-				return nil, false, errs.ErrAccountCurrencyInvalid
+				return nil, nil, false, errs.ErrAccountCurrencyInvalid
 			}
 
 			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = toAmount
@@ -197,7 +205,7 @@ func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, u
 			amount, err := utils.ParseAmount(fromAmount)
 
 			if err != nil {
-				return nil, false, errs.ErrAmountInvalid
+				return nil, nil, false, errs.ErrAmountInvalid
 			}
 
 			fromAmount = utils.FormatAmount(-amount)
@@ -210,7 +218,7 @@ func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, u
 			if fromAccount.Commodity != nil && fromAccount.Commodity.Space == gnucashCommodityCurrencySpace {
 				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromAccount.Commodity.Id
 			} else {
-				return nil, false, errs.ErrAccountCurrencyInvalid
+				return nil, nil, false, errs.ErrAccountCurrencyInvalid
 			}
 
 			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = fromAmount
@@ -230,7 +238,7 @@ func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, u
 				toAmount = amount1
 			} else {
 				log.Errorf(ctx, "[gnucash_transaction_table.parseTransaction] cannot parse transfer transaction \"id:%s\", because unexcepted account amounts \"%s\" and \"%s\"", gnucashTransaction.Id, amount1, amount2)
-				return nil, false, errs.ErrInvalidGnuCashFile
+				return nil, nil, false, errs.ErrInvalidGnuCashFile
 			}
 
 			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER))
@@ -252,50 +260,170 @@ func (t *gnucashTransactionDataRowIterator) parseTransaction(ctx core.Context, u
 			data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = toAmount
 		} else {
 			log.Errorf(ctx, "[gnucash_transaction_table.parseTransaction] cannot parse transaction \"id:%s\", because unexcepted account types \"%s\" and \"%s\"", gnucashTransaction.Id, account1.AccountType, account2.AccountType)
-			return nil, false, errs.ErrThereAreNotSupportedTransactionType
+			return nil, nil, false, errs.ErrThereAreNotSupportedTransactionType
 		}
 	} else if len(gnucashTransaction.Splits) == 1 {
 		splitData := gnucashTransaction.Splits[0]
 		account := t.dataTable.accountMap[splitData.Account]
 
 		if account == nil {
-			return nil, false, errs.ErrMissingAccountData
+			return nil, nil, false, errs.ErrMissingAccountData
 		}
 
 		if splitData.Quantity == "" {
-			return nil, false, errs.ErrAmountInvalid
+			return nil, nil, false, errs.ErrAmountInvalid
 		}
 
 		amount, err := t.parseAmount(splitData.Quantity)
 
 		if err != nil {
-			return nil, false, err
+			return nil, nil, false, err
 		}
 
 		amountNum, err := utils.ParseAmount(amount)
 
 		if err != nil {
-			return nil, false, err
+			return nil, nil, false, err
 		}
 
 		if amountNum == 0 {
 			log.Warnf(ctx, "[gnucash_transaction_table.parseTransaction] skip parsing transaction \"id:%s\" with zero amount", gnucashTransaction.Id)
-			return nil, false, nil
+			return nil, nil, false, nil
 		}
 
 		log.Errorf(ctx, "[gnucash_transaction_table.parseTransaction] cannot parse transaction \"id:%s\", because split count is %d", gnucashTransaction.Id, len(gnucashTransaction.Splits))
-		return nil, false, errs.ErrThereAreNotSupportedTransactionType
+		return nil, nil, false, errs.ErrThereAreNotSupportedTransactionType
 	} else if len(gnucashTransaction.Splits) < 1 {
 		log.Errorf(ctx, "[gnucash_transaction_table.parseTransaction] cannot parse transaction \"id:%s\", because split count is %d", gnucashTransaction.Id, len(gnucashTransaction.Splits))
-		return nil, false, errs.ErrInvalidGnuCashFile
+		return nil, nil, false, errs.ErrInvalidGnuCashFile
 	} else {
-		log.Errorf(ctx, "[gnucash_transaction_table.parseTransaction] cannot parse split transaction \"id:%s\", because split count is %d", gnucashTransaction.Id, len(gnucashTransaction.Splits))
-		return nil, false, errs.ErrNotSupportedSplitTransactions
+		var err error
+		splits, err = t.parseMultipleSplitsTransaction(ctx, gnucashTransaction, data)
+
+		if err != nil {
+			return nil, nil, false, err
+		}
 	}
 
 	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = gnucashTransaction.Description
 
-	return data, true, nil
+	return data, splits, true, nil
+}
+
+func (t *gnucashTransactionDataRowIterator) parseMultipleSplitsTransaction(ctx core.Context, gnucashTransaction *gnucashTransactionData, data map[datatable.TransactionDataTableColumn]string) ([]*datatable.TransactionDataRowSplit, error) {
+	var assetOrLiabilitySplitData *gnucashTransactionSplitData
+	categorySplitDataList := make([]*gnucashTransactionSplitData, 0, len(gnucashTransaction.Splits)-1)
+	categoryAccountType := ""
+
+	for i := 0; i < len(gnucashTransaction.Splits); i++ {
+		splitData := gnucashTransaction.Splits[i]
+		account := t.dataTable.accountMap[splitData.Account]
+
+		if account == nil {
+			return nil, errs.ErrMissingAccountData
+		}
+
+		if gnucashAssetOrLiabilityAccountTypes[account.AccountType] {
+			if assetOrLiabilitySplitData != nil {
+				log.Errorf(ctx, "[gnucash_transaction_table.parseMultipleSplitsTransaction] cannot parse split transaction \"id:%s\", because there are more than one asset or liability accounts", gnucashTransaction.Id)
+				return nil, errs.ErrNotSupportedSplitTransactions
+			}
+
+			assetOrLiabilitySplitData = splitData
+			continue
+		}
+
+		if account.AccountType != gnucashExpenseAccountType && account.AccountType != gnucashIncomeAccountType {
+			log.Errorf(ctx, "[gnucash_transaction_table.parseMultipleSplitsTransaction] cannot parse split transaction \"id:%s\", because unexcepted account type \"%s\"", gnucashTransaction.Id, account.AccountType)
+			return nil, errs.ErrNotSupportedSplitTransactions
+		}
+
+		if categoryAccountType != "" && categoryAccountType != account.AccountType {
+			log.Errorf(ctx, "[gnucash_transaction_table.parseMultipleSplitsTransaction] cannot parse split transaction \"id:%s\", because there are both income and expense accounts", gnucashTransaction.Id)
+			return nil, errs.ErrNotSupportedSplitTransactions
+		}
+
+		categoryAccountType = account.AccountType
+		categorySplitDataList = append(categorySplitDataList, splitData)
+	}
+
+	if assetOrLiabilitySplitData == nil || len(categorySplitDataList) < 1 {
+		log.Errorf(ctx, "[gnucash_transaction_table.parseMultipleSplitsTransaction] cannot parse split transaction \"id:%s\", because there is no asset or liability account or category account", gnucashTransaction.Id)
+		return nil, errs.ErrNotSupportedSplitTransactions
+	}
+
+	account := t.dataTable.accountMap[assetOrLiabilitySplitData.Account]
+
+	if account.Commodity == nil || account.Commodity.Space != gnucashCommodityCurrencySpace {
+		return nil, errs.ErrAccountCurrencyInvalid
+	}
+
+	amount, err := t.parseAmountNumber(assetOrLiabilitySplitData.Quantity)
+
+	if err != nil {
+		return nil, err
+	}
+
+	isExpense := categoryAccountType == gnucashExpenseAccountType
+
+	if isExpense {
+		amount = -amount
+	}
+
+	splits := make([]*datatable.TransactionDataRowSplit, len(categorySplitDataList))
+
+	for i := 0; i < len(categorySplitDataList); i++ {
+		splitData := categorySplitDataList[i]
+		categoryAccount := t.dataTable.accountMap[splitData.Account]
+		splitAmount, err := t.parseAmountNumber(splitData.Quantity)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !isExpense {
+			splitAmount = -splitAmount
+		}
+
+		splits[i] = &datatable.TransactionDataRowSplit{
+			Category:    t.getCategoryName(categoryAccount),
+			SubCategory: categoryAccount.Name,
+			Amount:      utils.FormatAmount(splitAmount),
+			Description: splitData.Memo,
+		}
+	}
+
+	if isExpense {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE))
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_INCOME))
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = splits[0].Category
+	data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = splits[0].SubCategory
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = account.Name
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = account.Commodity.Id
+	data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+
+	if len(splits) < 2 {
+		return nil, nil
+	}
+
+	return splits, nil
+}
+
+func (t *gnucashTransactionDataRowIterator) parseAmountNumber(quantity string) (int64, error) {
+	if quantity == "" {
+		return 0, errs.ErrAmountInvalid
+	}
+
+	amount, err := t.parseAmount(quantity)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return utils.ParseAmount(amount)
 }
 
 func (t *gnucashTransactionDataRowIterator) parseAmount(quantity string) (string, error) {
@@ -0,0 +1,162 @@
+package ledger
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ledgerAccountType represents the ledger account type
+type ledgerAccountType byte
+
+// Ledger account types
+const (
+	ledgerUnknownAccountType     ledgerAccountType = 0
+	ledgerAssetsAccountType      ledgerAccountType = 1
+	ledgerLiabilitiesAccountType ledgerAccountType = 2
+	ledgerEquityAccountType      ledgerAccountType = 3
+	ledgerIncomeAccountType      ledgerAccountType = 4
+	ledgerExpensesAccountType    ledgerAccountType = 5
+)
+
+// ledgerTransactionStatus represents the ledger transaction (or posting) status
+type ledgerTransactionStatus string
+
+// Ledger transaction status
+const (
+	ledgerTransactionStatusUnmarked ledgerTransactionStatus = ""
+	ledgerTransactionStatusPending  ledgerTransactionStatus = "!"
+	ledgerTransactionStatusCleared  ledgerTransactionStatus = "*"
+)
+
+// ledgerData defines the structure of ledger journal data
+type ledgerData struct {
+	Accounts     map[string]*ledgerAccount
+	Commodities  map[string]string
+	Transactions []*ledgerTransactionEntry
+}
+
+// ledgerAccount defines the structure of ledger account
+type ledgerAccount struct {
+	Name        string
+	AccountType ledgerAccountType
+}
+
+// ledgerTransactionEntry defines the structure of ledger transaction entry
+type ledgerTransactionEntry struct {
+	Date        string
+	Status      ledgerTransactionStatus
+	Code        string
+	Payee       string
+	Description string
+	Postings    []*ledgerPosting
+	Tags        []string
+}
+
+// ledgerPosting defines the structure of ledger transaction posting
+type ledgerPosting struct {
+	Account             string
+	Amount              string
+	OriginalAmount      string
+	Commodity           string
+	Price               string
+	PriceCommodity      string
+	TotalPrice          string
+	TotalPriceCommodity string
+
+	amount     *big.Rat
+	price      *big.Rat
+	totalPrice *big.Rat
+}
+
+// ledgerAutomatedTransaction defines the structure of ledger automated transaction, the postings of it are added to every transaction which has matched posting
+type ledgerAutomatedTransaction struct {
+	Query    string
+	Postings []*ledgerAutomatedPosting
+
+	accountPatterns     []*regexp.Regexp
+	descriptionPatterns []*regexp.Regexp
+}
+
+// ledgerAutomatedPosting defines the structure of ledger automated transaction posting, the amount of it is fixed amount or the multiplier of matched posting amount
+type ledgerAutomatedPosting struct {
+	Account   string
+	Commodity string
+
+	amount     *big.Rat
+	multiplier *big.Rat
+}
+
+// ledgerPeriod defines the structure of the period of ledger periodic transaction
+type ledgerPeriod struct {
+	StartDate      time.Time
+	EndDate        time.Time
+	IntervalDays   int
+	IntervalMonths int
+}
+
+func (a *ledgerAccount) isOpeningBalanceEquityAccount() bool {
+	if a.AccountType != ledgerEquityAccountType {
+		return false
+	}
+
+	nameItems := strings.Split(a.Name, ledgerAccountNameItemsSeparator)
+
+	if len(nameItems) < 2 {
+		return false
+	}
+
+	lastItem := strings.ToLower(nameItems[len(nameItems)-1])
+	lastItem = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(lastItem)
+
+	return lastItem == "openingbalances" || lastItem == "openingbalance"
+}
+
+// getCurrency returns the currency code of the specified commodity
+func (d *ledgerData) getCurrency(commodity string) string {
+	if currency, exists := d.Commodities[commodity]; exists && currency != "" {
+		return currency
+	}
+
+	if currency, exists := ledgerCommoditySymbolCurrencyMap[commodity]; exists {
+		return currency
+	}
+
+	return commodity
+}
+
+// isMatch returns whether the specified posting of the transaction matches the query of the automated transaction
+func (t *ledgerAutomatedTransaction) isMatch(transactionEntry *ledgerTransactionEntry, posting *ledgerPosting) bool {
+	if len(t.accountPatterns) > 0 {
+		matched := false
+
+		for i := 0; i < len(t.accountPatterns); i++ {
+			if t.accountPatterns[i].MatchString(posting.Account) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	if len(t.descriptionPatterns) > 0 {
+		matched := false
+
+		for i := 0; i < len(t.descriptionPatterns); i++ {
+			if t.descriptionPatterns[i].MatchString(transactionEntry.Payee) || t.descriptionPatterns[i].MatchString(transactionEntry.Description) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return len(t.accountPatterns) > 0 || len(t.descriptionPatterns) > 0
+}
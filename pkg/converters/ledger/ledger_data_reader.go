@@ -0,0 +1,1575 @@
+package ledger
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"math/big"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	textunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerAccountNameItemsSeparator = ":"
+const ledgerTopLevelCommentPrefixes = ";#%|*"
+const ledgerCommentPrefix = ';'
+const ledgerTagSeparator = ':'
+const ledgerPayeeNoteSeparator = "|"
+const ledgerTotalPricePrefix = "@@"
+const ledgerPricePrefix = "@"
+const ledgerBalanceAssertionPrefix = "="
+
+const ledgerPeriodicTransactionMaxOccurrences = 10000
+const ledgerZipFileMaxUncompressedSize = 100 * 1024 * 1024
+
+// the file extensions of ledger journal files in zip file
+var ledgerFileExtensions = map[string]bool{
+	".ledger":  true,
+	".journal": true,
+	".hledger": true,
+	".dat":     true,
+}
+
+// the intervals of period expression, the values are the days and months of the interval
+var ledgerPeriodIntervalMap = map[string][2]int{
+	"daily":       {1, 0},
+	"weekly":      {7, 0},
+	"biweekly":    {14, 0},
+	"fortnightly": {14, 0},
+	"monthly":     {0, 1},
+	"bimonthly":   {0, 2},
+	"quarterly":   {0, 3},
+	"yearly":      {0, 12},
+	"annually":    {0, 12},
+}
+
+// the interval units of "every N UNITS" period expression, the values are the days and months of the interval unit
+var ledgerPeriodIntervalUnitMap = map[string][2]int{
+	"day":     {1, 0},
+	"week":    {7, 0},
+	"month":   {0, 1},
+	"quarter": {0, 3},
+	"year":    {0, 12},
+}
+
+// the hledger query prefixes which are not supported in automated transaction
+var ledgerUnsupportedQueryPrefixes = map[string]bool{
+	"amt":    true,
+	"code":   true,
+	"cur":    true,
+	"date":   true,
+	"date2":  true,
+	"depth":  true,
+	"expr":   true,
+	"inacct": true,
+	"not":    true,
+	"note":   true,
+	"real":   true,
+	"status": true,
+	"tag":    true,
+	"type":   true,
+}
+
+var ledgerAccountTypeNameMap = map[string]ledgerAccountType{
+	"assets":      ledgerAssetsAccountType,
+	"asset":       ledgerAssetsAccountType,
+	"liabilities": ledgerLiabilitiesAccountType,
+	"liability":   ledgerLiabilitiesAccountType,
+	"equity":      ledgerEquityAccountType,
+	"income":      ledgerIncomeAccountType,
+	"revenue":     ledgerIncomeAccountType,
+	"revenues":    ledgerIncomeAccountType,
+	"expenses":    ledgerExpensesAccountType,
+	"expense":     ledgerExpensesAccountType,
+}
+
+// the account type codes and names of the "type:" tag in hledger account directive
+var ledgerAccountTypeCodeMap = map[string]ledgerAccountType{
+	"a":          ledgerAssetsAccountType,
+	"asset":      ledgerAssetsAccountType,
+	"c":          ledgerAssetsAccountType,
+	"cash":       ledgerAssetsAccountType,
+	"l":          ledgerLiabilitiesAccountType,
+	"liability":  ledgerLiabilitiesAccountType,
+	"e":          ledgerEquityAccountType,
+	"equity":     ledgerEquityAccountType,
+	"v":          ledgerEquityAccountType,
+	"conversion": ledgerEquityAccountType,
+	"r":          ledgerIncomeAccountType,
+	"revenue":    ledgerIncomeAccountType,
+	"x":          ledgerExpensesAccountType,
+	"expense":    ledgerExpensesAccountType,
+}
+
+var ledgerCommoditySymbolCurrencyMap = map[string]string{
+	"$":  "USD",
+	"€":  "EUR",
+	"£":  "GBP",
+	"₹":  "INR",
+	"₽":  "RUB",
+	"₩":  "KRW",
+	"₺":  "TRY",
+	"₫":  "VND",
+	"₴":  "UAH",
+	"฿":  "THB",
+	"R$": "BRL",
+}
+
+// ledgerDataReader defines the structure of ledger journal data reader
+type ledgerDataReader struct {
+	allLines              []string
+	accountAliases        map[string]string
+	defaultYear           string
+	defaultCommodity      string
+	automatedTransactions []*ledgerAutomatedTransaction
+}
+
+// read returns the imported ledger journal data
+// Reference: https://ledger-cli.org/doc/ledger3.html#Journal-Format and https://hledger.org/hledger.html#journal
+func (r *ledgerDataReader) read(ctx core.Context) (*ledgerData, error) {
+	if len(r.allLines) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	data := &ledgerData{
+		Accounts:     make(map[string]*ledgerAccount),
+		Commodities:  make(map[string]string),
+		Transactions: make([]*ledgerTransactionEntry, 0),
+	}
+
+	var err error
+	var currentTransactionEntry *ledgerTransactionEntry
+	var currentPeriod *ledgerPeriod
+	var currentAutomatedTransaction *ledgerAutomatedTransaction
+	var currentAccount *ledgerAccount
+	var currentCommodity string
+	inCommentBlock := false
+
+	for i := 0; i < len(r.allLines); i++ {
+		line := strings.TrimRight(r.allLines[i], " \t\r")
+
+		if inCommentBlock {
+			if line == "end comment" || line == "end test" {
+				inCommentBlock = false
+			}
+
+			continue
+		}
+
+		if len(line) == 0 { // empty line ends the current transaction or directive
+			if err = r.completeCurrentEntry(ctx, data, currentTransactionEntry, currentPeriod, currentAutomatedTransaction); err != nil {
+				return nil, err
+			}
+
+			currentTransactionEntry = nil
+			currentPeriod = nil
+			currentAutomatedTransaction = nil
+			currentAccount = nil
+			currentCommodity = ""
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' { // original line has space prefix, maybe transaction posting or sub directive
+			content := strings.TrimSpace(line)
+
+			if len(content) < 1 {
+				continue
+			}
+
+			if currentAutomatedTransaction != nil {
+				if content[0] == ledgerCommentPrefix {
+					continue
+				}
+
+				posting, err := r.readAutomatedTransactionPostingLine(ctx, i, content)
+
+				if err != nil {
+					return nil, err
+				}
+
+				if posting != nil {
+					currentAutomatedTransaction.Postings = append(currentAutomatedTransaction.Postings, posting)
+				}
+			} else if currentTransactionEntry != nil {
+				if content[0] == ledgerCommentPrefix {
+					currentTransactionEntry.Tags = r.appendTags(currentTransactionEntry.Tags, r.getTagsFromComment(content[1:]))
+					continue
+				}
+
+				posting, tags, err := r.readTransactionPostingLine(ctx, i, content, data)
+
+				if err != nil {
+					return nil, err
+				}
+
+				if posting != nil {
+					currentTransactionEntry.Postings = append(currentTransactionEntry.Postings, posting)
+				}
+
+				currentTransactionEntry.Tags = r.appendTags(currentTransactionEntry.Tags, tags)
+			} else if currentAccount != nil {
+				if content[0] == ledgerCommentPrefix {
+					if accountType, exists := r.getAccountTypeFromComment(content[1:]); exists {
+						currentAccount.AccountType = accountType
+					}
+				}
+			} else if currentCommodity != "" {
+				directive, args := r.getDirectiveAndArguments(content)
+
+				if directive == "alias" && args != "" {
+					data.Commodities[currentCommodity] = args
+				}
+			}
+
+			continue
+		}
+
+		// original line has no space prefix, so the previous transaction or directive ends
+		if err = r.completeCurrentEntry(ctx, data, currentTransactionEntry, currentPeriod, currentAutomatedTransaction); err != nil {
+			return nil, err
+		}
+
+		currentTransactionEntry = nil
+		currentPeriod = nil
+		currentAutomatedTransaction = nil
+		currentAccount = nil
+		currentCommodity = ""
+
+		if strings.IndexByte(ledgerTopLevelCommentPrefixes, line[0]) >= 0 { // skip comment lines
+			continue
+		}
+
+		if '0' <= line[0] && line[0] <= '9' { // transaction
+			currentTransactionEntry, err = r.readTransactionLine(ctx, i, line)
+
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if line[0] == '=' { // automated transaction
+			currentAutomatedTransaction = r.readAutomatedTransactionLine(ctx, i, line)
+			continue
+		}
+
+		if line[0] == '~' { // periodic transaction
+			currentTransactionEntry, currentPeriod = r.readPeriodicTransactionLine(ctx, i, line)
+			continue
+		}
+
+		directive, args := r.getDirectiveAndArguments(line)
+
+		switch directive {
+		case "include", "!include":
+			log.Errorf(ctx, "[ledger_data_reader.read] cannot parse include directive line#%d \"%s\"", i, line)
+			return nil, errs.ErrLedgerFileNotSupportInclude
+		case "account", "!account":
+			currentAccount = r.readAccountDirective(ctx, i, args, data)
+		case "commodity":
+			currentCommodity = r.readCommodityDirective(args, data)
+		case "alias":
+			r.readAliasDirective(ctx, i, args)
+		case "end":
+			if args == "aliases" {
+				r.accountAliases = make(map[string]string)
+			}
+		case "D":
+			r.readDefaultCommodityDirective(ctx, i, args)
+		case "Y", "year":
+			r.defaultYear = args
+		case "apply":
+			if directive, args := r.getDirectiveAndArguments(args); directive == "year" {
+				r.defaultYear = args
+			}
+		case "comment", "test":
+			inCommentBlock = true
+		default: // skip price / payee / tag / other directives
+			continue
+		}
+	}
+
+	if err = r.completeCurrentEntry(ctx, data, currentTransactionEntry, currentPeriod, currentAutomatedTransaction); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *ledgerDataReader) completeCurrentEntry(ctx core.Context, data *ledgerData, transactionEntry *ledgerTransactionEntry, period *ledgerPeriod, automatedTransaction *ledgerAutomatedTransaction) error {
+	if automatedTransaction != nil {
+		if len(automatedTransaction.Postings) > 0 {
+			r.automatedTransactions = append(r.automatedTransactions, automatedTransaction)
+		}
+
+		return nil
+	}
+
+	if period != nil {
+		return r.completePeriodicTransaction(ctx, data, transactionEntry, period)
+	}
+
+	return r.completeTransaction(ctx, data, transactionEntry)
+}
+
+func (r *ledgerDataReader) completePeriodicTransaction(ctx core.Context, data *ledgerData, templateTransactionEntry *ledgerTransactionEntry, period *ledgerPeriod) error {
+	for i := 0; ; i++ {
+		date := period.StartDate.AddDate(0, period.IntervalMonths*i, period.IntervalDays*i)
+
+		if !date.Before(period.EndDate) {
+			break
+		}
+
+		if i >= ledgerPeriodicTransactionMaxOccurrences {
+			log.Errorf(ctx, "[ledger_data_reader.completePeriodicTransaction] cannot complete periodic transaction \"%s\", because there are too many occurrences", templateTransactionEntry.Description)
+			return errs.ErrInvalidLedgerFile
+		}
+
+		transactionEntry := &ledgerTransactionEntry{
+			Date:        date.Format(time.DateOnly) + " 00:00:00",
+			Status:      templateTransactionEntry.Status,
+			Code:        templateTransactionEntry.Code,
+			Payee:       templateTransactionEntry.Payee,
+			Description: templateTransactionEntry.Description,
+			Postings:    make([]*ledgerPosting, len(templateTransactionEntry.Postings)),
+			Tags:        templateTransactionEntry.Tags,
+		}
+
+		for j := 0; j < len(templateTransactionEntry.Postings); j++ {
+			posting := *templateTransactionEntry.Postings[j]
+			transactionEntry.Postings[j] = &posting
+		}
+
+		if err := r.completeTransaction(ctx, data, transactionEntry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ledgerDataReader) completeTransaction(ctx core.Context, data *ledgerData, transactionEntry *ledgerTransactionEntry) error {
+	if transactionEntry == nil {
+		return nil
+	}
+
+	var elidedPosting *ledgerPosting
+	elidedPostingIndex := -1
+	totalAmounts := make(map[string]*big.Rat)
+	allCommodities := make([]string, 0, 2)
+
+	for i := 0; i < len(transactionEntry.Postings); i++ {
+		posting := transactionEntry.Postings[i]
+
+		if posting.amount == nil {
+			if elidedPosting != nil {
+				log.Errorf(ctx, "[ledger_data_reader.completeTransaction] cannot complete transaction \"%s %s\", because more than one posting has no amount", transactionEntry.Date, transactionEntry.Description)
+				return errs.ErrInvalidLedgerFile
+			}
+
+			elidedPosting = posting
+			elidedPostingIndex = i
+			continue
+		}
+
+		cost, costCommodity := posting.getCost()
+
+		if _, exists := totalAmounts[costCommodity]; !exists {
+			totalAmounts[costCommodity] = new(big.Rat)
+			allCommodities = append(allCommodities, costCommodity)
+		}
+
+		totalAmounts[costCommodity].Add(totalAmounts[costCommodity], cost)
+	}
+
+	if elidedPosting != nil {
+		// the amount of the posting without amount is the negative sum of other postings,
+		// and it would be split into multiple postings if other postings have different commodities
+		newPostings := make([]*ledgerPosting, 0, len(allCommodities))
+
+		for i := 0; i < len(allCommodities); i++ {
+			commodity := allCommodities[i]
+
+			if totalAmounts[commodity].Sign() == 0 && len(allCommodities) > 1 {
+				continue
+			}
+
+			newPostings = append(newPostings, &ledgerPosting{
+				Account:   elidedPosting.Account,
+				Commodity: commodity,
+				amount:    new(big.Rat).Neg(totalAmounts[commodity]),
+			})
+		}
+
+		if len(newPostings) < 1 {
+			log.Errorf(ctx, "[ledger_data_reader.completeTransaction] cannot complete transaction \"%s %s\", because no posting has amount", transactionEntry.Date, transactionEntry.Description)
+			return errs.ErrInvalidLedgerFile
+		}
+
+		postings := make([]*ledgerPosting, 0, len(transactionEntry.Postings)+len(newPostings)-1)
+		postings = append(postings, transactionEntry.Postings[:elidedPostingIndex]...)
+		postings = append(postings, newPostings...)
+		postings = append(postings, transactionEntry.Postings[elidedPostingIndex+1:]...)
+		transactionEntry.Postings = postings
+	}
+
+	if err := r.applyAutomatedTransactions(ctx, data, transactionEntry); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(transactionEntry.Postings); i++ {
+		posting := transactionEntry.Postings[i]
+		posting.Amount = utils.FormatAmount(roundLedgerAmountToCents(posting.amount))
+	}
+
+	data.Transactions = append(data.Transactions, transactionEntry)
+
+	return nil
+}
+
+func (r *ledgerDataReader) applyAutomatedTransactions(ctx core.Context, data *ledgerData, transactionEntry *ledgerTransactionEntry) error {
+	if len(r.automatedTransactions) < 1 {
+		return nil
+	}
+
+	originalPostings := transactionEntry.Postings
+	totalAmounts := make(map[string]*big.Rat)
+
+	for i := 0; i < len(r.automatedTransactions); i++ {
+		automatedTransaction := r.automatedTransactions[i]
+
+		for j := 0; j < len(originalPostings); j++ {
+			matchedPosting := originalPostings[j]
+
+			if !automatedTransaction.isMatch(transactionEntry, matchedPosting) {
+				continue
+			}
+
+			for k := 0; k < len(automatedTransaction.Postings); k++ {
+				automatedPosting := automatedTransaction.Postings[k]
+				posting := &ledgerPosting{
+					Account:   automatedPosting.Account,
+					Commodity: automatedPosting.Commodity,
+				}
+
+				if automatedPosting.multiplier != nil {
+					posting.Commodity = matchedPosting.Commodity
+					posting.amount = new(big.Rat).Mul(matchedPosting.amount, automatedPosting.multiplier)
+				} else {
+					posting.amount = new(big.Rat).Set(automatedPosting.amount)
+				}
+
+				if _, exists := totalAmounts[posting.Commodity]; !exists {
+					totalAmounts[posting.Commodity] = new(big.Rat)
+				}
+
+				totalAmounts[posting.Commodity].Add(totalAmounts[posting.Commodity], posting.amount)
+				transactionEntry.Postings = append(transactionEntry.Postings, posting)
+				r.getOrCreateAccount(data, posting.Account)
+			}
+		}
+	}
+
+	// the real postings added by automated transactions must be balanced, otherwise the transaction would be unbalanced
+	for commodity, totalAmount := range totalAmounts {
+		if totalAmount.Sign() != 0 {
+			log.Errorf(ctx, "[ledger_data_reader.applyAutomatedTransactions] cannot apply automated transactions to transaction \"%s %s\", because the added postings of commodity \"%s\" are not balanced", transactionEntry.Date, transactionEntry.Description, commodity)
+			return errs.ErrInvalidLedgerFile
+		}
+	}
+
+	return nil
+}
+
+func (r *ledgerDataReader) readAutomatedTransactionLine(ctx core.Context, lineIndex int, line string) *ledgerAutomatedTransaction {
+	// = QUERY [; COMMENT]
+	content, _ := r.splitComment(line[1:])
+	query := strings.TrimSpace(content)
+	automatedTransaction := &ledgerAutomatedTransaction{
+		Query:    query,
+		Postings: make([]*ledgerAutomatedPosting, 0, 2),
+	}
+
+	terms := strings.Fields(query)
+
+	for i := 0; i < len(terms); i++ {
+		term := terms[i]
+
+		if term == "or" {
+			continue
+		}
+
+		isDescriptionPattern := false
+
+		if index := strings.IndexByte(term, ':'); index > 0 {
+			prefix := strings.ToLower(term[0:index])
+
+			if prefix == "acct" || prefix == "account" {
+				term = term[index+1:]
+			} else if prefix == "desc" || prefix == "payee" {
+				term = term[index+1:]
+				isDescriptionPattern = true
+			} else if ledgerUnsupportedQueryPrefixes[prefix] {
+				term = ""
+			}
+		} else if term == "and" || term == "not" || term == "expr" || strings.ContainsAny(term, "=!&|()@%") {
+			term = ""
+		}
+
+		if len(term) > 1 && term[0] == '/' && term[len(term)-1] == '/' {
+			term = term[1 : len(term)-1]
+		}
+
+		pattern, err := regexp.Compile("(?i)" + term)
+
+		if term == "" || err != nil {
+			log.Warnf(ctx, "[ledger_data_reader.readAutomatedTransactionLine] skip automated transaction line#%d \"%s\", because the query is not supported", lineIndex, line)
+			return nil
+		}
+
+		if isDescriptionPattern {
+			automatedTransaction.descriptionPatterns = append(automatedTransaction.descriptionPatterns, pattern)
+		} else {
+			automatedTransaction.accountPatterns = append(automatedTransaction.accountPatterns, pattern)
+		}
+	}
+
+	if len(automatedTransaction.accountPatterns) < 1 && len(automatedTransaction.descriptionPatterns) < 1 {
+		log.Warnf(ctx, "[ledger_data_reader.readAutomatedTransactionLine] skip automated transaction line#%d \"%s\", because the query is empty", lineIndex, line)
+		return nil
+	}
+
+	return automatedTransaction
+}
+
+func (r *ledgerDataReader) readAutomatedTransactionPostingLine(ctx core.Context, lineIndex int, content string) (*ledgerAutomatedPosting, error) {
+	// [*|!] ACCOUNT  [*]AMOUNT [; COMMENT], the amount without commodity is the multiplier of the matched posting amount
+	content, _ = r.splitComment(content)
+	content = strings.TrimSpace(content)
+
+	if len(content) > 0 && (content[0] == '*' || content[0] == '!') {
+		content = strings.TrimSpace(content[1:])
+	}
+
+	accountName := content
+	amountText := ""
+
+	if index := r.getAccountNameEndIndex(content); index >= 0 {
+		accountName = content[0:index]
+		amountText = strings.TrimSpace(content[index:])
+	}
+
+	if accountName == "" {
+		log.Errorf(ctx, "[ledger_data_reader.readAutomatedTransactionPostingLine] cannot parse automated transaction posting line#%d \"%s\", because missing account name", lineIndex, content)
+		return nil, errs.ErrMissingAccountData
+	}
+
+	if (accountName[0] == '(' && accountName[len(accountName)-1] == ')') || (accountName[0] == '[' && accountName[len(accountName)-1] == ']') { // skip virtual postings
+		log.Warnf(ctx, "[ledger_data_reader.readAutomatedTransactionPostingLine] skip virtual posting line#%d \"%s\"", lineIndex, content)
+		return nil, nil
+	}
+
+	if aliasAccountName, exists := r.accountAliases[accountName]; exists {
+		accountName = aliasAccountName
+	}
+
+	posting := &ledgerAutomatedPosting{
+		Account: accountName,
+	}
+
+	if multiplierText := strings.TrimPrefix(amountText, "*"); multiplierText != "" {
+		negative := multiplierText[0] == '-'
+		multiplier, err := parseLedgerNumber(strings.TrimLeft(multiplierText, "+-"))
+
+		if err == nil {
+			if negative {
+				multiplier.Neg(multiplier)
+			}
+
+			posting.multiplier = multiplier
+			return posting, nil
+		}
+	}
+
+	amount, commodity, err := r.parseAmount(amountText)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_data_reader.readAutomatedTransactionPostingLine] cannot parse amount in automated transaction posting line#%d \"%s\"", lineIndex, content)
+		return nil, errs.ErrAmountInvalid
+	}
+
+	posting.Commodity = commodity
+	posting.amount = amount
+
+	return posting, nil
+}
+
+func (r *ledgerDataReader) readPeriodicTransactionLine(ctx core.Context, lineIndex int, line string) (*ledgerTransactionEntry, *ledgerPeriod) {
+	// ~ PERIOD_EXPRESSION  [DESCRIPTION] [; COMMENT]
+	content, comment := r.splitComment(line[1:])
+	content = strings.TrimSpace(content)
+	periodText := content
+	description := ""
+
+	if index := strings.Index(content, "  "); index >= 0 {
+		periodText = content[0:index]
+		description = strings.TrimSpace(content[index:])
+	}
+
+	period, err := r.parsePeriod(periodText)
+
+	if err != nil {
+		// the periodic transactions without both start date and end date are budget or forecast rules, which cannot be imported as transactions
+		log.Warnf(ctx, "[ledger_data_reader.readPeriodicTransactionLine] skip periodic transaction line#%d \"%s\", because the period is not supported or not bounded", lineIndex, line)
+		return nil, nil
+	}
+
+	transactionEntry := &ledgerTransactionEntry{
+		Postings: make([]*ledgerPosting, 0, 2),
+		Tags:     r.getTagsFromComment(comment),
+	}
+
+	if index := strings.Index(description, ledgerPayeeNoteSeparator); index >= 0 {
+		transactionEntry.Payee = strings.TrimSpace(description[0:index])
+		transactionEntry.Description = strings.TrimSpace(description[index+1:])
+	} else {
+		transactionEntry.Description = description
+	}
+
+	return transactionEntry, period
+}
+
+func (r *ledgerDataReader) readTransactionLine(ctx core.Context, lineIndex int, line string) (*ledgerTransactionEntry, error) {
+	// DATE[=DATE2] [*|!] [(CODE)] DESCRIPTION [; COMMENT]
+	content, comment := r.splitComment(line)
+	content = strings.TrimSpace(content)
+
+	dateText := content
+	remainContent := ""
+
+	if index := strings.IndexAny(content, " \t"); index >= 0 {
+		dateText = content[0:index]
+		remainContent = strings.TrimSpace(content[index+1:])
+	}
+
+	if index := strings.IndexByte(dateText, '='); index >= 0 { // only use the primary date
+		dateText = dateText[0:index]
+	}
+
+	transactionTime, err := r.parseDate(dateText)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_data_reader.readTransactionLine] cannot parse date in transaction line#%d \"%s\"", lineIndex, line)
+		return nil, errs.ErrTransactionTimeInvalid
+	}
+
+	transactionEntry := &ledgerTransactionEntry{
+		Date:     transactionTime,
+		Postings: make([]*ledgerPosting, 0, 2),
+		Tags:     r.getTagsFromComment(comment),
+	}
+
+	if len(remainContent) > 0 && (remainContent[0] == '*' || remainContent[0] == '!') {
+		transactionEntry.Status = ledgerTransactionStatus(remainContent[0:1])
+		remainContent = strings.TrimSpace(remainContent[1:])
+	}
+
+	if len(remainContent) > 0 && remainContent[0] == '(' {
+		if index := strings.IndexByte(remainContent, ')'); index > 0 {
+			transactionEntry.Code = remainContent[1:index]
+			remainContent = strings.TrimSpace(remainContent[index+1:])
+		}
+	}
+
+	// hledger supports "PAYEE | NOTE" format in description
+	if index := strings.Index(remainContent, ledgerPayeeNoteSeparator); index >= 0 {
+		transactionEntry.Payee = strings.TrimSpace(remainContent[0:index])
+		transactionEntry.Description = strings.TrimSpace(remainContent[index+1:])
+	} else {
+		transactionEntry.Description = remainContent
+	}
+
+	return transactionEntry, nil
+}
+
+func (r *ledgerDataReader) readTransactionPostingLine(ctx core.Context, lineIndex int, content string, data *ledgerData) (*ledgerPosting, []string, error) {
+	// [*|!] ACCOUNT  [AMOUNT] [@ PRICE | @@ TOTAL PRICE] [= BALANCE ASSERTION] [; COMMENT]
+	content, comment := r.splitComment(content)
+	tags := r.getTagsFromComment(comment)
+	content = strings.TrimSpace(content)
+
+	if len(content) > 0 && (content[0] == '*' || content[0] == '!') {
+		content = strings.TrimSpace(content[1:])
+	}
+
+	accountName := content
+	amountText := ""
+
+	// account name and amount are separated by two or more spaces or tab
+	if index := r.getAccountNameEndIndex(content); index >= 0 {
+		accountName = content[0:index]
+		amountText = strings.TrimSpace(content[index:])
+	}
+
+	if accountName == "" {
+		log.Errorf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse transaction posting line#%d \"%s\", because missing account name", lineIndex, content)
+		return nil, nil, errs.ErrMissingAccountData
+	}
+
+	if (accountName[0] == '(' && accountName[len(accountName)-1] == ')') || (accountName[0] == '[' && accountName[len(accountName)-1] == ']') { // skip virtual postings
+		log.Warnf(ctx, "[ledger_data_reader.readTransactionPostingLine] skip virtual posting line#%d \"%s\"", lineIndex, content)
+		return nil, tags, nil
+	}
+
+	if aliasAccountName, exists := r.accountAliases[accountName]; exists {
+		accountName = aliasAccountName
+	}
+
+	if index := strings.Index(amountText, ledgerBalanceAssertionPrefix); index >= 0 {
+		amountText = strings.TrimSpace(amountText[0:index])
+
+		if amountText == "" {
+			log.Errorf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse transaction posting line#%d \"%s\", because balance assignment is not supported", lineIndex, content)
+			return nil, nil, errs.ErrInvalidLedgerFile
+		}
+	}
+
+	posting := &ledgerPosting{
+		Account: accountName,
+	}
+
+	priceText := ""
+	totalPriceText := ""
+
+	if index := strings.Index(amountText, ledgerTotalPricePrefix); index >= 0 {
+		totalPriceText = strings.TrimSpace(amountText[index+len(ledgerTotalPricePrefix):])
+		amountText = strings.TrimSpace(amountText[0:index])
+	} else if index := strings.Index(amountText, ledgerPricePrefix); index >= 0 {
+		priceText = strings.TrimSpace(amountText[index+len(ledgerPricePrefix):])
+		amountText = strings.TrimSpace(amountText[0:index])
+	}
+
+	// lot annotations, {{TOTAL COST}} or {UNIT COST} is used as price when no price specified
+	if index := strings.Index(amountText, "{{"); index >= 0 {
+		if endIndex := strings.Index(amountText, "}}"); endIndex > index && totalPriceText == "" && priceText == "" {
+			totalPriceText = strings.TrimSpace(amountText[index+2 : endIndex])
+		}
+
+		amountText = strings.TrimSpace(amountText[0:index])
+	} else if index := strings.IndexByte(amountText, '{'); index >= 0 {
+		if endIndex := strings.IndexByte(amountText, '}'); endIndex > index && totalPriceText == "" && priceText == "" {
+			priceText = strings.TrimSpace(amountText[index+1 : endIndex])
+		}
+
+		amountText = strings.TrimSpace(amountText[0:index])
+	}
+
+	if index := strings.IndexByte(amountText, '['); index > 0 { // lot date
+		amountText = strings.TrimSpace(amountText[0:index])
+	}
+
+	if amountText != "" {
+		amount, commodity, err := r.parseAmount(amountText)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse amount in transaction posting line#%d \"%s\", because %s", lineIndex, content, err.Error())
+			return nil, nil, errs.ErrAmountInvalid
+		}
+
+		posting.OriginalAmount = amountText
+		posting.Commodity = commodity
+		posting.amount = amount
+	}
+
+	if totalPriceText != "" {
+		totalPrice, commodity, err := r.parseAmount(totalPriceText)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse total price in transaction posting line#%d \"%s\", because %s", lineIndex, content, err.Error())
+			return nil, nil, errs.ErrAmountInvalid
+		}
+
+		posting.TotalPrice = totalPrice.FloatString(2)
+		posting.TotalPriceCommodity = commodity
+		posting.totalPrice = totalPrice.Abs(totalPrice)
+	} else if priceText != "" {
+		price, commodity, err := r.parseAmount(priceText)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_data_reader.readTransactionPostingLine] cannot parse price in transaction posting line#%d \"%s\", because %s", lineIndex, content, err.Error())
+			return nil, nil, errs.ErrAmountInvalid
+		}
+
+		posting.Price = price.FloatString(6)
+		posting.PriceCommodity = commodity
+		posting.price = price
+	}
+
+	r.getOrCreateAccount(data, accountName)
+
+	return posting, tags, nil
+}
+
+func (r *ledgerDataReader) readAccountDirective(ctx core.Context, lineIndex int, args string, data *ledgerData) *ledgerAccount {
+	// account ACCOUNT [; type: TYPE]
+	content, comment := r.splitComment(args)
+	accountName := strings.TrimSpace(content)
+
+	if index := r.getAccountNameEndIndex(accountName); index >= 0 {
+		accountName = accountName[0:index]
+	}
+
+	if accountName == "" {
+		log.Warnf(ctx, "[ledger_data_reader.readAccountDirective] cannot parse account directive line#%d, because missing account name", lineIndex)
+		return nil
+	}
+
+	account := r.getOrCreateAccount(data, accountName)
+
+	if accountType, exists := r.getAccountTypeFromComment(comment); exists {
+		account.AccountType = accountType
+	}
+
+	return account
+}
+
+func (r *ledgerDataReader) readCommodityDirective(args string, data *ledgerData) string {
+	// commodity SYMBOL or commodity SAMPLE_AMOUNT
+	content, _ := r.splitComment(args)
+	content = strings.TrimSpace(content)
+
+	if content == "" {
+		return ""
+	}
+
+	commodity := content
+
+	if _, sampleCommodity, err := r.parseAmount(content); err == nil && sampleCommodity != "" {
+		commodity = sampleCommodity
+	}
+
+	if _, exists := data.Commodities[commodity]; !exists {
+		data.Commodities[commodity] = ""
+	}
+
+	return commodity
+}
+
+func (r *ledgerDataReader) readAliasDirective(ctx core.Context, lineIndex int, args string) {
+	// alias ALIAS=ACCOUNT
+	index := strings.IndexByte(args, '=')
+
+	if index <= 0 {
+		log.Warnf(ctx, "[ledger_data_reader.readAliasDirective] cannot parse alias directive line#%d \"%s\"", lineIndex, args)
+		return
+	}
+
+	alias := strings.TrimSpace(args[0:index])
+	accountName := strings.TrimSpace(args[index+1:])
+
+	if alias == "" || accountName == "" {
+		log.Warnf(ctx, "[ledger_data_reader.readAliasDirective] cannot parse alias directive line#%d \"%s\"", lineIndex, args)
+		return
+	}
+
+	r.accountAliases[alias] = accountName
+}
+
+func (r *ledgerDataReader) readDefaultCommodityDirective(ctx core.Context, lineIndex int, args string) {
+	// D SAMPLE_AMOUNT
+	_, commodity, err := r.parseAmount(args)
+
+	if err != nil || commodity == "" {
+		log.Warnf(ctx, "[ledger_data_reader.readDefaultCommodityDirective] cannot parse default commodity directive line#%d \"%s\"", lineIndex, args)
+		return
+	}
+
+	r.defaultCommodity = commodity
+}
+
+func (r *ledgerDataReader) getOrCreateAccount(data *ledgerData, accountName string) *ledgerAccount {
+	account, exists := data.Accounts[accountName]
+
+	if exists {
+		return account
+	}
+
+	account = &ledgerAccount{
+		Name:        accountName,
+		AccountType: ledgerUnknownAccountType,
+	}
+
+	accountNameItems := strings.Split(accountName, ledgerAccountNameItemsSeparator)
+
+	if accountType, exists := ledgerAccountTypeNameMap[strings.ToLower(accountNameItems[0])]; exists {
+		account.AccountType = accountType
+	}
+
+	data.Accounts[accountName] = account
+	return account
+}
+
+func (r *ledgerDataReader) getAccountTypeFromComment(comment string) (ledgerAccountType, bool) {
+	items := strings.Split(comment, ",")
+
+	for i := 0; i < len(items); i++ {
+		item := strings.TrimSpace(items[i])
+		index := strings.IndexByte(item, ledgerTagSeparator)
+
+		if index <= 0 || strings.ToLower(item[0:index]) != "type" {
+			continue
+		}
+
+		accountType, exists := ledgerAccountTypeCodeMap[strings.ToLower(strings.TrimSpace(item[index+1:]))]
+
+		if exists {
+			return accountType, true
+		}
+	}
+
+	return ledgerUnknownAccountType, false
+}
+
+func (r *ledgerDataReader) getTagsFromComment(comment string) []string {
+	tags := make([]string, 0)
+
+	// ledger style tags, e.g. ":tag1:tag2:"
+	fields := strings.Fields(comment)
+
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+
+		if len(field) < 3 || field[0] != ledgerTagSeparator || field[len(field)-1] != ledgerTagSeparator {
+			continue
+		}
+
+		for _, tag := range strings.Split(field[1:len(field)-1], string(ledgerTagSeparator)) {
+			if tag != "" {
+				tags = r.appendTags(tags, []string{tag})
+			}
+		}
+	}
+
+	// hledger style tags without value, e.g. "tag1:, tag2:"
+	items := strings.Split(comment, ",")
+
+	for i := 0; i < len(items); i++ {
+		item := strings.TrimSpace(items[i])
+
+		if len(item) < 2 || item[0] == ledgerTagSeparator || item[len(item)-1] != ledgerTagSeparator {
+			continue
+		}
+
+		tag := item[0 : len(item)-1]
+
+		if !strings.ContainsAny(tag, " \t:") {
+			tags = r.appendTags(tags, []string{tag})
+		}
+	}
+
+	return tags
+}
+
+func (r *ledgerDataReader) appendTags(tags []string, newTags []string) []string {
+	for i := 0; i < len(newTags); i++ {
+		exists := false
+
+		for j := 0; j < len(tags); j++ {
+			if tags[j] == newTags[i] {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			tags = append(tags, newTags[i])
+		}
+	}
+
+	return tags
+}
+
+func (r *ledgerDataReader) parseDate(dateText string) (string, error) {
+	dateText = strings.NewReplacer("/", "-", ".", "-").Replace(dateText)
+	dateItems := strings.Split(dateText, "-")
+
+	var year, month, day string
+
+	if len(dateItems) == 3 {
+		year, month, day = dateItems[0], dateItems[1], dateItems[2]
+	} else if len(dateItems) == 2 && r.defaultYear != "" { // date without year uses the year specified in "Y" or "year" directive
+		year, month, day = r.defaultYear, dateItems[0], dateItems[1]
+	} else {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	transactionTime, err := utils.FormatYearMonthDayToLongDateTime(year, month, day)
+
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = utils.ParseFromLongDateTime(transactionTime, 0); err != nil {
+		return "", err
+	}
+
+	return transactionTime, nil
+}
+
+func (r *ledgerDataReader) parsePeriod(periodText string) (*ledgerPeriod, error) {
+	// supports "INTERVAL from DATE to DATE", INTERVAL can be "daily", "weekly", "monthly", "every N days", etc.
+	words := strings.Fields(strings.ToLower(periodText))
+	period := &ledgerPeriod{}
+	hasStartDate := false
+	hasEndDate := false
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+
+		switch word {
+		case "from", "since", "to", "until":
+			if i+1 >= len(words) {
+				return nil, errs.ErrInvalidLedgerFile
+			}
+
+			date, err := parseLedgerPeriodDate(words[i+1])
+
+			if err != nil {
+				return nil, err
+			}
+
+			if word == "from" || word == "since" {
+				period.StartDate = date
+				hasStartDate = true
+			} else {
+				period.EndDate = date
+				hasEndDate = true
+			}
+
+			i++
+		case "every":
+			count := 1
+
+			if i+1 < len(words) {
+				if value, err := utils.StringToInt(words[i+1]); err == nil && value > 0 {
+					count = value
+					i++
+				}
+			}
+
+			if i+1 >= len(words) {
+				return nil, errs.ErrInvalidLedgerFile
+			}
+
+			intervalUnit, exists := ledgerPeriodIntervalUnitMap[strings.TrimSuffix(words[i+1], "s")]
+
+			if !exists {
+				return nil, errs.ErrInvalidLedgerFile
+			}
+
+			period.IntervalDays = intervalUnit[0] * count
+			period.IntervalMonths = intervalUnit[1] * count
+			i++
+		default:
+			interval, exists := ledgerPeriodIntervalMap[word]
+
+			if !exists {
+				return nil, errs.ErrInvalidLedgerFile
+			}
+
+			period.IntervalDays = interval[0]
+			period.IntervalMonths = interval[1]
+		}
+	}
+
+	if !hasStartDate || !hasEndDate || (period.IntervalDays <= 0 && period.IntervalMonths <= 0) {
+		return nil, errs.ErrInvalidLedgerFile
+	}
+
+	return period, nil
+}
+
+func (r *ledgerDataReader) parseAmount(amountText string) (*big.Rat, string, error) {
+	// supports "$1,234.56", "-$10", "$-10", "10.00 USD", "USD 10.00" and "10 "ABC 1"" formats
+	amountText = strings.TrimSpace(amountText)
+
+	if amountText == "" || amountText[0] == '(' { // value expression is not supported
+		return nil, "", errs.ErrAmountInvalid
+	}
+
+	negative := false
+
+	if amountText[0] == '-' || amountText[0] == '+' {
+		negative = amountText[0] == '-'
+		amountText = strings.TrimSpace(amountText[1:])
+	}
+
+	if amountText == "" {
+		return nil, "", errs.ErrAmountInvalid
+	}
+
+	commodity := ""
+	numberText := ""
+
+	if amountText[0] == '"' { // quoted commodity before number
+		index := strings.IndexByte(amountText[1:], '"')
+
+		if index < 0 {
+			return nil, "", errs.ErrAmountInvalid
+		}
+
+		commodity = amountText[1 : index+1]
+		numberText = strings.TrimSpace(amountText[index+2:])
+	} else if !isLedgerNumberCharacter(rune(amountText[0])) { // commodity before number
+		index := strings.IndexFunc(amountText, func(c rune) bool {
+			return unicode.IsDigit(c) || c == '-' || c == '+' || c == ' ' || c == '.'
+		})
+
+		if index < 0 {
+			return nil, "", errs.ErrAmountInvalid
+		}
+
+		commodity = amountText[0:index]
+		numberText = strings.TrimSpace(amountText[index:])
+	} else { // commodity after number
+		index := strings.IndexFunc(amountText, func(c rune) bool {
+			return !isLedgerNumberCharacter(c)
+		})
+
+		if index < 0 {
+			numberText = amountText
+		} else {
+			numberText = amountText[0:index]
+			commodity = strings.Trim(strings.TrimSpace(amountText[index:]), "\"")
+		}
+	}
+
+	if len(numberText) > 0 && (numberText[0] == '-' || numberText[0] == '+') {
+		if numberText[0] == '-' {
+			negative = !negative
+		}
+
+		numberText = strings.TrimSpace(numberText[1:])
+	}
+
+	amount, err := parseLedgerNumber(numberText)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if negative {
+		amount.Neg(amount)
+	}
+
+	if commodity == "" {
+		commodity = r.defaultCommodity
+	}
+
+	return amount, commodity, nil
+}
+
+func (r *ledgerDataReader) splitComment(content string) (string, string) {
+	index := strings.IndexByte(content, ledgerCommentPrefix)
+
+	if index < 0 {
+		return content, ""
+	}
+
+	return content[0:index], content[index+1:]
+}
+
+func (r *ledgerDataReader) getAccountNameEndIndex(content string) int {
+	tabIndex := strings.IndexByte(content, '\t')
+	spacesIndex := strings.Index(content, "  ")
+
+	if tabIndex < 0 {
+		return spacesIndex
+	} else if spacesIndex < 0 {
+		return tabIndex
+	} else if tabIndex < spacesIndex {
+		return tabIndex
+	}
+
+	return spacesIndex
+}
+
+func (r *ledgerDataReader) getDirectiveAndArguments(line string) (string, string) {
+	index := strings.IndexAny(line, " \t")
+
+	if index < 0 {
+		return line, ""
+	}
+
+	return line[0:index], strings.TrimSpace(line[index+1:])
+}
+
+func (p *ledgerPosting) getCost() (*big.Rat, string) {
+	if p.totalPrice != nil {
+		cost := new(big.Rat).Set(p.totalPrice)
+
+		if p.amount.Sign() < 0 {
+			cost.Neg(cost)
+		}
+
+		return cost, p.TotalPriceCommodity
+	} else if p.price != nil {
+		return new(big.Rat).Mul(p.amount, p.price), p.PriceCommodity
+	}
+
+	return p.amount, p.Commodity
+}
+
+func parseLedgerPeriodDate(dateText string) (time.Time, error) {
+	// supports "YYYY-MM-DD", "YYYY-MM" and "YYYY" formats, the separator can also be "/" or "."
+	dateItems := strings.Split(strings.NewReplacer("/", "-", ".", "-").Replace(dateText), "-")
+	dateValues := []int{0, 1, 1}
+
+	if len(dateItems) > len(dateValues) {
+		return time.Time{}, errs.ErrInvalidLedgerFile
+	}
+
+	for i := 0; i < len(dateItems); i++ {
+		value, err := utils.StringToInt(dateItems[i])
+
+		if err != nil {
+			return time.Time{}, errs.ErrInvalidLedgerFile
+		}
+
+		dateValues[i] = value
+	}
+
+	date := time.Date(dateValues[0], time.Month(dateValues[1]), dateValues[2], 0, 0, 0, 0, time.UTC)
+
+	if date.Year() != dateValues[0] || int(date.Month()) != dateValues[1] || date.Day() != dateValues[2] {
+		return time.Time{}, errs.ErrInvalidLedgerFile
+	}
+
+	return date, nil
+}
+
+func isLedgerNumberCharacter(c rune) bool {
+	return unicode.IsDigit(c) || c == '.' || c == ',' || c == '\''
+}
+
+func parseLedgerNumber(numberText string) (*big.Rat, error) {
+	numberText = strings.ReplaceAll(numberText, "'", "")
+
+	if numberText == "" {
+		return nil, errs.ErrAmountInvalid
+	}
+
+	lastDotIndex := strings.LastIndexByte(numberText, '.')
+	lastCommaIndex := strings.LastIndexByte(numberText, ',')
+
+	if lastDotIndex >= 0 && lastCommaIndex >= 0 { // the last one is the decimal mark
+		if lastDotIndex > lastCommaIndex {
+			numberText = strings.ReplaceAll(numberText, ",", "")
+		} else {
+			numberText = strings.ReplaceAll(numberText, ".", "")
+			numberText = strings.Replace(numberText, ",", ".", 1)
+		}
+	} else if lastCommaIndex >= 0 { // comma is the decimal mark only if it appears once and not followed by three digits
+		if strings.Count(numberText, ",") == 1 && len(numberText)-lastCommaIndex-1 != 3 {
+			numberText = strings.Replace(numberText, ",", ".", 1)
+		} else {
+			numberText = strings.ReplaceAll(numberText, ",", "")
+		}
+	} else if strings.Count(numberText, ".") > 1 { // dot is the digit group separator if it appears more than once
+		numberText = strings.ReplaceAll(numberText, ".", "")
+	}
+
+	for i := 0; i < len(numberText); i++ {
+		if !('0' <= numberText[i] && numberText[i] <= '9') && numberText[i] != '.' {
+			return nil, errs.ErrAmountInvalid
+		}
+	}
+
+	amount, ok := new(big.Rat).SetString(numberText)
+
+	if !ok {
+		return nil, errs.ErrAmountInvalid
+	}
+
+	return amount, nil
+}
+
+func roundLedgerAmountToCents(amount *big.Rat) int64 {
+	numerator := new(big.Int).Mul(amount.Num(), big.NewInt(100))
+	quotient, remainder := new(big.Int).QuoRem(numerator, amount.Denom(), new(big.Int))
+
+	// round half away from zero
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(amount.Denom()) >= 0 {
+		if amount.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient.Int64()
+}
+
+func createNewLedgerDataReader(ctx core.Context, data []byte) (*ledgerDataReader, error) {
+	allLines, err := readLedgerFileLines(ctx, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerDataReader{
+		allLines:       allLines,
+		accountAliases: make(map[string]string),
+	}, nil
+}
+
+// createNewLedgerDataReaderFromZip returns a ledger journal data reader of the main ledger file in the zip file, and the included files are resolved relative to the including file in the zip file
+func createNewLedgerDataReaderFromZip(ctx core.Context, data []byte) (*ledgerDataReader, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_data_reader.createNewLedgerDataReaderFromZip] cannot open zip file, because %s", err.Error())
+		return nil, errs.ErrInvalidZipFile
+	}
+
+	allFiles := make(map[string][]byte)
+	allLedgerFileNames := make([]string, 0)
+	totalUncompressedSize := uint64(0)
+
+	for i := 0; i < len(zipReader.File); i++ {
+		file := zipReader.File[i]
+		fileName := path.Clean(file.Name)
+
+		if file.FileInfo().IsDir() || strings.HasPrefix(fileName, "__MACOSX/") || strings.HasPrefix(path.Base(fileName), ".") {
+			continue
+		}
+
+		totalUncompressedSize += file.UncompressedSize64
+
+		if totalUncompressedSize > ledgerZipFileMaxUncompressedSize {
+			log.Errorf(ctx, "[ledger_data_reader.createNewLedgerDataReaderFromZip] the uncompressed size of zip file exceeds the limit")
+			return nil, errs.ErrInvalidZipFile
+		}
+
+		fileData, err := readLedgerZipFile(file)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_data_reader.createNewLedgerDataReaderFromZip] cannot read file \"%s\" in zip file, because %s", file.Name, err.Error())
+			return nil, errs.ErrInvalidZipFile
+		}
+
+		allFiles[fileName] = fileData
+
+		if ledgerFileExtensions[strings.ToLower(path.Ext(fileName))] {
+			allLedgerFileNames = append(allLedgerFileNames, fileName)
+		}
+	}
+
+	reader := &ledgerDataReader{
+		accountAliases: make(map[string]string),
+	}
+
+	mainFileName, err := reader.getMainFileName(ctx, allLedgerFileNames, allFiles)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reader.allLines, err = reader.readFileLinesWithIncludedFiles(ctx, mainFileName, allFiles, make(map[string]bool))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+// getMainFileName returns the name of main ledger file, which is the only ledger file not included by other files in the top level directory
+func (r *ledgerDataReader) getMainFileName(ctx core.Context, allLedgerFileNames []string, allFiles map[string][]byte) (string, error) {
+	includedFileNames := make(map[string]bool)
+
+	for i := 0; i < len(allLedgerFileNames); i++ {
+		fileName := allLedgerFileNames[i]
+		fileLines, err := readLedgerFileLines(ctx, allFiles[fileName])
+
+		if err != nil {
+			return "", err
+		}
+
+		for j := 0; j < len(fileLines); j++ {
+			directive, args := r.getDirectiveAndArguments(strings.TrimRight(fileLines[j], " \t\r"))
+
+			if directive != "include" && directive != "!include" {
+				continue
+			}
+
+			if names, err := getLedgerIncludedFileNames(ctx, fileName, strings.Trim(args, "\""), allFiles); err == nil {
+				for k := 0; k < len(names); k++ {
+					includedFileNames[names[k]] = true
+				}
+			}
+		}
+	}
+
+	mainFileNames := make([]string, 0, 1)
+	mainFileDepth := -1
+
+	for i := 0; i < len(allLedgerFileNames); i++ {
+		fileName := allLedgerFileNames[i]
+
+		if includedFileNames[fileName] {
+			continue
+		}
+
+		depth := strings.Count(fileName, "/")
+
+		if mainFileDepth < 0 || depth < mainFileDepth {
+			mainFileDepth = depth
+			mainFileNames = []string{fileName}
+		} else if depth == mainFileDepth {
+			mainFileNames = append(mainFileNames, fileName)
+		}
+	}
+
+	if len(mainFileNames) != 1 {
+		log.Errorf(ctx, "[ledger_data_reader.getMainFileName] cannot find main ledger file, because there are %d ledger files not included by other files in the top level directory", len(mainFileNames))
+		return "", errs.ErrLedgerMainFileNotFound
+	}
+
+	return mainFileNames[0], nil
+}
+
+func (r *ledgerDataReader) readFileLinesWithIncludedFiles(ctx core.Context, fileName string, allFiles map[string][]byte, includingFileNames map[string]bool) ([]string, error) {
+	fileLines, err := readLedgerFileLines(ctx, allFiles[fileName])
+
+	if err != nil {
+		return nil, err
+	}
+
+	includingFileNames[fileName] = true
+	defer delete(includingFileNames, fileName)
+
+	allLines := make([]string, 0, len(fileLines))
+	inCommentBlock := false
+
+	for i := 0; i < len(fileLines); i++ {
+		line := strings.TrimRight(fileLines[i], " \t\r")
+		directive, args := "", ""
+
+		if len(line) > 0 && line[0] != ' ' && line[0] != '\t' {
+			directive, args = r.getDirectiveAndArguments(line)
+		}
+
+		if inCommentBlock {
+			inCommentBlock = line != "end comment" && line != "end test"
+		} else if directive == "comment" || directive == "test" {
+			inCommentBlock = true
+		}
+
+		if inCommentBlock || (directive != "include" && directive != "!include") {
+			allLines = append(allLines, fileLines[i])
+			continue
+		}
+
+		includedFileNames, err := getLedgerIncludedFileNames(ctx, fileName, strings.Trim(args, "\""), allFiles)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(includedFileNames); j++ {
+			includedFileName := includedFileNames[j]
+
+			if includingFileNames[includedFileName] {
+				log.Errorf(ctx, "[ledger_data_reader.readFileLinesWithIncludedFiles] cannot include file \"%s\" in file \"%s\", because it is circular included", includedFileName, fileName)
+				return nil, errs.ErrInvalidLedgerFile
+			}
+
+			includedLines, err := r.readFileLinesWithIncludedFiles(ctx, includedFileName, allFiles, includingFileNames)
+
+			if err != nil {
+				return nil, err
+			}
+
+			// the empty lines make the transaction or directive before and in the included file end
+			allLines = append(allLines, "")
+			allLines = append(allLines, includedLines...)
+			allLines = append(allLines, "")
+		}
+	}
+
+	return allLines, nil
+}
+
+func getLedgerIncludedFileNames(ctx core.Context, fileName string, includePath string, allFiles map[string][]byte) ([]string, error) {
+	if includePath == "" || path.IsAbs(includePath) || strings.HasPrefix(includePath, "~") {
+		log.Errorf(ctx, "[ledger_data_reader.getLedgerIncludedFileNames] cannot include \"%s\" in file \"%s\", because only relative path is supported", includePath, fileName)
+		return nil, errs.ErrLedgerIncludedFileNotFound
+	}
+
+	includedFilePattern := path.Join(path.Dir(fileName), includePath)
+
+	if _, exists := allFiles[includedFilePattern]; exists {
+		return []string{includedFilePattern}, nil
+	}
+
+	includedFileNames := make([]string, 0)
+
+	if strings.ContainsAny(includedFilePattern, "*?[") {
+		for name := range allFiles {
+			if matched, err := path.Match(includedFilePattern, name); err == nil && matched && name != fileName {
+				includedFileNames = append(includedFileNames, name)
+			}
+		}
+
+		sort.Strings(includedFileNames)
+	}
+
+	if len(includedFileNames) < 1 {
+		log.Errorf(ctx, "[ledger_data_reader.getLedgerIncludedFileNames] cannot include \"%s\" in file \"%s\", because the file is not found in zip file", includePath, fileName)
+		return nil, errs.ErrLedgerIncludedFileNotFound
+	}
+
+	return includedFileNames, nil
+}
+
+func readLedgerFileLines(ctx core.Context, data []byte) ([]string, error) {
+	fallback := textunicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), textunicode.BOMOverride(fallback))
+	content, err := io.ReadAll(reader)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_data_reader.readLedgerFileLines] cannot read data, because %s", err.Error())
+		return nil, errs.ErrInvalidLedgerFile
+	}
+
+	return strings.Split(string(content), "\n"), nil
+}
+
+func readLedgerZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return io.ReadAll(io.LimitReader(reader, ledgerZipFileMaxUncompressedSize))
+}
+
+func isLedgerZipFile(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
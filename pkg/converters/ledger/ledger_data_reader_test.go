@@ -0,0 +1,410 @@
+package ledger
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestLedgerDataReaderRead(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"; Test Ledger Data\n"+
+		"account Bank:Checking  ; type: A\n"+
+		"account Card\n"+
+		"    ; type: L\n"+
+		"commodity ¥\n"+
+		"    alias CNY\n"+
+		"alias chk=Bank:Checking\n"+
+		"\n"+
+		"= /Expenses:Food/\n"+
+		"    (Budget:Food)  -1\n"+
+		"\n"+
+		"~ monthly\n"+
+		"    Expenses:Rent  $1,000.00\n"+
+		"    Assets:Checking\n"+
+		"\n"+
+		"2024/01/05=2024/01/06 * (#123) Payee Name | Foo Bar  ; :tag1:tag2:\n"+
+		"    ; tag3:\n"+
+		"    Income:Salary  -¥123.45\n"+
+		"    chk\n"+
+		"    (Budget:Income)  ¥123.45\n"+
+		"2024-01-06 ! Dinner\n"+
+		"    Expenses:Food  0.12 USD  ; :tag4:\n"+
+		"    Card  -0.12 USD = -100 USD\n"+
+		"comment\n"+
+		"2024-01-07 * Commented\n"+
+		"    Expenses:Food  1 USD\n"+
+		"    Card\n"+
+		"end comment\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(actualData.Accounts))
+	assert.Equal(t, ledgerAssetsAccountType, actualData.Accounts["Bank:Checking"].AccountType)
+	assert.Equal(t, ledgerLiabilitiesAccountType, actualData.Accounts["Card"].AccountType)
+	assert.Equal(t, ledgerIncomeAccountType, actualData.Accounts["Income:Salary"].AccountType)
+	assert.Equal(t, ledgerExpensesAccountType, actualData.Accounts["Expenses:Food"].AccountType)
+	assert.Equal(t, "CNY", actualData.getCurrency("¥"))
+	assert.Equal(t, "USD", actualData.getCurrency("$"))
+
+	assert.Equal(t, 2, len(actualData.Transactions))
+
+	assert.Equal(t, "2024-01-05 00:00:00", actualData.Transactions[0].Date)
+	assert.Equal(t, ledgerTransactionStatusCleared, actualData.Transactions[0].Status)
+	assert.Equal(t, "#123", actualData.Transactions[0].Code)
+	assert.Equal(t, "Payee Name", actualData.Transactions[0].Payee)
+	assert.Equal(t, "Foo Bar", actualData.Transactions[0].Description)
+	assert.Equal(t, []string{"tag1", "tag2", "tag3"}, actualData.Transactions[0].Tags)
+	assert.Equal(t, 2, len(actualData.Transactions[0].Postings))
+	assert.Equal(t, "Income:Salary", actualData.Transactions[0].Postings[0].Account)
+	assert.Equal(t, "-123.45", actualData.Transactions[0].Postings[0].Amount)
+	assert.Equal(t, "¥", actualData.Transactions[0].Postings[0].Commodity)
+	assert.Equal(t, "Bank:Checking", actualData.Transactions[0].Postings[1].Account)
+	assert.Equal(t, "123.45", actualData.Transactions[0].Postings[1].Amount)
+	assert.Equal(t, "¥", actualData.Transactions[0].Postings[1].Commodity)
+
+	assert.Equal(t, "2024-01-06 00:00:00", actualData.Transactions[1].Date)
+	assert.Equal(t, ledgerTransactionStatusPending, actualData.Transactions[1].Status)
+	assert.Equal(t, "", actualData.Transactions[1].Payee)
+	assert.Equal(t, "Dinner", actualData.Transactions[1].Description)
+	assert.Equal(t, []string{"tag4"}, actualData.Transactions[1].Tags)
+	assert.Equal(t, "0.12", actualData.Transactions[1].Postings[0].Amount)
+	assert.Equal(t, "Card", actualData.Transactions[1].Postings[1].Account)
+	assert.Equal(t, "-0.12", actualData.Transactions[1].Postings[1].Amount)
+	assert.Equal(t, "USD", actualData.Transactions[1].Postings[1].Commodity)
+}
+
+func TestLedgerDataReaderRead_PriceAndTotalPrice(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"2024-01-01 Exchange\n"+
+		"    Assets:USD  110.00 USD @@ 100 EUR\n"+
+		"    Assets:EUR\n"+
+		"2024-01-02 Travel\n"+
+		"    Expenses:Travel  20 EUR @ $1.105\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(actualData.Transactions))
+
+	assert.Equal(t, "100.00", actualData.Transactions[0].Postings[0].TotalPrice)
+	assert.Equal(t, "EUR", actualData.Transactions[0].Postings[0].TotalPriceCommodity)
+	assert.Equal(t, "-100.00", actualData.Transactions[0].Postings[1].Amount)
+	assert.Equal(t, "EUR", actualData.Transactions[0].Postings[1].Commodity)
+
+	assert.Equal(t, "EUR", actualData.Transactions[1].Postings[0].Commodity)
+	assert.Equal(t, "$", actualData.Transactions[1].Postings[0].PriceCommodity)
+	assert.Equal(t, "-22.10", actualData.Transactions[1].Postings[1].Amount)
+	assert.Equal(t, "$", actualData.Transactions[1].Postings[1].Commodity)
+}
+
+func TestLedgerDataReaderRead_DefaultYearAndCommodity(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"Y 2023\n"+
+		"D 1.000,00 EUR\n"+
+		"\n"+
+		"12/31 Groceries\n"+
+		"    Expenses:Food  1.234,56\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(actualData.Transactions))
+	assert.Equal(t, "2023-12-31 00:00:00", actualData.Transactions[0].Date)
+	assert.Equal(t, "1234.56", actualData.Transactions[0].Postings[0].Amount)
+	assert.Equal(t, "EUR", actualData.Transactions[0].Postings[0].Commodity)
+	assert.Equal(t, "-1234.56", actualData.Transactions[0].Postings[1].Amount)
+}
+
+func TestLedgerDataReaderRead_AutomatedTransaction(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"= /^Expenses:Food/\n"+
+		"    Expenses:Tax  0.05\n"+
+		"    Assets:Checking  *-0.05\n"+
+		"    (Budget:Food)  -1\n"+
+		"\n"+
+		"= desc:Coffee acct:^Expenses\n"+
+		"    Expenses:Donation  $1\n"+
+		"    Assets:Checking  $-1\n"+
+		"\n"+
+		"= tag:unsupported\n"+
+		"    Expenses:Unsupported  $1\n"+
+		"    Assets:Checking  $-1\n"+
+		"\n"+
+		"2024-01-01 Lunch\n"+
+		"    Expenses:Food  $20.00\n"+
+		"    Assets:Checking\n"+
+		"2024-01-02 Coffee\n"+
+		"    Expenses:Drink  $5.00\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(actualData.Transactions))
+	assert.Nil(t, actualData.Accounts["Expenses:Unsupported"])
+	assert.Nil(t, actualData.Accounts["Budget:Food"])
+
+	assert.Equal(t, 4, len(actualData.Transactions[0].Postings))
+	assert.Equal(t, "Expenses:Tax", actualData.Transactions[0].Postings[2].Account)
+	assert.Equal(t, "1.00", actualData.Transactions[0].Postings[2].Amount)
+	assert.Equal(t, "$", actualData.Transactions[0].Postings[2].Commodity)
+	assert.Equal(t, "Assets:Checking", actualData.Transactions[0].Postings[3].Account)
+	assert.Equal(t, "-1.00", actualData.Transactions[0].Postings[3].Amount)
+
+	assert.Equal(t, 4, len(actualData.Transactions[1].Postings))
+	assert.Equal(t, "Expenses:Donation", actualData.Transactions[1].Postings[2].Account)
+	assert.Equal(t, "1.00", actualData.Transactions[1].Postings[2].Amount)
+	assert.Equal(t, "Assets:Checking", actualData.Transactions[1].Postings[3].Account)
+	assert.Equal(t, "-1.00", actualData.Transactions[1].Postings[3].Amount)
+}
+
+func TestLedgerDataReaderRead_UnbalancedAutomatedTransaction(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"= Expenses:Food\n"+
+		"    Expenses:Tax  0.05\n"+
+		"\n"+
+		"2024-01-01 Lunch\n"+
+		"    Expenses:Food  $20.00\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+}
+
+func TestLedgerDataReaderRead_PeriodicTransaction(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReader(context, []byte(""+
+		"~ monthly from 2024-01-31 to 2024-04-01  Landlord | Rent  ; :home:\n"+
+		"    Expenses:Rent  $1,000.00\n"+
+		"    Assets:Checking\n"+
+		"\n"+
+		"~ every 2 weeks from 2024/01/01 until 2024/01/29\n"+
+		"    Expenses:Cleaning  $50\n"+
+		"    Assets:Checking\n"+
+		"\n"+
+		"~ monthly\n"+
+		"    Expenses:Budget  $1\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Nil(t, actualData.Accounts["Expenses:Budget"])
+	assert.Equal(t, 5, len(actualData.Transactions))
+
+	assert.Equal(t, "2024-01-31 00:00:00", actualData.Transactions[0].Date)
+	assert.Equal(t, "2024-03-02 00:00:00", actualData.Transactions[1].Date)
+	assert.Equal(t, "2024-03-31 00:00:00", actualData.Transactions[2].Date)
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "Landlord", actualData.Transactions[i].Payee)
+		assert.Equal(t, "Rent", actualData.Transactions[i].Description)
+		assert.Equal(t, []string{"home"}, actualData.Transactions[i].Tags)
+		assert.Equal(t, "1000.00", actualData.Transactions[i].Postings[0].Amount)
+		assert.Equal(t, "-1000.00", actualData.Transactions[i].Postings[1].Amount)
+	}
+
+	assert.Equal(t, "2024-01-01 00:00:00", actualData.Transactions[3].Date)
+	assert.Equal(t, "2024-01-15 00:00:00", actualData.Transactions[4].Date)
+	assert.Equal(t, "-50.00", actualData.Transactions[4].Postings[1].Amount)
+}
+
+func TestLedgerDataReaderReadFromZip_IncludedFiles(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewLedgerDataReaderFromZip(context, createLedgerTestZipFileFromDirectory(t, "testdata/include"))
+	assert.Nil(t, err)
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, ledgerAssetsAccountType, actualData.Accounts["Assets:Checking"].AccountType)
+	assert.Equal(t, ledgerLiabilitiesAccountType, actualData.Accounts["Liabilities:Card"].AccountType)
+
+	assert.Equal(t, 5, len(actualData.Transactions))
+
+	assert.Equal(t, "2024-01-05 00:00:00", actualData.Transactions[0].Date)
+	assert.Equal(t, 4, len(actualData.Transactions[0].Postings))
+	assert.Equal(t, "Expenses:Tips", actualData.Transactions[0].Postings[2].Account)
+	assert.Equal(t, "2.00", actualData.Transactions[0].Postings[2].Amount)
+	assert.Equal(t, "Liabilities:Card", actualData.Transactions[0].Postings[3].Account)
+	assert.Equal(t, "-2.00", actualData.Transactions[0].Postings[3].Amount)
+
+	assert.Equal(t, "2024-02-01 00:00:00", actualData.Transactions[1].Date)
+	assert.Equal(t, "Salary", actualData.Transactions[1].Description)
+	assert.Equal(t, 2, len(actualData.Transactions[1].Postings))
+
+	assert.Equal(t, "2024-02-10 00:00:00", actualData.Transactions[2].Date)
+	assert.Equal(t, 4, len(actualData.Transactions[2].Postings))
+
+	assert.Equal(t, "2024-03-01 00:00:00", actualData.Transactions[3].Date)
+	assert.Equal(t, "Rent", actualData.Transactions[3].Description)
+	assert.Equal(t, "2024-04-01 00:00:00", actualData.Transactions[4].Date)
+}
+
+func TestLedgerDataReaderReadFromZip_InvalidIncludedFiles(t *testing.T) {
+	context := core.NewNullContext()
+
+	_, err := createNewLedgerDataReaderFromZip(context, createLedgerTestZipFile(t, map[string]string{
+		"main.journal": "include missing.journal\n",
+	}))
+	assert.EqualError(t, err, errs.ErrLedgerIncludedFileNotFound.Message)
+
+	_, err = createNewLedgerDataReaderFromZip(context, createLedgerTestZipFile(t, map[string]string{
+		"main.journal":      "include ../outside.journal\n",
+		"sub/other.journal": "",
+	}))
+	assert.EqualError(t, err, errs.ErrLedgerIncludedFileNotFound.Message)
+
+	_, err = createNewLedgerDataReaderFromZip(context, createLedgerTestZipFile(t, map[string]string{
+		"main.journal": "include /etc/main.journal\n",
+	}))
+	assert.EqualError(t, err, errs.ErrLedgerIncludedFileNotFound.Message)
+
+	_, err = createNewLedgerDataReaderFromZip(context, createLedgerTestZipFile(t, map[string]string{
+		"main.journal":  "include sub/a.journal\n",
+		"sub/a.journal": "include b.journal\n",
+		"sub/b.journal": "include a.journal\n",
+	}))
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+
+	_, err = createNewLedgerDataReaderFromZip(context, createLedgerTestZipFile(t, map[string]string{
+		"a.journal": "",
+		"b.journal": "",
+	}))
+	assert.EqualError(t, err, errs.ErrLedgerMainFileNotFound.Message)
+
+	_, err = createNewLedgerDataReaderFromZip(context, createLedgerTestZipFile(t, map[string]string{
+		"readme.txt": "",
+	}))
+	assert.EqualError(t, err, errs.ErrLedgerMainFileNotFound.Message)
+
+	_, err = createNewLedgerDataReaderFromZip(context, []byte("PK\x03\x04invalid"))
+	assert.EqualError(t, err, errs.ErrInvalidZipFile.Message)
+}
+
+func TestLedgerDataReaderRead_InvalidData(t *testing.T) {
+	context := core.NewNullContext()
+
+	reader, err := createNewLedgerDataReader(context, []byte("include other.ledger\n"))
+	assert.Nil(t, err)
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrLedgerFileNotSupportInclude.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"2024-13-01 Invalid Date\n"+
+		"    Expenses:Food  1 USD\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"2024-01-01 Two Elided Postings\n"+
+		"    Expenses:Food\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+
+	reader, err = createNewLedgerDataReader(context, []byte(""+
+		"2024-01-01 Invalid Amount\n"+
+		"    Expenses:Food  1.2.3,4,5 USD\n"+
+		"    Assets:Checking\n"))
+	assert.Nil(t, err)
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
+
+func TestParseLedgerNumber(t *testing.T) {
+	expectedValues := map[string]string{
+		"1234":      "1234.00",
+		"1,234.56":  "1234.56",
+		"1.234,56":  "1234.56",
+		"1,5":       "1.50",
+		"1,000":     "1000.00",
+		"1.000.000": "1000000.00",
+		"1'000.25":  "1000.25",
+		".5":        "0.50",
+	}
+
+	for numberText, expectedValue := range expectedValues {
+		actualValue, err := parseLedgerNumber(numberText)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedValue, actualValue.FloatString(2))
+	}
+
+	_, err := parseLedgerNumber("")
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+
+	_, err = parseLedgerNumber("12a")
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
+
+func createLedgerTestZipFileFromDirectory(t *testing.T, directory string) []byte {
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(directory, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		content, err := os.ReadFile(filePath)
+
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(directory, filePath)
+
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(relativePath)] = string(content)
+		return nil
+	})
+
+	assert.Nil(t, err)
+
+	return createLedgerTestZipFile(t, files)
+}
+
+func createLedgerTestZipFile(t *testing.T, files map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+
+	for fileName, content := range files {
+		writer, err := zipWriter.Create(fileName)
+		assert.Nil(t, err)
+
+		_, err = writer.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, zipWriter.Close())
+
+	return buffer.Bytes()
+}
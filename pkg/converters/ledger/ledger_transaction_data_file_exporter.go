@@ -0,0 +1,239 @@
+package ledger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ledgerExportedDateFormat = "2006-01-02"
+const ledgerExportedPostingIndent = "    "
+const ledgerExportedPostingAccountNameMinWidth = 40
+
+const ledgerExportedAssetsAccountNamePrefix = "Assets"
+const ledgerExportedLiabilitiesAccountNamePrefix = "Liabilities"
+const ledgerExportedIncomeAccountNamePrefix = "Income"
+const ledgerExportedExpensesAccountNamePrefix = "Expenses"
+const ledgerExportedOpeningBalanceAccountName = "Equity:Opening Balances"
+
+// ledgerTransactionDataExporter defines the structure of ledger journal exporter for transaction data
+type ledgerTransactionDataExporter struct {
+}
+
+// Initialize a ledger transaction data exporter singleton instance
+var (
+	LedgerTransactionDataExporter = &ledgerTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported ledger journal data
+func (e *ledgerTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+	allTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].Type != models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			allTransactions = append(allTransactions, transactions[i])
+		}
+	}
+
+	// ledger journal is usually ordered by date ascending
+	sort.SliceStable(allTransactions, func(i, j int) bool {
+		return allTransactions[i].TransactionTime < allTransactions[j].TransactionTime
+	})
+
+	var builder strings.Builder
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		postings, err := e.getPostings(ctx, transaction, accountMap, categoryMap)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		transactionTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+
+		builder.WriteString(time.Unix(transactionTime, 0).In(transactionTimeZone).Format(ledgerExportedDateFormat))
+		builder.WriteString(" *")
+
+		if description := e.getSingleLineText(transaction.Comment); description != "" {
+			builder.WriteString(" ")
+			builder.WriteString(strings.ReplaceAll(description, string(ledgerCommentPrefix), ","))
+		}
+
+		builder.WriteString("\n")
+
+		if tags := e.getTags(transaction.TransactionId, allTagIndexes, tagMap); len(tags) > 0 {
+			builder.WriteString(ledgerExportedPostingIndent)
+			builder.WriteString(fmt.Sprintf("%c %c%s%c\n", ledgerCommentPrefix, ledgerTagSeparator, strings.Join(tags, string(ledgerTagSeparator)), ledgerTagSeparator))
+		}
+
+		for j := 0; j < len(postings); j++ {
+			builder.WriteString(ledgerExportedPostingIndent)
+			builder.WriteString(fmt.Sprintf("%-*s  %s\n", ledgerExportedPostingAccountNameMinWidth, postings[j][0], postings[j][1]))
+		}
+	}
+
+	return []byte(builder.String()), nil
+}
+
+func (e *ledgerTransactionDataExporter) getPostings(ctx core.Context, transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory) ([][2]string, error) {
+	account, exists := accountMap[transaction.AccountId]
+
+	if !exists {
+		log.Errorf(ctx, "[ledger_transaction_data_file_exporter.getPostings] cannot find account \"id:%d\" of transaction \"id:%d\"", transaction.AccountId, transaction.TransactionId)
+		return nil, errs.ErrAccountNotFound
+	}
+
+	accountName := e.getAccountName(account, accountMap)
+	amount := e.formatAmount(transaction.Amount, account.Currency)
+	negativeAmount := e.formatAmount(-transaction.Amount, account.Currency)
+
+	switch transaction.Type {
+	case models.TRANSACTION_DB_TYPE_MODIFY_BALANCE:
+		return [][2]string{
+			{accountName, amount},
+			{ledgerExportedOpeningBalanceAccountName, negativeAmount},
+		}, nil
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		if transaction.HasSplits && len(transaction.Splits) > 0 {
+			postings := [][2]string{{accountName, amount}}
+
+			for i := 0; i < len(transaction.Splits); i++ {
+				split := transaction.Splits[i]
+				postings = append(postings, [2]string{e.getCategoryName(ledgerExportedIncomeAccountNamePrefix, split.CategoryId, categoryMap), e.formatAmount(-split.Amount, account.Currency)})
+			}
+
+			return postings, nil
+		}
+
+		return [][2]string{
+			{accountName, amount},
+			{e.getCategoryName(ledgerExportedIncomeAccountNamePrefix, transaction.CategoryId, categoryMap), negativeAmount},
+		}, nil
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		if transaction.HasSplits && len(transaction.Splits) > 0 {
+			postings := make([][2]string, 0, len(transaction.Splits)+1)
+
+			for i := 0; i < len(transaction.Splits); i++ {
+				split := transaction.Splits[i]
+				postings = append(postings, [2]string{e.getCategoryName(ledgerExportedExpensesAccountNamePrefix, split.CategoryId, categoryMap), e.formatAmount(split.Amount, account.Currency)})
+			}
+
+			return append(postings, [2]string{accountName, negativeAmount}), nil
+		}
+
+		return [][2]string{
+			{e.getCategoryName(ledgerExportedExpensesAccountNamePrefix, transaction.CategoryId, categoryMap), amount},
+			{accountName, negativeAmount},
+		}, nil
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		relatedAccount, exists := accountMap[transaction.RelatedAccountId]
+
+		if !exists {
+			log.Errorf(ctx, "[ledger_transaction_data_file_exporter.getPostings] cannot find related account \"id:%d\" of transaction \"id:%d\"", transaction.RelatedAccountId, transaction.TransactionId)
+			return nil, errs.ErrAccountNotFound
+		}
+
+		relatedAmount := e.formatAmount(transaction.RelatedAccountAmount, relatedAccount.Currency)
+
+		// the total price makes the transaction balanced when transferring between different currencies
+		if relatedAccount.Currency != account.Currency {
+			relatedAmount = fmt.Sprintf("%s %s %s", relatedAmount, ledgerTotalPricePrefix, amount)
+		}
+
+		return [][2]string{
+			{e.getAccountName(relatedAccount, accountMap), relatedAmount},
+			{accountName, negativeAmount},
+		}, nil
+	default:
+		log.Errorf(ctx, "[ledger_transaction_data_file_exporter.getPostings] transaction type \"%d\" of transaction \"id:%d\" is invalid", transaction.Type, transaction.TransactionId)
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+}
+
+func (e *ledgerTransactionDataExporter) getAccountName(account *models.Account, accountMap map[int64]*models.Account) string {
+	prefix := ledgerExportedAssetsAccountNamePrefix
+	accountName := e.getAccountNameItem(account.Name)
+
+	if parentAccount, exists := accountMap[account.ParentAccountId]; exists && account.ParentAccountId != models.LevelOneAccountParentId {
+		accountName = e.getAccountNameItem(parentAccount.Name) + ledgerAccountNameItemsSeparator + accountName
+		account = parentAccount
+	}
+
+	if account.Category.IsLiability() {
+		prefix = ledgerExportedLiabilitiesAccountNamePrefix
+	}
+
+	return prefix + ledgerAccountNameItemsSeparator + accountName
+}
+
+func (e *ledgerTransactionDataExporter) getCategoryName(prefix string, categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	category, exists := categoryMap[categoryId]
+
+	if !exists {
+		return prefix
+	}
+
+	categoryName := e.getAccountNameItem(category.Name)
+
+	if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+		categoryName = e.getAccountNameItem(parentCategory.Name) + ledgerAccountNameItemsSeparator + categoryName
+	}
+
+	return prefix + ledgerAccountNameItemsSeparator + categoryName
+}
+
+func (e *ledgerTransactionDataExporter) getTags(transactionId int64, allTagIndexes map[int64][]int64, tagMap map[int64]*models.TransactionTag) []string {
+	tagIndexes, exists := allTagIndexes[transactionId]
+
+	if !exists {
+		return nil
+	}
+
+	tags := make([]string, 0, len(tagIndexes))
+
+	for i := 0; i < len(tagIndexes); i++ {
+		tag, exists := tagMap[tagIndexes[i]]
+
+		if !exists {
+			continue
+		}
+
+		// tag name in ledger cannot contain whitespace or colon
+		tagName := strings.Join(strings.Fields(strings.ReplaceAll(tag.Name, string(ledgerTagSeparator), " ")), "_")
+
+		if tagName != "" {
+			tags = append(tags, tagName)
+		}
+	}
+
+	return tags
+}
+
+func (e *ledgerTransactionDataExporter) getAccountNameItem(name string) string {
+	// account name in ledger cannot contain colon, tab or two consecutive spaces
+	name = strings.ReplaceAll(name, ledgerAccountNameItemsSeparator, "-")
+	name = strings.ReplaceAll(name, string(ledgerCommentPrefix), ",")
+
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func (e *ledgerTransactionDataExporter) getSingleLineText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func (e *ledgerTransactionDataExporter) formatAmount(amount int64, currency string) string {
+	return utils.FormatAmount(amount) + " " + currency
+}
@@ -0,0 +1,90 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestLedgerTransactionDataExporterToExportedContent(t *testing.T) {
+	exporter := LedgerTransactionDataExporter
+	context := core.NewNullContext()
+
+	accountMap := map[int64]*models.Account{
+		1: {AccountId: 1, Name: "Cash", Category: models.ACCOUNT_CATEGORY_CASH, Currency: "CNY"},
+		2: {AccountId: 2, Name: "Credit: Card", Category: models.ACCOUNT_CATEGORY_CREDIT_CARD, Currency: "CNY"},
+		3: {AccountId: 3, Name: "Euro", Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Currency: "EUR"},
+	}
+
+	categoryMap := map[int64]*models.TransactionCategory{
+		10: {CategoryId: 10, Name: "Food", ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+		11: {CategoryId: 11, Name: "Lunch", ParentCategoryId: 10},
+		20: {CategoryId: 20, Name: "Salary", ParentCategoryId: models.LevelOneTransactionCategoryParentId},
+	}
+
+	tagMap := map[int64]*models.TransactionTag{
+		100: {TagId: 100, Name: "my tag"},
+	}
+
+	transactions := []*models.Transaction{
+		{TransactionId: 4, Type: models.TRANSACTION_DB_TYPE_TRANSFER_OUT, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1725408000), AccountId: 1, Amount: 1000, RelatedAccountId: 3, RelatedAccountAmount: 130},
+		{TransactionId: 5, Type: models.TRANSACTION_DB_TYPE_TRANSFER_IN, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1725408000) + 1, AccountId: 3, Amount: 130, RelatedAccountId: 1, RelatedAccountAmount: 1000},
+		{TransactionId: 3, Type: models.TRANSACTION_DB_TYPE_EXPENSE, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1725321600), AccountId: 2, CategoryId: 11, Amount: 100, Comment: "Lunch\nwith friends"},
+		{TransactionId: 2, Type: models.TRANSACTION_DB_TYPE_INCOME, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1725235200), AccountId: 1, CategoryId: 20, Amount: 12},
+		{TransactionId: 1, Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1725148800), AccountId: 1, Amount: 12345},
+	}
+
+	allTagIndexes := map[int64][]int64{
+		3: {100},
+	}
+
+	result, err := exporter.ToExportedContent(context, 1234567890, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	assert.Nil(t, err)
+
+	expectedContent := "" +
+		"2024-09-01 *\n" +
+		"    Assets:Cash                               123.45 CNY\n" +
+		"    Equity:Opening Balances                   -123.45 CNY\n" +
+		"\n" +
+		"2024-09-02 *\n" +
+		"    Assets:Cash                               0.12 CNY\n" +
+		"    Income:Salary                             -0.12 CNY\n" +
+		"\n" +
+		"2024-09-03 * Lunch with friends\n" +
+		"    ; :my_tag:\n" +
+		"    Expenses:Food:Lunch                       1.00 CNY\n" +
+		"    Liabilities:Credit- Card                  -1.00 CNY\n" +
+		"\n" +
+		"2024-09-04 *\n" +
+		"    Assets:Euro                               1.30 EUR @@ 10.00 CNY\n" +
+		"    Assets:Cash                               -10.00 CNY\n"
+
+	assert.Equal(t, expectedContent, string(result))
+
+	// the exported content can be imported again
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, allNewTags, err := LedgerTransactionDataImporter.ParseImportedData(context, user, result, 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, "Expenses:Food:Lunch", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Liabilities:Credit- Card", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(130), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "EUR", allNewTransactions[3].OriginalDestinationAccountCurrency)
+}
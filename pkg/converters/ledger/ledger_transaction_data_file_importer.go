@@ -0,0 +1,56 @@
+package ledger
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var ledgerTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_MODIFY_BALANCE: utils.IntToString(int(models.TRANSACTION_TYPE_MODIFY_BALANCE)),
+	models.TRANSACTION_TYPE_INCOME:         utils.IntToString(int(models.TRANSACTION_TYPE_INCOME)),
+	models.TRANSACTION_TYPE_EXPENSE:        utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE)),
+	models.TRANSACTION_TYPE_TRANSFER:       utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER)),
+}
+
+// ledgerTransactionDataImporter defines the structure of ledger (and hledger) journal importer for transaction data
+type ledgerTransactionDataImporter struct {
+}
+
+// Initialize a ledger transaction data importer singleton instance
+var (
+	LedgerTransactionDataImporter = &ledgerTransactionDataImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing the ledger journal data, or the main ledger journal data in the zip file which contains all included files
+func (c *ledgerTransactionDataImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	var ledgerDataReader *ledgerDataReader
+	var err error
+
+	if isLedgerZipFile(data) {
+		ledgerDataReader, err = createNewLedgerDataReaderFromZip(ctx, data)
+	} else {
+		ledgerDataReader, err = createNewLedgerDataReader(ctx, data)
+	}
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	ledgerData, err := ledgerDataReader.read(ctx)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	transactionDataTable, err := createNewLedgerTransactionDataTable(ledgerData)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	dataTableImporter := converter.CreateNewImporterWithTypeNameMapping(ledgerTransactionTypeNameMapping, "", "", LEDGER_TRANSACTION_TAG_SEPARATOR)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
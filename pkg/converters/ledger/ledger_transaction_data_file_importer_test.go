@@ -0,0 +1,232 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestLedgerTransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Opening\n"+
+			"    Assets:TestAccount  123.45 CNY\n"+
+			"    Equity:Opening Balances\n"+
+			"2024-09-02 * Employer | Salary  ; :work:\n"+
+			"    Income:TestCategory  -0.12 CNY\n"+
+			"    Assets:TestAccount\n"+
+			"2024/09/03 * Lunch\n"+
+			"    Assets:TestAccount  -1.00 CNY\n"+
+			"    Expenses:TestCategory2  1.00 CNY\n"+
+			"2024-09-04 * Transfer\n"+
+			"    Assets:TestAccount  -0.05 CNY\n"+
+			"    Liabilities:TestAccount2\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 1, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:TestAccount", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Opening", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725235200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+	assert.Equal(t, "Income:TestCategory", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Employer", allNewTransactions[1].OriginalPayeeName)
+	assert.Equal(t, "Salary", allNewTransactions[1].Comment)
+	assert.Equal(t, []string{"work"}, allNewTransactions[1].OriginalTagNames)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725321600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(100), allNewTransactions[2].Amount)
+	assert.Equal(t, "Assets:TestAccount", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Expenses:TestCategory2", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(5), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(5), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Assets:TestAccount", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Liabilities:TestAccount2", allNewTransactions[3].OriginalDestinationAccountName)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseCommodityAndPrice(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Exchange\n"+
+			"    Assets:Euro  €90.00 @@ $100.00\n"+
+			"    Assets:Dollar\n"+
+			"2024-09-02 * Coffee\n"+
+			"    Expenses:Coffee  $3.50\n"+
+			"    Assets:Dollar\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:Dollar", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, int64(9000), allNewTransactions[0].RelatedAccountAmount)
+	assert.Equal(t, "Assets:Euro", allNewTransactions[0].OriginalDestinationAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(350), allNewTransactions[1].Amount)
+	assert.Equal(t, "USD", allNewTransactions[1].OriginalSourceAccountCurrency)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ZipFileWithIncludedFiles(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, createLedgerTestZipFile(t, map[string]string{
+		"journal/main.journal":    "include 2024/09.journal\n",
+		"journal/2024/09.journal": "2024-09-03 * Lunch\n    Assets:TestAccount  -1.00 CNY\n    Expenses:TestCategory  1.00 CNY\n",
+	}), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100), allNewTransactions[0].Amount)
+	assert.Equal(t, "Expenses:TestCategory", allNewTransactions[0].OriginalCategoryName)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_ParseSplitTransaction(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Expenses:Food": {
+			"": &models.TransactionCategory{CategoryId: 1001, Name: "Expenses:Food"},
+		},
+		"Expenses:Drink": {
+			"": &models.TransactionCategory{CategoryId: 1002, Name: "Expenses:Drink"},
+		},
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Split\n"+
+			"    Expenses:Food  1.00 CNY\n"+
+			"    Expenses:Drink  2.00 CNY\n"+
+			"    Assets:TestAccount\n"+
+			"2024-09-02 * Split With New Category\n"+
+			"    Expenses:Food  1.00 CNY\n"+
+			"    Expenses:Other  2.00 CNY\n"+
+			"    Assets:TestAccount\n"), 0, nil, expenseCategoryMap, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(300), allNewTransactions[0].Amount)
+	assert.Equal(t, "Assets:TestAccount", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, int64(1001), allNewTransactions[0].CategoryId)
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, int64(1001), allNewTransactions[0].Splits[0].CategoryId)
+	assert.Equal(t, int64(100), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, int64(1002), allNewTransactions[0].Splits[1].CategoryId)
+	assert.Equal(t, int64(200), allNewTransactions[0].Splits[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(300), allNewTransactions[1].Amount)
+	assert.Equal(t, "Expenses:Food", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, 0, len(allNewTransactions[1].Splits))
+
+	allNewTransactions, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Split Income\n"+
+			"    Assets:TestAccount  3.00 CNY\n"+
+			"    Income:Salary  -1.00 CNY\n"+
+			"    Income:Bonus  -2.00 CNY\n"), 0, nil, nil, map[string]map[string]*models.TransactionCategory{
+		"Income:Salary": {
+			"": &models.TransactionCategory{CategoryId: 2001, Name: "Income:Salary"},
+		},
+		"Income:Bonus": {
+			"": &models.TransactionCategory{CategoryId: 2002, Name: "Income:Bonus"},
+		},
+	}, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(300), allNewTransactions[0].Amount)
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, int64(2001), allNewTransactions[0].Splits[0].CategoryId)
+	assert.Equal(t, int64(100), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, int64(2002), allNewTransactions[0].Splits[1].CategoryId)
+	assert.Equal(t, int64(200), allNewTransactions[0].Splits[1].Amount)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_InvalidData(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Split\n"+
+			"    Expenses:Food  1.00 CNY\n"+
+			"    Assets:TestAccount2  2.00 CNY\n"+
+			"    Assets:TestAccount\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotSupportedSplitTransactions.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Split\n"+
+			"    Expenses:Food  1.00 CNY\n"+
+			"    Income:Salary  -2.00 CNY\n"+
+			"    Assets:TestAccount\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotSupportedSplitTransactions.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Unknown Account Type\n"+
+			"    Foo:Bar  1.00 CNY\n"+
+			"    Assets:TestAccount\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrThereAreNotSupportedTransactionType.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 * Single Posting\n"+
+			"    Assets:TestAccount  1.00 CNY\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidLedgerFile.Message)
+}
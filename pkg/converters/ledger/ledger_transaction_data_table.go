@@ -0,0 +1,357 @@
+package ledger
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var ledgerTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                    true,
+}
+
+var LEDGER_TRANSACTION_TAG_SEPARATOR = string(ledgerTagSeparator)
+
+// ledgerTransactionDataTable defines the structure of ledger transaction data table
+type ledgerTransactionDataTable struct {
+	allData []*ledgerTransactionEntry
+	data    *ledgerData
+}
+
+// ledgerTransactionDataRow defines the structure of ledger transaction data row
+type ledgerTransactionDataRow struct {
+	dataTable  *ledgerTransactionDataTable
+	data       *ledgerTransactionEntry
+	finalItems map[datatable.TransactionDataTableColumn]string
+	splits     []*datatable.TransactionDataRowSplit
+}
+
+// ledgerTransactionDataRowIterator defines the structure of ledger transaction data row iterator
+type ledgerTransactionDataRowIterator struct {
+	dataTable    *ledgerTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *ledgerTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := ledgerTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *ledgerTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *ledgerTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &ledgerTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *ledgerTransactionDataRow) IsValid() bool {
+	return true
+}
+
+// GetData returns the data in the specified column type
+func (r *ledgerTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := ledgerTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// GetSplits returns the split lines of the transaction data row
+func (r *ledgerTransactionDataRow) GetSplits() []*datatable.TransactionDataRowSplit {
+	return r.splits
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *ledgerTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next transaction data row
+func (t *ledgerTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, splits, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+		splits:     splits,
+	}, nil
+}
+
+func (t *ledgerTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, ledgerEntry *ledgerTransactionEntry) (map[datatable.TransactionDataTableColumn]string, []*datatable.TransactionDataRowSplit, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(ledgerTransactionSupportedColumns))
+	var splits []*datatable.TransactionDataRowSplit
+
+	if ledgerEntry.Date == "" {
+		return nil, nil, errs.ErrMissingTransactionTime
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = ledgerEntry.Date
+
+	if len(ledgerEntry.Postings) == 2 {
+		posting1 := ledgerEntry.Postings[0]
+		posting2 := ledgerEntry.Postings[1]
+
+		account1 := t.dataTable.data.Accounts[posting1.Account]
+		account2 := t.dataTable.data.Accounts[posting2.Account]
+
+		if account1 == nil || account2 == nil {
+			return nil, nil, errs.ErrMissingAccountData
+		}
+
+		amount1, err := utils.ParseAmount(posting1.Amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse amount \"%s\", because %s", posting1.Amount, err.Error())
+			return nil, nil, errs.ErrAmountInvalid
+		}
+
+		amount2, err := utils.ParseAmount(posting2.Amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse amount \"%s\", because %s", posting2.Amount, err.Error())
+			return nil, nil, errs.ErrAmountInvalid
+		}
+
+		currency1 := t.dataTable.data.getCurrency(posting1.Commodity)
+		currency2 := t.dataTable.data.getCurrency(posting2.Commodity)
+
+		if ((account1.AccountType == ledgerEquityAccountType || account1.AccountType == ledgerIncomeAccountType) && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType)) ||
+			((account2.AccountType == ledgerEquityAccountType || account2.AccountType == ledgerIncomeAccountType) && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType)) { // income
+			fromAccount := account1
+			toAccount := account2
+			toCurrency := currency2
+			toAmount := amount2
+
+			if (account2.AccountType == ledgerEquityAccountType || account2.AccountType == ledgerIncomeAccountType) && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType) {
+				fromAccount = account2
+				toAccount = account1
+				toCurrency = currency1
+				toAmount = amount1
+			}
+
+			if fromAccount.isOpeningBalanceEquityAccount() {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_MODIFY_BALANCE))
+			} else {
+				data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_INCOME))
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = toCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(toAmount)
+		} else if account1.AccountType == ledgerExpensesAccountType && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) ||
+			(account2.AccountType == ledgerExpensesAccountType && (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType)) { // expense
+			fromAccount := account1
+			fromCurrency := currency1
+			fromAmount := amount1
+			toAccount := account2
+
+			if account1.AccountType == ledgerExpensesAccountType && (account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) {
+				fromAccount = account2
+				fromCurrency = currency2
+				fromAmount = amount2
+				toAccount = account1
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE))
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-fromAmount)
+		} else if (account1.AccountType == ledgerAssetsAccountType || account1.AccountType == ledgerLiabilitiesAccountType) &&
+			(account2.AccountType == ledgerAssetsAccountType || account2.AccountType == ledgerLiabilitiesAccountType) {
+			var fromAccount, toAccount *ledgerAccount
+			var fromAmount, toAmount int64
+			var fromCurrency, toCurrency string
+
+			if amount1 < 0 {
+				fromAccount = account1
+				fromCurrency = currency1
+				fromAmount = -amount1
+				toAccount = account2
+				toCurrency = currency2
+				toAmount = amount2
+			} else if amount2 < 0 {
+				fromAccount = account2
+				fromCurrency = currency2
+				fromAmount = -amount2
+				toAccount = account1
+				toCurrency = currency1
+				toAmount = amount1
+			} else {
+				log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transfer transaction, because unexcepted account amounts \"%d\" and \"%d\"", amount1, amount2)
+				return nil, nil, errs.ErrInvalidLedgerFile
+			}
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER))
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = ""
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(fromAmount)
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = toAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = toCurrency
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(toAmount)
+		} else {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transaction, because unexcepted account types \"%d\" and \"%d\"", account1.AccountType, account2.AccountType)
+			return nil, nil, errs.ErrThereAreNotSupportedTransactionType
+		}
+	} else if len(ledgerEntry.Postings) <= 1 {
+		log.Errorf(ctx, "[ledger_transaction_data_table.parseTransaction] cannot parse transaction, because postings count is %d", len(ledgerEntry.Postings))
+		return nil, nil, errs.ErrInvalidLedgerFile
+	} else {
+		var err error
+		splits, err = t.parseMultiplePostingsTransaction(ctx, ledgerEntry, data)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(ledgerEntry.Tags, LEDGER_TRANSACTION_TAG_SEPARATOR)
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ledgerEntry.Description
+	data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ledgerEntry.Payee
+
+	return data, splits, nil
+}
+
+func (t *ledgerTransactionDataRowIterator) parseMultiplePostingsTransaction(ctx core.Context, ledgerEntry *ledgerTransactionEntry, data map[datatable.TransactionDataTableColumn]string) ([]*datatable.TransactionDataRowSplit, error) {
+	var assetOrLiabilityPosting *ledgerPosting
+	var assetOrLiabilityAccount *ledgerAccount
+	categoryPostings := make([]*ledgerPosting, 0, len(ledgerEntry.Postings)-1)
+	categoryAccountType := ledgerUnknownAccountType
+
+	for i := 0; i < len(ledgerEntry.Postings); i++ {
+		posting := ledgerEntry.Postings[i]
+		account := t.dataTable.data.Accounts[posting.Account]
+
+		if account == nil {
+			return nil, errs.ErrMissingAccountData
+		}
+
+		if account.AccountType == ledgerAssetsAccountType || account.AccountType == ledgerLiabilitiesAccountType {
+			if assetOrLiabilityPosting != nil {
+				log.Errorf(ctx, "[ledger_transaction_data_table.parseMultiplePostingsTransaction] cannot parse split transaction, because there are more than one assets or liabilities accounts")
+				return nil, errs.ErrNotSupportedSplitTransactions
+			}
+
+			assetOrLiabilityPosting = posting
+			assetOrLiabilityAccount = account
+			continue
+		}
+
+		if account.AccountType != ledgerExpensesAccountType && account.AccountType != ledgerIncomeAccountType {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseMultiplePostingsTransaction] cannot parse split transaction, because unexcepted account type \"%d\"", account.AccountType)
+			return nil, errs.ErrNotSupportedSplitTransactions
+		}
+
+		if categoryAccountType != ledgerUnknownAccountType && categoryAccountType != account.AccountType {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseMultiplePostingsTransaction] cannot parse split transaction, because there are both income and expenses accounts")
+			return nil, errs.ErrNotSupportedSplitTransactions
+		}
+
+		categoryAccountType = account.AccountType
+		categoryPostings = append(categoryPostings, posting)
+	}
+
+	if assetOrLiabilityPosting == nil || len(categoryPostings) < 2 {
+		log.Errorf(ctx, "[ledger_transaction_data_table.parseMultiplePostingsTransaction] cannot parse split transaction, because there is no assets or liabilities account")
+		return nil, errs.ErrNotSupportedSplitTransactions
+	}
+
+	amount, err := utils.ParseAmount(assetOrLiabilityPosting.Amount)
+
+	if err != nil {
+		log.Errorf(ctx, "[ledger_transaction_data_table.parseMultiplePostingsTransaction] cannot parse amount \"%s\", because %s", assetOrLiabilityPosting.Amount, err.Error())
+		return nil, errs.ErrAmountInvalid
+	}
+
+	isExpense := categoryAccountType == ledgerExpensesAccountType
+
+	if isExpense {
+		amount = -amount
+	}
+
+	splits := make([]*datatable.TransactionDataRowSplit, len(categoryPostings))
+
+	for i := 0; i < len(categoryPostings); i++ {
+		posting := categoryPostings[i]
+		splitAmount, err := utils.ParseAmount(posting.Amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[ledger_transaction_data_table.parseMultiplePostingsTransaction] cannot parse amount \"%s\", because %s", posting.Amount, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		if !isExpense {
+			splitAmount = -splitAmount
+		}
+
+		splits[i] = &datatable.TransactionDataRowSplit{
+			SubCategory: posting.Account,
+			Amount:      utils.FormatAmount(splitAmount),
+		}
+	}
+
+	if isExpense {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE))
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = utils.IntToString(int(models.TRANSACTION_TYPE_INCOME))
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = splits[0].SubCategory
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = assetOrLiabilityAccount.Name
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = t.dataTable.data.getCurrency(assetOrLiabilityPosting.Commodity)
+	data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+
+	return splits, nil
+}
+
+func createNewLedgerTransactionDataTable(ledgerData *ledgerData) (*ledgerTransactionDataTable, error) {
+	if ledgerData == nil {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return &ledgerTransactionDataTable{
+		allData: ledgerData.Transactions,
+		data:    ledgerData,
+	}, nil
+}
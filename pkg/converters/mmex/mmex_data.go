@@ -0,0 +1,96 @@
+package mmex
+
+// Money Manager Ex transaction codes
+const (
+	MMEX_TRANSACTION_CODE_WITHDRAWAL = "Withdrawal"
+	MMEX_TRANSACTION_CODE_DEPOSIT    = "Deposit"
+	MMEX_TRANSACTION_CODE_TRANSFER   = "Transfer"
+)
+
+// Money Manager Ex transaction status
+const (
+	MMEX_TRANSACTION_STATUS_VOID = "V"
+)
+
+// mmexData defines the structure of money manager ex database data
+type mmexData struct {
+	Accounts          map[int64]*mmexAccount
+	Categories        map[int64]*mmexCategory
+	SubCategories     map[int64]*mmexCategory
+	Payees            map[int64]string
+	Transactions      []*mmexTransaction
+	SplitTransactions map[int64][]*mmexSplitTransaction
+	TransactionTags   map[int64][]string
+}
+
+// mmexAccount defines the structure of money manager ex account
+type mmexAccount struct {
+	AccountId      int64
+	Name           string
+	Currency       string
+	InitialBalance float64
+	InitialDate    string
+}
+
+// mmexCategory defines the structure of money manager ex category
+type mmexCategory struct {
+	CategoryId int64
+	Name       string
+	ParentId   int64
+}
+
+// mmexTransaction defines the structure of money manager ex transaction
+type mmexTransaction struct {
+	TransactionId   int64
+	AccountId       int64
+	ToAccountId     int64
+	PayeeId         int64
+	TransactionCode string
+	Amount          float64
+	ToAmount        float64
+	Status          string
+	Notes           string
+	CategoryId      int64
+	SubCategoryId   int64
+	TransactionDate string
+}
+
+// mmexSplitTransaction defines the structure of money manager ex split transaction
+type mmexSplitTransaction struct {
+	SplitTransactionId int64
+	TransactionId      int64
+	CategoryId         int64
+	SubCategoryId      int64
+	Amount             float64
+	Notes              string
+}
+
+// GetCategoryNames returns the parent category name and category name of the specified category
+func (d *mmexData) GetCategoryNames(categoryId int64, subCategoryId int64) (string, string) {
+	// legacy database stores the sub category in a separate table
+	if subCategoryId > 0 {
+		subCategory, exists := d.SubCategories[subCategoryId]
+
+		if exists {
+			parentCategoryName := ""
+
+			if category, exists := d.Categories[subCategory.ParentId]; exists {
+				parentCategoryName = category.Name
+			}
+
+			return parentCategoryName, subCategory.Name
+		}
+	}
+
+	category, exists := d.Categories[categoryId]
+
+	if !exists {
+		return "", ""
+	}
+
+	if parentCategory, exists := d.Categories[category.ParentId]; exists && category.ParentId > 0 {
+		return parentCategory.Name, category.Name
+	}
+
+	return "", category.Name
+}
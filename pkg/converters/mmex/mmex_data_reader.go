@@ -0,0 +1,347 @@
+package mmex
+
+import (
+	"bytes"
+	"database/sql"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+const sqliteDatabaseFileHeader = "SQLite format 3\x00"
+
+// mmexDataReader defines the structure of money manager ex database reader
+type mmexDataReader struct {
+	data []byte
+}
+
+// read returns the imported money manager ex data
+// Reference: https://github.com/moneymanagerex/database/blob/master/tables.sql
+func (r *mmexDataReader) read(ctx core.Context) (*mmexData, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.read] cannot open in-memory database, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	conn, err := db.Conn(ctx)
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.read] cannot get database connection, because %s", err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		sqliteConn, ok := driverConn.(*sqlite3.SQLiteConn)
+
+		if !ok {
+			return errs.ErrOperationFailed
+		}
+
+		return sqliteConn.Deserialize(r.data, "main")
+	})
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.read] cannot load money manager ex database, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	for _, tableName := range []string{"ACCOUNTLIST_V1", "CHECKINGACCOUNT_V1", "CURRENCYFORMATS_V1"} {
+		if !r.hasTable(ctx, conn, tableName) {
+			log.Errorf(ctx, "[mmex_data_reader.read] cannot find table \"%s\" in money manager ex database", tableName)
+			return nil, errs.ErrInvalidMoneyManagerExFile
+		}
+	}
+
+	data := &mmexData{}
+
+	if data.Accounts, err = r.readAccounts(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	if data.Categories, data.SubCategories, err = r.readCategories(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	if data.Payees, err = r.readPayees(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	if data.Transactions, err = r.readTransactions(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	if data.SplitTransactions, err = r.readSplitTransactions(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	if data.TransactionTags, err = r.readTransactionTags(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *mmexDataReader) readAccounts(ctx core.Context, conn *sql.Conn) (map[int64]*mmexAccount, error) {
+	initialDateColumn := "''"
+
+	if r.hasColumn(ctx, conn, "ACCOUNTLIST_V1", "INITIALDATE") {
+		initialDateColumn = "COALESCE(a.INITIALDATE, '')"
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT a.ACCOUNTID, COALESCE(a.ACCOUNTNAME, ''), COALESCE(c.CURRENCY_SYMBOL, ''), COALESCE(a.INITIALBAL, 0), "+initialDateColumn+" FROM ACCOUNTLIST_V1 a LEFT JOIN CURRENCYFORMATS_V1 c ON a.CURRENCYID = c.CURRENCYID")
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.readAccounts] cannot query accounts, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	defer rows.Close()
+
+	accounts := make(map[int64]*mmexAccount)
+
+	for rows.Next() {
+		account := &mmexAccount{}
+
+		if err := rows.Scan(&account.AccountId, &account.Name, &account.Currency, &account.InitialBalance, &account.InitialDate); err != nil {
+			log.Errorf(ctx, "[mmex_data_reader.readAccounts] cannot read account, because %s", err.Error())
+			return nil, errs.ErrInvalidMoneyManagerExFile
+		}
+
+		accounts[account.AccountId] = account
+	}
+
+	return accounts, rows.Err()
+}
+
+func (r *mmexDataReader) readCategories(ctx core.Context, conn *sql.Conn) (map[int64]*mmexCategory, map[int64]*mmexCategory, error) {
+	categories := make(map[int64]*mmexCategory)
+	subCategories := make(map[int64]*mmexCategory)
+
+	if !r.hasTable(ctx, conn, "CATEGORY_V1") {
+		return categories, subCategories, nil
+	}
+
+	parentIdColumn := "-1"
+
+	if r.hasColumn(ctx, conn, "CATEGORY_V1", "PARENTID") {
+		parentIdColumn = "COALESCE(PARENTID, -1)"
+	}
+
+	err := r.readCategoriesByQuery(ctx, conn, "SELECT CATEGID, COALESCE(CATEGNAME, ''), "+parentIdColumn+" FROM CATEGORY_V1", categories)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// legacy database (before v1.6.0) stores the sub category in a separate table
+	if r.hasTable(ctx, conn, "SUBCATEGORY_V1") {
+		err = r.readCategoriesByQuery(ctx, conn, "SELECT SUBCATEGID, COALESCE(SUBCATEGNAME, ''), CATEGID FROM SUBCATEGORY_V1", subCategories)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return categories, subCategories, nil
+}
+
+func (r *mmexDataReader) readCategoriesByQuery(ctx core.Context, conn *sql.Conn, query string, categories map[int64]*mmexCategory) error {
+	rows, err := conn.QueryContext(ctx, query)
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.readCategoriesByQuery] cannot query categories, because %s", err.Error())
+		return errs.ErrInvalidMoneyManagerExFile
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		category := &mmexCategory{}
+
+		if err := rows.Scan(&category.CategoryId, &category.Name, &category.ParentId); err != nil {
+			log.Errorf(ctx, "[mmex_data_reader.readCategoriesByQuery] cannot read category, because %s", err.Error())
+			return errs.ErrInvalidMoneyManagerExFile
+		}
+
+		categories[category.CategoryId] = category
+	}
+
+	return rows.Err()
+}
+
+func (r *mmexDataReader) readPayees(ctx core.Context, conn *sql.Conn) (map[int64]string, error) {
+	payees := make(map[int64]string)
+
+	if !r.hasTable(ctx, conn, "PAYEE_V1") {
+		return payees, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT PAYEEID, COALESCE(PAYEENAME, '') FROM PAYEE_V1")
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.readPayees] cannot query payees, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var payeeId int64
+		var payeeName string
+
+		if err := rows.Scan(&payeeId, &payeeName); err != nil {
+			log.Errorf(ctx, "[mmex_data_reader.readPayees] cannot read payee, because %s", err.Error())
+			return nil, errs.ErrInvalidMoneyManagerExFile
+		}
+
+		payees[payeeId] = payeeName
+	}
+
+	return payees, rows.Err()
+}
+
+func (r *mmexDataReader) readTransactions(ctx core.Context, conn *sql.Conn) ([]*mmexTransaction, error) {
+	subCategoryIdColumn := "-1"
+	condition := ""
+
+	if r.hasColumn(ctx, conn, "CHECKINGACCOUNT_V1", "SUBCATEGID") {
+		subCategoryIdColumn = "COALESCE(SUBCATEGID, -1)"
+	}
+
+	if r.hasColumn(ctx, conn, "CHECKINGACCOUNT_V1", "DELETEDTIME") {
+		condition = " WHERE DELETEDTIME IS NULL OR DELETEDTIME = ''"
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT TRANSID, ACCOUNTID, COALESCE(TOACCOUNTID, -1), COALESCE(PAYEEID, -1), COALESCE(TRANSCODE, ''), COALESCE(TRANSAMOUNT, 0), COALESCE(TOTRANSAMOUNT, 0), COALESCE(STATUS, ''), COALESCE(NOTES, ''), COALESCE(CATEGID, -1), "+subCategoryIdColumn+", COALESCE(TRANSDATE, '') FROM CHECKINGACCOUNT_V1"+condition+" ORDER BY TRANSDATE, TRANSID")
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.readTransactions] cannot query transactions, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	defer rows.Close()
+
+	transactions := make([]*mmexTransaction, 0)
+
+	for rows.Next() {
+		transaction := &mmexTransaction{}
+
+		if err := rows.Scan(&transaction.TransactionId, &transaction.AccountId, &transaction.ToAccountId, &transaction.PayeeId, &transaction.TransactionCode, &transaction.Amount, &transaction.ToAmount, &transaction.Status, &transaction.Notes, &transaction.CategoryId, &transaction.SubCategoryId, &transaction.TransactionDate); err != nil {
+			log.Errorf(ctx, "[mmex_data_reader.readTransactions] cannot read transaction, because %s", err.Error())
+			return nil, errs.ErrInvalidMoneyManagerExFile
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *mmexDataReader) readSplitTransactions(ctx core.Context, conn *sql.Conn) (map[int64][]*mmexSplitTransaction, error) {
+	splitTransactions := make(map[int64][]*mmexSplitTransaction)
+
+	if !r.hasTable(ctx, conn, "SPLITTRANSACTIONS_V1") {
+		return splitTransactions, nil
+	}
+
+	subCategoryIdColumn := "-1"
+	notesColumn := "''"
+
+	if r.hasColumn(ctx, conn, "SPLITTRANSACTIONS_V1", "SUBCATEGID") {
+		subCategoryIdColumn = "COALESCE(SUBCATEGID, -1)"
+	}
+
+	if r.hasColumn(ctx, conn, "SPLITTRANSACTIONS_V1", "NOTES") {
+		notesColumn = "COALESCE(NOTES, '')"
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT SPLITTRANSID, TRANSID, COALESCE(CATEGID, -1), "+subCategoryIdColumn+", COALESCE(SPLITTRANSAMOUNT, 0), "+notesColumn+" FROM SPLITTRANSACTIONS_V1 ORDER BY SPLITTRANSID")
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.readSplitTransactions] cannot query split transactions, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		splitTransaction := &mmexSplitTransaction{}
+
+		if err := rows.Scan(&splitTransaction.SplitTransactionId, &splitTransaction.TransactionId, &splitTransaction.CategoryId, &splitTransaction.SubCategoryId, &splitTransaction.Amount, &splitTransaction.Notes); err != nil {
+			log.Errorf(ctx, "[mmex_data_reader.readSplitTransactions] cannot read split transaction, because %s", err.Error())
+			return nil, errs.ErrInvalidMoneyManagerExFile
+		}
+
+		splitTransactions[splitTransaction.TransactionId] = append(splitTransactions[splitTransaction.TransactionId], splitTransaction)
+	}
+
+	return splitTransactions, rows.Err()
+}
+
+func (r *mmexDataReader) readTransactionTags(ctx core.Context, conn *sql.Conn) (map[int64][]string, error) {
+	transactionTags := make(map[int64][]string)
+
+	// tags are supported since v1.8.0
+	if !r.hasTable(ctx, conn, "TAGS_V1") || !r.hasTable(ctx, conn, "TAGLINK_V1") {
+		return transactionTags, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT l.REFID, COALESCE(t.TAGNAME, '') FROM TAGLINK_V1 l INNER JOIN TAGS_V1 t ON l.TAGID = t.TAGID WHERE l.REFTYPE = 'Transaction' ORDER BY l.TAGLINKID")
+
+	if err != nil {
+		log.Errorf(ctx, "[mmex_data_reader.readTransactionTags] cannot query transaction tags, because %s", err.Error())
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var transactionId int64
+		var tagName string
+
+		if err := rows.Scan(&transactionId, &tagName); err != nil {
+			log.Errorf(ctx, "[mmex_data_reader.readTransactionTags] cannot read transaction tag, because %s", err.Error())
+			return nil, errs.ErrInvalidMoneyManagerExFile
+		}
+
+		if tagName != "" {
+			transactionTags[transactionId] = append(transactionTags[transactionId], tagName)
+		}
+	}
+
+	return transactionTags, rows.Err()
+}
+
+func (r *mmexDataReader) hasTable(ctx core.Context, conn *sql.Conn, tableName string) bool {
+	var count int
+	err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&count)
+
+	return err == nil && count > 0
+}
+
+func (r *mmexDataReader) hasColumn(ctx core.Context, conn *sql.Conn, tableName string, columnName string) bool {
+	var count int
+	err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", tableName, columnName).Scan(&count)
+
+	return err == nil && count > 0
+}
+
+func createNewMmexDataReader(data []byte) (*mmexDataReader, error) {
+	if len(data) < len(sqliteDatabaseFileHeader) || !bytes.Equal(data[0:len(sqliteDatabaseFileHeader)], []byte(sqliteDatabaseFileHeader)) {
+		return nil, errs.ErrInvalidMoneyManagerExFile
+	}
+
+	return &mmexDataReader{
+		data: data,
+	}, nil
+}
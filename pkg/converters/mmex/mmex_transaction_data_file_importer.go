@@ -0,0 +1,268 @@
+package mmex
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mmexTransactionTagSeparator = "\n"
+
+var mmexTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_MODIFY_BALANCE: "Initial Balance",
+	models.TRANSACTION_TYPE_INCOME:         MMEX_TRANSACTION_CODE_DEPOSIT,
+	models.TRANSACTION_TYPE_EXPENSE:        MMEX_TRANSACTION_CODE_WITHDRAWAL,
+	models.TRANSACTION_TYPE_TRANSFER:       MMEX_TRANSACTION_CODE_TRANSFER,
+}
+
+// mmexTransactionDataFileImporter defines the structure of money manager ex database importer for transaction data
+type mmexTransactionDataFileImporter struct{}
+
+// Initialize a money manager ex transaction data file importer singleton instance
+var (
+	MoneyManagerExTransactionDataFileImporter = &mmexTransactionDataFileImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing the money manager ex database file (.mmb)
+func (c *mmexTransactionDataFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	mmexDataReader, err := createNewMmexDataReader(data)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	mmexData, err := mmexDataReader.read(ctx)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	transactionDataTable, err := c.createNewMmexTransactionDataTable(ctx, mmexData)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	dataTableImporter := converter.CreateNewImporterWithTypeNameMapping(mmexTransactionTypeNameMapping, "", "", mmexTransactionTagSeparator)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
+
+func (c *mmexTransactionDataFileImporter) createNewMmexTransactionDataTable(ctx core.Context, mmexData *mmexData) (datatable.TransactionDataTable, error) {
+	transactionDataTable := datatable.CreateNewWritableTransactionDataTable([]datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+		datatable.TRANSACTION_DATA_TABLE_CATEGORY,
+		datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY,
+		datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY,
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+		datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY,
+		datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT,
+		datatable.TRANSACTION_DATA_TABLE_TAGS,
+		datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+		datatable.TRANSACTION_DATA_TABLE_PAYEE,
+	})
+
+	accountEarliestTransactionTimes := make(map[int64]string, len(mmexData.Accounts))
+	allTransactionRows := make([]map[datatable.TransactionDataTableColumn]string, 0, len(mmexData.Transactions))
+
+	for i := 0; i < len(mmexData.Transactions); i++ {
+		transaction := mmexData.Transactions[i]
+
+		if transaction.Status == MMEX_TRANSACTION_STATUS_VOID {
+			continue
+		}
+
+		transactionTime, err := parseMmexTransactionTime(transaction.TransactionDate)
+
+		if err != nil {
+			log.Errorf(ctx, "[mmex_transaction_data_file_importer.createNewMmexTransactionDataTable] cannot parse time \"%s\" of transaction#%d", transaction.TransactionDate, transaction.TransactionId)
+			return nil, err
+		}
+
+		account, exists := mmexData.Accounts[transaction.AccountId]
+
+		if !exists {
+			log.Errorf(ctx, "[mmex_transaction_data_file_importer.createNewMmexTransactionDataTable] cannot find account#%d of transaction#%d", transaction.AccountId, transaction.TransactionId)
+			return nil, errs.ErrMissingAccountData
+		}
+
+		if earliestTime, exists := accountEarliestTransactionTimes[account.AccountId]; !exists || transactionTime < earliestTime {
+			accountEarliestTransactionTimes[account.AccountId] = transactionTime
+		}
+
+		data := make(map[datatable.TransactionDataTableColumn]string, 13)
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = account.Name
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = account.Currency
+		data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(mmexData.TransactionTags[transaction.TransactionId], mmexTransactionTagSeparator)
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = transaction.Notes
+
+		if transaction.TransactionCode == MMEX_TRANSACTION_CODE_TRANSFER {
+			relatedAccount, exists := mmexData.Accounts[transaction.ToAccountId]
+
+			if !exists {
+				log.Errorf(ctx, "[mmex_transaction_data_file_importer.createNewMmexTransactionDataTable] cannot find related account#%d of transaction#%d", transaction.ToAccountId, transaction.TransactionId)
+				return nil, errs.ErrMissingAccountData
+			}
+
+			if earliestTime, exists := accountEarliestTransactionTimes[relatedAccount.AccountId]; !exists || transactionTime < earliestTime {
+				accountEarliestTransactionTimes[relatedAccount.AccountId] = transactionTime
+			}
+
+			amount := convertMmexAmount(transaction.Amount)
+			relatedAmount := convertMmexAmount(transaction.ToAmount)
+
+			if relatedAmount == 0 {
+				relatedAmount = amount
+			}
+
+			parentCategoryName, categoryName := mmexData.GetCategoryNames(transaction.CategoryId, transaction.SubCategoryId)
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = mmexTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = parentCategoryName
+			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = categoryName
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = relatedAccount.Name
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = relatedAccount.Currency
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(relatedAmount)
+
+			allTransactionRows = append(allTransactionRows, data)
+			continue
+		}
+
+		if transaction.TransactionCode != MMEX_TRANSACTION_CODE_WITHDRAWAL && transaction.TransactionCode != MMEX_TRANSACTION_CODE_DEPOSIT {
+			log.Errorf(ctx, "[mmex_transaction_data_file_importer.createNewMmexTransactionDataTable] cannot parse transaction code \"%s\" of transaction#%d", transaction.TransactionCode, transaction.TransactionId)
+			return nil, errs.ErrTransactionTypeInvalid
+		}
+
+		if transaction.PayeeId > 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = mmexData.Payees[transaction.PayeeId]
+		}
+
+		splitTransactions := mmexData.SplitTransactions[transaction.TransactionId]
+
+		if len(splitTransactions) < 1 {
+			c.fillMmexIncomeOrExpenseTransactionData(mmexData, data, transaction.TransactionCode, transaction.CategoryId, transaction.SubCategoryId, transaction.Amount)
+			allTransactionRows = append(allTransactionRows, data)
+			continue
+		}
+
+		// each split of a transaction is imported as a separate transaction
+		for j := 0; j < len(splitTransactions); j++ {
+			splitTransaction := splitTransactions[j]
+			splitData := make(map[datatable.TransactionDataTableColumn]string, len(data))
+
+			for column, value := range data {
+				splitData[column] = value
+			}
+
+			if splitTransaction.Notes != "" {
+				splitData[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = splitTransaction.Notes
+			}
+
+			c.fillMmexIncomeOrExpenseTransactionData(mmexData, splitData, transaction.TransactionCode, splitTransaction.CategoryId, splitTransaction.SubCategoryId, splitTransaction.Amount)
+			allTransactionRows = append(allTransactionRows, splitData)
+		}
+	}
+
+	allAccountIds := make([]int64, 0, len(mmexData.Accounts))
+
+	for accountId := range mmexData.Accounts {
+		allAccountIds = append(allAccountIds, accountId)
+	}
+
+	sort.Slice(allAccountIds, func(i, j int) bool {
+		return allAccountIds[i] < allAccountIds[j]
+	})
+
+	for i := 0; i < len(allAccountIds); i++ {
+		account := mmexData.Accounts[allAccountIds[i]]
+		initialBalance := convertMmexAmount(account.InitialBalance)
+
+		if initialBalance == 0 {
+			continue
+		}
+
+		transactionTime, err := parseMmexTransactionTime(account.InitialDate)
+
+		if err != nil {
+			earliestTime, exists := accountEarliestTransactionTimes[account.AccountId]
+
+			if !exists {
+				log.Warnf(ctx, "[mmex_transaction_data_file_importer.createNewMmexTransactionDataTable] skip initial balance of account#%d, because the initial date is unknown", account.AccountId)
+				continue
+			}
+
+			transactionTime = earliestTime
+		}
+
+		data := make(map[datatable.TransactionDataTableColumn]string, 13)
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = mmexTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = account.Name
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = account.Currency
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(initialBalance)
+
+		transactionDataTable.Add(data)
+	}
+
+	for i := 0; i < len(allTransactionRows); i++ {
+		transactionDataTable.Add(allTransactionRows[i])
+	}
+
+	return transactionDataTable, nil
+}
+
+func (c *mmexTransactionDataFileImporter) fillMmexIncomeOrExpenseTransactionData(mmexData *mmexData, data map[datatable.TransactionDataTableColumn]string, transactionCode string, categoryId int64, subCategoryId int64, amount float64) {
+	parentCategoryName, categoryName := mmexData.GetCategoryNames(categoryId, subCategoryId)
+	finalAmount := convertMmexAmount(amount)
+	isIncome := transactionCode == MMEX_TRANSACTION_CODE_DEPOSIT
+
+	// negative amount (e.g. split of a withdrawal with refund) means the opposite direction
+	if finalAmount < 0 {
+		isIncome = !isIncome
+		finalAmount = -finalAmount
+	}
+
+	if isIncome {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = mmexTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = mmexTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = parentCategoryName
+	data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = categoryName
+	data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(finalAmount)
+}
+
+func parseMmexTransactionTime(date string) (string, error) {
+	// newer versions store the date and time as "YYYY-MM-DDTHH:mm:ss"
+	if len(date) >= 19 {
+		dateTime := strings.Replace(date[0:19], "T", " ", 1)
+
+		if utils.IsValidLongDateTimeFormat(dateTime) {
+			return dateTime, nil
+		}
+	}
+
+	if len(date) >= 10 && utils.IsValidLongDateFormat(date[0:10]) {
+		return date[0:10] + " 00:00:00", nil
+	}
+
+	return "", errs.ErrTransactionTimeInvalid
+}
+
+func convertMmexAmount(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,171 @@
+package mmex
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestMmexTransactionDataFileImporterParseImportedData_MinimumValidData(t *testing.T) {
+	converter := MoneyManagerExTransactionDataFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data := createTestMmexDatabaseFile(t, []string{
+		"INSERT INTO CURRENCYFORMATS_V1 (CURRENCYID, CURRENCY_SYMBOL) VALUES (1, 'USD'), (2, 'EUR')",
+		"INSERT INTO ACCOUNTLIST_V1 (ACCOUNTID, ACCOUNTNAME, INITIALBAL, CURRENCYID, INITIALDATE) VALUES (1, 'Checking', 100.5, 1, '2024-09-01'), (2, 'Savings', 0, 2, '')",
+		"INSERT INTO CATEGORY_V1 (CATEGID, CATEGNAME, PARENTID) VALUES (1, 'Food', -1), (2, 'Groceries', 1), (3, 'Salary', -1)",
+		"INSERT INTO PAYEE_V1 (PAYEEID, PAYEENAME) VALUES (1, 'Supermarket')",
+		"INSERT INTO CHECKINGACCOUNT_V1 (TRANSID, ACCOUNTID, TOACCOUNTID, PAYEEID, TRANSCODE, TRANSAMOUNT, STATUS, NOTES, CATEGID, TRANSDATE, TOTRANSAMOUNT) VALUES " +
+			"(1, 1, -1, -1, 'Deposit', 1000, 'R', 'Pay', 3, '2024-09-02T08:30:00', 0), " +
+			"(2, 1, -1, 1, 'Withdrawal', 12.34, '', 'Weekly', 2, '2024-09-03', 0), " +
+			"(3, 1, 2, -1, 'Transfer', 100, '', '', -1, '2024-09-04', 90), " +
+			"(4, 1, -1, 1, 'Withdrawal', 50, 'V', '', 2, '2024-09-05', 0)",
+		"INSERT INTO TAGS_V1 (TAGID, TAGNAME) VALUES (1, 'family')",
+		"INSERT INTO TAGLINK_V1 (TAGLINKID, REFTYPE, REFID, TAGID) VALUES (1, 'Transaction', 2, 1)",
+	})
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 1, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(10050), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-02 08:30:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(100000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Salary", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Pay", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1234), allNewTransactions[2].Amount)
+	assert.Equal(t, "Groceries", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Supermarket", allNewTransactions[2].OriginalPayeeName)
+	assert.Equal(t, []string{"family"}, allNewTransactions[2].OriginalTagNames)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(9000), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[3].OriginalDestinationAccountCurrency)
+}
+
+func TestMmexTransactionDataFileImporterParseImportedData_SplitTransaction(t *testing.T) {
+	converter := MoneyManagerExTransactionDataFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data := createTestMmexDatabaseFile(t, []string{
+		"INSERT INTO CURRENCYFORMATS_V1 (CURRENCYID, CURRENCY_SYMBOL) VALUES (1, 'USD')",
+		"INSERT INTO ACCOUNTLIST_V1 (ACCOUNTID, ACCOUNTNAME, INITIALBAL, CURRENCYID, INITIALDATE) VALUES (1, 'Checking', 0, 1, '')",
+		"INSERT INTO CATEGORY_V1 (CATEGID, CATEGNAME, PARENTID) VALUES (1, 'Food', -1), (2, 'Household', -1), (3, 'Refund', -1)",
+		"INSERT INTO CHECKINGACCOUNT_V1 (TRANSID, ACCOUNTID, TOACCOUNTID, PAYEEID, TRANSCODE, TRANSAMOUNT, STATUS, NOTES, CATEGID, TRANSDATE, TOTRANSAMOUNT) VALUES " +
+			"(1, 1, -1, -1, 'Withdrawal', 25, '', 'Shopping', -1, '2024-09-03', 0)",
+		"INSERT INTO SPLITTRANSACTIONS_V1 (SPLITTRANSID, TRANSID, CATEGID, SPLITTRANSAMOUNT, NOTES) VALUES (1, 1, 1, 20, ''), (2, 1, 2, 10, 'Soap'), (3, 1, 3, -5, '')",
+	})
+
+	allNewTransactions, _, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(500), allNewTransactions[0].Amount)
+	assert.Equal(t, "Refund", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(2000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Food", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Shopping", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Household", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Soap", allNewTransactions[2].Comment)
+}
+
+func TestMmexTransactionDataFileImporterParseImportedData_InvalidFile(t *testing.T) {
+	converter := MoneyManagerExTransactionDataFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Date,Amount\n2024-09-01,1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidMoneyManagerExFile.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("SQLite format 3\x00invalid"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidMoneyManagerExFile.Message)
+}
+
+func createTestMmexDatabaseFile(t *testing.T, statements []string) []byte {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	allStatements := append([]string{
+		"CREATE TABLE CURRENCYFORMATS_V1 (CURRENCYID INTEGER PRIMARY KEY, CURRENCY_SYMBOL TEXT)",
+		"CREATE TABLE ACCOUNTLIST_V1 (ACCOUNTID INTEGER PRIMARY KEY, ACCOUNTNAME TEXT, INITIALBAL NUMERIC, CURRENCYID INTEGER, INITIALDATE TEXT)",
+		"CREATE TABLE CATEGORY_V1 (CATEGID INTEGER PRIMARY KEY, CATEGNAME TEXT, PARENTID INTEGER)",
+		"CREATE TABLE PAYEE_V1 (PAYEEID INTEGER PRIMARY KEY, PAYEENAME TEXT)",
+		"CREATE TABLE CHECKINGACCOUNT_V1 (TRANSID INTEGER PRIMARY KEY, ACCOUNTID INTEGER, TOACCOUNTID INTEGER, PAYEEID INTEGER, TRANSCODE TEXT, TRANSAMOUNT NUMERIC, STATUS TEXT, NOTES TEXT, CATEGID INTEGER, TRANSDATE TEXT, TOTRANSAMOUNT NUMERIC, DELETEDTIME TEXT)",
+		"CREATE TABLE SPLITTRANSACTIONS_V1 (SPLITTRANSID INTEGER PRIMARY KEY, TRANSID INTEGER, CATEGID INTEGER, SPLITTRANSAMOUNT NUMERIC, NOTES TEXT)",
+		"CREATE TABLE TAGS_V1 (TAGID INTEGER PRIMARY KEY, TAGNAME TEXT)",
+		"CREATE TABLE TAGLINK_V1 (TAGLINKID INTEGER PRIMARY KEY, REFTYPE TEXT, REFID INTEGER, TAGID INTEGER)",
+	}, statements...)
+
+	for i := 0; i < len(allStatements); i++ {
+		_, err = db.Exec(allStatements[i])
+		assert.Nil(t, err)
+	}
+
+	conn, err := db.Conn(core.NewNullContext())
+	assert.Nil(t, err)
+
+	defer conn.Close()
+
+	var data []byte
+
+	err = conn.Raw(func(driverConn any) error {
+		data, err = driverConn.(*sqlite3.SQLiteConn).Serialize("main")
+		return err
+	})
+	assert.Nil(t, err)
+
+	return data
+}
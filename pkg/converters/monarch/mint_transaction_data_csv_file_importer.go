@@ -0,0 +1,120 @@
+package monarch
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/csv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mintTransactionDateColumnName = "Date"
+const mintTransactionDescriptionColumnName = "Description"
+const mintTransactionAmountColumnName = "Amount"
+const mintTransactionTypeColumnName = "Transaction Type"
+const mintTransactionCategoryColumnName = "Category"
+const mintTransactionAccountNameColumnName = "Account Name"
+const mintTransactionNotesColumnName = "Notes"
+
+const mintTransactionTypeDebit = "debit"
+
+// mintTransactionDataCsvFileImporter defines the structure of mint csv importer for transaction data
+type mintTransactionDataCsvFileImporter struct{}
+
+// Initialize a mint transaction data csv file importer singleton instance
+var (
+	MintTransactionDataCsvFileImporter = &mintTransactionDataCsvFileImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing the mint transaction csv data
+func (c *mintTransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	fallback := unicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+
+	csvDataTable, err := csv.CreateNewCsvBasicDataTable(ctx, reader, true)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	commonDataTable := datatable.CreateNewCommonDataTableFromBasicDataTable(csvDataTable)
+
+	if !commonDataTable.HasColumn(mintTransactionDateColumnName) ||
+		!commonDataTable.HasColumn(mintTransactionDescriptionColumnName) ||
+		!commonDataTable.HasColumn(mintTransactionAmountColumnName) ||
+		!commonDataTable.HasColumn(mintTransactionTypeColumnName) ||
+		!commonDataTable.HasColumn(mintTransactionCategoryColumnName) ||
+		!commonDataTable.HasColumn(mintTransactionAccountNameColumnName) {
+		log.Errorf(ctx, "[mint_transaction_data_csv_file_importer.ParseImportedData] cannot parse import data, because missing essential columns in header row")
+		return nil, nil, nil, nil, nil, nil, errs.ErrMissingRequiredFieldInHeaderRow
+	}
+
+	allTransactions := make([]*monarchTransactionData, 0, commonDataTable.DataRowCount())
+	commonDataTableIterator := commonDataTable.DataRowIterator()
+
+	for commonDataTableIterator.HasNext() {
+		dataRow := commonDataTableIterator.Next()
+		rowId := commonDataTableIterator.CurrentRowId()
+
+		if dataRow.ColumnCount() < commonDataTable.HeaderColumnCount() {
+			log.Errorf(ctx, "[mint_transaction_data_csv_file_importer.ParseImportedData] cannot parse row \"%s\", because may missing some columns (column count %d in data row is less than header column count %d)", rowId, dataRow.ColumnCount(), commonDataTable.HeaderColumnCount())
+			return nil, nil, nil, nil, nil, nil, errs.ErrFewerFieldsInDataRowThanInHeaderRow
+		}
+
+		date := dataRow.GetData(mintTransactionDateColumnName)
+
+		if !utils.IsValidMonthDayYearLongOrShortDateFormat(date) {
+			log.Errorf(ctx, "[mint_transaction_data_csv_file_importer.ParseImportedData] cannot parse date \"%s\" in row \"%s\"", date, rowId)
+			return nil, nil, nil, nil, nil, nil, errs.ErrTransactionTimeInvalid
+		}
+
+		dateParts := strings.Split(date, "/")
+
+		if len(dateParts) != 3 {
+			log.Errorf(ctx, "[mint_transaction_data_csv_file_importer.ParseImportedData] cannot parse date \"%s\" in row \"%s\"", date, rowId)
+			return nil, nil, nil, nil, nil, nil, errs.ErrTransactionTimeInvalid
+		}
+
+		transactionTime, err := utils.FormatYearMonthDayToLongDateTime(dateParts[2], dateParts[0], dateParts[1])
+
+		if err != nil {
+			log.Errorf(ctx, "[mint_transaction_data_csv_file_importer.ParseImportedData] cannot parse date \"%s\" in row \"%s\", because %s", date, rowId, err.Error())
+			return nil, nil, nil, nil, nil, nil, errs.ErrTransactionTimeInvalid
+		}
+
+		amount, err := utils.ParseAmount(utils.TrimTrailingZerosInDecimal(strings.ReplaceAll(dataRow.GetData(mintTransactionAmountColumnName), ",", "")))
+
+		if err != nil {
+			log.Errorf(ctx, "[mint_transaction_data_csv_file_importer.ParseImportedData] cannot parse amount \"%s\" in row \"%s\", because %s", dataRow.GetData(mintTransactionAmountColumnName), rowId, err.Error())
+			return nil, nil, nil, nil, nil, nil, errs.ErrAmountInvalid
+		}
+
+		// the amount in mint is always positive, and the direction is in transaction type column
+		if strings.ToLower(dataRow.GetData(mintTransactionTypeColumnName)) == mintTransactionTypeDebit {
+			amount = -amount
+		}
+
+		allTransactions = append(allTransactions, &monarchTransactionData{
+			transactionTime: transactionTime,
+			payee:           dataRow.GetData(mintTransactionDescriptionColumnName),
+			category:        dataRow.GetData(mintTransactionCategoryColumnName),
+			accountName:     dataRow.GetData(mintTransactionAccountNameColumnName),
+			notes:           dataRow.GetData(mintTransactionNotesColumnName),
+			amount:          amount,
+		})
+	}
+
+	transactionDataTable := createNewMonarchTransactionDataTable(allTransactions)
+	dataTableImporter := converter.CreateNewImporterWithTypeNameMapping(monarchTransactionTypeNameMapping, "", "", monarchTransactionTagSeparator)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
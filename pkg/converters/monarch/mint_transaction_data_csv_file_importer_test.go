@@ -0,0 +1,70 @@
+package monarch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestMintTransactionDataCsvFileImporterParseImportedData_MinimumValidData(t *testing.T) {
+	converter := MintTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"\"Date\",\"Description\",\"Original Description\",\"Amount\",\"Transaction Type\",\"Category\",\"Account Name\",\"Labels\",\"Notes\"\n"+
+			"\"9/01/2024\",\"Employer\",\"PAYROLL\",\"1,250.00\",\"credit\",\"Paycheck\",\"Checking\",\"\",\"\"\n"+
+			"\"9/02/2024\",\"Grocery Store\",\"GROCERY #1\",\"45.67\",\"debit\",\"Groceries\",\"Credit Card\",\"\",\"Weekly\"\n"+
+			"\"9/03/2024\",\"Transfer to Savings\",\"XFER\",\"200.00\",\"debit\",\"Transfer\",\"Checking\",\"\",\"\"\n"+
+			"\"9/03/2024\",\"Transfer from Checking\",\"XFER\",\"200.00\",\"credit\",\"Transfer\",\"Savings\",\"\",\"\"\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(125000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Employer", allNewTransactions[0].OriginalPayeeName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(4567), allNewTransactions[1].Amount)
+	assert.Equal(t, "Groceries", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Weekly", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(20000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[2].OriginalDestinationAccountName)
+}
+
+func TestMintTransactionDataCsvFileImporterParseImportedData_InvalidData(t *testing.T) {
+	converter := MintTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"\"Date\",\"Description\",\"Amount\",\"Category\",\"Account Name\"\n"+
+			"\"9/01/2024\",\"Employer\",\"1250.00\",\"Paycheck\",\"Checking\"\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"\"Date\",\"Description\",\"Amount\",\"Transaction Type\",\"Category\",\"Account Name\"\n"+
+			"\"2024-09-01\",\"Employer\",\"1250.00\",\"credit\",\"Paycheck\",\"Checking\"\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
@@ -0,0 +1,113 @@
+package monarch
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/csv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const monarchTransactionDateColumnName = "Date"
+const monarchTransactionMerchantColumnName = "Merchant"
+const monarchTransactionCategoryColumnName = "Category"
+const monarchTransactionAccountColumnName = "Account"
+const monarchTransactionNotesColumnName = "Notes"
+const monarchTransactionAmountColumnName = "Amount"
+const monarchTransactionTagsColumnName = "Tags"
+
+// monarchTransactionDataCsvFileImporter defines the structure of monarch money csv importer for transaction data
+type monarchTransactionDataCsvFileImporter struct{}
+
+// Initialize a monarch money transaction data csv file importer singleton instance
+var (
+	MonarchTransactionDataCsvFileImporter = &monarchTransactionDataCsvFileImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing the monarch money transaction csv data
+func (c *monarchTransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	fallback := unicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+
+	csvDataTable, err := csv.CreateNewCsvBasicDataTable(ctx, reader, true)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	commonDataTable := datatable.CreateNewCommonDataTableFromBasicDataTable(csvDataTable)
+
+	if !commonDataTable.HasColumn(monarchTransactionDateColumnName) ||
+		!commonDataTable.HasColumn(monarchTransactionMerchantColumnName) ||
+		!commonDataTable.HasColumn(monarchTransactionCategoryColumnName) ||
+		!commonDataTable.HasColumn(monarchTransactionAccountColumnName) ||
+		!commonDataTable.HasColumn(monarchTransactionAmountColumnName) {
+		log.Errorf(ctx, "[monarch_transaction_data_csv_file_importer.ParseImportedData] cannot parse import data, because missing essential columns in header row")
+		return nil, nil, nil, nil, nil, nil, errs.ErrMissingRequiredFieldInHeaderRow
+	}
+
+	allTransactions := make([]*monarchTransactionData, 0, commonDataTable.DataRowCount())
+	commonDataTableIterator := commonDataTable.DataRowIterator()
+
+	for commonDataTableIterator.HasNext() {
+		dataRow := commonDataTableIterator.Next()
+		rowId := commonDataTableIterator.CurrentRowId()
+
+		if dataRow.ColumnCount() < commonDataTable.HeaderColumnCount() {
+			log.Errorf(ctx, "[monarch_transaction_data_csv_file_importer.ParseImportedData] cannot parse row \"%s\", because may missing some columns (column count %d in data row is less than header column count %d)", rowId, dataRow.ColumnCount(), commonDataTable.HeaderColumnCount())
+			return nil, nil, nil, nil, nil, nil, errs.ErrFewerFieldsInDataRowThanInHeaderRow
+		}
+
+		date := dataRow.GetData(monarchTransactionDateColumnName)
+
+		if !utils.IsValidLongDateFormat(date) {
+			log.Errorf(ctx, "[monarch_transaction_data_csv_file_importer.ParseImportedData] cannot parse date \"%s\" in row \"%s\"", date, rowId)
+			return nil, nil, nil, nil, nil, nil, errs.ErrTransactionTimeInvalid
+		}
+
+		amount, err := utils.ParseAmount(utils.TrimTrailingZerosInDecimal(strings.ReplaceAll(dataRow.GetData(monarchTransactionAmountColumnName), ",", "")))
+
+		if err != nil {
+			log.Errorf(ctx, "[monarch_transaction_data_csv_file_importer.ParseImportedData] cannot parse amount \"%s\" in row \"%s\", because %s", dataRow.GetData(monarchTransactionAmountColumnName), rowId, err.Error())
+			return nil, nil, nil, nil, nil, nil, errs.ErrAmountInvalid
+		}
+
+		var tags []string
+
+		if dataRow.HasData(monarchTransactionTagsColumnName) {
+			tagNames := strings.Split(dataRow.GetData(monarchTransactionTagsColumnName), monarchTransactionTagSeparator)
+
+			for i := 0; i < len(tagNames); i++ {
+				tagName := strings.TrimSpace(tagNames[i])
+
+				if tagName != "" {
+					tags = append(tags, tagName)
+				}
+			}
+		}
+
+		allTransactions = append(allTransactions, &monarchTransactionData{
+			transactionTime: date + " 00:00:00",
+			payee:           dataRow.GetData(monarchTransactionMerchantColumnName),
+			category:        dataRow.GetData(monarchTransactionCategoryColumnName),
+			accountName:     dataRow.GetData(monarchTransactionAccountColumnName),
+			notes:           dataRow.GetData(monarchTransactionNotesColumnName),
+			tags:            tags,
+			amount:          amount,
+		})
+	}
+
+	transactionDataTable := createNewMonarchTransactionDataTable(allTransactions)
+	dataTableImporter := converter.CreateNewImporterWithTypeNameMapping(monarchTransactionTypeNameMapping, "", "", monarchTransactionTagSeparator)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
@@ -0,0 +1,88 @@
+package monarch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestMonarchTransactionDataCsvFileImporterParseImportedData_MinimumValidData(t *testing.T) {
+	converter := MonarchTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		"Date,Merchant,Category,Account,Original Statement,Notes,Amount,Tags\n"+
+			"2024-09-01,Employer,Paychecks,Checking,PAYROLL,,2500.00,\n"+
+			"2024-09-02,Coffee Shop,Coffee Shops,Credit Card,COFFEE #123,Latte,-4.50,\"Work, Daily\"\n"+
+			"2024-09-03,Credit Card Payment,Credit Card Payment,Checking,PAYMENT,,-100.00,\n"+
+			"2024-09-03,Payment Thank You,Credit Card Payment,Credit Card,THANK YOU,,100.00,\n"+
+			"2024-09-04,Transfer,Transfer,Checking,XFER,,-50.00,\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 2, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(250000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Paychecks", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, "Employer", allNewTransactions[0].OriginalPayeeName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(450), allNewTransactions[1].Amount)
+	assert.Equal(t, "Credit Card", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, []string{"Work", "Daily"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "Latte", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Credit Card", allNewTransactions[2].OriginalDestinationAccountName)
+	assert.Equal(t, "", allNewTransactions[2].OriginalPayeeName)
+
+	// the transfer without related transaction is imported as an expense
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, int64(5000), allNewTransactions[3].Amount)
+	assert.Equal(t, "Transfer", allNewTransactions[3].OriginalCategoryName)
+}
+
+func TestMonarchTransactionDataCsvFileImporterParseImportedData_InvalidData(t *testing.T) {
+	converter := MonarchTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"Date,Merchant,Account,Amount\n"+
+			"2024-09-01,Employer,Checking,2500.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"Date,Merchant,Category,Account,Amount\n"+
+			"09/01/2024,Employer,Paychecks,Checking,2500.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"Date,Merchant,Category,Account,Amount\n"+
+			"2024-09-01,Employer,Paychecks,Checking,2500.001\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
@@ -0,0 +1,127 @@
+package monarch
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const monarchTransactionTagSeparator = ","
+
+var monarchTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_INCOME:   "Income",
+	models.TRANSACTION_TYPE_EXPENSE:  "Expense",
+	models.TRANSACTION_TYPE_TRANSFER: "Transfer",
+}
+
+// the transactions in these categories are one side of transfers between two accounts
+var monarchTransferCategoryNames = map[string]bool{
+	"transfer":            true,
+	"transfers":           true,
+	"credit card payment": true,
+}
+
+// monarchTransactionData defines the structure of a parsed row of monarch (or mint) csv data
+type monarchTransactionData struct {
+	transactionTime string
+	payee           string
+	category        string
+	accountName     string
+	notes           string
+	tags            []string
+	amount          int64
+}
+
+// isTransfer returns whether the transaction is one side of a transfer
+func (t *monarchTransactionData) isTransfer() bool {
+	return monarchTransferCategoryNames[strings.ToLower(t.category)]
+}
+
+// createNewMonarchTransactionDataTable returns the transaction data table of all the monarch (or mint) transactions,
+// both sides of a transfer are exported as two transactions in different accounts, so they are merged into one transfer transaction
+func createNewMonarchTransactionDataTable(allTransactions []*monarchTransactionData) datatable.TransactionDataTable {
+	transactionDataTable := datatable.CreateNewWritableTransactionDataTable([]datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+		datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY,
+		datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+		datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_TAGS,
+		datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+		datatable.TRANSACTION_DATA_TABLE_PAYEE,
+	})
+
+	merged := make([]bool, len(allTransactions))
+
+	for i := 0; i < len(allTransactions); i++ {
+		if merged[i] {
+			continue
+		}
+
+		transaction := allTransactions[i]
+
+		data := make(map[datatable.TransactionDataTableColumn]string, 9)
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transaction.transactionTime
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = transaction.accountName
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = transaction.category
+		data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.Join(transaction.tags, monarchTransactionTagSeparator)
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = transaction.notes
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = transaction.payee
+
+		relatedTransactionIndex := -1
+
+		if transaction.isTransfer() {
+			relatedTransactionIndex = findMonarchRelatedTransferTransaction(allTransactions, merged, i)
+		}
+
+		if relatedTransactionIndex >= 0 {
+			relatedTransaction := allTransactions[relatedTransactionIndex]
+			merged[relatedTransactionIndex] = true
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = monarchTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+
+			if transaction.amount < 0 {
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = relatedTransaction.accountName
+				data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-transaction.amount)
+			} else {
+				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = relatedTransaction.accountName
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = transaction.accountName
+				data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(transaction.amount)
+			}
+		} else if transaction.amount >= 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = monarchTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(transaction.amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = monarchTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-transaction.amount)
+		}
+
+		transactionDataTable.Add(data)
+	}
+
+	return transactionDataTable
+}
+
+func findMonarchRelatedTransferTransaction(allTransactions []*monarchTransactionData, merged []bool, index int) int {
+	transaction := allTransactions[index]
+
+	for i := index + 1; i < len(allTransactions); i++ {
+		relatedTransaction := allTransactions[i]
+
+		if merged[i] || !relatedTransaction.isTransfer() {
+			continue
+		}
+
+		if relatedTransaction.transactionTime == transaction.transactionTime &&
+			relatedTransaction.amount == -transaction.amount &&
+			relatedTransaction.accountName != transaction.accountName {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -15,15 +15,19 @@ const (
 	MT_INFORMATION_TO_ACCOUNT_OWNER_TAG_REMITTANCE string = "REMI"
 )
 
-// mt940Data defines the structure of mt940 data
+// mt940Data defines the structure of mt940 (or mt942) data
 type mt940Data struct {
 	StatementReferenceNumber string
 	RelatedReference         string
 	AccountId                string
 	SequentialNumber         string
+	DateTimeIndication       string
+	FloorLimitIndicators     []*mtFloorLimitIndicator
 	OpeningBalance           *mtBalance
 	ClosingBalance           *mtBalance
 	ClosingAvailableBalance  *mtBalance
+	DebitEntriesSummary      *mtEntriesSummary
+	CreditEntriesSummary     *mtEntriesSummary
 	Statements               []*mtStatement
 }
 
@@ -48,6 +52,37 @@ type mtBalance struct {
 	Amount          string
 }
 
+// mtFloorLimitIndicator defines the structure of mt942 floor limit indicator
+type mtFloorLimitIndicator struct {
+	Currency        string
+	DebitCreditMark mtCreditDebitMark
+	Amount          string
+}
+
+// mtEntriesSummary defines the structure of mt942 number and sum of debit or credit entries
+type mtEntriesSummary struct {
+	Count    string
+	Currency string
+	Amount   string
+}
+
+// GetCurrency returns the account currency of the mt940 (or mt942) data
+func (d *mt940Data) GetCurrency() string {
+	if d.OpeningBalance != nil && d.OpeningBalance.Currency != "" {
+		return d.OpeningBalance.Currency
+	} else if d.ClosingBalance != nil && d.ClosingBalance.Currency != "" {
+		return d.ClosingBalance.Currency
+	} else if len(d.FloorLimitIndicators) > 0 && d.FloorLimitIndicators[0].Currency != "" {
+		return d.FloorLimitIndicators[0].Currency
+	} else if d.DebitEntriesSummary != nil && d.DebitEntriesSummary.Currency != "" {
+		return d.DebitEntriesSummary.Currency
+	} else if d.CreditEntriesSummary != nil && d.CreditEntriesSummary.Currency != "" {
+		return d.CreditEntriesSummary.Currency
+	}
+
+	return ""
+}
+
 // GetInformationToAccountOwnerMap returns a map of additional information
 func (s *mtStatement) GetInformationToAccountOwnerMap() map[string]string {
 	additionalInfoMap := make(map[string]string, len(s.InformationToAccountOwner))
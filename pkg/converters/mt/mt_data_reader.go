@@ -24,11 +24,15 @@ const (
 	mtTagRelatedReference          = ":21:"
 	mtTagAccountId                 = ":25:"
 	mtTagSequentialNumber          = ":28C:"
+	mtTagDateTimeIndication        = ":13D:"
+	mtTagFloorLimitIndicator       = ":34F:"
 	mtTagOpeningBalanceF           = ":60F:"
 	mtTagOpeningBalanceM           = ":60M:"
 	mtTagClosingBalanceF           = ":62F:"
 	mtTagClosingBalanceM           = ":62M:"
 	mtTagClosingAvailableBalance   = ":64:"
+	mtTagNumberAndSumOfDebits      = ":90D:"
+	mtTagNumberAndSumOfCredits     = ":90C:"
 	mtTagStatementLine             = ":61:"
 	mtTagInformationToAccountOwner = ":86:"
 )
@@ -44,8 +48,9 @@ type mt940DataReader struct {
 	allLines []string
 }
 
-// read returns the imported mt940 data
+// read returns the imported mt940 (or mt942) data
 // Reference: https://www2.swift.com/knowledgecentre/publications/us9m_20230720/2.0?topic=mt940-format-spec.htm
+// Reference: https://www2.swift.com/knowledgecentre/publications/us9m_20230720/2.0?topic=mt942-format-spec.htm
 func (r *mt940DataReader) read(ctx core.Context) (*mt940Data, error) {
 	if len(r.allLines) < 1 {
 		return nil, errs.ErrNotFoundTransactionDataInFile
@@ -83,6 +88,32 @@ func (r *mt940DataReader) read(ctx core.Context) (*mt940Data, error) {
 		} else if strings.HasPrefix(line, mtTagSequentialNumber) {
 			data.SequentialNumber = line[len(mtTagSequentialNumber):]
 			lastTag = mtTagSequentialNumber
+		} else if strings.HasPrefix(line, mtTagDateTimeIndication) {
+			data.DateTimeIndication = line[len(mtTagDateTimeIndication):]
+			lastTag = mtTagDateTimeIndication
+		} else if strings.HasPrefix(line, mtTagFloorLimitIndicator) {
+			floorLimitIndicator, err := r.parseFloorLimitIndicator(ctx, line[len(mtTagFloorLimitIndicator):])
+
+			if err != nil {
+				return nil, err
+			}
+
+			data.FloorLimitIndicators = append(data.FloorLimitIndicators, floorLimitIndicator)
+			lastTag = mtTagFloorLimitIndicator
+		} else if strings.HasPrefix(line, mtTagNumberAndSumOfDebits) || strings.HasPrefix(line, mtTagNumberAndSumOfCredits) {
+			entriesSummary, err := r.parseEntriesSummary(ctx, line[len(mtTagNumberAndSumOfDebits):])
+
+			if err != nil {
+				return nil, err
+			}
+
+			if strings.HasPrefix(line, mtTagNumberAndSumOfDebits) {
+				data.DebitEntriesSummary = entriesSummary
+			} else {
+				data.CreditEntriesSummary = entriesSummary
+			}
+
+			lastTag = line[:len(mtTagNumberAndSumOfDebits)]
 		} else if strings.HasPrefix(line, mtTagOpeningBalanceF) || strings.HasPrefix(line, mtTagOpeningBalanceM) {
 			balance, err := r.parseBalance(ctx, line[len(mtTagOpeningBalanceF):])
 
@@ -169,6 +200,57 @@ func (r *mt940DataReader) parseBalance(ctx core.Context, data string) (*mtBalanc
 	return balance, nil
 }
 
+func (r *mt940DataReader) parseFloorLimitIndicator(ctx core.Context, data string) (*mtFloorLimitIndicator, error) {
+	// 3!a (currency)
+	// [1!a] (debit/credit mark, optional)
+	// 15d (amount)
+	if len(data) < 4 {
+		return nil, errs.ErrInvalidMT940File
+	}
+
+	floorLimitIndicator := &mtFloorLimitIndicator{
+		Currency: data[0:3],
+	}
+
+	if data[3] == MT_MARK_DEBIT[0] || data[3] == MT_MARK_CREDIT[0] {
+		floorLimitIndicator.DebitCreditMark = mtCreditDebitMark(data[3:4])
+		floorLimitIndicator.Amount = data[4:]
+	} else {
+		floorLimitIndicator.Amount = data[3:]
+	}
+
+	if len(floorLimitIndicator.Amount) < 1 {
+		log.Errorf(ctx, "[mt_data_reader.parseFloorLimitIndicator] cannot parse amount, current line is %s", data)
+		return nil, errs.ErrInvalidMT940File
+	}
+
+	return floorLimitIndicator, nil
+}
+
+func (r *mt940DataReader) parseEntriesSummary(ctx core.Context, data string) (*mtEntriesSummary, error) {
+	// 5n (number of entries)
+	// 3!a (currency)
+	// 15d (amount)
+	currentIndex := 0
+
+	for currentIndex < len(data) && currentIndex < 5 && '0' <= data[currentIndex] && data[currentIndex] <= '9' {
+		currentIndex++
+	}
+
+	if currentIndex < 1 || len(data) < currentIndex+4 {
+		log.Errorf(ctx, "[mt_data_reader.parseEntriesSummary] cannot parse number and sum of entries, current line is %s", data)
+		return nil, errs.ErrInvalidMT940File
+	}
+
+	entriesSummary := &mtEntriesSummary{
+		Count:    data[0:currentIndex],
+		Currency: data[currentIndex : currentIndex+3],
+		Amount:   data[currentIndex+3:],
+	}
+
+	return entriesSummary, nil
+}
+
 func (r *mt940DataReader) parseStatement(ctx core.Context, data string) (*mtStatement, error) {
 	// 6!n (value date)
 	// [4!n] (entry date, optional)
@@ -1,6 +1,7 @@
 package mt
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -339,3 +340,64 @@ func TestMT940DataReaderParseStatement_MissingField(t *testing.T) {
 	_, err = reader.parseStatement(context, "250601D234,56NTRF//ABC123456")
 	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
 }
+
+func TestMT940DataReaderParse_MT942Fields(t *testing.T) {
+	data, err := os.ReadFile("testdata/mt942_all_fields.txt")
+	assert.Nil(t, err)
+
+	reader := createNewMT940FileReader(data)
+	context := core.NewNullContext()
+
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "2506011230+0100", actualData.DateTimeIndication)
+	assert.Equal(t, 2, len(actualData.FloorLimitIndicators))
+	assert.Equal(t, "EUR", actualData.FloorLimitIndicators[0].Currency)
+	assert.Equal(t, MT_MARK_DEBIT, actualData.FloorLimitIndicators[0].DebitCreditMark)
+	assert.Equal(t, "100,00", actualData.FloorLimitIndicators[0].Amount)
+	assert.Equal(t, MT_MARK_CREDIT, actualData.FloorLimitIndicators[1].DebitCreditMark)
+	assert.Equal(t, "0", actualData.DebitEntriesSummary.Count)
+	assert.Equal(t, "EUR", actualData.DebitEntriesSummary.Currency)
+	assert.Equal(t, "1", actualData.CreditEntriesSummary.Count)
+	assert.Equal(t, "123,45", actualData.CreditEntriesSummary.Amount)
+	assert.Nil(t, actualData.OpeningBalance)
+	assert.Equal(t, "EUR", actualData.GetCurrency())
+
+	assert.Equal(t, 1, len(actualData.Statements))
+	assert.Equal(t, []string{"Transaction 1"}, actualData.Statements[0].InformationToAccountOwner)
+}
+
+func TestMT940DataReaderParseFloorLimitIndicator(t *testing.T) {
+	reader := &mt940DataReader{}
+	context := core.NewNullContext()
+
+	floorLimitIndicator, err := reader.parseFloorLimitIndicator(context, "CHF0,")
+	assert.Nil(t, err)
+	assert.Equal(t, "CHF", floorLimitIndicator.Currency)
+	assert.Equal(t, mtCreditDebitMark(""), floorLimitIndicator.DebitCreditMark)
+	assert.Equal(t, "0,", floorLimitIndicator.Amount)
+
+	_, err = reader.parseFloorLimitIndicator(context, "CHF")
+	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
+
+	_, err = reader.parseFloorLimitIndicator(context, "CHFD")
+	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
+}
+
+func TestMT940DataReaderParseEntriesSummary(t *testing.T) {
+	reader := &mt940DataReader{}
+	context := core.NewNullContext()
+
+	entriesSummary, err := reader.parseEntriesSummary(context, "12USD1234,56")
+	assert.Nil(t, err)
+	assert.Equal(t, "12", entriesSummary.Count)
+	assert.Equal(t, "USD", entriesSummary.Currency)
+	assert.Equal(t, "1234,56", entriesSummary.Amount)
+
+	_, err = reader.parseEntriesSummary(context, "USD1234,56")
+	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
+
+	_, err = reader.parseEntriesSummary(context, "1USD")
+	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
+}
@@ -13,16 +13,17 @@ var mt940TransactionTypeNameMapping = map[models.TransactionType]string{
 	models.TRANSACTION_TYPE_TRANSFER: utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER)),
 }
 
-// mt940TransactionDataFileImporter defines the structure of mt940 file importer for statement data
-type mt940TransactionDataFileImporter struct{}
+// mtTransactionDataFileImporter defines the structure of mt940 / mt942 file importer for statement data
+type mtTransactionDataFileImporter struct{}
 
-// Initialize a mt940 statement data importer singleton instance
+// Initialize a mt940 statement data importer and a mt942 interim transaction report importer singleton instance
 var (
-	MT940TransactionDataFileImporter = &mt940TransactionDataFileImporter{}
+	MT940TransactionDataFileImporter = &mtTransactionDataFileImporter{}
+	MT942TransactionDataFileImporter = &mtTransactionDataFileImporter{}
 )
 
-// ParseImportedData returns the imported data by parsing the mt940 file statement data
-func (c *mt940TransactionDataFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+// ParseImportedData returns the imported data by parsing the mt940 / mt942 file statement data
+func (c *mtTransactionDataFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
 	mt940DataReader := createNewMT940FileReader(data)
 	mt940Data, err := mt940DataReader.read(ctx)
 
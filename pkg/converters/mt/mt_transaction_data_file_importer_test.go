@@ -1,6 +1,7 @@
 package mt
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -212,3 +213,52 @@ func TestMT940TransactionDataFileParseImportedData_MissingRequiredField(t *testi
 		-}`), 0, nil, nil, nil, nil, nil)
 	assert.EqualError(t, err, errs.ErrAccountCurrencyInvalid.Message)
 }
+
+func TestMT942TransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	converter := MT942TransactionDataFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data, err := os.ReadFile("testdata/mt942_minimum_valid_data.txt")
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1748736000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Transaction 1", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(23456), allNewTransactions[1].Amount)
+	assert.Equal(t, "USD", allNewTransactions[1].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, "12345678", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+}
+
+func TestMT942TransactionDataFileParseImportedData_MissingCurrency(t *testing.T) {
+	converter := MT942TransactionDataFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data, err := os.ReadFile("testdata/mt942_missing_currency.txt")
+	assert.Nil(t, err)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAccountCurrencyInvalid.Message)
+}
@@ -118,14 +118,14 @@ func (t *mt940TransactionDataRowIterator) parseTransaction(ctx core.Context, use
 	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
 	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = mt940Data.AccountId
 
-	if mt940Data.OpeningBalance != nil && mt940Data.OpeningBalance.Currency != "" {
-		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = mt940Data.OpeningBalance.Currency
-	} else if mt940Data.ClosingBalance != nil && mt940Data.ClosingBalance.Currency != "" {
-		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = mt940Data.ClosingBalance.Currency
-	} else {
+	currency := mt940Data.GetCurrency()
+
+	if currency == "" {
 		return nil, errs.ErrAccountCurrencyInvalid
 	}
 
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = currency
+
 	amountValue := strings.ReplaceAll(statement.Amount, ",", ".") // decimal separator is comma in mt data
 
 	if len(amountValue) > 0 && amountValue[len(amountValue)-1] == '.' {
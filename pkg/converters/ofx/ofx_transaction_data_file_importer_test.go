@@ -546,6 +546,7 @@ func TestOFXTransactionDataFileParseImportedData_ParseDescription(t *testing.T)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(allNewTransactions))
 	assert.Equal(t, "foo    bar\t#test", allNewTransactions[0].Comment)
+	assert.Equal(t, "Test", allNewTransactions[0].OriginalPayeeName)
 
 	allNewTransactions, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
 		"<OFX>\n"+
@@ -572,6 +573,7 @@ func TestOFXTransactionDataFileParseImportedData_ParseDescription(t *testing.T)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(allNewTransactions))
 	assert.Equal(t, "Test", allNewTransactions[0].Comment)
+	assert.Equal(t, "Test", allNewTransactions[0].OriginalPayeeName)
 
 	allNewTransactions, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
 		"<OFX>\n"+
@@ -600,6 +602,7 @@ func TestOFXTransactionDataFileParseImportedData_ParseDescription(t *testing.T)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(allNewTransactions))
 	assert.Equal(t, "Test", allNewTransactions[0].Comment)
+	assert.Equal(t, "Test", allNewTransactions[0].OriginalPayeeName)
 }
 
 func TestOFXTransactionDataFileParseImportedData_MissingAccountFromNode(t *testing.T) {
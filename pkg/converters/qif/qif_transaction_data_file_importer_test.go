@@ -457,6 +457,63 @@ func TestQIFTransactionDataFileParseImportedData_ParseSubCategory(t *testing.T)
 	assert.Equal(t, "Sub Category", allNewSubExpenseCategories[0].Name)
 }
 
+func TestQIFTransactionDataFileParseImportedData_ParseSplitTransaction(t *testing.T) {
+	converter := QifYearMonthDayTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	expenseCategoryMap := map[string]map[string]*models.TransactionCategory{
+		"Part1 Category": {
+			"": &models.TransactionCategory{CategoryId: 1001, Name: "Part1 Category"},
+		},
+		"Part2 Category": {
+			"Test Category": &models.TransactionCategory{CategoryId: 1002, Name: "Part2 Category"},
+		},
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"!Type:Bank\n"+
+			"D2024-09-01\n"+
+			"T-123.45\n"+
+			"L--Split--\n"+
+			"SPart1 Category\n"+
+			"EPart1 Memo\n"+
+			"$-100.00\n"+
+			"STest Category:Part2 Category\n"+
+			"EPart2 Memo\n"+
+			"$-23.45\n"+
+			"^\n"+
+			"D2024-09-02\n"+
+			"T-123.45\n"+
+			"SPart1 Category\n"+
+			"$-100.00\n"+
+			"S[Test Account2]\n"+
+			"$-23.45\n"+
+			"^\n"), 0, nil, expenseCategoryMap, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, int64(1001), allNewTransactions[0].CategoryId)
+	assert.Equal(t, 2, len(allNewTransactions[0].Splits))
+	assert.Equal(t, int64(1001), allNewTransactions[0].Splits[0].CategoryId)
+	assert.Equal(t, int64(10000), allNewTransactions[0].Splits[0].Amount)
+	assert.Equal(t, "Part1 Memo", allNewTransactions[0].Splits[0].Comment)
+	assert.Equal(t, int64(1002), allNewTransactions[0].Splits[1].CategoryId)
+	assert.Equal(t, int64(2345), allNewTransactions[0].Splits[1].Amount)
+	assert.Equal(t, "Part2 Memo", allNewTransactions[0].Splits[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, 0, len(allNewTransactions[1].Splits))
+}
+
 func TestQIFTransactionDataFileParseImportedData_ParseDescription(t *testing.T) {
 	converter := QifYearMonthDayTransactionDataImporter
 	context := core.NewNullContext()
@@ -482,6 +539,8 @@ func TestQIFTransactionDataFileParseImportedData_ParseDescription(t *testing.T)
 	assert.Equal(t, 2, len(allNewTransactions))
 	assert.Equal(t, "foo    bar\t#test", allNewTransactions[0].Comment)
 	assert.Equal(t, "Test2", allNewTransactions[1].Comment)
+	assert.Equal(t, "Test", allNewTransactions[0].OriginalPayeeName)
+	assert.Equal(t, "Test2", allNewTransactions[1].OriginalPayeeName)
 }
 
 func TestQIFTransactionDataFileParseImportedData_MissingRequiredFields(t *testing.T) {
@@ -12,6 +12,7 @@ import (
 )
 
 const qifOpeningBalancePayeeText = "Opening Balance"
+const qifSplitCategoryText = "--Split--"
 
 var qifTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
 	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:     true,
@@ -22,6 +23,7 @@ var qifTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bo
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
 }
 
 // qifDateFormatType represents the quicken interchange format (qif) date format type
@@ -44,6 +46,7 @@ type qifTransactionDataRow struct {
 	dataTable  *qifTransactionDataTable
 	data       *qifTransactionData
 	finalItems map[datatable.TransactionDataTableColumn]string
+	splits     []*datatable.TransactionDataRowSplit
 }
 
 // qifTransactionDataRowIterator defines the structure of quicken interchange format (qif) transaction data row iterator
@@ -87,6 +90,11 @@ func (r *qifTransactionDataRow) GetData(column datatable.TransactionDataTableCol
 	return ""
 }
 
+// GetSplits returns the split lines of the transaction data row
+func (r *qifTransactionDataRow) GetSplits() []*datatable.TransactionDataRowSplit {
+	return r.splits
+}
+
 // HasNext returns whether the iterator does not reach the end
 func (t *qifTransactionDataRowIterator) HasNext() bool {
 	return t.currentIndex+1 < len(t.dataTable.allData)
@@ -101,7 +109,7 @@ func (t *qifTransactionDataRowIterator) Next(ctx core.Context, user *models.User
 	t.currentIndex++
 
 	data := t.dataTable.allData[t.currentIndex]
-	rowItems, err := t.parseTransaction(ctx, user, data)
+	rowItems, splits, err := t.parseTransaction(ctx, user, data)
 
 	if err != nil {
 		log.Errorf(ctx, "[qif_transaction_data_table.Next] cannot parsing transaction in row#%d, because %s", t.currentIndex, err.Error())
@@ -112,32 +120,34 @@ func (t *qifTransactionDataRowIterator) Next(ctx core.Context, user *models.User
 		dataTable:  t.dataTable,
 		data:       data,
 		finalItems: rowItems,
+		splits:     splits,
 	}, nil
 }
 
-func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, qifTransaction *qifTransactionData) (map[datatable.TransactionDataTableColumn]string, error) {
+func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, qifTransaction *qifTransactionData) (map[datatable.TransactionDataTableColumn]string, []*datatable.TransactionDataRowSplit, error) {
 	data := make(map[datatable.TransactionDataTableColumn]string, len(qifTransactionSupportedColumns))
+	var splits []*datatable.TransactionDataRowSplit
 
 	if qifTransaction.Date == "" {
-		return nil, errs.ErrMissingTransactionTime
+		return nil, nil, errs.ErrMissingTransactionTime
 	}
 
 	transactionTime, err := t.parseTransactionTime(ctx, qifTransaction.Date)
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
 
 	if qifTransaction.Amount == "" {
-		return nil, errs.ErrAmountInvalid
+		return nil, nil, errs.ErrAmountInvalid
 	}
 
 	amount, err := utils.ParseAmount(strings.ReplaceAll(qifTransaction.Amount, ",", "")) // trim thousands separator
 
 	if err != nil {
-		return nil, errs.ErrAmountInvalid
+		return nil, nil, errs.ErrAmountInvalid
 	}
 
 	if qifTransaction.Account != nil {
@@ -172,14 +182,14 @@ func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
 		}
 
-		if strings.Index(qifTransaction.Category, ":") > 0 { // category:subcategory
-			categories := strings.Split(qifTransaction.Category, ":")
-			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = categories[0]
-			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = categories[len(categories)-1]
-		} else {
-			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = ""
-			data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = qifTransaction.Category
+		splits = t.parseTransactionSplits(ctx, qifTransaction, amount < 0)
+		category := qifTransaction.Category
+
+		if len(splits) > 0 && (category == "" || category == qifSplitCategoryText) {
+			category = qifTransaction.SubTransactionCategory[0]
 		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_CATEGORY], data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = t.parseCategoryNames(category)
 	}
 
 	if qifTransaction.Memo != "" {
@@ -188,7 +198,69 @@ func (t *qifTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = qifTransaction.Payee
 	}
 
-	return data, nil
+	if qifTransaction.Payee != qifOpeningBalancePayeeText {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = qifTransaction.Payee
+	}
+
+	return data, splits, nil
+}
+
+func (t *qifTransactionDataRowIterator) parseTransactionSplits(ctx core.Context, qifTransaction *qifTransactionData, isExpense bool) []*datatable.TransactionDataRowSplit {
+	if len(qifTransaction.SubTransactionCategory) < 2 {
+		return nil
+	}
+
+	if len(qifTransaction.SubTransactionAmount) != len(qifTransaction.SubTransactionCategory) {
+		log.Warnf(ctx, "[qif_transaction_data_table.parseTransactionSplits] skip split lines, because split category count %d not equals split amount count %d", len(qifTransaction.SubTransactionCategory), len(qifTransaction.SubTransactionAmount))
+		return nil
+	}
+
+	splits := make([]*datatable.TransactionDataRowSplit, len(qifTransaction.SubTransactionCategory))
+
+	for i := 0; i < len(qifTransaction.SubTransactionCategory); i++ {
+		category := qifTransaction.SubTransactionCategory[i]
+
+		if len(category) > 0 && category[0] == '[' && category[len(category)-1] == ']' {
+			log.Warnf(ctx, "[qif_transaction_data_table.parseTransactionSplits] skip split lines, because split line to account \"%s\" is not supported", category)
+			return nil
+		}
+
+		amount, err := utils.ParseAmount(strings.ReplaceAll(qifTransaction.SubTransactionAmount[i], ",", "")) // trim thousands separator
+
+		if err != nil {
+			log.Warnf(ctx, "[qif_transaction_data_table.parseTransactionSplits] skip split lines, because cannot parse split amount \"%s\"", qifTransaction.SubTransactionAmount[i])
+			return nil
+		}
+
+		if isExpense {
+			amount = -amount
+		}
+
+		categoryName, subCategoryName := t.parseCategoryNames(category)
+		memo := ""
+
+		if i < len(qifTransaction.SubTransactionMemo) {
+			memo = qifTransaction.SubTransactionMemo[i]
+		}
+
+		splits[i] = &datatable.TransactionDataRowSplit{
+			Category:    categoryName,
+			SubCategory: subCategoryName,
+			Amount:      utils.FormatAmount(amount),
+			Description: memo,
+		}
+	}
+
+	return splits
+}
+
+func (t *qifTransactionDataRowIterator) parseCategoryNames(category string) (string, string) {
+	if strings.Index(category, ":") > 0 { // category:subcategory
+		categories := strings.Split(category, ":")
+		return categories[0], categories[len(categories)-1]
+	}
+
+	return "", category
 }
 
 func (t *qifTransactionDataRowIterator) parseTransactionTime(ctx core.Context, date string) (string, error) {
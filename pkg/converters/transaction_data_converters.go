@@ -1,6 +1,7 @@
 package converters
 
 import (
+	"github.com/mayswind/ezbookkeeping/pkg/converters/actual"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/alipay"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/beancount"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/camt"
@@ -12,10 +13,14 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/converters/fireflyIII"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/gnucash"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/iif"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ledger"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/mmex"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/monarch"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/mt"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/ofx"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/qif"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/wechat"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ynab"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 )
@@ -26,6 +31,8 @@ func GetTransactionDataExporter(fileType string) converter.TransactionDataExport
 		return _default.DefaultTransactionDataCSVFileConverter
 	} else if fileType == "tsv" {
 		return _default.DefaultTransactionDataTSVFileConverter
+	} else if fileType == "ledger" {
+		return ledger.LedgerTransactionDataExporter
 	} else {
 		return nil
 	}
@@ -49,16 +56,36 @@ func GetTransactionDataImporter(fileType string) (converter.TransactionDataImpor
 		return qif.QifDayMonthYearTransactionDataImporter, nil
 	} else if fileType == "iif" {
 		return iif.IifTransactionDataFileImporter, nil
+	} else if fileType == "camt052" {
+		return camt.Camt052TransactionDataImporter, nil
 	} else if fileType == "camt053" {
 		return camt.Camt053TransactionDataImporter, nil
+	} else if fileType == "camt054" {
+		return camt.Camt054TransactionDataImporter, nil
+	} else if fileType == "camt_zip" {
+		return camt.CamtZipTransactionDataImporter, nil
 	} else if fileType == "mt940" {
 		return mt.MT940TransactionDataFileImporter, nil
+	} else if fileType == "mt942" {
+		return mt.MT942TransactionDataFileImporter, nil
 	} else if fileType == "gnucash" {
 		return gnucash.GnuCashTransactionDataImporter, nil
 	} else if fileType == "firefly_iii_csv" {
 		return fireflyIII.FireflyIIITransactionDataCsvFileImporter, nil
 	} else if fileType == "beancount" {
 		return beancount.BeancountTransactionDataImporter, nil
+	} else if fileType == "ledger" {
+		return ledger.LedgerTransactionDataImporter, nil
+	} else if fileType == "ynab_csv" {
+		return ynab.YnabTransactionDataCsvFileImporter, nil
+	} else if fileType == "actual_csv" {
+		return actual.ActualTransactionDataCsvFileImporter, nil
+	} else if fileType == "monarch_csv" {
+		return monarch.MonarchTransactionDataCsvFileImporter, nil
+	} else if fileType == "mint_csv" {
+		return monarch.MintTransactionDataCsvFileImporter, nil
+	} else if fileType == "mmex" {
+		return mmex.MoneyManagerExTransactionDataFileImporter, nil
 	} else if fileType == "feidee_mymoney_csv" {
 		return feidee.FeideeMymoneyAppTransactionDataCsvFileImporter, nil
 	} else if fileType == "feidee_mymoney_xls" {
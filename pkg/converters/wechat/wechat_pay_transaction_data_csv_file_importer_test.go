@@ -308,6 +308,42 @@ func TestWeChatPayCsvFileImporterParseImportedData_ParseDescription(t *testing.T
 	assert.Equal(t, "Test", allNewTransactions[0].Comment)
 }
 
+func TestWeChatPayCsvFileImporterParseImportedData_ParsePayee(t *testing.T) {
+	converter := WeChatPayTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data1 := "微信支付账单明细,,,,\n" +
+		"微信昵称：[xxx],,,,\n" +
+		"起始时间：[2024-01-01 00:00:00] 终止时间：[2024-09-01 23:59:59],,,,\n" +
+		",,,,\n" +
+		"----------------------微信支付账单明细列表--------------------,,,,\n" +
+		"交易时间,交易类型,交易对方,收/支,金额(元),当前状态\n" +
+		"2024-09-01 01:23:45,商户消费,Test Shop,支出,￥0.12,支付成功\n"
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(data1), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Test Shop", allNewTransactions[0].OriginalPayeeName)
+
+	data2 := "微信支付账单明细,,,,\n" +
+		"微信昵称：[xxx],,,,\n" +
+		"起始时间：[2024-01-01 00:00:00] 终止时间：[2024-09-01 23:59:59],,,,\n" +
+		",,,,\n" +
+		"----------------------微信支付账单明细列表--------------------,,,,\n" +
+		"交易时间,交易类型,交易对方,收/支,金额(元),当前状态\n" +
+		"2024-09-01 01:23:45,商户消费,/,支出,￥0.12,支付成功\n"
+	allNewTransactions, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(data2), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "", allNewTransactions[0].OriginalPayeeName)
+}
+
 func TestWeChatPayCsvFileImporterParseImportedData_SkipUnknownTransferTransaction(t *testing.T) {
 	converter := WeChatPayTransactionDataCsvFileImporter
 	context := core.NewNullContext()
@@ -18,6 +18,7 @@ const wechatPayTransactionDataHeaderStartContentBeginning = "-------------------
 const wechatPayTransactionTimeColumnName = "交易时间"
 const wechatPayTransactionCategoryColumnName = "交易类型"
 const wechatPayTransactionProductNameColumnName = "商品"
+const wechatPayTransactionTargetNameColumnName = "交易对方"
 const wechatPayTransactionTypeColumnName = "收/支"
 const wechatPayTransactionAmountColumnName = "金额(元)"
 const wechatPayTransactionRelatedAccountColumnName = "支付方式"
@@ -37,6 +38,7 @@ var wechatPayTransactionSupportedColumns = map[datatable.TransactionDataTableCol
 	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+	datatable.TRANSACTION_DATA_TABLE_PAYEE:                true,
 }
 
 var wechatPayTransactionTypeNameMapping = map[models.TransactionType]string{
@@ -88,6 +90,12 @@ func (p *weChatPayTransactionDataRowParser) Parse(ctx core.Context, user *models
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	if p.hasOriginalColumn(wechatPayTransactionTargetNameColumnName) && dataRow.GetData(wechatPayTransactionTargetNameColumnName) != "/" {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = dataRow.GetData(wechatPayTransactionTargetNameColumnName)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = ""
+	}
+
 	relatedAccountName := ""
 
 	if p.hasOriginalColumn(wechatPayTransactionRelatedAccountColumnName) {
@@ -0,0 +1,380 @@
+package ynab
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/converter"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/csv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ynabTransactionAccountColumnName = "Account"
+const ynabTransactionDateColumnName = "Date"
+const ynabTransactionPayeeColumnName = "Payee"
+const ynabTransactionCategoryGroupColumnName = "Category Group"
+const ynabTransactionCategoryColumnName = "Category"
+const ynab4TransactionMasterCategoryColumnName = "Master Category"
+const ynab4TransactionSubCategoryColumnName = "Sub Category"
+const ynabTransactionMemoColumnName = "Memo"
+const ynabTransactionOutflowColumnName = "Outflow"
+const ynabTransactionInflowColumnName = "Inflow"
+
+const ynabTransactionTransferPayeePrefix = "Transfer : "
+const ynabTransactionStartingBalancePayee = "Starting Balance"
+
+var ynabTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_MODIFY_BALANCE: "Starting Balance",
+	models.TRANSACTION_TYPE_INCOME:         "Inflow",
+	models.TRANSACTION_TYPE_EXPENSE:        "Outflow",
+	models.TRANSACTION_TYPE_TRANSFER:       "Transfer",
+}
+
+// ynabDateOrder represents the order of year, month and day in the date of ynab register
+type ynabDateOrder byte
+
+// Date orders of ynab register
+const (
+	ynabDateOrderYearMonthDay ynabDateOrder = 1
+	ynabDateOrderMonthDayYear ynabDateOrder = 2
+	ynabDateOrderDayMonthYear ynabDateOrder = 3
+)
+
+// ynabAmountFormat represents the currency format of the amounts in ynab register
+type ynabAmountFormat struct {
+	decimalSeparator byte // zero means the currency has no decimal places
+}
+
+// ynabTransactionDataCsvFileImporter defines the structure of ynab register csv importer for transaction data
+type ynabTransactionDataCsvFileImporter struct{}
+
+// Initialize a ynab (ynab 4 and nynab) register csv file importer singleton instance
+var (
+	YnabTransactionDataCsvFileImporter = &ynabTransactionDataCsvFileImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing the ynab 4 or nynab register csv data
+func (c *ynabTransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]map[string]*models.TransactionCategory, incomeCategoryMap map[string]map[string]*models.TransactionCategory, transferCategoryMap map[string]map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	fallback := unicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+
+	csvDataTable, err := csv.CreateNewCsvBasicDataTable(ctx, reader, true)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	commonDataTable := datatable.CreateNewCommonDataTableFromBasicDataTable(csvDataTable)
+
+	if !commonDataTable.HasColumn(ynabTransactionAccountColumnName) ||
+		!commonDataTable.HasColumn(ynabTransactionDateColumnName) ||
+		!commonDataTable.HasColumn(ynabTransactionPayeeColumnName) ||
+		!commonDataTable.HasColumn(ynabTransactionOutflowColumnName) ||
+		!commonDataTable.HasColumn(ynabTransactionInflowColumnName) {
+		log.Errorf(ctx, "[ynab_transaction_data_csv_file_importer.ParseImportedData] cannot parse import data, because missing essential columns in header row")
+		return nil, nil, nil, nil, nil, nil, errs.ErrMissingRequiredFieldInHeaderRow
+	}
+
+	categoryGroupColumnName := ""
+	categoryColumnName := ""
+
+	if commonDataTable.HasColumn(ynab4TransactionMasterCategoryColumnName) && commonDataTable.HasColumn(ynab4TransactionSubCategoryColumnName) { // ynab 4
+		categoryGroupColumnName = ynab4TransactionMasterCategoryColumnName
+		categoryColumnName = ynab4TransactionSubCategoryColumnName
+	} else if commonDataTable.HasColumn(ynabTransactionCategoryColumnName) { // nynab
+		categoryGroupColumnName = ynabTransactionCategoryGroupColumnName
+		categoryColumnName = ynabTransactionCategoryColumnName
+	} else {
+		log.Errorf(ctx, "[ynab_transaction_data_csv_file_importer.ParseImportedData] cannot parse import data, because missing category column in header row")
+		return nil, nil, nil, nil, nil, nil, errs.ErrMissingRequiredFieldInHeaderRow
+	}
+
+	transactionDataTable, err := c.createNewYnabTransactionDataTable(ctx, commonDataTable, categoryGroupColumnName, categoryColumnName)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	dataTableImporter := converter.CreateNewSimpleImporterWithTypeNameMapping(ynabTransactionTypeNameMapping)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
+
+func (c *ynabTransactionDataCsvFileImporter) createNewYnabTransactionDataTable(ctx core.Context, commonDataTable datatable.CommonDataTable, categoryGroupColumnName string, categoryColumnName string) (datatable.TransactionDataTable, error) {
+	allRows := make([]datatable.CommonDataTableRow, 0, commonDataTable.DataRowCount())
+	allRowIds := make([]string, 0, commonDataTable.DataRowCount())
+	allDates := make([]string, 0, commonDataTable.DataRowCount())
+	allAmounts := make([]string, 0, commonDataTable.DataRowCount()*2)
+
+	commonDataTableIterator := commonDataTable.DataRowIterator()
+
+	for commonDataTableIterator.HasNext() {
+		dataRow := commonDataTableIterator.Next()
+		rowId := commonDataTableIterator.CurrentRowId()
+
+		if dataRow.ColumnCount() < commonDataTable.HeaderColumnCount() {
+			log.Errorf(ctx, "[ynab_transaction_data_csv_file_importer.createNewYnabTransactionDataTable] cannot parse row \"%s\", because may missing some columns (column count %d in data row is less than header column count %d)", rowId, dataRow.ColumnCount(), commonDataTable.HeaderColumnCount())
+			return nil, errs.ErrFewerFieldsInDataRowThanInHeaderRow
+		}
+
+		allRows = append(allRows, dataRow)
+		allRowIds = append(allRowIds, rowId)
+		allDates = append(allDates, dataRow.GetData(ynabTransactionDateColumnName))
+		allAmounts = append(allAmounts, dataRow.GetData(ynabTransactionOutflowColumnName), dataRow.GetData(ynabTransactionInflowColumnName))
+	}
+
+	dateOrder := getYnabDateOrder(allDates)
+	amountFormat := getYnabAmountFormat(allAmounts)
+
+	transactionDataTable := datatable.CreateNewWritableTransactionDataTable([]datatable.TransactionDataTableColumn{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME,
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE,
+		datatable.TRANSACTION_DATA_TABLE_CATEGORY,
+		datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY,
+		datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT,
+		datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME,
+		datatable.TRANSACTION_DATA_TABLE_DESCRIPTION,
+		datatable.TRANSACTION_DATA_TABLE_PAYEE,
+	})
+
+	// both sides of a transfer appear in the register when both accounts are exported, so the second side should be skipped
+	unmatchedTransferCounts := make(map[string]int)
+
+	for i := 0; i < len(allRows); i++ {
+		dataRow := allRows[i]
+		rowId := allRowIds[i]
+
+		transactionTime, err := parseYnabDate(allDates[i], dateOrder)
+
+		if err != nil {
+			log.Errorf(ctx, "[ynab_transaction_data_csv_file_importer.createNewYnabTransactionDataTable] cannot parse date \"%s\" in row \"%s\", because %s", allDates[i], rowId, err.Error())
+			return nil, errs.ErrTransactionTimeInvalid
+		}
+
+		outflow, err := parseYnabAmount(dataRow.GetData(ynabTransactionOutflowColumnName), amountFormat)
+
+		if err != nil {
+			log.Errorf(ctx, "[ynab_transaction_data_csv_file_importer.createNewYnabTransactionDataTable] cannot parse outflow \"%s\" in row \"%s\", because %s", dataRow.GetData(ynabTransactionOutflowColumnName), rowId, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		inflow, err := parseYnabAmount(dataRow.GetData(ynabTransactionInflowColumnName), amountFormat)
+
+		if err != nil {
+			log.Errorf(ctx, "[ynab_transaction_data_csv_file_importer.createNewYnabTransactionDataTable] cannot parse inflow \"%s\" in row \"%s\", because %s", dataRow.GetData(ynabTransactionInflowColumnName), rowId, err.Error())
+			return nil, errs.ErrAmountInvalid
+		}
+
+		amount := inflow - outflow
+		accountName := dataRow.GetData(ynabTransactionAccountColumnName)
+		payee := dataRow.GetData(ynabTransactionPayeeColumnName)
+
+		data := make(map[datatable.TransactionDataTableColumn]string, 9)
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = accountName
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = dataRow.GetData(ynabTransactionMemoColumnName)
+
+		if dataRow.HasData(categoryGroupColumnName) {
+			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = dataRow.GetData(categoryGroupColumnName)
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = dataRow.GetData(categoryColumnName)
+
+		if strings.HasPrefix(payee, ynabTransactionTransferPayeePrefix) {
+			relatedAccountName := strings.TrimSpace(payee[len(ynabTransactionTransferPayeePrefix):])
+
+			if amount > 0 {
+				data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = relatedAccountName
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = accountName
+			} else {
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = relatedAccountName
+				amount = -amount
+			}
+
+			transferKey := strings.Join([]string{transactionTime, data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME], data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME], utils.Int64ToString(amount)}, "\n")
+
+			if unmatchedTransferCounts[transferKey] > 0 {
+				unmatchedTransferCounts[transferKey]--
+				continue
+			}
+
+			unmatchedTransferCounts[transferKey]++
+
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else if payee == ynabTransactionStartingBalancePayee {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else if amount >= 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = payee
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+			data[datatable.TRANSACTION_DATA_TABLE_PAYEE] = payee
+		}
+
+		transactionDataTable.Add(data)
+	}
+
+	return transactionDataTable, nil
+}
+
+// getYnabDateOrder returns the date order of all the dates in ynab register, ynab exports dates in the date format of budget settings
+func getYnabDateOrder(allDates []string) ynabDateOrder {
+	dateOrder := ynabDateOrderMonthDayYear
+
+	for i := 0; i < len(allDates); i++ {
+		items := splitYnabDate(allDates[i])
+
+		if len(items) != 3 {
+			continue
+		}
+
+		if len(items[0]) == 4 {
+			return ynabDateOrderYearMonthDay
+		}
+
+		if first, err := utils.StringToInt(items[0]); err == nil && first > 12 {
+			dateOrder = ynabDateOrderDayMonthYear
+		}
+	}
+
+	return dateOrder
+}
+
+// parseYnabDate returns the long date time of the specified ynab date
+func parseYnabDate(date string, dateOrder ynabDateOrder) (string, error) {
+	items := splitYnabDate(date)
+
+	if len(items) != 3 {
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	for i := 0; i < len(items); i++ {
+		if _, err := utils.StringToInt(items[i]); err != nil {
+			return "", errs.ErrTransactionTimeInvalid
+		}
+	}
+
+	if dateOrder == ynabDateOrderYearMonthDay {
+		return utils.FormatYearMonthDayToLongDateTime(items[0], items[1], items[2])
+	} else if dateOrder == ynabDateOrderDayMonthYear {
+		return utils.FormatYearMonthDayToLongDateTime(items[2], items[1], items[0])
+	}
+
+	return utils.FormatYearMonthDayToLongDateTime(items[2], items[0], items[1])
+}
+
+func splitYnabDate(date string) []string {
+	return strings.FieldsFunc(strings.TrimSpace(date), func(r rune) bool {
+		return r == '/' || r == '-' || r == '.'
+	})
+}
+
+// getYnabAmountFormat returns the currency format of all the amounts in ynab register, ynab exports amounts in the currency format of budget settings
+// with fixed decimal places (e.g. "$0.00", "0,000 BD" or "¥0"), so the format can be detected by the amount whose last separator must be decimal separator,
+// returns nil if the format cannot be detected (e.g. all amounts are like "1,234")
+func getYnabAmountFormat(allAmounts []string) *ynabAmountFormat {
+	hasAmountWithoutSeparator := false
+
+	for i := 0; i < len(allAmounts); i++ {
+		amountValue := getYnabAmountDigitsAndSeparators(allAmounts[i])
+
+		if amountValue == "" {
+			continue
+		}
+
+		lastSeparatorIndex := strings.LastIndexAny(amountValue, ".,")
+
+		if lastSeparatorIndex < 0 {
+			hasAmountWithoutSeparator = true
+			continue
+		}
+
+		separator := amountValue[lastSeparatorIndex]
+		integerPart := amountValue[:lastSeparatorIndex]
+		otherSeparator := byte('.')
+
+		if separator == '.' {
+			otherSeparator = ','
+		}
+
+		// digit grouping symbol is always followed by three digits, and is never after the other separator or a zero integer part
+		if len(amountValue)-lastSeparatorIndex-1 != 3 || strings.IndexByte(integerPart, otherSeparator) >= 0 || strings.Trim(integerPart, "0") == "" {
+			return &ynabAmountFormat{
+				decimalSeparator: separator,
+			}
+		}
+	}
+
+	if hasAmountWithoutSeparator {
+		return &ynabAmountFormat{}
+	}
+
+	return nil
+}
+
+// parseYnabAmount returns the amount of the specified ynab currency text (e.g. "$1,234.56" or "1.234,56 €") in the specified currency format
+func parseYnabAmount(value string, amountFormat *ynabAmountFormat) (int64, error) {
+	negative := strings.Contains(value, "-") || (strings.Contains(value, "(") && strings.Contains(value, ")"))
+	amountValue := getYnabAmountDigitsAndSeparators(value)
+
+	if amountValue == "" {
+		return 0, nil
+	}
+
+	decimalSeparatorIndex := -1
+
+	if amountFormat == nil {
+		decimalSeparatorIndex = strings.LastIndexAny(amountValue, ".,")
+
+		// the last separator is digit grouping symbol if there are exactly three digits after it
+		if decimalSeparatorIndex >= 0 && len(amountValue)-decimalSeparatorIndex-1 == 3 {
+			decimalSeparatorIndex = -1
+		}
+	} else if amountFormat.decimalSeparator != 0 {
+		decimalSeparatorIndex = strings.LastIndexByte(amountValue, amountFormat.decimalSeparator)
+	}
+
+	if decimalSeparatorIndex >= 0 {
+		integerPart := strings.NewReplacer(".", "", ",", "").Replace(amountValue[:decimalSeparatorIndex])
+		amountValue = integerPart + "." + amountValue[decimalSeparatorIndex+1:]
+	} else {
+		amountValue = strings.NewReplacer(".", "", ",", "").Replace(amountValue)
+	}
+
+	amount, err := utils.ParseAmount(utils.TrimTrailingZerosInDecimal(amountValue))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if negative {
+		return -amount, nil
+	}
+
+	return amount, nil
+}
+
+func getYnabAmountDigitsAndSeparators(value string) string {
+	builder := strings.Builder{}
+
+	for _, ch := range value {
+		if ('0' <= ch && ch <= '9') || ch == '.' || ch == ',' {
+			builder.WriteRune(ch)
+		}
+	}
+
+	return builder.String()
+}
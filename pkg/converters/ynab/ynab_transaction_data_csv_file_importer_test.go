@@ -0,0 +1,202 @@
+package ynab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestYnabTransactionDataCsvFileImporterParseImportedData_NYnabRegister(t *testing.T) {
+	converter := YnabTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Flag\",\"Date\",\"Payee\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\"\n"+
+			"\"Checking\",\"\",\"09/01/2024\",\"Starting Balance\",\"Inflow: Ready to Assign\",\"Inflow\",\"Ready to Assign\",\"\",$0.00,\"$1,234.56\",\"Cleared\"\n"+
+			"\"Checking\",\"\",\"09/02/2024\",\"Employer\",\"Inflow: Ready to Assign\",\"Inflow\",\"Ready to Assign\",\"Salary\",$0.00,$100.00,\"Cleared\"\n"+
+			"\"Checking\",\"Red\",\"09/03/2024\",\"Grocery Store\",\"Everyday: Groceries\",\"Everyday\",\"Groceries\",\"Weekly\",$12.34,$0.00,\"Uncleared\"\n"+
+			"\"Checking\",\"\",\"09/04/2024\",\"Transfer : Savings\",\"\",\"\",\"\",\"\",$50.00,$0.00,\"Cleared\"\n"+
+			"\"Savings\",\"\",\"09/04/2024\",\"Transfer : Checking\",\"\",\"\",\"\",\"\",$0.00,$50.00,\"Cleared\"\n"+
+			"\"Savings\",\"\",\"09/05/2024\",\"Transfer : Checking\",\"\",\"\",\"\",\"\",$0.00,$20.00,\"Cleared\"\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(123456), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Ready to Assign", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, "Employer", allNewTransactions[1].OriginalPayeeName)
+	assert.Equal(t, "Salary", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(1234), allNewTransactions[2].Amount)
+	assert.Equal(t, "Groceries", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Grocery Store", allNewTransactions[2].OriginalPayeeName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, int64(5000), allNewTransactions[3].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[4].Type)
+	assert.Equal(t, int64(2000), allNewTransactions[4].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[4].OriginalDestinationAccountName)
+}
+
+func TestYnabTransactionDataCsvFileImporterParseImportedData_Ynab4Register(t *testing.T) {
+	converter := YnabTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "EUR",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Flag\",\"Check Number\",\"Date\",\"Payee\",\"Category\",\"Master Category\",\"Sub Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\",\"Running Balance\"\n"+
+			"\"Girokonto\",\"\",\"\",\"02/09/2024\",\"Bakery\",\"Food:Bread\",\"Food\",\"Bread\",\"\",\"1.234,50 €\",\"0,00 €\",\"C\",\"-1.234,50 €\"\n"+
+			"\"Girokonto\",\"\",\"\",\"13/09/2024\",\"Refund\",\"Food:Bread\",\"Food\",\"Bread\",\"\",\"0,00 €\",\"1,5 €\",\"C\",\"-1.233,00 €\"\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(123450), allNewTransactions[0].Amount)
+	assert.Equal(t, "Bread", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-13 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(150), allNewTransactions[1].Amount)
+}
+
+func TestYnabTransactionDataCsvFileImporterParseImportedData_MissingRequiredColumn(t *testing.T) {
+	converter := YnabTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Date\",\"Payee\",\"Memo\",\"Outflow\",\"Inflow\"\n"+
+			"\"Checking\",\"09/01/2024\",\"Store\",\"\",$1.00,$0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Date\",\"Payee\",\"Category\",\"Memo\",\"Inflow\"\n"+
+			"\"Checking\",\"09/01/2024\",\"Store\",\"Food\",\"\",$0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+}
+
+func TestYnabTransactionDataCsvFileImporterParseImportedData_InvalidDate(t *testing.T) {
+	converter := YnabTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Date\",\"Payee\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\"\n"+
+			"\"Checking\",\"2024/09\",\"Store\",\"Food\",\"\",$1.00,$0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestGetYnabAmountFormat(t *testing.T) {
+	assert.Equal(t, &ynabAmountFormat{decimalSeparator: '.'}, getYnabAmountFormat([]string{"$1,234.56", "$0.00"}))
+	assert.Equal(t, &ynabAmountFormat{decimalSeparator: ','}, getYnabAmountFormat([]string{"1.234,56 €", ""}))
+	assert.Equal(t, &ynabAmountFormat{decimalSeparator: '.'}, getYnabAmountFormat([]string{"BD1.234", "BD0.000"}))
+	assert.Equal(t, &ynabAmountFormat{decimalSeparator: ','}, getYnabAmountFormat([]string{"1.234,567 BD"}))
+	assert.Equal(t, &ynabAmountFormat{}, getYnabAmountFormat([]string{"¥1,234", "¥0"}))
+	assert.Nil(t, getYnabAmountFormat([]string{"¥1,234", ""}))
+}
+
+func TestParseYnabAmount(t *testing.T) {
+	amount, err := parseYnabAmount("$1,234.56", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(123456), amount)
+
+	amount, err = parseYnabAmount("1.234,56 €", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(123456), amount)
+
+	amount, err = parseYnabAmount("-$1,234", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-123400), amount)
+
+	amount, err = parseYnabAmount("", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), amount)
+}
+
+func TestParseYnabAmount_WithAmountFormat(t *testing.T) {
+	amount, err := parseYnabAmount("BD1.230", &ynabAmountFormat{decimalSeparator: '.'})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(123), amount)
+
+	amount, err = parseYnabAmount("1.234,500 BD", &ynabAmountFormat{decimalSeparator: ','})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(123450), amount)
+
+	amount, err = parseYnabAmount("-¥1,234", &ynabAmountFormat{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-123400), amount)
+
+	amount, err = parseYnabAmount("$12", &ynabAmountFormat{decimalSeparator: '.'})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1200), amount)
+
+	_, err = parseYnabAmount("BD1.234", &ynabAmountFormat{decimalSeparator: '.'})
+	assert.NotNil(t, err)
+}
+
+func TestYnabTransactionDataCsvFileImporterParseImportedData_ThreeDecimalPlacesCurrency(t *testing.T) {
+	converter := YnabTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "BHD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"\"Account\",\"Date\",\"Payee\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\"\n"+
+			"\"Checking\",\"09/02/2024\",\"Employer\",\"Inflow\",\"Ready to Assign\",\"\",BD0.000,BD1.230\n"+
+			"\"Checking\",\"09/03/2024\",\"Grocery Store\",\"Everyday\",\"Groceries\",\"\",\"BD1,234.500\",BD0.000\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, int64(123), allNewTransactions[0].Amount)
+	assert.Equal(t, int64(123450), allNewTransactions[1].Amount)
+}
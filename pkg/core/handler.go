@@ -27,5 +27,8 @@ type DataHandlerFunc func(*WebContext) ([]byte, string, *errs.Error)
 // ImageHandlerFunc represents the handler function that returns image byte array and content type
 type ImageHandlerFunc func(*WebContext) ([]byte, string, *errs.Error)
 
+// RedirectHandlerFunc represents the handler function that returns the redirect url
+type RedirectHandlerFunc func(*WebContext) (string, *errs.Error)
+
 // ProxyHandlerFunc represents the reverse proxy handler function
 type ProxyHandlerFunc func(*WebContext) (*httputil.ReverseProxy, *errs.Error)
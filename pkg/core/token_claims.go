@@ -11,21 +11,34 @@ type TokenType byte
 
 // Token types
 const (
-	USER_TOKEN_TYPE_NORMAL         TokenType = 1
-	USER_TOKEN_TYPE_REQUIRE_2FA    TokenType = 2
-	USER_TOKEN_TYPE_EMAIL_VERIFY   TokenType = 3
-	USER_TOKEN_TYPE_PASSWORD_RESET TokenType = 4
-	USER_TOKEN_TYPE_MCP            TokenType = 5
+	USER_TOKEN_TYPE_NORMAL          TokenType = 1
+	USER_TOKEN_TYPE_REQUIRE_2FA     TokenType = 2
+	USER_TOKEN_TYPE_EMAIL_VERIFY    TokenType = 3
+	USER_TOKEN_TYPE_PASSWORD_RESET  TokenType = 4
+	USER_TOKEN_TYPE_MCP             TokenType = 5
+	USER_TOKEN_TYPE_PERSONAL_ACCESS TokenType = 6
+	USER_TOKEN_TYPE_OAUTH2_CALLBACK TokenType = 7
+)
+
+// TokenScope represents the permission scope of token
+type TokenScope byte
+
+// Token scopes
+const (
+	USER_TOKEN_SCOPE_FULL_ACCESS TokenScope = 0
+	USER_TOKEN_SCOPE_READ_ONLY   TokenScope = 1
 )
 
 // UserTokenClaims represents user token
 type UserTokenClaims struct {
-	UserTokenId string    `json:"userTokenId"`
-	Uid         int64     `json:"jti,string"`
-	Username    string    `json:"username,omitempty"`
-	Type        TokenType `json:"type"`
-	IssuedAt    int64     `json:"iat"`
-	ExpiresAt   int64     `json:"exp"`
+	UserTokenId string           `json:"userTokenId"`
+	Uid         int64            `json:"jti,string"`
+	Username    string           `json:"username,omitempty"`
+	Type        TokenType        `json:"type"`
+	Scope       TokenScope       `json:"scope,omitempty"`
+	Permissions TokenPermissions `json:"permissions,omitempty"`
+	IssuedAt    int64            `json:"iat"`
+	ExpiresAt   int64            `json:"exp"`
 }
 
 // GetExpirationTime returns the expiration time of this token
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenPermissions represents all the permissions of personal access token
+type TokenPermissions uint64
+
+// Add returns a new token permissions with the specified permission
+func (p TokenPermissions) Add(permissionType TokenPermissionType) TokenPermissions {
+	typeValue := uint64(1 << (permissionType - 1))
+	return TokenPermissions(uint64(p) | typeValue)
+}
+
+// Contains returns whether contains the specified permission
+func (p TokenPermissions) Contains(permissionType TokenPermissionType) bool {
+	typeValue := uint64(1 << (permissionType - 1))
+	return uint64(p)&typeValue == typeValue
+}
+
+// Names returns the permission names of all the permissions of personal access token
+func (p TokenPermissions) Names() []string {
+	names := make([]string, 0)
+
+	for permissionType := tokenPermissionTypeMinValue; permissionType <= tokenPermissionTypeMaxValue; permissionType++ {
+		if p.Contains(permissionType) {
+			names = append(names, permissionType.String())
+		}
+	}
+
+	return names
+}
+
+// String returns a textual representation of all the permissions of personal access token
+func (p TokenPermissions) String() string {
+	return strings.Join(p.Names(), ",")
+}
+
+// ParseTokenPermissions returns permissions of personal access token according to the textual permission names separated by commas
+func ParseTokenPermissions(permissions string) (TokenPermissions, error) {
+	if len(permissions) < 1 {
+		return 0, nil
+	}
+
+	return ParseTokenPermissionNames(strings.Split(permissions, ","))
+}
+
+// ParseTokenPermissionNames returns permissions of personal access token according to the permission names
+func ParseTokenPermissionNames(permissionNames []string) (TokenPermissions, error) {
+	permissions := TokenPermissions(0)
+
+	for i := 0; i < len(permissionNames); i++ {
+		name := strings.TrimSpace(permissionNames[i])
+
+		if name == "" {
+			continue
+		}
+
+		permissionType, exists := tokenPermissionTypeNameMap[name]
+
+		if !exists {
+			return 0, fmt.Errorf("unknown token permission \"%s\"", name)
+		}
+
+		permissions = permissions.Add(permissionType)
+	}
+
+	return permissions, nil
+}
+
+// TokenPermissionType represents the permission type of personal access token
+type TokenPermissionType uint64
+
+// Token Permission Type
+const (
+	TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ    TokenPermissionType = 1
+	TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE   TokenPermissionType = 2
+	TOKEN_PERMISSION_TYPE_ACCOUNTS_READ        TokenPermissionType = 3
+	TOKEN_PERMISSION_TYPE_ACCOUNTS_WRITE       TokenPermissionType = 4
+	TOKEN_PERMISSION_TYPE_CATEGORIES_READ      TokenPermissionType = 5
+	TOKEN_PERMISSION_TYPE_CATEGORIES_WRITE     TokenPermissionType = 6
+	TOKEN_PERMISSION_TYPE_TAGS_READ            TokenPermissionType = 7
+	TOKEN_PERMISSION_TYPE_TAGS_WRITE           TokenPermissionType = 8
+	TOKEN_PERMISSION_TYPE_INVESTMENTS_READ     TokenPermissionType = 9
+	TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE    TokenPermissionType = 10
+	TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_READ  TokenPermissionType = 11
+	TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_WRITE TokenPermissionType = 12
+)
+
+const tokenPermissionTypeMinValue TokenPermissionType = TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ
+const tokenPermissionTypeMaxValue TokenPermissionType = TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_WRITE
+
+var tokenPermissionTypeNameMap = map[string]TokenPermissionType{
+	TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ.String():    TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ,
+	TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE.String():   TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE,
+	TOKEN_PERMISSION_TYPE_ACCOUNTS_READ.String():        TOKEN_PERMISSION_TYPE_ACCOUNTS_READ,
+	TOKEN_PERMISSION_TYPE_ACCOUNTS_WRITE.String():       TOKEN_PERMISSION_TYPE_ACCOUNTS_WRITE,
+	TOKEN_PERMISSION_TYPE_CATEGORIES_READ.String():      TOKEN_PERMISSION_TYPE_CATEGORIES_READ,
+	TOKEN_PERMISSION_TYPE_CATEGORIES_WRITE.String():     TOKEN_PERMISSION_TYPE_CATEGORIES_WRITE,
+	TOKEN_PERMISSION_TYPE_TAGS_READ.String():            TOKEN_PERMISSION_TYPE_TAGS_READ,
+	TOKEN_PERMISSION_TYPE_TAGS_WRITE.String():           TOKEN_PERMISSION_TYPE_TAGS_WRITE,
+	TOKEN_PERMISSION_TYPE_INVESTMENTS_READ.String():     TOKEN_PERMISSION_TYPE_INVESTMENTS_READ,
+	TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE.String():    TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE,
+	TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_READ.String():  TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_READ,
+	TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_WRITE.String(): TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_WRITE,
+}
+
+// String returns a textual representation of the permission type of personal access token
+func (t TokenPermissionType) String() string {
+	switch t {
+	case TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ:
+		return "transactions:read"
+	case TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE:
+		return "transactions:write"
+	case TOKEN_PERMISSION_TYPE_ACCOUNTS_READ:
+		return "accounts:read"
+	case TOKEN_PERMISSION_TYPE_ACCOUNTS_WRITE:
+		return "accounts:write"
+	case TOKEN_PERMISSION_TYPE_CATEGORIES_READ:
+		return "categories:read"
+	case TOKEN_PERMISSION_TYPE_CATEGORIES_WRITE:
+		return "categories:write"
+	case TOKEN_PERMISSION_TYPE_TAGS_READ:
+		return "tags:read"
+	case TOKEN_PERMISSION_TYPE_TAGS_WRITE:
+		return "tags:write"
+	case TOKEN_PERMISSION_TYPE_INVESTMENTS_READ:
+		return "investments:read"
+	case TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE:
+		return "investments:write"
+	case TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_READ:
+		return "exchange_rates:read"
+	case TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_WRITE:
+		return "exchange_rates:write"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(t))
+	}
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenPermissionsAdd(t *testing.T) {
+	var permissions TokenPermissions
+	permissions = permissions.Add(TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ)
+	expectedValue := TokenPermissions(1)
+	assert.Equal(t, expectedValue, permissions)
+
+	permissions = permissions.Add(TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE)
+	permissions = permissions.Add(TOKEN_PERMISSION_TYPE_ACCOUNTS_READ)
+	permissions = permissions.Add(TOKEN_PERMISSION_TYPE_ACCOUNTS_READ)
+	expectedValue = TokenPermissions(7)
+	assert.Equal(t, expectedValue, permissions)
+}
+
+func TestTokenPermissionsContains(t *testing.T) {
+	permissions := TokenPermissions(0).Add(TOKEN_PERMISSION_TYPE_ACCOUNTS_READ).Add(TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE)
+
+	assert.Equal(t, true, permissions.Contains(TOKEN_PERMISSION_TYPE_ACCOUNTS_READ))
+	assert.Equal(t, true, permissions.Contains(TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE))
+	assert.Equal(t, false, permissions.Contains(TOKEN_PERMISSION_TYPE_ACCOUNTS_WRITE))
+	assert.Equal(t, false, permissions.Contains(TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ))
+}
+
+func TestTokenPermissionsString(t *testing.T) {
+	permissions := TokenPermissions(0)
+	assert.Equal(t, "", permissions.String())
+
+	permissions = permissions.Add(TOKEN_PERMISSION_TYPE_TAGS_WRITE).Add(TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ)
+	assert.Equal(t, "transactions:read,tags:write", permissions.String())
+}
+
+func TestParseTokenPermissions(t *testing.T) {
+	permissions, err := ParseTokenPermissions("")
+	assert.Nil(t, err)
+	assert.Equal(t, TokenPermissions(0), permissions)
+
+	permissions, err = ParseTokenPermissions("transactions:read, accounts:write,,investments:write")
+	assert.Nil(t, err)
+	assert.Equal(t, true, permissions.Contains(TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ))
+	assert.Equal(t, true, permissions.Contains(TOKEN_PERMISSION_TYPE_ACCOUNTS_WRITE))
+	assert.Equal(t, true, permissions.Contains(TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE))
+	assert.Equal(t, false, permissions.Contains(TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE))
+
+	_, err = ParseTokenPermissions("transactions:read,transactions:delete")
+	assert.NotNil(t, err)
+}
@@ -84,6 +84,26 @@ func (c *CronJobSchedulerContainer) registerAllJobs(ctx core.Context, config *se
 	if config.EnableCreateScheduledTransaction {
 		Container.registerIntervalJob(ctx, CreateScheduledTransactionJob)
 	}
+
+	if config.EnablePostFixedIncomeInterest {
+		Container.registerIntervalJob(ctx, PostFixedIncomeInterestJob)
+	}
+
+	if config.EnableRebuildTransactionSuggestionModel {
+		Container.registerIntervalJob(ctx, RebuildTransactionSuggestionModelJob)
+	}
+
+	if config.EnableRemoveExpiredAuditEvents {
+		Container.registerIntervalJob(ctx, RemoveExpiredAuditEventsJob)
+	}
+
+	if config.EnableMCPServer {
+		Container.registerIntervalJob(ctx, RemoveIdleMCPSessionsJob)
+	}
+
+	if config.EnableUpdateExchangeRatesHistory && config.ExchangeRatesDataSource != settings.UserCustomExchangeRatesDataSource {
+		Container.registerIntervalJob(ctx, UpdateExchangeRatesHistoryJob)
+	}
 }
 
 func (c *CronJobSchedulerContainer) registerIntervalJob(ctx core.Context, job *CronJob) {
@@ -4,7 +4,11 @@ import (
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -19,6 +23,45 @@ var RemoveExpiredTokensJob = &CronJob{
 	},
 }
 
+// RemoveExpiredAuditEventsJob represents the cron job which periodically remove expired security audit events from the database
+var RemoveExpiredAuditEventsJob = &CronJob{
+	Name:        "RemoveExpiredAuditEvents",
+	Description: "Periodically remove expired security audit events from the database.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 1,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.AuditEvents.DeleteAllExpiredAuditEvents(c)
+	},
+}
+
+// UpdateExchangeRatesHistoryJob represents the cron job which periodically save the latest exchange rates of the current data source as historical exchange rates
+var UpdateExchangeRatesHistoryJob = &CronJob{
+	Name:        "UpdateExchangeRatesHistory",
+	Description: "Periodically save the latest exchange rates of the current data source as historical exchange rates.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 23,
+	},
+	Run: func(c *core.CronContext) error {
+		config := settings.Container.GetCurrentConfig()
+		exchangeRateResp, err := exchangerates.Container.GetLatestExchangeRatesFromCurrentDataSource(c, 0, config)
+
+		if err != nil {
+			return err
+		}
+
+		exchangeRateHistoryResp, err := services.ExchangeRatesHistory.SaveExchangeRatesResponse(c, config.ExchangeRatesDataSource, exchangeRateResp)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof(c, "[cron_jobs.UpdateExchangeRatesHistoryJob] exchange rates of %s from \"%s\" have been saved", exchangeRateHistoryResp.Date, config.ExchangeRatesDataSource)
+
+		return nil
+	},
+}
+
 // CreateScheduledTransactionJob represents the cron job which periodically create transaction by scheduled transaction template
 var CreateScheduledTransactionJob = &CronJob{
 	Name:        "CreateScheduledTransaction",
@@ -30,3 +73,49 @@ var CreateScheduledTransactionJob = &CronJob{
 		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval())
 	},
 }
+
+// PostFixedIncomeInterestJob represents the cron job which periodically create income transactions for the due interests of fixed income terms
+var PostFixedIncomeInterestJob = &CronJob{
+	Name:        "PostFixedIncomeInterest",
+	Description: "Periodically create income transactions for the due interests of fixed income terms.",
+	Period: CronJobIntervalPeriod{
+		Interval: time.Hour,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.FixedIncomes.PostAllDueInterests(c, time.Now().Unix())
+	},
+}
+
+// RebuildTransactionSuggestionModelJob represents the cron job which periodically rebuild the transaction suggestion models of users whose transactions have been changed
+var RebuildTransactionSuggestionModelJob = &CronJob{
+	Name:        "RebuildTransactionSuggestionModel",
+	Description: "Periodically rebuild the transaction suggestion models of users whose transactions have been changed.",
+	Period: CronJobIntervalPeriod{
+		Interval: time.Hour,
+	},
+	Run: func(c *core.CronContext) error {
+		return services.TransactionSuggestions.RebuildAllOutdatedModels(c)
+	},
+}
+
+// RemoveIdleMCPSessionsJob represents the cron job which periodically remove idle mcp sessions and notify mcp clients when the current month resource changes
+var RemoveIdleMCPSessionsJob = &CronJob{
+	Name:        "RemoveIdleMCPSessions",
+	Description: "Periodically remove idle mcp sessions and notify mcp clients when the current month resource changes.",
+	Period: CronJobEvery15MinutesPeriod{
+		Second: 0,
+	},
+	Run: func(c *core.CronContext) error {
+		removedCount := mcp.Sessions.RemoveIdleSessions()
+
+		if removedCount > 0 {
+			log.Infof(c, "[cron_jobs.RemoveIdleMCPSessionsJob] %d idle mcp sessions have been removed", removedCount)
+		}
+
+		if mcp.Sessions.NotifyCurrentMonthResourceChanged() {
+			log.Infof(c, "[cron_jobs.RemoveIdleMCPSessionsJob] resource list changed notification has been sent to all mcp sessions")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,195 @@
+package cryptoprices
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const coinGeckoSimplePricePath = "/simple/price"
+const coinGeckoLastUpdatedAtField = "last_updated_at"
+
+// coinGeckoCoinIds maps the ticker symbols of common cryptocurrencies to the coin ids of CoinGecko,
+// other ticker symbols are used as the coin ids directly in lower case
+var coinGeckoCoinIds = map[string]string{
+	"ADA":   "cardano",
+	"ATOM":  "cosmos",
+	"AVAX":  "avalanche-2",
+	"BCH":   "bitcoin-cash",
+	"BNB":   "binancecoin",
+	"BTC":   "bitcoin",
+	"DAI":   "dai",
+	"DOGE":  "dogecoin",
+	"DOT":   "polkadot",
+	"ETC":   "ethereum-classic",
+	"ETH":   "ethereum",
+	"LINK":  "chainlink",
+	"LTC":   "litecoin",
+	"MATIC": "matic-network",
+	"SHIB":  "shiba-inu",
+	"SOL":   "solana",
+	"TON":   "the-open-network",
+	"TRX":   "tron",
+	"UNI":   "uniswap",
+	"USDC":  "usd-coin",
+	"USDT":  "tether",
+	"XLM":   "stellar",
+	"XMR":   "monero",
+	"XRP":   "ripple",
+}
+
+// CoinGeckoCryptoPricesDataSource defines the structure of CoinGecko-compatible cryptocurrency prices data source
+type CoinGeckoCryptoPricesDataSource struct {
+	CryptoPricesDataSource
+}
+
+// GetLatestCryptoPrice returns the latest price of the cryptocurrency quoted in the specified currency from the CoinGecko-compatible api
+func (d *CoinGeckoCryptoPricesDataSource) GetLatestCryptoPrice(c core.Context, currentConfig *settings.Config, tickerSymbol string, currency string) (*models.StockPrice, error) {
+	if tickerSymbol == "" {
+		return nil, errs.ErrTickerSymbolIsEmpty
+	}
+
+	coinId := GetCoinGeckoCoinId(tickerSymbol)
+	vsCurrency := strings.ToLower(currency)
+
+	req, err := d.buildRequest(currentConfig, coinId, vsCurrency)
+
+	if err != nil {
+		log.Errorf(c, "[coingecko_datasource.GetLatestCryptoPrice] failed to build request for \"%s\", because %s", tickerSymbol, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	resp, err := d.newHttpClient(currentConfig).Do(req)
+
+	if err != nil {
+		log.Errorf(c, "[coingecko_datasource.GetLatestCryptoPrice] failed to request price of \"%s\", because %s", tickerSymbol, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		log.Errorf(c, "[coingecko_datasource.GetLatestCryptoPrice] failed to get price of \"%s\", because response code is %d", tickerSymbol, resp.StatusCode)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		log.Errorf(c, "[coingecko_datasource.GetLatestCryptoPrice] failed to read response of \"%s\", because %s", tickerSymbol, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	log.Debugf(c, "[coingecko_datasource.GetLatestCryptoPrice] response is %s", body)
+
+	return d.parse(c, body, tickerSymbol, coinId, vsCurrency)
+}
+
+func (d *CoinGeckoCryptoPricesDataSource) buildRequest(currentConfig *settings.Config, coinId string, vsCurrency string) (*http.Request, error) {
+	params := url.Values{}
+	params.Set("ids", coinId)
+	params.Set("vs_currencies", vsCurrency)
+	params.Set("include_last_updated_at", "true")
+	params.Set("precision", "full")
+
+	req, err := http.NewRequest("GET", currentConfig.CryptoPricesDataSourceUrl+coinGeckoSimplePricePath+"?"+params.Encode(), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s", settings.Version))
+
+	if currentConfig.CryptoPricesApiKey != "" && currentConfig.CryptoPricesApiKeyHeader != "" {
+		req.Header.Set(currentConfig.CryptoPricesApiKeyHeader, currentConfig.CryptoPricesApiKey)
+	}
+
+	return req, nil
+}
+
+func (d *CoinGeckoCryptoPricesDataSource) newHttpClient(currentConfig *settings.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	utils.SetProxyUrl(transport, currentConfig.InvestmentPricesProxy)
+
+	if currentConfig.InvestmentPricesSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(currentConfig.InvestmentPricesRequestTimeout) * time.Millisecond,
+	}
+}
+
+func (d *CoinGeckoCryptoPricesDataSource) parse(c core.Context, content []byte, tickerSymbol string, coinId string, vsCurrency string) (*models.StockPrice, error) {
+	var priceResp map[string]map[string]json.Number
+	err := json.Unmarshal(content, &priceResp)
+
+	if err != nil {
+		log.Errorf(c, "[coingecko_datasource.parse] failed to parse response of \"%s\", because %s", tickerSymbol, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	coinPrices, exists := priceResp[coinId]
+
+	if !exists {
+		log.Warnf(c, "[coingecko_datasource.parse] no price of coin \"%s\" in response", coinId)
+		return nil, errs.ErrCryptoPriceNotFound
+	}
+
+	price, exists := coinPrices[vsCurrency]
+
+	if !exists {
+		log.Warnf(c, "[coingecko_datasource.parse] no price of coin \"%s\" quoted in \"%s\" in response", coinId, vsCurrency)
+		return nil, errs.ErrCryptoPriceNotFound
+	}
+
+	priceValue, err := price.Float64()
+
+	if err != nil || priceValue <= 0 {
+		log.Warnf(c, "[coingecko_datasource.parse] price \"%s\" of coin \"%s\" is invalid", price, coinId)
+		return nil, errs.ErrCryptoPriceNotFound
+	}
+
+	updateTime := time.Now().Unix()
+
+	if lastUpdatedAt, exists := coinPrices[coinGeckoLastUpdatedAtField]; exists {
+		if lastUpdatedAtValue, err := lastUpdatedAt.Int64(); err == nil && lastUpdatedAtValue > 0 {
+			updateTime = lastUpdatedAtValue
+		}
+	}
+
+	currency := strings.ToUpper(vsCurrency)
+
+	return &models.StockPrice{
+		TickerSymbol:    models.GetCryptoPriceTickerSymbol(tickerSymbol, currency),
+		CurrentPrice:    models.ConvertInvestmentPriceFromFloat64(priceValue, models.CryptocurrencyPricePrecision),
+		PricePrecision:  models.CryptocurrencyPricePrecision,
+		Currency:        currency,
+		LastUpdatedTime: updateTime,
+	}, nil
+}
+
+// GetCoinGeckoCoinId returns the coin id of CoinGecko for the specified ticker symbol
+func GetCoinGeckoCoinId(tickerSymbol string) string {
+	if coinId, exists := coinGeckoCoinIds[strings.ToUpper(tickerSymbol)]; exists {
+		return coinId
+	}
+
+	return strings.ToLower(tickerSymbol)
+}
@@ -0,0 +1,104 @@
+package cryptoprices
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+func TestCoinGeckoCryptoPricesDataSourceGetLatestCryptoPrice(t *testing.T) {
+	var actualPath string
+	var actualIds string
+	var actualVsCurrencies string
+	var actualApiKey string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualPath = r.URL.Path
+		actualIds = r.URL.Query().Get("ids")
+		actualVsCurrencies = r.URL.Query().Get("vs_currencies")
+		actualApiKey = r.Header.Get("x-cg-demo-api-key")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"bitcoin":{"usd":67187.33912345,"last_updated_at":1711356300}}`))
+	}))
+	defer server.Close()
+
+	config := newTestConfig(server.URL)
+	config.CryptoPricesApiKey = "test-key"
+
+	dataSource := &CoinGeckoCryptoPricesDataSource{}
+	stockPrice, err := dataSource.GetLatestCryptoPrice(core.NewNullContext(), config, "BTC", "USD")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "/simple/price", actualPath)
+	assert.Equal(t, "bitcoin", actualIds)
+	assert.Equal(t, "usd", actualVsCurrencies)
+	assert.Equal(t, "test-key", actualApiKey)
+	assert.Equal(t, "CRYPTO:BTC:USD", stockPrice.TickerSymbol)
+	assert.Equal(t, int64(6718733912345), stockPrice.CurrentPrice)
+	assert.Equal(t, models.CryptocurrencyPricePrecision, stockPrice.PricePrecision)
+	assert.Equal(t, "USD", stockPrice.Currency)
+	assert.Equal(t, int64(1711356300), stockPrice.LastUpdatedTime)
+}
+
+func TestCoinGeckoCryptoPricesDataSourceGetLatestCryptoPrice_SubCentPrice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"pepe":{"eur":0.00001234}}`))
+	}))
+	defer server.Close()
+
+	dataSource := &CoinGeckoCryptoPricesDataSource{}
+	stockPrice, err := dataSource.GetLatestCryptoPrice(core.NewNullContext(), newTestConfig(server.URL), "PEPE", "EUR")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "CRYPTO:PEPE:EUR", stockPrice.TickerSymbol)
+	assert.Equal(t, int64(1234), stockPrice.CurrentPrice)
+	assert.Equal(t, "EUR", stockPrice.Currency)
+}
+
+func TestCoinGeckoCryptoPricesDataSourceGetLatestCryptoPrice_CoinNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	dataSource := &CoinGeckoCryptoPricesDataSource{}
+	stockPrice, err := dataSource.GetLatestCryptoPrice(core.NewNullContext(), newTestConfig(server.URL), "UNKNOWN", "USD")
+
+	assert.Nil(t, stockPrice)
+	assert.Equal(t, errs.ErrCryptoPriceNotFound, err)
+}
+
+func TestCoinGeckoCryptoPricesDataSourceGetLatestCryptoPrice_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	dataSource := &CoinGeckoCryptoPricesDataSource{}
+	stockPrice, err := dataSource.GetLatestCryptoPrice(core.NewNullContext(), newTestConfig(server.URL), "ETH", "USD")
+
+	assert.Nil(t, stockPrice)
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+}
+
+func TestGetCoinGeckoCoinId(t *testing.T) {
+	assert.Equal(t, "bitcoin", GetCoinGeckoCoinId("BTC"))
+	assert.Equal(t, "ethereum", GetCoinGeckoCoinId("eth"))
+	assert.Equal(t, "pepe", GetCoinGeckoCoinId("PEPE"))
+}
+
+func newTestConfig(dataSourceUrl string) *settings.Config {
+	return &settings.Config{
+		CryptoPricesDataSourceUrl:      dataSourceUrl,
+		CryptoPricesApiKeyHeader:       "x-cg-demo-api-key",
+		InvestmentPricesRequestTimeout: 10000,
+		InvestmentPricesProxy:          "none",
+	}
+}
@@ -0,0 +1,18 @@
+package cryptoprices
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// CryptoPricesDataSource defines the structure of cryptocurrency prices data source
+type CryptoPricesDataSource interface {
+	// GetLatestCryptoPrice returns the latest price of the cryptocurrency quoted in the specified currency
+	GetLatestCryptoPrice(c core.Context, currentConfig *settings.Config, tickerSymbol string, currency string) (*models.StockPrice, error)
+}
+
+// Initialize a cryptocurrency prices data source singleton instance
+var (
+	Container CryptoPricesDataSource = &CoinGeckoCryptoPricesDataSource{}
+)
@@ -1,7 +1,11 @@
 package datastore
 
 import (
+	"fmt"
+	"sync"
+
 	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -9,8 +13,9 @@ import (
 
 // Database represents a database instance
 type Database struct {
-	databaseType string
-	engineGroup  *xorm.EngineGroup
+	databaseType    string
+	engineGroup     *xorm.EngineGroup
+	fullTextIndexes sync.Map
 }
 
 // NewSession starts a new session with the specified context
@@ -63,3 +68,68 @@ func (db *Database) RollbackToSavePoint(sess *xorm.Session, savePointName string
 
 	return nil
 }
+
+// WidenDecimalColumns enlarges the precision and scale of the decimal columns in the database table of the specified model when they are less than the model definition,
+// because the structure synchronization does not modify the type of existing columns
+func (db *Database) WidenDecimalColumns(bean any) error {
+	if db.databaseType == settings.Sqlite3DbType {
+		// sqlite does not enforce the precision and scale of decimal columns
+		return nil
+	}
+
+	table, err := db.engineGroup.TableInfo(bean)
+
+	if err != nil {
+		return err
+	}
+
+	for _, column := range table.Columns() {
+		if column.SQLType.Name != schemas.Decimal && column.SQLType.Name != schemas.Numeric {
+			continue
+		}
+
+		var precision, scale int64
+		has, err := db.engineGroup.SQL(db.getColumnNumericPrecisionAndScaleSql(), table.Name, column.Name).Get(&precision, &scale)
+
+		if err != nil {
+			return err
+		}
+
+		if !has || (precision >= column.Length && scale >= column.Length2) {
+			continue
+		}
+
+		_, err = db.engineGroup.Exec(db.getModifyColumnTypeSql(table.Name, column))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *Database) getColumnNumericPrecisionAndScaleSql() string {
+	if db.databaseType == settings.PostgresDbType {
+		return "SELECT numeric_precision, numeric_scale FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?"
+	}
+
+	return "SELECT NUMERIC_PRECISION, NUMERIC_SCALE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?"
+}
+
+func (db *Database) getModifyColumnTypeSql(tableName string, column *schemas.Column) string {
+	dialect := db.engineGroup.Dialect()
+	quoter := dialect.Quoter()
+
+	if db.databaseType == settings.PostgresDbType {
+		return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", quoter.Quote(tableName), quoter.Quote(column.Name), dialect.SQLType(column))
+	}
+
+	nullable := "NULL"
+
+	if !column.Nullable {
+		nullable = "NOT NULL"
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s %s", quoter.Quote(tableName), quoter.Quote(column.Name), dialect.SQLType(column), nullable)
+}
@@ -0,0 +1,242 @@
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// minimumFullTextSearchTermLength represents the minimum length of the term which can be searched by full-text index,
+// the sqlite trigram tokenizer and the default innodb_ft_min_token_size of mysql both require at least 3 characters
+const minimumFullTextSearchTermLength = 3
+
+var errFullTextSearchModuleNotAvailable = errors.New("sqlite fts5 module is not available, please build with \"sqlite_fts5\" tag")
+
+// SyncFullTextIndex creates the full-text index of the specified column in the database table of the specified model if it does not exist,
+// the index is maintained by sqlite fts5 virtual table and triggers, postgresql tsvector gin index or mysql fulltext index
+func (db *Database) SyncFullTextIndex(bean any, columnName string) error {
+	table, err := db.engineGroup.TableInfo(bean)
+
+	if err != nil {
+		return err
+	}
+
+	if db.databaseType == settings.Sqlite3DbType {
+		err = db.syncSqliteFullTextIndex(table, columnName)
+	} else if db.databaseType == settings.PostgresDbType {
+		_, err = db.engineGroup.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (to_tsvector('simple', %s))", getFullTextIndexName(table.Name, columnName), db.quote(table.Name), db.quote(columnName)))
+	} else if db.databaseType == settings.MySqlDbType {
+		var exists bool
+		exists, err = db.isFullTextIndexExists(table.Name, columnName)
+
+		if err == nil && !exists {
+			_, err = db.engineGroup.Exec(fmt.Sprintf("CREATE FULLTEXT INDEX %s ON %s (%s)", getFullTextIndexName(table.Name, columnName), db.quote(table.Name), db.quote(columnName)))
+		}
+	}
+
+	db.fullTextIndexes.Delete(getFullTextIndexName(table.Name, columnName))
+
+	return err
+}
+
+// IsFullTextIndexAvailable returns whether the full-text index of the specified column in the database table of the specified model can be used
+func (db *Database) IsFullTextIndexAvailable(bean any, columnName string) bool {
+	table, err := db.engineGroup.TableInfo(bean)
+
+	if err != nil {
+		return false
+	}
+
+	indexName := getFullTextIndexName(table.Name, columnName)
+
+	if available, exists := db.fullTextIndexes.Load(indexName); exists {
+		return available.(bool)
+	}
+
+	available, err := db.isFullTextIndexExists(table.Name, columnName)
+
+	if err != nil {
+		return false
+	}
+
+	db.fullTextIndexes.Store(indexName, available)
+
+	return available
+}
+
+// GetFullTextMatchCondition returns the sql condition and parameters which match the text in the specified column by full-text index,
+// returns false if the full-text index is not available or cannot be used for the text, and then the caller should fall back to other matching method
+func (db *Database) GetFullTextMatchCondition(bean any, columnName string, text string) (string, []any, bool) {
+	text = strings.TrimSpace(text)
+
+	if utf8.RuneCountInString(text) < minimumFullTextSearchTermLength || !db.IsFullTextIndexAvailable(bean, columnName) {
+		return "", nil, false
+	}
+
+	table, err := db.engineGroup.TableInfo(bean)
+
+	if err != nil || len(table.PrimaryKeys) != 1 {
+		return "", nil, false
+	}
+
+	if db.databaseType == settings.Sqlite3DbType {
+		// the trigram tokenizer matches any substring which has at least 3 characters, so it keeps the same semantics with "LIKE"
+		ftsTableName := getSqliteFullTextTableName(table.Name, columnName)
+		return fmt.Sprintf("%s IN (SELECT rowid FROM %s WHERE %s MATCH ?)", table.PrimaryKeys[0], ftsTableName, ftsTableName), []any{"\"" + strings.ReplaceAll(text, "\"", "\"\"") + "\""}, true
+	}
+
+	// postgresql and mysql only split words by spaces and punctuations, so only the words (or phrase) which consist of ascii letters and digits can be searched by full-text index
+	words := strings.Fields(strings.ToLower(text))
+
+	for i := 0; i < len(words); i++ {
+		if !isFullTextSearchableWord(words[i]) {
+			return "", nil, false
+		}
+	}
+
+	if db.databaseType == settings.PostgresDbType {
+		query := words[0] + ":*"
+
+		if len(words) > 1 {
+			query = strings.Join(words, " <-> ")
+		}
+
+		return fmt.Sprintf("to_tsvector('simple', %s) @@ to_tsquery('simple', ?)", columnName), []any{query}, true
+	} else if db.databaseType == settings.MySqlDbType {
+		for i := 0; i < len(words); i++ {
+			if len(words[i]) < minimumFullTextSearchTermLength {
+				return "", nil, false
+			}
+		}
+
+		query := "+" + words[0] + "*"
+
+		if len(words) > 1 {
+			query = "+\"" + strings.Join(words, " ") + "\""
+		}
+
+		return fmt.Sprintf("MATCH(%s) AGAINST(? IN BOOLEAN MODE)", columnName), []any{query}, true
+	}
+
+	return "", nil, false
+}
+
+func (db *Database) syncSqliteFullTextIndex(table *schemas.Table, columnName string) error {
+	if len(table.PrimaryKeys) != 1 {
+		return fmt.Errorf("table \"%s\" must have exactly one primary key", table.Name)
+	}
+
+	insertTriggerName, deleteTriggerName, updateTriggerName := getSqliteFullTextTriggerNames(table.Name, columnName)
+
+	var ftsEnabled bool
+	_, err := db.engineGroup.SQL("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Get(&ftsEnabled)
+
+	if err != nil {
+		return err
+	}
+
+	if !ftsEnabled {
+		// the triggers would break all writes of the table when fts5 module is not available, so they must be dropped
+		for _, triggerName := range []string{insertTriggerName, deleteTriggerName, updateTriggerName} {
+			if _, err := db.engineGroup.Exec("DROP TRIGGER IF EXISTS " + triggerName); err != nil {
+				return err
+			}
+		}
+
+		return errFullTextSearchModuleNotAvailable
+	}
+
+	var triggersCount int64
+	_, err = db.engineGroup.SQL("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN (?, ?, ?)", insertTriggerName, deleteTriggerName, updateTriggerName).Get(&triggersCount)
+
+	if err != nil {
+		return err
+	}
+
+	if triggersCount == 3 {
+		return nil
+	}
+
+	ftsTableName := getSqliteFullTextTableName(table.Name, columnName)
+	idColumnName := table.PrimaryKeys[0]
+
+	return db.DoTransaction(nil, func(sess *xorm.Session) error {
+		statements := []string{
+			fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content='%s', content_rowid='%s', tokenize='trigram')", ftsTableName, columnName, table.Name, idColumnName),
+			"DROP TRIGGER IF EXISTS " + insertTriggerName,
+			"DROP TRIGGER IF EXISTS " + deleteTriggerName,
+			"DROP TRIGGER IF EXISTS " + updateTriggerName,
+			fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT ON %s BEGIN INSERT INTO %s(rowid, %s) VALUES (new.%s, new.%s); END", insertTriggerName, db.quote(table.Name), ftsTableName, columnName, idColumnName, columnName),
+			fmt.Sprintf("CREATE TRIGGER %s AFTER DELETE ON %s BEGIN INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.%s, old.%s); END", deleteTriggerName, db.quote(table.Name), ftsTableName, ftsTableName, columnName, idColumnName, columnName),
+			fmt.Sprintf("CREATE TRIGGER %s AFTER UPDATE OF %s ON %s BEGIN INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.%s, old.%s); INSERT INTO %s(rowid, %s) VALUES (new.%s, new.%s); END", updateTriggerName, columnName, db.quote(table.Name), ftsTableName, ftsTableName, columnName, idColumnName, columnName, ftsTableName, columnName, idColumnName, columnName),
+			fmt.Sprintf("INSERT INTO %s(%s) VALUES ('rebuild')", ftsTableName, ftsTableName),
+		}
+
+		for i := 0; i < len(statements); i++ {
+			if _, err := sess.Exec(statements[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (db *Database) isFullTextIndexExists(tableName string, columnName string) (bool, error) {
+	var count int64
+	var err error
+
+	if db.databaseType == settings.Sqlite3DbType {
+		var ftsEnabled bool
+		_, err = db.engineGroup.SQL("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Get(&ftsEnabled)
+
+		if err != nil || !ftsEnabled {
+			return false, err
+		}
+
+		insertTriggerName, _, _ := getSqliteFullTextTriggerNames(tableName, columnName)
+		_, err = db.engineGroup.SQL("SELECT COUNT(*) FROM sqlite_master WHERE (type = 'table' AND name = ?) OR (type = 'trigger' AND name = ?)", getSqliteFullTextTableName(tableName, columnName), insertTriggerName).Get(&count)
+
+		return count == 2, err
+	} else if db.databaseType == settings.PostgresDbType {
+		_, err = db.engineGroup.SQL("SELECT COUNT(*) FROM pg_indexes WHERE schemaname = current_schema() AND tablename = ? AND indexname = ?", tableName, getFullTextIndexName(tableName, columnName)).Get(&count)
+	} else if db.databaseType == settings.MySqlDbType {
+		_, err = db.engineGroup.SQL("SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND INDEX_NAME = ?", tableName, getFullTextIndexName(tableName, columnName)).Get(&count)
+	}
+
+	return count > 0, err
+}
+
+func (db *Database) quote(name string) string {
+	return db.engineGroup.Dialect().Quoter().Quote(name)
+}
+
+func getFullTextIndexName(tableName string, columnName string) string {
+	return fmt.Sprintf("idx_%s_%s_fts", tableName, columnName)
+}
+
+func getSqliteFullTextTableName(tableName string, columnName string) string {
+	return fmt.Sprintf("%s_%s_fts", tableName, columnName)
+}
+
+func getSqliteFullTextTriggerNames(tableName string, columnName string) (string, string, string) {
+	ftsTableName := getSqliteFullTextTableName(tableName, columnName)
+	return ftsTableName + "_ai", ftsTableName + "_ad", ftsTableName + "_au"
+}
+
+func isFullTextSearchableWord(word string) bool {
+	for _, ch := range word {
+		if ch > unicode.MaxASCII || (!unicode.IsLetter(ch) && !unicode.IsDigit(ch)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+package datastore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type fullTextIndexTestItem struct {
+	ItemId  int64  `xorm:"PK"`
+	Comment string `xorm:"VARCHAR(255) NOT NULL"`
+}
+
+func (i *fullTextIndexTestItem) TableName() string {
+	return "full_text_index_test_item"
+}
+
+func TestGetFullTextMatchCondition_TextTooShort(t *testing.T) {
+	db := createFullTextIndexTestDatabase(t, settings.Sqlite3DbType, true)
+
+	_, _, ok := db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "ab")
+	assert.False(t, ok)
+
+	_, _, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "  ab  ")
+	assert.False(t, ok)
+}
+
+func TestGetFullTextMatchCondition_FullTextIndexNotAvailable(t *testing.T) {
+	db := createFullTextIndexTestDatabase(t, settings.Sqlite3DbType, false)
+
+	_, _, ok := db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "coffee")
+	assert.False(t, ok)
+}
+
+func TestGetFullTextMatchCondition_Sqlite(t *testing.T) {
+	db := createFullTextIndexTestDatabase(t, settings.Sqlite3DbType, true)
+
+	condition, conditionParams, ok := db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "coffee")
+	assert.True(t, ok)
+	assert.Equal(t, "item_id IN (SELECT rowid FROM full_text_index_test_item_comment_fts WHERE full_text_index_test_item_comment_fts MATCH ?)", condition)
+	assert.Equal(t, []any{"\"coffee\""}, conditionParams)
+
+	condition, conditionParams, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "hot \"coffee\"")
+	assert.True(t, ok)
+	assert.Equal(t, "item_id IN (SELECT rowid FROM full_text_index_test_item_comment_fts WHERE full_text_index_test_item_comment_fts MATCH ?)", condition)
+	assert.Equal(t, []any{"\"hot \"\"coffee\"\"\""}, conditionParams)
+
+	condition, conditionParams, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "咖啡店")
+	assert.True(t, ok)
+	assert.Equal(t, "item_id IN (SELECT rowid FROM full_text_index_test_item_comment_fts WHERE full_text_index_test_item_comment_fts MATCH ?)", condition)
+	assert.Equal(t, []any{"\"咖啡店\""}, conditionParams)
+}
+
+func TestGetFullTextMatchCondition_Postgres(t *testing.T) {
+	db := createFullTextIndexTestDatabase(t, settings.PostgresDbType, true)
+
+	condition, conditionParams, ok := db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "Coffee")
+	assert.True(t, ok)
+	assert.Equal(t, "to_tsvector('simple', comment) @@ to_tsquery('simple', ?)", condition)
+	assert.Equal(t, []any{"coffee:*"}, conditionParams)
+
+	condition, conditionParams, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "Hot Coffee")
+	assert.True(t, ok)
+	assert.Equal(t, "to_tsvector('simple', comment) @@ to_tsquery('simple', ?)", condition)
+	assert.Equal(t, []any{"hot <-> coffee"}, conditionParams)
+
+	_, _, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "coffee-shop")
+	assert.False(t, ok)
+
+	_, _, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "咖啡店")
+	assert.False(t, ok)
+}
+
+func TestGetFullTextMatchCondition_MySql(t *testing.T) {
+	db := createFullTextIndexTestDatabase(t, settings.MySqlDbType, true)
+
+	condition, conditionParams, ok := db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "Coffee")
+	assert.True(t, ok)
+	assert.Equal(t, "MATCH(comment) AGAINST(? IN BOOLEAN MODE)", condition)
+	assert.Equal(t, []any{"+coffee*"}, conditionParams)
+
+	condition, conditionParams, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "Hot Coffee")
+	assert.True(t, ok)
+	assert.Equal(t, "MATCH(comment) AGAINST(? IN BOOLEAN MODE)", condition)
+	assert.Equal(t, []any{"+\"hot coffee\""}, conditionParams)
+
+	_, _, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "a coffee")
+	assert.False(t, ok)
+
+	_, _, ok = db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "咖啡店")
+	assert.False(t, ok)
+}
+
+func TestSyncFullTextIndex_SqliteTriggers(t *testing.T) {
+	db := createFullTextIndexTestDatabase(t, settings.Sqlite3DbType, false)
+
+	_, err := db.engineGroup.Insert(&fullTextIndexTestItem{ItemId: 1, Comment: "Morning coffee"})
+	assert.Nil(t, err)
+
+	err = db.SyncFullTextIndex(&fullTextIndexTestItem{}, "comment")
+
+	if err == errFullTextSearchModuleNotAvailable {
+		// the full-text index is not available without "sqlite_fts5" build tag, so the caller must fall back to "LIKE"
+		assert.False(t, db.IsFullTextIndexAvailable(&fullTextIndexTestItem{}, "comment"))
+
+		_, _, ok := db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", "coffee")
+		assert.False(t, ok)
+
+		_, err = db.engineGroup.Insert(&fullTextIndexTestItem{ItemId: 2, Comment: "Afternoon tea"})
+		assert.Nil(t, err)
+
+		t.Skip("sqlite fts5 module is not available, skip testing triggers")
+	}
+
+	assert.Nil(t, err)
+	assert.True(t, db.IsFullTextIndexAvailable(&fullTextIndexTestItem{}, "comment"))
+
+	// existed rows are indexed by rebuilding
+	assert.Equal(t, []int64{1}, findFullTextIndexTestItemIds(t, db, "coffee"))
+
+	// insert trigger
+	_, err = db.engineGroup.Insert(&fullTextIndexTestItem{ItemId: 2, Comment: "Afternoon coffee"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2}, findFullTextIndexTestItemIds(t, db, "coffee"))
+
+	// update trigger
+	_, err = db.engineGroup.ID(1).Cols("comment").Update(&fullTextIndexTestItem{Comment: "Morning tea"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, findFullTextIndexTestItemIds(t, db, "coffee"))
+	assert.Equal(t, []int64{1}, findFullTextIndexTestItemIds(t, db, "tea"))
+
+	// delete trigger
+	_, err = db.engineGroup.ID(2).Delete(&fullTextIndexTestItem{})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{}, findFullTextIndexTestItemIds(t, db, "coffee"))
+
+	// sync again would not recreate the existed triggers
+	err = db.SyncFullTextIndex(&fullTextIndexTestItem{}, "comment")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, findFullTextIndexTestItemIds(t, db, "tea"))
+}
+
+func createFullTextIndexTestDatabase(t *testing.T, databaseType string, fullTextIndexAvailable bool) *Database {
+	engineGroup, err := xorm.NewEngineGroup(settings.Sqlite3DbType, []string{filepath.Join(t.TempDir(), "test.db")})
+	assert.Nil(t, err)
+
+	t.Cleanup(func() {
+		_ = engineGroup.Close()
+	})
+
+	err = engineGroup.Sync2(&fullTextIndexTestItem{})
+	assert.Nil(t, err)
+
+	db := &Database{
+		databaseType: databaseType,
+		engineGroup:  engineGroup,
+	}
+
+	if fullTextIndexAvailable {
+		db.fullTextIndexes.Store(getFullTextIndexName("full_text_index_test_item", "comment"), true)
+	}
+
+	return db
+}
+
+func findFullTextIndexTestItemIds(t *testing.T, db *Database, text string) []int64 {
+	condition, conditionParams, ok := db.GetFullTextMatchCondition(&fullTextIndexTestItem{}, "comment", text)
+	assert.True(t, ok)
+
+	var items []*fullTextIndexTestItem
+	err := db.engineGroup.Where(condition, conditionParams...).OrderBy("item_id").Find(&items)
+	assert.Nil(t, err)
+
+	ids := make([]int64, len(items))
+
+	for i := 0; i < len(items); i++ {
+		ids[i] = items[i].ItemId
+	}
+
+	return ids
+}
@@ -52,6 +52,32 @@ func (s *DataStore) SyncStructs(beans ...any) error {
 	return err
 }
 
+// WidenDecimalColumns enlarges the precision and scale of the decimal columns in all databases by database model
+func (s *DataStore) WidenDecimalColumns(bean any) error {
+	for i := 0; i < len(s.databases); i++ {
+		err := s.databases[i].WidenDecimalColumns(bean)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SyncFullTextIndex creates the full-text index of the specified column in all databases by database model
+func (s *DataStore) SyncFullTextIndex(bean any, columnName string) error {
+	for i := 0; i < len(s.databases); i++ {
+		err := s.databases[i].SyncFullTextIndex(bean, columnName)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // NewDataStore returns a new data storage by a series of database
 func NewDataStore(databases ...*Database) (*DataStore, error) {
 	if len(databases) < 1 {
@@ -30,4 +30,10 @@ var (
 	ErrInvalidAmountExpression             = NewNormalError(NormalSubcategoryConverter, 23, http.StatusBadRequest, "invalid amount expression")
 	ErrInvalidXmlFile                      = NewNormalError(NormalSubcategoryConverter, 24, http.StatusBadRequest, "invalid xml file")
 	ErrInvalidMT940File                    = NewNormalError(NormalSubcategoryConverter, 25, http.StatusBadRequest, "invalid mt940 file")
+	ErrInvalidZipFile                      = NewNormalError(NormalSubcategoryConverter, 26, http.StatusBadRequest, "invalid zip file")
+	ErrInvalidMoneyManagerExFile           = NewNormalError(NormalSubcategoryConverter, 27, http.StatusBadRequest, "invalid money manager ex file")
+	ErrInvalidLedgerFile                   = NewNormalError(NormalSubcategoryConverter, 28, http.StatusBadRequest, "invalid ledger file")
+	ErrLedgerFileNotSupportInclude         = NewNormalError(NormalSubcategoryConverter, 29, http.StatusBadRequest, "include directive is only supported for ledger files in zip archive")
+	ErrLedgerIncludedFileNotFound          = NewNormalError(NormalSubcategoryConverter, 30, http.StatusBadRequest, "included ledger file is not found in zip archive")
+	ErrLedgerMainFileNotFound              = NewNormalError(NormalSubcategoryConverter, 31, http.StatusBadRequest, "cannot find main ledger file in zip archive")
 )
@@ -40,6 +40,13 @@ const (
 	NormalSubcategoryConverter              = 12
 	NormalSubcategoryUserCustomExchangeRate = 13
 	NormalSubcategoryModelContextProtocol   = 14
+	NormalSubcategoryTransactionRule        = 16
+	NormalSubcategoryExternalAuth           = 17
+	NormalSubcategoryWebAuthn               = 18
+	NormalSubcategoryExchangeRate           = 19
+	NormalSubcategoryFixedIncome            = 20
+	NormalSubcategoryLoan                   = 21
+	NormalSubcategoryPayee                  = 22
 )
 
 // Error represents the specific error returned to user
@@ -0,0 +1,11 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rates
+var (
+	ErrExchangeRatesHistoryNotSupported    = NewNormalError(NormalSubcategoryExchangeRate, 0, http.StatusBadRequest, "current exchange rates data source does not support historical exchange rates")
+	ErrExchangeRatesHistoryNotFound        = NewNormalError(NormalSubcategoryExchangeRate, 1, http.StatusNotFound, "historical exchange rates data not found")
+	ErrExchangeRatesDateInvalid            = NewNormalError(NormalSubcategoryExchangeRate, 2, http.StatusBadRequest, "exchange rates date is invalid")
+	ErrTooManyExchangeRatesHistoryRequests = NewNormalError(NormalSubcategoryExchangeRate, 3, http.StatusBadRequest, "too many historical exchange rates requests")
+)
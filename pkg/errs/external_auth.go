@@ -0,0 +1,18 @@
+package errs
+
+import "net/http"
+
+// Error codes related to external authentication
+var (
+	ErrOIDCAuthNotEnabled            = NewNormalError(NormalSubcategoryExternalAuth, 0, http.StatusBadRequest, "oidc authentication is not enabled")
+	ErrOIDCLoginStateInvalid         = NewNormalError(NormalSubcategoryExternalAuth, 1, http.StatusBadRequest, "oidc login state is invalid or expired")
+	ErrOIDCAuthFailed                = NewNormalError(NormalSubcategoryExternalAuth, 2, http.StatusUnauthorized, "oidc authentication failed")
+	ErrOIDCUserInfoInvalid           = NewNormalError(NormalSubcategoryExternalAuth, 3, http.StatusBadRequest, "oidc user info is invalid")
+	ErrExternalAuthUserNotLinked     = NewNormalError(NormalSubcategoryExternalAuth, 4, http.StatusBadRequest, "external account is not linked to any user")
+	ErrExternalAuthAlreadyLinked     = NewNormalError(NormalSubcategoryExternalAuth, 5, http.StatusBadRequest, "external account has already been linked to another user")
+	ErrUserExternalAuthAlreadyExists = NewNormalError(NormalSubcategoryExternalAuth, 6, http.StatusBadRequest, "user has already linked an external account of this type")
+	ErrUserExternalAuthNotFound      = NewNormalError(NormalSubcategoryExternalAuth, 7, http.StatusBadRequest, "user external account is not found")
+	ErrLDAPUserNotFound              = NewNormalError(NormalSubcategoryExternalAuth, 8, http.StatusUnauthorized, "ldap user is not found")
+	ErrLDAPAuthFailed                = NewNormalError(NormalSubcategoryExternalAuth, 9, http.StatusUnauthorized, "ldap authentication failed")
+	ErrLDAPUserInfoInvalid           = NewNormalError(NormalSubcategoryExternalAuth, 10, http.StatusBadRequest, "ldap user info is invalid")
+)
@@ -0,0 +1,19 @@
+package errs
+
+import "net/http"
+
+// Error codes related to fixed income terms
+var (
+	ErrFixedIncomeTermNotFound                 = NewNormalError(NormalSubcategoryFixedIncome, 0, http.StatusBadRequest, "fixed income term not found")
+	ErrFixedIncomeAccountCategoryInvalid       = NewNormalError(NormalSubcategoryFixedIncome, 1, http.StatusBadRequest, "account category does not support fixed income term")
+	ErrFixedIncomeInterestRateInvalid          = NewNormalError(NormalSubcategoryFixedIncome, 2, http.StatusBadRequest, "fixed income interest rate is invalid")
+	ErrFixedIncomeCompoundingFrequencyInvalid  = NewNormalError(NormalSubcategoryFixedIncome, 3, http.StatusBadRequest, "fixed income compounding frequency is invalid")
+	ErrFixedIncomePayoutFrequencyInvalid       = NewNormalError(NormalSubcategoryFixedIncome, 4, http.StatusBadRequest, "fixed income payout frequency is invalid")
+	ErrFixedIncomeStartDateInvalid             = NewNormalError(NormalSubcategoryFixedIncome, 5, http.StatusBadRequest, "fixed income start date is invalid")
+	ErrFixedIncomeMaturityDateInvalid          = NewNormalError(NormalSubcategoryFixedIncome, 6, http.StatusBadRequest, "fixed income maturity date is invalid")
+	ErrFixedIncomeInterestCategoryInvalid      = NewNormalError(NormalSubcategoryFixedIncome, 7, http.StatusBadRequest, "fixed income interest category is invalid")
+	ErrFixedIncomePayoutAccountCurrencyInvalid = NewNormalError(NormalSubcategoryFixedIncome, 8, http.StatusBadRequest, "fixed income payout account currency does not match")
+	ErrFixedIncomeInterestAlreadyPosted        = NewNormalError(NormalSubcategoryFixedIncome, 9, http.StatusBadRequest, "fixed income interest has already been posted")
+	ErrFixedIncomePayoutAccountRequired        = NewNormalError(NormalSubcategoryFixedIncome, 10, http.StatusBadRequest, "fixed income payout account is required")
+	ErrFixedIncomeInvestmentAssetTypeInvalid   = NewNormalError(NormalSubcategoryFixedIncome, 11, http.StatusBadRequest, "investment asset type does not support fixed income term")
+)
@@ -7,20 +7,28 @@ const (
 
 var (
 	// Investment
-	ErrInvestmentIdInvalid         = NewNormalError(NormalSubcategoryInvestment, 1, 400, "Investment id is invalid")
-	ErrInvestmentNotFound          = NewNormalError(NormalSubcategoryInvestment, 2, 400, "Investment not found")
-	ErrInvestmentAlreadyExists     = NewNormalError(NormalSubcategoryInvestment, 3, 400, "Investment already exists for this ticker")
-	ErrTickerSymbolIsEmpty         = NewNormalError(NormalSubcategoryInvestment, 4, 400, "Ticker symbol cannot be empty")
-	ErrInvalidSharesAmount         = NewNormalError(NormalSubcategoryInvestment, 5, 400, "Invalid number of shares")
-	ErrInvalidCostPerShare         = NewNormalError(NormalSubcategoryInvestment, 6, 400, "Invalid cost per share")
-	ErrInvalidPricePerShare        = NewNormalError(NormalSubcategoryInvestment, 7, 400, "Invalid price per share")
-	ErrInsufficientShares          = NewNormalError(NormalSubcategoryInvestment, 8, 400, "Insufficient shares for this transaction")
-	
+	ErrInvestmentIdInvalid                = NewNormalError(NormalSubcategoryInvestment, 1, 400, "Investment id is invalid")
+	ErrInvestmentNotFound                 = NewNormalError(NormalSubcategoryInvestment, 2, 400, "Investment not found")
+	ErrInvestmentAlreadyExists            = NewNormalError(NormalSubcategoryInvestment, 3, 400, "Investment already exists for this ticker")
+	ErrTickerSymbolIsEmpty                = NewNormalError(NormalSubcategoryInvestment, 4, 400, "Ticker symbol cannot be empty")
+	ErrInvalidSharesAmount                = NewNormalError(NormalSubcategoryInvestment, 5, 400, "Invalid number of shares")
+	ErrInvalidCostPerShare                = NewNormalError(NormalSubcategoryInvestment, 6, 400, "Invalid cost per share")
+	ErrInvalidPricePerShare               = NewNormalError(NormalSubcategoryInvestment, 7, 400, "Invalid price per share")
+	ErrInsufficientShares                 = NewNormalError(NormalSubcategoryInvestment, 8, 400, "Insufficient shares for this transaction")
+	ErrInvestmentCurrencyInvalid          = NewNormalError(NormalSubcategoryInvestment, 9, 400, "Investment currency is invalid")
+	ErrInvestmentAssetTypeInvalid         = NewNormalError(NormalSubcategoryInvestment, 10, 400, "Investment asset type is invalid")
+	ErrInvestmentQuantityPrecisionInvalid = NewNormalError(NormalSubcategoryInvestment, 11, 400, "Investment quantity precision is invalid")
+	ErrInvestmentPricePrecisionInvalid    = NewNormalError(NormalSubcategoryInvestment, 12, 400, "Investment price precision is invalid")
+	ErrInvestmentWalletAddressInvalid     = NewNormalError(NormalSubcategoryInvestment, 13, 400, "Wallet address is only available for cryptocurrency holdings")
+	ErrPreciousMetalNotSupported          = NewNormalError(NormalSubcategoryInvestment, 14, 400, "Precious metal is not supported")
+	ErrPreciousMetalCurrencyNotSupported  = NewNormalError(NormalSubcategoryInvestment, 15, 400, "Precious metal holding only supports USD")
+
 	// Stock Price
-	ErrSymbolIsRequired            = NewNormalError(NormalSubcategoryInvestment, 101, 400, "Symbol is required")
-	ErrSymbolsRequired             = NewNormalError(NormalSubcategoryInvestment, 102, 400, "Symbols are required")
-	ErrStockQuoteNotFound          = NewNormalError(NormalSubcategoryInvestment, 103, 400, "Stock quote not found")
-	ErrStockQuoteFetchFailed       = NewNormalError(NormalSubcategoryInvestment, 104, 503, "Failed to fetch stock quote")
-	ErrStockPriceNotFound          = NewNormalError(NormalSubcategoryInvestment, 105, 400, "Stock price not found")
+	ErrSymbolIsRequired             = NewNormalError(NormalSubcategoryInvestment, 101, 400, "Symbol is required")
+	ErrSymbolsRequired              = NewNormalError(NormalSubcategoryInvestment, 102, 400, "Symbols are required")
+	ErrStockQuoteNotFound           = NewNormalError(NormalSubcategoryInvestment, 103, 400, "Stock quote not found")
+	ErrStockQuoteFetchFailed        = NewNormalError(NormalSubcategoryInvestment, 104, 503, "Failed to fetch stock quote")
+	ErrStockPriceNotFound           = NewNormalError(NormalSubcategoryInvestment, 105, 400, "Stock price not found")
 	ErrStockPriceServiceUnavailable = NewNormalError(NormalSubcategoryInvestment, 106, 503, "Stock price service unavailable")
-)
\ No newline at end of file
+	ErrCryptoPriceNotFound          = NewNormalError(NormalSubcategoryInvestment, 107, 400, "Cryptocurrency price not found")
+)
@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to loan terms
+var (
+	ErrLoanTermNotFound                  = NewNormalError(NormalSubcategoryLoan, 0, http.StatusBadRequest, "loan term not found")
+	ErrLoanAccountCategoryInvalid        = NewNormalError(NormalSubcategoryLoan, 1, http.StatusBadRequest, "account category does not support loan term")
+	ErrLoanInterestRateInvalid           = NewNormalError(NormalSubcategoryLoan, 2, http.StatusBadRequest, "loan interest rate is invalid")
+	ErrLoanStartDateInvalid              = NewNormalError(NormalSubcategoryLoan, 3, http.StatusBadRequest, "loan start date is invalid")
+	ErrLoanInterestCategoryInvalid       = NewNormalError(NormalSubcategoryLoan, 4, http.StatusBadRequest, "loan interest category is invalid")
+	ErrLoanExtraPaymentDateInvalid       = NewNormalError(NormalSubcategoryLoan, 5, http.StatusBadRequest, "loan extra payment date is invalid")
+	ErrLoanPaymentAccountCurrencyInvalid = NewNormalError(NormalSubcategoryLoan, 6, http.StatusBadRequest, "loan payment account currency does not match")
+	ErrLoanPaymentSourceAccountInvalid   = NewNormalError(NormalSubcategoryLoan, 7, http.StatusBadRequest, "loan payment source account is invalid")
+)
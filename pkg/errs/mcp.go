@@ -4,5 +4,12 @@ import "net/http"
 
 // Error codes related to model context protocol server
 var (
-	ErrMCPServerNotEnabled = NewNormalError(NormalSubcategoryModelContextProtocol, 0, http.StatusBadRequest, "mcp server is not enabled")
+	ErrMCPServerNotEnabled           = NewNormalError(NormalSubcategoryModelContextProtocol, 0, http.StatusBadRequest, "mcp server is not enabled")
+	ErrMCPResourceNotFound           = NewNormalError(NormalSubcategoryModelContextProtocol, 1, http.StatusNotFound, "mcp resource not found")
+	ErrMCPPromptNotFound             = NewNormalError(NormalSubcategoryModelContextProtocol, 2, http.StatusNotFound, "mcp prompt not found")
+	ErrMCPSessionIdIsEmpty           = NewNormalError(NormalSubcategoryModelContextProtocol, 3, http.StatusBadRequest, "mcp session id is empty")
+	ErrMCPSessionNotFound            = NewNormalError(NormalSubcategoryModelContextProtocol, 4, http.StatusNotFound, "mcp session not found")
+	ErrMCPSessionStreamAlreadyOpened = NewNormalError(NormalSubcategoryModelContextProtocol, 5, http.StatusConflict, "mcp session stream has already been opened")
+	ErrMCPEventStreamNotAccepted     = NewNormalError(NormalSubcategoryModelContextProtocol, 6, http.StatusNotAcceptable, "mcp client does not accept event stream")
+	ErrMCPSessionCountLimitReached   = NewNormalError(NormalSubcategoryModelContextProtocol, 7, http.StatusTooManyRequests, "mcp session count has reached the limit")
 )
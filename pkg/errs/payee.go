@@ -0,0 +1,16 @@
+package errs
+
+import "net/http"
+
+// Error codes related to payees
+var (
+	ErrPayeeIdInvalid             = NewNormalError(NormalSubcategoryPayee, 0, http.StatusBadRequest, "payee id is invalid")
+	ErrPayeeNotFound              = NewNormalError(NormalSubcategoryPayee, 1, http.StatusBadRequest, "payee not found")
+	ErrPayeeNameIsEmpty           = NewNormalError(NormalSubcategoryPayee, 2, http.StatusBadRequest, "payee name is empty")
+	ErrPayeeNameAlreadyExists     = NewNormalError(NormalSubcategoryPayee, 3, http.StatusBadRequest, "payee name already exists")
+	ErrPayeeInUseCannotBeDeleted  = NewNormalError(NormalSubcategoryPayee, 4, http.StatusBadRequest, "payee is in use and cannot be deleted")
+	ErrPayeeHasTooManyAliases     = NewNormalError(NormalSubcategoryPayee, 5, http.StatusBadRequest, "payee has too many aliases")
+	ErrPayeeAliasInvalid          = NewNormalError(NormalSubcategoryPayee, 6, http.StatusBadRequest, "payee alias is invalid")
+	ErrCannotMergePayeeIntoItself = NewNormalError(NormalSubcategoryPayee, 7, http.StatusBadRequest, "cannot merge payee into itself")
+	ErrCannotUseHiddenPayee       = NewNormalError(NormalSubcategoryPayee, 8, http.StatusBadRequest, "cannot use hidden payee")
+)
@@ -24,4 +24,7 @@ var (
 	ErrInvalidPasswordResetTokenExpiredTime           = NewSystemError(SystemSubcategorySetting, 17, http.StatusInternalServerError, "invalid password reset token expired time")
 	ErrInvalidExchangeRatesDataSource                 = NewSystemError(SystemSubcategorySetting, 18, http.StatusInternalServerError, "invalid exchange rates data source")
 	ErrInvalidIpAddressPattern                        = NewSystemError(SystemSubcategorySetting, 19, http.StatusInternalServerError, "invalid ip address pattern")
+	ErrInvalidOIDCConfig                              = NewSystemError(SystemSubcategorySetting, 20, http.StatusInternalServerError, "invalid oidc config")
+	ErrInvalidLDAPConfig                              = NewSystemError(SystemSubcategorySetting, 21, http.StatusInternalServerError, "invalid ldap config")
+	ErrInvalidWebAuthnConfig                          = NewSystemError(SystemSubcategorySetting, 22, http.StatusInternalServerError, "invalid webauthn config")
 )
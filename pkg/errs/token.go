@@ -21,4 +21,6 @@ var (
 	ErrTokenIsEmpty                         = NewNormalError(NormalSubcategoryToken, 12, http.StatusBadRequest, "token is empty")
 	ErrEmailVerifyTokenIsInvalidOrExpired   = NewNormalError(NormalSubcategoryToken, 13, http.StatusBadRequest, "email verify token is invalid or expired")
 	ErrPasswordResetTokenIsInvalidOrExpired = NewNormalError(NormalSubcategoryToken, 14, http.StatusBadRequest, "password reset token is invalid or expired")
+	ErrTokenPermissionInvalid               = NewNormalError(NormalSubcategoryToken, 15, http.StatusBadRequest, "token permission is invalid")
+	ErrCurrentTokenPermissionDenied         = NewNormalError(NormalSubcategoryToken, 16, http.StatusForbidden, "current token does not have permission to access this api")
 )
@@ -41,4 +41,9 @@ var (
 	ErrImportFileTransactionTypeMappingInvalid                  = NewSystemError(NormalSubcategoryTransaction, 34, http.StatusBadRequest, "transaction type mapping invalid")
 	ErrImportFileTransactionTimeFormatInvalid                   = NewSystemError(NormalSubcategoryTransaction, 35, http.StatusBadRequest, "transaction time format invalid")
 	ErrImportFileTransactionTimezoneFormatInvalid               = NewSystemError(NormalSubcategoryTransaction, 36, http.StatusBadRequest, "transaction time zone format invalid")
+	ErrTransactionSplitsNotSupported                            = NewNormalError(NormalSubcategoryTransaction, 37, http.StatusBadRequest, "only income and expense transaction can be split")
+	ErrTransactionSplitCountInvalid                             = NewNormalError(NormalSubcategoryTransaction, 38, http.StatusBadRequest, "transaction split count is invalid")
+	ErrTransactionSplitsAmountNotEqual                          = NewNormalError(NormalSubcategoryTransaction, 39, http.StatusBadRequest, "sum of transaction split amounts is not equal to transaction amount")
+	ErrTransactionSearchQueryInvalid                            = NewNormalError(NormalSubcategoryTransaction, 40, http.StatusBadRequest, "transaction search query is invalid")
+	ErrTransactionSearchQueryTooComplex                         = NewNormalError(NormalSubcategoryTransaction, 41, http.StatusBadRequest, "transaction search query is too complex")
 )
@@ -0,0 +1,20 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction rules
+var (
+	ErrTransactionRuleIdInvalid                   = NewNormalError(NormalSubcategoryTransactionRule, 0, http.StatusBadRequest, "transaction rule id is invalid")
+	ErrTransactionRuleNotFound                    = NewNormalError(NormalSubcategoryTransactionRule, 1, http.StatusBadRequest, "transaction rule not found")
+	ErrTransactionRuleHasNoCondition              = NewNormalError(NormalSubcategoryTransactionRule, 2, http.StatusBadRequest, "transaction rule must have at least one condition")
+	ErrTransactionRuleHasNoAction                 = NewNormalError(NormalSubcategoryTransactionRule, 3, http.StatusBadRequest, "transaction rule must have at least one action")
+	ErrTransactionRuleCommentPatternInvalid       = NewNormalError(NormalSubcategoryTransactionRule, 4, http.StatusBadRequest, "transaction rule comment pattern is invalid")
+	ErrTransactionRuleCounterpartyPatternInvalid  = NewNormalError(NormalSubcategoryTransactionRule, 5, http.StatusBadRequest, "transaction rule counterparty pattern is invalid")
+	ErrTransactionRuleAmountRangeInvalid          = NewNormalError(NormalSubcategoryTransactionRule, 6, http.StatusBadRequest, "transaction rule amount range is invalid")
+	ErrTransactionRuleHasTooManyTags              = NewNormalError(NormalSubcategoryTransactionRule, 7, http.StatusBadRequest, "transaction rule has too many tags")
+	ErrTransactionRuleTypeRequiredForCategory     = NewNormalError(NormalSubcategoryTransactionRule, 8, http.StatusBadRequest, "transaction rule must specify transaction type when setting category")
+	ErrTransactionRuleTypeInvalid                 = NewNormalError(NormalSubcategoryTransactionRule, 9, http.StatusBadRequest, "transaction rule transaction type is invalid")
+	ErrTransactionRuleSplitCountInvalid           = NewNormalError(NormalSubcategoryTransactionRule, 10, http.StatusBadRequest, "transaction rule split count is invalid")
+	ErrTransactionRuleSplitRatioInvalid           = NewNormalError(NormalSubcategoryTransactionRule, 11, http.StatusBadRequest, "transaction rule split ratios are invalid")
+	ErrTransactionRuleCategoryAndSplitsConflicted = NewNormalError(NormalSubcategoryTransactionRule, 12, http.StatusBadRequest, "transaction rule cannot set both category and split lines")
+)
@@ -7,4 +7,6 @@ var (
 	ErrUserCustomExchangeRateNotFound             = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 0, http.StatusBadRequest, "user custom exchange rate data not found")
 	ErrCannotUpdateExchangeRateForDefaultCurrency = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 1, http.StatusBadRequest, "cannot update exchange rate data for base currency")
 	ErrCannotDeleteExchangeRateForDefaultCurrency = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 2, http.StatusBadRequest, "cannot delete exchange rate data for base currency")
+	ErrUserCustomExchangeRateInvalid              = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 3, http.StatusBadRequest, "user custom exchange rate is invalid")
+	ErrUserCustomExchangeRateEffectiveDateInvalid = NewNormalError(NormalSubcategoryUserCustomExchangeRate, 4, http.StatusBadRequest, "user custom exchange rate effective date is invalid")
 )
@@ -0,0 +1,18 @@
+package errs
+
+import "net/http"
+
+// Error codes related to webauthn
+var (
+	ErrWebAuthnNotEnabled                    = NewNormalError(NormalSubcategoryWebAuthn, 0, http.StatusBadRequest, "passkey is not enabled")
+	ErrWebAuthnPasswordlessLoginNotEnabled   = NewNormalError(NormalSubcategoryWebAuthn, 1, http.StatusBadRequest, "passwordless login is not enabled")
+	ErrWebAuthnSessionInvalid                = NewNormalError(NormalSubcategoryWebAuthn, 2, http.StatusBadRequest, "passkey session is invalid or expired")
+	ErrWebAuthnCredentialInvalid             = NewNormalError(NormalSubcategoryWebAuthn, 3, http.StatusBadRequest, "passkey credential is invalid")
+	ErrWebAuthnAttestationFormatNotSupported = NewNormalError(NormalSubcategoryWebAuthn, 4, http.StatusBadRequest, "passkey attestation format is not supported")
+	ErrWebAuthnCredentialAlreadyExists       = NewNormalError(NormalSubcategoryWebAuthn, 5, http.StatusBadRequest, "passkey has already been registered")
+	ErrWebAuthnCredentialNotFound            = NewNormalError(NormalSubcategoryWebAuthn, 6, http.StatusBadRequest, "passkey is not found")
+	ErrWebAuthnCredentialIdInvalid           = NewNormalError(NormalSubcategoryWebAuthn, 7, http.StatusBadRequest, "passkey id is invalid")
+	ErrWebAuthnAssertionInvalid              = NewNormalError(NormalSubcategoryWebAuthn, 8, http.StatusUnauthorized, "passkey verification failed")
+	ErrWebAuthnSignCountInvalid              = NewNormalError(NormalSubcategoryWebAuthn, 9, http.StatusUnauthorized, "passkey sign counter is invalid")
+	ErrWebAuthnCredentialCountLimitReached   = NewNormalError(NormalSubcategoryWebAuthn, 10, http.StatusBadRequest, "registered passkeys have reached the limit")
+)
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
@@ -16,12 +17,14 @@ import (
 )
 
 const bankOfCanadaExchangeRateUrl = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?recent=1"
+const bankOfCanadaHistoricalExchangeRateUrlFormat = "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?start_date=%s&end_date=%s"
 const bankOfCanadaExchangeRateReferenceUrl = "https://www.bankofcanada.ca/rates/exchange/daily-exchange-rates/"
 const bankOfCanadaDataSource = "Bank of Canada"
 const bankOfCanadaBaseCurrency = "CAD"
 
 const bankOfCanadaDataUpdateDateFormat = "2006-01-02 15:04"
 const bankOfCanadaDataUpdateDateTimezone = "America/Toronto"
+const bankOfCanadaHistoricalLookbackDays = 7
 
 // BankOfCanadaDataSource defines the structure of exchange rates data source of bank of Canada
 type BankOfCanadaDataSource struct {
@@ -141,6 +144,18 @@ func (e *BankOfCanadaDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildRequestsByDate returns the bank of Canada historical exchange rates http requests
+func (e *BankOfCanadaDataSource) BuildRequestsByDate(date time.Time) ([]*http.Request, error) {
+	startDate := date.AddDate(0, 0, -bankOfCanadaHistoricalLookbackDays) // Exchange rates are not published on weekends and holidays
+	req, err := http.NewRequest("GET", fmt.Sprintf(bankOfCanadaHistoricalExchangeRateUrlFormat, startDate.Format(time.DateOnly), date.Format(time.DateOnly)), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
 // Parse returns the common response entity according to the bank of Canada data source raw response
 func (e *BankOfCanadaDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	bankOfCanadaData := &BankOfCanadaExchangeRateData{}
@@ -160,3 +175,8 @@ func (e *BankOfCanadaDataSource) Parse(c core.Context, content []byte) (*models.
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseByDate returns the common response entity of the exchange rates published on or before the specified date according to the bank of Canada data source raw response
+func (e *BankOfCanadaDataSource) ParseByDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	return e.Parse(c, content)
+}
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -195,3 +196,12 @@ func TestBankOfCanadaDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestBankOfCanadaDataSource_BuildRequestsByDate(t *testing.T) {
+	dataSource := &BankOfCanadaDataSource{}
+
+	requests, err := dataSource.BuildRequestsByDate(time.Date(2021, 4, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "https://www.bankofcanada.ca/valet/observations/group/FX_RATES_DAILY/json?start_date=2021-03-28&end_date=2021-04-04", requests[0].URL.String())
+}
@@ -1,6 +1,7 @@
 package exchangerates
 
 import (
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
@@ -16,12 +17,15 @@ import (
 
 const czechNationalBankDailyExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/daily.txt"
 const czechNationalBankMonthlyOtherExchangeRateUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/fx-rates-of-other-currencies/fx-rates-of-other-currencies/fx_rates.txt"
+const czechNationalBankHistoricalDailyExchangeRateUrlFormat = czechNationalBankDailyExchangeRateUrl + "?date=%s"
+const czechNationalBankHistoricalMonthlyOtherExchangeRateUrlFormat = czechNationalBankMonthlyOtherExchangeRateUrl + "?year=%d&month=%d"
 const czechNationalBankExchangeRateReferenceUrl = "https://www.cnb.cz/en/financial-markets/foreign-exchange-market/central-bank-exchange-rate-fixing/central-bank-exchange-rate-fixing/"
 const czechNationalBankDataSource = "Česká národní banka"
 const czechNationalBankBaseCurrency = "CZK"
 
 const czechNationalBankDataUpdateDateFormat = "02 Jan 2006 15:04"
 const czechNationalBankDataUpdateDateTimezone = "Europe/Prague"
+const czechNationalBankRequestDateFormat = "02.01.2006"
 
 // CzechNationalBankDataSource defines the structure of exchange rates data source of Czech National Bank
 type CzechNationalBankDataSource struct {
@@ -45,6 +49,23 @@ func (e *CzechNationalBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{monthlyReq, dailyReq}, nil
 }
 
+// BuildRequestsByDate returns the Czech National Bank historical exchange rates http requests
+func (e *CzechNationalBankDataSource) BuildRequestsByDate(date time.Time) ([]*http.Request, error) {
+	monthlyReq, err := http.NewRequest("GET", fmt.Sprintf(czechNationalBankHistoricalMonthlyOtherExchangeRateUrlFormat, date.Year(), int(date.Month())), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dailyReq, err := http.NewRequest("GET", fmt.Sprintf(czechNationalBankHistoricalDailyExchangeRateUrlFormat, date.Format(czechNationalBankRequestDateFormat)), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{monthlyReq, dailyReq}, nil
+}
+
 // Parse returns the common response entity according to the czech nation bank data source raw response
 func (e *CzechNationalBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	lines := strings.Split(string(content), "\n")
@@ -181,3 +202,8 @@ func (e *CzechNationalBankDataSource) parseExchangeRate(c core.Context, line str
 		Rate:     utils.Float64ToString(finalRate),
 	}
 }
+
+// ParseByDate returns the common response entity of the exchange rates published on or before the specified date according to the czech nation bank data source raw response
+func (e *CzechNationalBankDataSource) ParseByDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	return e.Parse(c, content)
+}
@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -182,3 +183,13 @@ func TestCzechNationalBankDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestCzechNationalBankDataSource_BuildRequestsByDate(t *testing.T) {
+	dataSource := &CzechNationalBankDataSource{}
+
+	requests, err := dataSource.BuildRequestsByDate(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, czechNationalBankMonthlyOtherExchangeRateUrl+"?year=2021&month=4", requests[0].URL.String())
+	assert.Equal(t, czechNationalBankDailyExchangeRateUrl+"?date=01.04.2021", requests[1].URL.String())
+}
@@ -17,12 +17,15 @@ import (
 )
 
 const euroCentralBankExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+const euroCentralBankRecentHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+const euroCentralBankAllHistoricalExchangeRateUrl = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
 const euroCentralBankExchangeRateReferenceUrl = "https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html"
 const euroCentralBankDataSource = "European Central Bank"
 const euroCentralBankBaseCurrency = "EUR"
 
 const euroCentralBankDataUpdateDateFormat = "2006-01-02 15"
 const euroCentralBankDataUpdateDateTimezone = "Europe/Berlin"
+const euroCentralBankRecentHistoricalDays = 90
 
 // EuroCentralBankDataSource defines the structure of exchange rates data source of euro central bank
 type EuroCentralBankDataSource struct {
@@ -103,6 +106,64 @@ func (e *EuroCentralBankExchangeRateData) ToLatestExchangeRateResponse(c core.Co
 	return latestExchangeRateResp
 }
 
+// ToExchangeRateResponseByDate returns a view-object of the exchange rates published on or before the specified date according to original data from euro central bank
+func (e *EuroCentralBankExchangeRateData) ToExchangeRateResponseByDate(c core.Context, date time.Time) *models.LatestExchangeRateResponse {
+	dateString := date.Format(time.DateOnly)
+	var matchedExchangeRates *EuroCentralBankExchangeRates
+
+	for i := 0; i < len(e.AllExchangeRates); i++ {
+		exchangeRates := e.AllExchangeRates[i]
+
+		if exchangeRates.Date > dateString {
+			continue
+		}
+
+		if matchedExchangeRates == nil || exchangeRates.Date > matchedExchangeRates.Date {
+			matchedExchangeRates = exchangeRates
+		}
+	}
+
+	if matchedExchangeRates == nil {
+		log.Errorf(c, "[euro_central_bank_datasource.ToExchangeRateResponseByDate] no exchange rates published on or before %s", dateString)
+		return nil
+	}
+
+	matchedData := &EuroCentralBankExchangeRateData{
+		AllExchangeRates: []*EuroCentralBankExchangeRates{matchedExchangeRates},
+	}
+
+	return matchedData.ToLatestExchangeRateResponse(c)
+}
+
+// ToExchangeRateResponsesByDateRange returns view-objects of the exchange rates published between the specified dates according to original data from euro central bank
+func (e *EuroCentralBankExchangeRateData) ToExchangeRateResponsesByDateRange(c core.Context, startDate time.Time, endDate time.Time) []*models.LatestExchangeRateResponse {
+	startDateString := startDate.Format(time.DateOnly)
+	endDateString := endDate.Format(time.DateOnly)
+	exchangeRateResponses := make([]*models.LatestExchangeRateResponse, 0)
+
+	for i := len(e.AllExchangeRates) - 1; i >= 0; i-- {
+		exchangeRates := e.AllExchangeRates[i]
+
+		if exchangeRates.Date < startDateString || exchangeRates.Date > endDateString {
+			continue
+		}
+
+		matchedData := &EuroCentralBankExchangeRateData{
+			AllExchangeRates: []*EuroCentralBankExchangeRates{exchangeRates},
+		}
+
+		exchangeRateResponse := matchedData.ToLatestExchangeRateResponse(c)
+
+		if exchangeRateResponse == nil {
+			continue
+		}
+
+		exchangeRateResponses = append(exchangeRateResponses, exchangeRateResponse)
+	}
+
+	return exchangeRateResponses
+}
+
 // ToLatestExchangeRate returns a data pair according to original data from euro central bank
 func (e *EuroCentralBankExchangeRate) ToLatestExchangeRate() *models.LatestExchangeRate {
 	return &models.LatestExchangeRate{
@@ -122,17 +183,40 @@ func (e *EuroCentralBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildRequestsByDate returns the euro central bank historical exchange rates http requests
+func (e *EuroCentralBankDataSource) BuildRequestsByDate(date time.Time) ([]*http.Request, error) {
+	url := euroCentralBankAllHistoricalExchangeRateUrl
+
+	if time.Since(date) < euroCentralBankRecentHistoricalDays*24*time.Hour {
+		url = euroCentralBankRecentHistoricalExchangeRateUrl
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
+// BuildRequestByDateRange returns the euro central bank historical exchange rates http request which contains all the exchange rates between the specified dates
+func (e *EuroCentralBankDataSource) BuildRequestByDateRange(startDate time.Time, endDate time.Time) (*http.Request, error) {
+	requests, err := e.BuildRequestsByDate(startDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return requests[0], nil
+}
+
 // Parse returns the common response entity according to the euro central bank data source raw response
 func (e *EuroCentralBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
-	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
-	xmlDecoder.CharsetReader = charset.NewReaderLabel
-
-	euroCentralBankData := &EuroCentralBankExchangeRateData{}
-	err := xmlDecoder.Decode(euroCentralBankData)
+	euroCentralBankData, err := e.parseExchangeRateData(c, content)
 
 	if err != nil {
-		log.Errorf(c, "[euro_central_bank_datasource.Parse] failed to parse xml data, content is %s, because %s", string(content), err.Error())
-		return nil, errs.ErrFailedToRequestRemoteApi
+		return nil, err
 	}
 
 	latestExchangeRateResponse := euroCentralBankData.ToLatestExchangeRateResponse(c)
@@ -144,3 +228,47 @@ func (e *EuroCentralBankDataSource) Parse(c core.Context, content []byte) (*mode
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseByDate returns the common response entity of the exchange rates published on or before the specified date according to the euro central bank data source raw response
+func (e *EuroCentralBankDataSource) ParseByDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	euroCentralBankData, err := e.parseExchangeRateData(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exchangeRateResponse := euroCentralBankData.ToExchangeRateResponseByDate(c, date)
+
+	if exchangeRateResponse == nil {
+		log.Errorf(c, "[euro_central_bank_datasource.ParseByDate] failed to parse exchange rate data of %s", date.Format(time.DateOnly))
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return exchangeRateResponse, nil
+}
+
+// ParseByDateRange returns the common response entities of every publishing date between the specified dates according to the euro central bank data source raw response
+func (e *EuroCentralBankDataSource) ParseByDateRange(c core.Context, content []byte, startDate time.Time, endDate time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	euroCentralBankData, err := e.parseExchangeRateData(c, content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return euroCentralBankData.ToExchangeRateResponsesByDateRange(c, startDate, endDate), nil
+}
+
+func (e *EuroCentralBankDataSource) parseExchangeRateData(c core.Context, content []byte) (*EuroCentralBankExchangeRateData, error) {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	euroCentralBankData := &EuroCentralBankExchangeRateData{}
+	err := xmlDecoder.Decode(euroCentralBankData)
+
+	if err != nil {
+		log.Errorf(c, "[euro_central_bank_datasource.parseExchangeRateData] failed to parse xml data, content is %s, because %s", string(content), err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return euroCentralBankData, nil
+}
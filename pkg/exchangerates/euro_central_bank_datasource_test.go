@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -138,3 +139,100 @@ func TestEuroCentralBankDataSource_InvalidRate(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+const euroCentralBankHistoricalContent = "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+	"<gesmes:Envelope xmlns:gesmes=\"http://www.gesmes.org/xml/2002-08-01\" xmlns=\"http://www.ecb.int/vocabulary/2002-08-01/eurofxref\">\n" +
+	"  <Cube>\n" +
+	"    <Cube time=\"2021-04-06\">\n" +
+	"      <Cube currency=\"USD\" rate=\"1.1812\" />\n" +
+	"    </Cube>\n" +
+	"    <Cube time=\"2021-04-01\">\n" +
+	"      <Cube currency=\"USD\" rate=\"1.1746\" />\n" +
+	"    </Cube>\n" +
+	"    <Cube time=\"2021-03-31\">\n" +
+	"      <Cube currency=\"USD\" rate=\"1.1725\" />\n" +
+	"    </Cube>\n" +
+	"  </Cube>\n" +
+	"</gesmes:Envelope>"
+
+func TestEuroCentralBankDataSource_ParseByDateExactDate(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := core.NewNullContext()
+
+	actualExchangeRateResponse, err := dataSource.ParseByDate(context, []byte(euroCentralBankHistoricalContent), time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1617199200), actualExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.1725",
+	})
+}
+
+func TestEuroCentralBankDataSource_ParseByDateNonWorkingDay(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := core.NewNullContext()
+
+	actualExchangeRateResponse, err := dataSource.ParseByDate(context, []byte(euroCentralBankHistoricalContent), time.Date(2021, 4, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1617285600), actualExchangeRateResponse.UpdateTime)
+	assert.Contains(t, actualExchangeRateResponse.ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.1746",
+	})
+}
+
+func TestEuroCentralBankDataSource_ParseByDateBeforeAllData(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := core.NewNullContext()
+
+	_, err := dataSource.ParseByDate(context, []byte(euroCentralBankHistoricalContent), time.Date(2021, 3, 30, 0, 0, 0, 0, time.UTC))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEuroCentralBankDataSource_BuildRequestsByDate(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+
+	requests, err := dataSource.BuildRequestsByDate(time.Now().AddDate(0, 0, -10))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, euroCentralBankRecentHistoricalExchangeRateUrl, requests[0].URL.String())
+
+	requests, err = dataSource.BuildRequestsByDate(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, euroCentralBankAllHistoricalExchangeRateUrl, requests[0].URL.String())
+}
+
+func TestEuroCentralBankDataSource_ParseByDateRange(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := core.NewNullContext()
+
+	actualExchangeRateResponses, err := dataSource.ParseByDateRange(context, []byte(euroCentralBankHistoricalContent), time.Date(2021, 3, 30, 0, 0, 0, 0, time.UTC), time.Date(2021, 4, 5, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualExchangeRateResponses, 2)
+	assert.Equal(t, int64(1617199200), actualExchangeRateResponses[0].UpdateTime)
+	assert.Equal(t, int64(1617285600), actualExchangeRateResponses[1].UpdateTime)
+	assert.Contains(t, actualExchangeRateResponses[1].ExchangeRates, &models.LatestExchangeRate{
+		Currency: "USD",
+		Rate:     "1.1746",
+	})
+}
+
+func TestEuroCentralBankDataSource_ParseByDateRangeWithoutData(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+	context := core.NewNullContext()
+
+	actualExchangeRateResponses, err := dataSource.ParseByDateRange(context, []byte(euroCentralBankHistoricalContent), time.Date(2021, 4, 2, 0, 0, 0, 0, time.UTC), time.Date(2021, 4, 5, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Len(t, actualExchangeRateResponses, 0)
+}
+
+func TestEuroCentralBankDataSource_BuildRequestByDateRange(t *testing.T) {
+	dataSource := &EuroCentralBankDataSource{}
+
+	request, err := dataSource.BuildRequestByDateRange(time.Now().AddDate(0, 0, -10), time.Now())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, euroCentralBankRecentHistoricalExchangeRateUrl, request.URL.String())
+
+	request, err = dataSource.BuildRequestByDateRange(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), time.Now())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, euroCentralBankAllHistoricalExchangeRateUrl, request.URL.String())
+}
@@ -0,0 +1,160 @@
+package exchangerates
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// exchangeRatesDataPublishInterval is the expected interval between two publications of most exchange rates data sources
+const exchangeRatesDataPublishInterval = 24 * time.Hour
+
+// exchangeRatesCacheRetryInterval is the interval to request the data source again when the expected new data has not been published yet
+const exchangeRatesCacheRetryInterval = 1 * time.Hour
+
+// exchangeRatesDataPublishSchedule represents when the exchange rates data source is expected to publish new data
+type exchangeRatesDataPublishSchedule struct {
+	interval         time.Duration
+	businessDaysOnly bool
+}
+
+var defaultExchangeRatesDataPublishSchedule = &exchangeRatesDataPublishSchedule{
+	interval: exchangeRatesDataPublishInterval,
+}
+
+// exchangeRatesDataPublishSchedules contains the publish schedules of all exchange rates data sources,
+// most central banks do not publish new data on weekends, so the cached data of the last business day is still fresh on weekends
+var exchangeRatesDataPublishSchedules = map[string]*exchangeRatesDataPublishSchedule{
+	settings.ReserveBankOfAustraliaDataSource:    {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.BankOfCanadaDataSource:              {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.CzechNationalBankDataSource:         {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.DanmarksNationalbankDataSource:      {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.EuroCentralBankDataSource:           {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.NationalBankOfGeorgiaDataSource:     {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.CentralBankOfHungaryDataSource:      {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.BankOfIsraelDataSource:              {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.CentralBankOfMyanmarDataSource:      {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.NorgesBankDataSource:                {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.NationalBankOfPolandDataSource:      {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.NationalBankOfRomaniaDataSource:     {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.BankOfRussiaDataSource:              {interval: exchangeRatesDataPublishInterval, businessDaysOnly: false}, // Bank of Russia also publishes the exchange rates on Saturdays
+	settings.SwissNationalBankDataSource:         {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.NationalBankOfUkraineDataSource:     {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+	settings.CentralBankOfUzbekistanDataSource:   {interval: exchangeRatesDataPublishInterval, businessDaysOnly: false}, // Central Bank of Uzbekistan publishes the exchange rates of every day
+	settings.InternationalMonetaryFundDataSource: {interval: exchangeRatesDataPublishInterval, businessDaysOnly: true},
+}
+
+type exchangeRatesCacheItem struct {
+	response  *models.LatestExchangeRateResponse
+	fetchedAt time.Time
+	expiredAt time.Time
+}
+
+// exchangeRatesCache represents the in-memory cache of the latest exchange rates data shared by all users
+type exchangeRatesCache struct {
+	mutex sync.RWMutex
+	items map[string]*exchangeRatesCacheItem
+}
+
+func newExchangeRatesCache() *exchangeRatesCache {
+	return &exchangeRatesCache{
+		items: make(map[string]*exchangeRatesCacheItem),
+	}
+}
+
+func (c *exchangeRatesCache) getFresh(dataSource string, now time.Time) *models.LatestExchangeRateResponse {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	item, exists := c.items[dataSource]
+
+	if !exists || !now.Before(item.expiredAt) {
+		return nil
+	}
+
+	return cloneLatestExchangeRateResponse(item.response)
+}
+
+func (c *exchangeRatesCache) getStale(dataSource string, now time.Time, maxAge time.Duration) *models.LatestExchangeRateResponse {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	item, exists := c.items[dataSource]
+
+	if !exists || now.Sub(item.fetchedAt) > maxAge {
+		return nil
+	}
+
+	response := cloneLatestExchangeRateResponse(item.response)
+	response.Stale = true
+	return response
+}
+
+func (c *exchangeRatesCache) set(dataSource string, response *models.LatestExchangeRateResponse, now time.Time) {
+	if response == nil {
+		return
+	}
+
+	// the cached data would expire when the data source is expected to publish new data,
+	// and if that time has already passed, the data source would be requested again after a while
+	expiredAt := getNextExchangeRatesDataPublishTime(dataSource, time.Unix(response.UpdateTime, 0))
+
+	if !expiredAt.After(now) {
+		expiredAt = now.Add(exchangeRatesCacheRetryInterval)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.items[dataSource] = &exchangeRatesCacheItem{
+		response:  cloneLatestExchangeRateResponse(response),
+		fetchedAt: now,
+		expiredAt: expiredAt,
+	}
+}
+
+func (c *exchangeRatesCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.items = make(map[string]*exchangeRatesCacheItem)
+}
+
+func getNextExchangeRatesDataPublishTime(dataSource string, updateTime time.Time) time.Time {
+	schedule, exists := exchangeRatesDataPublishSchedules[dataSource]
+
+	if !exists {
+		schedule = defaultExchangeRatesDataPublishSchedule
+	}
+
+	nextPublishTime := updateTime.Add(schedule.interval)
+
+	if schedule.businessDaysOnly {
+		for nextPublishTime.UTC().Weekday() == time.Saturday || nextPublishTime.UTC().Weekday() == time.Sunday {
+			nextPublishTime = nextPublishTime.Add(24 * time.Hour)
+		}
+	}
+
+	return nextPublishTime
+}
+
+func cloneLatestExchangeRateResponse(response *models.LatestExchangeRateResponse) *models.LatestExchangeRateResponse {
+	clonedResponse := *response
+
+	if response.ExchangeRates != nil {
+		clonedResponse.ExchangeRates = make(models.LatestExchangeRateSlice, len(response.ExchangeRates))
+
+		for i := 0; i < len(response.ExchangeRates); i++ {
+			if response.ExchangeRates[i] == nil {
+				continue
+			}
+
+			exchangeRate := *response.ExchangeRates[i]
+			clonedResponse.ExchangeRates[i] = &exchangeRate
+		}
+	}
+
+	return &clonedResponse
+}
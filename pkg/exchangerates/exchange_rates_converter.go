@@ -0,0 +1,232 @@
+package exchangerates
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const historicalExchangeRatesMaxStaleDays = 7
+
+// ExchangeRatesConverter converts amounts between currencies, the user dated custom exchange rates effective on the specified date are used first,
+// then the historical exchange rates published on or before the specified date, and the latest exchange rates are used when no dated exchange rate is available
+type ExchangeRatesConverter struct {
+	latestExchangeRates     *models.LatestExchangeRateResponse
+	datedExchangeRates      map[string][]*models.UserDatedCustomExchangeRate
+	historicalExchangeRates []*models.HistoricalExchangeRateResponse
+}
+
+// NewExchangeRatesConverter returns a new exchange rates converter which contains the latest exchange rates, all dated custom exchange rates of the user
+// and the specified historical exchange rates of the current data source
+func (e *ExchangeRatesDataSourceContainer) NewExchangeRatesConverter(c core.Context, uid int64, currentConfig *settings.Config, exchangeRateHistories []*models.ExchangeRateHistory) (*ExchangeRatesConverter, error) {
+	latestExchangeRates, err := e.GetLatestExchangeRates(c, uid, currentConfig)
+
+	if err != nil {
+		log.Warnf(c, "[exchange_rates_converter.NewExchangeRatesConverter] failed to get latest exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+	}
+
+	datedExchangeRates, err := services.UserCustomExchangeRates.GetAllDatedCustomExchangeRatesByUid(c, uid, "")
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates_converter.NewExchangeRatesConverter] failed to get dated custom exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	return NewExchangeRatesConverterWithData(latestExchangeRates, datedExchangeRates, exchangeRateHistories), nil
+}
+
+// NewExchangeRatesConverterWithData returns a new exchange rates converter according to the specified latest exchange rates, dated custom exchange rates and historical exchange rates
+func NewExchangeRatesConverterWithData(latestExchangeRates *models.LatestExchangeRateResponse, datedExchangeRates []*models.UserDatedCustomExchangeRate, exchangeRateHistories []*models.ExchangeRateHistory) *ExchangeRatesConverter {
+	converter := &ExchangeRatesConverter{
+		latestExchangeRates:     latestExchangeRates,
+		datedExchangeRates:      make(map[string][]*models.UserDatedCustomExchangeRate),
+		historicalExchangeRates: models.ToHistoricalExchangeRateResponses(exchangeRateHistories),
+	}
+
+	for i := 0; i < len(datedExchangeRates); i++ {
+		datedExchangeRate := datedExchangeRates[i]
+
+		if datedExchangeRate.Rate <= 0 {
+			continue
+		}
+
+		converter.datedExchangeRates[datedExchangeRate.Currency] = append(converter.datedExchangeRates[datedExchangeRate.Currency], datedExchangeRate)
+	}
+
+	for _, currencyExchangeRates := range converter.datedExchangeRates {
+		sort.SliceStable(currencyExchangeRates, func(i, j int) bool {
+			return currencyExchangeRates[i].EffectiveDate < currencyExchangeRates[j].EffectiveDate
+		})
+	}
+
+	return converter
+}
+
+// GetExchangedAmount returns the amount exchanged from the source currency to the target currency with the exchange rate effective on the specified date (yyyy-MM-dd),
+// and whether the exchange rate exists
+func (r *ExchangeRatesConverter) GetExchangedAmount(amount int64, fromCurrency string, toCurrency string, date string) (int64, bool) {
+	if fromCurrency == toCurrency || amount == 0 {
+		return amount, true
+	}
+
+	rate, exists := r.getExchangeRate(fromCurrency, toCurrency, date)
+
+	if !exists {
+		return 0, false
+	}
+
+	return int64(math.Round(float64(amount) / rate)), true
+}
+
+// GetExchangeRate returns the amount of the source currency equivalent to one unit of the target currency multiplied by UserCustomExchangeRateFactorInDatabase
+// with the exchange rate effective on the specified date (yyyy-MM-dd), and whether the exchange rate exists
+func (r *ExchangeRatesConverter) GetExchangeRate(fromCurrency string, toCurrency string, date string) (int64, bool) {
+	rate, exists := r.getExchangeRate(fromCurrency, toCurrency, date)
+
+	if !exists {
+		return 0, false
+	}
+
+	return int64(math.Round(rate * float64(models.UserCustomExchangeRateFactorInDatabase))), true
+}
+
+// GetTransactionExchangeRate returns the exchange rate from the account currency to the target currency effective on the transaction date,
+// which would be stored in the transaction to lock its exchanged amount
+func (r *ExchangeRatesConverter) GetTransactionExchangeRate(transaction *models.Transaction, accountCurrency string, toCurrency string) (int64, bool) {
+	return r.GetExchangeRate(accountCurrency, toCurrency, getTransactionDate(transaction))
+}
+
+// GetTransactionExchangedAmount returns the amount of the transaction exchanged to the target currency with the exchange rate effective on the transaction date,
+// the cross-currency transfer uses the exchange rate locked by the amounts of both accounts when the target currency is the currency of the related account,
+// and the other transaction uses the exchange rate stored in the transaction when the target currency is the currency of the stored exchange rate
+func (r *ExchangeRatesConverter) GetTransactionExchangedAmount(transaction *models.Transaction, accountCurrency string, relatedAccountCurrency string, toCurrency string) (int64, bool) {
+	if accountCurrency == toCurrency {
+		return transaction.Amount, true
+	}
+
+	if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) && relatedAccountCurrency == toCurrency {
+		return transaction.RelatedAccountAmount, true
+	}
+
+	if transaction.ExchangeRateCurrency == toCurrency && transaction.ExchangeRate > 0 {
+		return int64(math.Round(float64(transaction.Amount) * float64(models.UserCustomExchangeRateFactorInDatabase) / float64(transaction.ExchangeRate))), true
+	}
+
+	return r.GetExchangedAmount(transaction.Amount, accountCurrency, toCurrency, getTransactionDate(transaction))
+}
+
+// getExchangeRate returns the amount of the currency equivalent to one unit of the base currency
+func (r *ExchangeRatesConverter) getExchangeRate(currency string, baseCurrency string, date string) (float64, bool) {
+	if currency == baseCurrency {
+		return 1, true
+	}
+
+	if datedExchangeRate := r.getDatedExchangeRate(currency, baseCurrency, date); datedExchangeRate != nil {
+		return datedExchangeRate.GetRate(), true
+	}
+
+	if datedExchangeRate := r.getDatedExchangeRate(baseCurrency, currency, date); datedExchangeRate != nil {
+		return 1 / datedExchangeRate.GetRate(), true
+	}
+
+	if historicalExchangeRate := r.getHistoricalExchangeRates(date); historicalExchangeRate != nil {
+		if rate, exists := getCrossExchangeRate(historicalExchangeRate.BaseCurrency, historicalExchangeRate.ExchangeRates, currency, baseCurrency); exists {
+			return rate, true
+		}
+	}
+
+	if r.latestExchangeRates == nil {
+		return 0, false
+	}
+
+	return getCrossExchangeRate(r.latestExchangeRates.BaseCurrency, r.latestExchangeRates.ExchangeRates, currency, baseCurrency)
+}
+
+func (r *ExchangeRatesConverter) getDatedExchangeRate(currency string, baseCurrency string, date string) *models.UserDatedCustomExchangeRate {
+	currencyExchangeRates, exists := r.datedExchangeRates[currency]
+
+	if !exists || date == "" {
+		return nil
+	}
+
+	for i := len(currencyExchangeRates) - 1; i >= 0; i-- {
+		datedExchangeRate := currencyExchangeRates[i]
+
+		if datedExchangeRate.EffectiveDate <= date && datedExchangeRate.BaseCurrency == baseCurrency {
+			return datedExchangeRate
+		}
+	}
+
+	return nil
+}
+
+// getHistoricalExchangeRates returns the historical exchange rates published on or before the specified date (yyyy-MM-dd), the stale data is never returned
+func (r *ExchangeRatesConverter) getHistoricalExchangeRates(date string) *models.HistoricalExchangeRateResponse {
+	if len(r.historicalExchangeRates) < 1 || date == "" {
+		return nil
+	}
+
+	index := sort.Search(len(r.historicalExchangeRates), func(i int) bool {
+		return r.historicalExchangeRates[i].Date > date
+	})
+
+	if index < 1 {
+		return nil
+	}
+
+	historicalExchangeRate := r.historicalExchangeRates[index-1]
+	dateTime, err := time.Parse(time.DateOnly, date)
+
+	if err != nil || historicalExchangeRate.Date < dateTime.AddDate(0, 0, -historicalExchangeRatesMaxStaleDays).Format(time.DateOnly) {
+		return nil
+	}
+
+	return historicalExchangeRate
+}
+
+// getCrossExchangeRate returns the amount of the currency equivalent to one unit of the base currency according to the exchange rates based on the specified currency
+func getCrossExchangeRate(exchangeRatesBaseCurrency string, exchangeRates models.LatestExchangeRateSlice, currency string, baseCurrency string) (float64, bool) {
+	currencyRate, currencyRateExists := getExchangeRateFromExchangeRates(exchangeRatesBaseCurrency, exchangeRates, currency)
+	baseCurrencyRate, baseCurrencyRateExists := getExchangeRateFromExchangeRates(exchangeRatesBaseCurrency, exchangeRates, baseCurrency)
+
+	if !currencyRateExists || !baseCurrencyRateExists {
+		return 0, false
+	}
+
+	return currencyRate / baseCurrencyRate, true
+}
+
+func getExchangeRateFromExchangeRates(exchangeRatesBaseCurrency string, exchangeRates models.LatestExchangeRateSlice, currency string) (float64, bool) {
+	if currency == exchangeRatesBaseCurrency {
+		return 1, true
+	}
+
+	for i := 0; i < len(exchangeRates); i++ {
+		if exchangeRates[i].Currency != currency {
+			continue
+		}
+
+		rate, err := utils.StringToFloat64(exchangeRates[i].Rate)
+
+		if err != nil || rate <= 0 {
+			return 0, false
+		}
+
+		return rate, true
+	}
+
+	return 0, false
+}
+
+// getTransactionDate returns the date (yyyy-MM-dd) of the transaction in the timezone of the transaction
+func getTransactionDate(transaction *models.Transaction) string {
+	timezone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+	return utils.FormatUnixTimeToLongDate(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), timezone)
+}
@@ -0,0 +1,210 @@
+package exchangerates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func newTestExchangeRatesConverter() *ExchangeRatesConverter {
+	latestExchangeRates := &models.LatestExchangeRateResponse{
+		BaseCurrency: "USD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "ARS", Rate: "1000"},
+			{Currency: "EUR", Rate: "0.5"},
+		},
+	}
+
+	datedExchangeRates := []*models.UserDatedCustomExchangeRate{
+		{Currency: "ARS", EffectiveDate: "2024-03-01", BaseCurrency: "USD", Rate: 1200 * models.UserCustomExchangeRateFactorInDatabase},
+		{Currency: "ARS", EffectiveDate: "2024-01-01", BaseCurrency: "USD", Rate: 800 * models.UserCustomExchangeRateFactorInDatabase},
+	}
+
+	return NewExchangeRatesConverterWithData(latestExchangeRates, datedExchangeRates, nil)
+}
+
+func newTestExchangeRatesConverterWithHistories(datedExchangeRates []*models.UserDatedCustomExchangeRate) *ExchangeRatesConverter {
+	latestExchangeRates := &models.LatestExchangeRateResponse{
+		BaseCurrency: "USD",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "ARS", Rate: "1000"},
+			{Currency: "CNY", Rate: "7"},
+			{Currency: "GBP", Rate: "0.8"},
+		},
+	}
+
+	exchangeRateHistories := []*models.ExchangeRateHistory{
+		{DataSource: "euro_central_bank", RateDate: "2024-02-05", Currency: "USD", BaseCurrency: "EUR", Rate: "2"},
+		{DataSource: "euro_central_bank", RateDate: "2024-02-05", Currency: "GBP", BaseCurrency: "EUR", Rate: "1"},
+		{DataSource: "euro_central_bank", RateDate: "2024-02-05", Currency: "ARS", BaseCurrency: "EUR", Rate: "2000"},
+		{DataSource: "euro_central_bank", RateDate: "2024-02-01", Currency: "USD", BaseCurrency: "EUR", Rate: "2"},
+		{DataSource: "euro_central_bank", RateDate: "2024-02-01", Currency: "GBP", BaseCurrency: "EUR", Rate: "0.5"},
+	}
+
+	return NewExchangeRatesConverterWithData(latestExchangeRates, datedExchangeRates, exchangeRateHistories)
+}
+
+func TestExchangeRatesConverterGetExchangedAmount_UseDatedExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverter()
+
+	amount, exists := converter.GetExchangedAmount(80000, "ARS", "USD", "2024-02-15")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	amount, exists = converter.GetExchangedAmount(120000, "ARS", "USD", "2024-03-01")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	amount, exists = converter.GetExchangedAmount(100, "USD", "ARS", "2024-02-15")
+	assert.True(t, exists)
+	assert.Equal(t, int64(80000), amount)
+}
+
+func TestExchangeRatesConverterGetExchangedAmount_FallbackToLatestExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverter()
+
+	amount, exists := converter.GetExchangedAmount(100000, "ARS", "USD", "2023-12-31")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	amount, exists = converter.GetExchangedAmount(100000, "ARS", "EUR", "2024-02-15")
+	assert.True(t, exists)
+	assert.Equal(t, int64(50), amount)
+
+	_, exists = converter.GetExchangedAmount(100, "JPY", "USD", "2024-02-15")
+	assert.False(t, exists)
+}
+
+func TestExchangeRatesConverterGetTransactionExchangedAmount_LockedTransferExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverter()
+	transactionTime := utils.GetMinTransactionTimeFromUnixTime(1708000000) // 2024-02-15
+
+	transaction := &models.Transaction{
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TransactionTime:      transactionTime,
+		Amount:               100,
+		RelatedAccountAmount: 105000,
+	}
+
+	amount, exists := converter.GetTransactionExchangedAmount(transaction, "USD", "ARS", "ARS")
+	assert.True(t, exists)
+	assert.Equal(t, int64(105000), amount)
+
+	amount, exists = converter.GetTransactionExchangedAmount(transaction, "USD", "ARS", "USD")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	transaction.Type = models.TRANSACTION_DB_TYPE_EXPENSE
+	transaction.Amount = 80000
+
+	amount, exists = converter.GetTransactionExchangedAmount(transaction, "ARS", "", "USD")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+}
+
+func TestExchangeRatesConverterGetTransactionExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverter()
+
+	transaction := &models.Transaction{
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		TransactionTime: utils.GetMinTransactionTimeFromUnixTime(1708000000), // 2024-02-15
+		Amount:          80000,
+	}
+
+	exchangeRate, exists := converter.GetTransactionExchangeRate(transaction, "ARS", "USD")
+	assert.True(t, exists)
+	assert.Equal(t, 800*models.UserCustomExchangeRateFactorInDatabase, exchangeRate)
+
+	exchangeRate, exists = converter.GetTransactionExchangeRate(transaction, "USD", "EUR")
+	assert.True(t, exists)
+	assert.Equal(t, 2*models.UserCustomExchangeRateFactorInDatabase, exchangeRate)
+
+	_, exists = converter.GetTransactionExchangeRate(transaction, "JPY", "USD")
+	assert.False(t, exists)
+}
+
+func TestExchangeRatesConverterGetTransactionExchangedAmount_StoredExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverter()
+
+	transaction := &models.Transaction{
+		Type:                 models.TRANSACTION_DB_TYPE_EXPENSE,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(1708000000), // 2024-02-15
+		Amount:               150000,
+		ExchangeRateCurrency: "USD",
+		ExchangeRate:         1500 * models.UserCustomExchangeRateFactorInDatabase,
+	}
+
+	// the stored exchange rate is used instead of the dated exchange rate
+	amount, exists := converter.GetTransactionExchangedAmount(transaction, "ARS", "", "USD")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	// the stored exchange rate is ignored when the target currency is not the currency of the stored exchange rate
+	amount, exists = converter.GetTransactionExchangedAmount(transaction, "ARS", "", "EUR")
+	assert.True(t, exists)
+	assert.Equal(t, int64(75), amount)
+}
+
+func TestExchangeRatesConverterGetExchangedAmount_UseHistoricalExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverterWithHistories(nil)
+
+	amount, exists := converter.GetExchangedAmount(100, "GBP", "USD", "2024-02-01")
+	assert.True(t, exists)
+	assert.Equal(t, int64(400), amount)
+
+	amount, exists = converter.GetExchangedAmount(100, "GBP", "USD", "2024-02-03")
+	assert.True(t, exists)
+	assert.Equal(t, int64(400), amount)
+
+	amount, exists = converter.GetExchangedAmount(100, "GBP", "USD", "2024-02-12")
+	assert.True(t, exists)
+	assert.Equal(t, int64(200), amount)
+
+	amount, exists = converter.GetExchangedAmount(100, "EUR", "USD", "2024-02-06")
+	assert.True(t, exists)
+	assert.Equal(t, int64(200), amount)
+
+	amount, exists = converter.GetExchangedAmount(200000, "ARS", "USD", "2024-02-06")
+	assert.True(t, exists)
+	assert.Equal(t, int64(200), amount)
+}
+
+func TestExchangeRatesConverterGetExchangedAmount_HistoricalExchangeRateFallbackToLatestExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverterWithHistories(nil)
+
+	amount, exists := converter.GetExchangedAmount(100, "GBP", "USD", "2024-01-31")
+	assert.True(t, exists)
+	assert.Equal(t, int64(125), amount)
+
+	amount, exists = converter.GetExchangedAmount(100, "GBP", "USD", "2024-02-13")
+	assert.True(t, exists)
+	assert.Equal(t, int64(125), amount)
+
+	amount, exists = converter.GetExchangedAmount(100, "GBP", "USD", "")
+	assert.True(t, exists)
+	assert.Equal(t, int64(125), amount)
+
+	amount, exists = converter.GetExchangedAmount(700, "CNY", "USD", "2024-02-03")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	_, exists = converter.GetExchangedAmount(100, "EUR", "USD", "2024-01-31")
+	assert.False(t, exists)
+}
+
+func TestExchangeRatesConverterGetExchangedAmount_DatedExchangeRatePriorToHistoricalExchangeRate(t *testing.T) {
+	converter := newTestExchangeRatesConverterWithHistories([]*models.UserDatedCustomExchangeRate{
+		{Currency: "ARS", EffectiveDate: "2024-01-01", BaseCurrency: "USD", Rate: 800 * models.UserCustomExchangeRateFactorInDatabase},
+	})
+
+	amount, exists := converter.GetExchangedAmount(80000, "ARS", "USD", "2024-02-06")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	amount, exists = converter.GetExchangedAmount(100, "GBP", "USD", "2024-02-06")
+	assert.True(t, exists)
+	assert.Equal(t, int64(200), amount)
+}
@@ -1,6 +1,8 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
@@ -11,3 +13,17 @@ type ExchangeRatesDataSource interface {
 	// GetLatestExchangeRates returns the common response entities
 	GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error)
 }
+
+// HistoricalExchangeRatesDataSource defines the structure of exchange rates data source which publishes historical exchange rates
+type HistoricalExchangeRatesDataSource interface {
+	ExchangeRatesDataSource
+
+	// IsHistoricalExchangeRatesSupported returns whether the data source supports requesting historical exchange rates
+	IsHistoricalExchangeRatesSupported() bool
+
+	// GetExchangeRatesByDate returns the common response entities of the exchange rates published on or before the specified date
+	GetExchangeRatesByDate(c core.Context, uid int64, currentConfig *settings.Config, date time.Time) (*models.LatestExchangeRateResponse, error)
+
+	// GetExchangeRatesByDateRange returns the common response entities of the exchange rates of every publishing date between the specified dates
+	GetExchangeRatesByDateRange(c core.Context, uid int64, currentConfig *settings.Config, startDate time.Time, endDate time.Time) ([]*models.LatestExchangeRateResponse, error)
+}
@@ -0,0 +1,82 @@
+package exchangerates
+
+import (
+	"sync"
+	"time"
+)
+
+// exchangeRatesDataSourceMinBackoffInterval is the interval to skip requesting the data source after it fails for the first time
+const exchangeRatesDataSourceMinBackoffInterval = 1 * time.Minute
+
+// exchangeRatesDataSourceMaxBackoffInterval is the maximum interval to skip requesting the data source after it fails continuously
+const exchangeRatesDataSourceMaxBackoffInterval = 1 * time.Hour
+
+type exchangeRatesDataSourceFailure struct {
+	failureCount int
+	lastError    error
+	retryAfter   time.Time
+}
+
+// exchangeRatesDataSourceBackoff represents the failure states of exchange rates data sources,
+// the data source which fails continuously would not be requested until the backoff interval elapses
+type exchangeRatesDataSourceBackoff struct {
+	mutex    sync.RWMutex
+	failures map[string]*exchangeRatesDataSourceFailure
+}
+
+func newExchangeRatesDataSourceBackoff() *exchangeRatesDataSourceBackoff {
+	return &exchangeRatesDataSourceBackoff{
+		failures: make(map[string]*exchangeRatesDataSourceFailure),
+	}
+}
+
+func (b *exchangeRatesDataSourceBackoff) getBackoffError(dataSource string, now time.Time) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	failure, exists := b.failures[dataSource]
+
+	if !exists || !now.Before(failure.retryAfter) {
+		return nil
+	}
+
+	return failure.lastError
+}
+
+func (b *exchangeRatesDataSourceBackoff) recordFailure(dataSource string, err error, now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	failure, exists := b.failures[dataSource]
+
+	if !exists {
+		failure = &exchangeRatesDataSourceFailure{}
+		b.failures[dataSource] = failure
+	}
+
+	failure.failureCount++
+	failure.lastError = err
+
+	// the backoff interval doubles after every continuous failure
+	backoffInterval := exchangeRatesDataSourceMinBackoffInterval
+
+	for i := 1; i < failure.failureCount && backoffInterval < exchangeRatesDataSourceMaxBackoffInterval; i++ {
+		backoffInterval *= 2
+	}
+
+	failure.retryAfter = now.Add(min(backoffInterval, exchangeRatesDataSourceMaxBackoffInterval))
+}
+
+func (b *exchangeRatesDataSourceBackoff) recordSuccess(dataSource string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.failures, dataSource)
+}
+
+func (b *exchangeRatesDataSourceBackoff) clear() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.failures = make(map[string]*exchangeRatesDataSourceFailure)
+}
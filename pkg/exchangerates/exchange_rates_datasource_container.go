@@ -1,86 +1,230 @@
 package exchangerates
 
 import (
+	"time"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // ExchangeRatesDataSourceContainer contains the current exchange rates data source
 type ExchangeRatesDataSourceContainer struct {
-	current ExchangeRatesDataSource
+	current     ExchangeRatesDataSource
+	currentName string
+	fallbacks   []*namedExchangeRatesDataSource
+	cache       *exchangeRatesCache
+	backoff     *exchangeRatesDataSourceBackoff
+}
+
+type namedExchangeRatesDataSource struct {
+	name       string
+	dataSource ExchangeRatesDataSource
 }
 
 // Initialize a exchange rates data source container singleton instance
 var (
-	Container = &ExchangeRatesDataSourceContainer{}
+	Container = &ExchangeRatesDataSourceContainer{
+		cache:   newExchangeRatesCache(),
+		backoff: newExchangeRatesDataSourceBackoff(),
+	}
 )
 
 // InitializeExchangeRatesDataSource initializes the current exchange rates data source according to the config
 func InitializeExchangeRatesDataSource(config *settings.Config) error {
-	if config.ExchangeRatesDataSource == settings.ReserveBankOfAustraliaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&ReserveBankOfAustraliaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfCanadaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&BankOfCanadaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CzechNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&CzechNationalBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.DanmarksNationalbankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&DanmarksNationalbankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.EuroCentralBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&EuroCentralBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfGeorgiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&NationalBankOfGeorgiaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfHungaryDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&CentralBankOfHungaryDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfIsraelDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&BankOfIsraelDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfMyanmarDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&CentralBankOfMyanmarDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NorgesBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&NorgesBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfPolandDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&NationalBankOfPolandDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfRomaniaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&NationalBankOfRomaniaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfRussiaDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&BankOfRussiaDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.SwissNationalBankDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&SwissNationalBankDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfUkraineDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&NationalBankOfUkraineDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfUzbekistanDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&CentralBankOfUzbekistanDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.InternationalMonetaryFundDataSource {
-		Container.current = newCommonHttpExchangeRatesDataSource(&InternationalMonetaryFundDataSource{})
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
-		Container.current = newUserCustomExchangeRatesDataSource()
-		return nil
+	dataSource := newExchangeRatesDataSource(config.ExchangeRatesDataSource)
+
+	if dataSource == nil {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	fallbacks := make([]*namedExchangeRatesDataSource, 0, len(config.ExchangeRatesFallbackDataSources))
+
+	for i := 0; i < len(config.ExchangeRatesFallbackDataSources); i++ {
+		fallbackName := config.ExchangeRatesFallbackDataSources[i]
+		fallbackDataSource := newExchangeRatesDataSource(fallbackName)
+
+		if fallbackDataSource == nil || fallbackName == settings.UserCustomExchangeRatesDataSource {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		fallbacks = append(fallbacks, &namedExchangeRatesDataSource{
+			name:       fallbackName,
+			dataSource: fallbackDataSource,
+		})
+	}
+
+	Container.current = dataSource
+	Container.currentName = config.ExchangeRatesDataSource
+	Container.fallbacks = fallbacks
+	Container.cache.clear()
+	Container.backoff.clear()
+
+	return nil
+}
+
+func newExchangeRatesDataSource(dataSource string) ExchangeRatesDataSource {
+	if dataSource == settings.ReserveBankOfAustraliaDataSource {
+		return newCommonHttpExchangeRatesDataSource(&ReserveBankOfAustraliaDataSource{})
+	} else if dataSource == settings.BankOfCanadaDataSource {
+		return newCommonHttpExchangeRatesDataSource(&BankOfCanadaDataSource{})
+	} else if dataSource == settings.CzechNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataSource(&CzechNationalBankDataSource{})
+	} else if dataSource == settings.DanmarksNationalbankDataSource {
+		return newCommonHttpExchangeRatesDataSource(&DanmarksNationalbankDataSource{})
+	} else if dataSource == settings.EuroCentralBankDataSource {
+		return newCommonHttpExchangeRatesDataSource(&EuroCentralBankDataSource{})
+	} else if dataSource == settings.NationalBankOfGeorgiaDataSource {
+		return newCommonHttpExchangeRatesDataSource(&NationalBankOfGeorgiaDataSource{})
+	} else if dataSource == settings.CentralBankOfHungaryDataSource {
+		return newCommonHttpExchangeRatesDataSource(&CentralBankOfHungaryDataSource{})
+	} else if dataSource == settings.BankOfIsraelDataSource {
+		return newCommonHttpExchangeRatesDataSource(&BankOfIsraelDataSource{})
+	} else if dataSource == settings.CentralBankOfMyanmarDataSource {
+		return newCommonHttpExchangeRatesDataSource(&CentralBankOfMyanmarDataSource{})
+	} else if dataSource == settings.NorgesBankDataSource {
+		return newCommonHttpExchangeRatesDataSource(&NorgesBankDataSource{})
+	} else if dataSource == settings.NationalBankOfPolandDataSource {
+		return newCommonHttpExchangeRatesDataSource(&NationalBankOfPolandDataSource{})
+	} else if dataSource == settings.NationalBankOfRomaniaDataSource {
+		return newCommonHttpExchangeRatesDataSource(&NationalBankOfRomaniaDataSource{})
+	} else if dataSource == settings.BankOfRussiaDataSource {
+		return newCommonHttpExchangeRatesDataSource(&BankOfRussiaDataSource{})
+	} else if dataSource == settings.SwissNationalBankDataSource {
+		return newCommonHttpExchangeRatesDataSource(&SwissNationalBankDataSource{})
+	} else if dataSource == settings.NationalBankOfUkraineDataSource {
+		return newCommonHttpExchangeRatesDataSource(&NationalBankOfUkraineDataSource{})
+	} else if dataSource == settings.CentralBankOfUzbekistanDataSource {
+		return newCommonHttpExchangeRatesDataSource(&CentralBankOfUzbekistanDataSource{})
+	} else if dataSource == settings.InternationalMonetaryFundDataSource {
+		return newCommonHttpExchangeRatesDataSource(&InternationalMonetaryFundDataSource{})
+	} else if dataSource == settings.UserCustomExchangeRatesDataSource {
+		return newUserCustomExchangeRatesDataSource()
 	}
 
-	return errs.ErrInvalidExchangeRatesDataSource
+	return nil
 }
 
-// GetLatestExchangeRates returns the latest exchange rates data from the current exchange rates data source
+// GetLatestExchangeRates returns the latest exchange rates data from the current exchange rates data source,
+// it would request the fallback data sources in order if the current one is unavailable, and return the stale cached data if all of them are unavailable,
+// the data source which fails continuously would be skipped until its backoff interval elapses
 func (e *ExchangeRatesDataSourceContainer) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
-	if Container.current == nil {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if e.currentName == settings.UserCustomExchangeRatesDataSource {
+		return e.current.GetLatestExchangeRates(c, uid, currentConfig)
+	}
+
+	dataSources := make([]*namedExchangeRatesDataSource, 0, len(e.fallbacks)+1)
+	dataSources = append(dataSources, &namedExchangeRatesDataSource{
+		name:       e.currentName,
+		dataSource: e.current,
+	})
+	dataSources = append(dataSources, e.fallbacks...)
+
+	now := time.Now()
+	var lastErr error
+
+	for i := 0; i < len(dataSources); i++ {
+		dataSource := dataSources[i]
+
+		if currentConfig.EnableExchangeRatesCache {
+			if cachedResponse := e.cache.getFresh(dataSource.name, now); cachedResponse != nil {
+				return cachedResponse, nil
+			}
+		}
+
+		if backoffErr := e.backoff.getBackoffError(dataSource.name, now); backoffErr != nil {
+			log.Debugf(c, "[exchange_rates_datasource_container.GetLatestExchangeRates] skip requesting latest exchange rates from \"%s\", because it failed recently", dataSource.name)
+			lastErr = backoffErr
+			continue
+		}
+
+		latestExchangeRateResponse, err := dataSource.dataSource.GetLatestExchangeRates(c, uid, currentConfig)
+
+		if err != nil {
+			log.Warnf(c, "[exchange_rates_datasource_container.GetLatestExchangeRates] failed to get latest exchange rates from \"%s\", because %s", dataSource.name, err.Error())
+			e.backoff.recordFailure(dataSource.name, err, now)
+			lastErr = err
+			continue
+		}
+
+		e.backoff.recordSuccess(dataSource.name)
+
+		if currentConfig.EnableExchangeRatesCache {
+			e.cache.set(dataSource.name, latestExchangeRateResponse, now)
+		}
+
+		return latestExchangeRateResponse, nil
+	}
+
+	if currentConfig.EnableExchangeRatesCache && currentConfig.ExchangeRatesStaleDataMaxAge > 0 {
+		maxAge := time.Duration(currentConfig.ExchangeRatesStaleDataMaxAge) * time.Second
+
+		for i := 0; i < len(dataSources); i++ {
+			if staleResponse := e.cache.getStale(dataSources[i].name, now, maxAge); staleResponse != nil {
+				log.Warnf(c, "[exchange_rates_datasource_container.GetLatestExchangeRates] all data sources are unavailable, return stale exchange rates from \"%s\"", dataSources[i].name)
+				return staleResponse, nil
+			}
+		}
+	}
+
+	return nil, lastErr
+}
+
+// IsHistoricalExchangeRatesSupported returns whether the current exchange rates data source supports requesting historical exchange rates
+func (e *ExchangeRatesDataSourceContainer) IsHistoricalExchangeRatesSupported() bool {
+	if e.current == nil {
+		return false
+	}
+
+	historicalDataSource, ok := e.current.(HistoricalExchangeRatesDataSource)
+
+	return ok && historicalDataSource.IsHistoricalExchangeRatesSupported()
+}
+
+// GetExchangeRatesByDate returns the exchange rates data published on or before the specified date from the current exchange rates data source
+func (e *ExchangeRatesDataSourceContainer) GetExchangeRatesByDate(c core.Context, uid int64, currentConfig *settings.Config, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	historicalDataSource, ok := e.current.(HistoricalExchangeRatesDataSource)
+
+	if !ok || !historicalDataSource.IsHistoricalExchangeRatesSupported() {
+		return nil, errs.ErrExchangeRatesHistoryNotSupported
+	}
+
+	return historicalDataSource.GetExchangeRatesByDate(c, uid, currentConfig, date)
+}
+
+// GetExchangeRatesByDateRange returns the exchange rates data of every publishing date between the specified dates from the current exchange rates data source
+func (e *ExchangeRatesDataSourceContainer) GetExchangeRatesByDateRange(c core.Context, uid int64, currentConfig *settings.Config, startDate time.Time, endDate time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	if e.current == nil {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	historicalDataSource, ok := e.current.(HistoricalExchangeRatesDataSource)
+
+	if !ok || !historicalDataSource.IsHistoricalExchangeRatesSupported() {
+		return nil, errs.ErrExchangeRatesHistoryNotSupported
+	}
+
+	return historicalDataSource.GetExchangeRatesByDateRange(c, uid, currentConfig, startDate, endDate)
+}
+
+// GetLatestExchangeRatesFromCurrentDataSource returns the latest exchange rates data from the current exchange rates data source only,
+// the fallback data sources and the cached data are never used, so the returned data can be saved as historical exchange rates
+func (e *ExchangeRatesDataSourceContainer) GetLatestExchangeRatesFromCurrentDataSource(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	if currentConfig.ExchangeRatesDataSource == settings.UserCustomExchangeRatesDataSource {
+		return nil, errs.ErrExchangeRatesHistoryNotSupported
+	}
+
+	if e.current == nil {
 		return nil, errs.ErrInvalidExchangeRatesDataSource
 	}
 
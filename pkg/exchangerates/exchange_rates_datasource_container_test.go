@@ -0,0 +1,220 @@
+package exchangerates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mockExchangeRatesDataSource struct {
+	response     *models.LatestExchangeRateResponse
+	err          error
+	requestCount int
+}
+
+func (m *mockExchangeRatesDataSource) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	m.requestCount++
+
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return m.response, nil
+}
+
+func newMockExchangeRatesDataSourceContainer(primary *mockExchangeRatesDataSource, fallback *mockExchangeRatesDataSource) *ExchangeRatesDataSourceContainer {
+	return &ExchangeRatesDataSourceContainer{
+		current:     primary,
+		currentName: settings.EuroCentralBankDataSource,
+		fallbacks: []*namedExchangeRatesDataSource{
+			{
+				name:       settings.InternationalMonetaryFundDataSource,
+				dataSource: fallback,
+			},
+		},
+		cache:   newExchangeRatesCache(),
+		backoff: newExchangeRatesDataSourceBackoff(),
+	}
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_CacheFreshData(t *testing.T) {
+	primary := &mockExchangeRatesDataSource{
+		response: &models.LatestExchangeRateResponse{DataSource: "primary", UpdateTime: time.Now().Unix()},
+	}
+	fallback := &mockExchangeRatesDataSource{}
+	container := newMockExchangeRatesDataSourceContainer(primary, fallback)
+	config := &settings.Config{EnableExchangeRatesCache: true}
+
+	resp, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", resp.DataSource)
+	assert.False(t, resp.Stale)
+
+	resp, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", resp.DataSource)
+	assert.Equal(t, 1, primary.requestCount)
+	assert.Equal(t, 0, fallback.requestCount)
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_CacheDisabled(t *testing.T) {
+	primary := &mockExchangeRatesDataSource{
+		response: &models.LatestExchangeRateResponse{DataSource: "primary", UpdateTime: time.Now().Unix()},
+	}
+	container := newMockExchangeRatesDataSourceContainer(primary, &mockExchangeRatesDataSource{})
+	config := &settings.Config{EnableExchangeRatesCache: false}
+
+	_, _ = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	_, _ = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Equal(t, 2, primary.requestCount)
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_Fallback(t *testing.T) {
+	primary := &mockExchangeRatesDataSource{err: errs.ErrFailedToRequestRemoteApi}
+	fallback := &mockExchangeRatesDataSource{
+		response: &models.LatestExchangeRateResponse{DataSource: "fallback", UpdateTime: time.Now().Unix()},
+	}
+	container := newMockExchangeRatesDataSourceContainer(primary, fallback)
+	config := &settings.Config{EnableExchangeRatesCache: true}
+
+	resp, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "fallback", resp.DataSource)
+	assert.False(t, resp.Stale)
+	assert.Equal(t, 1, primary.requestCount)
+	assert.Equal(t, 1, fallback.requestCount)
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_StaleData(t *testing.T) {
+	primary := &mockExchangeRatesDataSource{
+		response: &models.LatestExchangeRateResponse{DataSource: "primary", UpdateTime: time.Now().Add(-48 * time.Hour).Unix()},
+	}
+	fallback := &mockExchangeRatesDataSource{err: errs.ErrFailedToRequestRemoteApi}
+	container := newMockExchangeRatesDataSourceContainer(primary, fallback)
+	config := &settings.Config{EnableExchangeRatesCache: true, ExchangeRatesStaleDataMaxAge: 3600}
+
+	resp, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.False(t, resp.Stale)
+
+	container.cache.items[settings.EuroCentralBankDataSource].expiredAt = time.Now().Add(-time.Minute)
+	primary.err = errs.ErrFailedToRequestRemoteApi
+
+	resp, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", resp.DataSource)
+	assert.True(t, resp.Stale)
+	assert.False(t, primary.response.Stale)
+
+	container.cache.items[settings.EuroCentralBankDataSource].fetchedAt = time.Now().Add(-2 * time.Hour)
+
+	resp, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, err)
+	assert.Nil(t, resp)
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_BackoffFailedDataSource(t *testing.T) {
+	primary := &mockExchangeRatesDataSource{err: errs.ErrFailedToRequestRemoteApi}
+	fallback := &mockExchangeRatesDataSource{
+		response: &models.LatestExchangeRateResponse{DataSource: "fallback", UpdateTime: time.Now().Unix()},
+	}
+	container := newMockExchangeRatesDataSourceContainer(primary, fallback)
+	config := &settings.Config{EnableExchangeRatesCache: false}
+
+	resp, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "fallback", resp.DataSource)
+	assert.Equal(t, 1, primary.requestCount)
+
+	resp, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "fallback", resp.DataSource)
+	assert.Equal(t, 1, primary.requestCount)
+	assert.Equal(t, 2, fallback.requestCount)
+
+	container.backoff.failures[settings.EuroCentralBankDataSource].retryAfter = time.Now().Add(-time.Second)
+	primary.err = nil
+	primary.response = &models.LatestExchangeRateResponse{DataSource: "primary", UpdateTime: time.Now().Unix()}
+
+	resp, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "primary", resp.DataSource)
+	assert.Equal(t, 2, primary.requestCount)
+	assert.Equal(t, 0, len(container.backoff.failures))
+}
+
+func TestExchangeRatesDataSourceBackoffRecordFailure_IntervalDoubled(t *testing.T) {
+	backoff := newExchangeRatesDataSourceBackoff()
+	now := time.Now()
+
+	backoff.recordFailure("test", errs.ErrFailedToRequestRemoteApi, now)
+	assert.Equal(t, now.Add(exchangeRatesDataSourceMinBackoffInterval), backoff.failures["test"].retryAfter)
+	assert.Equal(t, errs.ErrFailedToRequestRemoteApi, backoff.getBackoffError("test", now))
+	assert.Nil(t, backoff.getBackoffError("test", now.Add(exchangeRatesDataSourceMinBackoffInterval)))
+
+	backoff.recordFailure("test", errs.ErrFailedToRequestRemoteApi, now)
+	assert.Equal(t, now.Add(2*exchangeRatesDataSourceMinBackoffInterval), backoff.failures["test"].retryAfter)
+
+	for i := 0; i < 10; i++ {
+		backoff.recordFailure("test", errs.ErrFailedToRequestRemoteApi, now)
+	}
+
+	assert.Equal(t, now.Add(exchangeRatesDataSourceMaxBackoffInterval), backoff.failures["test"].retryAfter)
+
+	backoff.recordSuccess("test")
+	assert.Nil(t, backoff.getBackoffError("test", now))
+}
+
+func TestExchangeRatesCacheGetFresh_ReturnDeepCopy(t *testing.T) {
+	cache := newExchangeRatesCache()
+	now := time.Now()
+	response := &models.LatestExchangeRateResponse{
+		UpdateTime: now.Unix(),
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.08"},
+		},
+	}
+
+	cache.set("test", response, now)
+	response.ExchangeRates[0].Rate = "1.09"
+
+	cachedResponse := cache.getFresh("test", now)
+	assert.Equal(t, "1.08", cachedResponse.ExchangeRates[0].Rate)
+
+	cachedResponse.ExchangeRates[0].Rate = "1.10"
+	cachedResponse.ExchangeRates = append(cachedResponse.ExchangeRates, &models.LatestExchangeRate{Currency: "CNY", Rate: "7.7"})
+
+	cachedResponse = cache.getFresh("test", now)
+	assert.Equal(t, 1, len(cachedResponse.ExchangeRates))
+	assert.Equal(t, "1.08", cachedResponse.ExchangeRates[0].Rate)
+}
+
+func TestExchangeRatesCacheSet_ExpiredAtNextBusinessDay(t *testing.T) {
+	cache := newExchangeRatesCache()
+	friday := time.Date(2024, 11, 15, 16, 0, 0, 0, time.UTC)
+	saturday := friday.Add(24 * time.Hour)
+
+	cache.set(settings.EuroCentralBankDataSource, &models.LatestExchangeRateResponse{UpdateTime: friday.Unix()}, saturday)
+	assert.Equal(t, friday.Add(3*24*time.Hour), cache.items[settings.EuroCentralBankDataSource].expiredAt.UTC())
+
+	cache.set(settings.BankOfRussiaDataSource, &models.LatestExchangeRateResponse{UpdateTime: friday.Unix()}, friday.Add(time.Hour))
+	assert.Equal(t, saturday, cache.items[settings.BankOfRussiaDataSource].expiredAt.UTC())
+}
+
+func TestExchangeRatesCacheSet_ExpiredAtNextPublishTime(t *testing.T) {
+	cache := newExchangeRatesCache()
+	now := time.Now()
+	updateTime := now.Add(-2 * time.Hour)
+
+	cache.set("test", &models.LatestExchangeRateResponse{UpdateTime: updateTime.Unix()}, now)
+	assert.Equal(t, time.Unix(updateTime.Unix(), 0).Add(exchangeRatesDataPublishInterval), cache.items["test"].expiredAt)
+
+	cache.set("test", &models.LatestExchangeRateResponse{UpdateTime: now.Add(-30 * time.Hour).Unix()}, now)
+	assert.Equal(t, now.Add(exchangeRatesCacheRetryInterval), cache.items["test"].expiredAt)
+}
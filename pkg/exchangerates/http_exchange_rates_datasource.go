@@ -25,13 +25,130 @@ type HttpExchangeRatesDataSource interface {
 	Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error)
 }
 
+// HistoricalHttpExchangeRatesDataSource defines the structure of http exchange rates data source which supports requesting historical exchange rates
+type HistoricalHttpExchangeRatesDataSource interface {
+	HttpExchangeRatesDataSource
+
+	// BuildRequestsByDate returns the http requests of the exchange rates published on or before the specified date
+	BuildRequestsByDate(date time.Time) ([]*http.Request, error)
+
+	// ParseByDate returns the common response entity of the exchange rates published on or before the specified date according to the data source raw response
+	ParseByDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error)
+}
+
+// HistoricalRangeHttpExchangeRatesDataSource defines the structure of http exchange rates data source which supports requesting the historical exchange rates of a date range in one request
+type HistoricalRangeHttpExchangeRatesDataSource interface {
+	HistoricalHttpExchangeRatesDataSource
+
+	// BuildRequestByDateRange returns the http request of the exchange rates published between the specified dates
+	BuildRequestByDateRange(startDate time.Time, endDate time.Time) (*http.Request, error)
+
+	// ParseByDateRange returns the common response entities of every publishing date between the specified dates according to the data source raw response
+	ParseByDateRange(c core.Context, content []byte, startDate time.Time, endDate time.Time) ([]*models.LatestExchangeRateResponse, error)
+}
+
 // CommonHttpExchangeRatesDataSource defines the structure of common http exchange rates data source
 type CommonHttpExchangeRatesDataSource struct {
-	ExchangeRatesDataSource
+	HistoricalExchangeRatesDataSource
 	dataSource HttpExchangeRatesDataSource
 }
 
+// GetLatestExchangeRates returns the latest exchange rates data from the http data source
 func (e *CommonHttpExchangeRatesDataSource) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	requests, err := e.dataSource.BuildRequests()
+
+	if err != nil {
+		log.Errorf(c, "[http_exchange_rates_datasource.GetLatestExchangeRates] failed to build requests for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return e.requestExchangeRates(c, uid, currentConfig, requests, e.dataSource.Parse)
+}
+
+// IsHistoricalExchangeRatesSupported returns whether the http data source supports requesting historical exchange rates
+func (e *CommonHttpExchangeRatesDataSource) IsHistoricalExchangeRatesSupported() bool {
+	_, ok := e.dataSource.(HistoricalHttpExchangeRatesDataSource)
+	return ok
+}
+
+// GetExchangeRatesByDate returns the exchange rates data published on or before the specified date from the http data source
+func (e *CommonHttpExchangeRatesDataSource) GetExchangeRatesByDate(c core.Context, uid int64, currentConfig *settings.Config, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	historicalDataSource, ok := e.dataSource.(HistoricalHttpExchangeRatesDataSource)
+
+	if !ok {
+		return nil, errs.ErrExchangeRatesHistoryNotSupported
+	}
+
+	requests, err := historicalDataSource.BuildRequestsByDate(date)
+
+	if err != nil {
+		log.Errorf(c, "[http_exchange_rates_datasource.GetExchangeRatesByDate] failed to build requests for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	return e.requestExchangeRates(c, uid, currentConfig, requests, func(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+		return historicalDataSource.ParseByDate(c, content, date)
+	})
+}
+
+// GetExchangeRatesByDateRange returns the exchange rates data of every publishing date between the specified dates from the http data source,
+// the whole date range is requested at once when the data source supports it, otherwise the exchange rates are requested by every weekday
+func (e *CommonHttpExchangeRatesDataSource) GetExchangeRatesByDateRange(c core.Context, uid int64, currentConfig *settings.Config, startDate time.Time, endDate time.Time) ([]*models.LatestExchangeRateResponse, error) {
+	if rangeDataSource, ok := e.dataSource.(HistoricalRangeHttpExchangeRatesDataSource); ok {
+		req, err := rangeDataSource.BuildRequestByDateRange(startDate, endDate)
+
+		if err != nil {
+			log.Errorf(c, "[http_exchange_rates_datasource.GetExchangeRatesByDateRange] failed to build request for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		body, err := e.requestContent(c, uid, e.newHttpClient(currentConfig), req, 0)
+
+		if err != nil {
+			return nil, err
+		}
+
+		exchangeRateResps, err := rangeDataSource.ParseByDateRange(c, body, startDate, endDate)
+
+		if err != nil {
+			log.Errorf(c, "[http_exchange_rates_datasource.GetExchangeRatesByDateRange] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+		}
+
+		return exchangeRateResps, nil
+	}
+
+	if !e.IsHistoricalExchangeRatesSupported() {
+		return nil, errs.ErrExchangeRatesHistoryNotSupported
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0)
+	requestedUpdateTimes := make(map[int64]bool)
+
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+
+		exchangeRateResp, err := e.GetExchangeRatesByDate(c, uid, currentConfig, date)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// the exchange rates of the previous publishing date are returned on holidays
+		if requestedUpdateTimes[exchangeRateResp.UpdateTime] {
+			continue
+		}
+
+		requestedUpdateTimes[exchangeRateResp.UpdateTime] = true
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	return exchangeRateResps, nil
+}
+
+func (e *CommonHttpExchangeRatesDataSource) newHttpClient(currentConfig *settings.Config) *http.Client {
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	utils.SetProxyUrl(transport, currentConfig.ExchangeRatesProxy)
 
@@ -41,50 +158,60 @@ func (e *CommonHttpExchangeRatesDataSource) GetLatestExchangeRates(c core.Contex
 		}
 	}
 
-	client := &http.Client{
+	return &http.Client{
 		Transport: transport,
 		Timeout:   time.Duration(currentConfig.ExchangeRatesRequestTimeout) * time.Millisecond,
 	}
+}
 
-	requests, err := e.dataSource.BuildRequests()
+func (e *CommonHttpExchangeRatesDataSource) requestContent(c core.Context, uid int64, client *http.Client, req *http.Request, index int) ([]byte, error) {
+	if len(req.Header.Values("User-Agent")) < 1 {
+		req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s", settings.Version))
+	} else if req.Header.Get("User-Agent") == "" {
+		req.Header.Del("User-Agent")
+	}
+
+	resp, err := client.Do(req)
 
 	if err != nil {
-		log.Errorf(c, "[http_exchange_rates_datasource.GetLatestExchangeRates] failed to build requests for user \"uid:%d\", because %s", uid, err.Error())
+		log.Errorf(c, "[http_exchange_rates_datasource.requestContent] failed to request exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, errs.ErrFailedToRequestRemoteApi
 	}
 
-	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(requests))
+	defer resp.Body.Close()
 
-	for i := 0; i < len(requests); i++ {
-		req := requests[i]
+	if resp.StatusCode != 200 {
+		log.Errorf(c, "[http_exchange_rates_datasource.requestContent] failed to get exchange rate data response for user \"uid:%d\", because response code is not 200", uid)
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
 
-		if len(req.Header.Values("User-Agent")) < 1 {
-			req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s", settings.Version))
-		} else if req.Header.Get("User-Agent") == "" {
-			req.Header.Del("User-Agent")
-		}
+	body, err := io.ReadAll(resp.Body)
 
-		resp, err := client.Do(req)
+	if err != nil {
+		log.Errorf(c, "[http_exchange_rates_datasource.requestContent] failed to read exchange rate data response for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
 
-		if err != nil {
-			log.Errorf(c, "[http_exchange_rates_datasource.GetLatestExchangeRates] failed to request latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.ErrFailedToRequestRemoteApi
-		}
+	log.Debugf(c, "[http_exchange_rates_datasource.requestContent] response#%d is %s", index, body)
 
-		if resp.StatusCode != 200 {
-			log.Errorf(c, "[http_exchange_rates_datasource.GetLatestExchangeRates] failed to get latest exchange rate data response for user \"uid:%d\", because response code is not 200", uid)
-			return nil, errs.ErrFailedToRequestRemoteApi
-		}
+	return body, nil
+}
 
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
+func (e *CommonHttpExchangeRatesDataSource) requestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config, requests []*http.Request, parse func(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error)) (*models.LatestExchangeRateResponse, error) {
+	client := e.newHttpClient(currentConfig)
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(requests))
+
+	for i := 0; i < len(requests); i++ {
+		body, err := e.requestContent(c, uid, client, requests[i], i)
 
-		log.Debugf(c, "[http_exchange_rates_datasource.GetLatestExchangeRates] response#%d is %s", i, body)
+		if err != nil {
+			return nil, err
+		}
 
-		exchangeRateResp, err := e.dataSource.Parse(c, body)
+		exchangeRateResp, err := parse(c, body)
 
 		if err != nil {
-			log.Errorf(c, "[http_exchange_rates_datasource.GetLatestExchangeRates] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
+			log.Errorf(c, "[http_exchange_rates_datasource.requestExchangeRates] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
 			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
 		}
 
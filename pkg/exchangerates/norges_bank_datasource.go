@@ -3,6 +3,7 @@ package exchangerates
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"math"
 	"net/http"
 	"time"
@@ -18,12 +19,14 @@ import (
 )
 
 const norgesBankExchangeRateUrl = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&lastNObservations=1"
+const norgesBankHistoricalExchangeRateUrlFormat = "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&startPeriod=%s&endPeriod=%s&lastNObservations=1"
 const norgesBankExchangeRateReferenceUrl = "https://www.norges-bank.no/en/topics/Statistics/exchange_rates/"
 const norgesBankDataSource = "Norges Bank"
 const norgesBankBaseCurrency = "NOK"
 
 const norgesBankUpdateDateFormat = "2006-01-02 15"
 const norgesBankUpdateDateTimezone = "Europe/Oslo"
+const norgesBankHistoricalLookbackDays = 7
 
 // NorgesBankDataSource defines the structure of exchange rates data source of Norges Bank
 type NorgesBankDataSource struct {
@@ -170,6 +173,18 @@ func (e *NorgesBankDataSource) BuildRequests() ([]*http.Request, error) {
 	return []*http.Request{req}, nil
 }
 
+// BuildRequestsByDate returns the Norges Bank historical exchange rates http requests
+func (e *NorgesBankDataSource) BuildRequestsByDate(date time.Time) ([]*http.Request, error) {
+	startDate := date.AddDate(0, 0, -norgesBankHistoricalLookbackDays) // Exchange rates are not published on weekends and holidays
+	req, err := http.NewRequest("GET", fmt.Sprintf(norgesBankHistoricalExchangeRateUrlFormat, startDate.Format(time.DateOnly), date.Format(time.DateOnly)), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*http.Request{req}, nil
+}
+
 // Parse returns the common response entity according to the Norges Bank data source raw response
 func (e *NorgesBankDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
 	xmlDecoder := xml.NewDecoder(bytes.NewReader(content))
@@ -192,3 +207,8 @@ func (e *NorgesBankDataSource) Parse(c core.Context, content []byte) (*models.La
 
 	return latestExchangeRateResponse, nil
 }
+
+// ParseByDate returns the common response entity of the exchange rates published on or before the specified date according to the Norges Bank data source raw response
+func (e *NorgesBankDataSource) ParseByDate(c core.Context, content []byte, date time.Time) (*models.LatestExchangeRateResponse, error) {
+	return e.Parse(c, content)
+}
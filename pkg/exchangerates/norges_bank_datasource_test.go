@@ -2,6 +2,7 @@ package exchangerates
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -220,3 +221,12 @@ func TestNorgesBankDataSource_InvalidUnit(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Len(t, actualLatestExchangeRateResponse.ExchangeRates, 0)
 }
+
+func TestNorgesBankDataSource_BuildRequestsByDate(t *testing.T) {
+	dataSource := &NorgesBankDataSource{}
+
+	requests, err := dataSource.BuildRequestsByDate(time.Date(2021, 4, 4, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "https://data.norges-bank.no/api/data/EXR/B..NOK.SP?format=sdmx-compact-2.1&startPeriod=2021-03-28&endPeriod=2021-04-04&lastNObservations=1", requests[0].URL.String())
+}
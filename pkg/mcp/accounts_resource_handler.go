@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mcpAccountsResourceHandler struct{}
+
+var MCPAccountsResourceHandler = &mcpAccountsResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpAccountsResourceHandler) URI() string {
+	return "ezbookkeeping://accounts"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpAccountsResourceHandler) Name() string {
+	return "accounts"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpAccountsResourceHandler) Description() string {
+	return "All account names of the current user in ezBookkeeping, grouped by account category."
+}
+
+// MimeType returns the mime type of the MCP resource contents
+func (h *mcpAccountsResourceHandler) MimeType() string {
+	return mcpJSONResourceMimeType
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpAccountsResourceHandler) Read(c *core.WebContext, uri string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	return readMCPResourceFromTool(c, uri, h.MimeType(), MCPQueryAllAccountsToolHandler, nil, user, currentConfig, services)
+}
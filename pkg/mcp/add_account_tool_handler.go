@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const mcpDefaultIconId = int64(1)
+const mcpDefaultColor = "000000"
+const mcpMaxNameLength = 64
+const mcpMaxCommentLength = 255
+
+var mcpAccountCategoryNames = map[string]models.AccountCategory{
+	"cash":                   models.ACCOUNT_CATEGORY_CASH,
+	"checking":               models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+	"savings":                models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT,
+	"credit_card":            models.ACCOUNT_CATEGORY_CREDIT_CARD,
+	"virtual":                models.ACCOUNT_CATEGORY_VIRTUAL,
+	"debt":                   models.ACCOUNT_CATEGORY_DEBT,
+	"receivables":            models.ACCOUNT_CATEGORY_RECEIVABLES,
+	"investment":             models.ACCOUNT_CATEGORY_INVESTMENT,
+	"certificate_of_deposit": models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT,
+}
+
+// MCPAddAccountRequest represents all parameters of the add account request
+type MCPAddAccountRequest struct {
+	Name                    string `json:"name" jsonschema_description:"Account name (maximum 64 characters)"`
+	Category                string `json:"category" jsonschema:"enum=cash,enum=checking,enum=savings,enum=credit_card,enum=virtual,enum=debt,enum=receivables,enum=investment,enum=certificate_of_deposit" jsonschema_description:"Account category (cash, checking, savings, credit_card, virtual, debt, receivables, investment, certificate_of_deposit)"`
+	Currency                string `json:"currency,omitempty" jsonschema_description:"Currency code of the account (e.g. USD) (optional, default is the default currency of the current user)"`
+	Balance                 string `json:"balance,omitempty" jsonschema_description:"Opening balance of the account, or opening outstanding balance for credit card and debt accounts (optional)"`
+	BalanceTime             string `json:"balance_time,omitempty" jsonschema:"format=date-time" jsonschema_description:"Time of the opening balance in RFC 3339 format (e.g. 2023-01-01T12:00:00Z) (required when balance is not zero)"`
+	CreditCardStatementDate int    `json:"credit_card_statement_date,omitempty" jsonschema:"minimum=0,maximum=28" jsonschema_description:"Statement day of month for credit card accounts (optional, 1-28)"`
+	Comment                 string `json:"comment,omitempty" jsonschema_description:"Account description (optional)"`
+	DryRun                  bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the account will not be saved, only validated (optional)"`
+}
+
+// MCPAddAccountResponse represents the response structure for add account
+type MCPAddAccountResponse struct {
+	Success  bool   `json:"success" jsonschema_description:"Indicates whether the account was added successfully"`
+	DryRun   bool   `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this is a dry run (account not saved actually)"`
+	Name     string `json:"name" jsonschema_description:"Account name"`
+	Currency string `json:"currency" jsonschema_description:"Currency code of the account (e.g. USD)"`
+	Balance  string `json:"balance" jsonschema_description:"Account balance (or outstanding balance for credit card and debt accounts)"`
+}
+
+type mcpAddAccountToolHandler struct{}
+
+var MCPAddAccountToolHandler = &mcpAddAccountToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpAddAccountToolHandler) Name() string {
+	return "add_account"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpAddAccountToolHandler) Description() string {
+	return "Add a new account in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpAddAccountToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddAccountRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpAddAccountToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddAccountResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpAddAccountToolHandler) IsReadOnly() bool {
+	return false
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpAddAccountToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var addAccountRequest MCPAddAccountRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &addAccountRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	name := strings.TrimSpace(addAccountRequest.Name)
+
+	if name == "" || utf8.RuneCountInString(name) > mcpMaxNameLength || utf8.RuneCountInString(addAccountRequest.Comment) > mcpMaxCommentLength {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	accountCategory, exists := mcpAccountCategoryNames[addAccountRequest.Category]
+
+	if !exists {
+		return nil, nil, errs.ErrAccountCategoryInvalid
+	}
+
+	if addAccountRequest.CreditCardStatementDate != 0 && accountCategory != models.ACCOUNT_CATEGORY_CREDIT_CARD {
+		return nil, nil, errs.ErrCannotSetStatementDateForNonCreditCard
+	}
+
+	if addAccountRequest.CreditCardStatementDate < 0 || addAccountRequest.CreditCardStatementDate > 28 {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(addAccountRequest.Currency))
+
+	if currency == "" {
+		currency = user.DefaultCurrency
+	}
+
+	if _, exists := validators.AllCurrencyNames[currency]; !exists {
+		return nil, nil, errs.ErrAccountCurrencyInvalid
+	}
+
+	balance := int64(0)
+
+	if addAccountRequest.Balance != "" {
+		amount, err := utils.ParseAmount(addAccountRequest.Balance)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		balance = amount
+	}
+
+	if accountCategory.IsLiability() {
+		balance = -balance
+	}
+
+	balanceTime := int64(0)
+	utcOffset := int16(0)
+
+	if balance != 0 {
+		if addAccountRequest.BalanceTime == "" {
+			return nil, nil, errs.ErrAccountBalanceTimeNotSet
+		}
+
+		balanceDateTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(addAccountRequest.BalanceTime)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		balanceTime = balanceDateTime.Unix()
+		utcOffset = utils.GetTimezoneOffsetMinutes(balanceDateTime.Location())
+	}
+
+	uid := user.Uid
+	account := &models.Account{
+		Uid:      uid,
+		Name:     name,
+		Category: accountCategory,
+		Type:     models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Icon:     mcpDefaultIconId,
+		Color:    mcpDefaultColor,
+		Currency: currency,
+		Balance:  balance,
+		Comment:  addAccountRequest.Comment,
+		Extend:   &models.AccountExtend{},
+	}
+
+	if accountCategory == models.ACCOUNT_CATEGORY_CREDIT_CARD {
+		account.Extend.CreditCardStatementDate = &addAccountRequest.CreditCardStatementDate
+	}
+
+	if !addAccountRequest.DryRun {
+		maxOrderId, err := services.GetAccountService().GetMaxDisplayOrder(c, uid, accountCategory)
+
+		if err != nil {
+			log.Errorf(c, "[add_account.Handle] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, nil, err
+		}
+
+		account.DisplayOrder = maxOrderId + 1
+		err = services.GetAccountService().CreateAccounts(c, account, balanceTime, nil, nil, utcOffset)
+
+		if err != nil {
+			log.Errorf(c, "[add_account.Handle] failed to create account \"id:%d\" for user \"uid:%d\", because %s", account.AccountId, uid, err.Error())
+			return nil, nil, err
+		}
+
+		log.Infof(c, "[add_account.Handle] user \"uid:%d\" has created a new account \"id:%d\" successfully", uid, account.AccountId)
+		Sessions.NotifyResourceUpdated(uid, MCPAccountsResourceHandler.URI())
+	}
+
+	response := MCPAddAccountResponse{
+		Success:  true,
+		DryRun:   addAccountRequest.DryRun,
+		Name:     account.Name,
+		Currency: account.Currency,
+	}
+
+	if accountCategory.IsLiability() {
+		response.Balance = utils.FormatAmount(-account.Balance)
+	} else {
+		response.Balance = utils.FormatAmount(account.Balance)
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
@@ -0,0 +1,336 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+const investmentTransactionTypeBuy = "buy"
+const investmentTransactionTypeSell = "sell"
+
+const investmentQuantityUnitTroyOunce = "troy_ounce"
+const investmentQuantityUnitGram = "gram"
+
+// MCPAddInvestmentTransactionRequest represents all parameters of the add investment transaction request
+type MCPAddInvestmentTransactionRequest struct {
+	Type          string `json:"type" jsonschema:"enum=buy,enum=sell" jsonschema_description:"Investment transaction type (buy, sell)"`
+	Time          string `json:"time" jsonschema:"format=date-time" jsonschema_description:"Investment transaction time in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
+	TickerSymbol  string `json:"ticker_symbol" jsonschema_description:"Ticker symbol of the investment (e.g. VTI, BTC for bitcoin, XAU for gold)"`
+	CompanyName   string `json:"company_name,omitempty" jsonschema_description:"Company or fund name of the investment, only used when buying a new holding (optional)"`
+	AssetType     string `json:"asset_type,omitempty" jsonschema:"enum=stock,enum=etf,enum=mutual_fund,enum=cryptocurrency,enum=precious_metal,enum=bond" jsonschema_description:"Asset type of the investment, only used when buying a new holding (optional, default is stock)"`
+	Shares        string `json:"shares" jsonschema_description:"Number of shares (or coins for cryptocurrency) bought or sold (e.g. 5 or 0.00012345)"`
+	QuantityUnit  string `json:"quantity_unit,omitempty" jsonschema:"enum=troy_ounce,enum=gram" jsonschema_description:"Unit of the shares and price per share for precious metal (optional, default is troy_ounce)"`
+	PricePerShare string `json:"price_per_share" jsonschema_description:"Price per share (or per coin for cryptocurrency, per quantity unit for precious metal) of the trade"`
+	Fees          string `json:"fees,omitempty" jsonschema_description:"Fees of the trade (optional)"`
+	Currency      string `json:"currency,omitempty" jsonschema_description:"Currency code of the trade (e.g. USD) (optional, default is the default currency of the current user, and the precious metal holding only supports USD)"`
+	WalletAddress string `json:"wallet_address,omitempty" jsonschema_description:"Wallet address of the cryptocurrency holding, only used when buying a new cryptocurrency holding (optional)"`
+	Comment       string `json:"comment,omitempty" jsonschema_description:"Investment transaction description (optional)"`
+	DryRun        bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the investment transaction will not be saved, only validated (optional)"`
+}
+
+// MCPAddInvestmentTransactionResponse represents the response structure for add investment transaction
+type MCPAddInvestmentTransactionResponse struct {
+	Success         bool   `json:"success" jsonschema_description:"Indicates whether the investment transaction was added successfully"`
+	DryRun          bool   `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this is a dry run (investment transaction not saved actually)"`
+	TickerSymbol    string `json:"ticker_symbol" jsonschema_description:"Ticker symbol of the investment (e.g. VTI)"`
+	AssetType       string `json:"asset_type" jsonschema:"enum=stock,enum=etf,enum=mutual_fund,enum=cryptocurrency,enum=precious_metal,enum=bond" jsonschema_description:"Asset type of the investment (stock, etf, mutual_fund, cryptocurrency, precious_metal, bond)"`
+	Shares          string `json:"shares" jsonschema_description:"Number of shares (or coins for cryptocurrency, troy ounces for precious metal) owned after the investment transaction"`
+	AvgCostPerShare string `json:"avg_cost_per_share" jsonschema_description:"Average cost per share after the investment transaction"`
+	TotalInvested   string `json:"total_invested" jsonschema_description:"Total cost of the owned shares after the investment transaction"`
+}
+
+type mcpAddInvestmentTransactionToolHandler struct{}
+
+var MCPAddInvestmentTransactionToolHandler = &mcpAddInvestmentTransactionToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpAddInvestmentTransactionToolHandler) Name() string {
+	return "add_investment_transaction"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpAddInvestmentTransactionToolHandler) Description() string {
+	return "Record a buy or sell trade of an investment holding in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpAddInvestmentTransactionToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddInvestmentTransactionRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpAddInvestmentTransactionToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddInvestmentTransactionResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpAddInvestmentTransactionToolHandler) IsReadOnly() bool {
+	return false
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpAddInvestmentTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var addInvestmentTransactionRequest MCPAddInvestmentTransactionRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &addInvestmentTransactionRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	tickerSymbol := strings.ToUpper(strings.TrimSpace(addInvestmentTransactionRequest.TickerSymbol))
+
+	if tickerSymbol == "" {
+		return nil, nil, errs.ErrTickerSymbolIsEmpty
+	}
+
+	investment, err := services.GetInvestmentService().GetInvestmentByTickerSymbol(c, uid, tickerSymbol)
+
+	if err != nil && err != errs.ErrInvestmentNotFound {
+		log.Errorf(c, "[add_investment_transaction.Handle] failed to get investment \"%s\" for user \"uid:%d\", because %s", tickerSymbol, uid, err.Error())
+		return nil, nil, err
+	}
+
+	transaction, holding, err := h.createNewInvestmentTransactionModel(uid, user.DefaultCurrency, tickerSymbol, investment, &addInvestmentTransactionRequest)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionEditable := user.CanEditTransactionByTransactionTime(utils.GetMinTransactionTimeFromUnixTime(transaction.TransactionTime), transaction.TimezoneUtcOffset)
+
+	if !transactionEditable {
+		return nil, nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
+	}
+
+	if investment == nil && transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_SELL {
+		log.Warnf(c, "[add_investment_transaction.Handle] investment \"%s\" not found for user \"uid:%d\"", transaction.TickerSymbol, uid)
+		return nil, nil, errs.ErrInvestmentNotFound
+	}
+
+	if investment != nil && transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_SELL && investment.SharesOwned < transaction.Shares {
+		return nil, nil, errs.ErrInsufficientShares
+	}
+
+	if addInvestmentTransactionRequest.DryRun {
+		newInvestment := h.getInvestmentAfterTransaction(holding, transaction)
+		return h.createNewMCPAddInvestmentTransactionResponse(newInvestment, true)
+	}
+
+	if investment == nil {
+		// buying a ticker symbol which is not held yet creates a new investment holding with the opening transaction
+		err = services.GetInvestmentService().CreateInvestmentWithTransaction(c, holding, transaction)
+
+		if err != nil {
+			log.Errorf(c, "[add_investment_transaction.Handle] failed to create investment \"%s\" for user \"uid:%d\", because %s", transaction.TickerSymbol, uid, err.Error())
+			return nil, nil, err
+		}
+
+		log.Infof(c, "[add_investment_transaction.Handle] user \"uid:%d\" has created a new investment \"id:%d\" with investment transaction \"id:%d\" successfully", uid, holding.InvestmentId, transaction.TransactionId)
+		Sessions.NotifyResourceUpdated(uid, MCPPortfolioResourceHandler.URI())
+
+		return h.createNewMCPAddInvestmentTransactionResponse(holding, false)
+	}
+
+	err = services.GetInvestmentService().AddInvestmentTransaction(c, transaction)
+
+	if err != nil {
+		log.Errorf(c, "[add_investment_transaction.Handle] failed to create investment transaction for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	log.Infof(c, "[add_investment_transaction.Handle] user \"uid:%d\" has created a new investment transaction \"id:%d\" successfully", uid, transaction.TransactionId)
+	Sessions.NotifyResourceUpdated(uid, MCPPortfolioResourceHandler.URI())
+
+	newInvestment, err := services.GetInvestmentService().GetInvestmentByTickerSymbol(c, uid, transaction.TickerSymbol)
+
+	if err != nil {
+		log.Warnf(c, "[add_investment_transaction.Handle] failed to get latest investment info after investment transaction created, because %s", err.Error())
+		newInvestment = h.getInvestmentAfterTransaction(investment, transaction)
+	}
+
+	return h.createNewMCPAddInvestmentTransactionResponse(newInvestment, false)
+}
+
+func (h *mcpAddInvestmentTransactionToolHandler) createNewInvestmentTransactionModel(uid int64, defaultCurrency string, tickerSymbol string, investment *models.Investment, addInvestmentTransactionRequest *MCPAddInvestmentTransactionRequest) (*models.InvestmentTransaction, *models.Investment, error) {
+	var transactionType models.InvestmentTransactionType
+
+	if addInvestmentTransactionRequest.Type == investmentTransactionTypeBuy {
+		transactionType = models.INVESTMENT_TRANSACTION_TYPE_BUY
+	} else if addInvestmentTransactionRequest.Type == investmentTransactionTypeSell {
+		transactionType = models.INVESTMENT_TRANSACTION_TYPE_SELL
+	} else {
+		return nil, nil, errs.ErrTransactionTypeInvalid
+	}
+
+	transactionTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(addInvestmentTransactionRequest.Time)
+
+	if err != nil {
+		return nil, nil, errs.ErrTransactionTimeInvalid
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(addInvestmentTransactionRequest.Currency))
+
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	if _, exists := validators.AllCurrencyNames[currency]; !exists {
+		return nil, nil, errs.ErrInvestmentCurrencyInvalid
+	}
+
+	newInvestment := investment
+
+	if newInvestment == nil {
+		assetType, err := getInvestmentAssetTypeByName(addInvestmentTransactionRequest.AssetType)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if assetType == models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL && !models.IsPreciousMetalTickerSymbol(tickerSymbol) {
+			return nil, nil, errs.ErrPreciousMetalNotSupported
+		}
+
+		if assetType == models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL && currency != models.PreciousMetalPriceCurrency {
+			return nil, nil, errs.ErrPreciousMetalCurrencyNotSupported
+		}
+
+		walletAddress := strings.TrimSpace(addInvestmentTransactionRequest.WalletAddress)
+
+		if walletAddress != "" && assetType != models.INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY {
+			return nil, nil, errs.ErrInvestmentWalletAddressInvalid
+		}
+
+		newInvestment = &models.Investment{
+			Uid:               uid,
+			TickerSymbol:      tickerSymbol,
+			CompanyName:       strings.TrimSpace(addInvestmentTransactionRequest.CompanyName),
+			AssetType:         assetType,
+			QuantityPrecision: assetType.DefaultQuantityPrecision(),
+			PricePrecision:    assetType.DefaultPricePrecision(),
+			Currency:          currency,
+			WalletAddress:     walletAddress,
+		}
+	}
+
+	shares, err := strconv.ParseFloat(strings.TrimSpace(addInvestmentTransactionRequest.Shares), 64)
+
+	if err != nil || shares <= 0 {
+		return nil, nil, errs.ErrInvalidSharesAmount
+	}
+
+	pricePerShare, err := strconv.ParseFloat(strings.TrimSpace(addInvestmentTransactionRequest.PricePerShare), 64)
+
+	if err != nil || pricePerShare <= 0 {
+		return nil, nil, errs.ErrInvalidPricePerShare
+	}
+
+	if addInvestmentTransactionRequest.QuantityUnit == investmentQuantityUnitGram {
+		if newInvestment.AssetType != models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL {
+			return nil, nil, errs.ErrInvalidSharesAmount
+		}
+
+		// precious metal holdings are always stored in troy ounces
+		shares = models.ConvertGramsToTroyOunces(shares)
+		pricePerShare = pricePerShare * models.GramsPerTroyOunce
+	} else if addInvestmentTransactionRequest.QuantityUnit != "" && addInvestmentTransactionRequest.QuantityUnit != investmentQuantityUnitTroyOunce {
+		return nil, nil, errs.ErrInvalidSharesAmount
+	}
+
+	shares = models.RoundInvestmentQuantity(shares, newInvestment.QuantityPrecision)
+
+	if shares <= 0 {
+		return nil, nil, errs.ErrInvalidSharesAmount
+	}
+
+	pricePerShareValue := models.ConvertInvestmentPriceFromFloat64(pricePerShare, newInvestment.PricePrecision)
+
+	if pricePerShareValue <= 0 {
+		return nil, nil, errs.ErrInvalidPricePerShare
+	}
+
+	fees := int64(0)
+
+	if addInvestmentTransactionRequest.Fees != "" {
+		fees, err = utils.ParseAmount(addInvestmentTransactionRequest.Fees)
+
+		if err != nil || fees < 0 {
+			return nil, nil, errs.ErrAmountInvalid
+		}
+	}
+
+	transaction := &models.InvestmentTransaction{
+		Uid:               uid,
+		TickerSymbol:      tickerSymbol,
+		Type:              transactionType,
+		Shares:            shares,
+		PricePerShare:     pricePerShareValue,
+		PricePrecision:    newInvestment.PricePrecision,
+		Fees:              fees,
+		Currency:          currency,
+		TransactionTime:   transactionTime.Unix(),
+		TimezoneUtcOffset: utils.GetTimezoneOffsetMinutes(transactionTime.Location()),
+		Comment:           addInvestmentTransactionRequest.Comment,
+	}
+
+	return transaction, newInvestment, nil
+}
+
+func (h *mcpAddInvestmentTransactionToolHandler) getInvestmentAfterTransaction(investment *models.Investment, transaction *models.InvestmentTransaction) *models.Investment {
+	totalAmount := models.GetInvestmentAmount(transaction.Shares, transaction.PricePerShare, transaction.PricePrecision)
+	newInvestment := *investment
+
+	if investment.InvestmentId == 0 {
+		newInvestment.SharesOwned = transaction.Shares
+		newInvestment.AvgCostPerShare = transaction.PricePerShare
+		newInvestment.TotalInvested = totalAmount
+		return &newInvestment
+	}
+
+	if transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_BUY {
+		newInvestment.SharesOwned = models.RoundInvestmentQuantity(investment.SharesOwned+transaction.Shares, investment.QuantityPrecision)
+		newInvestment.TotalInvested = investment.TotalInvested + totalAmount
+		newInvestment.AvgCostPerShare = models.GetInvestmentPriceFromAmount(newInvestment.TotalInvested, newInvestment.SharesOwned, investment.PricePrecision)
+	} else {
+		newInvestment.SharesOwned = models.RoundInvestmentQuantity(investment.SharesOwned-transaction.Shares, investment.QuantityPrecision)
+		newInvestment.TotalInvested = models.GetInvestmentAmount(newInvestment.SharesOwned, investment.AvgCostPerShare, investment.PricePrecision)
+	}
+
+	return &newInvestment
+}
+
+func (h *mcpAddInvestmentTransactionToolHandler) createNewMCPAddInvestmentTransactionResponse(investment *models.Investment, dryRun bool) (any, []*MCPTextContent, error) {
+	response := MCPAddInvestmentTransactionResponse{
+		Success:         true,
+		DryRun:          dryRun,
+		TickerSymbol:    investment.TickerSymbol,
+		AssetType:       getInvestmentAssetTypeName(investment.AssetType),
+		Shares:          formatInvestmentShares(investment.SharesOwned),
+		AvgCostPerShare: formatInvestmentPrice(investment.AvgCostPerShare, investment.PricePrecision),
+		TotalInvested:   utils.FormatAmount(investment.TotalInvested),
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
@@ -0,0 +1,175 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPAddTransactionCategoryRequest represents all parameters of the add transaction category request
+type MCPAddTransactionCategoryRequest struct {
+	Name                string `json:"name" jsonschema_description:"Transaction category name (maximum 64 characters)"`
+	Type                string `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction category type (income, expense, transfer)"`
+	PrimaryCategoryName string `json:"primary_category_name,omitempty" jsonschema_description:"Primary category name which the new secondary category belongs to (optional, leave empty to add a primary category)"`
+	Comment             string `json:"comment,omitempty" jsonschema_description:"Transaction category description (optional)"`
+	DryRun              bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction category will not be saved, only validated (optional)"`
+}
+
+// MCPAddTransactionCategoryResponse represents the response structure for add transaction category
+type MCPAddTransactionCategoryResponse struct {
+	Success             bool   `json:"success" jsonschema_description:"Indicates whether the transaction category was added successfully"`
+	DryRun              bool   `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this is a dry run (transaction category not saved actually)"`
+	Name                string `json:"name" jsonschema_description:"Transaction category name"`
+	PrimaryCategoryName string `json:"primary_category_name,omitempty" jsonschema_description:"Primary category name of the transaction category (only for secondary categories)"`
+}
+
+type mcpAddTransactionCategoryToolHandler struct{}
+
+var MCPAddTransactionCategoryToolHandler = &mcpAddTransactionCategoryToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpAddTransactionCategoryToolHandler) Name() string {
+	return "add_transaction_category"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpAddTransactionCategoryToolHandler) Description() string {
+	return "Add a new primary or secondary transaction category in ezBookkeeping, only secondary categories can be used in transactions."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpAddTransactionCategoryToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddTransactionCategoryRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpAddTransactionCategoryToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddTransactionCategoryResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpAddTransactionCategoryToolHandler) IsReadOnly() bool {
+	return false
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpAddTransactionCategoryToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var addCategoryRequest MCPAddTransactionCategoryRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &addCategoryRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	name := strings.TrimSpace(addCategoryRequest.Name)
+
+	if name == "" || utf8.RuneCountInString(name) > mcpMaxNameLength || utf8.RuneCountInString(addCategoryRequest.Comment) > mcpMaxCommentLength {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	var categoryType models.TransactionCategoryType
+
+	if addCategoryRequest.Type == transactionTypeIncome {
+		categoryType = models.CATEGORY_TYPE_INCOME
+	} else if addCategoryRequest.Type == transactionTypeExpense {
+		categoryType = models.CATEGORY_TYPE_EXPENSE
+	} else if addCategoryRequest.Type == transactionTypeTransfer {
+		categoryType = models.CATEGORY_TYPE_TRANSFER
+	} else {
+		return nil, nil, errs.ErrTransactionCategoryTypeInvalid
+	}
+
+	uid := user.Uid
+	var parentCategory *models.TransactionCategory
+
+	if addCategoryRequest.PrimaryCategoryName != "" {
+		primaryCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, categoryType, 0)
+
+		if err != nil {
+			log.Warnf(c, "[add_transaction_category.Handle] get primary transaction categories error, because %s", err.Error())
+			return nil, nil, err
+		}
+
+		primaryCategoriesMap := services.GetTransactionCategoryService().GetVisibleCategoryNameMapByList(primaryCategories)
+		category, exists := primaryCategoriesMap[addCategoryRequest.PrimaryCategoryName]
+
+		if !exists {
+			log.Warnf(c, "[add_transaction_category.Handle] primary category \"%s\" not found for user \"uid:%d\"", addCategoryRequest.PrimaryCategoryName, uid)
+			return nil, nil, errs.ErrParentTransactionCategoryNotFound
+		}
+
+		parentCategory = category
+	}
+
+	category := &models.TransactionCategory{
+		Uid:     uid,
+		Name:    name,
+		Type:    categoryType,
+		Icon:    mcpDefaultIconId,
+		Color:   mcpDefaultColor,
+		Comment: addCategoryRequest.Comment,
+	}
+
+	if parentCategory != nil {
+		category.ParentCategoryId = parentCategory.CategoryId
+		category.Icon = parentCategory.Icon
+		category.Color = parentCategory.Color
+	}
+
+	if !addCategoryRequest.DryRun {
+		var maxOrderId int32
+		var err error
+
+		if parentCategory == nil {
+			maxOrderId, err = services.GetTransactionCategoryService().GetMaxDisplayOrder(c, uid, categoryType)
+		} else {
+			maxOrderId, err = services.GetTransactionCategoryService().GetMaxSubCategoryDisplayOrder(c, uid, categoryType, parentCategory.CategoryId)
+		}
+
+		if err != nil {
+			log.Errorf(c, "[add_transaction_category.Handle] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, nil, err
+		}
+
+		category.DisplayOrder = maxOrderId + 1
+		err = services.GetTransactionCategoryService().CreateCategory(c, category)
+
+		if err != nil {
+			log.Errorf(c, "[add_transaction_category.Handle] failed to create category \"id:%d\" for user \"uid:%d\", because %s", category.CategoryId, uid, err.Error())
+			return nil, nil, err
+		}
+
+		log.Infof(c, "[add_transaction_category.Handle] user \"uid:%d\" has created a new category \"id:%d\" successfully", uid, category.CategoryId)
+		Sessions.NotifyResourceUpdated(uid, MCPTransactionCategoriesResourceHandler.URI())
+	}
+
+	response := MCPAddTransactionCategoryResponse{
+		Success: true,
+		DryRun:  addCategoryRequest.DryRun,
+		Name:    category.Name,
+	}
+
+	if parentCategory != nil {
+		response.PrimaryCategoryName = parentCategory.Name
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
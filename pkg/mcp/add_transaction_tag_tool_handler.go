@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPAddTransactionTagRequest represents all parameters of the add transaction tag request
+type MCPAddTransactionTagRequest struct {
+	Name   string `json:"name" jsonschema_description:"Transaction tag name (maximum 64 characters)"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction tag will not be saved, only validated (optional)"`
+}
+
+// MCPAddTransactionTagResponse represents the response structure for add transaction tag
+type MCPAddTransactionTagResponse struct {
+	Success bool   `json:"success" jsonschema_description:"Indicates whether the transaction tag was added successfully"`
+	DryRun  bool   `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this is a dry run (transaction tag not saved actually)"`
+	Name    string `json:"name" jsonschema_description:"Transaction tag name"`
+}
+
+type mcpAddTransactionTagToolHandler struct{}
+
+var MCPAddTransactionTagToolHandler = &mcpAddTransactionTagToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpAddTransactionTagToolHandler) Name() string {
+	return "add_transaction_tag"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpAddTransactionTagToolHandler) Description() string {
+	return "Add a new transaction tag in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpAddTransactionTagToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddTransactionTagRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpAddTransactionTagToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPAddTransactionTagResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpAddTransactionTagToolHandler) IsReadOnly() bool {
+	return false
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpAddTransactionTagToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var addTagRequest MCPAddTransactionTagRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &addTagRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	name := strings.TrimSpace(addTagRequest.Name)
+
+	if name == "" || utf8.RuneCountInString(name) > mcpMaxNameLength {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	tag := &models.TransactionTag{
+		Uid:  uid,
+		Name: name,
+	}
+
+	if !addTagRequest.DryRun {
+		maxOrderId, err := services.GetTransactionTagService().GetMaxDisplayOrder(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[add_transaction_tag.Handle] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, nil, err
+		}
+
+		tag.DisplayOrder = maxOrderId + 1
+		err = services.GetTransactionTagService().CreateTag(c, tag)
+
+		if err != nil {
+			log.Errorf(c, "[add_transaction_tag.Handle] failed to create tag \"id:%d\" for user \"uid:%d\", because %s", tag.TagId, uid, err.Error())
+			return nil, nil, err
+		}
+
+		log.Infof(c, "[add_transaction_tag.Handle] user \"uid:%d\" has created a new tag \"id:%d\" successfully", uid, tag.TagId)
+	} else {
+		exists, err := services.GetTransactionTagService().ExistsTagName(c, uid, name)
+
+		if err != nil {
+			log.Errorf(c, "[add_transaction_tag.Handle] failed to check whether tag name exists for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, nil, err
+		} else if exists {
+			return nil, nil, errs.ErrTransactionTagNameAlreadyExists
+		}
+	}
+
+	response := MCPAddTransactionTagResponse{
+		Success: true,
+		DryRun:  addTagRequest.DryRun,
+		Name:    tag.Name,
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
@@ -3,6 +3,7 @@ package mcp
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -18,16 +19,26 @@ const transactionTypeTransfer = "transfer"
 
 // MCPAddTransactionRequest represents all parameters of the add transaction request
 type MCPAddTransactionRequest struct {
-	Type                   string   `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction type (income, expense, transfer)"`
-	Time                   string   `json:"time" jsonschema:"format=date-time" jsonschema_description:"Transaction time in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
-	SecondaryCategoryName  string   `json:"category_name" jsonschema_description:"Secondary category name for the transaction"`
-	AccountName            string   `json:"account_name" jsonschema_description:"Account name for the transaction"`
-	Amount                 string   `json:"amount" jsonschema_description:"Transaction amount"`
-	DestinationAccountName string   `json:"destination_account_name,omitempty" jsonschema_description:"Destination account name for transfer transactions (optional)"`
-	DestinationAmount      string   `json:"destination_amount,omitempty" jsonschema_description:"Destination amount for transfer transactions (optional)"`
-	Tags                   []string `json:"tags,omitempty" jsonschema_description:"List of tags associated with the transaction (optional, maximum 10 tags allowed)"`
-	Comment                string   `json:"comment,omitempty" jsonschema_description:"Transaction description"`
-	DryRun                 bool     `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction will not be saved, only validated (optional)"`
+	Type                   string                           `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction type (income, expense, transfer)"`
+	Time                   string                           `json:"time" jsonschema:"format=date-time" jsonschema_description:"Transaction time in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
+	SecondaryCategoryName  string                           `json:"category_name" jsonschema_description:"Secondary category name for the transaction (can be omitted when splits are provided)"`
+	AccountName            string                           `json:"account_name" jsonschema_description:"Account name for the transaction"`
+	Amount                 string                           `json:"amount" jsonschema_description:"Transaction amount (must be equal to the sum of split amounts when splits are provided, can be omitted to use the sum)"`
+	DestinationAccountName string                           `json:"destination_account_name,omitempty" jsonschema_description:"Destination account name for transfer transactions (optional)"`
+	DestinationAmount      string                           `json:"destination_amount,omitempty" jsonschema_description:"Destination amount for transfer transactions (optional)"`
+	Tags                   []string                         `json:"tags,omitempty" jsonschema_description:"List of tags associated with the transaction (optional, maximum 10 tags allowed)"`
+	Comment                string                           `json:"comment,omitempty" jsonschema_description:"Transaction description"`
+	PayeeName              string                           `json:"payee_name,omitempty" jsonschema_description:"Payee (counterparty) name or alias of the transaction, a new payee will be created if not exists (optional)"`
+	Splits                 []*MCPAddTransactionSplitRequest `json:"splits,omitempty" jsonschema_description:"Split lines for splitting an income or expense transaction across multiple categories (optional, at least 2 lines)"`
+	DryRun                 bool                             `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction will not be saved, only validated (optional)"`
+}
+
+// MCPAddTransactionSplitRequest represents all parameters of a split line in the add transaction request
+type MCPAddTransactionSplitRequest struct {
+	SecondaryCategoryName string   `json:"category_name" jsonschema_description:"Secondary category name for the split line"`
+	Amount                string   `json:"amount" jsonschema_description:"Split line amount"`
+	Tags                  []string `json:"tags,omitempty" jsonschema_description:"List of tags associated with the split line (optional, maximum 10 tags allowed)"`
+	Comment               string   `json:"comment,omitempty" jsonschema_description:"Split line description (optional)"`
 }
 
 // MCPAddTransactionResponse represents the response structure for add transaction
@@ -62,6 +73,11 @@ func (h *mcpAddTransactionToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPAddTransactionResponse{})
 }
 
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpAddTransactionToolHandler) IsReadOnly() bool {
+	return false
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var addTransactionRequest MCPAddTransactionRequest
@@ -84,6 +100,26 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		return nil, nil, errs.ErrTransactionHasTooManyTags
 	}
 
+	if len(addTransactionRequest.Splits) > 0 {
+		if addTransactionRequest.Type == transactionTypeTransfer {
+			return nil, nil, errs.ErrTransactionSplitsNotSupported
+		}
+
+		if len(addTransactionRequest.Splits) < models.TransactionSplitMinCount || len(addTransactionRequest.Splits) > models.TransactionSplitMaxCount {
+			return nil, nil, errs.ErrTransactionSplitCountInvalid
+		}
+
+		for i := 0; i < len(addTransactionRequest.Splits); i++ {
+			if len(addTransactionRequest.Splits[i].Tags) > models.MaximumTagsCountOfTransaction {
+				return nil, nil, errs.ErrTransactionHasTooManyTags
+			}
+		}
+
+		if addTransactionRequest.SecondaryCategoryName == "" {
+			addTransactionRequest.SecondaryCategoryName = addTransactionRequest.Splits[0].SecondaryCategoryName
+		}
+	}
+
 	uid := user.Uid
 	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
 
@@ -130,8 +166,9 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 
 	var tagIds []int64
+	var tagMaps map[string]*models.TransactionTag
 
-	if len(addTransactionRequest.Tags) > 0 {
+	if len(addTransactionRequest.Tags) > 0 || len(addTransactionRequest.Splits) > 0 {
 		allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
 
 		if err != nil {
@@ -139,19 +176,67 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 			return nil, nil, err
 		}
 
-		tagMaps := services.GetTransactionTagService().GetTagNameMapByList(allTags)
-		tagIds = make([]int64, 0, len(addTransactionRequest.Tags))
+		tagMaps = services.GetTransactionTagService().GetTagNameMapByList(allTags)
+		tagIds = h.getTagIds(c, uid, addTransactionRequest.Tags, tagMaps)
+	}
+
+	var splits []*models.TransactionSplit
+
+	if len(addTransactionRequest.Splits) > 0 {
+		splits = make([]*models.TransactionSplit, len(addTransactionRequest.Splits))
 
-		for _, tagName := range addTransactionRequest.Tags {
-			if tag, exists := tagMaps[tagName]; exists {
-				tagIds = append(tagIds, tag.TagId)
-			} else {
-				log.Warnf(c, "[add_transaction.Handle] transaction tag \"%s\" not found for user \"uid:%d\"", tagName, uid)
+		for i := 0; i < len(addTransactionRequest.Splits); i++ {
+			splitRequest := addTransactionRequest.Splits[i]
+			splitCategory, exists := categoriesMap[splitRequest.SecondaryCategoryName]
+
+			if !exists {
+				log.Warnf(c, "[add_transaction.Handle] secondary category \"%s\" of split line not found for user \"uid:%d\"", splitRequest.SecondaryCategoryName, uid)
+				return nil, nil, errs.ErrTransactionCategoryNotFound
 			}
+
+			splitAmount, err := utils.ParseAmount(splitRequest.Amount)
+
+			if err != nil {
+				log.Warnf(c, "[add_transaction.Handle] failed to parse amount \"%s\" of split line, because %s", splitRequest.Amount, err.Error())
+				return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+			}
+
+			splits[i] = &models.TransactionSplit{
+				CategoryId: splitCategory.CategoryId,
+				Amount:     splitAmount,
+				Comment:    splitRequest.Comment,
+				TagIds:     strings.Join(utils.Int64ArrayToStringArray(utils.ToUniqueInt64Slice(h.getTagIds(c, uid, splitRequest.Tags, tagMaps))), ","),
+			}
+		}
+
+		if addTransactionRequest.Amount == "" {
+			addTransactionRequest.Amount = utils.FormatAmount(models.GetTransactionSplitsTotalAmount(splits))
 		}
 	}
 
 	transaction := h.createNewTransactionModel(uid, &addTransactionRequest, category.CategoryId, sourceAccount.AccountId, destinationAccountId, c.ClientIP())
+
+	if transaction == nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	transaction.Splits = splits
+
+	if addTransactionRequest.PayeeName != "" && transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		payeeId, err := h.getPayeeId(c, uid, addTransactionRequest.PayeeName, addTransactionRequest.DryRun, services)
+
+		if err != nil {
+			log.Warnf(c, "[add_transaction.Handle] get payee id error, because %s", err.Error())
+			return nil, nil, err
+		}
+
+		transaction.PayeeId = payeeId
+	}
+
+	if err := transaction.ValidateSplits(); err != nil {
+		return nil, nil, err
+	}
+
 	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset)
 
 	if !transactionEditable {
@@ -167,6 +252,7 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 		}
 
 		log.Infof(c, "[add_transaction.Handle] user \"uid:%d\" has created a new transaction \"id:%d\" successfully", uid, transaction.TransactionId)
+		Sessions.NotifyTransactionsUpdated(uid, transaction.TransactionTime)
 
 		accountIds := []int64{sourceAccount.AccountId}
 
@@ -212,6 +298,56 @@ func (h *mcpAddTransactionToolHandler) Handle(c *core.WebContext, callToolReq *M
 	}
 }
 
+func (h *mcpAddTransactionToolHandler) getTagIds(c *core.WebContext, uid int64, tagNames []string, tagMaps map[string]*models.TransactionTag) []int64 {
+	tagIds := make([]int64, 0, len(tagNames))
+
+	for _, tagName := range tagNames {
+		if tag, exists := tagMaps[tagName]; exists {
+			tagIds = append(tagIds, tag.TagId)
+		} else {
+			log.Warnf(c, "[add_transaction.Handle] transaction tag \"%s\" not found for user \"uid:%d\"", tagName, uid)
+		}
+	}
+
+	return tagIds
+}
+
+func (h *mcpAddTransactionToolHandler) getPayeeId(c *core.WebContext, uid int64, payeeName string, dryRun bool, services MCPAvailableServices) (int64, error) {
+	allPayees, err := services.GetPayeeService().GetAllPayeesByUid(c, uid)
+
+	if err != nil {
+		return 0, err
+	}
+
+	payee := services.GetPayeeService().GetPayeeByName(allPayees, payeeName)
+
+	if payee != nil {
+		if payee.Hidden {
+			return 0, errs.ErrCannotUseHiddenPayee
+		}
+
+		return payee.PayeeId, nil
+	}
+
+	if dryRun {
+		return 0, nil
+	}
+
+	payeeNameMap, err := services.GetPayeeService().CreatePayeesByNames(c, uid, []string{payeeName})
+
+	if err != nil {
+		return 0, err
+	}
+
+	payee, exists := payeeNameMap[strings.TrimSpace(payeeName)]
+
+	if !exists {
+		return 0, errs.ErrPayeeNotFound
+	}
+
+	return payee.PayeeId, nil
+}
+
 func (h *mcpAddTransactionToolHandler) createNewTransactionModel(uid int64, addTransactionRequest *MCPAddTransactionRequest, categoryId int64, sourceAccountId int64, destinationAccountId int64, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
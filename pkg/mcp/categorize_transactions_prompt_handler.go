@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+type mcpCategorizeTransactionsPromptHandler struct{}
+
+var MCPCategorizeTransactionsPromptHandler = &mcpCategorizeTransactionsPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpCategorizeTransactionsPromptHandler) Name() string {
+	return "categorize_uncategorized_transactions"
+}
+
+// Title returns the title of the MCP prompt
+func (h *mcpCategorizeTransactionsPromptHandler) Title() string {
+	return "Categorize uncategorized transactions"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpCategorizeTransactionsPromptHandler) Description() string {
+	return "Find the transactions of a month which are in a general or wrong category and move them to a more suitable category in ezBookkeeping."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpCategorizeTransactionsPromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "month",
+			Title:       "Month",
+			Description: "Month of the transactions in yyyy-mm format (e.g. 2023-01) (optional, default is the current month)",
+			Required:    false,
+		},
+	}
+}
+
+// Handle processes the MCP get prompt request and returns the prompt messages
+func (h *mcpCategorizeTransactionsPromptHandler) Handle(c *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) ([]*MCPPromptMessage, error) {
+	now := time.Now()
+	startTime := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	if yearMonth := getPromptReq.Arguments["month"]; yearMonth != "" {
+		year, month, err := utils.ParseNumericYearMonth(yearMonth)
+
+		if err != nil || year < 1 || month < 1 || month > 12 {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		startTime = time.Date(int(year), time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	}
+
+	endTime := startTime.AddDate(0, 1, 0).Add(-time.Second)
+
+	categories, err := MCPTransactionCategoriesResourceHandler.Read(c, MCPTransactionCategoriesResourceHandler.URI(), user, currentConfig, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*MCPPromptMessage{
+		NewMCPUserPromptMessage(NewMCPEmbeddedResource(categories)),
+		NewMCPUserPromptMessage(NewMCPTextContent(fmt.Sprintf("Above are all my transaction categories in ezBookkeeping. "+
+			"Please use the query_transactions tool to list my transactions between %s and %s, "+
+			"and find the transactions which are in a general category (e.g. \"Other\" or \"Miscellaneous\") or whose category obviously does not match the description. "+
+			"For each of them, suggest the most suitable secondary category from the list above and explain why. "+
+			"After I confirm the suggestions, use the modify_transaction tool to update the category of these transactions.",
+			startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))),
+	}, nil
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/validators"
+)
+
+// MCPGetStockQuoteRequest represents all parameters of the get stock quote request
+type MCPGetStockQuoteRequest struct {
+	TickerSymbol string `json:"ticker_symbol" jsonschema_description:"Ticker symbol to get the quote for (e.g. VTI, BTC for bitcoin, XAU for gold)"`
+	AssetType    string `json:"asset_type,omitempty" jsonschema:"enum=stock,enum=etf,enum=mutual_fund,enum=cryptocurrency,enum=precious_metal,enum=bond" jsonschema_description:"Asset type of the ticker symbol (optional, default is stock)"`
+	Currency     string `json:"currency,omitempty" jsonschema_description:"Currency code of the cryptocurrency price (e.g. USD) (optional, default is the default currency of the current user, and the precious metal price only supports USD)"`
+}
+
+// MCPGetStockQuoteResponse represents the response structure for getting stock quote
+type MCPGetStockQuoteResponse struct {
+	TickerSymbol string `json:"ticker_symbol" jsonschema_description:"Ticker symbol of the quote (e.g. VTI)"`
+	CompanyName  string `json:"company_name,omitempty" jsonschema_description:"Company or fund name of the ticker symbol"`
+	Price        string `json:"price" jsonschema_description:"Latest market price per share (or per coin for cryptocurrency, per troy ounce for precious metal)"`
+	Currency     string `json:"currency" jsonschema_description:"Currency code of the price (e.g. USD)"`
+	UpdateTime   string `json:"update_time" jsonschema_description:"Last update time of the price in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
+}
+
+type mcpGetStockQuoteToolHandler struct{}
+
+var MCPGetStockQuoteToolHandler = &mcpGetStockQuoteToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpGetStockQuoteToolHandler) Name() string {
+	return "get_stock_quote"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpGetStockQuoteToolHandler) Description() string {
+	return "Get the latest market price of the specified ticker symbol."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpGetStockQuoteToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPGetStockQuoteRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpGetStockQuoteToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPGetStockQuoteResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpGetStockQuoteToolHandler) IsReadOnly() bool {
+	return true
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpGetStockQuoteToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var getStockQuoteRequest MCPGetStockQuoteRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &getStockQuoteRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	tickerSymbol := strings.ToUpper(strings.TrimSpace(getStockQuoteRequest.TickerSymbol))
+
+	if tickerSymbol == "" {
+		return nil, nil, errs.ErrSymbolIsRequired
+	}
+
+	assetType, err := getInvestmentAssetTypeByName(getStockQuoteRequest.AssetType)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currency := strings.ToUpper(strings.TrimSpace(getStockQuoteRequest.Currency))
+
+	if currency == "" && assetType == models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL {
+		currency = models.PreciousMetalPriceCurrency
+	} else if currency == "" {
+		currency = user.DefaultCurrency
+	}
+
+	if _, exists := validators.AllCurrencyNames[currency]; !exists {
+		return nil, nil, errs.ErrInvestmentCurrencyInvalid
+	}
+
+	stockPrice, err := services.GetStockPriceService().GetAssetPrice(c, assetType, tickerSymbol, currency)
+
+	if err != nil {
+		log.Warnf(c, "[get_stock_quote.Handle] failed to get stock price of \"%s\", because %s", tickerSymbol, err.Error())
+		return nil, nil, errs.Or(err, errs.ErrStockQuoteFetchFailed)
+	}
+
+	if stockPrice == nil || stockPrice.CurrentPrice <= 0 {
+		return nil, nil, errs.ErrStockQuoteNotFound
+	}
+
+	response := MCPGetStockQuoteResponse{
+		TickerSymbol: tickerSymbol,
+		CompanyName:  stockPrice.CompanyName,
+		Price:        formatInvestmentPrice(stockPrice.CurrentPrice, stockPrice.PricePrecision),
+		Currency:     stockPrice.Currency,
+		UpdateTime:   utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(stockPrice.LastUpdatedTime, time.UTC),
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
@@ -14,8 +14,12 @@ type MCPAvailableServices interface {
 	GetTransactionService() *services.TransactionService
 	GetTransactionCategoryService() *services.TransactionCategoryService
 	GetTransactionTagService() *services.TransactionTagService
+	GetPayeeService() *services.PayeeService
 	GetAccountService() *services.AccountService
 	GetUserService() *services.UserService
+	GetInvestmentService() *services.InvestmentService
+	GetStockPriceService() *services.StockPriceService
+	GetExchangeRatesHistoryService() *services.ExchangeRatesHistoryService
 }
 
 // MCPToolHandler defines the MCP tool handler
@@ -32,6 +36,63 @@ type MCPToolHandler[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPRe
 	// OutputType returns the output type for the MCP tool response
 	OutputType() reflect.Type
 
+	// IsReadOnly returns whether the MCP tool only reads data without any modification
+	IsReadOnly() bool
+
 	// Handle processes the MCP call tool request and returns the response
 	Handle(*core.WebContext, *MCPCallToolRequest, *models.User, *settings.Config, MCPAvailableServices) (any, []*T, error)
 }
+
+// MCPResourceHandler defines the MCP resource handler
+type MCPResourceHandler interface {
+	// URI returns the uri of the MCP resource
+	URI() string
+
+	// Name returns the name of the MCP resource
+	Name() string
+
+	// Description returns the description of the MCP resource
+	Description() string
+
+	// MimeType returns the mime type of the MCP resource contents
+	MimeType() string
+
+	// Read returns the contents of the MCP resource
+	Read(*core.WebContext, string, *models.User, *settings.Config, MCPAvailableServices) (*MCPTextResourceContents, error)
+}
+
+// MCPResourceTemplateHandler defines the MCP resource template handler
+type MCPResourceTemplateHandler interface {
+	// URITemplate returns the uri template of the MCP resource
+	URITemplate() string
+
+	// Name returns the name of the MCP resource template
+	Name() string
+
+	// Description returns the description of the MCP resource template
+	Description() string
+
+	// MimeType returns the mime type of the MCP resource contents
+	MimeType() string
+
+	// Read returns the contents of the MCP resource, the second parameter is the variable part of the uri
+	Read(*core.WebContext, string, string, *models.User, *settings.Config, MCPAvailableServices) (*MCPTextResourceContents, error)
+}
+
+// MCPPromptHandler defines the MCP prompt handler
+type MCPPromptHandler interface {
+	// Name returns the name of the MCP prompt
+	Name() string
+
+	// Title returns the title of the MCP prompt
+	Title() string
+
+	// Description returns the description of the MCP prompt
+	Description() string
+
+	// Arguments returns the arguments of the MCP prompt
+	Arguments() []*MCPPromptArgument
+
+	// Handle processes the MCP get prompt request and returns the prompt messages
+	Handle(*core.WebContext, *MCPGetPromptRequest, *models.User, *settings.Config, MCPAvailableServices) ([]*MCPPromptMessage, error)
+}
@@ -1,6 +1,8 @@
 package mcp
 
 import (
+	"strings"
+
 	"github.com/invopop/jsonschema"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
@@ -18,8 +20,15 @@ type MCPContainer struct {
 	mcpResourceLinkTools     *orderedmap.OrderedMap[string, MCPToolHandler[MCPResourceLink]]
 	mcpEmbeddedResourceTools *orderedmap.OrderedMap[string, MCPToolHandler[MCPEmbeddedResource]]
 	mcpTools                 []*MCPTool
+	mcpReadOnlyToolNames     map[string]bool
+	mcpTransactionEditTools  map[string]bool
+	mcpResources             *orderedmap.OrderedMap[string, MCPResourceHandler]
+	mcpResourceTemplates     *orderedmap.OrderedMap[string, MCPResourceTemplateHandler]
+	mcpPrompts               *orderedmap.OrderedMap[string, MCPPromptHandler]
 }
 
+const mcpJSONResourceMimeType = "application/json"
+
 // Initialize a mcp handler container singleton instance
 var (
 	Container = &MCPContainer{}
@@ -34,6 +43,135 @@ func (c *MCPContainer) GetMCPTools() []*MCPTool {
 	return c.mcpTools
 }
 
+// IsReadOnlyTool returns whether the specified MCP tool only reads data
+func (c *MCPContainer) IsReadOnlyTool(name string) bool {
+	return c.mcpReadOnlyToolNames[name]
+}
+
+// IsToolAvailable returns whether the specified MCP tool is available for the user and the token scope
+func (c *MCPContainer) IsToolAvailable(name string, user *models.User, tokenScope core.TokenScope) bool {
+	if tokenScope == core.USER_TOKEN_SCOPE_READ_ONLY && !c.mcpReadOnlyToolNames[name] {
+		return false
+	}
+
+	if user.TransactionEditScope == models.TRANSACTION_EDIT_SCOPE_NONE && c.mcpTransactionEditTools[name] {
+		return false
+	}
+
+	return true
+}
+
+// GetMCPResources returns the registered MCP resources
+func (c *MCPContainer) GetMCPResources() []*MCPResource {
+	resources := make([]*MCPResource, 0, c.mcpResources.Len())
+
+	for pair := c.mcpResources.Oldest(); pair != nil; pair = pair.Next() {
+		resources = append(resources, &MCPResource{
+			URI:         pair.Value.URI(),
+			Name:        pair.Value.Name(),
+			MimeType:    pair.Value.MimeType(),
+			Description: pair.Value.Description(),
+		})
+	}
+
+	return resources
+}
+
+// GetMCPResourceTemplates returns the registered MCP resource templates
+func (c *MCPContainer) GetMCPResourceTemplates() []*MCPResourceTemplate {
+	resourceTemplates := make([]*MCPResourceTemplate, 0, c.mcpResourceTemplates.Len())
+
+	for pair := c.mcpResourceTemplates.Oldest(); pair != nil; pair = pair.Next() {
+		resourceTemplates = append(resourceTemplates, &MCPResourceTemplate{
+			URITemplate: pair.Value.URITemplate(),
+			Name:        pair.Value.Name(),
+			MimeType:    pair.Value.MimeType(),
+			Description: pair.Value.Description(),
+		})
+	}
+
+	return resourceTemplates
+}
+
+// GetMCPPrompts returns the registered MCP prompts
+func (c *MCPContainer) GetMCPPrompts() []*MCPPrompt {
+	prompts := make([]*MCPPrompt, 0, c.mcpPrompts.Len())
+
+	for pair := c.mcpPrompts.Oldest(); pair != nil; pair = pair.Next() {
+		prompts = append(prompts, &MCPPrompt{
+			Name:        pair.Value.Name(),
+			Title:       pair.Value.Title(),
+			Description: pair.Value.Description(),
+			Arguments:   pair.Value.Arguments(),
+		})
+	}
+
+	return prompts
+}
+
+// IsResourceExists returns whether the resource uri matches any registered MCP resource or resource template
+func (c *MCPContainer) IsResourceExists(uri string) bool {
+	if _, exists := c.mcpResources.Get(uri); exists {
+		return true
+	}
+
+	handler, _ := c.getMCPResourceTemplateHandler(uri)
+
+	return handler != nil
+}
+
+// ReadResource returns the contents of the MCP resource based on the resource uri
+func (c *MCPContainer) ReadResource(ctx *core.WebContext, uri string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
+	var contents *MCPTextResourceContents
+	var err error
+
+	if handler, exists := c.mcpResources.Get(uri); exists {
+		contents, err = handler.Read(ctx, uri, user, currentConfig, services)
+	} else if handler, variable := c.getMCPResourceTemplateHandler(uri); handler != nil {
+		contents, err = handler.Read(ctx, uri, variable, user, currentConfig, services)
+	} else {
+		return nil, errs.ErrMCPResourceNotFound
+	}
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	readResourceResp := MCPReadResourceResponse[MCPTextResourceContents]{
+		Contents: []*MCPTextResourceContents{contents},
+	}
+
+	return readResourceResp, nil
+}
+
+// GetPrompt returns the messages of the MCP prompt handler based on the prompt name
+func (c *MCPContainer) GetPrompt(ctx *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
+	handler, exists := c.mcpPrompts.Get(getPromptReq.Name)
+
+	if !exists {
+		return nil, errs.ErrMCPPromptNotFound
+	}
+
+	for _, argument := range handler.Arguments() {
+		if argument.Required && getPromptReq.Arguments[argument.Name] == "" {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+	}
+
+	messages, err := handler.Handle(ctx, getPromptReq, user, currentConfig, services)
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	getPromptResp := MCPGetPromptResponse{
+		Description: handler.Description(),
+		Messages:    messages,
+	}
+
+	return getPromptResp, nil
+}
+
 // HandleTool returns the result of the MCP tool handler based on the tool name
 func (c *MCPContainer) HandleTool(ctx *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, error) {
 	if handler, exists := c.mcpTextContentTools.Get(callToolReq.Name); exists {
@@ -68,14 +206,43 @@ func InitializeMCPHandlers(config *settings.Config) error {
 		mcpResourceLinkTools:     orderedmap.New[string, MCPToolHandler[MCPResourceLink]](),
 		mcpEmbeddedResourceTools: orderedmap.New[string, MCPToolHandler[MCPEmbeddedResource]](),
 		mcpTools:                 make([]*MCPTool, 0),
+		mcpReadOnlyToolNames:     make(map[string]bool),
+		mcpTransactionEditTools: map[string]bool{
+			MCPAddTransactionToolHandler.Name():    true,
+			MCPModifyTransactionToolHandler.Name(): true,
+			MCPDeleteTransactionToolHandler.Name(): true,
+		},
+		mcpResources:         orderedmap.New[string, MCPResourceHandler](),
+		mcpResourceTemplates: orderedmap.New[string, MCPResourceTemplateHandler](),
+		mcpPrompts:           orderedmap.New[string, MCPPromptHandler](),
 	}
 
 	registerMCPTextContentToolHandler(container, MCPAddTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPModifyTransactionToolHandler)
+	registerMCPTextContentToolHandler(container, MCPDeleteTransactionToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryTransactionsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllAccountsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllTransactionCategoriesToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryAllTransactionTagsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPAddAccountToolHandler)
+	registerMCPTextContentToolHandler(container, MCPAddTransactionCategoryToolHandler)
+	registerMCPTextContentToolHandler(container, MCPAddTransactionTagToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryTransactionStatisticsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryTransactionTrendsToolHandler)
 	registerMCPTextContentToolHandler(container, MCPQueryLatestExchangeRatesToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryInvestmentsToolHandler)
+	registerMCPTextContentToolHandler(container, MCPQueryPortfolioSummaryToolHandler)
+	registerMCPTextContentToolHandler(container, MCPGetStockQuoteToolHandler)
+	registerMCPTextContentToolHandler(container, MCPAddInvestmentTransactionToolHandler)
+
+	registerMCPResourceHandler(container, MCPAccountsResourceHandler)
+	registerMCPResourceHandler(container, MCPTransactionCategoriesResourceHandler)
+	registerMCPResourceHandler(container, MCPPortfolioResourceHandler)
+	registerMCPResourceTemplateHandler(container, MCPMonthlySummaryResourceHandler)
+
+	registerMCPPromptHandler(container, MCPMonthlySpendingReviewPromptHandler)
+	registerMCPPromptHandler(container, MCPCategorizeTransactionsPromptHandler)
+	registerMCPPromptHandler(container, MCPPortfolioCheckupPromptHandler)
 
 	Container = container
 	return nil
@@ -108,6 +275,73 @@ func registerMCPToolHandler[T MCPTextContent | MCPImageContent | MCPAudioContent
 
 	mcpToolHandlerMap.Set(handler.Name(), handler)
 	c.mcpTools = append(c.mcpTools, createNewMCPToolInfo(handler.Name(), handler))
+
+	if handler.IsReadOnly() {
+		c.mcpReadOnlyToolNames[handler.Name()] = true
+	}
+}
+
+func registerMCPResourceHandler(c *MCPContainer, handler MCPResourceHandler) {
+	if _, exists := c.mcpResources.Get(handler.URI()); exists {
+		return
+	}
+
+	c.mcpResources.Set(handler.URI(), handler)
+}
+
+func registerMCPResourceTemplateHandler(c *MCPContainer, handler MCPResourceTemplateHandler) {
+	if _, exists := c.mcpResourceTemplates.Get(handler.URITemplate()); exists {
+		return
+	}
+
+	c.mcpResourceTemplates.Set(handler.URITemplate(), handler)
+}
+
+func registerMCPPromptHandler(c *MCPContainer, handler MCPPromptHandler) {
+	if _, exists := c.mcpPrompts.Get(handler.Name()); exists {
+		return
+	}
+
+	c.mcpPrompts.Set(handler.Name(), handler)
+}
+
+// getMCPResourceTemplateHandler returns the resource template handler matching the uri and the variable part of the uri
+func (c *MCPContainer) getMCPResourceTemplateHandler(uri string) (MCPResourceTemplateHandler, string) {
+	for pair := c.mcpResourceTemplates.Oldest(); pair != nil; pair = pair.Next() {
+		uriTemplate := pair.Key
+		variableStartIndex := strings.Index(uriTemplate, "{")
+
+		if variableStartIndex < 0 || !strings.HasPrefix(uri, uriTemplate[:variableStartIndex]) {
+			continue
+		}
+
+		variable := uri[variableStartIndex:]
+
+		if variable == "" || strings.Contains(variable, "/") {
+			continue
+		}
+
+		return pair.Value, variable
+	}
+
+	return nil, ""
+}
+
+// readMCPResourceFromTool returns the resource contents which are the text result of the specified read-only tool
+func readMCPResourceFromTool(ctx *core.WebContext, uri string, mimeType string, handler MCPToolHandler[MCPTextContent], arguments []byte, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	_, result, err := handler.Handle(ctx, &MCPCallToolRequest{Name: handler.Name(), Arguments: arguments}, user, currentConfig, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := ""
+
+	if len(result) > 0 {
+		text = result[0].Text
+	}
+
+	return NewMCPTextResourceContents(uri, text, mimeType), nil
 }
 
 func handleTool[T MCPTextContent | MCPImageContent | MCPAudioContent | MCPResourceLink | MCPEmbeddedResource](ctx *core.WebContext, handler MCPToolHandler[T], currentConfig *settings.Config, services MCPAvailableServices, callToolReq *MCPCallToolRequest, user *models.User) (any, error) {
@@ -133,6 +367,9 @@ func createNewMCPToolInfo[T MCPTextContent | MCPImageContent | MCPAudioContent |
 	mcpTool := &MCPTool{
 		Name:        name,
 		Description: handler.Description(),
+		Annotations: &MCPToolAnnotations{
+			ReadOnlyHint: handler.IsReadOnly(),
+		},
 	}
 
 	schemeGenerator := jsonschema.Reflector{
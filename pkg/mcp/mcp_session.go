@@ -0,0 +1,340 @@
+package mcp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mcpSessionIdLength = 32
+const mcpSessionMaxIdleDuration = 24 * time.Hour
+const mcpSessionMaxCountPerUserToken = 16
+const mcpSessionNotificationQueueSize = 64
+
+// MCP notification methods
+const (
+	MCPNotificationMethodResourceUpdated     = "notifications/resources/updated"
+	MCPNotificationMethodResourceListChanged = "notifications/resources/list_changed"
+	MCPNotificationMethodToolListChanged     = "notifications/tools/list_changed"
+)
+
+// MCPSession represents a model context protocol session of streamable http transport
+type MCPSession struct {
+	Id                  string
+	Uid                 int64
+	UserTokenId         string
+	ProtocolVersion     string
+	lastActiveTime      time.Time
+	subscribedResources map[string]bool
+	notifications       chan *MCPNotification
+	mutex               sync.Mutex
+}
+
+// MCPSessionManager contains the all active model context protocol sessions
+type MCPSessionManager struct {
+	sessions     map[string]*MCPSession
+	currentMonth string
+	mutex        sync.RWMutex
+}
+
+// Initialize a mcp session manager singleton instance
+var (
+	Sessions = &MCPSessionManager{
+		sessions:     make(map[string]*MCPSession),
+		currentMonth: time.Now().Format("2006-01"),
+	}
+)
+
+// CreateSession creates a new session for the specified user token and returns it
+func (m *MCPSessionManager) CreateSession(uid int64, userTokenId string, protocolVersion string) (*MCPSession, error) {
+	sessionId, err := utils.GetRandomNumberOrLetter(mcpSessionIdLength)
+
+	if err != nil {
+		return nil, err
+	}
+
+	session := &MCPSession{
+		Id:                  sessionId,
+		Uid:                 uid,
+		UserTokenId:         userTokenId,
+		ProtocolVersion:     protocolVersion,
+		lastActiveTime:      time.Now(),
+		subscribedResources: make(map[string]bool),
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.removeIdleSessions()
+
+	if !m.ensureUserTokenSessionCountBelowLimit(userTokenId) {
+		return nil, errs.ErrMCPSessionCountLimitReached
+	}
+
+	m.sessions[sessionId] = session
+
+	return session, nil
+}
+
+// GetSession returns the session of the specified session id, or nil if the session does not exist or does not belong to the user token
+func (m *MCPSessionManager) GetSession(sessionId string, uid int64, userTokenId string) *MCPSession {
+	m.mutex.RLock()
+	session, exists := m.sessions[sessionId]
+	m.mutex.RUnlock()
+
+	if !exists || session.Uid != uid || session.UserTokenId != userTokenId {
+		return nil
+	}
+
+	session.mutex.Lock()
+	session.lastActiveTime = time.Now()
+	session.mutex.Unlock()
+
+	return session
+}
+
+// RemoveSession removes the specified session and closes its notification stream
+func (m *MCPSessionManager) RemoveSession(session *MCPSession) {
+	m.mutex.Lock()
+	delete(m.sessions, session.Id)
+	m.mutex.Unlock()
+
+	session.CloseNotificationStream()
+}
+
+// RemoveIdleSessions removes the sessions which are idle for a long time without opened stream
+func (m *MCPSessionManager) RemoveIdleSessions() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.removeIdleSessions()
+}
+
+// NotifyCurrentMonthResourceChanged sends the resource list changed notification to all sessions if the current month has changed,
+// because the monthly summary resource of the current month is in the resource list
+func (m *MCPSessionManager) NotifyCurrentMonthResourceChanged() bool {
+	currentMonth := time.Now().Format("2006-01")
+
+	m.mutex.Lock()
+	lastMonth := m.currentMonth
+	m.currentMonth = currentMonth
+	sessions := make([]*MCPSession, 0, len(m.sessions))
+
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+
+	m.mutex.Unlock()
+
+	if lastMonth == currentMonth {
+		return false
+	}
+
+	notification := &MCPNotification{
+		JSONRPC: core.JSONRPCVersion,
+		Method:  MCPNotificationMethodResourceListChanged,
+	}
+
+	for _, session := range sessions {
+		session.SendNotification(notification)
+	}
+
+	return true
+}
+
+// NotifyResourceUpdated sends the resource updated notification to all sessions of the user which subscribe the resource
+func (m *MCPSessionManager) NotifyResourceUpdated(uid int64, uri string) {
+	notification := &MCPNotification{
+		JSONRPC: core.JSONRPCVersion,
+		Method:  MCPNotificationMethodResourceUpdated,
+		Params: &MCPResourceUpdatedNotificationParams{
+			URI: uri,
+		},
+	}
+
+	for _, session := range m.getUserSessions(uid) {
+		if session.IsResourceSubscribed(uri) {
+			session.SendNotification(notification)
+		}
+	}
+}
+
+// NotifyTransactionsUpdated sends the resource updated notification of the monthly summaries which contain the specified transaction times
+func (m *MCPSessionManager) NotifyTransactionsUpdated(uid int64, transactionTimes ...int64) {
+	notifiedYearMonths := make(map[string]bool, len(transactionTimes))
+
+	for _, transactionTime := range transactionTimes {
+		// there is no client timezone in mcp requests, so the monthly summary is in the server timezone
+		yearMonth := utils.FormatUnixTimeToYearMonth(utils.GetUnixTimeFromTransactionTime(transactionTime), time.Local)
+
+		if notifiedYearMonths[yearMonth] {
+			continue
+		}
+
+		notifiedYearMonths[yearMonth] = true
+		m.NotifyResourceUpdated(uid, MCPMonthlySummaryResourceHandler.GetMonthlySummaryResourceURI(yearMonth))
+	}
+}
+
+// NotifyResourceListChanged sends the resource list changed notification to all sessions of the user
+func (m *MCPSessionManager) NotifyResourceListChanged(uid int64) {
+	m.notifyAllUserSessions(uid, MCPNotificationMethodResourceListChanged)
+}
+
+// NotifyToolListChanged sends the tool list changed notification to all sessions of the user
+func (m *MCPSessionManager) NotifyToolListChanged(uid int64) {
+	m.notifyAllUserSessions(uid, MCPNotificationMethodToolListChanged)
+}
+
+// SubscribeResource adds the resource to the subscribed resources of this session
+func (s *MCPSession) SubscribeResource(uri string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.subscribedResources[uri] = true
+}
+
+// UnsubscribeResource removes the resource from the subscribed resources of this session
+func (s *MCPSession) UnsubscribeResource(uri string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.subscribedResources, uri)
+}
+
+// IsResourceSubscribed returns whether the resource is subscribed in this session
+func (s *MCPSession) IsResourceSubscribed(uri string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.subscribedResources[uri]
+}
+
+// OpenNotificationStream returns a new notification channel of this session, only one stream can be opened at the same time
+func (s *MCPSession) OpenNotificationStream() (<-chan *MCPNotification, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.notifications != nil {
+		return nil, errs.ErrMCPSessionStreamAlreadyOpened
+	}
+
+	s.notifications = make(chan *MCPNotification, mcpSessionNotificationQueueSize)
+	s.lastActiveTime = time.Now()
+
+	return s.notifications, nil
+}
+
+// CloseNotificationStream closes the notification channel of this session if it is opened
+func (s *MCPSession) CloseNotificationStream() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.notifications != nil {
+		close(s.notifications)
+		s.notifications = nil
+	}
+}
+
+// SendNotification sends the notification to the notification stream of this session, the notification is dropped if the stream is not opened or is full
+func (s *MCPSession) SendNotification(notification *MCPNotification) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.notifications == nil {
+		return false
+	}
+
+	select {
+	case s.notifications <- notification:
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *MCPSessionManager) notifyAllUserSessions(uid int64, method string) {
+	notification := &MCPNotification{
+		JSONRPC: core.JSONRPCVersion,
+		Method:  method,
+	}
+
+	for _, session := range m.getUserSessions(uid) {
+		session.SendNotification(notification)
+	}
+}
+
+func (m *MCPSessionManager) getUserSessions(uid int64) []*MCPSession {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	sessions := make([]*MCPSession, 0)
+
+	for _, session := range m.sessions {
+		if session.Uid == uid {
+			sessions = append(sessions, session)
+		}
+	}
+
+	return sessions
+}
+
+// removeIdleSessions removes the sessions which are idle for a long time without opened stream, the caller must hold the lock
+func (m *MCPSessionManager) removeIdleSessions() int {
+	now := time.Now()
+	removedCount := 0
+
+	for sessionId, session := range m.sessions {
+		session.mutex.Lock()
+		idle := session.notifications == nil && now.Sub(session.lastActiveTime) > mcpSessionMaxIdleDuration
+		session.mutex.Unlock()
+
+		if idle {
+			delete(m.sessions, sessionId)
+			removedCount++
+		}
+	}
+
+	return removedCount
+}
+
+// ensureUserTokenSessionCountBelowLimit removes the least recently active session without opened stream of the user token if the session count reaches the limit,
+// returns false if all the sessions of the user token have opened streams, the caller must hold the lock
+func (m *MCPSessionManager) ensureUserTokenSessionCountBelowLimit(userTokenId string) bool {
+	sessionCount := 0
+	var leastRecentlyActiveSession *MCPSession
+	var leastRecentlyActiveTime time.Time
+
+	for _, session := range m.sessions {
+		if session.UserTokenId != userTokenId {
+			continue
+		}
+
+		sessionCount++
+
+		session.mutex.Lock()
+		streamOpened := session.notifications != nil
+		lastActiveTime := session.lastActiveTime
+		session.mutex.Unlock()
+
+		if !streamOpened && (leastRecentlyActiveSession == nil || lastActiveTime.Before(leastRecentlyActiveTime)) {
+			leastRecentlyActiveSession = session
+			leastRecentlyActiveTime = lastActiveTime
+		}
+	}
+
+	if sessionCount < mcpSessionMaxCountPerUserToken {
+		return true
+	}
+
+	if leastRecentlyActiveSession == nil {
+		return false
+	}
+
+	delete(m.sessions, leastRecentlyActiveSession.Id)
+
+	return true
+}
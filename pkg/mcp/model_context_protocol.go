@@ -23,9 +23,15 @@ const LatestSupportedMCPVersion = MCPProtocolVersion20250618
 // ToolResultStructuredContentMinVersion defines the minimum version of structured content supported in tool results
 const ToolResultStructuredContentMinVersion = MCPProtocolVersion20250618
 
+// ToolAnnotationsMinVersion defines the minimum version of annotations supported in tools
+const ToolAnnotationsMinVersion = MCPProtocolVersion20250326
+
 // MCPProtocolVersionHeaderName defines the HTTP header name for the MCP protocol version
 const MCPProtocolVersionHeaderName = "MCP-Protocol-Version"
 
+// MCPSessionIdHeaderName defines the HTTP header name for the MCP session id
+const MCPSessionIdHeaderName = "Mcp-Session-Id"
+
 // SupportedMCPVersion defines a map of supported MCP versions
 var SupportedMCPVersion = map[MCPProtocolVersion]bool{
 	MCPProtocolVersion20250618: true,
@@ -92,11 +98,31 @@ type MCPResource struct {
 	Description string `json:"description,omitempty"`
 }
 
+// MCPListResourceTemplatesResponse defines the response structure for listing resource templates in the MCP
+type MCPListResourceTemplatesResponse struct {
+	ResourceTemplates []*MCPResourceTemplate `json:"resourceTemplates"`
+	NextCursor        string                 `json:"nextCursor,omitempty"`
+}
+
+// MCPResourceTemplate defines the structure of a resource template in the MCP
+type MCPResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	MimeType    string `json:"mimeType,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
 // MCPReadResourceRequest defines the request structure for reading a resource in the MCP
 type MCPReadResourceRequest struct {
 	URI string `json:"uri"`
 }
 
+// MCPSubscribeResourceRequest defines the request structure for subscribing or unsubscribing a resource in the MCP
+type MCPSubscribeResourceRequest struct {
+	URI string `json:"uri"`
+}
+
 // MCPReadResourceResponse defines the response structure for reading a resource in the MCP
 type MCPReadResourceResponse[T MCPTextResourceContents | MCPBlobResourceContents] struct {
 	Contents []*T `json:"contents"`
@@ -124,11 +150,17 @@ type MCPListToolsResponse struct {
 
 // MCPTool defines the structure of a tool in the MCP
 type MCPTool struct {
-	Name         string             `json:"name"`
-	InputSchema  *jsonschema.Schema `json:"inputSchema"`
-	OutputSchema *jsonschema.Schema `json:"outputSchema,omitempty"`
-	Title        string             `json:"title,omitempty"`
-	Description  string             `json:"description,omitempty"`
+	Name         string              `json:"name"`
+	InputSchema  *jsonschema.Schema  `json:"inputSchema"`
+	OutputSchema *jsonschema.Schema  `json:"outputSchema,omitempty"`
+	Title        string              `json:"title,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	Annotations  *MCPToolAnnotations `json:"annotations,omitempty"`
+}
+
+// MCPToolAnnotations defines the additional hints of a tool in the MCP
+type MCPToolAnnotations struct {
+	ReadOnlyHint bool `json:"readOnlyHint"`
 }
 
 // MCPCallToolRequest defines the request structure for listing tools in the MCP
@@ -144,6 +176,58 @@ type MCPCallToolResponse[T MCPTextContent | MCPImageContent | MCPAudioContent |
 	IsError           bool `json:"isError,omitempty"`
 }
 
+// MCPListPromptsResponse defines the response structure for listing prompts in the MCP
+type MCPListPromptsResponse struct {
+	Prompts    []*MCPPrompt `json:"prompts"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// MCPPrompt defines the structure of a prompt in the MCP
+type MCPPrompt struct {
+	Name        string               `json:"name"`
+	Title       string               `json:"title,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Arguments   []*MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+// MCPPromptArgument defines the structure of a prompt argument in the MCP
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPGetPromptRequest defines the request structure for getting a prompt in the MCP
+type MCPGetPromptRequest struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// MCPGetPromptResponse defines the response structure for getting a prompt in the MCP
+type MCPGetPromptResponse struct {
+	Description string              `json:"description,omitempty"`
+	Messages    []*MCPPromptMessage `json:"messages"`
+}
+
+// MCPPromptMessage defines the structure of a prompt message in the MCP
+type MCPPromptMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+// MCPNotification defines the structure of a notification sent from the server in the MCP
+type MCPNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// MCPResourceUpdatedNotificationParams defines the parameters structure of the resource updated notification in the MCP
+type MCPResourceUpdatedNotificationParams struct {
+	URI string `json:"uri"`
+}
+
 // MCPTextContent defines the text content structure used in MCP
 type MCPTextContent struct {
 	Type string `json:"type"`
@@ -223,3 +307,20 @@ func NewMCPEmbeddedResource[T MCPTextResourceContents | MCPBlobResourceContents]
 		Resource: resource,
 	}
 }
+
+// NewMCPUserPromptMessage creates a new instance of MCPPromptMessage sent by user with the given content
+func NewMCPUserPromptMessage(content any) *MCPPromptMessage {
+	return &MCPPromptMessage{
+		Role:    "user",
+		Content: content,
+	}
+}
+
+// NewMCPTextResourceContents creates a new instance of MCPTextResourceContents with the given uri, text and MIME type
+func NewMCPTextResourceContents(uri string, text string, mimeType string) *MCPTextResourceContents {
+	return &MCPTextResourceContents{
+		URI:      uri,
+		Text:     text,
+		MimeType: mimeType,
+	}
+}
@@ -0,0 +1,362 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPModifyTransactionRequest represents all parameters of the modify transaction request
+type MCPModifyTransactionRequest struct {
+	Id                     string   `json:"id" jsonschema_description:"Transaction ID returned by the query transactions tool"`
+	Time                   string   `json:"time,omitempty" jsonschema:"format=date-time" jsonschema_description:"New transaction time in RFC 3339 format (e.g. 2023-01-01T12:00:00Z) (optional, leave empty to keep unchanged)"`
+	SecondaryCategoryName  string   `json:"category_name,omitempty" jsonschema_description:"New secondary category name for the transaction (optional, leave empty to keep unchanged)"`
+	AccountName            string   `json:"account_name,omitempty" jsonschema_description:"New account name for the transaction (optional, leave empty to keep unchanged)"`
+	Amount                 string   `json:"amount,omitempty" jsonschema_description:"New transaction amount (optional, leave empty to keep unchanged)"`
+	DestinationAccountName string   `json:"destination_account_name,omitempty" jsonschema_description:"New destination account name for transfer transactions (optional, leave empty to keep unchanged)"`
+	DestinationAmount      string   `json:"destination_amount,omitempty" jsonschema_description:"New destination amount for transfer transactions (optional, leave empty to keep unchanged)"`
+	Tags                   []string `json:"tags,omitempty" jsonschema_description:"New list of tags associated with the transaction, which replaces all existing tags (optional, omit to keep unchanged, set to empty list to remove all tags, maximum 10 tags allowed)"`
+	Comment                *string  `json:"comment,omitempty" jsonschema_description:"New transaction description (optional, omit to keep unchanged)"`
+	DryRun                 bool     `json:"dry_run,omitempty" jsonschema_description:"If true, the transaction will not be saved, only validated (optional)"`
+}
+
+// MCPModifyTransactionResponse represents the response structure for modify transaction
+type MCPModifyTransactionResponse struct {
+	Success     bool                `json:"success" jsonschema_description:"Indicates whether the transaction was modified successfully"`
+	DryRun      bool                `json:"dry_run,omitempty" jsonschema_description:"Indicates whether this is a dry run (transaction not saved actually)"`
+	Transaction *MCPTransactionInfo `json:"transaction" jsonschema_description:"Transaction information after modification"`
+}
+
+type mcpModifyTransactionToolHandler struct{}
+
+var MCPModifyTransactionToolHandler = &mcpModifyTransactionToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpModifyTransactionToolHandler) Name() string {
+	return "modify_transaction"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpModifyTransactionToolHandler) Description() string {
+	return "Modify an existing transaction in ezBookkeeping, only the specified fields will be changed."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpModifyTransactionToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPModifyTransactionRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpModifyTransactionToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPModifyTransactionResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpModifyTransactionToolHandler) IsReadOnly() bool {
+	return false
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpModifyTransactionToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var modifyTransactionRequest MCPModifyTransactionRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &modifyTransactionRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	transactionId, err := utils.StringToInt64(modifyTransactionRequest.Id)
+
+	if err != nil || transactionId <= 0 {
+		return nil, nil, errs.ErrTransactionIdInvalid
+	}
+
+	if len(modifyTransactionRequest.Tags) > models.MaximumTagsCountOfTransaction {
+		return nil, nil, errs.ErrTransactionHasTooManyTags
+	}
+
+	uid := user.Uid
+	transaction, err := services.GetTransactionService().GetTransactionByTransactionId(c, uid, transactionId)
+
+	if err != nil {
+		log.Errorf(c, "[modify_transaction.Handle] failed to get transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+		return nil, nil, err
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		log.Warnf(c, "[modify_transaction.Handle] cannot modify transaction \"id:%d\" for user \"uid:%d\", because transaction type is transfer in", transactionId, uid)
+		return nil, nil, errs.ErrTransactionTypeInvalid
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT && (modifyTransactionRequest.DestinationAccountName != "" || modifyTransactionRequest.DestinationAmount != "") {
+		log.Warnf(c, "[modify_transaction.Handle] cannot set destination account or amount for non-transfer transaction \"id:%d\"", transactionId)
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && modifyTransactionRequest.SecondaryCategoryName != "" {
+		log.Warnf(c, "[modify_transaction.Handle] balance modification transaction cannot set category")
+		return nil, nil, errs.ErrBalanceModificationTransactionCannotSetCategory
+	}
+
+	if transaction.HasSplits && modifyTransactionRequest.SecondaryCategoryName != "" {
+		log.Warnf(c, "[modify_transaction.Handle] cannot set category for split transaction \"id:%d\"", transactionId)
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	err = services.GetTransactionService().FillTransactionSplits(c, uid, []*models.Transaction{transaction})
+
+	if err != nil {
+		log.Errorf(c, "[modify_transaction.Handle] failed to get transaction split lines for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[modify_transaction.Handle] get account error, because %s", err.Error())
+		return nil, nil, err
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Warnf(c, "[modify_transaction.Handle] get transaction category error, because %s", err.Error())
+		return nil, nil, err
+	}
+
+	allTransactionTagIds, err := services.GetTransactionTagService().GetAllTagIdsOfTransactions(c, uid, []int64{transaction.TransactionId})
+
+	if err != nil {
+		log.Errorf(c, "[modify_transaction.Handle] failed to get transactions tag ids for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
+
+	if transactionTagIds == nil {
+		transactionTagIds = make([]int64, 0, 0)
+	}
+
+	newTransaction := &models.Transaction{
+		TransactionId:     transaction.TransactionId,
+		Uid:               uid,
+		CategoryId:        transaction.CategoryId,
+		TransactionTime:   transaction.TransactionTime,
+		TimezoneUtcOffset: transaction.TimezoneUtcOffset,
+		AccountId:         transaction.AccountId,
+		Amount:            transaction.Amount,
+		HideAmount:        transaction.HideAmount,
+		Comment:           transaction.Comment,
+		GeoLongitude:      transaction.GeoLongitude,
+		GeoLatitude:       transaction.GeoLatitude,
+		Splits:            transaction.Splits,
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		newTransaction.RelatedAccountId = transaction.RelatedAccountId
+		newTransaction.RelatedAccountAmount = transaction.RelatedAccountAmount
+	}
+
+	if modifyTransactionRequest.Time != "" {
+		transactionTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(modifyTransactionRequest.Time)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		if transactionTime.Unix() != utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) {
+			newTransaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix())
+		}
+
+		newTransaction.TimezoneUtcOffset = utils.GetTimezoneOffsetMinutes(transactionTime.Location())
+	}
+
+	if modifyTransactionRequest.SecondaryCategoryName != "" {
+		categoriesMap := services.GetTransactionCategoryService().GetVisibleCategoryNameMapByList(allCategories)
+		category, exists := categoriesMap[modifyTransactionRequest.SecondaryCategoryName]
+
+		if !exists {
+			log.Warnf(c, "[modify_transaction.Handle] secondary category \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.SecondaryCategoryName, uid)
+			return nil, nil, errs.ErrTransactionCategoryNotFound
+		}
+
+		newTransaction.CategoryId = category.CategoryId
+	}
+
+	accountsMap := services.GetAccountService().GetVisibleAccountNameMapByList(allAccounts)
+
+	if modifyTransactionRequest.AccountName != "" {
+		sourceAccount, exists := accountsMap[modifyTransactionRequest.AccountName]
+
+		if !exists {
+			log.Warnf(c, "[modify_transaction.Handle] source account \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.AccountName, uid)
+			return nil, nil, errs.ErrSourceAccountNotFound
+		}
+
+		newTransaction.AccountId = sourceAccount.AccountId
+	}
+
+	if modifyTransactionRequest.DestinationAccountName != "" {
+		destinationAccount, exists := accountsMap[modifyTransactionRequest.DestinationAccountName]
+
+		if !exists {
+			log.Warnf(c, "[modify_transaction.Handle] destination account \"%s\" not found for user \"uid:%d\"", modifyTransactionRequest.DestinationAccountName, uid)
+			return nil, nil, errs.ErrDestinationAccountNotFound
+		}
+
+		newTransaction.RelatedAccountId = destinationAccount.AccountId
+	}
+
+	if modifyTransactionRequest.Amount != "" {
+		amount, err := utils.ParseAmount(modifyTransactionRequest.Amount)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		newTransaction.Amount = amount
+	}
+
+	if modifyTransactionRequest.DestinationAmount != "" {
+		destinationAmount, err := utils.ParseAmount(modifyTransactionRequest.DestinationAmount)
+
+		if err != nil {
+			return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		newTransaction.RelatedAccountAmount = destinationAmount
+	}
+
+	if modifyTransactionRequest.Comment != nil {
+		newTransaction.Comment = *modifyTransactionRequest.Comment
+	}
+
+	tagIds := transactionTagIds
+
+	if modifyTransactionRequest.Tags != nil {
+		allTags, err := services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+
+		if err != nil {
+			log.Warnf(c, "[modify_transaction.Handle] get transaction tag ids error, because %s", err.Error())
+			return nil, nil, err
+		}
+
+		tagMaps := services.GetTransactionTagService().GetTagNameMapByList(allTags)
+		tagIds = make([]int64, 0, len(modifyTransactionRequest.Tags))
+
+		for _, tagName := range modifyTransactionRequest.Tags {
+			if tag, exists := tagMaps[tagName]; exists {
+				tagIds = append(tagIds, tag.TagId)
+			} else {
+				log.Warnf(c, "[modify_transaction.Handle] transaction tag \"%s\" not found for user \"uid:%d\"", tagName, uid)
+			}
+		}
+	}
+
+	if newTransaction.CategoryId == transaction.CategoryId &&
+		utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime) == utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) &&
+		newTransaction.TimezoneUtcOffset == transaction.TimezoneUtcOffset &&
+		newTransaction.AccountId == transaction.AccountId &&
+		newTransaction.Amount == transaction.Amount &&
+		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountId == transaction.RelatedAccountId) &&
+		(transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT || newTransaction.RelatedAccountAmount == transaction.RelatedAccountAmount) &&
+		newTransaction.Comment == transaction.Comment &&
+		utils.Int64SliceEquals(tagIds, transactionTagIds) {
+		return nil, nil, errs.ErrNothingWillBeUpdated
+	}
+
+	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset)
+	newTransactionEditable := user.CanEditTransactionByTransactionTime(newTransaction.TransactionTime, newTransaction.TimezoneUtcOffset)
+
+	if !transactionEditable || !newTransactionEditable {
+		return nil, nil, errs.ErrCannotModifyTransactionWithThisTransactionTime
+	}
+
+	var addTransactionTagIds []int64
+	var removeTransactionTagIds []int64
+
+	if !utils.Int64SliceEquals(tagIds, transactionTagIds) {
+		removeTransactionTagIds = transactionTagIds
+		addTransactionTagIds = tagIds
+	}
+
+	if !modifyTransactionRequest.DryRun {
+		err = services.GetTransactionService().ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, nil, nil)
+
+		if err != nil {
+			log.Errorf(c, "[modify_transaction.Handle] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionId, uid, err.Error())
+			return nil, nil, err
+		}
+
+		log.Infof(c, "[modify_transaction.Handle] user \"uid:%d\" has updated transaction \"id:%d\" successfully", uid, transactionId)
+		Sessions.NotifyTransactionsUpdated(uid, transaction.TransactionTime, newTransaction.TransactionTime)
+	}
+
+	newTransaction.Type = transaction.Type
+
+	response := MCPModifyTransactionResponse{
+		Success:     true,
+		DryRun:      modifyTransactionRequest.DryRun,
+		Transaction: h.createNewMCPTransactionInfo(newTransaction, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories)),
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func (h *mcpModifyTransactionToolHandler) createNewMCPTransactionInfo(transaction *models.Transaction, accountsMap map[int64]*models.Account, categoriesMap map[int64]*models.TransactionCategory) *MCPTransactionInfo {
+	transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+
+	transactionInfo := &MCPTransactionInfo{
+		Id:      utils.Int64ToString(transaction.TransactionId),
+		Time:    utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(transactionUnixTime, transactionTimeZone),
+		Amount:  utils.FormatAmount(transaction.Amount),
+		Comment: transaction.Comment,
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		transactionInfo.Type = transactionTypeExpense
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+		transactionInfo.Type = transactionTypeIncome
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transactionInfo.Type = transactionTypeTransfer
+	}
+
+	if category, exists := categoriesMap[transaction.CategoryId]; exists && category != nil {
+		transactionInfo.SecondaryCategoryName = category.Name
+	}
+
+	if account, exists := accountsMap[transaction.AccountId]; exists && account != nil {
+		transactionInfo.AccountName = account.Name
+		transactionInfo.Currency = account.Currency
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		transactionInfo.DestinationAmount = utils.FormatAmount(transaction.RelatedAccountAmount)
+
+		if destinationAccount, exists := accountsMap[transaction.RelatedAccountId]; exists && destinationAccount != nil {
+			transactionInfo.DestinationAccountName = destinationAccount.Name
+			transactionInfo.DestinationCurrency = destinationAccount.Currency
+		}
+	}
+
+	return transactionInfo
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+type mcpMonthlySpendingReviewPromptHandler struct{}
+
+var MCPMonthlySpendingReviewPromptHandler = &mcpMonthlySpendingReviewPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) Name() string {
+	return "monthly_spending_review"
+}
+
+// Title returns the title of the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) Title() string {
+	return "Monthly spending review"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) Description() string {
+	return "Review the income and expense of a month compared with the previous month in ezBookkeeping."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpMonthlySpendingReviewPromptHandler) Arguments() []*MCPPromptArgument {
+	return []*MCPPromptArgument{
+		{
+			Name:        "month",
+			Title:       "Month",
+			Description: "Month to review in yyyy-mm format (e.g. 2023-01) (optional, default is the current month)",
+			Required:    false,
+		},
+	}
+}
+
+// Handle processes the MCP get prompt request and returns the prompt messages
+func (h *mcpMonthlySpendingReviewPromptHandler) Handle(c *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) ([]*MCPPromptMessage, error) {
+	monthTime := time.Now()
+
+	if yearMonth := getPromptReq.Arguments["month"]; yearMonth != "" {
+		year, month, err := utils.ParseNumericYearMonth(yearMonth)
+
+		if err != nil || year < 1 || month < 1 || month > 12 {
+			return nil, errs.ErrIncompleteOrIncorrectSubmission
+		}
+
+		monthTime = time.Date(int(year), time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	}
+
+	currentMonth := monthTime.Format("2006-01")
+	previousMonth := time.Date(monthTime.Year(), monthTime.Month()-1, 1, 0, 0, 0, 0, time.Local).Format("2006-01")
+
+	currentMonthSummary, err := h.readMonthlySummary(c, currentMonth, user, currentConfig, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previousMonthSummary, err := h.readMonthlySummary(c, previousMonth, user, currentConfig, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*MCPPromptMessage{
+		NewMCPUserPromptMessage(NewMCPEmbeddedResource(currentMonthSummary)),
+		NewMCPUserPromptMessage(NewMCPEmbeddedResource(previousMonthSummary)),
+		NewMCPUserPromptMessage(NewMCPTextContent(fmt.Sprintf("Above are my income and expense summaries of %[1]s and %[2]s in ezBookkeeping. "+
+			"Please review my spending of %[1]s: list the categories I spent the most on, point out the categories which changed significantly compared with %[2]s, "+
+			"and give some practical suggestions to reduce unnecessary spending. "+
+			"If you need more details, use the query_transactions tool to look into the transactions of a specific category.", currentMonth, previousMonth))),
+	}, nil
+}
+
+func (h *mcpMonthlySpendingReviewPromptHandler) readMonthlySummary(c *core.WebContext, yearMonth string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	uri := MCPMonthlySummaryResourceHandler.GetMonthlySummaryResourceURI(yearMonth)
+	return MCPMonthlySummaryResourceHandler.Read(c, uri, yearMonth, user, currentConfig, services)
+}
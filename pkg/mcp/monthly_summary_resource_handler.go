@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+type mcpMonthlySummaryResourceHandler struct{}
+
+var MCPMonthlySummaryResourceHandler = &mcpMonthlySummaryResourceHandler{}
+
+// URITemplate returns the uri template of the MCP resource
+func (h *mcpMonthlySummaryResourceHandler) URITemplate() string {
+	return "ezbookkeeping://monthly-summary/{yyyy-mm}"
+}
+
+// Name returns the name of the MCP resource template
+func (h *mcpMonthlySummaryResourceHandler) Name() string {
+	return "monthly-summary"
+}
+
+// Description returns the description of the MCP resource template
+func (h *mcpMonthlySummaryResourceHandler) Description() string {
+	return "Total income and expense of the specified month (e.g. 2023-01) grouped by category, all amounts are converted to the default currency of the current user."
+}
+
+// MimeType returns the mime type of the MCP resource contents
+func (h *mcpMonthlySummaryResourceHandler) MimeType() string {
+	return mcpJSONResourceMimeType
+}
+
+// Read returns the contents of the MCP resource, the second parameter is the variable part of the uri
+func (h *mcpMonthlySummaryResourceHandler) Read(c *core.WebContext, uri string, yearMonth string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	year, month, err := utils.ParseNumericYearMonth(yearMonth)
+
+	if err != nil || year < 1 || month < 1 || month > 12 {
+		return nil, errs.ErrMCPResourceNotFound
+	}
+
+	// there is no client timezone in mcp requests, so the month range is calculated in the server timezone
+	startTime := time.Date(int(year), time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	endTime := startTime.AddDate(0, 1, 0).Add(-time.Second)
+
+	arguments, err := json.Marshal(MCPQueryTransactionStatisticsRequest{
+		StartTime: startTime.Format(time.RFC3339),
+		EndTime:   endTime.Format(time.RFC3339),
+		GroupBy:   transactionStatisticsGroupByCategory,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return readMCPResourceFromTool(c, uri, h.MimeType(), MCPQueryTransactionStatisticsToolHandler, arguments, user, currentConfig, services)
+}
+
+// GetMonthlySummaryResourceURI returns the monthly summary resource uri of the specified year and month
+func (h *mcpMonthlySummaryResourceHandler) GetMonthlySummaryResourceURI(yearMonth string) string {
+	return strings.Replace(h.URITemplate(), "{yyyy-mm}", yearMonth, 1)
+}
+
+// GetCurrentMonthResource returns the monthly summary resource of the current month in the server timezone
+func (h *mcpMonthlySummaryResourceHandler) GetCurrentMonthResource() *MCPResource {
+	return &MCPResource{
+		URI:         h.GetMonthlySummaryResourceURI(time.Now().Format("2006-01")),
+		Name:        "current-month-summary",
+		MimeType:    h.MimeType(),
+		Description: "Total income and expense of the current month grouped by category, all amounts are converted to the default currency of the current user.",
+	}
+}
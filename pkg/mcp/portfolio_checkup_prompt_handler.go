@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mcpPortfolioCheckupPromptHandler struct{}
+
+var MCPPortfolioCheckupPromptHandler = &mcpPortfolioCheckupPromptHandler{}
+
+// Name returns the name of the MCP prompt
+func (h *mcpPortfolioCheckupPromptHandler) Name() string {
+	return "portfolio_checkup"
+}
+
+// Title returns the title of the MCP prompt
+func (h *mcpPortfolioCheckupPromptHandler) Title() string {
+	return "Portfolio check-up"
+}
+
+// Description returns the description of the MCP prompt
+func (h *mcpPortfolioCheckupPromptHandler) Description() string {
+	return "Review the investment holdings, allocation and unrealized gain or loss of the portfolio in ezBookkeeping."
+}
+
+// Arguments returns the arguments of the MCP prompt
+func (h *mcpPortfolioCheckupPromptHandler) Arguments() []*MCPPromptArgument {
+	return nil
+}
+
+// Handle processes the MCP get prompt request and returns the prompt messages
+func (h *mcpPortfolioCheckupPromptHandler) Handle(c *core.WebContext, getPromptReq *MCPGetPromptRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) ([]*MCPPromptMessage, error) {
+	portfolio, err := MCPPortfolioResourceHandler.Read(c, MCPPortfolioResourceHandler.URI(), user, currentConfig, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*MCPPromptMessage{
+		NewMCPUserPromptMessage(NewMCPEmbeddedResource(portfolio)),
+		NewMCPUserPromptMessage(NewMCPTextContent("Above is my investment portfolio in ezBookkeeping. " +
+			"Please give my portfolio a check-up: summarize the allocation of each holding by current value, point out concentration risks, " +
+			"highlight the holdings with the largest unrealized gains or losses, and mention any holdings whose market prices are unavailable or outdated. " +
+			"Do not give any trading instructions, only observations and points worth considering.")),
+	}, nil
+}
@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"encoding/json"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+// MCPPortfolioResourceContent represents the content structure of portfolio resource
+type MCPPortfolioResourceContent struct {
+	Summary     any `json:"summary"`
+	Investments any `json:"investments"`
+}
+
+type mcpPortfolioResourceHandler struct{}
+
+var MCPPortfolioResourceHandler = &mcpPortfolioResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpPortfolioResourceHandler) URI() string {
+	return "ezbookkeeping://portfolio"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpPortfolioResourceHandler) Name() string {
+	return "portfolio"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpPortfolioResourceHandler) Description() string {
+	return "Investment portfolio summary and all investment holdings with latest market prices of the current user in ezBookkeeping."
+}
+
+// MimeType returns the mime type of the MCP resource contents
+func (h *mcpPortfolioResourceHandler) MimeType() string {
+	return mcpJSONResourceMimeType
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpPortfolioResourceHandler) Read(c *core.WebContext, uri string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	summary, _, err := MCPQueryPortfolioSummaryToolHandler.Handle(c, &MCPCallToolRequest{Name: MCPQueryPortfolioSummaryToolHandler.Name()}, user, currentConfig, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	investments, _, err := MCPQueryInvestmentsToolHandler.Handle(c, &MCPCallToolRequest{Name: MCPQueryInvestmentsToolHandler.Name()}, user, currentConfig, services)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(MCPPortfolioResourceContent{
+		Summary:     summary,
+		Investments: investments,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMCPTextResourceContents(uri, string(content), h.MimeType()), nil
+}
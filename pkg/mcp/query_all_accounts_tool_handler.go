@@ -47,6 +47,11 @@ func (h *mcpQueryAllAccountsToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryAllAccountsResponse{})
 }
 
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryAllAccountsToolHandler) IsReadOnly() bool {
+	return true
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllAccountsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
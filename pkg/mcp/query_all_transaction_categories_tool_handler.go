@@ -41,6 +41,11 @@ func (h *mcpQueryAllTransactionCategoriesToolHandler) OutputType() reflect.Type
 	return reflect.TypeOf(&MCPQueryAllTransactionCategoriesResponse{})
 }
 
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryAllTransactionCategoriesToolHandler) IsReadOnly() bool {
+	return true
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllTransactionCategoriesToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
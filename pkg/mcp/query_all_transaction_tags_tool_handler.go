@@ -39,6 +39,11 @@ func (h *mcpQueryAllTransactionTagsToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPAllQueryTransactionTagsResponse{})
 }
 
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryAllTransactionTagsToolHandler) IsReadOnly() bool {
+	return true
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryAllTransactionTagsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	uid := user.Uid
@@ -0,0 +1,194 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var investmentAssetTypeNames = map[models.InvestmentAssetType]string{
+	models.INVESTMENT_ASSET_TYPE_STOCK:          "stock",
+	models.INVESTMENT_ASSET_TYPE_ETF:            "etf",
+	models.INVESTMENT_ASSET_TYPE_MUTUAL_FUND:    "mutual_fund",
+	models.INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY: "cryptocurrency",
+	models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL: "precious_metal",
+	models.INVESTMENT_ASSET_TYPE_BOND:           "bond",
+}
+
+// MCPQueryInvestmentsRequest represents all parameters of the query investments request
+type MCPQueryInvestmentsRequest struct {
+	TickerSymbols string `json:"ticker_symbols,omitempty" jsonschema_description:"Comma-separated list of ticker symbols to filter investment holdings by (e.g. VTI,AAPL) (optional, leave empty for all holdings)"`
+}
+
+// MCPQueryInvestmentsResponse represents the response structure for querying investments
+type MCPQueryInvestmentsResponse struct {
+	Investments []*MCPInvestmentInfo `json:"investments" jsonschema_description:"List of investment holdings"`
+}
+
+// MCPInvestmentInfo defines the structure of investment holding information
+type MCPInvestmentInfo struct {
+	TickerSymbol    string `json:"ticker_symbol" jsonschema_description:"Ticker symbol of the investment (e.g. VTI)"`
+	CompanyName     string `json:"company_name,omitempty" jsonschema_description:"Company or fund name of the investment"`
+	AssetType       string `json:"asset_type" jsonschema:"enum=stock,enum=etf,enum=mutual_fund,enum=cryptocurrency,enum=precious_metal,enum=bond" jsonschema_description:"Asset type of the investment (stock, etf, mutual_fund, cryptocurrency, precious_metal, bond)"`
+	Shares          string `json:"shares" jsonschema_description:"Number of shares (or coins for cryptocurrency, troy ounces for precious metal) currently owned"`
+	AvgCostPerShare string `json:"avg_cost_per_share" jsonschema_description:"Average cost per share"`
+	TotalInvested   string `json:"total_invested" jsonschema_description:"Total cost of the currently owned shares"`
+	CurrentPrice    string `json:"current_price,omitempty" jsonschema_description:"Latest market price per share (empty if the price is unavailable)"`
+	CurrentValue    string `json:"current_value,omitempty" jsonschema_description:"Current market value of the holding (empty if the price is unavailable)"`
+	GainLoss        string `json:"gain_loss,omitempty" jsonschema_description:"Unrealized gain or loss of the holding (empty if the price is unavailable)"`
+	GainLossPercent string `json:"gain_loss_percent,omitempty" jsonschema_description:"Unrealized gain or loss percentage of the holding (empty if the price is unavailable)"`
+	Currency        string `json:"currency" jsonschema_description:"Currency code of the investment (e.g. USD)"`
+	WalletAddress   string `json:"wallet_address,omitempty" jsonschema_description:"Wallet address of the cryptocurrency holding"`
+	PriceUpdateTime string `json:"price_update_time,omitempty" jsonschema_description:"Last update time of the market price in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
+}
+
+type mcpQueryInvestmentsToolHandler struct{}
+
+var MCPQueryInvestmentsToolHandler = &mcpQueryInvestmentsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryInvestmentsToolHandler) Name() string {
+	return "query_investments"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryInvestmentsToolHandler) Description() string {
+	return "Query investment holdings with latest market prices and unrealized gain or loss for the current user in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryInvestmentsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryInvestmentsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryInvestmentsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryInvestmentsResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryInvestmentsToolHandler) IsReadOnly() bool {
+	return true
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryInvestmentsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryInvestmentsRequest MCPQueryInvestmentsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryInvestmentsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	}
+
+	uid := user.Uid
+	investments, err := services.GetInvestmentService().GetAllInvestments(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_investments.Handle] failed to get investments for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	queryTickerSymbols := make(map[string]bool)
+
+	for _, tickerSymbol := range strings.Split(queryInvestmentsRequest.TickerSymbols, ",") {
+		tickerSymbol = strings.ToUpper(strings.TrimSpace(tickerSymbol))
+
+		if tickerSymbol != "" {
+			queryTickerSymbols[tickerSymbol] = true
+		}
+	}
+
+	response := MCPQueryInvestmentsResponse{
+		Investments: make([]*MCPInvestmentInfo, 0, len(investments)),
+	}
+
+	for i := 0; i < len(investments); i++ {
+		investment := investments[i]
+
+		if _, exists := queryTickerSymbols[strings.ToUpper(investment.TickerSymbol)]; len(queryTickerSymbols) > 0 && !exists {
+			continue
+		}
+
+		response.Investments = append(response.Investments, h.createNewMCPInvestmentInfo(investment))
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+func (h *mcpQueryInvestmentsToolHandler) createNewMCPInvestmentInfo(investment *models.InvestmentWithCurrentPrice) *MCPInvestmentInfo {
+	investmentInfo := &MCPInvestmentInfo{
+		TickerSymbol:    investment.TickerSymbol,
+		CompanyName:     investment.CompanyName,
+		AssetType:       getInvestmentAssetTypeName(investment.AssetType),
+		Shares:          formatInvestmentShares(investment.SharesOwned),
+		AvgCostPerShare: formatInvestmentPrice(investment.AvgCostPerShare, investment.PricePrecision),
+		TotalInvested:   utils.FormatAmount(investment.TotalInvested),
+		Currency:        investment.Currency,
+		WalletAddress:   investment.WalletAddress,
+	}
+
+	if investment.CurrentPrice > 0 {
+		investmentInfo.CurrentPrice = formatInvestmentPrice(investment.CurrentPrice, investment.PricePrecision)
+		investmentInfo.CurrentValue = utils.FormatAmount(investment.CurrentValue)
+		investmentInfo.GainLoss = utils.FormatAmount(investment.GainLoss)
+		investmentInfo.GainLossPercent = formatInvestmentPercent(investment.GainLossPct)
+	}
+
+	if investment.LastPriceUpdate > 0 {
+		investmentInfo.PriceUpdateTime = utils.FormatUnixTimeToLongDateTimeWithTimezoneRFC3339Format(investment.LastPriceUpdate, time.UTC)
+	}
+
+	return investmentInfo
+}
+
+func getInvestmentAssetTypeName(assetType models.InvestmentAssetType) string {
+	if name, exists := investmentAssetTypeNames[assetType]; exists {
+		return name
+	}
+
+	return investmentAssetTypeNames[models.INVESTMENT_ASSET_TYPE_STOCK]
+}
+
+func getInvestmentAssetTypeByName(name string) (models.InvestmentAssetType, error) {
+	if name == "" {
+		return models.INVESTMENT_ASSET_TYPE_STOCK, nil
+	}
+
+	for assetType, assetTypeName := range investmentAssetTypeNames {
+		if assetTypeName == name {
+			return assetType, nil
+		}
+	}
+
+	return 0, errs.ErrInvestmentAssetTypeInvalid
+}
+
+func formatInvestmentShares(shares float64) string {
+	return strconv.FormatFloat(shares, 'f', -1, 64)
+}
+
+func formatInvestmentPrice(price int64, precision int32) string {
+	return strconv.FormatFloat(models.ConvertInvestmentPriceToFloat64(price, precision), 'f', int(precision), 64)
+}
+
+func formatInvestmentPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', 2, 64)
+}
@@ -56,6 +56,11 @@ func (h *mcpQueryLatestExchangeRatesToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryExchangeRatesResponse{})
 }
 
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryLatestExchangeRatesToolHandler) IsReadOnly() bool {
+	return true
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryLatestExchangeRatesToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var exchangeRatesRequest MCPQueryExchangeRatesRequest
@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryPortfolioSummaryResponse represents the response structure for querying portfolio summary
+type MCPQueryPortfolioSummaryResponse struct {
+	TotalInvested   string `json:"total_invested" jsonschema_description:"Total cost of all currently owned investment holdings"`
+	CurrentValue    string `json:"current_value" jsonschema_description:"Current market value of all investment holdings"`
+	GainLoss        string `json:"gain_loss" jsonschema_description:"Total unrealized gain or loss of the portfolio"`
+	GainLossPercent string `json:"gain_loss_percent" jsonschema_description:"Total unrealized gain or loss percentage of the portfolio"`
+	Currency        string `json:"currency" jsonschema_description:"Currency code of the portfolio summary (e.g. USD)"`
+}
+
+type mcpQueryPortfolioSummaryToolHandler struct{}
+
+var MCPQueryPortfolioSummaryToolHandler = &mcpQueryPortfolioSummaryToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryPortfolioSummaryToolHandler) Name() string {
+	return "query_portfolio_summary"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryPortfolioSummaryToolHandler) Description() string {
+	return "Query investment portfolio summary including total invested, current value and unrealized gain or loss for the current user in ezBookkeeping."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryPortfolioSummaryToolHandler) InputType() reflect.Type {
+	return nil
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryPortfolioSummaryToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryPortfolioSummaryResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryPortfolioSummaryToolHandler) IsReadOnly() bool {
+	return true
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryPortfolioSummaryToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	uid := user.Uid
+	summary, err := services.GetInvestmentService().GetPortfolioSummary(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[query_portfolio_summary.Handle] failed to get portfolio summary for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	response := MCPQueryPortfolioSummaryResponse{
+		TotalInvested:   utils.FormatAmount(summary.TotalInvested),
+		CurrentValue:    utils.FormatAmount(summary.CurrentValue),
+		GainLoss:        utils.FormatAmount(summary.TotalGainLoss),
+		GainLossPercent: formatInvestmentPercent(summary.TotalGainLossPct),
+		Currency:        summary.Currency,
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
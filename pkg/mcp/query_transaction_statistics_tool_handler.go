@@ -0,0 +1,348 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const transactionStatisticsGroupByCategory = "category"
+const transactionStatisticsGroupByPrimaryCategory = "primary_category"
+const transactionStatisticsGroupByAccount = "account"
+
+// MCPQueryTransactionStatisticsRequest represents all parameters of the query transaction statistics request
+type MCPQueryTransactionStatisticsRequest struct {
+	StartTime             string `json:"start_time" jsonschema:"format=date-time" jsonschema_description:"Start time for the statistics in RFC 3339 format (e.g. 2023-01-01T00:00:00Z)"`
+	EndTime               string `json:"end_time" jsonschema:"format=date-time" jsonschema_description:"End time for the statistics in RFC 3339 format (e.g. 2023-12-31T23:59:59Z)"`
+	Type                  string `json:"type,omitempty" jsonschema:"enum=income,enum=expense" jsonschema_description:"Transaction type to filter by (income, expense) (optional, leave empty for both)"`
+	GroupBy               string `json:"group_by,omitempty" jsonschema:"enum=category,enum=primary_category,enum=account,default=category" jsonschema_description:"Dimension to group the total amounts by (category, primary_category, account) (default: category)"`
+	SecondaryCategoryName string `json:"category_name,omitempty" jsonschema_description:"Secondary category name to filter transactions by (optional)"`
+	AccountName           string `json:"account_name,omitempty" jsonschema_description:"Account name to filter transactions by (optional)"`
+	Keyword               string `json:"keyword,omitempty" jsonschema_description:"Keyword to search in transaction description (optional)"`
+}
+
+// MCPQueryTransactionStatisticsResponse represents the response structure for querying transaction statistics
+type MCPQueryTransactionStatisticsResponse struct {
+	Currency              string                         `json:"currency" jsonschema_description:"Currency code of all amounts in the response, which is the default currency of the current user (e.g. USD)"`
+	TotalIncome           string                         `json:"total_income" jsonschema_description:"Total income amount"`
+	TotalExpense          string                         `json:"total_expense" jsonschema_description:"Total expense amount"`
+	Items                 []*MCPTransactionStatisticItem `json:"items" jsonschema_description:"Total amounts grouped by the specified dimension, sorted by amount in descending order"`
+	UnconvertedCurrencies []string                       `json:"unconverted_currencies,omitempty" jsonschema_description:"Currencies which have no exchange rate to the default currency, amounts in these currencies are not included in the statistics"`
+}
+
+// MCPTransactionStatisticItem defines the structure of transaction statistic item
+type MCPTransactionStatisticItem struct {
+	Type       string `json:"type" jsonschema:"enum=income,enum=expense" jsonschema_description:"Transaction type (income, expense)"`
+	Name       string `json:"name" jsonschema_description:"Name of the category or account"`
+	ParentName string `json:"parent_name,omitempty" jsonschema_description:"Primary category name of the secondary category (only when grouping by category)"`
+	Amount     string `json:"amount" jsonschema_description:"Total amount in the default currency"`
+}
+
+type mcpQueryTransactionStatisticsToolHandler struct{}
+
+var MCPQueryTransactionStatisticsToolHandler = &mcpQueryTransactionStatisticsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) Name() string {
+	return "query_transaction_statistics"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryTransactionStatisticsToolHandler) Description() string {
+	return "Query total income and expense amounts grouped by category or account within a time range, all amounts are converted to the default currency of the current user."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryTransactionStatisticsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionStatisticsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryTransactionStatisticsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionStatisticsResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryTransactionStatisticsToolHandler) IsReadOnly() bool {
+	return true
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryTransactionStatisticsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryStatisticsRequest MCPQueryTransactionStatisticsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryStatisticsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	startTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryStatisticsRequest.StartTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	endTime, err := utils.ParseFromLongDateTimeWithTimezoneRFC3339Format(queryStatisticsRequest.EndTime)
+
+	if err != nil {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	aggregator, err := createNewMCPTransactionStatisticsAggregator(c, user, currentConfig, services, queryStatisticsRequest.Type, queryStatisticsRequest.GroupBy, queryStatisticsRequest.SecondaryCategoryName, queryStatisticsRequest.AccountName, startTime.Unix(), endTime.Unix())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	utcOffset := utils.GetTimezoneOffsetMinutes(startTime.Location())
+	totalAmounts, err := services.GetTransactionService().GetAccountsAndCategoriesDailyIncomeAndExpense(c, uid, startTime.Unix(), endTime.Unix(), nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, queryStatisticsRequest.Keyword, utcOffset, false)
+
+	if err != nil {
+		log.Errorf(c, "[query_transaction_statistics.Handle] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	totalIncome, totalExpense, items := aggregator.aggregate(totalAmounts)
+
+	response := MCPQueryTransactionStatisticsResponse{
+		Currency:              user.DefaultCurrency,
+		TotalIncome:           utils.FormatAmount(totalIncome),
+		TotalExpense:          utils.FormatAmount(totalExpense),
+		Items:                 items,
+		UnconvertedCurrencies: aggregator.getUnconvertedCurrencies(),
+	}
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
+
+// mcpTransactionStatisticsAggregator groups the total amounts of accounts and categories and converts them to the default currency
+type mcpTransactionStatisticsAggregator struct {
+	defaultCurrency        string
+	exchangeRatesConverter *exchangerates.ExchangeRatesConverter
+	accountsMap            map[int64]*models.Account
+	categoriesMap          map[int64]*models.TransactionCategory
+	filterType             models.TransactionCategoryType
+	filterCategoryId       int64
+	filterAccountId        int64
+	groupBy                string
+	unconvertedCurrencies  map[string]bool
+}
+
+func createNewMCPTransactionStatisticsAggregator(c *core.WebContext, user *models.User, currentConfig *settings.Config, services MCPAvailableServices, transactionType string, groupBy string, secondaryCategoryName string, accountName string, startUnixTime int64, endUnixTime int64) (*mcpTransactionStatisticsAggregator, error) {
+	uid := user.Uid
+	aggregator := &mcpTransactionStatisticsAggregator{
+		defaultCurrency:       user.DefaultCurrency,
+		groupBy:               groupBy,
+		unconvertedCurrencies: make(map[string]bool),
+	}
+
+	if transactionType == transactionTypeIncome {
+		aggregator.filterType = models.CATEGORY_TYPE_INCOME
+	} else if transactionType == transactionTypeExpense {
+		aggregator.filterType = models.CATEGORY_TYPE_EXPENSE
+	} else if transactionType != "" {
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+
+	if groupBy == "" {
+		aggregator.groupBy = transactionStatisticsGroupByCategory
+	} else if groupBy != transactionStatisticsGroupByCategory && groupBy != transactionStatisticsGroupByPrimaryCategory && groupBy != transactionStatisticsGroupByAccount {
+		return nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	allAccounts, err := services.GetAccountService().GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Warnf(c, "[query_transaction_statistics.createNewMCPTransactionStatisticsAggregator] get account error, because %s", err.Error())
+		return nil, err
+	}
+
+	aggregator.accountsMap = services.GetAccountService().GetAccountMapByList(allAccounts)
+
+	if accountName != "" {
+		accountsNameMap := services.GetAccountService().GetVisibleAccountNameMapByList(allAccounts)
+
+		if account, exists := accountsNameMap[accountName]; exists {
+			aggregator.filterAccountId = account.AccountId
+		} else {
+			return nil, errs.ErrAccountNotFound
+		}
+	}
+
+	allCategories, err := services.GetTransactionCategoryService().GetAllCategoriesByUid(c, uid, 0, -1)
+
+	if err != nil {
+		log.Warnf(c, "[query_transaction_statistics.createNewMCPTransactionStatisticsAggregator] get transaction category error, because %s", err.Error())
+		return nil, err
+	}
+
+	aggregator.categoriesMap = services.GetTransactionCategoryService().GetCategoryMapByList(allCategories)
+
+	if secondaryCategoryName != "" {
+		categoriesNameMap := services.GetTransactionCategoryService().GetVisibleCategoryNameMapByList(allCategories)
+
+		if category, exists := categoriesNameMap[secondaryCategoryName]; exists {
+			aggregator.filterCategoryId = category.CategoryId
+		} else {
+			return nil, errs.ErrTransactionCategoryNotFound
+		}
+	}
+
+	exchangeRateHistories, err := services.GetExchangeRatesHistoryService().GetExchangeRatesByTimeRange(c, currentConfig.ExchangeRatesDataSource, startUnixTime, endUnixTime)
+
+	if err != nil {
+		log.Warnf(c, "[query_transaction_statistics.createNewMCPTransactionStatisticsAggregator] failed to get historical exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	exchangeRatesConverter, err := exchangerates.Container.NewExchangeRatesConverter(c, uid, currentConfig, exchangeRateHistories)
+
+	if err != nil {
+		log.Warnf(c, "[query_transaction_statistics.createNewMCPTransactionStatisticsAggregator] failed to get exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	aggregator.exchangeRatesConverter = exchangeRatesConverter
+
+	return aggregator, nil
+}
+
+func (a *mcpTransactionStatisticsAggregator) aggregate(totalAmounts []*models.Transaction) (int64, int64, []*MCPTransactionStatisticItem) {
+	totalIncome := int64(0)
+	totalExpense := int64(0)
+	itemAmounts := make(map[string]int64)
+	itemsMap := make(map[string]*MCPTransactionStatisticItem)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmount := totalAmounts[i]
+		category, exists := a.categoriesMap[totalAmount.CategoryId]
+
+		if !exists {
+			continue
+		}
+
+		account, exists := a.accountsMap[totalAmount.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		if (a.filterType != 0 && category.Type != a.filterType) || (a.filterCategoryId != 0 && category.CategoryId != a.filterCategoryId) || (a.filterAccountId != 0 && account.AccountId != a.filterAccountId) {
+			continue
+		}
+
+		amount, converted := a.getAmountInDefaultCurrency(totalAmount, account.Currency)
+
+		if !converted {
+			continue
+		}
+
+		item := &MCPTransactionStatisticItem{}
+
+		if category.Type == models.CATEGORY_TYPE_INCOME {
+			item.Type = transactionTypeIncome
+			totalIncome += amount
+		} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
+			item.Type = transactionTypeExpense
+			totalExpense += amount
+		} else {
+			continue
+		}
+
+		if a.groupBy == transactionStatisticsGroupByAccount {
+			item.Name = account.Name
+		} else if a.groupBy == transactionStatisticsGroupByPrimaryCategory {
+			item.Name = category.Name
+
+			if parentCategory, exists := a.categoriesMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+				item.Name = parentCategory.Name
+			}
+		} else {
+			item.Name = category.Name
+
+			if parentCategory, exists := a.categoriesMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId {
+				item.ParentName = parentCategory.Name
+			}
+		}
+
+		itemKey := item.Type + "\n" + item.ParentName + "\n" + item.Name
+
+		if _, exists := itemsMap[itemKey]; !exists {
+			itemsMap[itemKey] = item
+		}
+
+		itemAmounts[itemKey] += amount
+	}
+
+	itemKeys := make([]string, 0, len(itemsMap))
+
+	for itemKey := range itemsMap {
+		itemKeys = append(itemKeys, itemKey)
+	}
+
+	sort.Slice(itemKeys, func(i, j int) bool {
+		if itemAmounts[itemKeys[i]] != itemAmounts[itemKeys[j]] {
+			return itemAmounts[itemKeys[i]] > itemAmounts[itemKeys[j]]
+		}
+
+		return itemKeys[i] < itemKeys[j]
+	})
+
+	items := make([]*MCPTransactionStatisticItem, len(itemKeys))
+
+	for i := 0; i < len(itemKeys); i++ {
+		items[i] = itemsMap[itemKeys[i]]
+		items[i].Amount = utils.FormatAmount(itemAmounts[itemKeys[i]])
+	}
+
+	return totalIncome, totalExpense, items
+}
+
+func (a *mcpTransactionStatisticsAggregator) getAmountInDefaultCurrency(totalAmount *models.Transaction, currency string) (int64, bool) {
+	if currency == a.defaultCurrency {
+		return totalAmount.Amount, true
+	}
+
+	if exchangedAmount, exists := a.exchangeRatesConverter.GetTransactionExchangedAmount(totalAmount, currency, "", a.defaultCurrency); exists {
+		return exchangedAmount, true
+	}
+
+	a.unconvertedCurrencies[currency] = true
+	return 0, false
+}
+
+func (a *mcpTransactionStatisticsAggregator) getUnconvertedCurrencies() []string {
+	if len(a.unconvertedCurrencies) == 0 {
+		return nil
+	}
+
+	currencies := make([]string, 0, len(a.unconvertedCurrencies))
+
+	for currency := range a.unconvertedCurrencies {
+		currencies = append(currencies, currency)
+	}
+
+	sort.Strings(currencies)
+
+	return currencies
+}
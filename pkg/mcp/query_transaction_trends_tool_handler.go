@@ -0,0 +1,155 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPQueryTransactionTrendsRequest represents all parameters of the query transaction trends request
+type MCPQueryTransactionTrendsRequest struct {
+	StartYearMonth        string `json:"start_year_month" jsonschema_description:"Start year and month for the trends in YYYY-MM format (e.g. 2023-01)"`
+	EndYearMonth          string `json:"end_year_month" jsonschema_description:"End year and month for the trends in YYYY-MM format (e.g. 2023-12)"`
+	Type                  string `json:"type,omitempty" jsonschema:"enum=income,enum=expense" jsonschema_description:"Transaction type to filter by (income, expense) (optional, leave empty for both)"`
+	GroupBy               string `json:"group_by,omitempty" jsonschema:"enum=category,enum=primary_category,enum=account,default=category" jsonschema_description:"Dimension to group the monthly amounts by (category, primary_category, account) (default: category)"`
+	SecondaryCategoryName string `json:"category_name,omitempty" jsonschema_description:"Secondary category name to filter transactions by (optional)"`
+	AccountName           string `json:"account_name,omitempty" jsonschema_description:"Account name to filter transactions by (optional)"`
+	Keyword               string `json:"keyword,omitempty" jsonschema_description:"Keyword to search in transaction description (optional)"`
+}
+
+// MCPQueryTransactionTrendsResponse represents the response structure for querying transaction trends
+type MCPQueryTransactionTrendsResponse struct {
+	Currency              string                            `json:"currency" jsonschema_description:"Currency code of all amounts in the response, which is the default currency of the current user (e.g. USD)"`
+	Months                []*MCPTransactionMonthlyTrendInfo `json:"months" jsonschema_description:"Monthly total amounts sorted by month in ascending order, months without transactions are omitted"`
+	UnconvertedCurrencies []string                          `json:"unconverted_currencies,omitempty" jsonschema_description:"Currencies which have no exchange rate to the default currency, amounts in these currencies are not included in the trends"`
+}
+
+// MCPTransactionMonthlyTrendInfo defines the structure of monthly transaction trend information
+type MCPTransactionMonthlyTrendInfo struct {
+	YearMonth    string                         `json:"year_month" jsonschema_description:"Year and month in YYYY-MM format (e.g. 2023-01)"`
+	TotalIncome  string                         `json:"total_income" jsonschema_description:"Total income amount of the month"`
+	TotalExpense string                         `json:"total_expense" jsonschema_description:"Total expense amount of the month"`
+	Items        []*MCPTransactionStatisticItem `json:"items" jsonschema_description:"Total amounts of the month grouped by the specified dimension, sorted by amount in descending order"`
+}
+
+type mcpQueryTransactionTrendsToolHandler struct{}
+
+var MCPQueryTransactionTrendsToolHandler = &mcpQueryTransactionTrendsToolHandler{}
+
+// Name returns the name of the MCP tool
+func (h *mcpQueryTransactionTrendsToolHandler) Name() string {
+	return "query_transaction_trends"
+}
+
+// Description returns the description of the MCP tool
+func (h *mcpQueryTransactionTrendsToolHandler) Description() string {
+	return "Query monthly income and expense amounts grouped by category or account within a month range, all amounts are converted to the default currency of the current user."
+}
+
+// InputType returns the input type for the MCP tool request
+func (h *mcpQueryTransactionTrendsToolHandler) InputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionTrendsRequest{})
+}
+
+// OutputType returns the output type for the MCP tool response
+func (h *mcpQueryTransactionTrendsToolHandler) OutputType() reflect.Type {
+	return reflect.TypeOf(&MCPQueryTransactionTrendsResponse{})
+}
+
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryTransactionTrendsToolHandler) IsReadOnly() bool {
+	return true
+}
+
+// Handle processes the MCP call tool request and returns the response
+func (h *mcpQueryTransactionTrendsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
+	var queryTrendsRequest MCPQueryTransactionTrendsRequest
+
+	if callToolReq.Arguments != nil {
+		if err := json.Unmarshal(callToolReq.Arguments, &queryTrendsRequest); err != nil {
+			return nil, nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+		}
+	} else {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	yearMonthRange := &models.YearMonthRangeRequest{
+		StartYearMonth: queryTrendsRequest.StartYearMonth,
+		EndYearMonth:   queryTrendsRequest.EndYearMonth,
+	}
+
+	startYear, startMonth, endYear, endMonth, err := yearMonthRange.GetNumericYearMonthRange()
+
+	if err != nil || startYear <= 0 || endYear <= 0 {
+		return nil, nil, errs.ErrIncompleteOrIncorrectSubmission
+	}
+
+	uid := user.Uid
+	startUnixTime := time.Date(int(startYear), time.Month(startMonth), 1, 0, 0, 0, 0, time.UTC).Unix()
+	endUnixTime := time.Date(int(endYear), time.Month(endMonth)+1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	aggregator, err := createNewMCPTransactionStatisticsAggregator(c, user, currentConfig, services, queryTrendsRequest.Type, queryTrendsRequest.GroupBy, queryTrendsRequest.SecondaryCategoryName, queryTrendsRequest.AccountName, startUnixTime, endUnixTime)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// there is no client timezone in mcp requests, so each transaction is counted in the month of its own timezone
+	allMonthlyTotalAmounts, err := services.GetTransactionService().GetAccountsAndCategoriesMonthlyIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, queryTrendsRequest.Keyword, 0, true)
+
+	if err != nil {
+		log.Errorf(c, "[query_transaction_trends.Handle] failed to get accounts and categories monthly income and expense for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, nil, err
+	}
+
+	allYearMonths := make([]int32, 0, len(allMonthlyTotalAmounts))
+
+	for yearMonth := range allMonthlyTotalAmounts {
+		allYearMonths = append(allYearMonths, yearMonth)
+	}
+
+	sort.Slice(allYearMonths, func(i, j int) bool {
+		return allYearMonths[i] < allYearMonths[j]
+	})
+
+	response := MCPQueryTransactionTrendsResponse{
+		Currency: user.DefaultCurrency,
+		Months:   make([]*MCPTransactionMonthlyTrendInfo, 0, len(allYearMonths)),
+	}
+
+	for i := 0; i < len(allYearMonths); i++ {
+		yearMonth := allYearMonths[i]
+		totalIncome, totalExpense, items := aggregator.aggregate(allMonthlyTotalAmounts[yearMonth])
+
+		if len(items) == 0 {
+			continue
+		}
+
+		response.Months = append(response.Months, &MCPTransactionMonthlyTrendInfo{
+			YearMonth:    fmt.Sprintf("%04d-%02d", yearMonth/100, yearMonth%100),
+			TotalIncome:  utils.FormatAmount(totalIncome),
+			TotalExpense: utils.FormatAmount(totalExpense),
+			Items:        items,
+		})
+	}
+
+	response.UnconvertedCurrencies = aggregator.getUnconvertedCurrencies()
+
+	content, err := json.Marshal(response)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response, []*MCPTextContent{
+		NewMCPTextContent(string(content)),
+	}, nil
+}
@@ -22,6 +22,7 @@ type MCPQueryTransactionsRequest struct {
 	SecondaryCategoryName string `json:"category_name,omitempty" jsonschema_description:"Secondary category name to filter transactions by (optional)"`
 	AccountName           string `json:"account_name,omitempty" jsonschema_description:"Account name to filter transactions by (optional)"`
 	Keyword               string `json:"keyword,omitempty" jsonschema_description:"Keyword to search in transaction description (optional)"`
+	Query                 string `json:"query,omitempty" jsonschema_description:"Search query to filter transactions (optional), plain words and quoted phrases match the transaction description, supports field filters (tag:, account:, category:, payee:, type:, comment:), amount comparisons and ranges (e.g. amount>100, amount:10..20), AND / OR operators, parentheses and negation by NOT or \"-\" prefix, e.g. amount>100 AND tag:travel AND -account:Visa"`
 	Count                 int32  `json:"count,omitempty" jsonschema:"default=100" jsonschema_description:"Maximum number of results to return (default: 100)"`
 	Page                  int32  `json:"page,omitempty" jsonschema:"default=1" jsonschema_description:"Page number for pagination (default: 1)"`
 	ResponseFields        string `json:"response_fields,omitempty" jsonschema_description:"Comma-separated list of fields to include in the response (optional, leave empty for all fields, available fields: time, currency, category_name, account_name, comment)"`
@@ -37,6 +38,7 @@ type MCPQueryTransactionsResponse struct {
 
 // MCPTransactionInfo defines the structure of transaction information
 type MCPTransactionInfo struct {
+	Id                     string `json:"id" jsonschema_description:"Transaction ID, which can be used to modify or delete the transaction"`
 	Time                   string `json:"time,omitempty" jsonschema_description:"Time of the transaction in RFC 3339 format (e.g. 2023-01-01T12:00:00Z)"`
 	Type                   string `json:"type" jsonschema:"enum=income,enum=expense,enum=transfer" jsonschema_description:"Transaction type (income, expense, transfer)"`
 	Amount                 string `json:"amount" jsonschema_description:"Amount of the transaction in the specified currency"`
@@ -73,6 +75,11 @@ func (h *mcpQueryTransactionsToolHandler) OutputType() reflect.Type {
 	return reflect.TypeOf(&MCPQueryTransactionsResponse{})
 }
 
+// IsReadOnly returns whether the MCP tool only reads data without any modification
+func (h *mcpQueryTransactionsToolHandler) IsReadOnly() bool {
+	return true
+}
+
 // Handle processes the MCP call tool request and returns the response
 func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq *MCPCallToolRequest, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (any, []*MCPTextContent, error) {
 	var queryTransactionsRequest MCPQueryTransactionsRequest
@@ -155,14 +162,21 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 		}
 	}
 
-	totalCount, err := services.GetTransactionService().GetTransactionCount(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", queryTransactionsRequest.Keyword)
+	searchQuery, err := h.parseSearchQuery(c, uid, queryTransactionsRequest.Query, allAccounts, allCategories, services)
+
+	if err != nil {
+		log.Warnf(c, "[query_transactions.Handle] parse search query \"%s\" failed, because %s", queryTransactionsRequest.Query, err.Error())
+		return nil, nil, err
+	}
+
+	totalCount, err := services.GetTransactionService().GetTransactionCount(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, "", queryTransactionsRequest.Keyword, searchQuery)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionListHandler] failed to get transaction count for user \"uid:%d\", because %s", uid, err.Error())
 		return nil, nil, err
 	}
 
-	transactions, err := services.GetTransactionService().GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", queryTransactionsRequest.Keyword, queryTransactionsRequest.Page, queryTransactionsRequest.Count, false, true)
+	transactions, err := services.GetTransactionService().GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, filterCategoryIds, filterAccountIds, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, "", queryTransactionsRequest.Keyword, searchQuery, queryTransactionsRequest.Page, queryTransactionsRequest.Count, false, true)
 	structuredResponse, response, err := h.createNewMCPQueryTransactionsResponse(c, &queryTransactionsRequest, transactions, totalCount, services.GetAccountService().GetAccountMapByList(allAccounts), services.GetTransactionCategoryService().GetCategoryMapByList(allCategories))
 
 	if err != nil {
@@ -172,6 +186,37 @@ func (h *mcpQueryTransactionsToolHandler) Handle(c *core.WebContext, callToolReq
 	return structuredResponse, response, nil
 }
 
+func (h *mcpQueryTransactionsToolHandler) parseSearchQuery(c *core.WebContext, uid int64, query string, allAccounts []*models.Account, allCategories []*models.TransactionCategory, services MCPAvailableServices) (*models.TransactionSearchQueryNode, error) {
+	searchQuery, err := models.ParseTransactionSearchQuery(query)
+
+	if err != nil || searchQuery == nil {
+		return nil, err
+	}
+
+	var tags []*models.TransactionTag
+	var payees []*models.Payee
+
+	if searchQuery.HasField(models.TRANSACTION_SEARCH_QUERY_FIELD_TAG) {
+		tags, err = services.GetTransactionTagService().GetAllTagsByUid(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if searchQuery.HasField(models.TRANSACTION_SEARCH_QUERY_FIELD_PAYEE) {
+		payees, err = services.GetPayeeService().GetAllPayeesByUid(c, uid)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	searchQuery.ResolveNames(allAccounts, allCategories, tags, payees)
+
+	return searchQuery, nil
+}
+
 func (h *mcpQueryTransactionsToolHandler) createNewMCPQueryTransactionsResponse(c *core.WebContext, queryTransactionsRequest *MCPQueryTransactionsRequest, transactions []*models.Transaction, totalCount int64, accountsMap map[int64]*models.Account, categoriesMap map[int64]*models.TransactionCategory) (any, []*MCPTextContent, error) {
 	response := MCPQueryTransactionsResponse{
 		TotalCount:   totalCount,
@@ -191,6 +236,7 @@ func (h *mcpQueryTransactionsToolHandler) createNewMCPQueryTransactionsResponse(
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 		transactionInfo := MCPTransactionInfo{
+			Id:     utils.Int64ToString(transaction.TransactionId),
 			Amount: utils.FormatAmount(transaction.Amount),
 		}
 
@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type mcpTransactionCategoriesResourceHandler struct{}
+
+var MCPTransactionCategoriesResourceHandler = &mcpTransactionCategoriesResourceHandler{}
+
+// URI returns the uri of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) URI() string {
+	return "ezbookkeeping://categories"
+}
+
+// Name returns the name of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Name() string {
+	return "categories"
+}
+
+// Description returns the description of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Description() string {
+	return "All income, expense and transfer categories of the current user in ezBookkeeping, secondary category names are grouped by primary category name."
+}
+
+// MimeType returns the mime type of the MCP resource contents
+func (h *mcpTransactionCategoriesResourceHandler) MimeType() string {
+	return mcpJSONResourceMimeType
+}
+
+// Read returns the contents of the MCP resource
+func (h *mcpTransactionCategoriesResourceHandler) Read(c *core.WebContext, uri string, user *models.User, currentConfig *settings.Config, services MCPAvailableServices) (*MCPTextResourceContents, error) {
+	return readMCPResourceFromTool(c, uri, h.MimeType(), MCPQueryAllTransactionCategoriesToolHandler, nil, user, currentConfig, services)
+}
@@ -1,6 +1,9 @@
 package middlewares
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -20,6 +23,26 @@ const (
 	TOKEN_SOURCE_TYPE_COOKIE   TokenSourceType = 3
 )
 
+type personalAccessTokenRoutePermission struct {
+	pathPrefix      string
+	readPermission  core.TokenPermissionType
+	writePermission core.TokenPermissionType
+}
+
+// personalAccessTokenRoutePermissions defines the permissions which personal access token requires for each route group, other routes cannot be accessed by personal access token
+var personalAccessTokenRoutePermissions = []*personalAccessTokenRoutePermission{
+	{"/api/v1/accounts/", core.TOKEN_PERMISSION_TYPE_ACCOUNTS_READ, core.TOKEN_PERMISSION_TYPE_ACCOUNTS_WRITE},
+	{"/api/v1/transactions/", core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ, core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE},
+	{"/api/v1/transaction/pictures/", core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ, core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE},
+	{"/api/v1/transaction/templates/", core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ, core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE},
+	{"/api/v1/transaction/rules/", core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ, core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE},
+	{"/api/v1/transaction/categories/", core.TOKEN_PERMISSION_TYPE_CATEGORIES_READ, core.TOKEN_PERMISSION_TYPE_CATEGORIES_WRITE},
+	{"/api/v1/transaction/tags/", core.TOKEN_PERMISSION_TYPE_TAGS_READ, core.TOKEN_PERMISSION_TYPE_TAGS_WRITE},
+	{"/api/v1/payees/", core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_READ, core.TOKEN_PERMISSION_TYPE_TRANSACTIONS_WRITE},
+	{"/api/v1/exchange_rates/", core.TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_READ, core.TOKEN_PERMISSION_TYPE_EXCHANGE_RATES_WRITE},
+	{"/api/v1/stock_prices/", core.TOKEN_PERMISSION_TYPE_INVESTMENTS_READ, core.TOKEN_PERMISSION_TYPE_INVESTMENTS_WRITE},
+}
+
 // JWTAuthorization verifies whether current request is valid by jwt token in header
 func JWTAuthorization(c *core.WebContext) {
 	jwtAuthorization(c, TOKEN_SOURCE_TYPE_HEADER)
@@ -92,6 +115,25 @@ func JWTResetPasswordAuthorization(c *core.WebContext) {
 	c.Next()
 }
 
+// JWTOAuth2CallbackAuthorization verifies whether current request is valid by oauth2 callback token in header
+func JWTOAuth2CallbackAuthorization(c *core.WebContext) {
+	claims, err := getTokenClaims(c, TOKEN_SOURCE_TYPE_HEADER)
+
+	if err != nil {
+		utils.PrintJsonErrorResult(c, err)
+		return
+	}
+
+	if claims.Type != core.USER_TOKEN_TYPE_OAUTH2_CALLBACK {
+		log.Warnf(c, "[authorization.JWTOAuth2CallbackAuthorization] user \"uid:%d\" token type (%d) is not oauth2 callback token", claims.Uid, claims.Type)
+		utils.PrintJsonErrorResult(c, errs.ErrCurrentInvalidTokenType)
+		return
+	}
+
+	c.SetTokenClaims(claims)
+	c.Next()
+}
+
 // JWTMCPAuthorization verifies whether current request is valid by jwt mcp token in header
 func JWTMCPAuthorization(c *core.WebContext) {
 	claims, err := getTokenClaims(c, TOKEN_SOURCE_TYPE_HEADER)
@@ -125,7 +167,13 @@ func jwtAuthorization(c *core.WebContext, source TokenSourceType) {
 		return
 	}
 
-	if claims.Type != core.USER_TOKEN_TYPE_NORMAL {
+	if claims.Type == core.USER_TOKEN_TYPE_PERSONAL_ACCESS && source == TOKEN_SOURCE_TYPE_HEADER {
+		if !hasPersonalAccessTokenPermission(c, claims) {
+			log.Warnf(c, "[authorization.jwtAuthorization] user \"uid:%d\" personal access token (permissions: %s) cannot access \"%s %s\"", claims.Uid, claims.Permissions.String(), c.Request.Method, c.FullPath())
+			utils.PrintJsonErrorResult(c, errs.ErrCurrentTokenPermissionDenied)
+			return
+		}
+	} else if claims.Type != core.USER_TOKEN_TYPE_NORMAL {
 		log.Warnf(c, "[authorization.jwtAuthorization] user \"uid:%d\" token type (%d) is invalid", claims.Uid, claims.Type)
 		utils.PrintJsonErrorResult(c, errs.ErrCurrentInvalidTokenType)
 		return
@@ -135,6 +183,26 @@ func jwtAuthorization(c *core.WebContext, source TokenSourceType) {
 	c.Next()
 }
 
+func hasPersonalAccessTokenPermission(c *core.WebContext, claims *core.UserTokenClaims) bool {
+	path := c.FullPath()
+
+	for i := 0; i < len(personalAccessTokenRoutePermissions); i++ {
+		routePermission := personalAccessTokenRoutePermissions[i]
+
+		if !strings.HasPrefix(path, routePermission.pathPrefix) {
+			continue
+		}
+
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return claims.Permissions.Contains(routePermission.readPermission)
+		}
+
+		return claims.Permissions.Contains(routePermission.writePermission)
+	}
+
+	return false
+}
+
 func getTokenClaims(c *core.WebContext, source TokenSourceType) (*core.UserTokenClaims, *errs.Error) {
 	token, claims, err := parseToken(c, source)
 
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/mcp"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MCPSession verifies whether the mcp session id in header belongs to current mcp token if it is provided
+func MCPSession(c *core.WebContext) {
+	sessionId := c.GetHeader(mcp.MCPSessionIdHeaderName)
+
+	if sessionId == "" {
+		c.Next()
+		return
+	}
+
+	claims := c.GetTokenClaims()
+
+	if mcp.Sessions.GetSession(sessionId, claims.Uid, claims.UserTokenId) == nil {
+		log.Warnf(c, "[mcp_session.MCPSession] mcp session \"%s\" of user \"uid:%d\" does not exist or has expired", sessionId, claims.Uid)
+		utils.PrintJsonErrorResult(c, errs.ErrMCPSessionNotFound)
+		return
+	}
+
+	c.Next()
+}
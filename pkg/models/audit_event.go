@@ -0,0 +1,137 @@
+package models
+
+import "fmt"
+
+// AuditEventMaxUserAgentLength represents the maximum size of user agent stored in database
+const AuditEventMaxUserAgentLength = 255
+
+// AuditEventMaxDetailLength represents the maximum size of event detail stored in database
+const AuditEventMaxDetailLength = 255
+
+// AuditEventType represents the type of security audit event
+type AuditEventType byte
+
+// Security audit event types
+const (
+	AUDIT_EVENT_TYPE_LOGIN                                 AuditEventType = 1
+	AUDIT_EVENT_TYPE_LOGIN_FAILED                          AuditEventType = 2
+	AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFIED                   AuditEventType = 3
+	AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFY_FAILED              AuditEventType = 4
+	AUDIT_EVENT_TYPE_TWO_FACTOR_ENABLED                    AuditEventType = 5
+	AUDIT_EVENT_TYPE_TWO_FACTOR_DISABLED                   AuditEventType = 6
+	AUDIT_EVENT_TYPE_TWO_FACTOR_RECOVERY_CODES_REGENERATED AuditEventType = 7
+	AUDIT_EVENT_TYPE_LOGOUT                                AuditEventType = 8
+	AUDIT_EVENT_TYPE_TOKEN_GENERATED                       AuditEventType = 9
+	AUDIT_EVENT_TYPE_TOKEN_REVOKED                         AuditEventType = 10
+	AUDIT_EVENT_TYPE_ALL_TOKENS_REVOKED                    AuditEventType = 11
+	AUDIT_EVENT_TYPE_DATA_EXPORTED                         AuditEventType = 12
+	AUDIT_EVENT_TYPE_ALL_DATA_CLEARED                      AuditEventType = 13
+	AUDIT_EVENT_TYPE_ALL_TRANSACTIONS_CLEARED              AuditEventType = 14
+	AUDIT_EVENT_TYPE_PASSWORD_RESET_REQUESTED              AuditEventType = 15
+	AUDIT_EVENT_TYPE_PASSWORD_RESET                        AuditEventType = 16
+	AUDIT_EVENT_TYPE_PASSKEY_REGISTERED                    AuditEventType = 17
+	AUDIT_EVENT_TYPE_PASSKEY_DELETED                       AuditEventType = 18
+	AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_LINKED               AuditEventType = 19
+	AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_UNLINKED             AuditEventType = 20
+)
+
+// String returns a textual representation of the audit event type
+func (t AuditEventType) String() string {
+	switch t {
+	case AUDIT_EVENT_TYPE_LOGIN:
+		return "Login"
+	case AUDIT_EVENT_TYPE_LOGIN_FAILED:
+		return "Login Failed"
+	case AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFIED:
+		return "Two-Factor Verified"
+	case AUDIT_EVENT_TYPE_TWO_FACTOR_VERIFY_FAILED:
+		return "Two-Factor Verify Failed"
+	case AUDIT_EVENT_TYPE_TWO_FACTOR_ENABLED:
+		return "Two-Factor Enabled"
+	case AUDIT_EVENT_TYPE_TWO_FACTOR_DISABLED:
+		return "Two-Factor Disabled"
+	case AUDIT_EVENT_TYPE_TWO_FACTOR_RECOVERY_CODES_REGENERATED:
+		return "Two-Factor Recovery Codes Regenerated"
+	case AUDIT_EVENT_TYPE_LOGOUT:
+		return "Logout"
+	case AUDIT_EVENT_TYPE_TOKEN_GENERATED:
+		return "Token Generated"
+	case AUDIT_EVENT_TYPE_TOKEN_REVOKED:
+		return "Token Revoked"
+	case AUDIT_EVENT_TYPE_ALL_TOKENS_REVOKED:
+		return "All Tokens Revoked"
+	case AUDIT_EVENT_TYPE_DATA_EXPORTED:
+		return "Data Exported"
+	case AUDIT_EVENT_TYPE_ALL_DATA_CLEARED:
+		return "All Data Cleared"
+	case AUDIT_EVENT_TYPE_ALL_TRANSACTIONS_CLEARED:
+		return "All Transactions Cleared"
+	case AUDIT_EVENT_TYPE_PASSWORD_RESET_REQUESTED:
+		return "Password Reset Requested"
+	case AUDIT_EVENT_TYPE_PASSWORD_RESET:
+		return "Password Reset"
+	case AUDIT_EVENT_TYPE_PASSKEY_REGISTERED:
+		return "Passkey Registered"
+	case AUDIT_EVENT_TYPE_PASSKEY_DELETED:
+		return "Passkey Deleted"
+	case AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_LINKED:
+		return "External Account Linked"
+	case AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_UNLINKED:
+		return "External Account Unlinked"
+	default:
+		return fmt.Sprintf("Invalid(%d)", int(t))
+	}
+}
+
+// AuditEvent represents security audit event data stored in database
+type AuditEvent struct {
+	EventId         int64          `xorm:"PK"`
+	Uid             int64          `xorm:"INDEX(IDX_audit_event_uid_created_time) NOT NULL"`
+	EventType       AuditEventType `xorm:"TINYINT NOT NULL"`
+	IpAddress       string         `xorm:"VARCHAR(45)"`
+	UserAgent       string         `xorm:"VARCHAR(255)"`
+	RequestId       string         `xorm:"VARCHAR(36)"`
+	Detail          string         `xorm:"VARCHAR(255)"`
+	CreatedUnixTime int64          `xorm:"INDEX(IDX_audit_event_uid_created_time) INDEX(IDX_audit_event_created_time) NOT NULL"`
+}
+
+// AuditEventListRequest represents all parameters of audit event listing request
+type AuditEventListRequest struct {
+	Page  int32 `form:"page" binding:"omitempty,min=1"`
+	Count int32 `form:"count" binding:"required,min=1,max=50"`
+}
+
+// AuditEventInfoResponse represents a view-object of security audit event
+type AuditEventInfoResponse struct {
+	Id        int64          `json:"id,string"`
+	EventType AuditEventType `json:"eventType"`
+	IpAddress string         `json:"ipAddress"`
+	UserAgent string         `json:"userAgent"`
+	RequestId string         `json:"requestId"`
+	Detail    string         `json:"detail,omitempty"`
+	CreatedAt int64          `json:"createdAt"`
+}
+
+// AuditEventInfoPageWrapperResponse represents a response of audit events which contains items and total count
+type AuditEventInfoPageWrapperResponse struct {
+	Items      []*AuditEventInfoResponse `json:"items"`
+	TotalCount int64                     `json:"totalCount"`
+}
+
+// TableName returns the table name of AuditEvent
+func (e *AuditEvent) TableName() string {
+	return "ebk_audit_events"
+}
+
+// ToAuditEventInfoResponse returns a view-object according to database model
+func (e *AuditEvent) ToAuditEventInfoResponse() *AuditEventInfoResponse {
+	return &AuditEventInfoResponse{
+		Id:        e.EventId,
+		EventType: e.EventType,
+		IpAddress: e.IpAddress,
+		UserAgent: e.UserAgent,
+		RequestId: e.RequestId,
+		Detail:    e.Detail,
+		CreatedAt: e.CreatedUnixTime,
+	}
+}
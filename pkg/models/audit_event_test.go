@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEventTypeString(t *testing.T) {
+	assert.Equal(t, "Login", AUDIT_EVENT_TYPE_LOGIN.String())
+	assert.Equal(t, "Two-Factor Recovery Codes Regenerated", AUDIT_EVENT_TYPE_TWO_FACTOR_RECOVERY_CODES_REGENERATED.String())
+	assert.Equal(t, "Password Reset", AUDIT_EVENT_TYPE_PASSWORD_RESET.String())
+	assert.Equal(t, "Passkey Registered", AUDIT_EVENT_TYPE_PASSKEY_REGISTERED.String())
+	assert.Equal(t, "External Account Unlinked", AUDIT_EVENT_TYPE_EXTERNAL_ACCOUNT_UNLINKED.String())
+	assert.Equal(t, "Invalid(0)", AuditEventType(0).String())
+	assert.Equal(t, "Invalid(21)", AuditEventType(21).String())
+}
+
+func TestAuditEventToAuditEventInfoResponse(t *testing.T) {
+	event := &AuditEvent{
+		EventId:         1234,
+		Uid:             1,
+		EventType:       AUDIT_EVENT_TYPE_DATA_EXPORTED,
+		IpAddress:       "127.0.0.1",
+		UserAgent:       "test",
+		RequestId:       "00000000-0000-0000-0000-000000000000",
+		Detail:          "csv",
+		CreatedUnixTime: 1700000000,
+	}
+
+	actualResponse := event.ToAuditEventInfoResponse()
+	assert.Equal(t, int64(1234), actualResponse.Id)
+	assert.Equal(t, AUDIT_EVENT_TYPE_DATA_EXPORTED, actualResponse.EventType)
+	assert.Equal(t, "127.0.0.1", actualResponse.IpAddress)
+	assert.Equal(t, "test", actualResponse.UserAgent)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000", actualResponse.RequestId)
+	assert.Equal(t, "csv", actualResponse.Detail)
+	assert.Equal(t, int64(1700000000), actualResponse.CreatedAt)
+}
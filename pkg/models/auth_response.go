@@ -4,6 +4,7 @@ package models
 type AuthResponse struct {
 	Token                    string                        `json:"token"`
 	Need2FA                  bool                          `json:"need2FA"`
+	TwoFactorMethods         []string                      `json:"twoFactorMethods,omitempty"`
 	User                     *UserBasicInfo                `json:"user"`
 	ApplicationCloudSettings *ApplicationCloudSettingSlice `json:"applicationCloudSettings,omitempty"`
 	NotificationContent      string                        `json:"notificationContent,omitempty"`
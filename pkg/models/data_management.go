@@ -23,6 +23,7 @@ type ExportTransactionDataRequest struct {
 	AccountIds    string                   `form:"account_ids"`
 	TagIds        string                   `form:"tag_ids"`
 	TagFilterType TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	PayeeIds      string                   `form:"payee_ids"`
 	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword       string                   `form:"keyword"`
 	MaxTime       int64                    `form:"max_time" binding:"min=0"` // Unix timestamp in seconds
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"strings"
 
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
@@ -42,6 +43,45 @@ type LatestExchangeRateResponse struct {
 	UpdateTime    int64                   `json:"updateTime"`
 	BaseCurrency  string                  `json:"baseCurrency"`
 	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+	Stale         bool                    `json:"stale,omitempty"`
+}
+
+// GetExchangedAmount returns the amount exchanged from the source currency to the target currency, and whether both exchange rates exist
+func (r *LatestExchangeRateResponse) GetExchangedAmount(amount int64, fromCurrency string, toCurrency string) (int64, bool) {
+	if fromCurrency == toCurrency || amount == 0 {
+		return amount, true
+	}
+
+	fromRate, fromRateExists := r.getExchangeRate(fromCurrency)
+	toRate, toRateExists := r.getExchangeRate(toCurrency)
+
+	if !fromRateExists || !toRateExists {
+		return 0, false
+	}
+
+	return int64(math.Round(float64(amount) * toRate / fromRate)), true
+}
+
+func (r *LatestExchangeRateResponse) getExchangeRate(currency string) (float64, bool) {
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		if r.ExchangeRates[i].Currency != currency {
+			continue
+		}
+
+		rate, err := utils.StringToFloat64(r.ExchangeRates[i].Rate)
+
+		if err != nil || rate <= 0 {
+			return 0, false
+		}
+
+		return rate, true
+	}
+
+	if currency == r.BaseCurrency {
+		return 1, true
+	}
+
+	return 0, false
 }
 
 // LatestExchangeRate represents a data pair of currency and exchange rate
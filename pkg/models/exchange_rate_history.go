@@ -0,0 +1,106 @@
+package models
+
+import "sort"
+
+// ExchangeRateHistory represents historical exchange rate data of a exchange rates data source stored in database
+type ExchangeRateHistory struct {
+	DataSource      string `xorm:"PK VARCHAR(32) NOT NULL"`
+	RateDate        string `xorm:"PK VARCHAR(10) NOT NULL"`
+	Currency        string `xorm:"PK VARCHAR(3) NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	UpdateUnixTime  int64
+	CreatedUnixTime int64
+}
+
+// HistoricalExchangeRateRequest represents all parameters of historical exchange rate data request
+type HistoricalExchangeRateRequest struct {
+	Date string `form:"date" binding:"required,len=10"`
+}
+
+// HistoricalExchangeRateResponse returns a view-object which contains the exchange rates published on the specified date
+type HistoricalExchangeRateResponse struct {
+	DataSource    string                  `json:"dataSource"`
+	Date          string                  `json:"date"`
+	UpdateTime    int64                   `json:"updateTime"`
+	BaseCurrency  string                  `json:"baseCurrency"`
+	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+}
+
+// TableName returns the table name of ExchangeRateHistory
+func (r *ExchangeRateHistory) TableName() string {
+	return "ebk_exchange_rates_history"
+}
+
+// ToLatestExchangeRate returns a data pair of currency and exchange rate according to database model
+func (r *ExchangeRateHistory) ToLatestExchangeRate() *LatestExchangeRate {
+	return &LatestExchangeRate{
+		Currency: r.Currency,
+		Rate:     r.Rate,
+	}
+}
+
+// ToHistoricalExchangeRateResponse returns a view-object according to database models of the same data source and date
+func ToHistoricalExchangeRateResponse(exchangeRateHistories []*ExchangeRateHistory) *HistoricalExchangeRateResponse {
+	if len(exchangeRateHistories) < 1 {
+		return nil
+	}
+
+	exchangeRates := make(LatestExchangeRateSlice, len(exchangeRateHistories))
+
+	for i := 0; i < len(exchangeRateHistories); i++ {
+		exchangeRates[i] = exchangeRateHistories[i].ToLatestExchangeRate()
+	}
+
+	sort.Sort(exchangeRates)
+
+	return &HistoricalExchangeRateResponse{
+		DataSource:    exchangeRateHistories[0].DataSource,
+		Date:          exchangeRateHistories[0].RateDate,
+		UpdateTime:    exchangeRateHistories[0].UpdateUnixTime,
+		BaseCurrency:  exchangeRateHistories[0].BaseCurrency,
+		ExchangeRates: exchangeRates,
+	}
+}
+
+// ToHistoricalExchangeRateResponses returns view-objects of every date ordered by date according to database models of the same data source
+func ToHistoricalExchangeRateResponses(exchangeRateHistories []*ExchangeRateHistory) []*HistoricalExchangeRateResponse {
+	exchangeRateHistoriesByDate := make(map[string][]*ExchangeRateHistory)
+
+	for i := 0; i < len(exchangeRateHistories); i++ {
+		exchangeRateHistory := exchangeRateHistories[i]
+		exchangeRateHistoriesByDate[exchangeRateHistory.RateDate] = append(exchangeRateHistoriesByDate[exchangeRateHistory.RateDate], exchangeRateHistory)
+	}
+
+	historicalExchangeRateResps := make([]*HistoricalExchangeRateResponse, 0, len(exchangeRateHistoriesByDate))
+
+	for _, dailyExchangeRateHistories := range exchangeRateHistoriesByDate {
+		historicalExchangeRateResps = append(historicalExchangeRateResps, ToHistoricalExchangeRateResponse(dailyExchangeRateHistories))
+	}
+
+	sort.Slice(historicalExchangeRateResps, func(i, j int) bool {
+		return historicalExchangeRateResps[i].Date < historicalExchangeRateResps[j].Date
+	})
+
+	return historicalExchangeRateResps
+}
+
+// CreateExchangeRateHistories returns historical exchange rate database models according to the exchange rates response of the data source
+func CreateExchangeRateHistories(dataSource string, rateDate string, exchangeRateResp *LatestExchangeRateResponse) []*ExchangeRateHistory {
+	exchangeRateHistories := make([]*ExchangeRateHistory, 0, len(exchangeRateResp.ExchangeRates))
+
+	for i := 0; i < len(exchangeRateResp.ExchangeRates); i++ {
+		exchangeRate := exchangeRateResp.ExchangeRates[i]
+
+		exchangeRateHistories = append(exchangeRateHistories, &ExchangeRateHistory{
+			DataSource:     dataSource,
+			RateDate:       rateDate,
+			Currency:       exchangeRate.Currency,
+			BaseCurrency:   exchangeRateResp.BaseCurrency,
+			Rate:           exchangeRate.Rate,
+			UpdateUnixTime: exchangeRateResp.UpdateTime,
+		})
+	}
+
+	return exchangeRateHistories
+}
@@ -25,3 +25,74 @@ func TestLatestExchangeRateSliceLess(t *testing.T) {
 	assert.Equal(t, "EUR", latestExchangeRateSlice[1].Currency)
 	assert.Equal(t, "USD", latestExchangeRateSlice[2].Currency)
 }
+
+func TestLatestExchangeRateResponseGetExchangedAmount(t *testing.T) {
+	latestExchangeRateResponse := &LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			&LatestExchangeRate{Currency: "USD", Rate: "1.25"},
+			&LatestExchangeRate{Currency: "CNY", Rate: "8"},
+			&LatestExchangeRate{Currency: "JPY", Rate: "invalid"},
+		},
+	}
+
+	amount, exists := latestExchangeRateResponse.GetExchangedAmount(1000, "EUR", "USD")
+	assert.True(t, exists)
+	assert.Equal(t, int64(1250), amount)
+
+	amount, exists = latestExchangeRateResponse.GetExchangedAmount(1250, "USD", "EUR")
+	assert.True(t, exists)
+	assert.Equal(t, int64(1000), amount)
+
+	amount, exists = latestExchangeRateResponse.GetExchangedAmount(125, "USD", "CNY")
+	assert.True(t, exists)
+	assert.Equal(t, int64(800), amount)
+
+	amount, exists = latestExchangeRateResponse.GetExchangedAmount(100, "GBP", "GBP")
+	assert.True(t, exists)
+	assert.Equal(t, int64(100), amount)
+
+	_, exists = latestExchangeRateResponse.GetExchangedAmount(100, "GBP", "USD")
+	assert.False(t, exists)
+
+	_, exists = latestExchangeRateResponse.GetExchangedAmount(100, "JPY", "USD")
+	assert.False(t, exists)
+}
+
+func TestToHistoricalExchangeRateResponse(t *testing.T) {
+	exchangeRateResp := &LatestExchangeRateResponse{
+		UpdateTime:   1617285600,
+		BaseCurrency: "EUR",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "USD", Rate: "1.1746"},
+			{Currency: "CNY", Rate: "7.7195"},
+		},
+	}
+
+	exchangeRateHistories := CreateExchangeRateHistories("euro_central_bank", "2021-04-01", exchangeRateResp)
+	assert.Equal(t, 2, len(exchangeRateHistories))
+
+	actualResponse := ToHistoricalExchangeRateResponse(exchangeRateHistories)
+	assert.Equal(t, "euro_central_bank", actualResponse.DataSource)
+	assert.Equal(t, "2021-04-01", actualResponse.Date)
+	assert.Equal(t, int64(1617285600), actualResponse.UpdateTime)
+	assert.Equal(t, "EUR", actualResponse.BaseCurrency)
+	assert.Equal(t, "CNY", actualResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "7.7195", actualResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, "USD", actualResponse.ExchangeRates[1].Currency)
+	assert.Equal(t, "1.1746", actualResponse.ExchangeRates[1].Rate)
+}
+
+func TestToHistoricalExchangeRateResponse_Empty(t *testing.T) {
+	assert.Nil(t, ToHistoricalExchangeRateResponse(nil))
+}
+
+func TestCreateUserDatedCustomExchangeRate(t *testing.T) {
+	exchangeRate, err := CreateUserDatedCustomExchangeRate(1, "ARS", "2024-03-01", "1234.56789", "USD")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(123456789000), exchangeRate.Rate)
+	assert.Equal(t, "1234.56789", exchangeRate.ToUserDatedCustomExchangeRateInfoResponse().Rate)
+
+	_, err = CreateUserDatedCustomExchangeRate(1, "ARS", "2024-03-01", "abc", "USD")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,385 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// FixedIncomeInterestRateFactorInDatabase represents the factor of annual interest rate (in percent) stored in database
+const FixedIncomeInterestRateFactorInDatabase = 10000
+
+// FixedIncomeMaxInterestRate represents the maximum annual interest rate (in percent) of fixed income term
+const FixedIncomeMaxInterestRate = 100
+
+const fixedIncomeDaysPerYear = 365
+
+// FixedIncomeFrequency represents the compounding or payout frequency of fixed income term
+type FixedIncomeFrequency byte
+
+// Fixed income frequencies
+const (
+	FIXED_INCOME_FREQUENCY_MONTHLY       FixedIncomeFrequency = 1
+	FIXED_INCOME_FREQUENCY_QUARTERLY     FixedIncomeFrequency = 2
+	FIXED_INCOME_FREQUENCY_SEMI_ANNUALLY FixedIncomeFrequency = 3
+	FIXED_INCOME_FREQUENCY_ANNUALLY      FixedIncomeFrequency = 4
+	FIXED_INCOME_FREQUENCY_AT_MATURITY   FixedIncomeFrequency = 5
+)
+
+var fixedIncomeFrequencyMonths = map[FixedIncomeFrequency]int{
+	FIXED_INCOME_FREQUENCY_MONTHLY:       1,
+	FIXED_INCOME_FREQUENCY_QUARTERLY:     3,
+	FIXED_INCOME_FREQUENCY_SEMI_ANNUALLY: 6,
+	FIXED_INCOME_FREQUENCY_ANNUALLY:      12,
+	FIXED_INCOME_FREQUENCY_AT_MATURITY:   0,
+}
+
+// IsValid returns whether the fixed income frequency is valid
+func (f FixedIncomeFrequency) IsValid() bool {
+	_, exists := fixedIncomeFrequencyMonths[f]
+	return exists
+}
+
+// Months returns the month count of each period, or 0 if the frequency is at maturity
+func (f FixedIncomeFrequency) Months() int {
+	return fixedIncomeFrequencyMonths[f]
+}
+
+// FixedIncomeTerm represents the fixed income terms (certificate of deposit or bond) of an account or a bond holding stored in database,
+// the account id of the term of a bond holding is the same as the investment id (the ids of accounts and investments never conflict)
+type FixedIncomeTerm struct {
+	AccountId            int64                `xorm:"PK"`
+	InvestmentId         int64                `xorm:"NOT NULL DEFAULT 0"`
+	Uid                  int64                `xorm:"INDEX(IDX_fixed_income_term_uid_deleted_maturity_date) NOT NULL"`
+	Deleted              bool                 `xorm:"INDEX(IDX_fixed_income_term_uid_deleted_maturity_date) NOT NULL"`
+	Principal            int64                `xorm:"NOT NULL"`
+	InterestRate         int64                `xorm:"NOT NULL"`
+	CompoundingFrequency FixedIncomeFrequency `xorm:"NOT NULL"`
+	PayoutFrequency      FixedIncomeFrequency `xorm:"NOT NULL"`
+	StartDate            string               `xorm:"VARCHAR(10) NOT NULL"`
+	MaturityDate         string               `xorm:"VARCHAR(10) INDEX(IDX_fixed_income_term_uid_deleted_maturity_date) NOT NULL"`
+	InterestCategoryId   int64                `xorm:"NOT NULL"`
+	PayoutAccountId      int64                `xorm:"NOT NULL"`
+	LastPayoutDate       string               `xorm:"VARCHAR(10)"`
+	TimezoneUtcOffset    int16                `xorm:"NOT NULL"`
+	CreatedUnixTime      int64
+	UpdatedUnixTime      int64
+	DeletedUnixTime      int64
+}
+
+// FixedIncomeTermGetRequest represents all parameters of fixed income term getting request
+type FixedIncomeTermGetRequest struct {
+	AccountId    int64 `form:"accountId,string" binding:"omitempty,min=1"`
+	InvestmentId int64 `form:"investmentId,string" binding:"omitempty,min=1"`
+}
+
+// FixedIncomeTermSetRequest represents all parameters of fixed income term setting request
+type FixedIncomeTermSetRequest struct {
+	AccountId            int64                `json:"accountId,string" binding:"omitempty,min=1"`
+	InvestmentId         int64                `json:"investmentId,string" binding:"omitempty,min=1"`
+	Principal            int64                `json:"principal" binding:"required,min=1,max=99999999999"`
+	InterestRate         string               `json:"interestRate" binding:"required"`
+	CompoundingFrequency FixedIncomeFrequency `json:"compoundingFrequency" binding:"required"`
+	PayoutFrequency      FixedIncomeFrequency `json:"payoutFrequency" binding:"required"`
+	StartDate            string               `json:"startDate" binding:"required,len=10"`
+	MaturityDate         string               `json:"maturityDate" binding:"required,len=10"`
+	InterestCategoryId   int64                `json:"interestCategoryId,string" binding:"required,min=1"`
+	PayoutAccountId      int64                `json:"payoutAccountId,string" binding:"min=0"`
+}
+
+// FixedIncomeTermDeleteRequest represents all parameters of fixed income term deleting request
+type FixedIncomeTermDeleteRequest struct {
+	AccountId    int64 `json:"accountId,string" binding:"omitempty,min=1"`
+	InvestmentId int64 `json:"investmentId,string" binding:"omitempty,min=1"`
+}
+
+// FixedIncomeMaturityListRequest represents all parameters of upcoming fixed income maturities listing request
+type FixedIncomeMaturityListRequest struct {
+	Days int32 `form:"days" binding:"min=0,max=36500"`
+}
+
+// FixedIncomeTermInfoResponse represents a view-object of fixed income term
+type FixedIncomeTermInfoResponse struct {
+	AccountId              int64                `json:"accountId,string"`
+	InvestmentId           int64                `json:"investmentId,string,omitempty"`
+	Principal              int64                `json:"principal"`
+	InterestRate           string               `json:"interestRate"`
+	CompoundingFrequency   FixedIncomeFrequency `json:"compoundingFrequency"`
+	PayoutFrequency        FixedIncomeFrequency `json:"payoutFrequency"`
+	StartDate              string               `json:"startDate"`
+	MaturityDate           string               `json:"maturityDate"`
+	InterestCategoryId     int64                `json:"interestCategoryId,string"`
+	PayoutAccountId        int64                `json:"payoutAccountId,string"`
+	LastPayoutDate         string               `json:"lastPayoutDate"`
+	NextPayoutDate         string               `json:"nextPayoutDate"`
+	ProjectedMaturityValue int64                `json:"projectedMaturityValue"`
+	ProjectedTotalInterest int64                `json:"projectedTotalInterest"`
+}
+
+// FixedIncomeMaturityInfoResponse represents a view-object of upcoming fixed income maturity
+type FixedIncomeMaturityInfoResponse struct {
+	AccountId              int64  `json:"accountId,string"`
+	InvestmentId           int64  `json:"investmentId,string,omitempty"`
+	AccountName            string `json:"accountName"`
+	Currency               string `json:"currency"`
+	Principal              int64  `json:"principal"`
+	InterestRate           string `json:"interestRate"`
+	MaturityDate           string `json:"maturityDate"`
+	DaysToMaturity         int32  `json:"daysToMaturity"`
+	ProjectedMaturityValue int64  `json:"projectedMaturityValue"`
+	ProjectedTotalInterest int64  `json:"projectedTotalInterest"`
+}
+
+// FixedIncomeProjectionsResponse represents a view-object of fixed income projections of the whole portfolio
+type FixedIncomeProjectionsResponse struct {
+	Maturities []*FixedIncomeMaturityInfoResponse `json:"maturities"`
+}
+
+// FixedIncomeInterestPayout represents an interest payout of fixed income term on the specified date
+type FixedIncomeInterestPayout struct {
+	PayoutDate string
+	Amount     int64
+}
+
+// TableName returns the table name of FixedIncomeTerm
+func (t *FixedIncomeTerm) TableName() string {
+	return "ebk_fixed_income_terms"
+}
+
+// GetInterestRate returns the annual interest rate in percent
+func (t *FixedIncomeTerm) GetInterestRate() float64 {
+	return float64(t.InterestRate) / float64(FixedIncomeInterestRateFactorInDatabase)
+}
+
+// IsBondHolding returns whether the term belongs to a bond holding instead of an account
+func (t *FixedIncomeTerm) IsBondHolding() bool {
+	return t.InvestmentId > 0
+}
+
+// IsInterestPaidOut returns whether the interest is paid out to another account, the paid out interest does not compound
+func (t *FixedIncomeTerm) IsInterestPaidOut() bool {
+	return t.PayoutAccountId > 0 && t.PayoutAccountId != t.AccountId
+}
+
+// GetPayoutDates returns all payout dates of the term in ascending order, the last payout date is always the maturity date
+func (t *FixedIncomeTerm) GetPayoutDates() ([]string, error) {
+	startDate, err := time.Parse(time.DateOnly, t.StartDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	maturityDate, err := time.Parse(time.DateOnly, t.MaturityDate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payoutDates := make([]string, 0)
+	months := t.PayoutFrequency.Months()
+
+	if months > 0 {
+		for i := 1; ; i++ {
+			payoutDate := addMonthsWithoutOverflow(startDate, i*months)
+
+			if !payoutDate.Before(maturityDate) {
+				break
+			}
+
+			payoutDates = append(payoutDates, payoutDate.Format(time.DateOnly))
+		}
+	}
+
+	payoutDates = append(payoutDates, t.MaturityDate)
+
+	return payoutDates, nil
+}
+
+// GetNextPayoutDate returns the first payout date which has not been paid out yet, or empty if all interests have been paid out
+func (t *FixedIncomeTerm) GetNextPayoutDate() (string, error) {
+	payoutDates, err := t.GetPayoutDates()
+
+	if err != nil {
+		return "", err
+	}
+
+	for i := 0; i < len(payoutDates); i++ {
+		if strings.Compare(payoutDates[i], t.LastPayoutDate) > 0 {
+			return payoutDates[i], nil
+		}
+	}
+
+	return "", nil
+}
+
+// GetDueInterestPayouts returns all interest payouts which are due on or before the specified date and have not been paid out yet
+func (t *FixedIncomeTerm) GetDueInterestPayouts(currentDate string) ([]*FixedIncomeInterestPayout, error) {
+	payoutDates, err := t.GetPayoutDates()
+
+	if err != nil {
+		return nil, err
+	}
+
+	payouts := make([]*FixedIncomeInterestPayout, 0)
+	previousDate := t.StartDate
+
+	for i := 0; i < len(payoutDates); i++ {
+		payoutDate := payoutDates[i]
+
+		if strings.Compare(payoutDate, currentDate) > 0 {
+			break
+		}
+
+		if strings.Compare(payoutDate, t.LastPayoutDate) > 0 {
+			previousValue, err := t.GetValueAtDate(previousDate)
+
+			if err != nil {
+				return nil, err
+			}
+
+			currentValue, err := t.GetValueAtDate(payoutDate)
+
+			if err != nil {
+				return nil, err
+			}
+
+			payouts = append(payouts, &FixedIncomeInterestPayout{
+				PayoutDate: payoutDate,
+				Amount:     currentValue - previousValue,
+			})
+		}
+
+		previousDate = payoutDate
+	}
+
+	return payouts, nil
+}
+
+// GetValueAtDate returns the projected value (principal and accrued interest) of the term at the specified date,
+// the interest only compounds when it is kept in the account, otherwise it is simple interest on the principal
+func (t *FixedIncomeTerm) GetValueAtDate(date string) (int64, error) {
+	startDate, err := time.Parse(time.DateOnly, t.StartDate)
+
+	if err != nil {
+		return 0, err
+	}
+
+	currentDate, err := time.Parse(time.DateOnly, date)
+
+	if err != nil {
+		return 0, err
+	}
+
+	maturityDate, err := time.Parse(time.DateOnly, t.MaturityDate)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if currentDate.After(maturityDate) {
+		currentDate = maturityDate
+	}
+
+	if !currentDate.After(startDate) {
+		return t.Principal, nil
+	}
+
+	years := currentDate.Sub(startDate).Hours() / 24 / fixedIncomeDaysPerYear
+	rate := t.GetInterestRate() / 100
+	months := t.CompoundingFrequency.Months()
+
+	var value float64
+
+	if months > 0 && !t.IsInterestPaidOut() {
+		periodsPerYear := float64(12 / months)
+		value = float64(t.Principal) * math.Pow(1+rate/periodsPerYear, periodsPerYear*years)
+	} else {
+		value = float64(t.Principal) * (1 + rate*years)
+	}
+
+	return int64(math.Round(value)), nil
+}
+
+// GetProjectedMaturityValue returns the projected value of the term at maturity date
+func (t *FixedIncomeTerm) GetProjectedMaturityValue() (int64, error) {
+	return t.GetValueAtDate(t.MaturityDate)
+}
+
+// ToFixedIncomeTermInfoResponse returns a view-object according to database model
+func (t *FixedIncomeTerm) ToFixedIncomeTermInfoResponse() (*FixedIncomeTermInfoResponse, error) {
+	maturityValue, err := t.GetProjectedMaturityValue()
+
+	if err != nil {
+		return nil, err
+	}
+
+	nextPayoutDate, err := t.GetNextPayoutDate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &FixedIncomeTermInfoResponse{
+		AccountId:              t.AccountId,
+		InvestmentId:           t.InvestmentId,
+		Principal:              t.Principal,
+		InterestRate:           utils.Float64ToString(t.GetInterestRate()),
+		CompoundingFrequency:   t.CompoundingFrequency,
+		PayoutFrequency:        t.PayoutFrequency,
+		StartDate:              t.StartDate,
+		MaturityDate:           t.MaturityDate,
+		InterestCategoryId:     t.InterestCategoryId,
+		PayoutAccountId:        t.PayoutAccountId,
+		LastPayoutDate:         t.LastPayoutDate,
+		NextPayoutDate:         nextPayoutDate,
+		ProjectedMaturityValue: maturityValue,
+		ProjectedTotalInterest: maturityValue - t.Principal,
+	}, nil
+}
+
+// ParseFixedIncomeInterestRate returns the annual interest rate stored in database according to the rate string in percent
+func ParseFixedIncomeInterestRate(interestRate string) (int64, error) {
+	rate, err := utils.StringToFloat64(interestRate)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(rate * float64(FixedIncomeInterestRateFactorInDatabase))), nil
+}
+
+func addMonthsWithoutOverflow(t time.Time, months int) time.Time {
+	firstDayOfMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+	lastDayOfMonth := firstDayOfMonth.AddDate(0, 1, -1).Day()
+	day := t.Day()
+
+	if day > lastDayOfMonth {
+		day = lastDayOfMonth
+	}
+
+	return time.Date(firstDayOfMonth.Year(), firstDayOfMonth.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// FixedIncomeMaturityInfoResponseSlice represents the slice data structure of FixedIncomeMaturityInfoResponse
+type FixedIncomeMaturityInfoResponseSlice []*FixedIncomeMaturityInfoResponse
+
+// Len returns the count of items
+func (s FixedIncomeMaturityInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s FixedIncomeMaturityInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s FixedIncomeMaturityInfoResponseSlice) Less(i, j int) bool {
+	if s[i].MaturityDate != s[j].MaturityDate {
+		return strings.Compare(s[i].MaturityDate, s[j].MaturityDate) < 0
+	}
+
+	return s[i].AccountId < s[j].AccountId
+}
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixedIncomeFrequency_Months(t *testing.T) {
+	assert.Equal(t, 1, FIXED_INCOME_FREQUENCY_MONTHLY.Months())
+	assert.Equal(t, 3, FIXED_INCOME_FREQUENCY_QUARTERLY.Months())
+	assert.Equal(t, 6, FIXED_INCOME_FREQUENCY_SEMI_ANNUALLY.Months())
+	assert.Equal(t, 12, FIXED_INCOME_FREQUENCY_ANNUALLY.Months())
+	assert.Equal(t, 0, FIXED_INCOME_FREQUENCY_AT_MATURITY.Months())
+
+	assert.True(t, FIXED_INCOME_FREQUENCY_MONTHLY.IsValid())
+	assert.True(t, FIXED_INCOME_FREQUENCY_AT_MATURITY.IsValid())
+	assert.False(t, FixedIncomeFrequency(0).IsValid())
+	assert.False(t, FixedIncomeFrequency(6).IsValid())
+}
+
+func TestFixedIncomeTermTableName(t *testing.T) {
+	term := &FixedIncomeTerm{}
+	assert.Equal(t, "ebk_fixed_income_terms", term.TableName())
+}
+
+func TestParseFixedIncomeInterestRate(t *testing.T) {
+	rate, err := ParseFixedIncomeInterestRate("4.25")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42500), rate)
+
+	rate, err = ParseFixedIncomeInterestRate("0.0001")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rate)
+
+	_, err = ParseFixedIncomeInterestRate("abc")
+	assert.NotNil(t, err)
+}
+
+func TestFixedIncomeTermGetPayoutDates_Monthly(t *testing.T) {
+	term := &FixedIncomeTerm{
+		PayoutFrequency: FIXED_INCOME_FREQUENCY_MONTHLY,
+		StartDate:       "2024-01-31",
+		MaturityDate:    "2024-05-15",
+	}
+
+	payoutDates, err := term.GetPayoutDates()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2024-02-29", "2024-03-31", "2024-04-30", "2024-05-15"}, payoutDates)
+}
+
+func TestFixedIncomeTermGetPayoutDates_SemiAnnuallyEndsOnMaturity(t *testing.T) {
+	term := &FixedIncomeTerm{
+		PayoutFrequency: FIXED_INCOME_FREQUENCY_SEMI_ANNUALLY,
+		StartDate:       "2024-03-01",
+		MaturityDate:    "2025-03-01",
+	}
+
+	payoutDates, err := term.GetPayoutDates()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2024-09-01", "2025-03-01"}, payoutDates)
+}
+
+func TestFixedIncomeTermGetPayoutDates_AtMaturity(t *testing.T) {
+	term := &FixedIncomeTerm{
+		PayoutFrequency: FIXED_INCOME_FREQUENCY_AT_MATURITY,
+		StartDate:       "2024-03-01",
+		MaturityDate:    "2026-03-01",
+	}
+
+	payoutDates, err := term.GetPayoutDates()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2026-03-01"}, payoutDates)
+}
+
+func TestFixedIncomeTermGetPayoutDates_InvalidDate(t *testing.T) {
+	term := &FixedIncomeTerm{
+		PayoutFrequency: FIXED_INCOME_FREQUENCY_MONTHLY,
+		StartDate:       "2024-13-01",
+		MaturityDate:    "2025-03-01",
+	}
+
+	_, err := term.GetPayoutDates()
+	assert.NotNil(t, err)
+}
+
+func TestFixedIncomeTermGetValueAtDate_SimpleInterest(t *testing.T) {
+	term := &FixedIncomeTerm{
+		Principal:            1000000,
+		InterestRate:         50000,
+		CompoundingFrequency: FIXED_INCOME_FREQUENCY_AT_MATURITY,
+		StartDate:            "2023-01-01",
+		MaturityDate:         "2024-01-01",
+	}
+
+	value, err := term.GetValueAtDate("2022-12-01")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000000), value)
+
+	value, err = term.GetValueAtDate("2023-07-02")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1024932), value)
+
+	value, err = term.GetProjectedMaturityValue()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1050000), value)
+
+	value, err = term.GetValueAtDate("2025-01-01")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1050000), value)
+}
+
+func TestFixedIncomeTermGetValueAtDate_MonthlyCompounding(t *testing.T) {
+	term := &FixedIncomeTerm{
+		Principal:            1000000,
+		InterestRate:         50000,
+		CompoundingFrequency: FIXED_INCOME_FREQUENCY_MONTHLY,
+		StartDate:            "2023-01-01",
+		MaturityDate:         "2024-01-01",
+	}
+
+	value, err := term.GetProjectedMaturityValue()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1051162), value)
+}
+
+func TestFixedIncomeTermGetDueInterestPayouts(t *testing.T) {
+	term := &FixedIncomeTerm{
+		Principal:            1000000,
+		InterestRate:         40000,
+		CompoundingFrequency: FIXED_INCOME_FREQUENCY_AT_MATURITY,
+		PayoutFrequency:      FIXED_INCOME_FREQUENCY_SEMI_ANNUALLY,
+		StartDate:            "2023-01-01",
+		MaturityDate:         "2024-01-01",
+	}
+
+	payouts, err := term.GetDueInterestPayouts("2022-12-31")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(payouts))
+
+	payouts, err = term.GetDueInterestPayouts("2023-07-01")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(payouts))
+	assert.Equal(t, "2023-07-01", payouts[0].PayoutDate)
+	assert.Equal(t, int64(19836), payouts[0].Amount)
+
+	payouts, err = term.GetDueInterestPayouts("2024-06-01")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(payouts))
+	assert.Equal(t, int64(40000), payouts[0].Amount+payouts[1].Amount)
+
+	term.LastPayoutDate = "2023-07-01"
+	payouts, err = term.GetDueInterestPayouts("2024-06-01")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(payouts))
+	assert.Equal(t, "2024-01-01", payouts[0].PayoutDate)
+	assert.Equal(t, int64(20164), payouts[0].Amount)
+}
+
+func TestFixedIncomeTermGetDueInterestPayouts_InterestPaidOut(t *testing.T) {
+	term := &FixedIncomeTerm{
+		AccountId:            1,
+		Principal:            1000000,
+		InterestRate:         50000,
+		CompoundingFrequency: FIXED_INCOME_FREQUENCY_MONTHLY,
+		PayoutFrequency:      FIXED_INCOME_FREQUENCY_SEMI_ANNUALLY,
+		StartDate:            "2023-01-01",
+		MaturityDate:         "2024-01-01",
+		PayoutAccountId:      2,
+	}
+
+	assert.True(t, term.IsInterestPaidOut())
+
+	payouts, err := term.GetDueInterestPayouts("2024-06-01")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(payouts))
+	assert.Equal(t, int64(24795), payouts[0].Amount)
+	assert.Equal(t, int64(25205), payouts[1].Amount)
+
+	value, err := term.GetProjectedMaturityValue()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1050000), value)
+
+	term.PayoutAccountId = 1
+	assert.False(t, term.IsInterestPaidOut())
+
+	value, err = term.GetProjectedMaturityValue()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1051162), value)
+}
+
+func TestFixedIncomeTermGetNextPayoutDate(t *testing.T) {
+	term := &FixedIncomeTerm{
+		PayoutFrequency: FIXED_INCOME_FREQUENCY_QUARTERLY,
+		StartDate:       "2023-01-15",
+		MaturityDate:    "2023-12-15",
+	}
+
+	nextPayoutDate, err := term.GetNextPayoutDate()
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-04-15", nextPayoutDate)
+
+	term.LastPayoutDate = "2023-10-15"
+	nextPayoutDate, err = term.GetNextPayoutDate()
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-12-15", nextPayoutDate)
+
+	term.LastPayoutDate = "2023-12-15"
+	nextPayoutDate, err = term.GetNextPayoutDate()
+	assert.Nil(t, err)
+	assert.Equal(t, "", nextPayoutDate)
+}
+
+func TestFixedIncomeMaturityInfoResponseSliceLess(t *testing.T) {
+	var maturitySlice FixedIncomeMaturityInfoResponseSlice
+	maturitySlice = append(maturitySlice, &FixedIncomeMaturityInfoResponse{AccountId: 2, MaturityDate: "2024-05-01"})
+	maturitySlice = append(maturitySlice, &FixedIncomeMaturityInfoResponse{AccountId: 3, MaturityDate: "2024-03-01"})
+	maturitySlice = append(maturitySlice, &FixedIncomeMaturityInfoResponse{AccountId: 1, MaturityDate: "2024-05-01"})
+
+	assert.True(t, maturitySlice.Less(1, 0))
+	assert.True(t, maturitySlice.Less(2, 0))
+	assert.False(t, maturitySlice.Less(0, 1))
+}
@@ -12,6 +12,10 @@ type ImportTransaction struct {
 	OriginalDestinationAccountName     string
 	OriginalDestinationAccountCurrency string
 	OriginalTagNames                   []string
+	OriginalPayeeName                  string
+	MatchedRuleIds                     []int64
+	SuggestedCategoryId                int64
+	SuggestedTagIds                    []int64
 }
 
 // ImportTransactionResponse represents a view-object of the imported transaction data
@@ -31,6 +35,12 @@ type ImportTransactionResponse struct {
 	DestinationAmount                  int64                           `json:"destinationAmount,omitempty"`
 	TagIds                             []string                        `json:"tagIds"`
 	OriginalTagNames                   []string                        `json:"originalTagNames"`
+	PayeeId                            int64                           `json:"payeeId,string,omitempty"`
+	OriginalPayeeName                  string                          `json:"originalPayeeName,omitempty"`
+	MatchedRuleIds                     []string                        `json:"matchedRuleIds,omitempty"`
+	SuggestedCategoryId                int64                           `json:"suggestedCategoryId,string,omitempty"`
+	SuggestedTagIds                    []string                        `json:"suggestedTagIds,omitempty"`
+	Splits                             []*TransactionSplitInfoResponse `json:"splits,omitempty"`
 	Comment                            string                          `json:"comment"`
 	GeoLocation                        *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
 }
@@ -74,6 +84,12 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		DestinationAmount:                  t.RelatedAccountAmount,
 		TagIds:                             t.TagIds,
 		OriginalTagNames:                   t.OriginalTagNames,
+		PayeeId:                            t.PayeeId,
+		OriginalPayeeName:                  t.OriginalPayeeName,
+		MatchedRuleIds:                     utils.Int64ArrayToStringArray(t.MatchedRuleIds),
+		SuggestedCategoryId:                t.SuggestedCategoryId,
+		SuggestedTagIds:                    utils.Int64ArrayToStringArray(t.SuggestedTagIds),
+		Splits:                             GetTransactionSplitInfoResponses(t.Splits),
 		Comment:                            t.Comment,
 		GeoLocation:                        geoLocation,
 	}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"math"
+
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 )
 
@@ -13,17 +15,62 @@ const (
 	INVESTMENT_TRANSACTION_TYPE_SELL InvestmentTransactionType = 2
 )
 
+// InvestmentAssetType represents investment asset type
+type InvestmentAssetType byte
+
+// Investment asset types
+const (
+	INVESTMENT_ASSET_TYPE_STOCK          InvestmentAssetType = 1
+	INVESTMENT_ASSET_TYPE_ETF            InvestmentAssetType = 2
+	INVESTMENT_ASSET_TYPE_MUTUAL_FUND    InvestmentAssetType = 3
+	INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY InvestmentAssetType = 4
+	INVESTMENT_ASSET_TYPE_PRECIOUS_METAL InvestmentAssetType = 5
+	INVESTMENT_ASSET_TYPE_BOND           InvestmentAssetType = 6
+)
+
+// Investment quantity and price precisions (number of decimal places)
+const (
+	DefaultInvestmentQuantityPrecision int32 = 4
+	DefaultInvestmentPricePrecision    int32 = 2
+	CryptocurrencyQuantityPrecision    int32 = 8
+	CryptocurrencyPricePrecision       int32 = 8
+	MaxInvestmentQuantityPrecision     int32 = 8
+	MaxInvestmentPricePrecision        int32 = 8
+	investmentAmountPrecision          int32 = 2
+)
+
+// InvestmentDefaultPrecision represents that the default precision of the asset type is used, so that 0 decimal places can be specified
+const InvestmentDefaultPrecision int32 = -1
+
+// GramsPerTroyOunce represents the number of grams in one troy ounce
+const GramsPerTroyOunce = 31.1034768
+
+// PreciousMetalPriceCurrency represents the currency of the market quotes of precious metals
+const PreciousMetalPriceCurrency = "USD"
+
+// preciousMetalPriceSymbols maps the precious metal ticker symbols (ISO 4217 codes) to the symbols of the market quotes priced per troy ounce
+var preciousMetalPriceSymbols = map[string]string{
+	"XAU": "GC=F", // Gold
+	"XAG": "SI=F", // Silver
+	"XPT": "PL=F", // Platinum
+	"XPD": "PA=F", // Palladium
+}
+
 // Investment represents a stock/investment holding in database
 type Investment struct {
-	InvestmentId      int64   `xorm:"PK"`
-	Uid               int64   `xorm:"INDEX(IDX_investment_uid_deleted) INDEX(IDX_investment_uid_deleted_ticker) NOT NULL"`
-	Deleted           bool    `xorm:"INDEX(IDX_investment_uid_deleted) INDEX(IDX_investment_uid_deleted_ticker) NOT NULL"`
-	TickerSymbol      string  `xorm:"VARCHAR(10) INDEX(IDX_investment_uid_deleted_ticker) NOT NULL"`
-	CompanyName       string  `xorm:"VARCHAR(255)"`
-	SharesOwned       float64 `xorm:"DECIMAL(12,4) NOT NULL"`
-	AvgCostPerShare   int64   `xorm:"NOT NULL"` // Stored in cents
-	TotalInvested     int64   `xorm:"NOT NULL"` // Stored in cents
-	Currency          string  `xorm:"VARCHAR(3) NOT NULL"`
+	InvestmentId      int64               `xorm:"PK"`
+	Uid               int64               `xorm:"INDEX(IDX_investment_uid_deleted) INDEX(IDX_investment_uid_deleted_ticker) NOT NULL"`
+	Deleted           bool                `xorm:"INDEX(IDX_investment_uid_deleted) INDEX(IDX_investment_uid_deleted_ticker) NOT NULL"`
+	TickerSymbol      string              `xorm:"VARCHAR(32) INDEX(IDX_investment_uid_deleted_ticker) NOT NULL"`
+	CompanyName       string              `xorm:"VARCHAR(255)"`
+	AssetType         InvestmentAssetType `xorm:"NOT NULL DEFAULT 1"`
+	SharesOwned       float64             `xorm:"DECIMAL(24,8) NOT NULL"`
+	QuantityPrecision int32               `xorm:"NOT NULL DEFAULT 4"`
+	AvgCostPerShare   int64               `xorm:"NOT NULL"` // Stored in 10^-PricePrecision units of currency
+	PricePrecision    int32               `xorm:"NOT NULL DEFAULT 2"`
+	TotalInvested     int64               `xorm:"NOT NULL"` // Stored in cents
+	Currency          string              `xorm:"VARCHAR(3) NOT NULL"`
+	WalletAddress     string              `xorm:"VARCHAR(255)"`
 	CreatedUnixTime   int64
 	UpdatedUnixTime   int64
 	DeletedUnixTime   int64
@@ -34,10 +81,11 @@ type InvestmentTransaction struct {
 	TransactionId     int64                     `xorm:"PK"`
 	Uid               int64                     `xorm:"INDEX(IDX_inv_transaction_uid_deleted) INDEX(IDX_inv_transaction_uid_deleted_ticker) NOT NULL"`
 	Deleted           bool                      `xorm:"INDEX(IDX_inv_transaction_uid_deleted) INDEX(IDX_inv_transaction_uid_deleted_ticker) NOT NULL"`
-	TickerSymbol      string                    `xorm:"VARCHAR(10) INDEX(IDX_inv_transaction_uid_deleted_ticker) NOT NULL"`
+	TickerSymbol      string                    `xorm:"VARCHAR(32) INDEX(IDX_inv_transaction_uid_deleted_ticker) NOT NULL"`
 	Type              InvestmentTransactionType `xorm:"NOT NULL"`
-	Shares            float64                   `xorm:"DECIMAL(12,4) NOT NULL"`
-	PricePerShare     int64                     `xorm:"NOT NULL"` // Stored in cents
+	Shares            float64                   `xorm:"DECIMAL(24,8) NOT NULL"`
+	PricePerShare     int64                     `xorm:"NOT NULL"` // Stored in 10^-PricePrecision units of currency
+	PricePrecision    int32                     `xorm:"NOT NULL DEFAULT 2"`
 	TotalAmount       int64                     `xorm:"NOT NULL"` // Stored in cents
 	Fees              int64                     `xorm:"NOT NULL"` // Stored in cents
 	Currency          string                    `xorm:"VARCHAR(3) NOT NULL"`
@@ -51,24 +99,47 @@ type InvestmentTransaction struct {
 
 // StockPrice represents current stock price cache in database
 type StockPrice struct {
-	TickerSymbol    string `xorm:"PK VARCHAR(10)"`
+	TickerSymbol    string `xorm:"PK VARCHAR(64)"`
 	CompanyName     string `xorm:"VARCHAR(255)"`
-	CurrentPrice    int64  `xorm:"NOT NULL"` // Stored in cents
+	CurrentPrice    int64  `xorm:"NOT NULL"` // Stored in 10^-PricePrecision units of currency
+	PricePrecision  int32  `xorm:"NOT NULL DEFAULT 2"`
 	Currency        string `xorm:"VARCHAR(3) NOT NULL"`
 	LastUpdatedTime int64  `xorm:"NOT NULL"`
 }
 
 // InvestmentCreateRequest represents investment creation request
 type InvestmentCreateRequest struct {
-	TickerSymbol    string  `json:"tickerSymbol" binding:"required,max=10"`
-	CompanyName     string  `json:"companyName" binding:"max=255"`
-	Shares          float64 `json:"shares" binding:"required,min=0.0001"`
-	PricePerShare   float64 `json:"pricePerShare" binding:"required,min=0.01"`
-	Fees            float64 `json:"fees" binding:"min=0"`
-	Currency        string  `json:"currency" binding:"required,len=3"`
-	TransactionTime int64   `json:"transactionTime" binding:"required,min=1"`
-	UtcOffset       int16   `json:"utcOffset" binding:"min=-720,max=840"`
-	Comment         string  `json:"comment" binding:"max=255"`
+	TickerSymbol      string              `json:"tickerSymbol" binding:"required,max=32"`
+	CompanyName       string              `json:"companyName" binding:"max=255"`
+	AssetType         InvestmentAssetType `json:"assetType" binding:"omitempty,min=1,max=6"`
+	Shares            float64             `json:"shares" binding:"required,gt=0"`
+	QuantityPrecision *int32              `json:"quantityPrecision" binding:"omitempty,min=0,max=8"`
+	PricePerShare     float64             `json:"pricePerShare" binding:"required,gt=0"`
+	PricePrecision    *int32              `json:"pricePrecision" binding:"omitempty,min=0,max=8"`
+	Fees              float64             `json:"fees" binding:"min=0"`
+	Currency          string              `json:"currency" binding:"required,len=3"`
+	WalletAddress     string              `json:"walletAddress" binding:"max=255"`
+	TransactionTime   int64               `json:"transactionTime" binding:"required,min=1"`
+	UtcOffset         int16               `json:"utcOffset" binding:"min=-720,max=840"`
+	Comment           string              `json:"comment" binding:"max=255"`
+}
+
+// GetQuantityPrecision returns the quantity precision of the request, or InvestmentDefaultPrecision if it is not specified
+func (r *InvestmentCreateRequest) GetQuantityPrecision() int32 {
+	if r.QuantityPrecision == nil {
+		return InvestmentDefaultPrecision
+	}
+
+	return *r.QuantityPrecision
+}
+
+// GetPricePrecision returns the price precision of the request, or InvestmentDefaultPrecision if it is not specified
+func (r *InvestmentCreateRequest) GetPricePrecision() int32 {
+	if r.PricePrecision == nil {
+		return InvestmentDefaultPrecision
+	}
+
+	return *r.PricePrecision
 }
 
 // InvestmentModifyRequest represents investment modification request
@@ -80,9 +151,9 @@ type InvestmentModifyRequest struct {
 // InvestmentTransactionCreateRequest represents investment transaction creation request
 type InvestmentTransactionCreateRequest struct {
 	Type            InvestmentTransactionType `json:"type" binding:"required"`
-	TickerSymbol    string                    `json:"tickerSymbol" binding:"required,max=10"`
-	Shares          float64                   `json:"shares" binding:"required,min=0.0001"`
-	PricePerShare   float64                   `json:"pricePerShare" binding:"required,min=0.01"`
+	TickerSymbol    string                    `json:"tickerSymbol" binding:"required,max=32"`
+	Shares          float64                   `json:"shares" binding:"required,gt=0"`
+	PricePerShare   float64                   `json:"pricePerShare" binding:"required,gt=0"`
 	Fees            float64                   `json:"fees" binding:"min=0"`
 	Currency        string                    `json:"currency" binding:"required,len=3"`
 	TransactionTime int64                     `json:"transactionTime" binding:"required,min=1"`
@@ -118,11 +189,11 @@ type PortfolioSummary struct {
 // InvestmentWithCurrentPrice represents investment holding with current market data
 type InvestmentWithCurrentPrice struct {
 	*Investment
-	CurrentPrice     int64   `json:"currentPrice"`
-	CurrentValue     int64   `json:"currentValue"`
-	GainLoss         int64   `json:"gainLoss"`
-	GainLossPct      float64 `json:"gainLossPct"`
-	LastPriceUpdate  int64   `json:"lastPriceUpdate"`
+	CurrentPrice    int64   `json:"currentPrice"`
+	CurrentValue    int64   `json:"currentValue"`
+	GainLoss        int64   `json:"gainLoss"`
+	GainLossPct     float64 `json:"gainLossPct"`
+	LastPriceUpdate int64   `json:"lastPriceUpdate"`
 }
 
 // TableName returns the table name of Investment
@@ -146,4 +217,96 @@ func (t InvestmentTransactionType) Validate() error {
 		return nil
 	}
 	return errs.ErrTransactionTypeInvalid
-}
\ No newline at end of file
+}
+
+// Validate validates investment asset type
+func (t InvestmentAssetType) Validate() error {
+	if t >= INVESTMENT_ASSET_TYPE_STOCK && t <= INVESTMENT_ASSET_TYPE_BOND {
+		return nil
+	}
+
+	return errs.ErrInvestmentAssetTypeInvalid
+}
+
+// DefaultQuantityPrecision returns the default number of decimal places of the quantity for the asset type
+func (t InvestmentAssetType) DefaultQuantityPrecision() int32 {
+	if t == INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY {
+		return CryptocurrencyQuantityPrecision
+	}
+
+	return DefaultInvestmentQuantityPrecision
+}
+
+// DefaultPricePrecision returns the default number of decimal places of the price for the asset type
+func (t InvestmentAssetType) DefaultPricePrecision() int32 {
+	if t == INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY {
+		return CryptocurrencyPricePrecision
+	}
+
+	return DefaultInvestmentPricePrecision
+}
+
+// IsPreciousMetalTickerSymbol returns whether the ticker symbol is a supported precious metal (e.g. XAU for gold)
+func IsPreciousMetalTickerSymbol(tickerSymbol string) bool {
+	_, exists := preciousMetalPriceSymbols[tickerSymbol]
+	return exists
+}
+
+// GetPreciousMetalPriceSymbol returns the symbol of the market quote priced per troy ounce for the precious metal ticker symbol
+func GetPreciousMetalPriceSymbol(tickerSymbol string) string {
+	return preciousMetalPriceSymbols[tickerSymbol]
+}
+
+// GetPreciousMetalPriceTickerSymbol returns the ticker symbol used for storing the precious metal price, which would not clash with the stock ticker symbol
+func GetPreciousMetalPriceTickerSymbol(tickerSymbol string) string {
+	return "METAL:" + tickerSymbol
+}
+
+// GetCryptoPriceTickerSymbol returns the ticker symbol used for storing the cryptocurrency price quoted in the specified currency
+func GetCryptoPriceTickerSymbol(tickerSymbol string, currency string) string {
+	return "CRYPTO:" + tickerSymbol + ":" + currency
+}
+
+// ConvertGramsToTroyOunces returns the weight in troy ounces of the specified grams
+func ConvertGramsToTroyOunces(grams float64) float64 {
+	return grams / GramsPerTroyOunce
+}
+
+// RoundInvestmentQuantity returns the quantity rounded to the specified number of decimal places
+func RoundInvestmentQuantity(quantity float64, precision int32) float64 {
+	factor := math.Pow10(int(precision))
+	return math.Round(quantity*factor) / factor
+}
+
+// ConvertInvestmentPriceFromFloat64 returns the price in 10^-precision units of currency
+func ConvertInvestmentPriceFromFloat64(price float64, precision int32) int64 {
+	return int64(math.Round(price * math.Pow10(int(precision))))
+}
+
+// ConvertInvestmentPriceToFloat64 returns the price in units of currency from 10^-precision units of currency
+func ConvertInvestmentPriceToFloat64(price int64, precision int32) float64 {
+	return float64(price) / math.Pow10(int(precision))
+}
+
+// ConvertInvestmentPrice returns the price converted from one precision to another precision
+func ConvertInvestmentPrice(price int64, fromPrecision int32, toPrecision int32) int64 {
+	if fromPrecision == toPrecision {
+		return price
+	}
+
+	return ConvertInvestmentPriceFromFloat64(ConvertInvestmentPriceToFloat64(price, fromPrecision), toPrecision)
+}
+
+// GetInvestmentAmount returns the amount in cents of the specified quantity at the price in 10^-precision units of currency
+func GetInvestmentAmount(quantity float64, price int64, pricePrecision int32) int64 {
+	return ConvertInvestmentPriceFromFloat64(ConvertInvestmentPriceToFloat64(price, pricePrecision)*quantity, investmentAmountPrecision)
+}
+
+// GetInvestmentPriceFromAmount returns the price in 10^-precision units of currency of the specified amount in cents and quantity
+func GetInvestmentPriceFromAmount(amount int64, quantity float64, pricePrecision int32) int64 {
+	if quantity <= 0 {
+		return 0
+	}
+
+	return ConvertInvestmentPriceFromFloat64(ConvertInvestmentPriceToFloat64(amount, investmentAmountPrecision)/quantity, pricePrecision)
+}
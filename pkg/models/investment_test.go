@@ -36,4 +36,75 @@ func TestInvestmentTransactionTableName(t *testing.T) {
 func TestStockPriceTableName(t *testing.T) {
 	stockPrice := &StockPrice{}
 	assert.Equal(t, "ebk_stock_prices", stockPrice.TableName())
-}
\ No newline at end of file
+}
+
+func TestInvestmentAssetType_Validate(t *testing.T) {
+	assert.Nil(t, INVESTMENT_ASSET_TYPE_STOCK.Validate())
+	assert.Nil(t, INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY.Validate())
+	assert.Nil(t, INVESTMENT_ASSET_TYPE_PRECIOUS_METAL.Validate())
+	assert.Nil(t, INVESTMENT_ASSET_TYPE_BOND.Validate())
+	assert.Equal(t, errs.ErrInvestmentAssetTypeInvalid, InvestmentAssetType(0).Validate())
+	assert.Equal(t, errs.ErrInvestmentAssetTypeInvalid, InvestmentAssetType(7).Validate())
+}
+
+func TestInvestmentAssetType_DefaultPrecision(t *testing.T) {
+	assert.Equal(t, int32(4), INVESTMENT_ASSET_TYPE_STOCK.DefaultQuantityPrecision())
+	assert.Equal(t, int32(2), INVESTMENT_ASSET_TYPE_STOCK.DefaultPricePrecision())
+	assert.Equal(t, int32(8), INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY.DefaultQuantityPrecision())
+	assert.Equal(t, int32(8), INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY.DefaultPricePrecision())
+	assert.Equal(t, int32(4), INVESTMENT_ASSET_TYPE_PRECIOUS_METAL.DefaultQuantityPrecision())
+}
+
+func TestRoundInvestmentQuantity(t *testing.T) {
+	assert.Equal(t, 0.00012345, RoundInvestmentQuantity(0.000123454, 8))
+	assert.Equal(t, 1.2346, RoundInvestmentQuantity(1.23456, 4))
+	assert.Equal(t, float64(0), RoundInvestmentQuantity(0.00004, 4))
+}
+
+func TestConvertInvestmentPrice(t *testing.T) {
+	assert.Equal(t, int64(1234), ConvertInvestmentPriceFromFloat64(0.00001234, 8))
+	assert.Equal(t, int64(12346), ConvertInvestmentPriceFromFloat64(123.456, 2))
+	assert.Equal(t, 0.00001234, ConvertInvestmentPriceToFloat64(1234, 8))
+	assert.Equal(t, int64(12345000000), ConvertInvestmentPrice(12345, 2, 8))
+	assert.Equal(t, int64(12346), ConvertInvestmentPrice(12345678901, 8, 2))
+}
+
+func TestGetInvestmentAmount(t *testing.T) {
+	// 0.00012345 BTC at 67187.33912345 USD
+	assert.Equal(t, int64(829), GetInvestmentAmount(0.00012345, 6718733912345, 8))
+	// 10 shares at 123.45 USD
+	assert.Equal(t, int64(123450), GetInvestmentAmount(10, 12345, 2))
+}
+
+func TestGetInvestmentPriceFromAmount(t *testing.T) {
+	assert.Equal(t, int64(12345), GetInvestmentPriceFromAmount(123450, 10, 2))
+	assert.Equal(t, int64(1234), GetInvestmentPriceFromAmount(1234, 1000000, 8))
+	assert.Equal(t, int64(0), GetInvestmentPriceFromAmount(1234, 0, 8))
+}
+
+func TestPreciousMetalTickerSymbol(t *testing.T) {
+	assert.True(t, IsPreciousMetalTickerSymbol("XAU"))
+	assert.True(t, IsPreciousMetalTickerSymbol("XAG"))
+	assert.False(t, IsPreciousMetalTickerSymbol("AAPL"))
+	assert.Equal(t, "GC=F", GetPreciousMetalPriceSymbol("XAU"))
+	assert.Equal(t, "SI=F", GetPreciousMetalPriceSymbol("XAG"))
+	assert.Equal(t, "METAL:XAU", GetPreciousMetalPriceTickerSymbol("XAU"))
+	assert.NotEqual(t, GetPreciousMetalPriceTickerSymbol("XAU"), "XAU")
+}
+
+func TestInvestmentCreateRequest_GetPrecision(t *testing.T) {
+	request := &InvestmentCreateRequest{}
+	assert.Equal(t, InvestmentDefaultPrecision, request.GetQuantityPrecision())
+	assert.Equal(t, InvestmentDefaultPrecision, request.GetPricePrecision())
+
+	zero := int32(0)
+	request.QuantityPrecision = &zero
+	request.PricePrecision = &zero
+	assert.Equal(t, int32(0), request.GetQuantityPrecision())
+	assert.Equal(t, int32(0), request.GetPricePrecision())
+}
+
+func TestConvertGramsToTroyOunces(t *testing.T) {
+	assert.InDelta(t, 1.0, ConvertGramsToTroyOunces(31.1034768), 1e-12)
+	assert.InDelta(t, 3.2150747, ConvertGramsToTroyOunces(100), 1e-7)
+}
@@ -0,0 +1,355 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// LoanInterestRateFactorInDatabase represents the factor of annual percentage rate (in percent) stored in database
+const LoanInterestRateFactorInDatabase = 10000
+
+// LoanMaxInterestRate represents the maximum annual percentage rate (in percent) of loan term
+const LoanMaxInterestRate = 100
+
+// LoanTerm represents the loan terms of a debt account stored in database
+type LoanTerm struct {
+	AccountId           int64  `xorm:"PK"`
+	Uid                 int64  `xorm:"INDEX(IDX_loan_term_uid_deleted) NOT NULL"`
+	Deleted             bool   `xorm:"INDEX(IDX_loan_term_uid_deleted) NOT NULL"`
+	Principal           int64  `xorm:"NOT NULL"`
+	InterestRate        int64  `xorm:"NOT NULL"`
+	TermMonths          int32  `xorm:"NOT NULL"`
+	StartDate           string `xorm:"VARCHAR(10) NOT NULL"`
+	PaymentDay          byte   `xorm:"NOT NULL"`
+	ExtraMonthlyPayment int64  `xorm:"NOT NULL"`
+	InterestCategoryId  int64  `xorm:"NOT NULL"`
+	CreatedUnixTime     int64
+	UpdatedUnixTime     int64
+	DeletedUnixTime     int64
+}
+
+// LoanExtraPayment represents a one-time extra payment of loan which is applied on the first payment date on or after the specified date
+type LoanExtraPayment struct {
+	Date   string `json:"date" binding:"required,len=10"`
+	Amount int64  `json:"amount" binding:"required,min=1,max=99999999999"`
+}
+
+// LoanTermGetRequest represents all parameters of loan term getting request
+type LoanTermGetRequest struct {
+	AccountId int64 `form:"accountId,string" binding:"required,min=1"`
+}
+
+// LoanTermSetRequest represents all parameters of loan term setting request
+type LoanTermSetRequest struct {
+	AccountId           int64  `json:"accountId,string" binding:"required,min=1"`
+	Principal           int64  `json:"principal" binding:"required,min=1,max=99999999999"`
+	InterestRate        string `json:"interestRate" binding:"required"`
+	TermMonths          int32  `json:"termMonths" binding:"required,min=1,max=600"`
+	StartDate           string `json:"startDate" binding:"required,len=10"`
+	PaymentDay          byte   `json:"paymentDay" binding:"required,min=1,max=31"`
+	ExtraMonthlyPayment int64  `json:"extraMonthlyPayment" binding:"min=0,max=99999999999"`
+	InterestCategoryId  int64  `json:"interestCategoryId,string" binding:"required,min=1"`
+}
+
+// LoanTermDeleteRequest represents all parameters of loan term deleting request
+type LoanTermDeleteRequest struct {
+	AccountId int64 `json:"accountId,string" binding:"required,min=1"`
+}
+
+// LoanWhatIfRequest represents all parameters of loan extra payments what-if request
+type LoanWhatIfRequest struct {
+	AccountId           int64               `json:"accountId,string" binding:"required,min=1"`
+	ExtraMonthlyPayment int64               `json:"extraMonthlyPayment" binding:"min=0,max=99999999999"`
+	ExtraPayments       []*LoanExtraPayment `json:"extraPayments" binding:"omitempty,max=600,dive"`
+}
+
+// LoanPaymentCreateRequest represents all parameters of loan payment creation request
+type LoanPaymentCreateRequest struct {
+	AccountId          int64  `json:"accountId,string" binding:"required,min=1"`
+	SourceAccountId    int64  `json:"sourceAccountId,string" binding:"required,min=1"`
+	TransferCategoryId int64  `json:"transferCategoryId,string" binding:"required,min=1"`
+	Amount             int64  `json:"amount" binding:"required,min=1,max=99999999999"`
+	Time               int64  `json:"time" binding:"required,min=1"`
+	UtcOffset          int16  `json:"utcOffset" binding:"min=-720,max=840"`
+	Comment            string `json:"comment" binding:"max=255"`
+	ClientSessionId    string `json:"clientSessionId"`
+}
+
+// LoanTermInfoResponse represents a view-object of loan term
+type LoanTermInfoResponse struct {
+	AccountId           int64  `json:"accountId,string"`
+	Principal           int64  `json:"principal"`
+	InterestRate        string `json:"interestRate"`
+	TermMonths          int32  `json:"termMonths"`
+	StartDate           string `json:"startDate"`
+	PaymentDay          byte   `json:"paymentDay"`
+	ExtraMonthlyPayment int64  `json:"extraMonthlyPayment"`
+	InterestCategoryId  int64  `json:"interestCategoryId,string"`
+}
+
+// LoanAmortizationScheduleItem represents a payment in loan amortization schedule
+type LoanAmortizationScheduleItem struct {
+	Index            int32  `json:"index"`
+	PaymentDate      string `json:"paymentDate"`
+	Payment          int64  `json:"payment"`
+	Principal        int64  `json:"principal"`
+	Interest         int64  `json:"interest"`
+	ExtraPayment     int64  `json:"extraPayment"`
+	RemainingBalance int64  `json:"remainingBalance"`
+}
+
+// LoanAmortizationSummary represents the summary of loan amortization schedule
+type LoanAmortizationSummary struct {
+	MonthlyPayment int64  `json:"monthlyPayment"`
+	PaymentCount   int32  `json:"paymentCount"`
+	PayoffDate     string `json:"payoffDate"`
+	TotalInterest  int64  `json:"totalInterest"`
+	TotalPayment   int64  `json:"totalPayment"`
+}
+
+// LoanAmortizationScheduleResponse represents a view-object of loan amortization schedule
+type LoanAmortizationScheduleResponse struct {
+	Term     *LoanTermInfoResponse           `json:"term"`
+	Summary  *LoanAmortizationSummary        `json:"summary"`
+	Schedule []*LoanAmortizationScheduleItem `json:"schedule"`
+}
+
+// LoanWhatIfResponse represents a view-object of loan extra payments what-if result
+type LoanWhatIfResponse struct {
+	Baseline      *LoanAmortizationSummary `json:"baseline"`
+	Scenario      *LoanAmortizationSummary `json:"scenario"`
+	InterestSaved int64                    `json:"interestSaved"`
+	PaymentsSaved int32                    `json:"paymentsSaved"`
+}
+
+// LoanPaymentCreateResponse represents a view-object of the created loan payment
+type LoanPaymentCreateResponse struct {
+	Principal             int64 `json:"principal"`
+	Interest              int64 `json:"interest"`
+	TransferTransactionId int64 `json:"transferTransactionId,string"`
+	InterestTransactionId int64 `json:"interestTransactionId,string"`
+}
+
+// TableName returns the table name of LoanTerm
+func (t *LoanTerm) TableName() string {
+	return "ebk_loan_terms"
+}
+
+// GetInterestRate returns the annual percentage rate in percent
+func (t *LoanTerm) GetInterestRate() float64 {
+	return float64(t.InterestRate) / float64(LoanInterestRateFactorInDatabase)
+}
+
+// GetMonthlyInterestRate returns the monthly interest rate as a fraction
+func (t *LoanTerm) GetMonthlyInterestRate() float64 {
+	return t.GetInterestRate() / 100 / 12
+}
+
+// GetScheduledMonthlyPayment returns the scheduled monthly payment (principal and interest) without extra payments
+func (t *LoanTerm) GetScheduledMonthlyPayment() int64 {
+	if t.TermMonths <= 0 {
+		return t.Principal
+	}
+
+	monthlyRate := t.GetMonthlyInterestRate()
+
+	if monthlyRate <= 0 {
+		return int64(math.Ceil(float64(t.Principal) / float64(t.TermMonths)))
+	}
+
+	payment := float64(t.Principal) * monthlyRate / (1 - math.Pow(1+monthlyRate, -float64(t.TermMonths)))
+
+	return int64(math.Round(payment))
+}
+
+// GetInterestOfBalance returns the interest of one month for the specified outstanding balance
+func (t *LoanTerm) GetInterestOfBalance(balance int64) int64 {
+	if balance <= 0 {
+		return 0
+	}
+
+	return int64(math.Round(float64(balance) * t.GetMonthlyInterestRate()))
+}
+
+// GetPaymentDate returns the payment date of the specified payment index (starting from 1)
+func (t *LoanTerm) GetPaymentDate(index int32) (string, error) {
+	startDate, err := time.Parse(time.DateOnly, t.StartDate)
+
+	if err != nil {
+		return "", err
+	}
+
+	firstDayOfMonth := time.Date(startDate.Year(), startDate.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, int(index), 0)
+	lastDayOfMonth := firstDayOfMonth.AddDate(0, 1, -1).Day()
+	day := int(t.PaymentDay)
+
+	if day > lastDayOfMonth {
+		day = lastDayOfMonth
+	}
+
+	return time.Date(firstDayOfMonth.Year(), firstDayOfMonth.Month(), day, 0, 0, 0, 0, time.UTC).Format(time.DateOnly), nil
+}
+
+// GetAmortizationSchedule returns the amortization schedule and its summary of the loan with the extra monthly payment of the term and the specified one-time extra payments
+func (t *LoanTerm) GetAmortizationSchedule(extraPayments []*LoanExtraPayment) ([]*LoanAmortizationScheduleItem, *LoanAmortizationSummary, error) {
+	monthlyPayment := t.GetScheduledMonthlyPayment()
+	sortedExtraPayments := make([]*LoanExtraPayment, len(extraPayments))
+	copy(sortedExtraPayments, extraPayments)
+
+	sort.SliceStable(sortedExtraPayments, func(i, j int) bool {
+		return strings.Compare(sortedExtraPayments[i].Date, sortedExtraPayments[j].Date) < 0
+	})
+
+	schedule := make([]*LoanAmortizationScheduleItem, 0, t.TermMonths)
+	summary := &LoanAmortizationSummary{
+		MonthlyPayment: monthlyPayment,
+	}
+
+	balance := t.Principal
+	extraPaymentIndex := 0
+
+	for index := int32(1); index <= t.TermMonths && balance > 0; index++ {
+		paymentDate, err := t.GetPaymentDate(index)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		interest := t.GetInterestOfBalance(balance)
+		principal := monthlyPayment - interest
+		extraPayment := t.ExtraMonthlyPayment
+
+		for ; extraPaymentIndex < len(sortedExtraPayments) && strings.Compare(sortedExtraPayments[extraPaymentIndex].Date, paymentDate) <= 0; extraPaymentIndex++ {
+			extraPayment += sortedExtraPayments[extraPaymentIndex].Amount
+		}
+
+		if principal < 0 {
+			principal = 0
+		}
+
+		if index == t.TermMonths || principal > balance {
+			principal = balance
+		}
+
+		if extraPayment > balance-principal {
+			extraPayment = balance - principal
+		}
+
+		balance = balance - principal - extraPayment
+
+		schedule = append(schedule, &LoanAmortizationScheduleItem{
+			Index:            index,
+			PaymentDate:      paymentDate,
+			Payment:          interest + principal + extraPayment,
+			Principal:        principal,
+			Interest:         interest,
+			ExtraPayment:     extraPayment,
+			RemainingBalance: balance,
+		})
+
+		summary.PaymentCount = index
+		summary.PayoffDate = paymentDate
+		summary.TotalInterest += interest
+		summary.TotalPayment += interest + principal + extraPayment
+	}
+
+	return schedule, summary, nil
+}
+
+// GetInterestOfPeriod returns the interest accrued for the specified outstanding balance from the last payment date to the payment date,
+// each whole month accrues the interest of one month and the remaining days accrue the interest of one month pro rata
+func (t *LoanTerm) GetInterestOfPeriod(balance int64, lastPaymentDate string, paymentDate string) (int64, error) {
+	if balance <= 0 {
+		return 0, nil
+	}
+
+	startDate, err := time.Parse(time.DateOnly, lastPaymentDate)
+
+	if err != nil {
+		return 0, err
+	}
+
+	endDate, err := time.Parse(time.DateOnly, paymentDate)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(float64(balance) * t.GetMonthlyInterestRate() * getLoanElapsedMonths(startDate, endDate))), nil
+}
+
+// SplitPayment returns the principal and interest part of a payment according to the outstanding balance and the interest accrued since the last payment date
+func (t *LoanTerm) SplitPayment(amount int64, outstandingBalance int64, lastPaymentDate string, paymentDate string) (int64, int64, error) {
+	interest, err := t.GetInterestOfPeriod(outstandingBalance, lastPaymentDate, paymentDate)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if interest > amount {
+		interest = amount
+	}
+
+	return amount - interest, interest, nil
+}
+
+// ToLoanTermInfoResponse returns a view-object according to database model
+func (t *LoanTerm) ToLoanTermInfoResponse() *LoanTermInfoResponse {
+	return &LoanTermInfoResponse{
+		AccountId:           t.AccountId,
+		Principal:           t.Principal,
+		InterestRate:        utils.Float64ToString(t.GetInterestRate()),
+		TermMonths:          t.TermMonths,
+		StartDate:           t.StartDate,
+		PaymentDay:          t.PaymentDay,
+		ExtraMonthlyPayment: t.ExtraMonthlyPayment,
+		InterestCategoryId:  t.InterestCategoryId,
+	}
+}
+
+// ParseLoanInterestRate returns the annual percentage rate stored in database according to the rate string in percent
+func ParseLoanInterestRate(interestRate string) (int64, error) {
+	rate, err := utils.StringToFloat64(interestRate)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Round(rate * float64(LoanInterestRateFactorInDatabase))), nil
+}
+
+func getLoanElapsedMonths(startDate time.Time, endDate time.Time) float64 {
+	if !endDate.After(startDate) {
+		return 0
+	}
+
+	months := (endDate.Year()-startDate.Year())*12 + int(endDate.Month()) - int(startDate.Month())
+	monthStartDate := addLoanMonths(startDate, months)
+
+	if monthStartDate.After(endDate) {
+		months--
+		monthStartDate = addLoanMonths(startDate, months)
+	}
+
+	nextMonthStartDate := addLoanMonths(startDate, months+1)
+	remainingDays := endDate.Sub(monthStartDate).Hours() / 24
+	daysOfMonth := nextMonthStartDate.Sub(monthStartDate).Hours() / 24
+
+	return float64(months) + remainingDays/daysOfMonth
+}
+
+func addLoanMonths(date time.Time, months int) time.Time {
+	firstDayOfMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+	lastDayOfMonth := firstDayOfMonth.AddDate(0, 1, -1).Day()
+	day := date.Day()
+
+	if day > lastDayOfMonth {
+		day = lastDayOfMonth
+	}
+
+	return time.Date(firstDayOfMonth.Year(), firstDayOfMonth.Month(), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoanTermTableName(t *testing.T) {
+	term := &LoanTerm{}
+	assert.Equal(t, "ebk_loan_terms", term.TableName())
+}
+
+func TestParseLoanInterestRate(t *testing.T) {
+	rate, err := ParseLoanInterestRate("6.5")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(65000), rate)
+
+	_, err = ParseLoanInterestRate("")
+	assert.NotNil(t, err)
+}
+
+func TestLoanTermGetScheduledMonthlyPayment(t *testing.T) {
+	term := &LoanTerm{
+		Principal:    20000000,
+		InterestRate: 60000,
+		TermMonths:   360,
+	}
+
+	assert.Equal(t, int64(119910), term.GetScheduledMonthlyPayment())
+
+	term = &LoanTerm{
+		Principal:    100000,
+		InterestRate: 0,
+		TermMonths:   3,
+	}
+
+	assert.Equal(t, int64(33334), term.GetScheduledMonthlyPayment())
+}
+
+func TestLoanTermGetPaymentDate(t *testing.T) {
+	term := &LoanTerm{
+		StartDate:  "2024-01-15",
+		PaymentDay: 31,
+	}
+
+	paymentDate, err := term.GetPaymentDate(1)
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-02-29", paymentDate)
+
+	paymentDate, err = term.GetPaymentDate(2)
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-03-31", paymentDate)
+
+	paymentDate, err = term.GetPaymentDate(12)
+	assert.Nil(t, err)
+	assert.Equal(t, "2025-01-31", paymentDate)
+
+	term.StartDate = "invalid"
+	_, err = term.GetPaymentDate(1)
+	assert.NotNil(t, err)
+}
+
+func TestLoanTermGetAmortizationSchedule(t *testing.T) {
+	term := &LoanTerm{
+		Principal:    1200000,
+		InterestRate: 120000,
+		TermMonths:   12,
+		StartDate:    "2024-01-01",
+		PaymentDay:   1,
+	}
+
+	schedule, summary, err := term.GetAmortizationSchedule(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 12, len(schedule))
+	assert.Equal(t, int64(106619), summary.MonthlyPayment)
+	assert.Equal(t, int32(12), summary.PaymentCount)
+	assert.Equal(t, "2025-01-01", summary.PayoffDate)
+
+	assert.Equal(t, "2024-02-01", schedule[0].PaymentDate)
+	assert.Equal(t, int64(12000), schedule[0].Interest)
+	assert.Equal(t, int64(94619), schedule[0].Principal)
+	assert.Equal(t, int64(1105381), schedule[0].RemainingBalance)
+	assert.Equal(t, int64(0), schedule[11].RemainingBalance)
+
+	totalPrincipal := int64(0)
+
+	for i := 0; i < len(schedule); i++ {
+		totalPrincipal += schedule[i].Principal + schedule[i].ExtraPayment
+	}
+
+	assert.Equal(t, int64(1200000), totalPrincipal)
+	assert.Equal(t, summary.TotalPayment, summary.TotalInterest+int64(1200000))
+}
+
+func TestLoanTermGetAmortizationSchedule_WithExtraPayments(t *testing.T) {
+	term := &LoanTerm{
+		Principal:    1200000,
+		InterestRate: 120000,
+		TermMonths:   12,
+		StartDate:    "2024-01-01",
+		PaymentDay:   1,
+	}
+
+	_, baseline, err := term.GetAmortizationSchedule(nil)
+	assert.Nil(t, err)
+
+	term.ExtraMonthlyPayment = 50000
+	schedule, scenario, err := term.GetAmortizationSchedule([]*LoanExtraPayment{
+		{Date: "2024-03-15", Amount: 200000},
+	})
+	assert.Nil(t, err)
+
+	assert.Less(t, scenario.PaymentCount, baseline.PaymentCount)
+	assert.Less(t, scenario.TotalInterest, baseline.TotalInterest)
+	assert.Equal(t, int64(50000), schedule[0].ExtraPayment)
+	assert.Equal(t, int64(50000), schedule[1].ExtraPayment)
+	assert.Equal(t, int64(250000), schedule[2].ExtraPayment)
+	assert.Equal(t, int64(0), schedule[len(schedule)-1].RemainingBalance)
+}
+
+func TestLoanTermSplitPayment(t *testing.T) {
+	term := &LoanTerm{
+		InterestRate: 120000,
+	}
+
+	principal, interest, err := term.SplitPayment(50000, 1000000, "2024-01-15", "2024-02-15")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(40000), principal)
+	assert.Equal(t, int64(10000), interest)
+
+	principal, interest, err = term.SplitPayment(5000, 1000000, "2024-01-15", "2024-02-15")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), principal)
+	assert.Equal(t, int64(5000), interest)
+
+	principal, interest, err = term.SplitPayment(5000, 0, "2024-01-15", "2024-02-15")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5000), principal)
+	assert.Equal(t, int64(0), interest)
+
+	_, _, err = term.SplitPayment(5000, 1000000, "invalid", "2024-02-15")
+	assert.NotNil(t, err)
+}
+
+func TestLoanTermSplitPayment_LatePayment(t *testing.T) {
+	term := &LoanTerm{
+		InterestRate: 120000,
+	}
+
+	principal, interest, err := term.SplitPayment(50000, 1000000, "2024-01-15", "2024-03-15")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(30000), principal)
+	assert.Equal(t, int64(20000), interest)
+
+	principal, interest, err = term.SplitPayment(50000, 1000000, "2024-01-15", "2024-03-30")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(25161), principal)
+	assert.Equal(t, int64(24839), interest)
+}
+
+func TestLoanTermSplitPayment_MultiplePaymentsInSamePeriod(t *testing.T) {
+	term := &LoanTerm{
+		InterestRate: 120000,
+	}
+
+	principal, interest, err := term.SplitPayment(50000, 1000000, "2024-02-15", "2024-02-15")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50000), principal)
+	assert.Equal(t, int64(0), interest)
+
+	principal, interest, err = term.SplitPayment(50000, 1000000, "2024-02-15", "2024-03-01")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(44828), principal)
+	assert.Equal(t, int64(5172), interest)
+}
+
+func TestLoanTermSplitPayment_EndOfMonthPaymentDay(t *testing.T) {
+	term := &LoanTerm{
+		InterestRate: 120000,
+	}
+
+	principal, interest, err := term.SplitPayment(50000, 1000000, "2024-01-31", "2024-02-29")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(40000), principal)
+	assert.Equal(t, int64(10000), interest)
+}
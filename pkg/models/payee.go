@@ -0,0 +1,186 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// MaximumAliasesCountOfPayee represents the maximum count of aliases of one payee
+const MaximumAliasesCountOfPayee = 50
+
+const payeeAliasesSeparator = "\n"
+
+// Payee represents payee (counterparty) data stored in database
+type Payee struct {
+	PayeeId         int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_payee_uid_deleted_name) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_payee_uid_deleted_name) NOT NULL"`
+	Name            string `xorm:"INDEX(IDX_payee_uid_deleted_name) VARCHAR(64) NOT NULL"`
+	Aliases         string `xorm:"VARCHAR(4096) NOT NULL"`
+	Hidden          bool   `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// PayeeGetRequest represents all parameters of payee getting request
+type PayeeGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// PayeeCreateRequest represents all parameters of payee creation request
+type PayeeCreateRequest struct {
+	Name    string   `json:"name" binding:"required,notBlank,max=64"`
+	Aliases []string `json:"aliases" binding:"omitempty,max=50,dive,notBlank,max=64"`
+}
+
+// PayeeModifyRequest represents all parameters of payee modification request
+type PayeeModifyRequest struct {
+	Id      int64    `json:"id,string" binding:"required,min=1"`
+	Name    string   `json:"name" binding:"required,notBlank,max=64"`
+	Aliases []string `json:"aliases" binding:"omitempty,max=50,dive,notBlank,max=64"`
+}
+
+// PayeeHideRequest represents all parameters of payee hiding request
+type PayeeHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// PayeeMergeRequest represents all parameters of payee merging request
+type PayeeMergeRequest struct {
+	Id          int64    `json:"id,string" binding:"required,min=1"`
+	FromIds     []string `json:"fromIds" binding:"required,min=1"`
+	KeepAliases bool     `json:"keepAliases"`
+}
+
+// PayeeDeleteRequest represents all parameters of payee deleting request
+type PayeeDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// PayeeInfoResponse represents a view-object of payee
+type PayeeInfoResponse struct {
+	Id      int64    `json:"id,string"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	Hidden  bool     `json:"hidden"`
+}
+
+// TableName returns the table name of Payee
+func (p *Payee) TableName() string {
+	return "ebk_payees"
+}
+
+// GetAliases returns all aliases of the payee
+func (p *Payee) GetAliases() []string {
+	if p.Aliases == "" {
+		return []string{}
+	}
+
+	return strings.Split(p.Aliases, payeeAliasesSeparator)
+}
+
+// SetAliases sets the aliases of the payee, blank, duplicated aliases and the aliases equal to payee name are ignored
+func (p *Payee) SetAliases(aliases []string) error {
+	finalAliases := make([]string, 0, len(aliases))
+	existedAliases := make(map[string]bool, len(aliases))
+
+	for i := 0; i < len(aliases); i++ {
+		alias := strings.TrimSpace(aliases[i])
+
+		if alias == "" {
+			continue
+		}
+
+		if strings.Contains(alias, payeeAliasesSeparator) || len(alias) > 64 {
+			return errs.ErrPayeeAliasInvalid
+		}
+
+		lowerAlias := strings.ToLower(alias)
+
+		if existedAliases[lowerAlias] || strings.EqualFold(alias, p.Name) {
+			continue
+		}
+
+		existedAliases[lowerAlias] = true
+		finalAliases = append(finalAliases, alias)
+	}
+
+	if len(finalAliases) > MaximumAliasesCountOfPayee {
+		return errs.ErrPayeeHasTooManyAliases
+	}
+
+	p.Aliases = strings.Join(finalAliases, payeeAliasesSeparator)
+
+	return nil
+}
+
+// IsNameMatched returns whether the given name equals to the name or one of the aliases of the payee (case-insensitive)
+func (p *Payee) IsNameMatched(name string) bool {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return false
+	}
+
+	if strings.EqualFold(p.Name, name) {
+		return true
+	}
+
+	aliases := p.GetAliases()
+
+	for i := 0; i < len(aliases); i++ {
+		if strings.EqualFold(aliases[i], name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsNameOrAliasConflicted returns whether the name or any alias of the payee matches the name or any alias of the other payee (case-insensitive)
+func (p *Payee) IsNameOrAliasConflicted(other *Payee) bool {
+	if other.IsNameMatched(p.Name) {
+		return true
+	}
+
+	aliases := p.GetAliases()
+
+	for i := 0; i < len(aliases); i++ {
+		if other.IsNameMatched(aliases[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToPayeeInfoResponse returns a view-object according to database model
+func (p *Payee) ToPayeeInfoResponse() *PayeeInfoResponse {
+	return &PayeeInfoResponse{
+		Id:      p.PayeeId,
+		Name:    p.Name,
+		Aliases: p.GetAliases(),
+		Hidden:  p.Hidden,
+	}
+}
+
+// PayeeInfoResponseSlice represents the slice data structure of PayeeInfoResponse
+type PayeeInfoResponseSlice []*PayeeInfoResponse
+
+// Len returns the count of items
+func (s PayeeInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s PayeeInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s PayeeInfoResponseSlice) Less(i, j int) bool {
+	return strings.ToLower(s[i].Name) < strings.ToLower(s[j].Name)
+}
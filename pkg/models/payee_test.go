@@ -0,0 +1,92 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestPayeeGetAliases(t *testing.T) {
+	payee := &Payee{
+		Aliases: "AMZN\nAmazon.com",
+	}
+
+	assert.EqualValues(t, []string{"AMZN", "Amazon.com"}, payee.GetAliases())
+
+	payee = &Payee{}
+	assert.Equal(t, 0, len(payee.GetAliases()))
+}
+
+func TestPayeeSetAliases(t *testing.T) {
+	payee := &Payee{
+		Name: "Amazon",
+	}
+
+	err := payee.SetAliases([]string{" AMZN ", "", "amzn", "amazon", "Amazon.com"})
+	assert.Nil(t, err)
+	assert.Equal(t, "AMZN\nAmazon.com", payee.Aliases)
+
+	err = payee.SetAliases(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", payee.Aliases)
+}
+
+func TestPayeeSetAliases_InvalidAlias(t *testing.T) {
+	payee := &Payee{
+		Name: "Amazon",
+	}
+
+	err := payee.SetAliases([]string{"foo\nbar"})
+	assert.Equal(t, errs.ErrPayeeAliasInvalid, err)
+
+	err = payee.SetAliases([]string{strings.Repeat("a", 65)})
+	assert.Equal(t, errs.ErrPayeeAliasInvalid, err)
+}
+
+func TestPayeeSetAliases_TooManyAliases(t *testing.T) {
+	payee := &Payee{
+		Name: "Amazon",
+	}
+
+	aliases := make([]string, MaximumAliasesCountOfPayee+1)
+
+	for i := 0; i < len(aliases); i++ {
+		aliases[i] = strings.Repeat("a", i+1)
+	}
+
+	err := payee.SetAliases(aliases)
+	assert.Equal(t, errs.ErrPayeeHasTooManyAliases, err)
+
+	err = payee.SetAliases(aliases[:MaximumAliasesCountOfPayee])
+	assert.Nil(t, err)
+	assert.Equal(t, MaximumAliasesCountOfPayee, len(payee.GetAliases()))
+}
+
+func TestPayeeIsNameMatched(t *testing.T) {
+	payee := &Payee{
+		Name:    "Amazon",
+		Aliases: "AMZN\nAmazon.com",
+	}
+
+	assert.True(t, payee.IsNameMatched("amazon"))
+	assert.True(t, payee.IsNameMatched(" amzn "))
+	assert.True(t, payee.IsNameMatched("AMAZON.COM"))
+	assert.False(t, payee.IsNameMatched("Amazon Prime"))
+	assert.False(t, payee.IsNameMatched(""))
+}
+
+func TestPayeeIsNameOrAliasConflicted(t *testing.T) {
+	payee := &Payee{
+		Name:    "Amazon",
+		Aliases: "AMZN\nAmazon.com",
+	}
+
+	assert.True(t, payee.IsNameOrAliasConflicted(&Payee{Name: "AMAZON"}))
+	assert.True(t, payee.IsNameOrAliasConflicted(&Payee{Name: "Amazon Marketplace", Aliases: "amzn"}))
+	assert.True(t, payee.IsNameOrAliasConflicted(&Payee{Name: "amzn"}))
+	assert.True(t, payee.IsNameOrAliasConflicted(&Payee{Name: "Online Shop", Aliases: "eBay\namazon.COM"}))
+	assert.False(t, payee.IsNameOrAliasConflicted(&Payee{Name: "eBay", Aliases: "Amazon Prime"}))
+}
@@ -5,21 +5,36 @@ import "github.com/mayswind/ezbookkeeping/pkg/core"
 // TokenMaxUserAgentLength represents the maximum size of user agent stored in database
 const TokenMaxUserAgentLength = 255
 
+// TokenMaxNameLength represents the maximum size of personal access token name stored in database
+const TokenMaxNameLength = 64
+
 // TokenRecord represents token data stored in database
 type TokenRecord struct {
-	Uid              int64          `xorm:"PK INDEX(IDX_token_record_uid_type_expired_time) INDEX(IDX_token_record_expired_time)"`
-	UserTokenId      int64          `xorm:"PK"`
-	TokenType        core.TokenType `xorm:"INDEX(IDX_token_record_uid_type_expired_time) TINYINT NOT NULL"`
-	Secret           string         `xorm:"VARCHAR(10) NOT NULL"`
-	UserAgent        string         `xorm:"VARCHAR(255)"`
-	CreatedUnixTime  int64          `xorm:"PK"`
-	ExpiredUnixTime  int64          `xorm:"INDEX(IDX_token_record_uid_type_expired_time) INDEX(IDX_token_record_expired_time)"`
+	Uid              int64                 `xorm:"PK INDEX(IDX_token_record_uid_type_expired_time) INDEX(IDX_token_record_expired_time)"`
+	UserTokenId      int64                 `xorm:"PK"`
+	TokenType        core.TokenType        `xorm:"INDEX(IDX_token_record_uid_type_expired_time) TINYINT NOT NULL"`
+	Scope            core.TokenScope       `xorm:"TINYINT"`
+	Name             string                `xorm:"VARCHAR(64)"`
+	Permissions      core.TokenPermissions `xorm:"BIGINT"`
+	Secret           string                `xorm:"VARCHAR(10) NOT NULL"`
+	UserAgent        string                `xorm:"VARCHAR(255)"`
+	CreatedUnixTime  int64                 `xorm:"PK"`
+	ExpiredUnixTime  int64                 `xorm:"INDEX(IDX_token_record_uid_type_expired_time) INDEX(IDX_token_record_expired_time)"`
 	LastSeenUnixTime int64
 }
 
 // TokenGenerateMCPRequest represents all parameters of mcp token generation request
 type TokenGenerateMCPRequest struct {
 	Password string `json:"password" binding:"omitempty,min=6,max=128"`
+	ReadOnly bool   `json:"readOnly"`
+}
+
+// TokenGeneratePersonalAccessRequest represents all parameters of personal access token generation request
+type TokenGeneratePersonalAccessRequest struct {
+	Password      string   `json:"password" binding:"omitempty,min=6,max=128"`
+	Name          string   `json:"name" binding:"required,notBlank,max=64"`
+	Permissions   []string `json:"permissions" binding:"required,min=1"`
+	ExpiredInDays int      `json:"expiredInDays" binding:"min=0,max=3650"`
 }
 
 // TokenRevokeRequest represents all parameters of token revoking request
@@ -33,6 +48,12 @@ type TokenGenerateMCPResponse struct {
 	MCPUrl string `json:"mcpUrl"`
 }
 
+// TokenGeneratePersonalAccessResponse represents all response parameters of generated personal access token
+type TokenGeneratePersonalAccessResponse struct {
+	Token     string `json:"token"`
+	ExpiredAt int64  `json:"expiredAt"`
+}
+
 // TokenRefreshResponse represents all response parameters of token refreshing
 type TokenRefreshResponse struct {
 	NewToken                 string                        `json:"newToken,omitempty"`
@@ -44,11 +65,14 @@ type TokenRefreshResponse struct {
 
 // TokenInfoResponse represents a view-object of token
 type TokenInfoResponse struct {
-	TokenId   string         `json:"tokenId"`
-	TokenType core.TokenType `json:"tokenType"`
-	UserAgent string         `json:"userAgent"`
-	LastSeen  int64          `json:"lastSeen"`
-	IsCurrent bool           `json:"isCurrent"`
+	TokenId     string         `json:"tokenId"`
+	TokenType   core.TokenType `json:"tokenType"`
+	ReadOnly    bool           `json:"readOnly,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	Permissions []string       `json:"permissions,omitempty"`
+	UserAgent   string         `json:"userAgent"`
+	LastSeen    int64          `json:"lastSeen"`
+	IsCurrent   bool           `json:"isCurrent"`
 }
 
 // TokenInfoResponseSlice represents the slice data structure of TokenInfoResponse
@@ -98,26 +98,31 @@ const (
 // Transaction represents transaction data stored in database
 type Transaction struct {
 	TransactionId        int64             `xorm:"PK"`
-	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
+	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_payee_id_time) NOT NULL"`
+	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_payee_id_time) NOT NULL"`
 	Type                 TransactionDbType `xorm:"INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
 	CategoryId           int64             `xorm:"INDEX(IDX_transaction_uid_deleted_category_id_time) NOT NULL"`
 	AccountId            int64             `xorm:"INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
-	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) NOT NULL"`
+	TransactionTime      int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_payee_id_time) NOT NULL"`
 	TimezoneUtcOffset    int16             `xorm:"NOT NULL"`
 	Amount               int64             `xorm:"NOT NULL"`
 	RelatedId            int64             `xorm:"NOT NULL"`
 	RelatedAccountId     int64             `xorm:"NOT NULL"`
 	RelatedAccountAmount int64             `xorm:"NOT NULL"`
-	HideAmount           bool              `xorm:"NOT NULL"`
-	Comment              string            `xorm:"VARCHAR(255) NOT NULL"`
-	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
-	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
-	CreatedIp            string            `xorm:"VARCHAR(39)"`
+	ExchangeRateCurrency string            `xorm:"VARCHAR(3)"`
+	ExchangeRate         int64
+	HideAmount           bool    `xorm:"NOT NULL"`
+	PayeeId              int64   `xorm:"INDEX(IDX_transaction_uid_deleted_payee_id_time) NOT NULL DEFAULT 0"`
+	Comment              string  `xorm:"VARCHAR(255) NOT NULL"`
+	GeoLongitude         float64 `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	GeoLatitude          float64 `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
+	CreatedIp            string  `xorm:"VARCHAR(39)"`
 	ScheduledCreated     bool
+	HasSplits            bool
 	CreatedUnixTime      int64
 	UpdatedUnixTime      int64
 	DeletedUnixTime      int64
+	Splits               []*TransactionSplit `xorm:"-"`
 }
 
 // TransactionWithAccountBalance represents a transaction item with account balance
@@ -145,9 +150,13 @@ type TransactionCreateRequest struct {
 	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
 	HideAmount           bool                           `json:"hideAmount"`
 	TagIds               []string                       `json:"tagIds"`
+	PayeeId              int64                          `json:"payeeId,string" binding:"min=0"`
 	PictureIds           []string                       `json:"pictureIds"`
+	PayeeName            string                         `json:"payeeName" binding:"max=64"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,dive"`
+	ApplyRules           bool                           `json:"applyRules"`
 	ClientSessionId      string                         `json:"clientSessionId"`
 }
 
@@ -163,9 +172,11 @@ type TransactionModifyRequest struct {
 	DestinationAmount    int64                          `json:"destinationAmount" binding:"min=-99999999999,max=99999999999"`
 	HideAmount           bool                           `json:"hideAmount"`
 	TagIds               []string                       `json:"tagIds"`
+	PayeeId              int64                          `json:"payeeId,string" binding:"min=0"`
 	PictureIds           []string                       `json:"pictureIds"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,dive"`
 }
 
 // TransactionImportRequest represents all parameters of transaction import request
@@ -186,8 +197,10 @@ type TransactionCountRequest struct {
 	AccountIds    string                   `form:"account_ids"`
 	TagIds        string                   `form:"tag_ids"`
 	TagFilterType TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	PayeeIds      string                   `form:"payee_ids"`
 	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword       string                   `form:"keyword"`
+	Query         string                   `form:"query" binding:"max=1000"`
 	MaxTime       int64                    `form:"max_time" binding:"min=0"` // Transaction time sequence id
 	MinTime       int64                    `form:"min_time" binding:"min=0"` // Transaction time sequence id
 }
@@ -199,8 +212,10 @@ type TransactionListByMaxTimeRequest struct {
 	AccountIds    string                   `form:"account_ids"`
 	TagIds        string                   `form:"tag_ids"`
 	TagFilterType TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	PayeeIds      string                   `form:"payee_ids"`
 	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword       string                   `form:"keyword"`
+	Query         string                   `form:"query" binding:"max=1000"`
 	MaxTime       int64                    `form:"max_time" binding:"min=0"` // Transaction time sequence id
 	MinTime       int64                    `form:"min_time" binding:"min=0"` // Transaction time sequence id
 	Page          int32                    `form:"page" binding:"min=0"`
@@ -221,8 +236,10 @@ type TransactionListInMonthByPageRequest struct {
 	AccountIds    string                   `form:"account_ids"`
 	TagIds        string                   `form:"tag_ids"`
 	TagFilterType TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	PayeeIds      string                   `form:"payee_ids"`
 	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
 	Keyword       string                   `form:"keyword"`
+	Query         string                   `form:"query" binding:"max=1000"`
 	WithPictures  bool                     `form:"with_pictures"`
 	TrimAccount   bool                     `form:"trim_account"`
 	TrimCategory  bool                     `form:"trim_category"`
@@ -242,8 +259,11 @@ type TransactionStatisticRequest struct {
 	EndTime                int64                    `form:"end_time" binding:"min=0"`
 	TagIds                 string                   `form:"tag_ids"`
 	TagFilterType          TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	PayeeIds               string                   `form:"payee_ids"`
+	GroupByPayee           bool                     `form:"group_by_payee"`
 	Keyword                string                   `form:"keyword"`
 	UseTransactionTimezone bool                     `form:"use_transaction_timezone"`
+	ReportingCurrency      string                   `form:"reporting_currency" binding:"omitempty,len=3,validCurrency"`
 }
 
 // TransactionStatisticTrendsRequest represents all parameters of transaction statistic trends request
@@ -251,14 +271,18 @@ type TransactionStatisticTrendsRequest struct {
 	YearMonthRangeRequest
 	TagIds                 string                   `form:"tag_ids"`
 	TagFilterType          TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	PayeeIds               string                   `form:"payee_ids"`
+	GroupByPayee           bool                     `form:"group_by_payee"`
 	Keyword                string                   `form:"keyword"`
 	UseTransactionTimezone bool                     `form:"use_transaction_timezone"`
+	ReportingCurrency      string                   `form:"reporting_currency" binding:"omitempty,len=3,validCurrency"`
 }
 
 // TransactionAmountsRequest represents all parameters of transaction amounts request
 type TransactionAmountsRequest struct {
 	Query                  string `form:"query"`
 	UseTransactionTimezone bool   `form:"use_transaction_timezone"`
+	ReportingCurrency      string `form:"reporting_currency" binding:"omitempty,len=3,validCurrency"`
 }
 
 // TransactionAmountsRequestItem represents an item of transaction amounts request
@@ -312,9 +336,11 @@ type TransactionInfoResponse struct {
 	HideAmount           bool                                     `json:"hideAmount"`
 	TagIds               []string                                 `json:"tagIds"`
 	Tags                 []*TransactionTagInfoResponse            `json:"tags,omitempty"`
+	PayeeId              int64                                    `json:"payeeId,string,omitempty"`
 	Pictures             TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
 	Comment              string                                   `json:"comment"`
 	GeoLocation          *TransactionGeoLocationResponse          `json:"geoLocation,omitempty"`
+	Splits               []*TransactionSplitInfoResponse          `json:"splits,omitempty"`
 	Editable             bool                                     `json:"editable"`
 }
 
@@ -354,30 +380,40 @@ type TransactionReconciliationStatementResponse struct {
 
 // TransactionStatisticResponse represents transaction statistic response
 type TransactionStatisticResponse struct {
-	StartTime int64                               `json:"startTime"`
-	EndTime   int64                               `json:"endTime"`
-	Items     []*TransactionStatisticResponseItem `json:"items"`
+	StartTime             int64                               `json:"startTime"`
+	EndTime               int64                               `json:"endTime"`
+	Items                 []*TransactionStatisticResponseItem `json:"items"`
+	ReportingCurrency     string                              `json:"reportingCurrency,omitempty"`
+	UnconvertedCurrencies []string                            `json:"unconvertedCurrencies,omitempty"`
 }
 
 // TransactionStatisticResponseItem represents total amount item for a response
 type TransactionStatisticResponseItem struct {
-	CategoryId  int64 `json:"categoryId,string"`
-	AccountId   int64 `json:"accountId,string"`
-	TotalAmount int64 `json:"amount"`
+	CategoryId      int64  `json:"categoryId,string"`
+	AccountId       int64  `json:"accountId,string"`
+	PayeeId         int64  `json:"payeeId,string,omitempty"`
+	TotalAmount     int64  `json:"amount"`
+	ConvertedAmount *int64 `json:"convertedAmount,omitempty"`
 }
 
 // TransactionStatisticTrendsResponseItem represents the data within each statistic interval
 type TransactionStatisticTrendsResponseItem struct {
-	Year  int32                               `json:"year"`
-	Month int32                               `json:"month"`
-	Items []*TransactionStatisticResponseItem `json:"items"`
+	Year                  int32                               `json:"year"`
+	Month                 int32                               `json:"month"`
+	Items                 []*TransactionStatisticResponseItem `json:"items"`
+	ReportingCurrency     string                              `json:"reportingCurrency,omitempty"`
+	UnconvertedCurrencies []string                            `json:"unconvertedCurrencies,omitempty"`
 }
 
 // TransactionAmountsResponseItem represents an item of transaction amounts
 type TransactionAmountsResponseItem struct {
-	StartTime int64                                       `json:"startTime"`
-	EndTime   int64                                       `json:"endTime"`
-	Amounts   []*TransactionAmountsResponseItemAmountInfo `json:"amounts"`
+	StartTime              int64                                       `json:"startTime"`
+	EndTime                int64                                       `json:"endTime"`
+	Amounts                []*TransactionAmountsResponseItemAmountInfo `json:"amounts"`
+	ReportingCurrency      string                                      `json:"reportingCurrency,omitempty"`
+	ConvertedIncomeAmount  *int64                                      `json:"convertedIncomeAmount,omitempty"`
+	ConvertedExpenseAmount *int64                                      `json:"convertedExpenseAmount,omitempty"`
+	UnconvertedCurrencies  []string                                    `json:"unconvertedCurrencies,omitempty"`
 }
 
 // TransactionMonthAmountsResponseItem represents an item of transaction month amounts
@@ -459,9 +495,11 @@ func (t *Transaction) ToTransactionInfoResponse(tagIds []int64, editable bool) *
 		SourceAmount:         sourceAmount,
 		DestinationAmount:    destinationAmount,
 		HideAmount:           t.HideAmount,
+		PayeeId:              t.PayeeId,
 		TagIds:               utils.Int64ArrayToStringArray(tagIds),
 		Comment:              t.Comment,
 		GeoLocation:          geoLocation,
+		Splits:               GetTransactionSplitInfoResponses(t.Splits),
 		Editable:             editable,
 	}
 }
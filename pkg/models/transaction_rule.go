@@ -0,0 +1,450 @@
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const MaximumTagsCountOfTransactionRule = 10
+
+// MaximumSplitsCountOfTransactionRule represents the maximum count of split lines of transaction rule
+const MaximumSplitsCountOfTransactionRule = 10
+
+// TransactionRuleSplitRatioTotal represents the total ratio of all split lines of transaction rule (ratios are in basis points)
+const TransactionRuleSplitRatioTotal = 10000
+
+// TransactionRule represents transaction auto-categorization rule stored in database
+type TransactionRule struct {
+	RuleId              int64           `xorm:"PK"`
+	Uid                 int64           `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Deleted             bool            `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Name                string          `xorm:"VARCHAR(64) NOT NULL"`
+	TransactionType     TransactionType `xorm:"NOT NULL"`
+	AccountId           int64           `xorm:"NOT NULL"`
+	CommentPattern      string          `xorm:"VARCHAR(255) NOT NULL"`
+	CounterpartyPattern string          `xorm:"VARCHAR(255) NOT NULL"`
+	MinAmount           *int64
+	MaxAmount           *int64
+	CategoryId          int64                     `xorm:"NOT NULL"`
+	TagIds              string                    `xorm:"VARCHAR(255) NOT NULL"`
+	Splits              TransactionRuleSplitSlice `xorm:"BLOB"`
+	RewriteComment      bool                      `xorm:"NOT NULL"`
+	NewComment          string                    `xorm:"VARCHAR(255) NOT NULL"`
+	StopProcessing      bool                      `xorm:"NOT NULL"`
+	DisplayOrder        int32                     `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Disabled            bool                      `xorm:"NOT NULL"`
+	CreatedUnixTime     int64
+	UpdatedUnixTime     int64
+	DeletedUnixTime     int64
+}
+
+// TransactionRuleSplitSlice represents the slice data structure of TransactionRuleSplit
+type TransactionRuleSplitSlice []*TransactionRuleSplit
+
+// TransactionRuleSplit represents a split line which the transaction rule splits the matched transaction into
+type TransactionRuleSplit struct {
+	CategoryId int64  `json:"categoryId,string" binding:"required,min=1"`
+	Ratio      int32  `json:"ratio" binding:"required,min=1,max=10000"`
+	Comment    string `json:"comment" binding:"max=255"`
+}
+
+// TransactionRuleGetRequest represents all parameters of transaction rule getting request
+type TransactionRuleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleCreateRequest represents all parameters of transaction rule creation request
+type TransactionRuleCreateRequest struct {
+	Name                string                    `json:"name" binding:"required,notBlank,max=64"`
+	TransactionType     TransactionType           `json:"transactionType" binding:"min=0,max=4"`
+	AccountId           int64                     `json:"accountId,string" binding:"min=0"`
+	CommentPattern      string                    `json:"commentPattern" binding:"max=255"`
+	CounterpartyPattern string                    `json:"counterpartyPattern" binding:"max=255"`
+	MinAmount           *int64                    `json:"minAmount" binding:"omitempty,min=0,max=99999999999"`
+	MaxAmount           *int64                    `json:"maxAmount" binding:"omitempty,min=0,max=99999999999"`
+	CategoryId          int64                     `json:"categoryId,string" binding:"min=0"`
+	TagIds              []string                  `json:"tagIds"`
+	Splits              TransactionRuleSplitSlice `json:"splits" binding:"omitempty,dive"`
+	RewriteComment      bool                      `json:"rewriteComment"`
+	NewComment          string                    `json:"newComment" binding:"max=255"`
+	StopProcessing      bool                      `json:"stopProcessing"`
+	ClientSessionId     string                    `json:"clientSessionId"`
+}
+
+// TransactionRuleModifyRequest represents all parameters of transaction rule modification request
+type TransactionRuleModifyRequest struct {
+	Id                  int64                     `json:"id,string" binding:"required,min=1"`
+	Name                string                    `json:"name" binding:"required,notBlank,max=64"`
+	TransactionType     TransactionType           `json:"transactionType" binding:"min=0,max=4"`
+	AccountId           int64                     `json:"accountId,string" binding:"min=0"`
+	CommentPattern      string                    `json:"commentPattern" binding:"max=255"`
+	CounterpartyPattern string                    `json:"counterpartyPattern" binding:"max=255"`
+	MinAmount           *int64                    `json:"minAmount" binding:"omitempty,min=0,max=99999999999"`
+	MaxAmount           *int64                    `json:"maxAmount" binding:"omitempty,min=0,max=99999999999"`
+	CategoryId          int64                     `json:"categoryId,string" binding:"min=0"`
+	TagIds              []string                  `json:"tagIds"`
+	Splits              TransactionRuleSplitSlice `json:"splits" binding:"omitempty,dive"`
+	RewriteComment      bool                      `json:"rewriteComment"`
+	NewComment          string                    `json:"newComment" binding:"max=255"`
+	StopProcessing      bool                      `json:"stopProcessing"`
+}
+
+// TransactionRuleDisableRequest represents all parameters of transaction rule disabling request
+type TransactionRuleDisableRequest struct {
+	Id       int64 `json:"id,string" binding:"required,min=1"`
+	Disabled bool  `json:"disabled"`
+}
+
+// TransactionRuleMoveRequest represents all parameters of transaction rule moving request
+type TransactionRuleMoveRequest struct {
+	NewDisplayOrders []*TransactionRuleNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// TransactionRuleNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionRuleNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionRuleDeleteRequest represents all parameters of transaction rule deleting request
+type TransactionRuleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleTestRequest represents all parameters of testing a transaction rule against transaction history
+type TransactionRuleTestRequest struct {
+	TransactionType     TransactionType           `json:"transactionType" binding:"min=0,max=4"`
+	AccountId           int64                     `json:"accountId,string" binding:"min=0"`
+	CommentPattern      string                    `json:"commentPattern" binding:"max=255"`
+	CounterpartyPattern string                    `json:"counterpartyPattern" binding:"max=255"`
+	MinAmount           *int64                    `json:"minAmount" binding:"omitempty,min=0,max=99999999999"`
+	MaxAmount           *int64                    `json:"maxAmount" binding:"omitempty,min=0,max=99999999999"`
+	CategoryId          int64                     `json:"categoryId,string" binding:"min=0"`
+	TagIds              []string                  `json:"tagIds"`
+	Splits              TransactionRuleSplitSlice `json:"splits" binding:"omitempty,dive"`
+	RewriteComment      bool                      `json:"rewriteComment"`
+	NewComment          string                    `json:"newComment" binding:"max=255"`
+	Count               int32                     `json:"count" binding:"min=0,max=1000"`
+}
+
+// TransactionRuleInfoResponse represents a view-object of transaction rule
+type TransactionRuleInfoResponse struct {
+	Id                  int64                     `json:"id,string"`
+	Name                string                    `json:"name"`
+	TransactionType     TransactionType           `json:"transactionType"`
+	AccountId           int64                     `json:"accountId,string"`
+	CommentPattern      string                    `json:"commentPattern"`
+	CounterpartyPattern string                    `json:"counterpartyPattern"`
+	MinAmount           *int64                    `json:"minAmount,omitempty"`
+	MaxAmount           *int64                    `json:"maxAmount,omitempty"`
+	CategoryId          int64                     `json:"categoryId,string"`
+	TagIds              []string                  `json:"tagIds"`
+	Splits              TransactionRuleSplitSlice `json:"splits"`
+	RewriteComment      bool                      `json:"rewriteComment"`
+	NewComment          string                    `json:"newComment"`
+	StopProcessing      bool                      `json:"stopProcessing"`
+	DisplayOrder        int32                     `json:"displayOrder"`
+	Disabled            bool                      `json:"disabled"`
+}
+
+// TransactionRuleTestResponse represents the result of testing a transaction rule against transaction history
+type TransactionRuleTestResponse struct {
+	TotalCount   int64                              `json:"totalCount"`
+	MatchedCount int64                              `json:"matchedCount"`
+	Items        []*TransactionRuleTestResponseItem `json:"items"`
+}
+
+// TransactionRuleTestResponseItem represents a transaction which matches the tested transaction rule
+type TransactionRuleTestResponseItem struct {
+	TransactionId int64                           `json:"transactionId,string"`
+	Time          int64                           `json:"time"`
+	Type          TransactionType                 `json:"type"`
+	AccountId     int64                           `json:"accountId,string"`
+	Amount        int64                           `json:"amount"`
+	CategoryId    int64                           `json:"categoryId,string"`
+	Comment       string                          `json:"comment"`
+	NewCategoryId int64                           `json:"newCategoryId,string,omitempty"`
+	NewSplits     []*TransactionSplitInfoResponse `json:"newSplits,omitempty"`
+	NewComment    *string                         `json:"newComment,omitempty"`
+	AddedTagIds   []string                        `json:"addedTagIds,omitempty"`
+}
+
+// TransactionRuleMatcher represents a compiled transaction rule which is used to match transactions
+type TransactionRuleMatcher struct {
+	Rule              *TransactionRule
+	commentRegex      *regexp.Regexp
+	counterpartyRegex *regexp.Regexp
+}
+
+// GetTagIds returns all tag ids of the transaction rule
+func (r *TransactionRule) GetTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if r.TagIds != "" {
+		tagIds = strings.Split(r.TagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// HasCondition returns whether the transaction rule has at least one condition
+func (r *TransactionRule) HasCondition() bool {
+	return r.TransactionType > 0 || r.AccountId > 0 || r.CommentPattern != "" || r.CounterpartyPattern != "" || r.MinAmount != nil || r.MaxAmount != nil
+}
+
+// HasAction returns whether the transaction rule has at least one action
+func (r *TransactionRule) HasAction() bool {
+	return r.CategoryId > 0 || len(r.Splits) > 0 || r.TagIds != "" || r.RewriteComment
+}
+
+// GetMatchableTransactionTypes returns all transaction types which the transaction rule can match
+func (r *TransactionRule) GetMatchableTransactionTypes() []TransactionType {
+	if r.TransactionType > 0 {
+		return []TransactionType{r.TransactionType}
+	}
+
+	return []TransactionType{TRANSACTION_TYPE_MODIFY_BALANCE, TRANSACTION_TYPE_INCOME, TRANSACTION_TYPE_EXPENSE, TRANSACTION_TYPE_TRANSFER}
+}
+
+// ValidateSplits returns whether the split lines of the transaction rule are valid
+func (r *TransactionRule) ValidateSplits() error {
+	if len(r.Splits) < 1 {
+		return nil
+	}
+
+	if r.CategoryId > 0 {
+		return errs.ErrTransactionRuleCategoryAndSplitsConflicted
+	}
+
+	if len(r.Splits) < TransactionSplitMinCount || len(r.Splits) > MaximumSplitsCountOfTransactionRule {
+		return errs.ErrTransactionRuleSplitCountInvalid
+	}
+
+	totalRatio := int32(0)
+
+	for i := 0; i < len(r.Splits); i++ {
+		if r.Splits[i].Ratio <= 0 {
+			return errs.ErrTransactionRuleSplitRatioInvalid
+		}
+
+		totalRatio += r.Splits[i].Ratio
+	}
+
+	if totalRatio != TransactionRuleSplitRatioTotal {
+		return errs.ErrTransactionRuleSplitRatioInvalid
+	}
+
+	return nil
+}
+
+// GetSplitsByAmount returns the split lines of the specified amount according to the split ratios of the transaction rule,
+// the rounding difference is added to the last split line so that the sum of split lines always equals the amount
+func (r *TransactionRule) GetSplitsByAmount(amount int64) []*TransactionSplit {
+	if len(r.Splits) < 1 {
+		return nil
+	}
+
+	splits := make([]*TransactionSplit, len(r.Splits))
+	remainingAmount := amount
+
+	for i := 0; i < len(r.Splits); i++ {
+		splitAmount := remainingAmount
+
+		if i < len(r.Splits)-1 {
+			splitAmount = amount * int64(r.Splits[i].Ratio) / TransactionRuleSplitRatioTotal
+		}
+
+		splits[i] = &TransactionSplit{
+			CategoryId: r.Splits[i].CategoryId,
+			Amount:     splitAmount,
+			Comment:    r.Splits[i].Comment,
+		}
+
+		remainingAmount -= splitAmount
+	}
+
+	return splits
+}
+
+// CreateMatcher returns a compiled matcher of the transaction rule
+func (r *TransactionRule) CreateMatcher() (*TransactionRuleMatcher, error) {
+	matcher := &TransactionRuleMatcher{
+		Rule: r,
+	}
+
+	if r.CommentPattern != "" {
+		commentRegex, err := regexp.Compile(r.CommentPattern)
+
+		if err != nil {
+			return nil, errs.ErrTransactionRuleCommentPatternInvalid
+		}
+
+		matcher.commentRegex = commentRegex
+	}
+
+	if r.CounterpartyPattern != "" {
+		counterpartyRegex, err := regexp.Compile(r.CounterpartyPattern)
+
+		if err != nil {
+			return nil, errs.ErrTransactionRuleCounterpartyPatternInvalid
+		}
+
+		matcher.counterpartyRegex = counterpartyRegex
+	}
+
+	return matcher, nil
+}
+
+// ToTransactionRuleInfoResponse returns a view-object according to database model
+func (r *TransactionRule) ToTransactionRuleInfoResponse() *TransactionRuleInfoResponse {
+	tagIds := make([]string, 0)
+
+	if r.TagIds != "" {
+		tagIds = strings.Split(r.TagIds, ",")
+	}
+
+	splits := r.Splits
+
+	if splits == nil {
+		splits = make(TransactionRuleSplitSlice, 0)
+	}
+
+	return &TransactionRuleInfoResponse{
+		Id:                  r.RuleId,
+		Name:                r.Name,
+		TransactionType:     r.TransactionType,
+		AccountId:           r.AccountId,
+		CommentPattern:      r.CommentPattern,
+		CounterpartyPattern: r.CounterpartyPattern,
+		MinAmount:           r.MinAmount,
+		MaxAmount:           r.MaxAmount,
+		CategoryId:          r.CategoryId,
+		TagIds:              tagIds,
+		Splits:              splits,
+		RewriteComment:      r.RewriteComment,
+		NewComment:          r.NewComment,
+		StopProcessing:      r.StopProcessing,
+		DisplayOrder:        r.DisplayOrder,
+		Disabled:            r.Disabled,
+	}
+}
+
+// IsMatched returns whether the specified transaction data matches all conditions of the transaction rule
+func (m *TransactionRuleMatcher) IsMatched(transactionType TransactionType, accountId int64, amount int64, comment string, counterparty string) bool {
+	if m.Rule.TransactionType > 0 && m.Rule.TransactionType != transactionType {
+		return false
+	}
+
+	if m.Rule.AccountId > 0 && m.Rule.AccountId != accountId {
+		return false
+	}
+
+	if amount < 0 {
+		amount = -amount
+	}
+
+	if m.Rule.MinAmount != nil && amount < *m.Rule.MinAmount {
+		return false
+	}
+
+	if m.Rule.MaxAmount != nil && amount > *m.Rule.MaxAmount {
+		return false
+	}
+
+	if m.commentRegex != nil && !m.commentRegex.MatchString(comment) {
+		return false
+	}
+
+	if m.counterpartyRegex != nil && (counterparty == "" || !m.counterpartyRegex.MatchString(counterparty)) {
+		return false
+	}
+
+	return true
+}
+
+// GetRewrittenComment returns the comment after rewriting by the transaction rule,
+// the comment pattern groups can be referenced in new comment (e.g. $1) when comment pattern is set
+func (m *TransactionRuleMatcher) GetRewrittenComment(comment string) string {
+	if !m.Rule.RewriteComment {
+		return comment
+	}
+
+	if m.commentRegex != nil {
+		return m.commentRegex.ReplaceAllString(comment, m.Rule.NewComment)
+	}
+
+	return m.Rule.NewComment
+}
+
+// IsTransactionRuleSplitsEqual returns whether the two split line lists of transaction rule have the same content
+func IsTransactionRuleSplitsEqual(splits1 TransactionRuleSplitSlice, splits2 TransactionRuleSplitSlice) bool {
+	if len(splits1) != len(splits2) {
+		return false
+	}
+
+	for i := 0; i < len(splits1); i++ {
+		if splits1[i].CategoryId != splits2[i].CategoryId ||
+			splits1[i].Ratio != splits2[i].Ratio ||
+			splits1[i].Comment != splits2[i].Comment {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FromDB fills the fields from the data stored in database
+func (s *TransactionRuleSplitSlice) FromDB(data []byte) error {
+	return json.Unmarshal(data, s)
+}
+
+// ToDB returns the actual stored data in database
+func (s *TransactionRuleSplitSlice) ToDB() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// TransactionRuleInfoResponseSlice represents the slice data structure of TransactionRuleInfoResponse
+type TransactionRuleInfoResponseSlice []*TransactionRuleInfoResponse
+
+// Len returns the count of items
+func (s TransactionRuleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionRuleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionRuleInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
+
+// TransactionRuleSlice represents the slice data structure of TransactionRule
+type TransactionRuleSlice []*TransactionRule
+
+// Len returns the count of items
+func (s TransactionRuleSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionRuleSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionRuleSlice) Less(i, j int) bool {
+	if s[i].DisplayOrder != s[j].DisplayOrder {
+		return s[i].DisplayOrder < s[j].DisplayOrder
+	}
+
+	return s[i].RuleId < s[j].RuleId
+}
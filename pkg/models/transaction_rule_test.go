@@ -0,0 +1,201 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestTransactionRuleGetTagIds(t *testing.T) {
+	rule := &TransactionRule{
+		TagIds: "1,2,3",
+	}
+	assert.Equal(t, []int64{1, 2, 3}, rule.GetTagIds())
+
+	rule = &TransactionRule{}
+	assert.Equal(t, 0, len(rule.GetTagIds()))
+}
+
+func TestTransactionRuleHasConditionAndAction(t *testing.T) {
+	rule := &TransactionRule{}
+	assert.False(t, rule.HasCondition())
+	assert.False(t, rule.HasAction())
+
+	minAmount := int64(100)
+	rule = &TransactionRule{
+		MinAmount:      &minAmount,
+		RewriteComment: true,
+	}
+	assert.True(t, rule.HasCondition())
+	assert.True(t, rule.HasAction())
+}
+
+func TestTransactionRuleGetMatchableTransactionTypes(t *testing.T) {
+	rule := &TransactionRule{
+		TransactionType: TRANSACTION_TYPE_EXPENSE,
+	}
+	assert.Equal(t, []TransactionType{TRANSACTION_TYPE_EXPENSE}, rule.GetMatchableTransactionTypes())
+
+	rule = &TransactionRule{}
+	assert.Equal(t, []TransactionType{TRANSACTION_TYPE_MODIFY_BALANCE, TRANSACTION_TYPE_INCOME, TRANSACTION_TYPE_EXPENSE, TRANSACTION_TYPE_TRANSFER}, rule.GetMatchableTransactionTypes())
+}
+
+func TestTransactionRuleValidateSplits(t *testing.T) {
+	rule := &TransactionRule{}
+	assert.Nil(t, rule.ValidateSplits())
+
+	rule.Splits = TransactionRuleSplitSlice{
+		{CategoryId: 1, Ratio: 6000},
+		{CategoryId: 2, Ratio: 4000},
+	}
+	assert.Nil(t, rule.ValidateSplits())
+	assert.True(t, rule.HasAction())
+
+	rule.Splits[1].Ratio = 3000
+	assert.Equal(t, errs.ErrTransactionRuleSplitRatioInvalid, rule.ValidateSplits())
+
+	rule.Splits = rule.Splits[:1]
+	rule.Splits[0].Ratio = 10000
+	assert.Equal(t, errs.ErrTransactionRuleSplitCountInvalid, rule.ValidateSplits())
+
+	rule.CategoryId = 3
+	assert.Equal(t, errs.ErrTransactionRuleCategoryAndSplitsConflicted, rule.ValidateSplits())
+}
+
+func TestTransactionRuleGetSplitsByAmount(t *testing.T) {
+	rule := &TransactionRule{
+		Splits: TransactionRuleSplitSlice{
+			{CategoryId: 1, Ratio: 3333, Comment: "foo"},
+			{CategoryId: 2, Ratio: 3333},
+			{CategoryId: 3, Ratio: 3334},
+		},
+	}
+
+	splits := rule.GetSplitsByAmount(1000)
+	assert.Equal(t, 3, len(splits))
+	assert.Equal(t, int64(1), splits[0].CategoryId)
+	assert.Equal(t, int64(333), splits[0].Amount)
+	assert.Equal(t, "foo", splits[0].Comment)
+	assert.Equal(t, int64(333), splits[1].Amount)
+	assert.Equal(t, int64(334), splits[2].Amount)
+	assert.Equal(t, int64(1000), GetTransactionSplitsTotalAmount(splits))
+
+	splits = rule.GetSplitsByAmount(-1001)
+	assert.Equal(t, int64(-1001), GetTransactionSplitsTotalAmount(splits))
+
+	rule = &TransactionRule{}
+	assert.Nil(t, rule.GetSplitsByAmount(1000))
+}
+
+func TestIsTransactionRuleSplitsEqual(t *testing.T) {
+	splits1 := TransactionRuleSplitSlice{
+		{CategoryId: 1, Ratio: 5000},
+		{CategoryId: 2, Ratio: 5000, Comment: "foo"},
+	}
+	splits2 := TransactionRuleSplitSlice{
+		{CategoryId: 1, Ratio: 5000},
+		{CategoryId: 2, Ratio: 5000, Comment: "foo"},
+	}
+	assert.True(t, IsTransactionRuleSplitsEqual(splits1, splits2))
+	assert.True(t, IsTransactionRuleSplitsEqual(nil, TransactionRuleSplitSlice{}))
+
+	splits2[1].Comment = "bar"
+	assert.False(t, IsTransactionRuleSplitsEqual(splits1, splits2))
+	assert.False(t, IsTransactionRuleSplitsEqual(splits1, splits2[:1]))
+}
+
+func TestTransactionRuleCreateMatcher_InvalidPattern(t *testing.T) {
+	rule := &TransactionRule{
+		CommentPattern: "(",
+	}
+	_, err := rule.CreateMatcher()
+	assert.EqualError(t, err, errs.ErrTransactionRuleCommentPatternInvalid.Message)
+
+	rule = &TransactionRule{
+		CounterpartyPattern: "[a-",
+	}
+	_, err = rule.CreateMatcher()
+	assert.EqualError(t, err, errs.ErrTransactionRuleCounterpartyPatternInvalid.Message)
+}
+
+func TestTransactionRuleMatcherIsMatched(t *testing.T) {
+	minAmount := int64(1000)
+	maxAmount := int64(5000)
+	rule := &TransactionRule{
+		TransactionType:     TRANSACTION_TYPE_EXPENSE,
+		AccountId:           123,
+		CommentPattern:      "(?i)coffee",
+		CounterpartyPattern: "^Star",
+		MinAmount:           &minAmount,
+		MaxAmount:           &maxAmount,
+	}
+
+	matcher, err := rule.CreateMatcher()
+	assert.Nil(t, err)
+
+	assert.True(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 123, 2000, "Morning Coffee", "Starbucks"))
+	assert.True(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 123, -2000, "Morning Coffee", "Starbucks"))
+	assert.False(t, matcher.IsMatched(TRANSACTION_TYPE_INCOME, 123, 2000, "Morning Coffee", "Starbucks"))
+	assert.False(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 456, 2000, "Morning Coffee", "Starbucks"))
+	assert.False(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 123, 999, "Morning Coffee", "Starbucks"))
+	assert.False(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 123, 5001, "Morning Coffee", "Starbucks"))
+	assert.False(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 123, 2000, "Lunch", "Starbucks"))
+	assert.False(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 123, 2000, "Morning Coffee", "Costa"))
+	assert.False(t, matcher.IsMatched(TRANSACTION_TYPE_EXPENSE, 123, 2000, "Morning Coffee", ""))
+}
+
+func TestTransactionRuleMatcherGetRewrittenComment(t *testing.T) {
+	rule := &TransactionRule{
+		CommentPattern: "^POS (\\w+) .*$",
+		RewriteComment: true,
+		NewComment:     "Card payment at $1",
+	}
+
+	matcher, err := rule.CreateMatcher()
+	assert.Nil(t, err)
+	assert.Equal(t, "Card payment at SHOP", matcher.GetRewrittenComment("POS SHOP 20240101"))
+
+	rule = &TransactionRule{
+		AccountId:      123,
+		RewriteComment: true,
+		NewComment:     "New Comment",
+	}
+
+	matcher, err = rule.CreateMatcher()
+	assert.Nil(t, err)
+	assert.Equal(t, "New Comment", matcher.GetRewrittenComment("Old Comment"))
+
+	rule = &TransactionRule{
+		AccountId:  123,
+		NewComment: "New Comment",
+	}
+
+	matcher, err = rule.CreateMatcher()
+	assert.Nil(t, err)
+	assert.Equal(t, "Old Comment", matcher.GetRewrittenComment("Old Comment"))
+}
+
+func TestTransactionRuleSliceLess(t *testing.T) {
+	var ruleSlice TransactionRuleSlice
+	ruleSlice = append(ruleSlice, &TransactionRule{
+		RuleId:       1,
+		DisplayOrder: 3,
+	})
+	ruleSlice = append(ruleSlice, &TransactionRule{
+		RuleId:       3,
+		DisplayOrder: 1,
+	})
+	ruleSlice = append(ruleSlice, &TransactionRule{
+		RuleId:       2,
+		DisplayOrder: 1,
+	})
+
+	sort.Sort(ruleSlice)
+
+	assert.Equal(t, int64(2), ruleSlice[0].RuleId)
+	assert.Equal(t, int64(3), ruleSlice[1].RuleId)
+	assert.Equal(t, int64(1), ruleSlice[2].RuleId)
+}
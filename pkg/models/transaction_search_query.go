@@ -0,0 +1,583 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// MaximumTermsCountOfTransactionSearchQuery represents the maximum count of terms in one transaction search query
+const MaximumTermsCountOfTransactionSearchQuery = 32
+
+// MaximumNestingDepthOfTransactionSearchQuery represents the maximum nesting depth of parentheses and negations in one transaction search query
+const MaximumNestingDepthOfTransactionSearchQuery = 8
+
+// TransactionSearchQueryNodeType represents the node type of transaction search query
+type TransactionSearchQueryNodeType byte
+
+// Transaction search query node types
+const (
+	TRANSACTION_SEARCH_QUERY_NODE_TYPE_TERM TransactionSearchQueryNodeType = 1
+	TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND  TransactionSearchQueryNodeType = 2
+	TRANSACTION_SEARCH_QUERY_NODE_TYPE_OR   TransactionSearchQueryNodeType = 3
+	TRANSACTION_SEARCH_QUERY_NODE_TYPE_NOT  TransactionSearchQueryNodeType = 4
+)
+
+// TransactionSearchQueryField represents the field which a transaction search query term filters by
+type TransactionSearchQueryField string
+
+// Transaction search query fields
+const (
+	TRANSACTION_SEARCH_QUERY_FIELD_COMMENT  TransactionSearchQueryField = "comment"
+	TRANSACTION_SEARCH_QUERY_FIELD_AMOUNT   TransactionSearchQueryField = "amount"
+	TRANSACTION_SEARCH_QUERY_FIELD_TYPE     TransactionSearchQueryField = "type"
+	TRANSACTION_SEARCH_QUERY_FIELD_ACCOUNT  TransactionSearchQueryField = "account"
+	TRANSACTION_SEARCH_QUERY_FIELD_CATEGORY TransactionSearchQueryField = "category"
+	TRANSACTION_SEARCH_QUERY_FIELD_TAG      TransactionSearchQueryField = "tag"
+	TRANSACTION_SEARCH_QUERY_FIELD_PAYEE    TransactionSearchQueryField = "payee"
+)
+
+var transactionSearchQueryFieldNames = map[string]TransactionSearchQueryField{
+	"comment":  TRANSACTION_SEARCH_QUERY_FIELD_COMMENT,
+	"amount":   TRANSACTION_SEARCH_QUERY_FIELD_AMOUNT,
+	"type":     TRANSACTION_SEARCH_QUERY_FIELD_TYPE,
+	"account":  TRANSACTION_SEARCH_QUERY_FIELD_ACCOUNT,
+	"category": TRANSACTION_SEARCH_QUERY_FIELD_CATEGORY,
+	"tag":      TRANSACTION_SEARCH_QUERY_FIELD_TAG,
+	"payee":    TRANSACTION_SEARCH_QUERY_FIELD_PAYEE,
+}
+
+var transactionSearchQueryTypeNames = map[string]TransactionType{
+	"balance":  TRANSACTION_TYPE_MODIFY_BALANCE,
+	"income":   TRANSACTION_TYPE_INCOME,
+	"expense":  TRANSACTION_TYPE_EXPENSE,
+	"transfer": TRANSACTION_TYPE_TRANSFER,
+}
+
+// TransactionSearchQueryOperator represents the comparison operator of transaction search query term
+type TransactionSearchQueryOperator byte
+
+// Transaction search query operators
+const (
+	TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL                 TransactionSearchQueryOperator = 1
+	TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN          TransactionSearchQueryOperator = 2
+	TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL TransactionSearchQueryOperator = 3
+	TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN             TransactionSearchQueryOperator = 4
+	TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN_OR_EQUAL    TransactionSearchQueryOperator = 5
+	TRANSACTION_SEARCH_QUERY_OPERATOR_BETWEEN               TransactionSearchQueryOperator = 6
+)
+
+var transactionSearchQueryOperatorNames = map[string]TransactionSearchQueryOperator{
+	":":  TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL,
+	"=":  TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL,
+	">":  TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN,
+	">=": TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL,
+	"<":  TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN,
+	"<=": TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN_OR_EQUAL,
+}
+
+// TransactionSearchQueryNode represents a node of the parsed transaction search query
+type TransactionSearchQueryNode struct {
+	Type     TransactionSearchQueryNodeType
+	Children []*TransactionSearchQueryNode
+	Field    TransactionSearchQueryField
+	Operator TransactionSearchQueryOperator
+	Value    string
+	Amount   int64
+	Amount2  int64
+	Ids      []int64
+}
+
+type transactionSearchQueryTokenType byte
+
+const (
+	transactionSearchQueryTokenWord       transactionSearchQueryTokenType = 1
+	transactionSearchQueryTokenPhrase     transactionSearchQueryTokenType = 2
+	transactionSearchQueryTokenOperator   transactionSearchQueryTokenType = 3
+	transactionSearchQueryTokenLeftParen  transactionSearchQueryTokenType = 4
+	transactionSearchQueryTokenRightParen transactionSearchQueryTokenType = 5
+	transactionSearchQueryTokenNegation   transactionSearchQueryTokenType = 6
+)
+
+type transactionSearchQueryToken struct {
+	tokenType transactionSearchQueryTokenType
+	value     string
+}
+
+type transactionSearchQueryParser struct {
+	tokens     []*transactionSearchQueryToken
+	position   int
+	termsCount int
+}
+
+// ParseTransactionSearchQuery parses the transaction search query and returns the root node, returns nil if the query is empty
+//
+// The query supports the following syntax:
+//   - plain words and "quoted phrases" match the transaction comment
+//   - field filters, e.g. tag:travel, account:"Credit Card", category:Food, payee:Amazon, type:expense, comment:coffee
+//   - amount comparisons and ranges, e.g. amount>100, amount:<=50.5, amount:10..20
+//   - boolean operators AND, OR (terms separated by spaces are joined by AND) and parentheses
+//   - negation by NOT or the "-" prefix, e.g. -tag:travel, NOT (payee:Amazon OR payee:eBay)
+func ParseTransactionSearchQuery(query string) (*TransactionSearchQueryNode, error) {
+	tokens, err := tokenizeTransactionSearchQuery(query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) < 1 {
+		return nil, nil
+	}
+
+	parser := &transactionSearchQueryParser{
+		tokens: tokens,
+	}
+
+	node, err := parser.parseOrExpression(0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.position < len(parser.tokens) {
+		return nil, errs.ErrTransactionSearchQueryInvalid
+	}
+
+	return node, nil
+}
+
+// HasField returns whether the search query contains any term of the specified field
+func (n *TransactionSearchQueryNode) HasField(field TransactionSearchQueryField) bool {
+	if n == nil {
+		return false
+	}
+
+	if n.Type == TRANSACTION_SEARCH_QUERY_NODE_TYPE_TERM {
+		return n.Field == field
+	}
+
+	for i := 0; i < len(n.Children); i++ {
+		if n.Children[i].HasField(field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResolveIds sets the ids of all the terms of the specified field by the given resolve function, which returns the ids matched the name in the term
+func (n *TransactionSearchQueryNode) ResolveIds(field TransactionSearchQueryField, resolve func(name string) []int64) {
+	if n == nil {
+		return
+	}
+
+	if n.Type == TRANSACTION_SEARCH_QUERY_NODE_TYPE_TERM {
+		if n.Field == field {
+			n.Ids = resolve(n.Value)
+		}
+
+		return
+	}
+
+	for i := 0; i < len(n.Children); i++ {
+		n.Children[i].ResolveIds(field, resolve)
+	}
+}
+
+// ResolveNames sets the ids of all the account, category, tag and payee terms by the names of the given accounts, categories, tags and payees,
+// the primary category and parent account also match all their sub-categories and sub-accounts
+func (n *TransactionSearchQueryNode) ResolveNames(accounts []*Account, categories []*TransactionCategory, tags []*TransactionTag, payees []*Payee) {
+	n.ResolveIds(TRANSACTION_SEARCH_QUERY_FIELD_ACCOUNT, func(name string) []int64 {
+		ids := make([]int64, 0)
+
+		for i := 0; i < len(accounts); i++ {
+			account := accounts[i]
+
+			if strings.EqualFold(account.Name, name) {
+				ids = append(ids, account.AccountId)
+				continue
+			}
+
+			for j := 0; j < len(accounts); j++ {
+				if accounts[j].AccountId == account.ParentAccountId && strings.EqualFold(accounts[j].Name, name) {
+					ids = append(ids, account.AccountId)
+					break
+				}
+			}
+		}
+
+		return ids
+	})
+
+	n.ResolveIds(TRANSACTION_SEARCH_QUERY_FIELD_CATEGORY, func(name string) []int64 {
+		ids := make([]int64, 0)
+
+		for i := 0; i < len(categories); i++ {
+			category := categories[i]
+
+			if strings.EqualFold(category.Name, name) {
+				ids = append(ids, category.CategoryId)
+				continue
+			}
+
+			for j := 0; j < len(categories); j++ {
+				if categories[j].CategoryId == category.ParentCategoryId && strings.EqualFold(categories[j].Name, name) {
+					ids = append(ids, category.CategoryId)
+					break
+				}
+			}
+		}
+
+		return ids
+	})
+
+	n.ResolveIds(TRANSACTION_SEARCH_QUERY_FIELD_TAG, func(name string) []int64 {
+		ids := make([]int64, 0)
+
+		for i := 0; i < len(tags); i++ {
+			if strings.EqualFold(tags[i].Name, name) {
+				ids = append(ids, tags[i].TagId)
+			}
+		}
+
+		return ids
+	})
+
+	n.ResolveIds(TRANSACTION_SEARCH_QUERY_FIELD_PAYEE, func(name string) []int64 {
+		ids := make([]int64, 0)
+
+		for i := 0; i < len(payees); i++ {
+			if payees[i].IsNameMatched(name) {
+				ids = append(ids, payees[i].PayeeId)
+			}
+		}
+
+		return ids
+	})
+}
+
+// GetTransactionType returns the transaction type of the type term
+func (n *TransactionSearchQueryNode) GetTransactionType() TransactionType {
+	return transactionSearchQueryTypeNames[strings.ToLower(n.Value)]
+}
+
+func (p *transactionSearchQueryParser) parseOrExpression(depth int) (*TransactionSearchQueryNode, error) {
+	node, err := p.parseAndExpression(depth)
+
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*TransactionSearchQueryNode{node}
+
+	for p.isCurrentKeyword("OR") {
+		p.position++
+		node, err = p.parseAndExpression(depth)
+
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, node)
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+
+	return &TransactionSearchQueryNode{
+		Type:     TRANSACTION_SEARCH_QUERY_NODE_TYPE_OR,
+		Children: children,
+	}, nil
+}
+
+func (p *transactionSearchQueryParser) parseAndExpression(depth int) (*TransactionSearchQueryNode, error) {
+	node, err := p.parseUnaryExpression(depth)
+
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*TransactionSearchQueryNode{node}
+
+	for p.position < len(p.tokens) {
+		token := p.tokens[p.position]
+
+		if token.tokenType == transactionSearchQueryTokenRightParen || p.isCurrentKeyword("OR") {
+			break
+		}
+
+		if p.isCurrentKeyword("AND") {
+			p.position++
+		}
+
+		node, err = p.parseUnaryExpression(depth)
+
+		if err != nil {
+			return nil, err
+		}
+
+		children = append(children, node)
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+
+	return &TransactionSearchQueryNode{
+		Type:     TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND,
+		Children: children,
+	}, nil
+}
+
+func (p *transactionSearchQueryParser) parseUnaryExpression(depth int) (*TransactionSearchQueryNode, error) {
+	if depth >= MaximumNestingDepthOfTransactionSearchQuery {
+		return nil, errs.ErrTransactionSearchQueryTooComplex
+	}
+
+	if p.position >= len(p.tokens) {
+		return nil, errs.ErrTransactionSearchQueryInvalid
+	}
+
+	token := p.tokens[p.position]
+
+	if token.tokenType == transactionSearchQueryTokenNegation || p.isCurrentKeyword("NOT") {
+		p.position++
+		node, err := p.parseUnaryExpression(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &TransactionSearchQueryNode{
+			Type:     TRANSACTION_SEARCH_QUERY_NODE_TYPE_NOT,
+			Children: []*TransactionSearchQueryNode{node},
+		}, nil
+	}
+
+	if token.tokenType == transactionSearchQueryTokenLeftParen {
+		p.position++
+		node, err := p.parseOrExpression(depth + 1)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if p.position >= len(p.tokens) || p.tokens[p.position].tokenType != transactionSearchQueryTokenRightParen {
+			return nil, errs.ErrTransactionSearchQueryInvalid
+		}
+
+		p.position++
+		return node, nil
+	}
+
+	return p.parseTerm()
+}
+
+func (p *transactionSearchQueryParser) parseTerm() (*TransactionSearchQueryNode, error) {
+	token := p.tokens[p.position]
+
+	if (token.tokenType != transactionSearchQueryTokenWord && token.tokenType != transactionSearchQueryTokenPhrase) || p.isCurrentKeyword("AND") || p.isCurrentKeyword("OR") {
+		return nil, errs.ErrTransactionSearchQueryInvalid
+	}
+
+	p.termsCount++
+
+	if p.termsCount > MaximumTermsCountOfTransactionSearchQuery {
+		return nil, errs.ErrTransactionSearchQueryTooComplex
+	}
+
+	p.position++
+	field, isField := transactionSearchQueryFieldNames[strings.ToLower(token.value)]
+
+	if token.tokenType != transactionSearchQueryTokenWord || !isField || p.position >= len(p.tokens) || p.tokens[p.position].tokenType != transactionSearchQueryTokenOperator {
+		return &TransactionSearchQueryNode{
+			Type:     TRANSACTION_SEARCH_QUERY_NODE_TYPE_TERM,
+			Field:    TRANSACTION_SEARCH_QUERY_FIELD_COMMENT,
+			Operator: TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL,
+			Value:    token.value,
+		}, nil
+	}
+
+	operator := transactionSearchQueryOperatorNames[p.tokens[p.position].value]
+	p.position++
+
+	// allow both "amount:>100" and "amount>100"
+	if operator == TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL && p.position < len(p.tokens) && p.tokens[p.position].tokenType == transactionSearchQueryTokenOperator {
+		operator = transactionSearchQueryOperatorNames[p.tokens[p.position].value]
+		p.position++
+	}
+
+	if p.position >= len(p.tokens) || (p.tokens[p.position].tokenType != transactionSearchQueryTokenWord && p.tokens[p.position].tokenType != transactionSearchQueryTokenPhrase) {
+		return nil, errs.ErrTransactionSearchQueryInvalid
+	}
+
+	value := p.tokens[p.position].value
+	p.position++
+
+	node := &TransactionSearchQueryNode{
+		Type:     TRANSACTION_SEARCH_QUERY_NODE_TYPE_TERM,
+		Field:    field,
+		Operator: operator,
+		Value:    value,
+	}
+
+	if field == TRANSACTION_SEARCH_QUERY_FIELD_AMOUNT {
+		return p.parseAmountTerm(node)
+	}
+
+	if operator != TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL || value == "" {
+		return nil, errs.ErrTransactionSearchQueryInvalid
+	}
+
+	if field == TRANSACTION_SEARCH_QUERY_FIELD_TYPE {
+		if _, exists := transactionSearchQueryTypeNames[strings.ToLower(value)]; !exists {
+			return nil, errs.ErrTransactionSearchQueryInvalid
+		}
+	}
+
+	return node, nil
+}
+
+func (p *transactionSearchQueryParser) parseAmountTerm(node *TransactionSearchQueryNode) (*TransactionSearchQueryNode, error) {
+	var err error
+
+	if node.Operator == TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL && strings.Contains(node.Value, "..") {
+		items := strings.SplitN(node.Value, "..", 2)
+
+		if items[0] == "" && items[1] == "" {
+			return nil, errs.ErrTransactionSearchQueryInvalid
+		} else if items[0] == "" {
+			node.Operator = TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN_OR_EQUAL
+			node.Amount, err = utils.ParseAmount(items[1])
+		} else if items[1] == "" {
+			node.Operator = TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL
+			node.Amount, err = utils.ParseAmount(items[0])
+		} else {
+			node.Operator = TRANSACTION_SEARCH_QUERY_OPERATOR_BETWEEN
+			node.Amount, err = utils.ParseAmount(items[0])
+
+			if err == nil {
+				node.Amount2, err = utils.ParseAmount(items[1])
+			}
+
+			if err == nil && node.Amount > node.Amount2 {
+				node.Amount, node.Amount2 = node.Amount2, node.Amount
+			}
+		}
+	} else {
+		node.Amount, err = utils.ParseAmount(node.Value)
+	}
+
+	if err != nil {
+		return nil, errs.ErrTransactionSearchQueryInvalid
+	}
+
+	return node, nil
+}
+
+func (p *transactionSearchQueryParser) isCurrentKeyword(keyword string) bool {
+	if p.position >= len(p.tokens) {
+		return false
+	}
+
+	token := p.tokens[p.position]
+
+	return token.tokenType == transactionSearchQueryTokenWord && token.value == keyword
+}
+
+func tokenizeTransactionSearchQuery(query string) ([]*transactionSearchQueryToken, error) {
+	runes := []rune(query)
+	tokens := make([]*transactionSearchQueryToken, 0)
+
+	for i := 0; i < len(runes); {
+		ch := runes[i]
+
+		if unicode.IsSpace(ch) {
+			i++
+			continue
+		}
+
+		if ch == '(' {
+			tokens = append(tokens, &transactionSearchQueryToken{tokenType: transactionSearchQueryTokenLeftParen})
+			i++
+			continue
+		}
+
+		if ch == ')' {
+			tokens = append(tokens, &transactionSearchQueryToken{tokenType: transactionSearchQueryTokenRightParen})
+			i++
+			continue
+		}
+
+		if isTransactionSearchQueryOperatorRune(ch) {
+			operator := string(ch)
+
+			if (ch == '>' || ch == '<') && i+1 < len(runes) && runes[i+1] == '=' {
+				operator = operator + "="
+			}
+
+			tokens = append(tokens, &transactionSearchQueryToken{tokenType: transactionSearchQueryTokenOperator, value: operator})
+			i += len(operator)
+			continue
+		}
+
+		// the "-" prefix means negation, except the value after an operator (e.g. "amount:-1..1")
+		if ch == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && (len(tokens) < 1 || tokens[len(tokens)-1].tokenType != transactionSearchQueryTokenOperator) {
+			tokens = append(tokens, &transactionSearchQueryToken{tokenType: transactionSearchQueryTokenNegation})
+			i++
+			continue
+		}
+
+		if ch == '"' {
+			var value strings.Builder
+			closed := false
+			i++
+
+			for ; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					value.WriteRune(runes[i+1])
+					i++
+				} else if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				} else {
+					value.WriteRune(runes[i])
+				}
+			}
+
+			if !closed {
+				return nil, errs.ErrTransactionSearchQueryInvalid
+			}
+
+			tokens = append(tokens, &transactionSearchQueryToken{tokenType: transactionSearchQueryTokenPhrase, value: value.String()})
+			continue
+		}
+
+		start := i
+
+		for ; i < len(runes); i++ {
+			if unicode.IsSpace(runes[i]) || runes[i] == '(' || runes[i] == ')' || runes[i] == '"' {
+				break
+			}
+
+			// the operator only splits the word when the word before it is a field name (e.g. "tag:travel"), otherwise it is a part of the word (e.g. "12:30")
+			if isTransactionSearchQueryOperatorRune(runes[i]) {
+				if _, isField := transactionSearchQueryFieldNames[strings.ToLower(string(runes[start:i]))]; isField {
+					break
+				}
+			}
+		}
+
+		tokens = append(tokens, &transactionSearchQueryToken{tokenType: transactionSearchQueryTokenWord, value: string(runes[start:i])})
+	}
+
+	return tokens, nil
+}
+
+func isTransactionSearchQueryOperatorRune(ch rune) bool {
+	return ch == ':' || ch == '=' || ch == '>' || ch == '<'
+}
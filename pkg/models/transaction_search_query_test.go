@@ -0,0 +1,197 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestParseTransactionSearchQuery_EmptyQuery(t *testing.T) {
+	node, err := ParseTransactionSearchQuery("")
+	assert.Nil(t, err)
+	assert.Nil(t, node)
+
+	node, err = ParseTransactionSearchQuery("   ")
+	assert.Nil(t, err)
+	assert.Nil(t, node)
+}
+
+func TestParseTransactionSearchQuery_PlainWordsAndPhrases(t *testing.T) {
+	node, err := ParseTransactionSearchQuery("coffee")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_TERM, node.Type)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_COMMENT, node.Field)
+	assert.Equal(t, "coffee", node.Value)
+
+	node, err = ParseTransactionSearchQuery("coffee \"new york\" 12:30")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND, node.Type)
+	assert.Equal(t, 3, len(node.Children))
+	assert.Equal(t, "coffee", node.Children[0].Value)
+	assert.Equal(t, "new york", node.Children[1].Value)
+	assert.Equal(t, "12:30", node.Children[2].Value)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_COMMENT, node.Children[2].Field)
+
+	node, err = ParseTransactionSearchQuery("\"say \\\"hi\\\"\"")
+	assert.Nil(t, err)
+	assert.Equal(t, "say \"hi\"", node.Value)
+
+	node, err = ParseTransactionSearchQuery("\"tag:travel\"")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_COMMENT, node.Field)
+	assert.Equal(t, "tag:travel", node.Value)
+}
+
+func TestParseTransactionSearchQuery_FieldFilters(t *testing.T) {
+	node, err := ParseTransactionSearchQuery("tag:travel account:\"Credit Card\" Category:Food payee:Amazon type:expense comment:lunch")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND, node.Type)
+	assert.Equal(t, 6, len(node.Children))
+
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_TAG, node.Children[0].Field)
+	assert.Equal(t, "travel", node.Children[0].Value)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_ACCOUNT, node.Children[1].Field)
+	assert.Equal(t, "Credit Card", node.Children[1].Value)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_CATEGORY, node.Children[2].Field)
+	assert.Equal(t, "Food", node.Children[2].Value)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_PAYEE, node.Children[3].Field)
+	assert.Equal(t, "Amazon", node.Children[3].Value)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_TYPE, node.Children[4].Field)
+	assert.Equal(t, TRANSACTION_TYPE_EXPENSE, node.Children[4].GetTransactionType())
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_COMMENT, node.Children[5].Field)
+	assert.Equal(t, "lunch", node.Children[5].Value)
+}
+
+func TestParseTransactionSearchQuery_AmountComparisons(t *testing.T) {
+	testCases := []struct {
+		query    string
+		operator TransactionSearchQueryOperator
+		amount   int64
+		amount2  int64
+	}{
+		{"amount:100", TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL, 10000, 0},
+		{"amount=1.5", TRANSACTION_SEARCH_QUERY_OPERATOR_EQUAL, 150, 0},
+		{"amount>100", TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN, 10000, 0},
+		{"amount > 100", TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN, 10000, 0},
+		{"amount:>=100", TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL, 10000, 0},
+		{"amount<5", TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN, 500, 0},
+		{"amount:<=5", TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN_OR_EQUAL, 500, 0},
+		{"amount:10..20", TRANSACTION_SEARCH_QUERY_OPERATOR_BETWEEN, 1000, 2000},
+		{"amount:20..10", TRANSACTION_SEARCH_QUERY_OPERATOR_BETWEEN, 1000, 2000},
+		{"amount:10..", TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL, 1000, 0},
+		{"amount:..20", TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN_OR_EQUAL, 2000, 0},
+	}
+
+	for _, testCase := range testCases {
+		node, err := ParseTransactionSearchQuery(testCase.query)
+		assert.Nil(t, err, testCase.query)
+		assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_AMOUNT, node.Field, testCase.query)
+		assert.Equal(t, testCase.operator, node.Operator, testCase.query)
+		assert.Equal(t, testCase.amount, node.Amount, testCase.query)
+		assert.Equal(t, testCase.amount2, node.Amount2, testCase.query)
+	}
+}
+
+func TestParseTransactionSearchQuery_BooleanOperatorsAndNegation(t *testing.T) {
+	node, err := ParseTransactionSearchQuery("amount > 100 AND tag:travel OR -account:Visa")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_OR, node.Type)
+	assert.Equal(t, 2, len(node.Children))
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND, node.Children[0].Type)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_AMOUNT, node.Children[0].Children[0].Field)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_TAG, node.Children[0].Children[1].Field)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_NOT, node.Children[1].Type)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_FIELD_ACCOUNT, node.Children[1].Children[0].Field)
+	assert.Equal(t, "Visa", node.Children[1].Children[0].Value)
+
+	node, err = ParseTransactionSearchQuery("NOT (payee:Amazon OR payee:eBay) coffee")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND, node.Type)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_NOT, node.Children[0].Type)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_OR, node.Children[0].Children[0].Type)
+	assert.Equal(t, "coffee", node.Children[1].Value)
+
+	node, err = ParseTransactionSearchQuery("amount:-1..1")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_OPERATOR_BETWEEN, node.Operator)
+	assert.Equal(t, int64(-100), node.Amount)
+	assert.Equal(t, int64(100), node.Amount2)
+
+	node, err = ParseTransactionSearchQuery("and or not")
+	assert.Nil(t, err)
+	assert.Equal(t, TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND, node.Type)
+	assert.Equal(t, 3, len(node.Children))
+}
+
+func TestParseTransactionSearchQuery_InvalidQuery(t *testing.T) {
+	queries := []string{
+		"\"unclosed",
+		"(coffee",
+		"coffee)",
+		"coffee AND",
+		"OR coffee",
+		"NOT",
+		"amount:abc",
+		"amount:..",
+		"tag:",
+		"tag:>travel",
+		"type:unknown",
+		"()",
+	}
+
+	for _, query := range queries {
+		_, err := ParseTransactionSearchQuery(query)
+		assert.Equal(t, errs.ErrTransactionSearchQueryInvalid, err, query)
+	}
+}
+
+func TestParseTransactionSearchQuery_TooComplexQuery(t *testing.T) {
+	_, err := ParseTransactionSearchQuery(strings.Repeat("a ", MaximumTermsCountOfTransactionSearchQuery+1))
+	assert.Equal(t, errs.ErrTransactionSearchQueryTooComplex, err)
+
+	_, err = ParseTransactionSearchQuery(strings.Repeat("a ", MaximumTermsCountOfTransactionSearchQuery))
+	assert.Nil(t, err)
+
+	_, err = ParseTransactionSearchQuery(strings.Repeat("(", MaximumNestingDepthOfTransactionSearchQuery) + "a" + strings.Repeat(")", MaximumNestingDepthOfTransactionSearchQuery))
+	assert.Equal(t, errs.ErrTransactionSearchQueryTooComplex, err)
+
+	_, err = ParseTransactionSearchQuery(strings.Repeat("-", MaximumNestingDepthOfTransactionSearchQuery) + "a")
+	assert.Equal(t, errs.ErrTransactionSearchQueryTooComplex, err)
+}
+
+func TestTransactionSearchQueryNodeResolveNames(t *testing.T) {
+	node, err := ParseTransactionSearchQuery("account:bank category:food tag:TRAVEL payee:amzn tag:unknown")
+	assert.Nil(t, err)
+	assert.True(t, node.HasField(TRANSACTION_SEARCH_QUERY_FIELD_PAYEE))
+	assert.False(t, node.HasField(TRANSACTION_SEARCH_QUERY_FIELD_AMOUNT))
+
+	accounts := []*Account{
+		{AccountId: 1, Name: "Bank"},
+		{AccountId: 2, Name: "Checking", ParentAccountId: 1},
+		{AccountId: 3, Name: "Cash"},
+	}
+	categories := []*TransactionCategory{
+		{CategoryId: 10, Name: "Food"},
+		{CategoryId: 11, Name: "Lunch", ParentCategoryId: 10},
+		{CategoryId: 12, Name: "Transport"},
+	}
+	tags := []*TransactionTag{
+		{TagId: 20, Name: "Travel"},
+		{TagId: 21, Name: "Work"},
+	}
+	payees := []*Payee{
+		{PayeeId: 30, Name: "Amazon", Aliases: "AMZN"},
+		{PayeeId: 31, Name: "eBay"},
+	}
+
+	node.ResolveNames(accounts, categories, tags, payees)
+
+	assert.EqualValues(t, []int64{1, 2}, node.Children[0].Ids)
+	assert.EqualValues(t, []int64{10, 11}, node.Children[1].Ids)
+	assert.EqualValues(t, []int64{20}, node.Children[2].Ids)
+	assert.EqualValues(t, []int64{30}, node.Children[3].Ids)
+	assert.Equal(t, 0, len(node.Children[4].Ids))
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionSplitMinCount represents the minimum count of split lines of a split transaction
+const TransactionSplitMinCount = 2
+
+// TransactionSplitMaxCount represents the maximum count of split lines of a split transaction
+const TransactionSplitMaxCount = 100
+
+// TransactionSplit represents a split line of an income or expense transaction stored in database
+type TransactionSplit struct {
+	SplitId         int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) NOT NULL"`
+	TransactionId   int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) NOT NULL"`
+	CategoryId      int64  `xorm:"NOT NULL"`
+	Amount          int64  `xorm:"NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	TagIds          string `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionSplitRequest represents all parameters of a split line in transaction creation or modification request
+type TransactionSplitRequest struct {
+	CategoryId int64    `json:"categoryId,string" binding:"required,min=1"`
+	Amount     int64    `json:"amount" binding:"min=-99999999999,max=99999999999"`
+	Comment    string   `json:"comment" binding:"max=255"`
+	TagIds     []string `json:"tagIds"`
+}
+
+// TransactionSplitInfoResponse represents a view-object of transaction split line
+type TransactionSplitInfoResponse struct {
+	Id         int64    `json:"id,string"`
+	CategoryId int64    `json:"categoryId,string"`
+	Amount     int64    `json:"amount"`
+	Comment    string   `json:"comment"`
+	TagIds     []string `json:"tagIds"`
+}
+
+// TableName returns the table name of TransactionSplit
+func (s *TransactionSplit) TableName() string {
+	return "ebk_transaction_splits"
+}
+
+// GetTagIds returns all tag ids of the transaction split line
+func (s *TransactionSplit) GetTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if s.TagIds != "" {
+		tagIds = strings.Split(s.TagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// ToTransactionSplitInfoResponse returns a view-object according to database model
+func (s *TransactionSplit) ToTransactionSplitInfoResponse() *TransactionSplitInfoResponse {
+	return &TransactionSplitInfoResponse{
+		Id:         s.SplitId,
+		CategoryId: s.CategoryId,
+		Amount:     s.Amount,
+		Comment:    s.Comment,
+		TagIds:     utils.Int64ArrayToStringArray(s.GetTagIds()),
+	}
+}
+
+// ToTransactionSplit returns a transaction split line model (without split id and transaction id) according to the request
+func (r *TransactionSplitRequest) ToTransactionSplit() (*TransactionSplit, error) {
+	tagIds, err := utils.StringArrayToInt64Array(r.TagIds)
+
+	if err != nil {
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	return &TransactionSplit{
+		CategoryId: r.CategoryId,
+		Amount:     r.Amount,
+		Comment:    r.Comment,
+		TagIds:     strings.Join(utils.Int64ArrayToStringArray(utils.ToUniqueInt64Slice(tagIds)), ","),
+	}, nil
+}
+
+// ToTransactionSplits returns transaction split line models according to the requests
+func ToTransactionSplits(splitReqs []*TransactionSplitRequest) ([]*TransactionSplit, error) {
+	if len(splitReqs) < 1 {
+		return nil, nil
+	}
+
+	splits := make([]*TransactionSplit, len(splitReqs))
+
+	for i := 0; i < len(splitReqs); i++ {
+		split, err := splitReqs[i].ToTransactionSplit()
+
+		if err != nil {
+			return nil, err
+		}
+
+		splits[i] = split
+	}
+
+	return splits, nil
+}
+
+// GetTransactionSplitInfoResponses returns the view-objects of the specified split lines
+func GetTransactionSplitInfoResponses(splits []*TransactionSplit) []*TransactionSplitInfoResponse {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	splitResps := make([]*TransactionSplitInfoResponse, len(splits))
+
+	for i := 0; i < len(splits); i++ {
+		splitResps[i] = splits[i].ToTransactionSplitInfoResponse()
+	}
+
+	return splitResps
+}
+
+// ValidateSplits returns an error if the split lines of the transaction are not valid
+func (t *Transaction) ValidateSplits() error {
+	if len(t.Splits) < 1 {
+		return nil
+	}
+
+	if t.Type != TRANSACTION_DB_TYPE_INCOME && t.Type != TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrTransactionSplitsNotSupported
+	}
+
+	if len(t.Splits) < TransactionSplitMinCount || len(t.Splits) > TransactionSplitMaxCount {
+		return errs.ErrTransactionSplitCountInvalid
+	}
+
+	if GetTransactionSplitsTotalAmount(t.Splits) != t.Amount {
+		return errs.ErrTransactionSplitsAmountNotEqual
+	}
+
+	for i := 0; i < len(t.Splits); i++ {
+		if len(t.Splits[i].GetTagIds()) > MaximumTagsCountOfTransaction {
+			return errs.ErrTransactionHasTooManyTags
+		}
+	}
+
+	return nil
+}
+
+// IsTransactionSplitsEqual returns whether the two split line lists have the same content
+func IsTransactionSplitsEqual(splits1 []*TransactionSplit, splits2 []*TransactionSplit) bool {
+	if len(splits1) != len(splits2) {
+		return false
+	}
+
+	for i := 0; i < len(splits1); i++ {
+		if splits1[i].CategoryId != splits2[i].CategoryId ||
+			splits1[i].Amount != splits2[i].Amount ||
+			splits1[i].Comment != splits2[i].Comment ||
+			splits1[i].TagIds != splits2[i].TagIds {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetTransactionSplitsTotalAmount returns the sum of the amounts of all split lines
+func GetTransactionSplitsTotalAmount(splits []*TransactionSplit) int64 {
+	totalAmount := int64(0)
+
+	for i := 0; i < len(splits); i++ {
+		totalAmount += splits[i].Amount
+	}
+
+	return totalAmount
+}
+
+// GetSplitTransactionAmounts returns the amount items of each split line of the specified transaction, every item has the same type, account, payee and time as the transaction
+func GetSplitTransactionAmounts(transaction *Transaction, splits []*TransactionSplit) []*Transaction {
+	amounts := make([]*Transaction, len(splits))
+
+	for i := 0; i < len(splits); i++ {
+		amounts[i] = &Transaction{
+			TransactionId:        transaction.TransactionId,
+			Type:                 transaction.Type,
+			CategoryId:           splits[i].CategoryId,
+			AccountId:            transaction.AccountId,
+			PayeeId:              transaction.PayeeId,
+			TransactionTime:      transaction.TransactionTime,
+			TimezoneUtcOffset:    transaction.TimezoneUtcOffset,
+			Amount:               splits[i].Amount,
+			ExchangeRateCurrency: transaction.ExchangeRateCurrency,
+			ExchangeRate:         transaction.ExchangeRate,
+		}
+	}
+
+	return amounts
+}
+
+// TransactionSplitSlice represents the slice data structure of TransactionSplit
+type TransactionSplitSlice []*TransactionSplit
+
+// Len returns the count of items
+func (s TransactionSplitSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionSplitSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionSplitSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
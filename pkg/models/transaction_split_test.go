@@ -0,0 +1,122 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestTransactionSplitGetTagIds(t *testing.T) {
+	split := &TransactionSplit{
+		TagIds: "1,2,3",
+	}
+
+	expectedValue := []int64{1, 2, 3}
+	assert.EqualValues(t, expectedValue, split.GetTagIds())
+
+	split = &TransactionSplit{}
+	assert.Equal(t, 0, len(split.GetTagIds()))
+}
+
+func TestTransactionSplitRequestToTransactionSplit(t *testing.T) {
+	splitReq := &TransactionSplitRequest{
+		CategoryId: 10,
+		Amount:     1234,
+		Comment:    "foo",
+		TagIds:     []string{"3", "1", "3"},
+	}
+
+	split, err := splitReq.ToTransactionSplit()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), split.CategoryId)
+	assert.Equal(t, int64(1234), split.Amount)
+	assert.Equal(t, "foo", split.Comment)
+	assert.Equal(t, "3,1", split.TagIds)
+
+	splitReq.TagIds = []string{"abc"}
+	_, err = splitReq.ToTransactionSplit()
+	assert.Equal(t, errs.ErrTransactionTagIdInvalid, err)
+}
+
+func TestTransactionValidateSplits(t *testing.T) {
+	transaction := &Transaction{
+		Type:   TRANSACTION_DB_TYPE_EXPENSE,
+		Amount: 1000,
+	}
+	assert.Nil(t, transaction.ValidateSplits())
+
+	transaction.Splits = []*TransactionSplit{
+		{CategoryId: 1, Amount: 600},
+		{CategoryId: 2, Amount: 400},
+	}
+	assert.Nil(t, transaction.ValidateSplits())
+
+	transaction.Splits[1].Amount = 300
+	assert.Equal(t, errs.ErrTransactionSplitsAmountNotEqual, transaction.ValidateSplits())
+
+	transaction.Splits = transaction.Splits[:1]
+	transaction.Splits[0].Amount = 1000
+	assert.Equal(t, errs.ErrTransactionSplitCountInvalid, transaction.ValidateSplits())
+
+	transaction.Type = TRANSACTION_DB_TYPE_TRANSFER_OUT
+	assert.Equal(t, errs.ErrTransactionSplitsNotSupported, transaction.ValidateSplits())
+}
+
+func TestIsTransactionSplitsEqual(t *testing.T) {
+	splits1 := []*TransactionSplit{
+		{SplitId: 1, CategoryId: 1, Amount: 600, TagIds: "1"},
+		{SplitId: 2, CategoryId: 2, Amount: 400, Comment: "foo"},
+	}
+	splits2 := []*TransactionSplit{
+		{SplitId: 3, CategoryId: 1, Amount: 600, TagIds: "1"},
+		{SplitId: 4, CategoryId: 2, Amount: 400, Comment: "foo"},
+	}
+
+	assert.True(t, IsTransactionSplitsEqual(splits1, splits2))
+	assert.True(t, IsTransactionSplitsEqual(nil, nil))
+	assert.False(t, IsTransactionSplitsEqual(splits1, nil))
+
+	splits2[1].Comment = "bar"
+	assert.False(t, IsTransactionSplitsEqual(splits1, splits2))
+}
+
+func TestGetSplitTransactionAmounts(t *testing.T) {
+	transaction := &Transaction{
+		TransactionId:        100,
+		Type:                 TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:           1,
+		AccountId:            5,
+		PayeeId:              7,
+		TransactionTime:      1234567890000,
+		TimezoneUtcOffset:    480,
+		Amount:               1000,
+		ExchangeRateCurrency: "USD",
+		ExchangeRate:         720000000,
+	}
+	splits := []*TransactionSplit{
+		{CategoryId: 1, Amount: 600},
+		{CategoryId: 2, Amount: 400},
+	}
+
+	amounts := GetSplitTransactionAmounts(transaction, splits)
+	assert.Equal(t, 2, len(amounts))
+
+	assert.Equal(t, int64(1), amounts[0].CategoryId)
+	assert.Equal(t, int64(600), amounts[0].Amount)
+	assert.Equal(t, int64(2), amounts[1].CategoryId)
+	assert.Equal(t, int64(400), amounts[1].Amount)
+
+	for i := 0; i < len(amounts); i++ {
+		assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, amounts[i].Type)
+		assert.Equal(t, int64(5), amounts[i].AccountId)
+		assert.Equal(t, int64(7), amounts[i].PayeeId)
+		assert.Equal(t, int64(1234567890000), amounts[i].TransactionTime)
+		assert.Equal(t, int16(480), amounts[i].TimezoneUtcOffset)
+		assert.Equal(t, "USD", amounts[i].ExchangeRateCurrency)
+		assert.Equal(t, int64(720000000), amounts[i].ExchangeRate)
+	}
+
+	assert.Equal(t, transaction.Amount, GetTransactionSplitsTotalAmount(splits))
+}
@@ -0,0 +1,518 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const maximumTransactionSuggestionFeatureLength = 64
+const transactionSuggestionSmoothingFactor = 1.0
+
+// TransactionSuggestionMinimumConfidence represents the minimum confidence of a suggestion which would be applied to imported transactions
+const TransactionSuggestionMinimumConfidence = 0.5
+
+// Special features of transaction suggestion model
+const (
+	TRANSACTION_SUGGESTION_FEATURE_PRIOR string = "#prior"
+	TRANSACTION_SUGGESTION_FEATURE_TOTAL string = "#total"
+)
+
+// TransactionSuggestionTargetType represents the target type of transaction suggestion feature
+type TransactionSuggestionTargetType byte
+
+// Transaction suggestion target types
+const (
+	TRANSACTION_SUGGESTION_TARGET_TYPE_ALL      TransactionSuggestionTargetType = 0
+	TRANSACTION_SUGGESTION_TARGET_TYPE_CATEGORY TransactionSuggestionTargetType = 1
+	TRANSACTION_SUGGESTION_TARGET_TYPE_TAG      TransactionSuggestionTargetType = 2
+)
+
+// TransactionSuggestionModelInfo represents the summary of transaction suggestion model stored in database
+type TransactionSuggestionModelInfo struct {
+	Uid              int64 `xorm:"PK"`
+	TransactionCount int64 `xorm:"NOT NULL"`
+	VocabularySize   int64 `xorm:"NOT NULL"`
+	BuiltUnixTime    int64 `xorm:"NOT NULL"`
+	CreatedUnixTime  int64
+	UpdatedUnixTime  int64
+}
+
+// TransactionSuggestionFeature represents the occurrence count of a feature for a category or tag stored in database
+type TransactionSuggestionFeature struct {
+	Uid        int64                           `xorm:"PK"`
+	TargetType TransactionSuggestionTargetType `xorm:"PK"`
+	TargetId   int64                           `xorm:"PK"`
+	Feature    string                          `xorm:"PK VARCHAR(64)"`
+	Count      int64                           `xorm:"NOT NULL"`
+}
+
+// TransactionSuggestionTrainedSample represents the transaction data which has been trained into the transaction suggestion model stored in database,
+// it is used for removing the previous counts of the transaction when the model is updated incrementally
+type TransactionSuggestionTrainedSample struct {
+	Uid           int64             `xorm:"PK"`
+	TransactionId int64             `xorm:"PK"`
+	Type          TransactionDbType `xorm:"NOT NULL"`
+	AccountId     int64             `xorm:"NOT NULL"`
+	Amount        int64             `xorm:"NOT NULL"`
+	Comment       string            `xorm:"VARCHAR(255) NOT NULL"`
+	CategoryId    int64             `xorm:"NOT NULL"`
+	TagIds        string            `xorm:"VARCHAR(255) NOT NULL"`
+}
+
+// TransactionSuggestionSample represents a transaction which is used for training transaction suggestion model
+type TransactionSuggestionSample struct {
+	Features   []string
+	CategoryId int64
+	TagIds     []int64
+}
+
+// TransactionSuggestionItem represents a suggested category or tag and its confidence
+type TransactionSuggestionItem struct {
+	Id         int64
+	Confidence float64
+}
+
+// TransactionSuggestionRequest represents all parameters of transaction suggestion request
+type TransactionSuggestionRequest struct {
+	Type      TransactionType `form:"type" binding:"required,min=2,max=4"`
+	AccountId int64           `form:"account_id" binding:"min=0"`
+	Amount    int64           `form:"amount"`
+	Comment   string          `form:"comment" binding:"max=255"`
+	Count     int32           `form:"count" binding:"min=0,max=10"`
+}
+
+// TransactionSuggestionItemResponse represents a view-object of suggested category or tag
+type TransactionSuggestionItemResponse struct {
+	Id         int64   `json:"id,string"`
+	Confidence float64 `json:"confidence"`
+}
+
+// TransactionSuggestionResponse represents a view-object of transaction suggestion
+type TransactionSuggestionResponse struct {
+	Categories []*TransactionSuggestionItemResponse `json:"categories"`
+	Tags       []*TransactionSuggestionItemResponse `json:"tags"`
+}
+
+// TransactionSuggestionModel represents the trained naive bayes model of transaction suggestion
+type TransactionSuggestionModel struct {
+	transactionCount int64
+	vocabularySize   int64
+	counts           map[TransactionSuggestionTargetType]map[int64]map[string]int64
+}
+
+// GetTransactionSuggestionFeatures returns the features of the specified transaction data
+func GetTransactionSuggestionFeatures(transactionType TransactionType, accountId int64, amount int64, comment string) []string {
+	features := make([]string, 0, 8)
+	features = append(features, fmt.Sprintf("type:%d", transactionType))
+
+	if accountId > 0 {
+		features = append(features, fmt.Sprintf("acc:%d", accountId))
+	}
+
+	if amount < 0 {
+		amount = -amount
+	}
+
+	features = append(features, fmt.Sprintf("amt:%d", bits.Len64(uint64(amount))))
+
+	words := make(map[string]bool)
+	currentWord := strings.Builder{}
+
+	appendWord := func(word string) {
+		if word == "" || words[word] || len(word) > maximumTransactionSuggestionFeatureLength-2 {
+			return
+		}
+
+		words[word] = true
+		features = append(features, "w:"+word)
+	}
+
+	flushWord := func() {
+		word := currentWord.String()
+		currentWord.Reset()
+
+		if utf8.RuneCountInString(word) < 2 || isTransactionSuggestionNumberWord(word) {
+			return
+		}
+
+		appendWord(word)
+	}
+
+	for _, ch := range strings.ToLower(comment) {
+		if unicode.In(ch, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			flushWord()
+			appendWord(string(ch))
+		} else if unicode.IsLetter(ch) || unicode.IsNumber(ch) {
+			currentWord.WriteRune(ch)
+		} else {
+			flushWord()
+		}
+	}
+
+	flushWord()
+
+	return features
+}
+
+// NewTransactionSuggestionTrainedSample returns a new trained sample of the specified transaction and its tag ids
+func NewTransactionSuggestionTrainedSample(transaction *Transaction, tagIds []int64) *TransactionSuggestionTrainedSample {
+	return &TransactionSuggestionTrainedSample{
+		Uid:           transaction.Uid,
+		TransactionId: transaction.TransactionId,
+		Type:          transaction.Type,
+		AccountId:     transaction.AccountId,
+		Amount:        transaction.Amount,
+		Comment:       transaction.Comment,
+		CategoryId:    transaction.CategoryId,
+		TagIds:        strings.Join(utils.Int64ArrayToStringArray(tagIds), ","),
+	}
+}
+
+// ToTransactionSuggestionSample returns the sample for training transaction suggestion model, or nil if the transaction type is not supported
+func (s *TransactionSuggestionTrainedSample) ToTransactionSuggestionSample() *TransactionSuggestionSample {
+	transactionType, err := s.Type.ToTransactionType()
+
+	if err != nil {
+		return nil
+	}
+
+	var tagIds []int64
+
+	if s.TagIds != "" {
+		tagIds, err = utils.StringArrayToInt64Array(strings.Split(s.TagIds, ","))
+
+		if err != nil {
+			tagIds = nil
+		}
+	}
+
+	return &TransactionSuggestionSample{
+		Features:   GetTransactionSuggestionFeatures(transactionType, s.AccountId, s.Amount, s.Comment),
+		CategoryId: s.CategoryId,
+		TagIds:     tagIds,
+	}
+}
+
+// BuildTransactionSuggestionFeatures returns the feature counts and vocabulary size of the model trained from the specified samples
+func BuildTransactionSuggestionFeatures(uid int64, samples []*TransactionSuggestionSample) ([]*TransactionSuggestionFeature, int64) {
+	counts := make(map[TransactionSuggestionTargetType]map[int64]map[string]int64)
+	vocabulary := make(map[string]bool)
+
+	for i := 0; i < len(samples); i++ {
+		for j := 0; j < len(samples[i].Features); j++ {
+			vocabulary[samples[i].Features[j]] = true
+		}
+
+		addTransactionSuggestionSampleCounts(counts, samples[i], 1)
+	}
+
+	return getTransactionSuggestionFeaturesFromCounts(uid, counts), int64(len(vocabulary))
+}
+
+// GetTransactionSuggestionFeatureCountDeltas returns the count changes of features after the removed samples are untrained and the added samples are trained,
+// the count of returned feature is the delta (may be negative) and the features whose count does not change are not returned
+func GetTransactionSuggestionFeatureCountDeltas(uid int64, removedSamples []*TransactionSuggestionSample, addedSamples []*TransactionSuggestionSample) []*TransactionSuggestionFeature {
+	counts := make(map[TransactionSuggestionTargetType]map[int64]map[string]int64)
+
+	for i := 0; i < len(removedSamples); i++ {
+		addTransactionSuggestionSampleCounts(counts, removedSamples[i], -1)
+	}
+
+	for i := 0; i < len(addedSamples); i++ {
+		addTransactionSuggestionSampleCounts(counts, addedSamples[i], 1)
+	}
+
+	return getTransactionSuggestionFeaturesFromCounts(uid, counts)
+}
+
+func addTransactionSuggestionSampleCounts(counts map[TransactionSuggestionTargetType]map[int64]map[string]int64, sample *TransactionSuggestionSample, weight int64) {
+	addCount := func(targetType TransactionSuggestionTargetType, targetId int64, feature string, count int64) {
+		targetCounts, exists := counts[targetType]
+
+		if !exists {
+			targetCounts = make(map[int64]map[string]int64)
+			counts[targetType] = targetCounts
+		}
+
+		featureCounts, exists := targetCounts[targetId]
+
+		if !exists {
+			featureCounts = make(map[string]int64)
+			targetCounts[targetId] = featureCounts
+		}
+
+		featureCounts[feature] += count
+	}
+
+	addSample := func(targetType TransactionSuggestionTargetType, targetId int64, features []string) {
+		addCount(targetType, targetId, TRANSACTION_SUGGESTION_FEATURE_PRIOR, weight)
+		addCount(targetType, targetId, TRANSACTION_SUGGESTION_FEATURE_TOTAL, int64(len(features))*weight)
+
+		for i := 0; i < len(features); i++ {
+			addCount(targetType, targetId, features[i], weight)
+		}
+	}
+
+	addSample(TRANSACTION_SUGGESTION_TARGET_TYPE_ALL, 0, sample.Features)
+
+	if sample.CategoryId > 0 {
+		addSample(TRANSACTION_SUGGESTION_TARGET_TYPE_CATEGORY, sample.CategoryId, sample.Features)
+	}
+
+	for i := 0; i < len(sample.TagIds); i++ {
+		addSample(TRANSACTION_SUGGESTION_TARGET_TYPE_TAG, sample.TagIds[i], sample.Features)
+	}
+}
+
+func getTransactionSuggestionFeaturesFromCounts(uid int64, counts map[TransactionSuggestionTargetType]map[int64]map[string]int64) []*TransactionSuggestionFeature {
+	result := make([]*TransactionSuggestionFeature, 0)
+
+	for targetType, targetCounts := range counts {
+		for targetId, featureCounts := range targetCounts {
+			for feature, count := range featureCounts {
+				if count == 0 {
+					continue
+				}
+
+				result = append(result, &TransactionSuggestionFeature{
+					Uid:        uid,
+					TargetType: targetType,
+					TargetId:   targetId,
+					Feature:    feature,
+					Count:      count,
+				})
+			}
+		}
+	}
+
+	sort.Sort(TransactionSuggestionFeatureSlice(result))
+
+	return result
+}
+
+// NewTransactionSuggestionModel returns a new transaction suggestion model by the specified model info and feature counts
+func NewTransactionSuggestionModel(modelInfo *TransactionSuggestionModelInfo, features []*TransactionSuggestionFeature) *TransactionSuggestionModel {
+	model := &TransactionSuggestionModel{
+		counts: make(map[TransactionSuggestionTargetType]map[int64]map[string]int64),
+	}
+
+	if modelInfo != nil {
+		model.transactionCount = modelInfo.TransactionCount
+		model.vocabularySize = modelInfo.VocabularySize
+	}
+
+	for i := 0; i < len(features); i++ {
+		feature := features[i]
+		targetCounts, exists := model.counts[feature.TargetType]
+
+		if !exists {
+			targetCounts = make(map[int64]map[string]int64)
+			model.counts[feature.TargetType] = targetCounts
+		}
+
+		featureCounts, exists := targetCounts[feature.TargetId]
+
+		if !exists {
+			featureCounts = make(map[string]int64)
+			targetCounts[feature.TargetId] = featureCounts
+		}
+
+		featureCounts[feature.Feature] = feature.Count
+	}
+
+	return model
+}
+
+// PredictCategories returns the most likely categories (only in the candidate categories) of the transaction with the specified features
+func (m *TransactionSuggestionModel) PredictCategories(features []string, candidateCategoryIds map[int64]bool, count int) []*TransactionSuggestionItem {
+	if m.transactionCount < 1 || count < 1 {
+		return nil
+	}
+
+	categoryCounts := m.counts[TRANSACTION_SUGGESTION_TARGET_TYPE_CATEGORY]
+	scores := make(map[int64]float64, len(categoryCounts))
+	maxScore := math.Inf(-1)
+
+	for categoryId, featureCounts := range categoryCounts {
+		if !candidateCategoryIds[categoryId] {
+			continue
+		}
+
+		score := m.getLogLikelihood(featureCounts[TRANSACTION_SUGGESTION_FEATURE_PRIOR], featureCounts[TRANSACTION_SUGGESTION_FEATURE_TOTAL], features, func(feature string) int64 {
+			return featureCounts[feature]
+		})
+
+		scores[categoryId] = score
+
+		if score > maxScore {
+			maxScore = score
+		}
+	}
+
+	if len(scores) < 1 {
+		return nil
+	}
+
+	totalProbability := 0.0
+
+	for categoryId, score := range scores {
+		scores[categoryId] = math.Exp(score - maxScore)
+		totalProbability += scores[categoryId]
+	}
+
+	result := make([]*TransactionSuggestionItem, 0, len(scores))
+
+	for categoryId, probability := range scores {
+		result = append(result, &TransactionSuggestionItem{
+			Id:         categoryId,
+			Confidence: probability / totalProbability,
+		})
+	}
+
+	sort.Sort(TransactionSuggestionItemSlice(result))
+
+	if len(result) > count {
+		result = result[:count]
+	}
+
+	return result
+}
+
+// PredictTags returns the likely tags (only in the candidate tags) of the transaction with the specified features
+func (m *TransactionSuggestionModel) PredictTags(features []string, candidateTagIds map[int64]bool, count int) []*TransactionSuggestionItem {
+	if m.transactionCount < 1 || count < 1 {
+		return nil
+	}
+
+	allFeatureCounts := m.counts[TRANSACTION_SUGGESTION_TARGET_TYPE_ALL][0]
+	allPriorCount := allFeatureCounts[TRANSACTION_SUGGESTION_FEATURE_PRIOR]
+	allTotalCount := allFeatureCounts[TRANSACTION_SUGGESTION_FEATURE_TOTAL]
+	result := make([]*TransactionSuggestionItem, 0)
+
+	for tagId, featureCounts := range m.counts[TRANSACTION_SUGGESTION_TARGET_TYPE_TAG] {
+		if !candidateTagIds[tagId] {
+			continue
+		}
+
+		priorCount := featureCounts[TRANSACTION_SUGGESTION_FEATURE_PRIOR]
+		totalCount := featureCounts[TRANSACTION_SUGGESTION_FEATURE_TOTAL]
+		confidence := 1.0
+
+		if allPriorCount > priorCount {
+			positiveScore := m.getLogLikelihood(priorCount, totalCount, features, func(feature string) int64 {
+				return featureCounts[feature]
+			})
+			negativeScore := m.getLogLikelihood(allPriorCount-priorCount, allTotalCount-totalCount, features, func(feature string) int64 {
+				return allFeatureCounts[feature] - featureCounts[feature]
+			})
+			confidence = 1 / (1 + math.Exp(negativeScore-positiveScore))
+		}
+
+		if confidence < TransactionSuggestionMinimumConfidence {
+			continue
+		}
+
+		result = append(result, &TransactionSuggestionItem{
+			Id:         tagId,
+			Confidence: confidence,
+		})
+	}
+
+	sort.Sort(TransactionSuggestionItemSlice(result))
+
+	if len(result) > count {
+		result = result[:count]
+	}
+
+	return result
+}
+
+func (m *TransactionSuggestionModel) getLogLikelihood(priorCount int64, totalCount int64, features []string, getFeatureCount func(feature string) int64) float64 {
+	score := math.Log(float64(priorCount) / float64(m.transactionCount))
+	denominator := float64(totalCount) + transactionSuggestionSmoothingFactor*float64(m.vocabularySize+1)
+
+	for i := 0; i < len(features); i++ {
+		score += math.Log((float64(getFeatureCount(features[i])) + transactionSuggestionSmoothingFactor) / denominator)
+	}
+
+	return score
+}
+
+func isTransactionSuggestionNumberWord(word string) bool {
+	for _, ch := range word {
+		if !unicode.IsNumber(ch) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToTransactionSuggestionItemResponseList returns a view-object list according to the suggestion items
+func ToTransactionSuggestionItemResponseList(items []*TransactionSuggestionItem) []*TransactionSuggestionItemResponse {
+	result := make([]*TransactionSuggestionItemResponse, len(items))
+
+	for i := 0; i < len(items); i++ {
+		result[i] = &TransactionSuggestionItemResponse{
+			Id:         items[i].Id,
+			Confidence: math.Round(items[i].Confidence*10000) / 10000,
+		}
+	}
+
+	return result
+}
+
+// TransactionSuggestionItemSlice represents the slice data structure of TransactionSuggestionItem
+type TransactionSuggestionItemSlice []*TransactionSuggestionItem
+
+// Len returns the count of items
+func (s TransactionSuggestionItemSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionSuggestionItemSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionSuggestionItemSlice) Less(i, j int) bool {
+	if s[i].Confidence != s[j].Confidence {
+		return s[i].Confidence > s[j].Confidence
+	}
+
+	return s[i].Id < s[j].Id
+}
+
+// TransactionSuggestionFeatureSlice represents the slice data structure of TransactionSuggestionFeature
+type TransactionSuggestionFeatureSlice []*TransactionSuggestionFeature
+
+// Len returns the count of items
+func (s TransactionSuggestionFeatureSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionSuggestionFeatureSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionSuggestionFeatureSlice) Less(i, j int) bool {
+	if s[i].TargetType != s[j].TargetType {
+		return s[i].TargetType < s[j].TargetType
+	}
+
+	if s[i].TargetId != s[j].TargetId {
+		return s[i].TargetId < s[j].TargetId
+	}
+
+	return s[i].Feature < s[j].Feature
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransactionSuggestionFeatures(t *testing.T) {
+	features := GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 123, -1500, "Starbucks Coffee #1234, coffee")
+	assert.Equal(t, []string{"type:3", "acc:123", "amt:11", "w:starbucks", "w:coffee"}, features)
+
+	features = GetTransactionSuggestionFeatures(TRANSACTION_TYPE_INCOME, 0, 0, "")
+	assert.Equal(t, []string{"type:2", "amt:0"}, features)
+
+	features = GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 0, 100, "星巴克 a")
+	assert.Equal(t, []string{"type:3", "amt:7", "w:星", "w:巴", "w:克"}, features)
+}
+
+func TestBuildTransactionSuggestionFeatures(t *testing.T) {
+	samples := []*TransactionSuggestionSample{
+		{Features: []string{"w:coffee"}, CategoryId: 1, TagIds: []int64{10}},
+		{Features: []string{"w:coffee", "w:bean"}, CategoryId: 1},
+	}
+
+	features, vocabularySize := BuildTransactionSuggestionFeatures(1001, samples)
+	assert.Equal(t, int64(2), vocabularySize)
+
+	counts := make(map[TransactionSuggestionTargetType]map[int64]map[string]int64)
+
+	for i := 0; i < len(features); i++ {
+		feature := features[i]
+		assert.Equal(t, int64(1001), feature.Uid)
+
+		if counts[feature.TargetType] == nil {
+			counts[feature.TargetType] = make(map[int64]map[string]int64)
+		}
+
+		if counts[feature.TargetType][feature.TargetId] == nil {
+			counts[feature.TargetType][feature.TargetId] = make(map[string]int64)
+		}
+
+		counts[feature.TargetType][feature.TargetId][feature.Feature] = feature.Count
+	}
+
+	assert.Equal(t, map[string]int64{TRANSACTION_SUGGESTION_FEATURE_PRIOR: 2, TRANSACTION_SUGGESTION_FEATURE_TOTAL: 3, "w:coffee": 2, "w:bean": 1}, counts[TRANSACTION_SUGGESTION_TARGET_TYPE_ALL][0])
+	assert.Equal(t, map[string]int64{TRANSACTION_SUGGESTION_FEATURE_PRIOR: 2, TRANSACTION_SUGGESTION_FEATURE_TOTAL: 3, "w:coffee": 2, "w:bean": 1}, counts[TRANSACTION_SUGGESTION_TARGET_TYPE_CATEGORY][1])
+	assert.Equal(t, map[string]int64{TRANSACTION_SUGGESTION_FEATURE_PRIOR: 1, TRANSACTION_SUGGESTION_FEATURE_TOTAL: 1, "w:coffee": 1}, counts[TRANSACTION_SUGGESTION_TARGET_TYPE_TAG][10])
+}
+
+func TestGetTransactionSuggestionFeatureCountDeltas(t *testing.T) {
+	removedSamples := []*TransactionSuggestionSample{
+		{Features: []string{"w:coffee"}, CategoryId: 1, TagIds: []int64{10}},
+	}
+	addedSamples := []*TransactionSuggestionSample{
+		{Features: []string{"w:coffee", "w:bean"}, CategoryId: 2},
+	}
+
+	deltas := GetTransactionSuggestionFeatureCountDeltas(1001, removedSamples, addedSamples)
+	counts := make(map[TransactionSuggestionTargetType]map[int64]map[string]int64)
+
+	for i := 0; i < len(deltas); i++ {
+		delta := deltas[i]
+		assert.Equal(t, int64(1001), delta.Uid)
+
+		if counts[delta.TargetType] == nil {
+			counts[delta.TargetType] = make(map[int64]map[string]int64)
+		}
+
+		if counts[delta.TargetType][delta.TargetId] == nil {
+			counts[delta.TargetType][delta.TargetId] = make(map[string]int64)
+		}
+
+		counts[delta.TargetType][delta.TargetId][delta.Feature] = delta.Count
+	}
+
+	assert.Equal(t, map[string]int64{TRANSACTION_SUGGESTION_FEATURE_TOTAL: 1, "w:bean": 1}, counts[TRANSACTION_SUGGESTION_TARGET_TYPE_ALL][0])
+	assert.Equal(t, map[string]int64{TRANSACTION_SUGGESTION_FEATURE_PRIOR: -1, TRANSACTION_SUGGESTION_FEATURE_TOTAL: -1, "w:coffee": -1}, counts[TRANSACTION_SUGGESTION_TARGET_TYPE_CATEGORY][1])
+	assert.Equal(t, map[string]int64{TRANSACTION_SUGGESTION_FEATURE_PRIOR: 1, TRANSACTION_SUGGESTION_FEATURE_TOTAL: 2, "w:coffee": 1, "w:bean": 1}, counts[TRANSACTION_SUGGESTION_TARGET_TYPE_CATEGORY][2])
+	assert.Equal(t, map[string]int64{TRANSACTION_SUGGESTION_FEATURE_PRIOR: -1, TRANSACTION_SUGGESTION_FEATURE_TOTAL: -1, "w:coffee": -1}, counts[TRANSACTION_SUGGESTION_TARGET_TYPE_TAG][10])
+}
+
+func TestTransactionSuggestionTrainedSampleToTransactionSuggestionSample(t *testing.T) {
+	transaction := &Transaction{
+		TransactionId: 2001,
+		Uid:           1001,
+		Type:          TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:    11,
+		AccountId:     123,
+		Amount:        1500,
+		Comment:       "Starbucks Coffee",
+	}
+
+	trainedSample := NewTransactionSuggestionTrainedSample(transaction, []int64{21, 22})
+	assert.Equal(t, int64(1001), trainedSample.Uid)
+	assert.Equal(t, int64(2001), trainedSample.TransactionId)
+	assert.Equal(t, "21,22", trainedSample.TagIds)
+
+	sample := trainedSample.ToTransactionSuggestionSample()
+	assert.Equal(t, GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 123, 1500, "Starbucks Coffee"), sample.Features)
+	assert.Equal(t, int64(11), sample.CategoryId)
+	assert.Equal(t, []int64{21, 22}, sample.TagIds)
+
+	sample = NewTransactionSuggestionTrainedSample(transaction, nil).ToTransactionSuggestionSample()
+	assert.Nil(t, sample.TagIds)
+}
+
+func TestTransactionSuggestionModelPredictCategories(t *testing.T) {
+	samples := []*TransactionSuggestionSample{
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 500, "Starbucks coffee"), CategoryId: 11},
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 450, "Coffee shop"), CategoryId: 11},
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 2, 8000, "Supermarket groceries"), CategoryId: 12},
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 2, 6500, "Weekly groceries"), CategoryId: 12},
+	}
+
+	features, vocabularySize := BuildTransactionSuggestionFeatures(1001, samples)
+	model := NewTransactionSuggestionModel(&TransactionSuggestionModelInfo{TransactionCount: int64(len(samples)), VocabularySize: vocabularySize}, features)
+	candidateCategoryIds := map[int64]bool{11: true, 12: true}
+
+	result := model.PredictCategories(GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 480, "coffee"), candidateCategoryIds, 2)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, int64(11), result[0].Id)
+	assert.Greater(t, result[0].Confidence, TransactionSuggestionMinimumConfidence)
+	assert.InDelta(t, 1.0, result[0].Confidence+result[1].Confidence, 0.000001)
+
+	result = model.PredictCategories(GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 2, 7000, "groceries"), candidateCategoryIds, 1)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, int64(12), result[0].Id)
+
+	result = model.PredictCategories(GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 480, "coffee"), map[int64]bool{12: true}, 2)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, int64(12), result[0].Id)
+}
+
+func TestTransactionSuggestionModelPredictTags(t *testing.T) {
+	samples := []*TransactionSuggestionSample{
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 3000, "Business lunch"), CategoryId: 11, TagIds: []int64{21}},
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 3500, "Business dinner"), CategoryId: 11, TagIds: []int64{21}},
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 1200, "Family dinner"), CategoryId: 11},
+		{Features: GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 900, "Family lunch"), CategoryId: 11},
+	}
+
+	features, vocabularySize := BuildTransactionSuggestionFeatures(1001, samples)
+	model := NewTransactionSuggestionModel(&TransactionSuggestionModelInfo{TransactionCount: int64(len(samples)), VocabularySize: vocabularySize}, features)
+
+	result := model.PredictTags(GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 3200, "business meeting"), map[int64]bool{21: true}, 10)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, int64(21), result[0].Id)
+
+	result = model.PredictTags(GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 1000, "family trip"), map[int64]bool{21: true}, 10)
+	assert.Equal(t, 0, len(result))
+
+	result = model.PredictTags(GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 3200, "business meeting"), map[int64]bool{}, 10)
+	assert.Equal(t, 0, len(result))
+}
+
+func TestTransactionSuggestionModelPredict_EmptyModel(t *testing.T) {
+	model := NewTransactionSuggestionModel(nil, nil)
+	features := GetTransactionSuggestionFeatures(TRANSACTION_TYPE_EXPENSE, 1, 480, "coffee")
+
+	assert.Equal(t, 0, len(model.PredictCategories(features, map[int64]bool{11: true}, 3)))
+	assert.Equal(t, 0, len(model.PredictTags(features, map[int64]bool{21: true}, 3)))
+}
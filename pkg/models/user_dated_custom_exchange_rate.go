@@ -0,0 +1,102 @@
+package models
+
+import (
+	"math"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// UserDatedCustomExchangeRate represents user custom exchange rate data which takes effect from the specified date
+type UserDatedCustomExchangeRate struct {
+	Uid             int64  `xorm:"PK NOT NULL"`
+	DeletedUnixTime int64  `xorm:"PK NOT NULL"`
+	Currency        string `xorm:"PK VARCHAR(3) NOT NULL"`
+	EffectiveDate   string `xorm:"PK VARCHAR(10) NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            int64  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// UserDatedCustomExchangeRateListRequest represents all parameters of user dated custom exchange rates listing request
+type UserDatedCustomExchangeRateListRequest struct {
+	Currency string `form:"currency" binding:"omitempty,len=3,validCurrency"`
+}
+
+// UserDatedCustomExchangeRateUpdateRequest represents all parameters of user dated custom exchange rate data updating request
+type UserDatedCustomExchangeRateUpdateRequest struct {
+	Currency      string `json:"currency" binding:"required,len=3,validCurrency"`
+	EffectiveDate string `json:"effectiveDate" binding:"required,len=10"`
+	Rate          string `json:"rate" binding:"required"`
+}
+
+// UserDatedCustomExchangeRateDeleteRequest represents all parameters of user dated custom exchange rate data deleting request
+type UserDatedCustomExchangeRateDeleteRequest struct {
+	Currency      string `json:"currency" binding:"required,len=3,validCurrency"`
+	EffectiveDate string `json:"effectiveDate" binding:"required,len=10"`
+}
+
+// UserDatedCustomExchangeRateInfoResponse represents a view-object of user dated custom exchange rate
+type UserDatedCustomExchangeRateInfoResponse struct {
+	Currency      string `json:"currency"`
+	EffectiveDate string `json:"effectiveDate"`
+	BaseCurrency  string `json:"baseCurrency"`
+	Rate          string `json:"rate"`
+	UpdateTime    int64  `json:"updateTime"`
+}
+
+// GetRate returns the exchange rate of the currency against the base currency
+func (r *UserDatedCustomExchangeRate) GetRate() float64 {
+	return float64(r.Rate) / float64(UserCustomExchangeRateFactorInDatabase)
+}
+
+// ToUserDatedCustomExchangeRateInfoResponse returns a view-object according to database model
+func (r *UserDatedCustomExchangeRate) ToUserDatedCustomExchangeRateInfoResponse() *UserDatedCustomExchangeRateInfoResponse {
+	return &UserDatedCustomExchangeRateInfoResponse{
+		Currency:      r.Currency,
+		EffectiveDate: r.EffectiveDate,
+		BaseCurrency:  r.BaseCurrency,
+		Rate:          utils.Float64ToString(r.GetRate()),
+		UpdateTime:    r.UpdatedUnixTime,
+	}
+}
+
+// CreateUserDatedCustomExchangeRate returns a user dated custom exchange rate database model according to currency, effective date and rate
+func CreateUserDatedCustomExchangeRate(uid int64, currency string, effectiveDate string, exchangeRate string, baseCurrency string) (*UserDatedCustomExchangeRate, error) {
+	rate, err := utils.StringToFloat64(exchangeRate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserDatedCustomExchangeRate{
+		Uid:           uid,
+		Currency:      currency,
+		EffectiveDate: effectiveDate,
+		BaseCurrency:  baseCurrency,
+		Rate:          int64(math.Round(rate * float64(UserCustomExchangeRateFactorInDatabase))),
+	}, nil
+}
+
+// UserDatedCustomExchangeRateInfoResponseSlice represents the slice data structure of UserDatedCustomExchangeRateInfoResponse
+type UserDatedCustomExchangeRateInfoResponseSlice []*UserDatedCustomExchangeRateInfoResponse
+
+// Len returns the count of items
+func (s UserDatedCustomExchangeRateInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s UserDatedCustomExchangeRateInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s UserDatedCustomExchangeRateInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Currency != s[j].Currency {
+		return strings.Compare(s[i].Currency, s[j].Currency) < 0
+	}
+
+	return strings.Compare(s[i].EffectiveDate, s[j].EffectiveDate) > 0
+}
@@ -0,0 +1,60 @@
+package models
+
+// UserExternalAuthType represents external authentication type
+type UserExternalAuthType byte
+
+// External authentication types
+const (
+	USER_EXTERNAL_AUTH_TYPE_OIDC UserExternalAuthType = 1
+	USER_EXTERNAL_AUTH_TYPE_LDAP UserExternalAuthType = 2
+)
+
+// String returns a textual representation of the external authentication type
+func (t UserExternalAuthType) String() string {
+	switch t {
+	case USER_EXTERNAL_AUTH_TYPE_OIDC:
+		return "OIDC"
+	case USER_EXTERNAL_AUTH_TYPE_LDAP:
+		return "LDAP"
+	default:
+		return "Unknown"
+	}
+}
+
+// UserExternalAuth represents the link between user and external account stored in database
+type UserExternalAuth struct {
+	Uid              int64                `xorm:"PK"`
+	ExternalAuthType UserExternalAuthType `xorm:"PK UNIQUE(UQE_user_external_auth_type_external_user_id) NOT NULL"`
+	ExternalUserId   string               `xorm:"VARCHAR(255) UNIQUE(UQE_user_external_auth_type_external_user_id) NOT NULL"`
+	ExternalUsername string               `xorm:"VARCHAR(255)"`
+	ExternalEmail    string               `xorm:"VARCHAR(100)"`
+	CreatedUnixTime  int64
+}
+
+// UserOIDCLinkRequest represents all parameters of user linking oidc external account request
+type UserOIDCLinkRequest struct {
+	Platform string `json:"platform" binding:"required,oneof=desktop mobile"`
+}
+
+// UserOIDCLinkResponse represents all response parameters of user linking oidc external account request
+type UserOIDCLinkResponse struct {
+	Url string `json:"url"`
+}
+
+// UserExternalAuthInfoResponse represents a view-object of user external authentication
+type UserExternalAuthInfoResponse struct {
+	ExternalAuthType UserExternalAuthType `json:"externalAuthType"`
+	ExternalUsername string               `json:"externalUsername"`
+	ExternalEmail    string               `json:"externalEmail"`
+	CreatedAt        int64                `json:"createdAt"`
+}
+
+// ToUserExternalAuthInfoResponse returns a view-object according to database model
+func (a *UserExternalAuth) ToUserExternalAuthInfoResponse() *UserExternalAuthInfoResponse {
+	return &UserExternalAuthInfoResponse{
+		ExternalAuthType: a.ExternalAuthType,
+		ExternalUsername: a.ExternalUsername,
+		ExternalEmail:    a.ExternalEmail,
+		CreatedAt:        a.CreatedUnixTime,
+	}
+}
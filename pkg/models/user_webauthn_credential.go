@@ -0,0 +1,108 @@
+package models
+
+import "encoding/json"
+
+// WebAuthnCredentialMaxNameLength represents the maximum size of passkey name stored in database
+const WebAuthnCredentialMaxNameLength = 64
+
+// WebAuthnCredentialMaxCountPerUser represents the maximum count of passkeys of each user
+const WebAuthnCredentialMaxCountPerUser = 20
+
+// webAuthnFlagBackupEligible represents the backup eligible flag in authenticator data
+const webAuthnFlagBackupEligible uint8 = 0x08
+
+// Supported attestation formats of passkey
+const (
+	WEBAUTHN_ATTESTATION_FORMAT_NONE   = "none"
+	WEBAUTHN_ATTESTATION_FORMAT_PACKED = "packed"
+)
+
+// Two-factor authentication methods
+const (
+	TWO_FACTOR_METHOD_PASSCODE = "passcode"
+	TWO_FACTOR_METHOD_WEBAUTHN = "webauthn"
+)
+
+// UserWebAuthnCredential represents user passkey (webauthn credential) stored in database
+type UserWebAuthnCredential struct {
+	Uid               int64  `xorm:"PK"`
+	CredentialId      int64  `xorm:"PK"`
+	Name              string `xorm:"VARCHAR(64) NOT NULL"`
+	RawCredentialId   string `xorm:"VARCHAR(1400) NOT NULL"`
+	PublicKey         []byte `xorm:"BLOB NOT NULL"`
+	AttestationFormat string `xorm:"VARCHAR(32) NOT NULL"`
+	Transports        string `xorm:"VARCHAR(255)"`
+	Aaguid            string `xorm:"VARCHAR(36)"`
+	Flags             uint8  `xorm:"TINYINT NOT NULL"`
+	SignCount         uint32 `xorm:"NOT NULL"`
+	CreatedUnixTime   int64
+	LastUsedUnixTime  int64
+}
+
+// UserWebAuthnChallenge represents the challenge of pending passkey ceremony stored in database, each challenge can only be used once
+type UserWebAuthnChallenge struct {
+	Challenge       string `xorm:"VARCHAR(128) PK"`
+	Uid             int64  `xorm:"NOT NULL"`
+	Purpose         string `xorm:"VARCHAR(16) NOT NULL"`
+	CreatedUnixTime int64
+	ExpiredUnixTime int64 `xorm:"INDEX(IDX_user_webauthn_challenge_expired_time)"`
+}
+
+// WebAuthnRegisterBeginResponse represents all response parameters of passkey registration begin request
+type WebAuthnRegisterBeginResponse struct {
+	Options any    `json:"options"`
+	Session string `json:"session"`
+}
+
+// WebAuthnRegisterFinishRequest represents all parameters of passkey registration finish request
+type WebAuthnRegisterFinishRequest struct {
+	Name       string          `json:"name" binding:"required,notBlank,max=64"`
+	Session    string          `json:"session" binding:"required,notBlank"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// WebAuthnLoginBeginResponse represents all response parameters of passkey login begin request
+type WebAuthnLoginBeginResponse struct {
+	Options any    `json:"options"`
+	Session string `json:"session"`
+}
+
+// WebAuthnLoginRequest represents all parameters of passkey login request
+type WebAuthnLoginRequest struct {
+	Session    string          `json:"session" binding:"required,notBlank"`
+	Credential json.RawMessage `json:"credential" binding:"required"`
+}
+
+// WebAuthnCredentialModifyRequest represents all parameters of passkey modification request
+type WebAuthnCredentialModifyRequest struct {
+	Id   int64  `json:"id,string" binding:"required,min=1"`
+	Name string `json:"name" binding:"required,notBlank,max=64"`
+}
+
+// WebAuthnCredentialDeleteRequest represents all parameters of passkey deletion request
+type WebAuthnCredentialDeleteRequest struct {
+	Id       int64  `json:"id,string" binding:"required,min=1"`
+	Password string `json:"password" binding:"omitempty,min=6,max=128"`
+}
+
+// WebAuthnCredentialInfoResponse represents a view-object of user passkey
+type WebAuthnCredentialInfoResponse struct {
+	Id                int64  `json:"id,string"`
+	Name              string `json:"name"`
+	AttestationFormat string `json:"attestationFormat"`
+	BackupEligible    bool   `json:"backupEligible"`
+	CreatedAt         int64  `json:"createdAt"`
+	LastUsedAt        int64  `json:"lastUsedAt,omitempty"`
+}
+
+// ToWebAuthnCredentialInfoResponse returns a view-object according to database model
+func (c *UserWebAuthnCredential) ToWebAuthnCredentialInfoResponse() *WebAuthnCredentialInfoResponse {
+	return &WebAuthnCredentialInfoResponse{
+		Id:                c.CredentialId,
+		Name:              c.Name,
+		AttestationFormat: c.AttestationFormat,
+		BackupEligible:    c.Flags&webAuthnFlagBackupEligible == webAuthnFlagBackupEligible,
+		CreatedAt:         c.CreatedUnixTime,
+		LastUsedAt:        c.LastUsedUnixTime,
+	}
+}
@@ -731,6 +731,28 @@ func (s *AccountService) DeleteAccount(c core.Context, uid int64, accountId int6
 			return errs.ErrAccountNotFound
 		}
 
+		updateFixedIncomeTerm := &models.FixedIncomeTerm{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Update(updateFixedIncomeTerm)
+
+		if err != nil {
+			return err
+		}
+
+		updateLoanTerm := &models.LoanTerm{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Update(updateLoanTerm)
+
+		if err != nil {
+			return err
+		}
+
 		if len(relatedTransactionsByAccount) > 0 {
 			updateTransaction := &models.Transaction{
 				Deleted:         true,
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// AuditEventService represents security audit event service
+type AuditEventService struct {
+	ServiceUsingDB
+	ServiceUsingConfig
+	ServiceUsingUuid
+}
+
+// Initialize a security audit event service singleton instance
+var (
+	AuditEvents = &AuditEventService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAuditEventsByUid returns the audit events of given user in the given page, the newest events are returned first
+func (s *AuditEventService) GetAuditEventsByUid(c core.Context, uid int64, page int32, count int32) ([]*models.AuditEvent, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if page < 1 {
+		return nil, errs.ErrPageIndexInvalid
+	}
+
+	if count < 1 {
+		return nil, errs.ErrPageCountInvalid
+	}
+
+	var events []*models.AuditEvent
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("created_unix_time desc, event_id desc").Limit(int(count), int(count*(page-1))).Find(&events)
+
+	return events, err
+}
+
+// GetAuditEventCountByUid returns the total count of audit events of given user
+func (s *AuditEventService) GetAuditEventCountByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().NewSession(c).Where("uid=?", uid).Count(&models.AuditEvent{})
+}
+
+// CreateAuditEvent saves a new audit event of given user with client info of current request to database,
+// the failure of saving audit event does not affect current request, so the error is only logged
+func (s *AuditEventService) CreateAuditEvent(c *core.WebContext, uid int64, eventType models.AuditEventType, detail string) {
+	if uid <= 0 {
+		return
+	}
+
+	event := &models.AuditEvent{
+		EventId:         s.GenerateUuid(uuid.UUID_TYPE_AUDIT_EVENT),
+		Uid:             uid,
+		EventType:       eventType,
+		IpAddress:       c.ClientIP(),
+		UserAgent:       s.getUserAgent(c),
+		RequestId:       c.GetContextId(),
+		Detail:          detail,
+		CreatedUnixTime: time.Now().Unix(),
+	}
+
+	if len(event.Detail) > models.AuditEventMaxDetailLength {
+		event.Detail = utils.SubString(event.Detail, 0, models.AuditEventMaxDetailLength)
+	}
+
+	if event.EventId < 1 {
+		log.Errorf(c, "[audit_events.CreateAuditEvent] failed to generate event id for audit event \"%s\" of user \"uid:%d\"", eventType, uid)
+		return
+	}
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(event)
+		return err
+	})
+
+	if err != nil {
+		log.Errorf(c, "[audit_events.CreateAuditEvent] failed to save audit event \"%s\" of user \"uid:%d\", because %s", eventType, uid, err.Error())
+	}
+}
+
+// DeleteAllExpiredAuditEvents deletes all audit events which are older than the retention days
+func (s *AuditEventService) DeleteAllExpiredAuditEvents(c core.Context) error {
+	retentionDays := s.CurrentConfig().AuditEventRetentionDays
+
+	if retentionDays < 1 {
+		log.Infof(c, "[audit_events.DeleteAllExpiredAuditEvents] audit events are kept forever, no audit events need to be deleted")
+		return nil
+	}
+
+	expiredUnixTime := time.Now().Add(-time.Duration(retentionDays) * 24 * time.Hour).Unix()
+	totalCount := int64(0)
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		count, err := sess.Where("created_unix_time<?", expiredUnixTime).Delete(&models.AuditEvent{})
+		totalCount = count
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if totalCount > 0 {
+		log.Infof(c, "[audit_events.DeleteAllExpiredAuditEvents] %d expired audit events have been deleted", totalCount)
+	} else {
+		log.Infof(c, "[audit_events.DeleteAllExpiredAuditEvents] no expired audit events have been deleted")
+	}
+
+	return nil
+}
+
+func (s *AuditEventService) getUserAgent(c *core.WebContext) string {
+	userAgent := ""
+
+	if c != nil && c.Request != nil {
+		userAgent = c.Request.UserAgent()
+	}
+
+	if len(userAgent) > models.AuditEventMaxUserAgentLength {
+		userAgent = utils.SubString(userAgent, 0, models.AuditEventMaxUserAgentLength)
+	}
+
+	return userAgent
+}
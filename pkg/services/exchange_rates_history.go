@@ -0,0 +1,133 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const exchangeRatesHistoryMaxLookBackDays = 8
+
+// ExchangeRatesHistoryService represents historical exchange rates data service
+type ExchangeRatesHistoryService struct {
+	ServiceUsingDB
+}
+
+// Initialize a historical exchange rates data service singleton instance
+var (
+	ExchangeRatesHistory = &ExchangeRatesHistoryService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetExchangeRatesByDate returns the historical exchange rate models of the given data source published on or before the given date
+func (s *ExchangeRatesHistoryService) GetExchangeRatesByDate(c core.Context, dataSource string, rateDate string) ([]*models.ExchangeRateHistory, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if rateDate == "" {
+		return nil, errs.ErrExchangeRatesDateInvalid
+	}
+
+	latestExchangeRate := &models.ExchangeRateHistory{}
+	has, err := s.UserDB().NewSession(c).Cols("rate_date").Where("data_source=? AND rate_date<=?", dataSource, rateDate).OrderBy("rate_date desc").Limit(1).Get(latestExchangeRate)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+
+	var exchangeRates []*models.ExchangeRateHistory
+	err = s.UserDB().NewSession(c).Where("data_source=? AND rate_date=?", dataSource, latestExchangeRate.RateDate).Find(&exchangeRates)
+
+	return exchangeRates, err
+}
+
+// GetExchangeRatesByTimeRange returns the historical exchange rate models of the given data source which can be used by the transactions between the given unix times,
+// the exchange rates published several days before the start time are also returned, since the exchange rates are not published on weekends and holidays
+func (s *ExchangeRatesHistoryService) GetExchangeRatesByTimeRange(c core.Context, dataSource string, startUnixTime int64, endUnixTime int64) ([]*models.ExchangeRateHistory, error) {
+	if dataSource == "" {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	condition := "data_source=?"
+	conditionParams := []any{dataSource}
+
+	if startUnixTime > 0 {
+		// the transactions in the timezones ahead of UTC may be on the next day of the start time
+		startDate := time.Unix(startUnixTime, 0).UTC().AddDate(0, 0, -exchangeRatesHistoryMaxLookBackDays).Format(time.DateOnly)
+		condition = condition + " AND rate_date>=?"
+		conditionParams = append(conditionParams, startDate)
+	}
+
+	if endUnixTime > 0 {
+		// the transactions in the timezones ahead of UTC may be on the next day of the end time
+		endDate := time.Unix(endUnixTime, 0).UTC().AddDate(0, 0, 1).Format(time.DateOnly)
+		condition = condition + " AND rate_date<=?"
+		conditionParams = append(conditionParams, endDate)
+	}
+
+	var exchangeRates []*models.ExchangeRateHistory
+	err := s.UserDB().NewSession(c).Where(condition, conditionParams...).OrderBy("rate_date asc").Find(&exchangeRates)
+
+	return exchangeRates, err
+}
+
+// SaveExchangeRatesResponse saves the exchange rates of the given data source response to database as the historical exchange rates of its publishing date
+func (s *ExchangeRatesHistoryService) SaveExchangeRatesResponse(c core.Context, dataSource string, exchangeRateResp *models.LatestExchangeRateResponse) (*models.HistoricalExchangeRateResponse, error) {
+	if exchangeRateResp == nil || exchangeRateResp.UpdateTime <= 0 || len(exchangeRateResp.ExchangeRates) < 1 {
+		return nil, errs.ErrExchangeRatesHistoryNotFound
+	}
+
+	rateDate := time.Unix(exchangeRateResp.UpdateTime, 0).UTC().Format(time.DateOnly)
+	exchangeRateHistories := models.CreateExchangeRateHistories(dataSource, rateDate, exchangeRateResp)
+	err := s.SaveExchangeRates(c, dataSource, rateDate, exchangeRateHistories)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ToHistoricalExchangeRateResponse(exchangeRateHistories), nil
+}
+
+// SaveExchangeRates saves the exchange rates of the given data source and date to database, the existed exchange rates of the same date would be replaced
+func (s *ExchangeRatesHistoryService) SaveExchangeRates(c core.Context, dataSource string, rateDate string, exchangeRates []*models.ExchangeRateHistory) error {
+	if dataSource == "" {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	if rateDate == "" {
+		return errs.ErrExchangeRatesDateInvalid
+	}
+
+	now := time.Now().Unix()
+
+	for i := 0; i < len(exchangeRates); i++ {
+		exchangeRates[i].CreatedUnixTime = now
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("data_source=? AND rate_date=?", dataSource, rateDate).Delete(&models.ExchangeRateHistory{})
+
+		if err != nil {
+			return err
+		}
+
+		if len(exchangeRates) < 1 {
+			return nil
+		}
+
+		_, err = sess.Insert(exchangeRates)
+
+		return err
+	})
+}
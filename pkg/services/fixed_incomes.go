@@ -0,0 +1,252 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// FixedIncomeService represents fixed income term service
+type FixedIncomeService struct {
+	ServiceUsingDB
+	transactionService *TransactionService
+}
+
+// Initialize a fixed income term service singleton instance
+var (
+	FixedIncomes = &FixedIncomeService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		transactionService: Transactions,
+	}
+)
+
+// GetAllFixedIncomeTermsByUid returns all fixed income term models of user
+func (s *FixedIncomeService) GetAllFixedIncomeTermsByUid(c core.Context, uid int64) ([]*models.FixedIncomeTerm, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var terms []*models.FixedIncomeTerm
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("maturity_date asc").Find(&terms)
+
+	return terms, err
+}
+
+// GetFixedIncomeTermByAccountId returns the fixed income term model of the specified account
+func (s *FixedIncomeService) GetFixedIncomeTermByAccountId(c core.Context, uid int64, accountId int64) (*models.FixedIncomeTerm, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	term := &models.FixedIncomeTerm{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Get(term)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrFixedIncomeTermNotFound
+	}
+
+	return term, nil
+}
+
+// SetFixedIncomeTerm saves the fixed income term of the account to database, the last payout date is kept if the start date is not changed
+func (s *FixedIncomeService) SetFixedIncomeTerm(c core.Context, term *models.FixedIncomeTerm) error {
+	if term.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	term.Deleted = false
+	term.UpdatedUnixTime = now
+	term.DeletedUnixTime = 0
+
+	return s.UserDataDB(term.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldTerm := &models.FixedIncomeTerm{}
+		has, err := sess.ID(term.AccountId).Where("uid=?", term.Uid).Get(oldTerm)
+
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			term.CreatedUnixTime = now
+			_, err = sess.Insert(term)
+			return err
+		}
+
+		if !oldTerm.Deleted && oldTerm.StartDate == term.StartDate {
+			term.LastPayoutDate = oldTerm.LastPayoutDate
+		}
+
+		term.CreatedUnixTime = oldTerm.CreatedUnixTime
+		_, err = sess.ID(term.AccountId).Where("uid=?", term.Uid).AllCols().Update(term)
+
+		return err
+	})
+}
+
+// DeleteFixedIncomeTerm deletes the fixed income term of the account from database
+func (s *FixedIncomeService) DeleteFixedIncomeTerm(c core.Context, uid int64, accountId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.FixedIncomeTerm{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrFixedIncomeTermNotFound
+		}
+
+		return err
+	})
+}
+
+// PostAllDueInterests creates income transactions for all fixed income interests which are due and have not been paid out yet
+func (s *FixedIncomeService) PostAllDueInterests(c core.Context, currentUnixTime int64) error {
+	var allTerms []*models.FixedIncomeTerm
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var terms []*models.FixedIncomeTerm
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND start_date<=? AND (last_payout_date IS NULL OR last_payout_date<maturity_date)", false, utils.FormatUnixTimeToLongDate(currentUnixTime+24*60*60, time.UTC)).Find(&terms)
+
+		if err != nil {
+			return err
+		}
+
+		allTerms = append(allTerms, terms...)
+	}
+
+	if len(allTerms) < 1 {
+		return nil
+	}
+
+	log.Infof(c, "[fixed_incomes.PostAllDueInterests] should process %d fixed income terms now", len(allTerms))
+
+	successCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allTerms); i++ {
+		term := allTerms[i]
+		termTimezone := time.FixedZone("Term Timezone", int(term.TimezoneUtcOffset)*60)
+		currentDate := utils.FormatUnixTimeToLongDate(currentUnixTime, termTimezone)
+		payouts, err := term.GetDueInterestPayouts(currentDate)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[fixed_incomes.PostAllDueInterests] failed to calculate due interests of fixed income term \"account_id:%d\" for user \"uid:%d\", because %s", term.AccountId, term.Uid, err.Error())
+			continue
+		}
+
+		for j := 0; j < len(payouts); j++ {
+			err = s.postInterest(c, term, payouts[j])
+
+			if err != nil {
+				failedCount++
+				log.Errorf(c, "[fixed_incomes.PostAllDueInterests] failed to post interest of %s for fixed income term \"account_id:%d\" of user \"uid:%d\", because %s", payouts[j].PayoutDate, term.AccountId, term.Uid, err.Error())
+				break
+			}
+
+			successCount++
+		}
+	}
+
+	log.Infof(c, "[fixed_incomes.PostAllDueInterests] %d interest transactions has been created successfully and %d interest transactions failed to create", successCount, failedCount)
+
+	return nil
+}
+
+func (s *FixedIncomeService) postInterest(c core.Context, term *models.FixedIncomeTerm, payout *models.FixedIncomeInterestPayout) error {
+	updateLastPayoutDate := func(sess *xorm.Session) error {
+		updateModel := &models.FixedIncomeTerm{
+			LastPayoutDate:  payout.PayoutDate,
+			UpdatedUnixTime: time.Now().Unix(),
+		}
+
+		sess = sess.Cols("last_payout_date", "updated_unix_time").Where("uid=? AND deleted=? AND account_id=?", term.Uid, false, term.AccountId)
+
+		if term.LastPayoutDate == "" {
+			sess = sess.And("(last_payout_date IS NULL OR last_payout_date=?)", "")
+		} else {
+			sess = sess.And("last_payout_date=?", term.LastPayoutDate)
+		}
+
+		updatedRows, err := sess.Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrFixedIncomeInterestAlreadyPosted
+		}
+
+		return nil
+	}
+
+	if payout.Amount > 0 {
+		payoutTime, err := utils.ParseFromLongDateFirstTime(payout.PayoutDate, term.TimezoneUtcOffset)
+
+		if err != nil {
+			return err
+		}
+
+		accountId := term.AccountId
+
+		if term.PayoutAccountId > 0 {
+			accountId = term.PayoutAccountId
+		}
+
+		transaction := &models.Transaction{
+			Uid:               term.Uid,
+			Type:              models.TRANSACTION_DB_TYPE_INCOME,
+			CategoryId:        term.InterestCategoryId,
+			TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(payoutTime.Unix()),
+			TimezoneUtcOffset: term.TimezoneUtcOffset,
+			AccountId:         accountId,
+			Amount:            payout.Amount,
+			CreatedIp:         "127.0.0.1",
+			ScheduledCreated:  true,
+		}
+
+		err = s.transactionService.createTransaction(c, transaction, nil, nil, updateLastPayoutDate)
+
+		if err != nil {
+			return err
+		}
+
+		log.Infof(c, "[fixed_incomes.postInterest] fixed income term \"account_id:%d\" has created a new interest transaction \"id:%d\"", term.AccountId, transaction.TransactionId)
+	} else {
+		err := s.UserDataDB(term.Uid).DoTransaction(c, updateLastPayoutDate)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	term.LastPayoutDate = payout.PayoutDate
+
+	return nil
+}
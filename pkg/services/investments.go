@@ -1,8 +1,11 @@
 package services
 
 import (
+	"strings"
 	"time"
 
+	"xorm.io/xorm"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -41,12 +44,12 @@ func (s *InvestmentService) GetAllInvestments(c core.Context, uid int64) ([]*mod
 			Investment: investment,
 		}
 		
-		stockPrice, err := s.stockPriceService.GetStockPrice(c, investment.TickerSymbol)
+		stockPrice, err := s.stockPriceService.GetAssetPrice(c, investment.AssetType, investment.TickerSymbol, investment.Currency)
 		if err != nil {
 			log.Warnf(c, "[investments.GetAllInvestments] failed to get stock price for %s, error %s", investment.TickerSymbol, err.Error())
 			investmentWithPrice.CurrentPrice = 0
 		} else {
-			investmentWithPrice.CurrentPrice = stockPrice.CurrentPrice
+			investmentWithPrice.CurrentPrice = models.ConvertInvestmentPrice(stockPrice.CurrentPrice, stockPrice.PricePrecision, investment.PricePrecision)
 			investmentWithPrice.LastPriceUpdate = stockPrice.LastUpdatedTime
 		}
 
@@ -86,12 +89,12 @@ func (s *InvestmentService) GetInvestment(c core.Context, uid int64, investmentI
 		Investment: &investment,
 	}
 	
-	stockPrice, err := s.stockPriceService.GetStockPrice(c, investment.TickerSymbol)
+	stockPrice, err := s.stockPriceService.GetAssetPrice(c, investment.AssetType, investment.TickerSymbol, investment.Currency)
 	if err != nil {
 		log.Warnf(c, "[investments.GetInvestment] failed to get stock price for %s, error %s", investment.TickerSymbol, err.Error())
 		result.CurrentPrice = 0
 	} else {
-		result.CurrentPrice = stockPrice.CurrentPrice
+		result.CurrentPrice = models.ConvertInvestmentPrice(stockPrice.CurrentPrice, stockPrice.PricePrecision, investment.PricePrecision)
 		result.LastPriceUpdate = stockPrice.LastUpdatedTime
 	}
 
@@ -111,12 +114,9 @@ func (s *InvestmentService) CreateInvestment(c core.Context, investment *models.
 		return errs.ErrTickerSymbolIsEmpty
 	}
 
-	if investment.SharesOwned <= 0 {
-		return errs.ErrInvalidSharesAmount
-	}
-
-	if investment.AvgCostPerShare <= 0 {
-		return errs.ErrInvalidCostPerShare
+	err := s.normalizeAndValidateInvestment(investment)
+	if err != nil {
+		return err
 	}
 
 	// Check if investment already exists for this user and ticker
@@ -133,7 +133,7 @@ func (s *InvestmentService) CreateInvestment(c core.Context, investment *models.
 	defer sess.Close()
 
 	investment.InvestmentId = s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT)
-	investment.TotalInvested = s.convertPriceFromFloat64(float64(investment.AvgCostPerShare)/100 * investment.SharesOwned)
+	investment.TotalInvested = models.GetInvestmentAmount(investment.SharesOwned, investment.AvgCostPerShare, investment.PricePrecision)
 	investment.CreatedUnixTime = time.Now().Unix()
 	investment.UpdatedUnixTime = time.Now().Unix()
 	investment.Deleted = false
@@ -152,12 +152,9 @@ func (s *InvestmentService) UpdateInvestment(c core.Context, investment *models.
 		return errs.ErrInvestmentIdInvalid
 	}
 
-	if investment.SharesOwned <= 0 {
-		return errs.ErrInvalidSharesAmount
-	}
-
-	if investment.AvgCostPerShare <= 0 {
-		return errs.ErrInvalidCostPerShare
+	err := s.normalizeAndValidateInvestment(investment)
+	if err != nil {
+		return err
 	}
 
 	sess := s.UserDataDB(investment.Uid).NewSession(c)
@@ -174,10 +171,10 @@ func (s *InvestmentService) UpdateInvestment(c core.Context, investment *models.
 		return errs.ErrInvestmentNotFound
 	}
 
-	investment.TotalInvested = s.convertPriceFromFloat64(float64(investment.AvgCostPerShare)/100 * investment.SharesOwned)
+	investment.TotalInvested = models.GetInvestmentAmount(investment.SharesOwned, investment.AvgCostPerShare, investment.PricePrecision)
 	investment.UpdatedUnixTime = time.Now().Unix()
 
-	_, err = sess.Where("uid=? AND investment_id=?", investment.Uid, investment.InvestmentId).Update(investment)
+	_, err = sess.Where("uid=? AND investment_id=?", investment.Uid, investment.InvestmentId).MustCols("wallet_address").Update(investment)
 	return err
 }
 
@@ -253,43 +250,78 @@ func (s *InvestmentService) AddInvestmentTransaction(c core.Context, transaction
 		return errs.ErrInvestmentNotFound
 	}
 
-	// Create transaction record
-	transaction.TransactionId = s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT_TRANSACTION)
-	transaction.TotalAmount = s.convertPriceFromFloat64(float64(transaction.PricePerShare)/100 * transaction.Shares)
-	transaction.CreatedUnixTime = time.Now().Unix()
-	transaction.UpdatedUnixTime = time.Now().Unix()
+	err = s.applyInvestmentTransaction(sess, investment, transaction)
+	if err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// CreateInvestmentWithTransaction creates a new investment holding together with its opening buy transaction
+func (s *InvestmentService) CreateInvestmentWithTransaction(c core.Context, investment *models.Investment, transaction *models.InvestmentTransaction) error {
+	if investment.Uid <= 0 || transaction.Uid != investment.Uid {
+		return errs.ErrUserIdInvalid
+	}
+
+	if investment.TickerSymbol == "" || transaction.TickerSymbol != investment.TickerSymbol {
+		return errs.ErrTickerSymbolIsEmpty
+	}
+
+	if transaction.Type != models.INVESTMENT_TRANSACTION_TYPE_BUY {
+		return errs.ErrInvestmentNotFound
+	}
+
+	if transaction.Shares <= 0 {
+		return errs.ErrInvalidSharesAmount
+	}
+
+	if transaction.PricePerShare <= 0 {
+		return errs.ErrInvalidPricePerShare
+	}
+
+	investment.SharesOwned = transaction.Shares
+	investment.AvgCostPerShare = transaction.PricePerShare
 
-	_, err = sess.Insert(transaction)
+	err := s.normalizeAndValidateInvestment(investment)
 	if err != nil {
 		return err
 	}
 
-	// Update investment totals
-	if transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_BUY {
-		// Buy: increase shares and recalculate average cost
-		newTotalShares := investment.SharesOwned + transaction.Shares
-		newTotalInvested := investment.TotalInvested + transaction.TotalAmount
-		newAvgCost := s.convertPriceFromFloat64(float64(newTotalInvested) / 100 / newTotalShares)
+	// the holding starts empty and the opening transaction sets its shares and cost basis
+	investment.SharesOwned = 0
+	investment.AvgCostPerShare = 0
 
-		investment.SharesOwned = newTotalShares
-		investment.TotalInvested = newTotalInvested
-		investment.AvgCostPerShare = newAvgCost
-	} else {
-		// Sell: decrease shares, keep same average cost
-		if investment.SharesOwned < transaction.Shares {
-			return errs.ErrInsufficientShares
-		}
+	sess := s.UserDataDB(investment.Uid).NewSession(c)
+	defer sess.Close()
 
-		newTotalShares := investment.SharesOwned - transaction.Shares
-		newTotalInvested := s.convertPriceFromFloat64(float64(investment.AvgCostPerShare)/100 * newTotalShares)
+	err = sess.Begin()
+	if err != nil {
+		return err
+	}
+	defer sess.Rollback()
 
-		investment.SharesOwned = newTotalShares
-		investment.TotalInvested = newTotalInvested
+	exists, err := sess.Where("uid=? AND ticker_symbol=? AND deleted=?", investment.Uid, investment.TickerSymbol, false).Exist(&models.Investment{})
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return errs.ErrInvestmentAlreadyExists
 	}
 
+	investment.InvestmentId = s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT)
+	investment.TotalInvested = 0
+	investment.CreatedUnixTime = time.Now().Unix()
 	investment.UpdatedUnixTime = time.Now().Unix()
+	investment.Deleted = false
 
-	_, err = sess.Where("uid=? AND investment_id=?", investment.Uid, investment.InvestmentId).Update(investment)
+	_, err = sess.Insert(investment)
+	if err != nil {
+		return err
+	}
+
+	err = s.applyInvestmentTransaction(sess, investment, transaction)
 	if err != nil {
 		return err
 	}
@@ -324,6 +356,45 @@ func (s *InvestmentService) GetPortfolioSummary(c core.Context, uid int64) (*mod
 	return summary, nil
 }
 
+// GetInvestmentByTickerSymbol returns the investment holding of the specified ticker symbol without current price
+func (s *InvestmentService) GetInvestmentByTickerSymbol(c core.Context, uid int64, tickerSymbol string) (*models.Investment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if tickerSymbol == "" {
+		return nil, errs.ErrTickerSymbolIsEmpty
+	}
+
+	return s.getInvestmentByTicker(c, uid, tickerSymbol)
+}
+
+// GetInvestmentsByInvestmentIds returns the investment holding models of the specified investment ids without current price
+func (s *InvestmentService) GetInvestmentsByInvestmentIds(c core.Context, uid int64, investmentIds []int64) (map[int64]*models.Investment, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if investmentIds == nil {
+		return nil, errs.ErrInvestmentIdInvalid
+	}
+
+	var investments []*models.Investment
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("investment_id", investmentIds).Find(&investments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	investmentMap := make(map[int64]*models.Investment, len(investments))
+
+	for i := 0; i < len(investments); i++ {
+		investmentMap[investments[i].InvestmentId] = investments[i]
+	}
+
+	return investmentMap, nil
+}
+
 // Helper methods
 
 func (s *InvestmentService) getInvestmentByTicker(c core.Context, uid int64, tickerSymbol string) (*models.Investment, error) {
@@ -343,9 +414,118 @@ func (s *InvestmentService) getInvestmentByTicker(c core.Context, uid int64, tic
 	return &investment, nil
 }
 
+func (s *InvestmentService) applyInvestmentTransaction(sess *xorm.Session, investment *models.Investment, transaction *models.InvestmentTransaction) error {
+	// The price per share of the transaction is in the price precision of the holding
+	transaction.Shares = models.RoundInvestmentQuantity(transaction.Shares, investment.QuantityPrecision)
+	transaction.PricePrecision = investment.PricePrecision
+
+	if transaction.Shares <= 0 {
+		return errs.ErrInvalidSharesAmount
+	}
+
+	// Create transaction record
+	transaction.TransactionId = s.GenerateUuid(uuid.UUID_TYPE_INVESTMENT_TRANSACTION)
+	transaction.TotalAmount = models.GetInvestmentAmount(transaction.Shares, transaction.PricePerShare, transaction.PricePrecision)
+	transaction.CreatedUnixTime = time.Now().Unix()
+	transaction.UpdatedUnixTime = time.Now().Unix()
+
+	_, err := sess.Insert(transaction)
+	if err != nil {
+		return err
+	}
+
+	// Update investment totals
+	if transaction.Type == models.INVESTMENT_TRANSACTION_TYPE_BUY {
+		// Buy: increase shares and recalculate average cost
+		newTotalShares := models.RoundInvestmentQuantity(investment.SharesOwned+transaction.Shares, investment.QuantityPrecision)
+		newTotalInvested := investment.TotalInvested + transaction.TotalAmount
+		newAvgCost := models.GetInvestmentPriceFromAmount(newTotalInvested, newTotalShares, investment.PricePrecision)
+
+		investment.SharesOwned = newTotalShares
+		investment.TotalInvested = newTotalInvested
+		investment.AvgCostPerShare = newAvgCost
+	} else {
+		// Sell: decrease shares, keep same average cost
+		newTotalShares := models.RoundInvestmentQuantity(investment.SharesOwned-transaction.Shares, investment.QuantityPrecision)
+
+		if newTotalShares < 0 {
+			return errs.ErrInsufficientShares
+		}
+
+		newTotalInvested := models.GetInvestmentAmount(newTotalShares, investment.AvgCostPerShare, investment.PricePrecision)
+
+		investment.SharesOwned = newTotalShares
+		investment.TotalInvested = newTotalInvested
+	}
+
+	investment.UpdatedUnixTime = time.Now().Unix()
+
+	// selling all the shares updates the shares and total invested to zero, so the columns must be specified explicitly
+	_, err = sess.Where("uid=? AND investment_id=?", investment.Uid, investment.InvestmentId).Cols("shares_owned", "avg_cost_per_share", "total_invested", "updated_unix_time").Update(investment)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *InvestmentService) normalizeAndValidateInvestment(investment *models.Investment) error {
+	if investment.AssetType == 0 {
+		investment.AssetType = models.INVESTMENT_ASSET_TYPE_STOCK
+	}
+
+	err := investment.AssetType.Validate()
+	if err != nil {
+		return err
+	}
+
+	if investment.QuantityPrecision == models.InvestmentDefaultPrecision {
+		investment.QuantityPrecision = investment.AssetType.DefaultQuantityPrecision()
+	}
+
+	if investment.QuantityPrecision < 0 || investment.QuantityPrecision > models.MaxInvestmentQuantityPrecision {
+		return errs.ErrInvestmentQuantityPrecisionInvalid
+	}
+
+	if investment.PricePrecision == models.InvestmentDefaultPrecision {
+		investment.PricePrecision = investment.AssetType.DefaultPricePrecision()
+	}
+
+	if investment.PricePrecision < 0 || investment.PricePrecision > models.MaxInvestmentPricePrecision {
+		return errs.ErrInvestmentPricePrecisionInvalid
+	}
+
+	if investment.AssetType == models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL && !models.IsPreciousMetalTickerSymbol(investment.TickerSymbol) {
+		return errs.ErrPreciousMetalNotSupported
+	}
+
+	// the market quotes of precious metals are priced in USD, which could not be applied to the holdings in other currencies
+	if investment.AssetType == models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL && investment.Currency != models.PreciousMetalPriceCurrency {
+		return errs.ErrPreciousMetalCurrencyNotSupported
+	}
+
+	investment.WalletAddress = strings.TrimSpace(investment.WalletAddress)
+
+	if investment.WalletAddress != "" && investment.AssetType != models.INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY {
+		return errs.ErrInvestmentWalletAddressInvalid
+	}
+
+	investment.SharesOwned = models.RoundInvestmentQuantity(investment.SharesOwned, investment.QuantityPrecision)
+
+	if investment.SharesOwned <= 0 {
+		return errs.ErrInvalidSharesAmount
+	}
+
+	if investment.AvgCostPerShare <= 0 {
+		return errs.ErrInvalidCostPerShare
+	}
+
+	return nil
+}
+
 func (s *InvestmentService) calculateInvestmentMetrics(investment *models.InvestmentWithCurrentPrice) {
 	if investment.CurrentPrice > 0 {
-		investment.CurrentValue = s.convertPriceFromFloat64(float64(investment.CurrentPrice)/100 * investment.Investment.SharesOwned)
+		investment.CurrentValue = models.GetInvestmentAmount(investment.Investment.SharesOwned, investment.CurrentPrice, investment.Investment.PricePrecision)
 		investment.GainLoss = investment.CurrentValue - investment.Investment.TotalInvested
 		
 		if investment.Investment.TotalInvested > 0 {
@@ -354,10 +534,6 @@ func (s *InvestmentService) calculateInvestmentMetrics(investment *models.Invest
 	}
 }
 
-func (s *InvestmentService) convertPriceFromFloat64(price float64) int64 {
-	return int64(price * 100)
-}
-
 // NewInvestmentService returns new investment service
 func NewInvestmentService(container *datastore.DataStoreContainer, uuidContainer *uuid.UuidContainer, stockPriceService *StockPriceService) *InvestmentService {
 	return &InvestmentService{
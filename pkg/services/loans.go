@@ -0,0 +1,224 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// LoanService represents loan term service
+type LoanService struct {
+	ServiceUsingDB
+	transactionService *TransactionService
+}
+
+// Initialize a loan term service singleton instance
+var (
+	Loans = &LoanService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		transactionService: Transactions,
+	}
+)
+
+// GetLoanTermByAccountId returns the loan term model of the specified account
+func (s *LoanService) GetLoanTermByAccountId(c core.Context, uid int64, accountId int64) (*models.LoanTerm, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return nil, errs.ErrAccountIdInvalid
+	}
+
+	term := &models.LoanTerm{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Get(term)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrLoanTermNotFound
+	}
+
+	return term, nil
+}
+
+// SetLoanTerm saves the loan term of the account to database
+func (s *LoanService) SetLoanTerm(c core.Context, term *models.LoanTerm) error {
+	if term.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	term.Deleted = false
+	term.UpdatedUnixTime = now
+	term.DeletedUnixTime = 0
+
+	return s.UserDataDB(term.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		oldTerm := &models.LoanTerm{}
+		has, err := sess.ID(term.AccountId).Where("uid=?", term.Uid).Get(oldTerm)
+
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			term.CreatedUnixTime = now
+			_, err = sess.Insert(term)
+			return err
+		}
+
+		term.CreatedUnixTime = oldTerm.CreatedUnixTime
+		_, err = sess.ID(term.AccountId).Where("uid=?", term.Uid).AllCols().Update(term)
+
+		return err
+	})
+}
+
+// DeleteLoanTerm deletes the loan term of the account from database
+func (s *LoanService) DeleteLoanTerm(c core.Context, uid int64, accountId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.LoanTerm{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND account_id=?", uid, false, accountId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrLoanTermNotFound
+		}
+
+		return err
+	})
+}
+
+// CreateLoanPayment splits the payment into principal and interest according to the outstanding balance of the loan account and the interest accrued since the last payment,
+// then saves a transfer transaction from the source account to the loan account for the principal and an expense transaction of the source account for the interest
+func (s *LoanService) CreateLoanPayment(c core.Context, term *models.LoanTerm, loanAccount *models.Account, sourceAccount *models.Account, transferCategoryId int64, amount int64, transactionUnixTime int64, utcOffset int16, comment string, clientIp string) (*models.Transaction, *models.Transaction, error) {
+	if term.Uid <= 0 {
+		return nil, nil, errs.ErrUserIdInvalid
+	}
+
+	if loanAccount.AccountId != term.AccountId || sourceAccount.AccountId == loanAccount.AccountId {
+		return nil, nil, errs.ErrLoanPaymentSourceAccountInvalid
+	}
+
+	if sourceAccount.Currency != loanAccount.Currency {
+		return nil, nil, errs.ErrLoanPaymentAccountCurrencyInvalid
+	}
+
+	// check the transfer category and the accounts before calculating the principal and the interest, even if the payment only contains interest
+	err := s.transactionService.CheckNewTransactionAccountsAndCategory(c, &models.Transaction{
+		Uid:                  term.Uid,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		CategoryId:           transferCategoryId,
+		AccountId:            sourceAccount.AccountId,
+		RelatedAccountId:     loanAccount.AccountId,
+		Amount:               amount,
+		RelatedAccountAmount: amount,
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the interest is calculated according to the outstanding balance just before the payment time instead of the current balance
+	loanBalance, err := s.transactionService.GetAccountBalanceByMaxTime(c, term.Uid, loanAccount.AccountId, utils.GetMinTransactionTimeFromUnixTime(transactionUnixTime)-1)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the interest is accrued from the last principal payment (or the start date of the loan if there is no payment yet) to the payment date
+	lastPaymentDate, err := s.getLastLoanPaymentDate(c, term, utils.GetMinTransactionTimeFromUnixTime(transactionUnixTime))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paymentDate := utils.FormatUnixTimeToLongDate(transactionUnixTime, time.FixedZone("Transaction Timezone", int(utcOffset)*60))
+	principal, interest, err := term.SplitPayment(amount, -loanBalance, lastPaymentDate, paymentDate)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactions := make([]*models.Transaction, 0, 2)
+
+	var interestTransaction *models.Transaction
+	var transferTransaction *models.Transaction
+
+	if interest > 0 {
+		interestTransaction = &models.Transaction{
+			Uid:               term.Uid,
+			Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+			CategoryId:        term.InterestCategoryId,
+			TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionUnixTime),
+			TimezoneUtcOffset: utcOffset,
+			AccountId:         sourceAccount.AccountId,
+			Amount:            interest,
+			Comment:           comment,
+			CreatedIp:         clientIp,
+		}
+
+		transactions = append(transactions, interestTransaction)
+	}
+
+	if principal > 0 {
+		transferTransaction = &models.Transaction{
+			Uid:                  term.Uid,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			CategoryId:           transferCategoryId,
+			TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(transactionUnixTime),
+			TimezoneUtcOffset:    utcOffset,
+			AccountId:            sourceAccount.AccountId,
+			Amount:               principal,
+			RelatedAccountId:     loanAccount.AccountId,
+			RelatedAccountAmount: principal,
+			Comment:              comment,
+			CreatedIp:            clientIp,
+		}
+
+		transactions = append(transactions, transferTransaction)
+	}
+
+	err = s.transactionService.BatchCreateTransactions(c, term.Uid, transactions, nil, nil, nil)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return transferTransaction, interestTransaction, nil
+}
+
+func (s *LoanService) getLastLoanPaymentDate(c core.Context, term *models.LoanTerm, maxTransactionTime int64) (string, error) {
+	lastPaymentTransaction := &models.Transaction{}
+	has, err := s.UserDataDB(term.Uid).NewSession(c).Where("uid=? AND deleted=? AND account_id=? AND type=? AND transaction_time<?", term.Uid, false, term.AccountId, models.TRANSACTION_DB_TYPE_TRANSFER_IN, maxTransactionTime).OrderBy("transaction_time desc").Limit(1).Get(lastPaymentTransaction)
+
+	if err != nil {
+		return "", err
+	} else if !has {
+		return term.StartDate, nil
+	}
+
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(lastPaymentTransaction.TimezoneUtcOffset)*60)
+
+	return utils.FormatUnixTimeToLongDate(utils.GetUnixTimeFromTransactionTime(lastPaymentTransaction.TransactionTime), transactionTimeZone), nil
+}
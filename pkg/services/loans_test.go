@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func insertTestLoanAccounts(t *testing.T, c core.Context) (*models.LoanTerm, *models.Account, *models.Account) {
+	assert.Nil(t, datastore.Container.UserDataStore.SyncStructs(new(models.TransactionCategory)))
+
+	now := time.Now().Unix()
+	sess := Loans.UserDataDB(1).NewSession(c)
+
+	loanAccount := &models.Account{AccountId: 101, Uid: 1, Category: models.ACCOUNT_CATEGORY_DEBT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Loan", Currency: "USD", Balance: -1000000, CreatedUnixTime: now, UpdatedUnixTime: now}
+	sourceAccount := &models.Account{AccountId: 102, Uid: 1, Category: models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT, Type: models.ACCOUNT_TYPE_SINGLE_ACCOUNT, Name: "Checking", Currency: "USD", Balance: 1000000, CreatedUnixTime: now, UpdatedUnixTime: now}
+	_, err := sess.Insert([]*models.Account{loanAccount, sourceAccount})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert([]*models.TransactionCategory{
+		{CategoryId: 201, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: models.LevelOneTransactionCategoryParentId, Name: "Finance", CreatedUnixTime: now, UpdatedUnixTime: now},
+		{CategoryId: 202, Uid: 1, Type: models.CATEGORY_TYPE_EXPENSE, ParentCategoryId: 201, Name: "Interest", CreatedUnixTime: now, UpdatedUnixTime: now},
+		{CategoryId: 203, Uid: 1, Type: models.CATEGORY_TYPE_TRANSFER, ParentCategoryId: models.LevelOneTransactionCategoryParentId, Name: "Transfer", CreatedUnixTime: now, UpdatedUnixTime: now},
+		{CategoryId: 204, Uid: 1, Type: models.CATEGORY_TYPE_TRANSFER, ParentCategoryId: 203, Name: "Loan Payment", CreatedUnixTime: now, UpdatedUnixTime: now},
+	})
+	assert.Nil(t, err)
+
+	// the initial balance of the loan account is at the start date of the loan
+	startTime, err := utils.ParseFromLongDateFirstTime("2024-01-15", 0)
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{
+		TransactionId:        1001,
+		Uid:                  1,
+		Type:                 models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TransactionTime:      utils.GetMinTransactionTimeFromUnixTime(startTime.Unix()),
+		AccountId:            101,
+		RelatedAccountId:     101,
+		RelatedAccountAmount: -1000000,
+		CreatedUnixTime:      now,
+		UpdatedUnixTime:      now,
+	})
+	assert.Nil(t, err)
+
+	term := &models.LoanTerm{
+		AccountId:          101,
+		Uid:                1,
+		Principal:          1000000,
+		InterestRate:       120000,
+		TermMonths:         12,
+		StartDate:          "2024-01-15",
+		PaymentDay:         15,
+		InterestCategoryId: 202,
+	}
+
+	return term, loanAccount, sourceAccount
+}
+
+func createTestLoanPayment(t *testing.T, c core.Context, term *models.LoanTerm, loanAccount *models.Account, sourceAccount *models.Account, amount int64, dateTime string) (int64, int64) {
+	paymentTime, err := utils.ParseFromLongDateTime(dateTime, 0)
+	assert.Nil(t, err)
+
+	transferTransaction, interestTransaction, err := Loans.CreateLoanPayment(c, term, loanAccount, sourceAccount, 204, amount, paymentTime.Unix(), 0, "", "127.0.0.1")
+	assert.Nil(t, err)
+
+	principal := int64(0)
+	interest := int64(0)
+
+	if transferTransaction != nil {
+		principal = transferTransaction.Amount
+	}
+
+	if interestTransaction != nil {
+		interest = interestTransaction.Amount
+	}
+
+	return principal, interest
+}
+
+func TestLoanServiceCreateLoanPayment_LatePayment(t *testing.T) {
+	c := initializeTransactionServiceTestDataStore(t)
+	term, loanAccount, sourceAccount := insertTestLoanAccounts(t, c)
+
+	principal, interest := createTestLoanPayment(t, c, term, loanAccount, sourceAccount, 50000, "2024-03-15 12:00:00")
+	assert.Equal(t, int64(30000), principal)
+	assert.Equal(t, int64(20000), interest)
+}
+
+func TestLoanServiceCreateLoanPayment_MultiplePaymentsInSamePeriod(t *testing.T) {
+	c := initializeTransactionServiceTestDataStore(t)
+	term, loanAccount, sourceAccount := insertTestLoanAccounts(t, c)
+
+	principal, interest := createTestLoanPayment(t, c, term, loanAccount, sourceAccount, 50000, "2024-02-15 09:00:00")
+	assert.Equal(t, int64(40000), principal)
+	assert.Equal(t, int64(10000), interest)
+
+	principal, interest = createTestLoanPayment(t, c, term, loanAccount, sourceAccount, 50000, "2024-02-15 18:00:00")
+	assert.Equal(t, int64(50000), principal)
+	assert.Equal(t, int64(0), interest)
+
+	// the interest of the half month after the last payment is based on the balance after two payments
+	principal, interest = createTestLoanPayment(t, c, term, loanAccount, sourceAccount, 50000, "2024-03-01 12:00:00")
+	assert.Equal(t, int64(45293), principal)
+	assert.Equal(t, int64(4707), interest)
+}
@@ -0,0 +1,512 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// PayeeService represents payee service
+type PayeeService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a payee service singleton instance
+var (
+	Payees = &PayeeService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllPayeesByUid returns all payee models of user
+func (s *PayeeService) GetAllPayeesByUid(c core.Context, uid int64) ([]*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var payees []*models.Payee
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&payees)
+
+	return payees, err
+}
+
+// GetPayeeByPayeeId returns a payee model according to payee id
+func (s *PayeeService) GetPayeeByPayeeId(c core.Context, uid int64, payeeId int64) (*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if payeeId <= 0 {
+		return nil, errs.ErrPayeeIdInvalid
+	}
+
+	payee := &models.Payee{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(payeeId).Where("uid=? AND deleted=?", uid, false).Get(payee)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrPayeeNotFound
+	}
+
+	return payee, nil
+}
+
+// GetPayeesByPayeeIds returns payee models according to payee ids
+func (s *PayeeService) GetPayeesByPayeeIds(c core.Context, uid int64, payeeIds []int64) (map[int64]*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if payeeIds == nil {
+		return nil, errs.ErrPayeeIdInvalid
+	}
+
+	var payees []*models.Payee
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("payee_id", payeeIds).Find(&payees)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payeeMap := s.GetPayeeMapByList(payees)
+	return payeeMap, err
+}
+
+// CreatePayee saves a new payee model to database
+func (s *PayeeService) CreatePayee(c core.Context, payee *models.Payee) error {
+	if payee.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if payee.Name == "" {
+		return errs.ErrPayeeNameIsEmpty
+	}
+
+	payee.PayeeId = s.GenerateUuid(uuid.UUID_TYPE_PAYEE)
+
+	if payee.PayeeId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	payee.Deleted = false
+	payee.CreatedUnixTime = time.Now().Unix()
+	payee.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(payee.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := s.existsPayeeNameOrAlias(sess, payee.Uid, payee, nil)
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeNameAlreadyExists
+		}
+
+		_, err = sess.Insert(payee)
+		return err
+	})
+}
+
+// CreatePayeesByNames returns the payees whose name or alias matches the given names, and saves new payee models for the names which do not match any existed payee
+func (s *PayeeService) CreatePayeesByNames(c core.Context, uid int64, names []string) (map[string]*models.Payee, error) {
+	payeeNameMap, newPayees, err := s.PreparePayeesByNames(c, uid, names)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(newPayees) < 1 {
+		return payeeNameMap, nil
+	}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		return s.insertPayees(sess, newPayees)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return payeeNameMap, nil
+}
+
+// PreparePayeesByNames returns the payees whose name or alias matches the given names, and the new payee models (not saved to database yet) for the names which do not match any existed payee
+func (s *PayeeService) PreparePayeesByNames(c core.Context, uid int64, names []string) (map[string]*models.Payee, []*models.Payee, error) {
+	if uid <= 0 {
+		return nil, nil, errs.ErrUserIdInvalid
+	}
+
+	existedPayees, err := s.GetAllPayeesByUid(c, uid)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payeeNameMap := make(map[string]*models.Payee, len(names))
+	newPayees := make([]*models.Payee, 0, len(names))
+
+	for i := 0; i < len(names); i++ {
+		name := strings.TrimSpace(names[i])
+
+		if name == "" {
+			continue
+		}
+
+		if _, exists := payeeNameMap[name]; exists {
+			continue
+		}
+
+		payee := s.GetPayeeByName(existedPayees, name)
+
+		if payee == nil {
+			payee = s.GetPayeeByName(newPayees, name)
+		}
+
+		if payee == nil {
+			payee = &models.Payee{
+				Uid:  uid,
+				Name: name,
+			}
+
+			newPayees = append(newPayees, payee)
+		}
+
+		payeeNameMap[name] = payee
+	}
+
+	if len(newPayees) < 1 {
+		return payeeNameMap, newPayees, nil
+	}
+
+	payeeUuids := s.GenerateUuids(uuid.UUID_TYPE_PAYEE, uint16(len(newPayees)))
+
+	if len(payeeUuids) < len(newPayees) {
+		return nil, nil, errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+
+	for i := 0; i < len(newPayees); i++ {
+		payee := newPayees[i]
+		payee.PayeeId = payeeUuids[i]
+		payee.Deleted = false
+		payee.CreatedUnixTime = now
+		payee.UpdatedUnixTime = now
+	}
+
+	return payeeNameMap, newPayees, nil
+}
+
+// ModifyPayee saves an existed payee model to database
+func (s *PayeeService) ModifyPayee(c core.Context, payee *models.Payee) error {
+	if payee.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if payee.Name == "" {
+		return errs.ErrPayeeNameIsEmpty
+	}
+
+	payee.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(payee.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := s.existsPayeeNameOrAlias(sess, payee.Uid, payee, []int64{payee.PayeeId})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeNameAlreadyExists
+		}
+
+		updatedRows, err := sess.ID(payee.PayeeId).Cols("name", "aliases", "updated_unix_time").Where("uid=? AND deleted=?", payee.Uid, false).Update(payee)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return err
+	})
+}
+
+// HidePayee updates hidden field of given payees
+func (s *PayeeService) HidePayee(c core.Context, uid int64, ids []int64, hidden bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Payee{
+		Hidden:          hidden,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("hidden", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("payee_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return err
+	})
+}
+
+// MergePayees moves all transactions of the source payees to the target payee and deletes the source payees,
+// the names and aliases of the source payees would be added to the aliases of the target payee if keepAliases is true
+func (s *PayeeService) MergePayees(c core.Context, uid int64, targetPayeeId int64, fromPayeeIds []int64, keepAliases bool) (*models.Payee, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	fromPayeeIds = utils.ToUniqueInt64Slice(fromPayeeIds)
+
+	for i := 0; i < len(fromPayeeIds); i++ {
+		if fromPayeeIds[i] == targetPayeeId {
+			return nil, errs.ErrCannotMergePayeeIntoItself
+		}
+	}
+
+	now := time.Now().Unix()
+	targetPayee := &models.Payee{}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		has, err := sess.ID(targetPayeeId).Where("uid=? AND deleted=?", uid, false).Get(targetPayee)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrPayeeNotFound
+		}
+
+		var fromPayees []*models.Payee
+		err = sess.Where("uid=? AND deleted=?", uid, false).In("payee_id", fromPayeeIds).Find(&fromPayees)
+
+		if err != nil {
+			return err
+		} else if len(fromPayees) != len(fromPayeeIds) {
+			return errs.ErrPayeeNotFound
+		}
+
+		if keepAliases {
+			aliases := targetPayee.GetAliases()
+
+			for i := 0; i < len(fromPayees); i++ {
+				aliases = append(aliases, fromPayees[i].Name)
+				aliases = append(aliases, fromPayees[i].GetAliases()...)
+			}
+
+			err = targetPayee.SetAliases(aliases)
+
+			if err != nil {
+				return err
+			}
+
+			excludePayeeIds := append([]int64{targetPayee.PayeeId}, fromPayeeIds...)
+			exists, err := s.existsPayeeNameOrAlias(sess, uid, targetPayee, excludePayeeIds)
+
+			if err != nil {
+				return err
+			} else if exists {
+				return errs.ErrPayeeNameAlreadyExists
+			}
+
+			targetPayee.UpdatedUnixTime = now
+			_, err = sess.ID(targetPayee.PayeeId).Cols("aliases", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(targetPayee)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		transactionUpdateModel := &models.Transaction{
+			PayeeId:         targetPayee.PayeeId,
+			UpdatedUnixTime: now,
+		}
+
+		_, err = sess.Cols("payee_id", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("payee_id", fromPayeeIds).Update(transactionUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		payeeDeleteModel := &models.Payee{
+			Deleted:         true,
+			DeletedUnixTime: now,
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("payee_id", fromPayeeIds).Update(payeeDeleteModel)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return targetPayee, nil
+}
+
+// DeletePayee deletes an existed payee from database
+func (s *PayeeService) DeletePayee(c core.Context, uid int64, payeeId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Payee{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "deleted", "payee_id").Where("uid=? AND deleted=? AND payee_id=?", uid, false, payeeId).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeInUseCannotBeDeleted
+		}
+
+		deletedRows, err := sess.ID(payeeId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrPayeeNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllPayees deletes all existed payees from database
+func (s *PayeeService) DeleteAllPayees(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.Payee{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid", "deleted", "payee_id").Where("uid=? AND deleted=? AND payee_id<>?", uid, false, 0).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrPayeeInUseCannotBeDeleted
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		return err
+	})
+}
+
+// ExistsPayeeName returns whether the given payee name equals to the name or any alias of other payees (case-insensitive)
+func (s *PayeeService) ExistsPayeeName(c core.Context, uid int64, name string, excludePayeeId int64) (bool, error) {
+	if name == "" {
+		return false, errs.ErrPayeeNameIsEmpty
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+	defer sess.Close()
+
+	return s.existsPayeeNameOrAlias(sess, uid, &models.Payee{Name: name}, []int64{excludePayeeId})
+}
+
+// GetPayeeByName returns the first payee whose name or alias matches the given name in the payee list
+func (s *PayeeService) GetPayeeByName(payees []*models.Payee, name string) *models.Payee {
+	for i := 0; i < len(payees); i++ {
+		if payees[i].IsNameMatched(name) {
+			return payees[i]
+		}
+	}
+
+	return nil
+}
+
+// GetPayeeMapByList returns a payee map by a list
+func (s *PayeeService) GetPayeeMapByList(payees []*models.Payee) map[int64]*models.Payee {
+	payeeMap := make(map[int64]*models.Payee)
+
+	for i := 0; i < len(payees); i++ {
+		payee := payees[i]
+		payeeMap[payee.PayeeId] = payee
+	}
+
+	return payeeMap
+}
+
+// GetPayeeIds converts a comma-separated string of payee ids into a slice of int64
+func (s *PayeeService) GetPayeeIds(payeeIds string) ([]int64, error) {
+	if payeeIds == "" || payeeIds == "0" {
+		return nil, nil
+	}
+
+	requestPayeeIds, err := utils.StringArrayToInt64Array(strings.Split(payeeIds, ","))
+
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrPayeeIdInvalid)
+	}
+
+	return requestPayeeIds, nil
+}
+
+func (s *PayeeService) insertPayees(sess *xorm.Session, newPayees []*models.Payee) error {
+	for i := 0; i < len(newPayees); i++ {
+		_, err := sess.Insert(newPayees[i])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *PayeeService) existsPayeeNameOrAlias(sess *xorm.Session, uid int64, payee *models.Payee, excludePayeeIds []int64) (bool, error) {
+	var payees []*models.Payee
+	err := sess.Cols("payee_id", "name", "aliases").Where("uid=? AND deleted=?", uid, false).Find(&payees)
+
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; i < len(payees); i++ {
+		if slices.Contains(excludePayeeIds, payees[i].PayeeId) {
+			continue
+		}
+
+		if payee.IsNameOrAliasConflicted(payees[i]) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
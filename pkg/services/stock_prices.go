@@ -7,15 +7,18 @@ import (
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/cryptoprices"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // StockPriceService represents stock price service
 type StockPriceService struct {
 	ServiceUsingDB
+	ServiceUsingConfig
 	container *datastore.DataStoreContainer
 }
 
@@ -56,33 +59,63 @@ func (s *StockPriceService) GetStockPrice(c core.Context, tickerSymbol string) (
 		return nil, errs.ErrTickerSymbolIsEmpty
 	}
 
-	// First check cache
-	stockPrice, err := s.getStockPriceFromCache(c, tickerSymbol)
-	if err == nil && stockPrice != nil {
-		// Check if cache is still fresh (less than 1 hour old)
-		if time.Now().Unix()-stockPrice.LastUpdatedTime < 3600 {
-			return stockPrice, nil
-		}
+	return s.getPrice(c, tickerSymbol, func() (*models.StockPrice, error) {
+		return s.fetchStockPriceFromAPI(c, tickerSymbol)
+	})
+}
+
+// GetCryptoPrice returns current price of a cryptocurrency quoted in the specified currency
+func (s *StockPriceService) GetCryptoPrice(c core.Context, tickerSymbol string, currency string) (*models.StockPrice, error) {
+	if tickerSymbol == "" {
+		return nil, errs.ErrTickerSymbolIsEmpty
 	}
 
-	// Cache miss or stale, fetch from API
-	stockPrice, err = s.fetchStockPriceFromAPI(c, tickerSymbol)
-	if err != nil {
-		log.Warnf(c, "[stock_prices.GetStockPrice] failed to fetch stock price for %s from API, error %s", tickerSymbol, err.Error())
-		// If API fails, return cached data even if stale
-		if stockPrice != nil {
-			return stockPrice, nil
-		}
-		return nil, err
+	return s.getPrice(c, models.GetCryptoPriceTickerSymbol(tickerSymbol, currency), func() (*models.StockPrice, error) {
+		return cryptoprices.Container.GetLatestCryptoPrice(c, s.CurrentConfig(), tickerSymbol, currency)
+	})
+}
+
+// GetPreciousMetalPrice returns current price per troy ounce of a precious metal (e.g. XAU for gold) quoted in the specified currency,
+// only USD is supported because the market quotes of precious metals are priced in USD
+func (s *StockPriceService) GetPreciousMetalPrice(c core.Context, tickerSymbol string, currency string) (*models.StockPrice, error) {
+	if tickerSymbol == "" {
+		return nil, errs.ErrTickerSymbolIsEmpty
 	}
 
-	// Save to cache
-	err = s.saveStockPriceToCache(c, stockPrice)
-	if err != nil {
-		log.Warnf(c, "[stock_prices.GetStockPrice] failed to save stock price to cache, error %s", err.Error())
+	if !models.IsPreciousMetalTickerSymbol(tickerSymbol) {
+		return nil, errs.ErrPreciousMetalNotSupported
 	}
 
-	return stockPrice, nil
+	if currency != models.PreciousMetalPriceCurrency {
+		return nil, errs.ErrPreciousMetalCurrencyNotSupported
+	}
+
+	return s.getPrice(c, models.GetPreciousMetalPriceTickerSymbol(tickerSymbol), func() (*models.StockPrice, error) {
+		stockPrice, err := s.fetchFromYahooFinance(c, models.GetPreciousMetalPriceSymbol(tickerSymbol))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if stockPrice.Currency != models.PreciousMetalPriceCurrency {
+			return nil, errs.ErrPreciousMetalCurrencyNotSupported
+		}
+
+		stockPrice.TickerSymbol = tickerSymbol
+
+		return stockPrice, nil
+	})
+}
+
+// GetAssetPrice returns current price of the asset according to the asset type
+func (s *StockPriceService) GetAssetPrice(c core.Context, assetType models.InvestmentAssetType, tickerSymbol string, currency string) (*models.StockPrice, error) {
+	if assetType == models.INVESTMENT_ASSET_TYPE_CRYPTOCURRENCY {
+		return s.GetCryptoPrice(c, tickerSymbol, currency)
+	} else if assetType == models.INVESTMENT_ASSET_TYPE_PRECIOUS_METAL {
+		return s.GetPreciousMetalPrice(c, tickerSymbol, currency)
+	}
+
+	return s.GetStockPrice(c, tickerSymbol)
 }
 
 // GetMultipleStockPrices returns stock prices for multiple ticker symbols
@@ -121,6 +154,37 @@ func (s *StockPriceService) RefreshStockPriceCache(c core.Context, tickerSymbol
 	return stockPrice, nil
 }
 
+// getPrice returns the cached price if it is still fresh (less than 1 hour old), otherwise fetches the price and saves it to cache
+func (s *StockPriceService) getPrice(c core.Context, cacheTickerSymbol string, fetch func() (*models.StockPrice, error)) (*models.StockPrice, error) {
+	cachedStockPrice, err := s.getStockPriceFromCache(c, cacheTickerSymbol)
+
+	if err == nil && cachedStockPrice != nil && time.Now().Unix()-cachedStockPrice.LastUpdatedTime < 3600 {
+		return cachedStockPrice, nil
+	}
+
+	stockPrice, err := fetch()
+
+	if err != nil {
+		log.Warnf(c, "[stock_prices.getPrice] failed to fetch price for %s from API, error %s", cacheTickerSymbol, err.Error())
+
+		// If API fails, return cached data even if stale
+		if cachedStockPrice != nil {
+			return cachedStockPrice, nil
+		}
+
+		return nil, err
+	}
+
+	stockPrice.TickerSymbol = cacheTickerSymbol
+	err = s.saveStockPriceToCache(c, stockPrice)
+
+	if err != nil {
+		log.Warnf(c, "[stock_prices.getPrice] failed to save price to cache, error %s", err.Error())
+	}
+
+	return stockPrice, nil
+}
+
 // getStockPriceFromCache retrieves stock price from database cache
 func (s *StockPriceService) getStockPriceFromCache(c core.Context, tickerSymbol string) (*models.StockPrice, error) {
 	sess := s.container.UserDataStore.Get(0).NewSession(c)
@@ -239,6 +303,7 @@ func (s *StockPriceService) fetchFromYahooFinance(c core.Context, tickerSymbol s
 		TickerSymbol:    symbol,
 		CompanyName:     "", // Yahoo doesn't provide company name in this endpoint
 		CurrentPrice:    priceInCents,
+		PricePrecision:  models.DefaultInvestmentPricePrecision,
 		Currency:        currency,
 		LastUpdatedTime: time.Now().Unix(),
 	}
@@ -264,11 +329,14 @@ func (s *StockPriceService) convertPriceFromFloat64(price float64) int64 {
 }
 
 // NewStockPriceService returns new stock price service
-func NewStockPriceService(container *datastore.DataStoreContainer) *StockPriceService {
+func NewStockPriceService(container *datastore.DataStoreContainer, configContainer *settings.ConfigContainer) *StockPriceService {
 	return &StockPriceService{
 		ServiceUsingDB: ServiceUsingDB{
 			container: container,
 		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: configContainer,
+		},
 		container: container,
 	}
 }
\ No newline at end of file
@@ -58,8 +58,8 @@ func (s *TokenService) GetAllTokensByUid(c core.Context, uid int64) ([]*models.T
 	return tokenRecords, err
 }
 
-// GetAllUnexpiredNormalAndMCPTokensByUid returns all available token models of given user
-func (s *TokenService) GetAllUnexpiredNormalAndMCPTokensByUid(c core.Context, uid int64) ([]*models.TokenRecord, error) {
+// GetAllUnexpiredAccessTokensByUid returns all available normal, mcp and personal access token models of given user
+func (s *TokenService) GetAllUnexpiredAccessTokensByUid(c core.Context, uid int64) ([]*models.TokenRecord, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -67,7 +67,7 @@ func (s *TokenService) GetAllUnexpiredNormalAndMCPTokensByUid(c core.Context, ui
 	now := time.Now().Unix()
 
 	var tokenRecords []*models.TokenRecord
-	err := s.TokenDB(uid).NewSession(c).Cols("uid", "user_token_id", "token_type", "user_agent", "created_unix_time", "expired_unix_time", "last_seen_unix_time").Where("uid=? AND (token_type=? OR token_type=?) AND expired_unix_time>?", uid, core.USER_TOKEN_TYPE_NORMAL, core.USER_TOKEN_TYPE_MCP, now).Find(&tokenRecords)
+	err := s.TokenDB(uid).NewSession(c).Cols("uid", "user_token_id", "token_type", "scope", "name", "permissions", "user_agent", "created_unix_time", "expired_unix_time", "last_seen_unix_time").Where("uid=? AND (token_type=? OR token_type=? OR token_type=?) AND expired_unix_time>?", uid, core.USER_TOKEN_TYPE_NORMAL, core.USER_TOKEN_TYPE_MCP, core.USER_TOKEN_TYPE_PERSONAL_ACCESS, now).Find(&tokenRecords)
 
 	return tokenRecords, err
 }
@@ -79,57 +79,75 @@ func (s *TokenService) ParseToken(c core.Context, token string) (*jwt.Token, *co
 
 // CreateTokenViaCli generates a new normal token and saves to database
 func (s *TokenService) CreateTokenViaCli(c *core.CliContext, user *models.User) (string, *models.TokenRecord, error) {
-	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_NORMAL, TokenUserAgentCreatedViaCli, s.CurrentConfig().TokenExpiredTimeDuration)
+	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_NORMAL, core.USER_TOKEN_SCOPE_FULL_ACCESS, TokenUserAgentCreatedViaCli, s.CurrentConfig().TokenExpiredTimeDuration)
 	return token, tokenRecord, err
 }
 
 // CreateToken generates a new normal token and saves to database
 func (s *TokenService) CreateToken(c *core.WebContext, user *models.User) (string, *core.UserTokenClaims, error) {
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_NORMAL, s.getUserAgent(c), s.CurrentConfig().TokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_NORMAL, core.USER_TOKEN_SCOPE_FULL_ACCESS, s.getUserAgent(c), s.CurrentConfig().TokenExpiredTimeDuration)
 	return token, claims, err
 }
 
 // CreateRequire2FAToken generates a new token requiring user to verify 2fa passcode and saves to database
 func (s *TokenService) CreateRequire2FAToken(c *core.WebContext, user *models.User) (string, *core.UserTokenClaims, error) {
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_REQUIRE_2FA, s.getUserAgent(c), s.CurrentConfig().TemporaryTokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_REQUIRE_2FA, core.USER_TOKEN_SCOPE_FULL_ACCESS, s.getUserAgent(c), s.CurrentConfig().TemporaryTokenExpiredTimeDuration)
 	return token, claims, err
 }
 
 // CreateEmailVerifyToken generates a new email verify token and saves to database
 func (s *TokenService) CreateEmailVerifyToken(c *core.WebContext, user *models.User) (string, *core.UserTokenClaims, error) {
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_EMAIL_VERIFY, s.getUserAgent(c), s.CurrentConfig().EmailVerifyTokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_EMAIL_VERIFY, core.USER_TOKEN_SCOPE_FULL_ACCESS, s.getUserAgent(c), s.CurrentConfig().EmailVerifyTokenExpiredTimeDuration)
 	return token, claims, err
 }
 
 // CreateEmailVerifyTokenWithoutUserAgent generates a new email verify token and saves to database
 func (s *TokenService) CreateEmailVerifyTokenWithoutUserAgent(c core.Context, user *models.User) (string, *core.UserTokenClaims, error) {
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_EMAIL_VERIFY, "", s.CurrentConfig().EmailVerifyTokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_EMAIL_VERIFY, core.USER_TOKEN_SCOPE_FULL_ACCESS, "", s.CurrentConfig().EmailVerifyTokenExpiredTimeDuration)
 	return token, claims, err
 }
 
 // CreatePasswordResetToken generates a new password reset token and saves to database
 func (s *TokenService) CreatePasswordResetToken(c *core.WebContext, user *models.User) (string, *core.UserTokenClaims, error) {
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_PASSWORD_RESET, s.getUserAgent(c), s.CurrentConfig().PasswordResetTokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_PASSWORD_RESET, core.USER_TOKEN_SCOPE_FULL_ACCESS, s.getUserAgent(c), s.CurrentConfig().PasswordResetTokenExpiredTimeDuration)
 	return token, claims, err
 }
 
 // CreatePasswordResetTokenWithoutUserAgent generates a new password reset token and saves to database
 func (s *TokenService) CreatePasswordResetTokenWithoutUserAgent(c core.Context, user *models.User) (string, *core.UserTokenClaims, error) {
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_PASSWORD_RESET, "", s.CurrentConfig().PasswordResetTokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_PASSWORD_RESET, core.USER_TOKEN_SCOPE_FULL_ACCESS, "", s.CurrentConfig().PasswordResetTokenExpiredTimeDuration)
 	return token, claims, err
 }
 
-// CreateMCPToken generates a new MCP token and saves to database
-func (s *TokenService) CreateMCPToken(c *core.WebContext, user *models.User) (string, *core.UserTokenClaims, error) {
+// CreateOAuth2CallbackToken generates a new token for user who has been authenticated by external identity provider and saves to database
+func (s *TokenService) CreateOAuth2CallbackToken(c *core.WebContext, user *models.User) (string, *core.UserTokenClaims, error) {
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_OAUTH2_CALLBACK, core.USER_TOKEN_SCOPE_FULL_ACCESS, s.getUserAgent(c), s.CurrentConfig().TemporaryTokenExpiredTimeDuration)
+	return token, claims, err
+}
+
+// CreateMCPToken generates a new MCP token with the specified scope and saves to database
+func (s *TokenService) CreateMCPToken(c *core.WebContext, user *models.User, tokenScope core.TokenScope) (string, *core.UserTokenClaims, error) {
 	tokenExpiredTimeDuration := time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
-	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, s.getUserAgent(c), tokenExpiredTimeDuration)
+	token, claims, _, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, tokenScope, s.getUserAgent(c), tokenExpiredTimeDuration)
 	return token, claims, err
 }
 
-// CreateMCPTokenViaCli generates a new MCP token and saves to database
-func (s *TokenService) CreateMCPTokenViaCli(c *core.CliContext, user *models.User) (string, *models.TokenRecord, error) {
+// CreateMCPTokenViaCli generates a new MCP token with the specified scope and saves to database
+func (s *TokenService) CreateMCPTokenViaCli(c *core.CliContext, user *models.User, tokenScope core.TokenScope) (string, *models.TokenRecord, error) {
 	tokenExpiredTimeDuration := time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
-	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, TokenUserAgentCreatedViaCli, tokenExpiredTimeDuration)
+	token, _, tokenRecord, err := s.createToken(c, user, core.USER_TOKEN_TYPE_MCP, tokenScope, TokenUserAgentCreatedViaCli, tokenExpiredTimeDuration)
+	return token, tokenRecord, err
+}
+
+// CreatePersonalAccessToken generates a new personal access token with the specified permissions and saves to database
+func (s *TokenService) CreatePersonalAccessToken(c *core.WebContext, user *models.User, name string, permissions core.TokenPermissions, expiredInDays int) (string, *core.UserTokenClaims, error) {
+	token, claims, _, err := s.createPersonalAccessToken(c, user, name, permissions, s.getUserAgent(c), expiredInDays)
+	return token, claims, err
+}
+
+// CreatePersonalAccessTokenViaCli generates a new personal access token with the specified permissions and saves to database
+func (s *TokenService) CreatePersonalAccessTokenViaCli(c *core.CliContext, user *models.User, name string, permissions core.TokenPermissions, expiredInDays int) (string, *models.TokenRecord, error) {
+	token, _, tokenRecord, err := s.createPersonalAccessToken(c, user, name, permissions, TokenUserAgentCreatedViaCli, expiredInDays)
 	return token, tokenRecord, err
 }
 
@@ -374,20 +392,46 @@ func (s *TokenService) parseToken(c core.Context, tokenString string) (*jwt.Toke
 	return token, claims, err
 }
 
-func (s *TokenService) createToken(c core.Context, user *models.User, tokenType core.TokenType, userAgent string, expiryDate time.Duration) (string, *core.UserTokenClaims, *models.TokenRecord, error) {
-	var err error
-	now := time.Now()
+func (s *TokenService) createPersonalAccessToken(c core.Context, user *models.User, name string, permissions core.TokenPermissions, userAgent string, expiredInDays int) (string, *core.UserTokenClaims, *models.TokenRecord, error) {
+	if name == "" || len(name) > models.TokenMaxNameLength {
+		return "", nil, nil, errs.ErrParameterInvalid
+	}
+
+	if permissions == 0 {
+		return "", nil, nil, errs.ErrTokenPermissionInvalid
+	}
+
+	tokenExpiredTimeDuration := time.Unix(tokenMaxExpiredAtUnixTime, 0).Sub(time.Now())
+
+	if expiredInDays > 0 {
+		tokenExpiredTimeDuration = time.Duration(expiredInDays) * 24 * time.Hour
+	}
 
 	tokenRecord := &models.TokenRecord{
-		Uid:              user.Uid,
-		UserTokenId:      s.getUserTokenId(),
-		TokenType:        tokenType,
-		UserAgent:        userAgent,
-		CreatedUnixTime:  now.Unix(),
-		ExpiredUnixTime:  now.Add(expiryDate).Unix(),
-		LastSeenUnixTime: now.Unix(),
+		Name:        name,
+		Permissions: permissions,
 	}
 
+	return s.createTokenFromRecord(c, user, tokenRecord, core.USER_TOKEN_TYPE_PERSONAL_ACCESS, core.USER_TOKEN_SCOPE_FULL_ACCESS, userAgent, tokenExpiredTimeDuration)
+}
+
+func (s *TokenService) createToken(c core.Context, user *models.User, tokenType core.TokenType, tokenScope core.TokenScope, userAgent string, expiryDate time.Duration) (string, *core.UserTokenClaims, *models.TokenRecord, error) {
+	return s.createTokenFromRecord(c, user, &models.TokenRecord{}, tokenType, tokenScope, userAgent, expiryDate)
+}
+
+func (s *TokenService) createTokenFromRecord(c core.Context, user *models.User, tokenRecord *models.TokenRecord, tokenType core.TokenType, tokenScope core.TokenScope, userAgent string, expiryDate time.Duration) (string, *core.UserTokenClaims, *models.TokenRecord, error) {
+	var err error
+	now := time.Now()
+
+	tokenRecord.Uid = user.Uid
+	tokenRecord.UserTokenId = s.getUserTokenId()
+	tokenRecord.TokenType = tokenType
+	tokenRecord.Scope = tokenScope
+	tokenRecord.UserAgent = userAgent
+	tokenRecord.CreatedUnixTime = now.Unix()
+	tokenRecord.ExpiredUnixTime = now.Add(expiryDate).Unix()
+	tokenRecord.LastSeenUnixTime = now.Unix()
+
 	if tokenRecord.Secret, err = utils.GetRandomString(10); err != nil {
 		return "", nil, nil, err
 	}
@@ -397,6 +441,8 @@ func (s *TokenService) createToken(c core.Context, user *models.User, tokenType
 		Uid:         tokenRecord.Uid,
 		Username:    user.Username,
 		Type:        tokenRecord.TokenType,
+		Scope:       tokenRecord.Scope,
+		Permissions: tokenRecord.Permissions,
 		IssuedAt:    tokenRecord.CreatedUnixTime,
 		ExpiresAt:   tokenRecord.ExpiredUnixTime,
 	}
@@ -0,0 +1,445 @@
+package services
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionRuleService represents transaction rule service
+type TransactionRuleService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction rule service singleton instance
+var (
+	TransactionRules = &TransactionRuleService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetTotalRuleCountByUid returns total transaction rule count of user
+func (s *TransactionRuleService) GetTotalRuleCountByUid(c core.Context, uid int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(&models.TransactionRule{})
+
+	return count, err
+}
+
+// GetAllRulesByUid returns all transaction rule models of user
+func (s *TransactionRuleService) GetAllRulesByUid(c core.Context, uid int64) ([]*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var rules []*models.TransactionRule
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Find(&rules)
+
+	return rules, err
+}
+
+// GetRuleByRuleId returns a transaction rule model according to transaction rule id
+func (s *TransactionRuleService) GetRuleByRuleId(c core.Context, uid int64, ruleId int64) (*models.TransactionRule, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if ruleId <= 0 {
+		return nil, errs.ErrTransactionRuleIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(ruleId).Where("uid=? AND deleted=?", uid, false).Get(rule)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionRuleNotFound
+	}
+
+	return rule, nil
+}
+
+// GetEnabledRuleMatchersByUid returns the compiled matchers of all enabled transaction rules of user in priority order
+func (s *TransactionRuleService) GetEnabledRuleMatchersByUid(c core.Context, uid int64) ([]*models.TransactionRuleMatcher, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var rules models.TransactionRuleSlice
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND disabled=?", uid, false, false).Find(&rules)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(rules)
+	matchers := make([]*models.TransactionRuleMatcher, 0, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		matcher, err := rules[i].CreateMatcher()
+
+		if err != nil {
+			log.Warnf(c, "[transaction_rules.GetEnabledRuleMatchersByUid] skip invalid rule \"id:%d\" of user \"uid:%d\", because %s", rules[i].RuleId, uid, err.Error())
+			continue
+		}
+
+		matchers = append(matchers, matcher)
+	}
+
+	return matchers, nil
+}
+
+// GetMaxDisplayOrder returns the max display order
+func (s *TransactionRuleService) GetMaxDisplayOrder(c core.Context, uid int64) (int32, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	rule := &models.TransactionRule{}
+	has, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "display_order").Where("uid=? AND deleted=?", uid, false).OrderBy("display_order desc").Limit(1).Get(rule)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if has {
+		return rule.DisplayOrder, nil
+	} else {
+		return 0, nil
+	}
+}
+
+// CreateRule saves a new transaction rule model to database
+func (s *TransactionRuleService) CreateRule(c core.Context, rule *models.TransactionRule) error {
+	if rule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	rule.RuleId = s.GenerateUuid(uuid.UUID_TYPE_RULE)
+
+	if rule.RuleId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	rule.Deleted = false
+	rule.CreatedUnixTime = time.Now().Unix()
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(rule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isRuleValid(sess, rule)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(rule)
+		return err
+	})
+}
+
+// ModifyRule saves an existed transaction rule model to database
+func (s *TransactionRuleService) ModifyRule(c core.Context, rule *models.TransactionRule) error {
+	if rule.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	rule.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(rule.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		err := s.isRuleValid(sess, rule)
+
+		if err != nil {
+			return err
+		}
+
+		updatedRows, err := sess.ID(rule.RuleId).Cols("name", "transaction_type", "account_id", "comment_pattern", "counterparty_pattern", "min_amount", "max_amount", "category_id", "tag_ids", "splits", "rewrite_comment", "new_comment", "stop_processing", "updated_unix_time").Where("uid=? AND deleted=?", rule.Uid, false).Update(rule)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return err
+	})
+}
+
+// DisableRule updates disabled field of given transaction rules
+func (s *TransactionRuleService) DisableRule(c core.Context, uid int64, ids []int64, disabled bool) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Disabled:        disabled,
+		UpdatedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("disabled", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).In("rule_id", ids).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return err
+	})
+}
+
+// ModifyRuleDisplayOrders updates display order (which is the priority) of given transaction rules
+func (s *TransactionRuleService) ModifyRuleDisplayOrders(c core.Context, uid int64, rules []*models.TransactionRule) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	for i := 0; i < len(rules); i++ {
+		rules[i].UpdatedUnixTime = time.Now().Unix()
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(rules); i++ {
+			rule := rules[i]
+			updatedRows, err := sess.ID(rule.RuleId).Cols("display_order", "updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(rule)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionRuleNotFound
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteRule deletes an existed transaction rule from database
+func (s *TransactionRuleService) DeleteRule(c core.Context, uid int64, ruleId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(ruleId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionRuleNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllRules deletes all existed transaction rules from database
+func (s *TransactionRuleService) DeleteAllRules(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionRule{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// ApplyRulesToTransaction applies the matched transaction rules to the specified transaction and returns the new tag ids and the ids of matched rules
+func (s *TransactionRuleService) ApplyRulesToTransaction(matchers []*models.TransactionRuleMatcher, transaction *models.Transaction, tagIds []int64, counterparty string) ([]int64, []int64) {
+	transactionType, err := transaction.Type.ToTransactionType()
+
+	if err != nil {
+		return tagIds, nil
+	}
+
+	var matchedRuleIds []int64
+
+	for i := 0; i < len(matchers); i++ {
+		matcher := matchers[i]
+
+		if !matcher.IsMatched(transactionType, transaction.AccountId, transaction.Amount, transaction.Comment, counterparty) {
+			continue
+		}
+
+		matchedRuleIds = append(matchedRuleIds, matcher.Rule.RuleId)
+
+		if matcher.Rule.CategoryId > 0 && transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			transaction.CategoryId = matcher.Rule.CategoryId
+		}
+
+		// split lines entered by user or set by a rule with higher priority are never overwritten
+		if len(matcher.Rule.Splits) > 0 && !transaction.HasSplits && len(transaction.Splits) < 1 &&
+			(transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE) {
+			transaction.Splits = matcher.Rule.GetSplitsByAmount(transaction.Amount)
+			transaction.CategoryId = transaction.Splits[0].CategoryId
+		}
+
+		ruleTagIds := matcher.Rule.GetTagIds()
+
+		for j := 0; j < len(ruleTagIds); j++ {
+			if !slices.Contains(tagIds, ruleTagIds[j]) && len(tagIds) < models.MaximumTagsCountOfTransaction {
+				tagIds = append(tagIds, ruleTagIds[j])
+			}
+		}
+
+		transaction.Comment = matcher.GetRewrittenComment(transaction.Comment)
+
+		if matcher.Rule.StopProcessing {
+			break
+		}
+	}
+
+	return tagIds, matchedRuleIds
+}
+
+// ApplyRulesToImportTransactions applies the matched transaction rules to the parsed import transactions
+func (s *TransactionRuleService) ApplyRulesToImportTransactions(matchers []*models.TransactionRuleMatcher, transactions models.ImportedTransactionSlice, categoryMap map[int64]*models.TransactionCategory) {
+	if len(matchers) < 1 {
+		return
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		tagIds, err := utils.StringArrayToInt64Array(transaction.TagIds)
+
+		if err != nil {
+			continue
+		}
+
+		originalCategoryId := transaction.CategoryId
+		newTagIds, matchedRuleIds := s.ApplyRulesToTransaction(matchers, transaction.Transaction, tagIds, transaction.OriginalPayeeName)
+
+		if len(matchedRuleIds) < 1 {
+			continue
+		}
+
+		transaction.MatchedRuleIds = matchedRuleIds
+
+		if transaction.CategoryId != originalCategoryId {
+			if category, exists := categoryMap[transaction.CategoryId]; exists {
+				transaction.OriginalCategoryName = category.Name
+			}
+		}
+
+		if len(newTagIds) > len(tagIds) {
+			transaction.TagIds = utils.Int64ArrayToStringArray(newTagIds)
+		}
+	}
+}
+
+func (s *TransactionRuleService) isRuleValid(sess *xorm.Session, rule *models.TransactionRule) error {
+	if rule.AccountId > 0 {
+		account := &models.Account{}
+		has, err := sess.ID(rule.AccountId).Where("uid=? AND deleted=?", rule.Uid, false).Get(account)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrAccountNotFound
+		}
+	}
+
+	if rule.CategoryId > 0 {
+		err := s.isRuleCategoryValid(sess, rule, rule.CategoryId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(rule.Splits); i++ {
+		err := s.isRuleCategoryValid(sess, rule, rule.Splits[i].CategoryId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	tagIds := rule.GetTagIds()
+
+	if len(tagIds) > 0 {
+		var tags []*models.TransactionTag
+		err := sess.Where("uid=? AND deleted=?", rule.Uid, false).In("tag_id", tagIds).Find(&tags)
+
+		if err != nil {
+			return err
+		} else if len(tags) < len(tagIds) {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionRuleService) isRuleCategoryValid(sess *xorm.Session, rule *models.TransactionRule, categoryId int64) error {
+	category := &models.TransactionCategory{}
+	has, err := sess.ID(categoryId).Where("uid=? AND deleted=?", rule.Uid, false).Get(category)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	if category.ParentCategoryId == models.LevelOneTransactionCategoryParentId {
+		return errs.ErrCannotUsePrimaryCategoryForTransaction
+	}
+
+	// the category must be valid for every transaction type which the rule can match
+	transactionTypes := rule.GetMatchableTransactionTypes()
+
+	for i := 0; i < len(transactionTypes); i++ {
+		transactionType := transactionTypes[i]
+
+		if (transactionType == models.TRANSACTION_TYPE_MODIFY_BALANCE) ||
+			(transactionType == models.TRANSACTION_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_INCOME) ||
+			(transactionType == models.TRANSACTION_TYPE_EXPENSE && category.Type != models.CATEGORY_TYPE_EXPENSE) ||
+			(transactionType == models.TRANSACTION_TYPE_TRANSFER && category.Type != models.CATEGORY_TYPE_TRANSFER) {
+			return errs.ErrTransactionCategoryTypeInvalid
+		}
+	}
+
+	return nil
+}
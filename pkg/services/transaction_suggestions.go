@@ -0,0 +1,575 @@
+package services
+
+import (
+	"slices"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const pageCountForLoadTransactionSuggestionSamples = 1000
+const batchCountForInsertTransactionSuggestionFeatures = 100
+const maxChangedTransactionCountForUpdateTransactionSuggestionModelIncrementally = 500
+
+// TransactionSuggestionService represents transaction suggestion service
+type TransactionSuggestionService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction suggestion service singleton instance
+var (
+	TransactionSuggestions = &TransactionSuggestionService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetModelInfoByUid returns the transaction suggestion model info of user, or nil if the model has not been built
+func (s *TransactionSuggestionService) GetModelInfoByUid(c core.Context, uid int64) (*models.TransactionSuggestionModelInfo, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	modelInfo := &models.TransactionSuggestionModelInfo{}
+	has, err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).Get(modelInfo)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+
+	return modelInfo, nil
+}
+
+// GetModelByUid returns the transaction suggestion model of user, only the specified features would be loaded if features is not nil
+func (s *TransactionSuggestionService) GetModelByUid(c core.Context, uid int64, features []string) (*models.TransactionSuggestionModel, error) {
+	modelInfo, err := s.GetModelInfoByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	} else if modelInfo == nil {
+		return models.NewTransactionSuggestionModel(nil, nil), nil
+	}
+
+	var modelFeatures []*models.TransactionSuggestionFeature
+	sess := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid)
+
+	if features != nil {
+		loadFeatures := make([]string, 0, len(features)+2)
+		loadFeatures = append(loadFeatures, models.TRANSACTION_SUGGESTION_FEATURE_PRIOR, models.TRANSACTION_SUGGESTION_FEATURE_TOTAL)
+		loadFeatures = append(loadFeatures, features...)
+		sess = sess.In("feature", loadFeatures)
+	}
+
+	err = sess.Find(&modelFeatures)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewTransactionSuggestionModel(modelInfo, modelFeatures), nil
+}
+
+// IsModelOutdated returns whether any transaction of user has been changed after the transaction suggestion model was built
+func (s *TransactionSuggestionService) IsModelOutdated(c core.Context, uid int64) (bool, error) {
+	modelInfo, err := s.GetModelInfoByUid(c, uid)
+
+	if err != nil {
+		return false, err
+	} else if modelInfo == nil {
+		return true, nil
+	}
+
+	return s.UserDataDB(uid).NewSession(c).Where("uid=? AND (created_unix_time>=? OR updated_unix_time>=? OR deleted_unix_time>=?)", uid, modelInfo.BuiltUnixTime, modelInfo.BuiltUnixTime, modelInfo.BuiltUnixTime).Exist(&models.Transaction{})
+}
+
+// RebuildModel updates the transaction suggestion model of user by the transactions changed after the model was built,
+// or trains the model from all transactions if the model has not been built or there are too many changed transactions
+func (s *TransactionSuggestionService) RebuildModel(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	modelInfo, err := s.GetModelInfoByUid(c, uid)
+
+	if err != nil {
+		return err
+	}
+
+	if modelInfo != nil {
+		updated, err := s.updateModelIncrementally(c, uid, modelInfo)
+
+		if err != nil {
+			return err
+		} else if updated {
+			return nil
+		}
+	}
+
+	return s.rebuildModelFully(c, uid)
+}
+
+func (s *TransactionSuggestionService) rebuildModelFully(c core.Context, uid int64) error {
+	now := time.Now().Unix()
+	trainedSamples, err := s.getAllTransactionSuggestionTrainedSamples(c, uid)
+
+	if err != nil {
+		return err
+	}
+
+	samples := s.toTransactionSuggestionSamples(trainedSamples)
+	features, vocabularySize := models.BuildTransactionSuggestionFeatures(uid, samples)
+
+	modelInfo := &models.TransactionSuggestionModelInfo{
+		Uid:              uid,
+		TransactionCount: int64(len(samples)),
+		VocabularySize:   vocabularySize,
+		BuiltUnixTime:    now,
+		CreatedUnixTime:  now,
+		UpdatedUnixTime:  now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=?", uid).Delete(&models.TransactionSuggestionFeature{})
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(features); i += batchCountForInsertTransactionSuggestionFeatures {
+			end := i + batchCountForInsertTransactionSuggestionFeatures
+
+			if end > len(features) {
+				end = len(features)
+			}
+
+			_, err = sess.Insert(features[i:end])
+
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = sess.Where("uid=?", uid).Delete(&models.TransactionSuggestionTrainedSample{})
+
+		if err != nil {
+			return err
+		}
+
+		err = s.insertTrainedSamples(sess, trainedSamples)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("uid=?", uid).Delete(&models.TransactionSuggestionModelInfo{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(modelInfo)
+
+		return err
+	})
+}
+
+// updateModelIncrementally updates the feature counts of the transactions changed after the model was built, returns false if the model needs to be rebuilt fully
+func (s *TransactionSuggestionService) updateModelIncrementally(c core.Context, uid int64, modelInfo *models.TransactionSuggestionModelInfo) (bool, error) {
+	now := time.Now().Unix()
+
+	if modelInfo.TransactionCount > 0 {
+		// the model built by previous versions does not have trained samples, so the previous counts of the changed transactions are unknown
+		exists, err := s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).Exist(&models.TransactionSuggestionTrainedSample{})
+
+		if err != nil {
+			return false, err
+		} else if !exists {
+			return false, nil
+		}
+	}
+
+	var changedTransactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, uid, deleted, type, category_id, account_id, amount, comment").Where("uid=? AND (created_unix_time>=? OR updated_unix_time>=? OR deleted_unix_time>=?)", uid, modelInfo.BuiltUnixTime, modelInfo.BuiltUnixTime, modelInfo.BuiltUnixTime).Limit(maxChangedTransactionCountForUpdateTransactionSuggestionModelIncrementally+1, 0).Find(&changedTransactions)
+
+	if err != nil {
+		return false, err
+	} else if len(changedTransactions) > maxChangedTransactionCountForUpdateTransactionSuggestionModelIncrementally {
+		return false, nil
+	}
+
+	changedTransactionIds := make([]int64, len(changedTransactions))
+
+	for i := 0; i < len(changedTransactions); i++ {
+		changedTransactionIds[i] = changedTransactions[i].TransactionId
+	}
+
+	var oldTrainedSamples []*models.TransactionSuggestionTrainedSample
+	var tagIndexes []*models.TransactionTagIndex
+
+	if len(changedTransactionIds) > 0 {
+		err = s.UserDataDB(uid).NewSession(c).Where("uid=?", uid).In("transaction_id", changedTransactionIds).Find(&oldTrainedSamples)
+
+		if err != nil {
+			return false, err
+		}
+
+		err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", changedTransactionIds).Find(&tagIndexes)
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	allTagIds := make(map[int64][]int64)
+
+	for i := 0; i < len(tagIndexes); i++ {
+		allTagIds[tagIndexes[i].TransactionId] = append(allTagIds[tagIndexes[i].TransactionId], tagIndexes[i].TagId)
+	}
+
+	newTrainedSamples := make([]*models.TransactionSuggestionTrainedSample, 0, len(changedTransactions))
+
+	for i := 0; i < len(changedTransactions); i++ {
+		transaction := changedTransactions[i]
+
+		if transaction.Deleted || (transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE && transaction.Type != models.TRANSACTION_DB_TYPE_TRANSFER_OUT) {
+			continue
+		}
+
+		newTrainedSamples = append(newTrainedSamples, models.NewTransactionSuggestionTrainedSample(transaction, allTagIds[transaction.TransactionId]))
+	}
+
+	featureCountDeltas := models.GetTransactionSuggestionFeatureCountDeltas(uid, s.toTransactionSuggestionSamples(oldTrainedSamples), s.toTransactionSuggestionSamples(newTrainedSamples))
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(featureCountDeltas); i++ {
+			delta := featureCountDeltas[i]
+			feature := &models.TransactionSuggestionFeature{}
+			has, err := sess.Where("uid=? AND target_type=? AND target_id=? AND feature=?", uid, delta.TargetType, delta.TargetId, delta.Feature).Get(feature)
+
+			if err != nil {
+				return err
+			}
+
+			if !has {
+				if delta.Count > 0 {
+					_, err = sess.Insert(delta)
+				}
+			} else if feature.Count+delta.Count > 0 {
+				feature.Count += delta.Count
+				_, err = sess.Cols("count").Where("uid=? AND target_type=? AND target_id=? AND feature=?", uid, delta.TargetType, delta.TargetId, delta.Feature).Update(feature)
+			} else {
+				_, err = sess.Where("uid=? AND target_type=? AND target_id=? AND feature=?", uid, delta.TargetType, delta.TargetId, delta.Feature).Delete(&models.TransactionSuggestionFeature{})
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(changedTransactionIds) > 0 {
+			_, err := sess.Where("uid=?", uid).In("transaction_id", changedTransactionIds).Delete(&models.TransactionSuggestionTrainedSample{})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err := s.insertTrainedSamples(sess, newTrainedSamples)
+
+		if err != nil {
+			return err
+		}
+
+		// the prior count of all samples is the transaction count, and the features of all samples (except the special features) are the vocabulary
+		priorFeature := &models.TransactionSuggestionFeature{}
+		_, err = sess.Where("uid=? AND target_type=? AND target_id=? AND feature=?", uid, models.TRANSACTION_SUGGESTION_TARGET_TYPE_ALL, 0, models.TRANSACTION_SUGGESTION_FEATURE_PRIOR).Get(priorFeature)
+
+		if err != nil {
+			return err
+		}
+
+		vocabularySize, err := sess.Where("uid=? AND target_type=? AND target_id=?", uid, models.TRANSACTION_SUGGESTION_TARGET_TYPE_ALL, 0).NotIn("feature", models.TRANSACTION_SUGGESTION_FEATURE_PRIOR, models.TRANSACTION_SUGGESTION_FEATURE_TOTAL).Count(&models.TransactionSuggestionFeature{})
+
+		if err != nil {
+			return err
+		}
+
+		modelInfo.TransactionCount = priorFeature.Count
+		modelInfo.VocabularySize = vocabularySize
+		modelInfo.BuiltUnixTime = now
+		modelInfo.UpdatedUnixTime = now
+
+		_, err = sess.Cols("transaction_count", "vocabulary_size", "built_unix_time", "updated_unix_time").Where("uid=?", uid).Update(modelInfo)
+
+		return err
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RebuildAllOutdatedModels rebuilds the transaction suggestion models of all users whose transactions have been changed after the last building
+func (s *TransactionSuggestionService) RebuildAllOutdatedModels(c core.Context) error {
+	var allUids []int64
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		var transactions []*models.Transaction
+		err := s.UserDataDBByIndex(i).NewSession(c).Distinct("uid").Find(&transactions)
+
+		if err != nil {
+			return err
+		}
+
+		for j := 0; j < len(transactions); j++ {
+			allUids = append(allUids, transactions[j].Uid)
+		}
+	}
+
+	rebuiltCount := 0
+	failedCount := 0
+
+	for i := 0; i < len(allUids); i++ {
+		uid := allUids[i]
+		outdated, err := s.IsModelOutdated(c, uid)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[transaction_suggestions.RebuildAllOutdatedModels] failed to check whether the model of user \"uid:%d\" is outdated, because %s", uid, err.Error())
+			continue
+		}
+
+		if !outdated {
+			continue
+		}
+
+		err = s.RebuildModel(c, uid)
+
+		if err != nil {
+			failedCount++
+			log.Errorf(c, "[transaction_suggestions.RebuildAllOutdatedModels] failed to rebuild the model of user \"uid:%d\", because %s", uid, err.Error())
+			continue
+		}
+
+		rebuiltCount++
+	}
+
+	if rebuiltCount > 0 || failedCount > 0 {
+		log.Infof(c, "[transaction_suggestions.RebuildAllOutdatedModels] %d transaction suggestion models have been rebuilt, %d models failed to rebuild", rebuiltCount, failedCount)
+	}
+
+	return nil
+}
+
+// DeleteModel deletes the transaction suggestion model of user from database
+func (s *TransactionSuggestionService) DeleteModel(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=?", uid).Delete(&models.TransactionSuggestionFeature{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("uid=?", uid).Delete(&models.TransactionSuggestionTrainedSample{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Where("uid=?", uid).Delete(&models.TransactionSuggestionModelInfo{})
+
+		return err
+	})
+}
+
+// GetSuggestions returns the most likely categories and tags of the specified transaction data
+func (s *TransactionSuggestionService) GetSuggestions(model *models.TransactionSuggestionModel, transactionType models.TransactionType, accountId int64, amount int64, comment string, candidateCategoryIds map[int64]bool, candidateTagIds map[int64]bool, categoryCount int, tagCount int) ([]*models.TransactionSuggestionItem, []*models.TransactionSuggestionItem) {
+	features := models.GetTransactionSuggestionFeatures(transactionType, accountId, amount, comment)
+	categories := model.PredictCategories(features, candidateCategoryIds, categoryCount)
+	tags := model.PredictTags(features, candidateTagIds, tagCount)
+
+	return categories, tags
+}
+
+// ApplySuggestionsToImportTransactions sets the suggested category (only when the category is not matched) and tags to the parsed import transactions
+func (s *TransactionSuggestionService) ApplySuggestionsToImportTransactions(model *models.TransactionSuggestionModel, transactions models.ImportedTransactionSlice, categories []*models.TransactionCategory, tags []*models.TransactionTag) {
+	candidateCategoryIds := make(map[models.TransactionType]map[int64]bool)
+	candidateTagIds := s.GetCandidateTagIds(tags)
+
+	for _, transactionType := range []models.TransactionType{models.TRANSACTION_TYPE_INCOME, models.TRANSACTION_TYPE_EXPENSE, models.TRANSACTION_TYPE_TRANSFER} {
+		candidateCategoryIds[transactionType] = s.GetCandidateCategoryIds(categories, transactionType)
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		transactionType, err := transaction.Type.ToTransactionType()
+
+		if err != nil || transactionType == models.TRANSACTION_TYPE_MODIFY_BALANCE {
+			continue
+		}
+
+		suggestedCategories, suggestedTags := s.GetSuggestions(model, transactionType, transaction.AccountId, transaction.Amount, transaction.Comment, candidateCategoryIds[transactionType], candidateTagIds, 1, models.MaximumTagsCountOfTransaction)
+
+		if transaction.CategoryId == 0 && len(suggestedCategories) > 0 && suggestedCategories[0].Confidence >= models.TransactionSuggestionMinimumConfidence {
+			transaction.SuggestedCategoryId = suggestedCategories[0].Id
+		}
+
+		for j := 0; j < len(suggestedTags); j++ {
+			tagId := utils.Int64ToString(suggestedTags[j].Id)
+
+			if !slices.Contains(transaction.TagIds, tagId) {
+				transaction.SuggestedTagIds = append(transaction.SuggestedTagIds, suggestedTags[j].Id)
+			}
+		}
+	}
+}
+
+// GetCandidateCategoryIds returns the ids of visible secondary categories which can be suggested for the specified transaction type
+func (s *TransactionSuggestionService) GetCandidateCategoryIds(categories []*models.TransactionCategory, transactionType models.TransactionType) map[int64]bool {
+	var categoryType models.TransactionCategoryType
+
+	switch transactionType {
+	case models.TRANSACTION_TYPE_INCOME:
+		categoryType = models.CATEGORY_TYPE_INCOME
+	case models.TRANSACTION_TYPE_EXPENSE:
+		categoryType = models.CATEGORY_TYPE_EXPENSE
+	case models.TRANSACTION_TYPE_TRANSFER:
+		categoryType = models.CATEGORY_TYPE_TRANSFER
+	default:
+		return nil
+	}
+
+	candidateCategoryIds := make(map[int64]bool)
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.Type == categoryType && category.ParentCategoryId != models.LevelOneTransactionCategoryParentId && !category.Hidden {
+			candidateCategoryIds[category.CategoryId] = true
+		}
+	}
+
+	return candidateCategoryIds
+}
+
+// GetCandidateTagIds returns the ids of visible tags which can be suggested
+func (s *TransactionSuggestionService) GetCandidateTagIds(tags []*models.TransactionTag) map[int64]bool {
+	candidateTagIds := make(map[int64]bool)
+
+	for i := 0; i < len(tags); i++ {
+		if !tags[i].Hidden {
+			candidateTagIds[tags[i].TagId] = true
+		}
+	}
+
+	return candidateTagIds
+}
+
+func (s *TransactionSuggestionService) insertTrainedSamples(sess *xorm.Session, trainedSamples []*models.TransactionSuggestionTrainedSample) error {
+	for i := 0; i < len(trainedSamples); i += batchCountForInsertTransactionSuggestionFeatures {
+		end := i + batchCountForInsertTransactionSuggestionFeatures
+
+		if end > len(trainedSamples) {
+			end = len(trainedSamples)
+		}
+
+		_, err := sess.Insert(trainedSamples[i:end])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionSuggestionService) toTransactionSuggestionSamples(trainedSamples []*models.TransactionSuggestionTrainedSample) []*models.TransactionSuggestionSample {
+	samples := make([]*models.TransactionSuggestionSample, 0, len(trainedSamples))
+
+	for i := 0; i < len(trainedSamples); i++ {
+		sample := trainedSamples[i].ToTransactionSuggestionSample()
+
+		if sample != nil {
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples
+}
+
+func (s *TransactionSuggestionService) getAllTransactionSuggestionTrainedSamples(c core.Context, uid int64) ([]*models.TransactionSuggestionTrainedSample, error) {
+	allTagIds := make(map[int64][]int64)
+	maxTagIndexId := int64(0)
+
+	for maxTagIndexId >= 0 {
+		var tagIndexes []*models.TransactionTagIndex
+		sess := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false)
+
+		if maxTagIndexId > 0 {
+			sess = sess.And("tag_index_id<=?", maxTagIndexId)
+		}
+
+		err := sess.Limit(pageCountForLoadTransactionSuggestionSamples, 0).OrderBy("tag_index_id desc").Find(&tagIndexes)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(tagIndexes); i++ {
+			allTagIds[tagIndexes[i].TransactionId] = append(allTagIds[tagIndexes[i].TransactionId], tagIndexes[i].TagId)
+		}
+
+		if len(tagIndexes) < pageCountForLoadTransactionSuggestionSamples {
+			break
+		}
+
+		maxTagIndexId = tagIndexes[len(tagIndexes)-1].TagIndexId - 1
+	}
+
+	var trainedSamples []*models.TransactionSuggestionTrainedSample
+	maxTransactionTime := int64(0)
+
+	for maxTransactionTime >= 0 {
+		var transactions []*models.Transaction
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, uid, type, category_id, account_id, transaction_time, amount, comment").Where("uid=? AND deleted=? AND (type=? OR type=? OR type=?)", uid, false, models.TRANSACTION_DB_TYPE_INCOME, models.TRANSACTION_DB_TYPE_EXPENSE, models.TRANSACTION_DB_TYPE_TRANSFER_OUT)
+
+		if maxTransactionTime > 0 {
+			sess = sess.And("transaction_time<=?", maxTransactionTime)
+		}
+
+		err := sess.Limit(pageCountForLoadTransactionSuggestionSamples, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+			trainedSamples = append(trainedSamples, models.NewTransactionSuggestionTrainedSample(transaction, allTagIds[transaction.TransactionId]))
+		}
+
+		if len(transactions) < pageCountForLoadTransactionSuggestionSamples {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return trainedSamples, nil
+}
@@ -76,11 +76,11 @@ func (s *TransactionService) GetAllTransactions(c core.Context, uid int64, pageC
 
 // GetAllTransactionsByMaxTime returns all transactions before given time
 func (s *TransactionService) GetAllTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, count int32, noDuplicated bool) ([]*models.Transaction, error) {
-	return s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", 1, count, false, noDuplicated)
+	return s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, "", "", nil, 1, count, false, noDuplicated)
 }
 
 // GetAllSpecifiedTransactions returns all transactions that match given conditions
-func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, amountFilter string, keyword string, pageCount int32, noDuplicated bool) ([]*models.Transaction, error) {
 	if maxTransactionTime <= 0 {
 		maxTransactionTime = utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
 	}
@@ -88,7 +88,7 @@ func (s *TransactionService) GetAllSpecifiedTransactions(c core.Context, uid int
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagIds, noTags, tagFilterType, amountFilter, keyword, 1, pageCount, false, noDuplicated)
+		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, minTransactionTime, transactionType, categoryIds, accountIds, tagIds, noTags, tagFilterType, payeeIds, amountFilter, keyword, nil, 1, pageCount, false, noDuplicated)
 
 		if err != nil {
 			return nil, err
@@ -116,7 +116,7 @@ func (s *TransactionService) GetAllTransactionsWithAccountBalanceByMaxTime(c cor
 	var allTransactions []*models.Transaction
 
 	for maxTransactionTime > 0 {
-		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "", 1, pageCount, false, true)
+		transactions, err := s.GetTransactionsByMaxTime(c, uid, maxTransactionTime, 0, 0, nil, []int64{accountId}, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, "", "", nil, 1, pageCount, false, true)
 
 		if err != nil {
 			return nil, 0, 0, 0, 0, err
@@ -197,8 +197,50 @@ func (s *TransactionService) GetAllTransactionsWithAccountBalanceByMaxTime(c cor
 	return allTransactionsAndAccountBalance, totalInflows, totalOutflows, openingBalance, accumulatedBalance, nil
 }
 
+// GetAccountBalanceByMaxTime returns the balance of the specified account calculated by all transactions not later than given time
+func (s *TransactionService) GetAccountBalanceByMaxTime(c core.Context, uid int64, accountId int64, maxTransactionTime int64) (int64, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	if accountId <= 0 {
+		return 0, errs.ErrAccountIdInvalid
+	}
+
+	balance := int64(0)
+
+	for maxTransactionTime > 0 {
+		var transactions []*models.Transaction
+		err := s.UserDataDB(uid).NewSession(c).Select("type, transaction_time, amount, related_account_amount").Where("uid=? AND deleted=? AND account_id=? AND transaction_time<=?", uid, false, accountId, maxTransactionTime).Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+				balance = balance + transaction.RelatedAccountAmount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+				balance = balance + transaction.Amount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+				balance = balance - transaction.Amount
+			}
+		}
+
+		if len(transactions) < pageCountForLoadTransactionAmounts {
+			break
+		}
+
+		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
+	}
+
+	return balance, nil
+}
+
 // GetTransactionsByMaxTime returns transactions before given time
-func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, amountFilter string, keyword string, searchQuery *models.TransactionSearchQueryNode, page int32, count int32, needOneMoreItem bool, noDuplicated bool) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -232,7 +274,7 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 		actualCount++
 	}
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, amountFilter, keyword, noDuplicated)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, payeeIds, amountFilter, keyword, searchQuery, noDuplicated)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
 
@@ -242,7 +284,7 @@ func (s *TransactionService) GetTransactionsByMaxTime(c core.Context, uid int64,
 }
 
 // GetTransactionsInMonthByPage returns all transactions in given year and month
-func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid int64, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string) ([]*models.Transaction, error) {
+func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid int64, year int32, month int32, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, amountFilter string, keyword string, searchQuery *models.TransactionSearchQueryNode) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -266,7 +308,7 @@ func (s *TransactionService) GetTransactionsInMonthByPage(c core.Context, uid in
 
 	var transactions []*models.Transaction
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, amountFilter, keyword, true)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, payeeIds, amountFilter, keyword, searchQuery, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
 
@@ -311,11 +353,11 @@ func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid i
 
 // GetAllTransactionCount returns total count of transactions
 func (s *TransactionService) GetAllTransactionCount(c core.Context, uid int64) (int64, error) {
-	return s.GetTransactionCount(c, uid, 0, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "")
+	return s.GetTransactionCount(c, uid, 0, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, nil, "", "", nil)
 }
 
 // GetTransactionCount returns count of transactions
-func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, amountFilter string, keyword string) (int64, error) {
+func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxTransactionTime int64, minTransactionTime int64, transactionType models.TransactionType, categoryIds []int64, accountIds []int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, amountFilter string, keyword string, searchQuery *models.TransactionSearchQueryNode) (int64, error) {
 	if uid <= 0 {
 		return 0, errs.ErrUserIdInvalid
 	}
@@ -331,7 +373,7 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 		}
 	}
 
-	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, amountFilter, keyword, true)
+	condition, conditionParams := s.buildTransactionQueryCondition(uid, maxTransactionTime, minTransactionTime, transactionDbType, categoryIds, accountIds, tagIds, payeeIds, amountFilter, keyword, searchQuery, true)
 	sess := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...)
 	sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
 
@@ -340,6 +382,11 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 
 // CreateTransaction saves a new transaction to database
 func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64) error {
+	return s.createTransaction(c, transaction, tagIds, pictureIds, nil)
+}
+
+// createTransaction saves a new transaction to database, and calls the after created function in the same database transaction if it is not nil
+func (s *TransactionService) createTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, afterCreated func(sess *xorm.Session) error) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -384,6 +431,12 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 	transaction.CreatedUnixTime = now
 	transaction.UpdatedUnixTime = now
 
+	err = s.prepareTransactionSplits(transaction, now)
+
+	if err != nil {
+		return err
+	}
+
 	transactionTagIndexes := make([]*models.TransactionTagIndex, len(tagIds))
 
 	for i := 0; i < len(tagIds); i++ {
@@ -406,12 +459,42 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 	userDataDb := s.UserDataDB(transaction.Uid)
 
 	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
-		return s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+		err := s.doCreateTransaction(c, userDataDb, sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+
+		if err != nil || afterCreated == nil {
+			return err
+		}
+
+		return afterCreated(sess)
 	})
 }
 
-// BatchCreateTransactions saves new transactions to database
-func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, processHandler core.TaskProcessUpdateHandler) error {
+// CheckNewTransactionAccountsAndCategory returns whether the accounts and the category of the transaction which would be created are valid
+func (s *TransactionService) CheckNewTransactionAccountsAndCategory(c core.Context, transaction *models.Transaction) error {
+	if transaction.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	err := s.isAccountIdValid(transaction)
+
+	if err != nil {
+		return err
+	}
+
+	sess := s.UserDataDB(transaction.Uid).NewSession(c)
+	defer sess.Close()
+
+	_, _, err = s.getAndVerifyAccountModelsForNewTransaction(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	return s.isCategoryValid(sess, transaction)
+}
+
+// BatchCreateTransactions saves new transactions and the new payees used by them to database
+func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, newPayees []*models.Payee, processHandler core.TaskProcessUpdateHandler) error {
 	now := time.Now().Unix()
 	currentProcess := float64(0)
 	processUpdateStep := int(math.Max(100.0, float64(len(transactions)/100.0)))
@@ -443,6 +526,12 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 
 		transaction.CreatedUnixTime = now
 		transaction.UpdatedUnixTime = now
+
+		err = s.prepareTransactionSplits(transaction, now)
+
+		if err != nil {
+			return err
+		}
 	}
 
 	for index, tagIds := range allTagIds {
@@ -513,7 +602,21 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 
 	userDataDb := s.UserDataDB(uid)
 
+	for i := 0; i < len(newPayees); i++ {
+		if newPayees[i].Uid != uid || newPayees[i].PayeeId <= 0 {
+			return errs.ErrPayeeIdInvalid
+		}
+	}
+
 	return userDataDb.DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(newPayees); i++ {
+			_, err := sess.Insert(newPayees[i])
+
+			if err != nil {
+				return err
+			}
+		}
+
 		for i := 0; i < len(transactions); i++ {
 			transaction := transactions[i]
 			transactionTagIndexes := allTransactionTagIndexes[transaction.TransactionId]
@@ -700,6 +803,14 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 	transaction.UpdatedUnixTime = now
 	updateCols = append(updateCols, "updated_unix_time")
 
+	// Split lines are only replaced when they are specified (an empty slice removes all split lines)
+	replaceSplits := transaction.Splits != nil
+	err := s.prepareTransactionSplits(transaction, now)
+
+	if err != nil {
+		return err
+	}
+
 	addTagIds = utils.ToUniqueInt64Slice(addTagIds)
 	removeTagIds = utils.ToUniqueInt64Slice(removeTagIds)
 
@@ -717,7 +828,7 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		}
 	}
 
-	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+	err = s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
 		has, err := sess.ID(transaction.TransactionId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(oldTransaction)
@@ -735,6 +846,23 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			transaction.RelatedId = oldTransaction.RelatedId
 		}
 
+		// Keep the existing split lines if new split lines are not specified
+		if !replaceSplits && oldTransaction.HasSplits {
+			var oldSplits []*models.TransactionSplit
+			err = sess.Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).OrderBy("display_order asc").Find(&oldSplits)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to get current transaction split lines, because %s", err.Error())
+				return err
+			}
+
+			if len(oldSplits) > 0 {
+				transaction.HasSplits = true
+				transaction.Splits = oldSplits
+				transaction.CategoryId = oldSplits[0].CategoryId
+			}
+		}
+
 		// Check whether account id is valid
 		err = s.isAccountIdValid(transaction)
 
@@ -791,6 +919,26 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			updateCols = append(updateCols, "category_id")
 		}
 
+		if transaction.HasSplits && replaceSplits {
+			// Get and verify split lines
+			err = s.isSplitsValid(sess, transaction)
+
+			if err != nil {
+				return err
+			}
+		} else if transaction.HasSplits {
+			// Existing split lines must still match the transaction amount
+			err = transaction.ValidateSplits()
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if transaction.HasSplits != oldTransaction.HasSplits {
+			updateCols = append(updateCols, "has_splits")
+		}
+
 		modifyTransactionTime := false
 
 		if utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) != utils.GetUnixTimeFromTransactionTime(oldTransaction.TransactionTime) {
@@ -836,6 +984,16 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			updateCols = append(updateCols, "amount")
 		}
 
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME || transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			if transaction.ExchangeRateCurrency != oldTransaction.ExchangeRateCurrency {
+				updateCols = append(updateCols, "exchange_rate_currency")
+			}
+
+			if transaction.ExchangeRate != oldTransaction.ExchangeRate {
+				updateCols = append(updateCols, "exchange_rate")
+			}
+		}
+
 		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
 			if transaction.RelatedAccountId != oldTransaction.RelatedAccountId {
 				updateCols = append(updateCols, "related_account_id")
@@ -850,6 +1008,17 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			updateCols = append(updateCols, "hide_amount")
 		}
 
+		if transaction.PayeeId != oldTransaction.PayeeId {
+			// Get and verify payee
+			err = s.isPayeeValid(sess, transaction)
+
+			if err != nil {
+				return err
+			}
+
+			updateCols = append(updateCols, "payee_id")
+		}
+
 		if transaction.Comment != oldTransaction.Comment {
 			updateCols = append(updateCols, "comment")
 		}
@@ -965,6 +1134,28 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		// Update transaction split lines
+		if replaceSplits && (transaction.HasSplits || oldTransaction.HasSplits) {
+			splitUpdateModel := &models.TransactionSplit{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to remove old transaction split lines, because %s", err.Error())
+				return err
+			}
+
+			err = s.insertTransactionSplits(sess, transaction)
+
+			if err != nil {
+				log.Errorf(c, "[transactions.ModifyTransaction] failed to add new transaction split lines, because %s", err.Error())
+				return err
+			}
+		}
+
 		// Update transaction picture
 		if len(removePictureIds) > 0 {
 			pictureUpdateModel := &models.TransactionPictureInfo{
@@ -1191,6 +1382,11 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
@@ -1250,6 +1446,15 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return err
 		}
 
+		// Update transaction split lines
+		if oldTransaction.HasSplits {
+			_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if oldTransaction.RelatedAccountAmount != 0 {
@@ -1342,6 +1547,11 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	accountUpdateModel := &models.Account{
 		Balance:         0,
 		Deleted:         deleteAccount,
@@ -1370,6 +1580,13 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64, de
 			return err
 		}
 
+		// Update all transaction split lines to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update all accounts to deleted or set amount to zero
 		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
 
@@ -1409,6 +1626,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		RelatedId:            originalTransaction.TransactionId,
 		RelatedAccountId:     originalTransaction.AccountId,
 		RelatedAccountAmount: originalTransaction.Amount,
+		PayeeId:              originalTransaction.PayeeId,
 		Comment:              originalTransaction.Comment,
 		GeoLongitude:         originalTransaction.GeoLongitude,
 		GeoLatitude:          originalTransaction.GeoLatitude,
@@ -1511,11 +1729,104 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 }
 
 // GetAccountsAndCategoriesTotalIncomeAndExpense returns the every accounts and categories total income and expense amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, keyword string, utcOffset int16, useTransactionTimezone bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, keyword string, utcOffset int16, useTransactionTimezone bool) ([]*models.Transaction, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	allTransactions, err := s.getAllIncomeAndExpenseTransactionAmounts(c, uid, startUnixTime, endUnixTime, tagIds, noTags, tagFilterType, payeeIds, keyword, utcOffset, useTransactionTimezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactionTotalAmountsMap := make(map[string]*models.Transaction)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		groupKey := fmt.Sprintf("%d_%d_%d", transaction.CategoryId, transaction.AccountId, transaction.PayeeId)
+		totalAmounts, exists := transactionTotalAmountsMap[groupKey]
+
+		if !exists {
+			totalAmounts = &models.Transaction{
+				CategoryId: transaction.CategoryId,
+				AccountId:  transaction.AccountId,
+				PayeeId:    transaction.PayeeId,
+				Amount:     0,
+			}
+
+			transactionTotalAmountsMap[groupKey] = totalAmounts
+		}
+
+		totalAmounts.Amount += transaction.Amount
+	}
+
+	transactionTotalAmounts := make([]*models.Transaction, 0, len(transactionTotalAmountsMap))
+
+	for _, totalAmounts := range transactionTotalAmountsMap {
+		transactionTotalAmounts = append(transactionTotalAmounts, totalAmounts)
+	}
+
+	return transactionTotalAmounts, nil
+}
+
+// GetAccountsAndCategoriesDailyIncomeAndExpense returns the every accounts and categories daily income and expense amount by specific date range,
+// the transaction time and timezone of each returned item are the ones of the first transaction in the same day (both in the timezone of the transaction and in the timezone for filtering)
+func (s *TransactionService) GetAccountsAndCategoriesDailyIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, keyword string, utcOffset int16, useTransactionTimezone bool) ([]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
 
+	allTransactions, err := s.getAllIncomeAndExpenseTransactionAmounts(c, uid, startUnixTime, endUnixTime, tagIds, noTags, tagFilterType, payeeIds, keyword, utcOffset, useTransactionTimezone)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactionDailyAmountsMap := make(map[string]*models.Transaction)
+	transactionDailyAmounts := make([]*models.Transaction, 0)
+
+	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
+
+	for i := 0; i < len(allTransactions); i++ {
+		transaction := allTransactions[i]
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		timeZone := clientLocation
+
+		if useTransactionTimezone {
+			timeZone = transactionTimeZone
+		}
+
+		unixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		transactionDate := utils.FormatUnixTimeToLongDate(unixTime, transactionTimeZone)
+		localDate := utils.FormatUnixTimeToLongDate(unixTime, timeZone)
+		groupKey := fmt.Sprintf("%d_%d_%d_%s_%s_%s_%d", transaction.CategoryId, transaction.AccountId, transaction.PayeeId, transactionDate, localDate, transaction.ExchangeRateCurrency, transaction.ExchangeRate)
+		dailyAmounts, exists := transactionDailyAmountsMap[groupKey]
+
+		if !exists {
+			dailyAmounts = &models.Transaction{
+				Type:                 transaction.Type,
+				CategoryId:           transaction.CategoryId,
+				AccountId:            transaction.AccountId,
+				PayeeId:              transaction.PayeeId,
+				TransactionTime:      transaction.TransactionTime,
+				TimezoneUtcOffset:    transaction.TimezoneUtcOffset,
+				Amount:               0,
+				ExchangeRateCurrency: transaction.ExchangeRateCurrency,
+				ExchangeRate:         transaction.ExchangeRate,
+			}
+
+			transactionDailyAmountsMap[groupKey] = dailyAmounts
+			transactionDailyAmounts = append(transactionDailyAmounts, dailyAmounts)
+		}
+
+		dailyAmounts.Amount += transaction.Amount
+	}
+
+	return transactionDailyAmounts, nil
+}
+
+func (s *TransactionService) getAllIncomeAndExpenseTransactionAmounts(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, keyword string, utcOffset int16, useTransactionTimezone bool) ([]*models.Transaction, error) {
 	clientLocation := time.FixedZone("Client Timezone", int(utcOffset)*60)
 	var startLocalDateTime, endLocalDateTime, startTransactionTime, endTransactionTime int64
 
@@ -1559,12 +1870,18 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c cor
 			finalConditionParams = append(finalConditionParams, maxTransactionTime)
 		}
 
+		if len(payeeIds) > 0 {
+			payeeIdsCondition, payeeIdsConditionParams := s.getPayeeIdsCondition(payeeIds)
+			finalCondition = finalCondition + " AND " + payeeIdsCondition
+			finalConditionParams = append(finalConditionParams, payeeIdsConditionParams...)
+		}
+
 		if keyword != "" {
 			finalCondition = finalCondition + " AND comment LIKE ?"
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, type, category_id, account_id, payee_id, transaction_time, timezone_utc_offset, amount, exchange_rate_currency, exchange_rate, has_splits").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -1583,7 +1900,13 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c cor
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
-	transactionTotalAmountsMap := make(map[string]*models.Transaction)
+	allTransactions, err := s.expandSplitTransactionAmounts(c, uid, allTransactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filteredTransactions := make([]*models.Transaction, 0, len(allTransactions))
 
 	for i := 0; i < len(allTransactions); i++ {
 		transaction := allTransactions[i]
@@ -1599,33 +1922,14 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c cor
 			continue
 		}
 
-		groupKey := fmt.Sprintf("%d_%d", transaction.CategoryId, transaction.AccountId)
-		totalAmounts, exists := transactionTotalAmountsMap[groupKey]
-
-		if !exists {
-			totalAmounts = &models.Transaction{
-				CategoryId: transaction.CategoryId,
-				AccountId:  transaction.AccountId,
-				Amount:     0,
-			}
-
-			transactionTotalAmountsMap[groupKey] = totalAmounts
-		}
-
-		totalAmounts.Amount += transaction.Amount
-	}
-
-	transactionTotalAmounts := make([]*models.Transaction, 0, len(transactionTotalAmountsMap))
-
-	for _, totalAmounts := range transactionTotalAmountsMap {
-		transactionTotalAmounts = append(transactionTotalAmounts, totalAmounts)
+		filteredTransactions = append(filteredTransactions, transaction)
 	}
 
-	return transactionTotalAmounts, nil
+	return filteredTransactions, nil
 }
 
 // GetAccountsAndCategoriesMonthlyIncomeAndExpense returns the every accounts monthly income and expense amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, keyword string, utcOffset int16, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, payeeIds []int64, keyword string, utcOffset int16, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -1678,12 +1982,18 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 			finalConditionParams = append(finalConditionParams, maxTransactionTime)
 		}
 
+		if len(payeeIds) > 0 {
+			payeeIdsCondition, payeeIdsConditionParams := s.getPayeeIdsCondition(payeeIds)
+			finalCondition = finalCondition + " AND " + payeeIdsCondition
+			finalConditionParams = append(finalConditionParams, payeeIdsConditionParams...)
+		}
+
 		if keyword != "" {
 			finalCondition = finalCondition + " AND comment LIKE ?"
 			finalConditionParams = append(finalConditionParams, "%%"+keyword+"%%")
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, category_id, account_id, payee_id, transaction_time, timezone_utc_offset, amount, has_splits").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -1702,6 +2012,12 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
+	allTransactions, err = s.expandSplitTransactionAmounts(c, uid, allTransactions)
+
+	if err != nil {
+		return nil, err
+	}
+
 	startYearMonth := startYear*100 + startMonth
 	endYearMonth := endYear*100 + endMonth
 	transactionsMonthlyAmountsMap := make(map[string]*models.Transaction)
@@ -1721,13 +2037,14 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 			continue
 		}
 
-		groupKey := fmt.Sprintf("%d_%d_%d", yearMonth, transaction.CategoryId, transaction.AccountId)
+		groupKey := fmt.Sprintf("%d_%d_%d_%d", yearMonth, transaction.CategoryId, transaction.AccountId, transaction.PayeeId)
 		transactionAmounts, exists := transactionsMonthlyAmountsMap[groupKey]
 
 		if !exists {
 			transactionAmounts = &models.Transaction{
 				CategoryId: transaction.CategoryId,
 				AccountId:  transaction.AccountId,
+				PayeeId:    transaction.PayeeId,
 			}
 			transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
 		}
@@ -1751,6 +2068,66 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 	return transactionsMonthlyAmounts, nil
 }
 
+// GetAllSplitsOfTransactions returns all split lines of the specified transactions, grouped by transaction id
+func (s *TransactionService) GetAllSplitsOfTransactions(c core.Context, uid int64, transactionIds []int64) (map[int64][]*models.TransactionSplit, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(transactionIds); i += pageCountForLoadTransactionAmounts {
+		endIndex := i + pageCountForLoadTransactionAmounts
+
+		if endIndex > len(transactionIds) {
+			endIndex = len(transactionIds)
+		}
+
+		var splits []*models.TransactionSplit
+		err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds[i:endIndex]).OrderBy("transaction_id asc, display_order asc").Find(&splits)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(splits); j++ {
+			split := splits[j]
+			allTransactionSplits[split.TransactionId] = append(allTransactionSplits[split.TransactionId], split)
+		}
+	}
+
+	return allTransactionSplits, nil
+}
+
+// FillTransactionSplits loads and sets the split lines of the specified transactions which have split lines
+func (s *TransactionService) FillTransactionSplits(c core.Context, uid int64, transactions []*models.Transaction) error {
+	splitTransactionIds := make([]int64, 0)
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].HasSplits {
+			splitTransactionIds = append(splitTransactionIds, transactions[i].TransactionId)
+		}
+	}
+
+	if len(splitTransactionIds) < 1 {
+		return nil
+	}
+
+	allTransactionSplits, err := s.GetAllSplitsOfTransactions(c, uid, splitTransactionIds)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(transactions); i++ {
+		if transactions[i].HasSplits {
+			transactions[i].Splits = allTransactionSplits[transactions[i].TransactionId]
+		}
+	}
+
+	return nil
+}
+
 // GetTransactionMapByList returns a transaction map by a list
 func (s *TransactionService) GetTransactionMapByList(transactions []*models.Transaction) map[int64]*models.Transaction {
 	transactionMap := make(map[int64]*models.Transaction)
@@ -1776,20 +2153,12 @@ func (s *TransactionService) GetTransactionIds(transactions []*models.Transactio
 
 func (s *TransactionService) doCreateTransaction(c core.Context, database *datastore.Database, sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo) error {
 	// Get and verify source and destination account
-	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
+	sourceAccount, destinationAccount, err := s.getAndVerifyAccountModelsForNewTransaction(sess, transaction)
 
 	if err != nil {
 		return err
 	}
 
-	if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
-		return errs.ErrCannotAddTransactionToHiddenAccount
-	}
-
-	if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
-		return errs.ErrCannotAddTransactionToParentAccount
-	}
-
 	if (transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN) &&
 		sourceAccount.Currency == destinationAccount.Currency && transaction.Amount != transaction.RelatedAccountAmount {
 		return errs.ErrTransactionSourceAndDestinationAmountNotEqual
@@ -1807,6 +2176,20 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		return err
 	}
 
+	// Get and verify split lines
+	err = s.isSplitsValid(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	// Get and verify payee
+	err = s.isPayeeValid(sess, transaction)
+
+	if err != nil {
+		return err
+	}
+
 	// Get and verify tags
 	err = s.isTagsValid(sess, transaction, transactionTagIndexes, tagIds)
 
@@ -1945,6 +2328,16 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 		}
 	}
 
+	// Insert transaction split lines
+	if transaction.HasSplits {
+		err = s.insertTransactionSplits(sess, transaction)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.doCreateTransaction] failed to add transaction split lines, because %s", err.Error())
+			return err
+		}
+	}
+
 	// Update transaction picture
 	if len(pictureIds) > 0 {
 		_, err = sess.Cols("transaction_id", "updated_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, models.TransactionPictureNewPictureTransactionId).In("picture_id", pictureIds).Update(pictureUpdateModel)
@@ -2028,7 +2421,29 @@ func (s *TransactionService) doCreateTransaction(c core.Context, database *datas
 	return err
 }
 
-func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagIds []int64, amountFilter string, keyword string, noDuplicated bool) (string, []any) {
+func (s *TransactionService) expandSplitTransactionAmounts(c core.Context, uid int64, transactions []*models.Transaction) ([]*models.Transaction, error) {
+	err := s.FillTransactionSplits(c, uid, transactions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expandedTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.HasSplits && len(transaction.Splits) > 0 {
+			expandedTransactions = append(expandedTransactions, models.GetSplitTransactionAmounts(transaction, transaction.Splits)...)
+		} else {
+			expandedTransactions = append(expandedTransactions, transaction)
+		}
+	}
+
+	return expandedTransactions, nil
+}
+
+func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransactionTime int64, minTransactionTime int64, transactionDbType models.TransactionDbType, categoryIds []int64, accountIds []int64, tagIds []int64, payeeIds []int64, amountFilter string, keyword string, searchQuery *models.TransactionSearchQueryNode, noDuplicated bool) (string, []any) {
 	condition := "uid=? AND deleted=?"
 	conditionParams := make([]any, 0, 16)
 	conditionParams = append(conditionParams, uid)
@@ -2096,22 +2511,9 @@ func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransa
 	}
 
 	if len(categoryIds) > 0 {
-		var conditions strings.Builder
-
-		for i := 0; i < len(categoryIds); i++ {
-			if i > 0 {
-				conditions.WriteString(",")
-			}
-
-			conditions.WriteString("?")
-			conditionParams = append(conditionParams, categoryIds[i])
-		}
-
-		if conditions.Len() > 1 {
-			condition = condition + " AND category_id IN (" + conditions.String() + ")"
-		} else {
-			condition = condition + " AND category_id = " + conditions.String()
-		}
+		categoryIdsCondition, categoryIdsConditionParams := s.getCategoryIdsCondition(uid, categoryIds)
+		condition = condition + " AND " + categoryIdsCondition
+		conditionParams = append(conditionParams, categoryIdsConditionParams...)
 	}
 
 	if len(accountIds) > 0 {
@@ -2124,6 +2526,12 @@ func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransa
 		conditionParams = append(conditionParams, accountIdConditionParams...)
 	}
 
+	if len(payeeIds) > 0 {
+		payeeIdsCondition, payeeIdsConditionParams := s.getPayeeIdsCondition(payeeIds)
+		condition = condition + " AND " + payeeIdsCondition
+		conditionParams = append(conditionParams, payeeIdsConditionParams...)
+	}
+
 	if amountFilter != "" {
 		amountFilterItems := strings.Split(amountFilter, ":")
 
@@ -2181,9 +2589,144 @@ func (s *TransactionService) buildTransactionQueryCondition(uid int64, maxTransa
 		conditionParams = append(conditionParams, "%%"+keyword+"%%")
 	}
 
+	if searchQuery != nil {
+		searchQueryCondition, searchQueryConditionParams := s.buildSearchQueryCondition(uid, searchQuery)
+		condition = condition + " AND " + searchQueryCondition
+		conditionParams = append(conditionParams, searchQueryConditionParams...)
+	}
+
 	return condition, conditionParams
 }
 
+func (s *TransactionService) getCategoryIdsCondition(uid int64, categoryIds []int64) (string, []any) {
+	categoryIdsCondition, categoryIdsConditionParams := s.getIdsCondition("category_id", categoryIds)
+	subQuery := "SELECT transaction_id FROM ebk_transaction_splits WHERE uid=? AND deleted=? AND " + categoryIdsCondition
+	conditionParams := make([]any, 0, 2*len(categoryIdsConditionParams)+2)
+	conditionParams = append(conditionParams, categoryIdsConditionParams...)
+	conditionParams = append(conditionParams, uid, false)
+	conditionParams = append(conditionParams, categoryIdsConditionParams...)
+	return "(" + categoryIdsCondition + " OR transaction_id IN (" + subQuery + "))", conditionParams
+}
+
+func (s *TransactionService) getPayeeIdsCondition(payeeIds []int64) (string, []any) {
+	return s.getIdsCondition("payee_id", payeeIds)
+}
+
+func (s *TransactionService) getIdsCondition(columnName string, ids []int64) (string, []any) {
+	var conditions strings.Builder
+	conditionParams := make([]any, 0, len(ids))
+
+	for i := 0; i < len(ids); i++ {
+		if i > 0 {
+			conditions.WriteString(",")
+		}
+
+		conditions.WriteString("?")
+		conditionParams = append(conditionParams, ids[i])
+	}
+
+	if len(ids) > 1 {
+		return columnName + " IN (" + conditions.String() + ")", conditionParams
+	} else {
+		return columnName + " = " + conditions.String(), conditionParams
+	}
+}
+
+func (s *TransactionService) getCommentMatchCondition(uid int64, keyword string) (string, []any) {
+	condition, conditionParams, ok := s.UserDataDB(uid).GetFullTextMatchCondition(&models.Transaction{}, "comment", keyword)
+
+	if ok {
+		return condition, conditionParams
+	}
+
+	return "comment LIKE ?", []any{"%" + keyword + "%"}
+}
+
+func (s *TransactionService) buildSearchQueryCondition(uid int64, node *models.TransactionSearchQueryNode) (string, []any) {
+	if node.Type == models.TRANSACTION_SEARCH_QUERY_NODE_TYPE_AND || node.Type == models.TRANSACTION_SEARCH_QUERY_NODE_TYPE_OR {
+		separator := " AND "
+
+		if node.Type == models.TRANSACTION_SEARCH_QUERY_NODE_TYPE_OR {
+			separator = " OR "
+		}
+
+		conditions := make([]string, len(node.Children))
+		conditionParams := make([]any, 0, len(node.Children))
+
+		for i := 0; i < len(node.Children); i++ {
+			childCondition, childConditionParams := s.buildSearchQueryCondition(uid, node.Children[i])
+			conditions[i] = childCondition
+			conditionParams = append(conditionParams, childConditionParams...)
+		}
+
+		return "(" + strings.Join(conditions, separator) + ")", conditionParams
+	} else if node.Type == models.TRANSACTION_SEARCH_QUERY_NODE_TYPE_NOT {
+		childCondition, childConditionParams := s.buildSearchQueryCondition(uid, node.Children[0])
+		return "NOT (" + childCondition + ")", childConditionParams
+	}
+
+	switch node.Field {
+	case models.TRANSACTION_SEARCH_QUERY_FIELD_COMMENT:
+		condition, conditionParams := s.getCommentMatchCondition(uid, node.Value)
+		return "(" + condition + ")", conditionParams
+	case models.TRANSACTION_SEARCH_QUERY_FIELD_AMOUNT:
+		switch node.Operator {
+		case models.TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN:
+			return "amount > ?", []any{node.Amount}
+		case models.TRANSACTION_SEARCH_QUERY_OPERATOR_GREATER_THAN_OR_EQUAL:
+			return "amount >= ?", []any{node.Amount}
+		case models.TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN:
+			return "amount < ?", []any{node.Amount}
+		case models.TRANSACTION_SEARCH_QUERY_OPERATOR_LESS_THAN_OR_EQUAL:
+			return "amount <= ?", []any{node.Amount}
+		case models.TRANSACTION_SEARCH_QUERY_OPERATOR_BETWEEN:
+			return "(amount >= ? AND amount <= ?)", []any{node.Amount, node.Amount2}
+		default:
+			return "amount = ?", []any{node.Amount}
+		}
+	case models.TRANSACTION_SEARCH_QUERY_FIELD_TYPE:
+		transactionType := node.GetTransactionType()
+
+		if transactionType == models.TRANSACTION_TYPE_TRANSFER {
+			return "(type = ? OR type = ?)", []any{models.TRANSACTION_DB_TYPE_TRANSFER_OUT, models.TRANSACTION_DB_TYPE_TRANSFER_IN}
+		}
+
+		transactionDbType, err := transactionType.ToTransactionDbType()
+
+		if err != nil {
+			return "1 = 0", nil
+		}
+
+		return "type = ?", []any{transactionDbType}
+	}
+
+	if len(node.Ids) < 1 {
+		return "1 = 0", nil
+	}
+
+	switch node.Field {
+	case models.TRANSACTION_SEARCH_QUERY_FIELD_ACCOUNT:
+		accountIdsCondition, accountIdsConditionParams := s.getIdsCondition("account_id", node.Ids)
+		relatedAccountIdsCondition, relatedAccountIdsConditionParams := s.getIdsCondition("related_account_id", node.Ids)
+		return "(" + accountIdsCondition + " OR " + relatedAccountIdsCondition + ")", append(accountIdsConditionParams, relatedAccountIdsConditionParams...)
+	case models.TRANSACTION_SEARCH_QUERY_FIELD_CATEGORY:
+		return s.getCategoryIdsCondition(uid, node.Ids)
+	case models.TRANSACTION_SEARCH_QUERY_FIELD_PAYEE:
+		return s.getIdsCondition("payee_id", node.Ids)
+	case models.TRANSACTION_SEARCH_QUERY_FIELD_TAG:
+		tagIdsCondition, tagIdsConditionParams := s.getIdsCondition("tag_id", node.Ids)
+		subQuery := "SELECT transaction_id FROM transaction_tag_index WHERE uid=? AND deleted=? AND " + tagIdsCondition
+		conditionParams := make([]any, 0, 2*(len(tagIdsConditionParams)+2))
+		conditionParams = append(conditionParams, uid, false)
+		conditionParams = append(conditionParams, tagIdsConditionParams...)
+		conditionParams = append(conditionParams, uid, false)
+		conditionParams = append(conditionParams, tagIdsConditionParams...)
+		return "(transaction_id IN (" + subQuery + ") OR related_id IN (" + subQuery + "))", conditionParams
+	}
+
+	return "1 = 0", nil
+}
+
 func (s *TransactionService) appendFilterTagIdsConditionToQuery(sess *xorm.Session, uid int64, maxTransactionTime int64, minTransactionTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType) *xorm.Session {
 	subQueryCondition := builder.And(builder.Eq{"uid": uid}, builder.Eq{"deleted": false})
 
@@ -2246,6 +2789,24 @@ func (s *TransactionService) isAccountIdValid(transaction *models.Transaction) e
 	return nil
 }
 
+func (s *TransactionService) getAndVerifyAccountModelsForNewTransaction(sess *xorm.Session, transaction *models.Transaction) (*models.Account, *models.Account, error) {
+	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if sourceAccount.Hidden || (destinationAccount != nil && destinationAccount.Hidden) {
+		return nil, nil, errs.ErrCannotAddTransactionToHiddenAccount
+	}
+
+	if sourceAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS || (destinationAccount != nil && destinationAccount.Type == models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS) {
+		return nil, nil, errs.ErrCannotAddTransactionToParentAccount
+	}
+
+	return sourceAccount, destinationAccount, nil
+}
+
 func (s *TransactionService) getAccountModels(sess *xorm.Session, transaction *models.Transaction) (sourceAccount *models.Account, destinationAccount *models.Account, err error) {
 	sourceAccount = &models.Account{}
 	destinationAccount = &models.Account{}
@@ -2386,6 +2947,54 @@ func (s *TransactionService) getRelatedUpdateColumns(updateCols []string) []stri
 	return relatedUpdateCols
 }
 
+func (s *TransactionService) prepareTransactionSplits(transaction *models.Transaction, now int64) error {
+	transaction.HasSplits = len(transaction.Splits) > 0
+
+	if !transaction.HasSplits {
+		return nil
+	}
+
+	if len(transaction.Splits) > models.TransactionSplitMaxCount {
+		return errs.ErrTransactionSplitCountInvalid
+	}
+
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION_SPLIT, uint16(len(transaction.Splits)))
+
+	if len(splitUuids) < len(transaction.Splits) {
+		return errs.ErrSystemIsBusy
+	}
+
+	// The category of the first split line is used as the category of the parent transaction
+	transaction.CategoryId = transaction.Splits[0].CategoryId
+
+	for i := 0; i < len(transaction.Splits); i++ {
+		split := transaction.Splits[i]
+		split.SplitId = splitUuids[i]
+		split.Uid = transaction.Uid
+		split.Deleted = false
+		split.DisplayOrder = int32(i + 1)
+		split.CreatedUnixTime = now
+		split.UpdatedUnixTime = now
+	}
+
+	return nil
+}
+
+func (s *TransactionService) insertTransactionSplits(sess *xorm.Session, transaction *models.Transaction) error {
+	for i := 0; i < len(transaction.Splits); i++ {
+		split := transaction.Splits[i]
+		split.TransactionId = transaction.TransactionId
+
+		_, err := sess.Insert(split)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *models.Transaction) error {
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		if transaction.CategoryId != 0 {
@@ -2432,6 +3041,83 @@ func (s *TransactionService) isCategoryValid(sess *xorm.Session, transaction *mo
 	return nil
 }
 
+func (s *TransactionService) isPayeeValid(sess *xorm.Session, transaction *models.Transaction) error {
+	if transaction.PayeeId == 0 {
+		return nil
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		return errs.ErrPayeeIdInvalid
+	}
+
+	payee := &models.Payee{}
+	has, err := sess.ID(transaction.PayeeId).Where("uid=? AND deleted=?", transaction.Uid, false).Get(payee)
+
+	if err != nil {
+		return err
+	} else if !has {
+		return errs.ErrPayeeNotFound
+	}
+
+	if payee.Hidden {
+		return errs.ErrCannotUseHiddenPayee
+	}
+
+	return nil
+}
+
+func (s *TransactionService) isSplitsValid(sess *xorm.Session, transaction *models.Transaction) error {
+	if len(transaction.Splits) < 1 {
+		return nil
+	}
+
+	err := transaction.ValidateSplits()
+
+	if err != nil {
+		return err
+	}
+
+	allTagIds := make([]int64, 0)
+
+	for i := 0; i < len(transaction.Splits); i++ {
+		split := transaction.Splits[i]
+		err = s.isCategoryValid(sess, &models.Transaction{
+			Uid:        transaction.Uid,
+			Type:       transaction.Type,
+			CategoryId: split.CategoryId,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		allTagIds = append(allTagIds, split.GetTagIds()...)
+	}
+
+	allTagIds = utils.ToUniqueInt64Slice(allTagIds)
+
+	if len(allTagIds) > 0 {
+		var tags []*models.TransactionTag
+		err = sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("tag_id", allTagIds).Find(&tags)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(tags); i++ {
+			if tags[i].Hidden {
+				return errs.ErrCannotUseHiddenTransactionTag
+			}
+		}
+
+		if len(tags) < len(allTagIds) {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	return nil
+}
+
 func (s *TransactionService) isTagsValid(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64) error {
 	if len(transactionTagIndexes) > 0 {
 		var tags []*models.TransactionTag
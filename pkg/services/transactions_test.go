@@ -0,0 +1,150 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+func initializeTransactionServiceTestDataStore(t *testing.T) core.Context {
+	config := &settings.Config{
+		DatabaseConfig: &settings.DatabaseConfig{
+			DatabaseType: settings.Sqlite3DbType,
+			DatabasePath: filepath.Join(t.TempDir(), "ezbookkeeping.db"),
+		},
+		UuidGeneratorType: settings.InternalUuidGeneratorType,
+	}
+
+	assert.Nil(t, datastore.InitializeDataStore(config))
+	assert.Nil(t, uuid.InitializeUuidGenerator(config))
+
+	for _, bean := range []any{new(models.Account), new(models.Transaction), new(models.TransactionSplit), new(models.TransactionTagIndex)} {
+		assert.Nil(t, datastore.Container.UserDataStore.SyncStructs(bean))
+	}
+
+	return core.NewNullContext()
+}
+
+func insertTestSplitTransaction(t *testing.T, c core.Context) {
+	now := time.Now().Unix()
+	sess := Transactions.UserDataDB(1).NewSession(c)
+
+	_, err := sess.Insert(&models.Account{
+		AccountId:       101,
+		Uid:             1,
+		Category:        models.ACCOUNT_CATEGORY_CASH,
+		Type:            models.ACCOUNT_TYPE_SINGLE_ACCOUNT,
+		Name:            "Cash",
+		Currency:        "USD",
+		Balance:         -1000,
+		CreatedUnixTime: now,
+		UpdatedUnixTime: now,
+	})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert(&models.Transaction{
+		TransactionId:   1001,
+		Uid:             1,
+		Type:            models.TRANSACTION_DB_TYPE_EXPENSE,
+		CategoryId:      201,
+		TransactionTime: 1700000000000,
+		AccountId:       101,
+		Amount:          1000,
+		HasSplits:       true,
+		Comment:         "shopping",
+		CreatedUnixTime: now,
+		UpdatedUnixTime: now,
+	})
+	assert.Nil(t, err)
+
+	_, err = sess.Insert([]*models.TransactionSplit{
+		{SplitId: 3001, Uid: 1, TransactionId: 1001, CategoryId: 201, Amount: 600, Comment: "food", DisplayOrder: 1, CreatedUnixTime: now, UpdatedUnixTime: now},
+		{SplitId: 3002, Uid: 1, TransactionId: 1001, CategoryId: 202, Amount: 400, Comment: "drink", DisplayOrder: 2, CreatedUnixTime: now, UpdatedUnixTime: now},
+	})
+	assert.Nil(t, err)
+}
+
+func TestTransactionServiceModifyTransaction_KeepSplitsWhenSplitsNotSpecified(t *testing.T) {
+	c := initializeTransactionServiceTestDataStore(t)
+	insertTestSplitTransaction(t, c)
+
+	err := Transactions.ModifyTransaction(c, &models.Transaction{
+		TransactionId:   1001,
+		Uid:             1,
+		CategoryId:      201,
+		TransactionTime: 1700000000000,
+		AccountId:       101,
+		Amount:          1000,
+		Comment:         "weekly shopping",
+	}, 0, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1, 1001)
+	assert.Nil(t, err)
+	assert.Equal(t, "weekly shopping", transaction.Comment)
+	assert.True(t, transaction.HasSplits)
+
+	allSplits, err := Transactions.GetAllSplitsOfTransactions(c, 1, []int64{1001})
+	assert.Nil(t, err)
+
+	splits := allSplits[1001]
+	assert.Equal(t, 2, len(splits))
+	assert.Equal(t, int64(3001), splits[0].SplitId)
+	assert.Equal(t, int64(600), splits[0].Amount)
+	assert.Equal(t, int64(3002), splits[1].SplitId)
+	assert.Equal(t, int64(400), splits[1].Amount)
+}
+
+func TestTransactionServiceModifyTransaction_KeepSplitsAndChangeAmount(t *testing.T) {
+	c := initializeTransactionServiceTestDataStore(t)
+	insertTestSplitTransaction(t, c)
+
+	err := Transactions.ModifyTransaction(c, &models.Transaction{
+		TransactionId:   1001,
+		Uid:             1,
+		CategoryId:      201,
+		TransactionTime: 1700000000000,
+		AccountId:       101,
+		Amount:          1200,
+		Comment:         "shopping",
+	}, 0, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionSplitsAmountNotEqual.Message)
+
+	allSplits, err := Transactions.GetAllSplitsOfTransactions(c, 1, []int64{1001})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allSplits[1001]))
+}
+
+func TestTransactionServiceModifyTransaction_RemoveSplitsWhenSplitsEmpty(t *testing.T) {
+	c := initializeTransactionServiceTestDataStore(t)
+	insertTestSplitTransaction(t, c)
+
+	err := Transactions.ModifyTransaction(c, &models.Transaction{
+		TransactionId:   1001,
+		Uid:             1,
+		CategoryId:      201,
+		TransactionTime: 1700000000000,
+		AccountId:       101,
+		Amount:          1000,
+		Comment:         "shopping",
+		Splits:          make([]*models.TransactionSplit, 0),
+	}, 0, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	transaction, err := Transactions.GetTransactionByTransactionId(c, 1, 1001)
+	assert.Nil(t, err)
+	assert.False(t, transaction.HasSplits)
+
+	allSplits, err := Transactions.GetAllSplitsOfTransactions(c, 1, []int64{1001})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(allSplits[1001]))
+}
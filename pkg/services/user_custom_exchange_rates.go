@@ -129,6 +129,93 @@ func (s *UserCustomExchangeRatesService) DeleteCustomExchangeRate(c core.Context
 	})
 }
 
+// GetAllDatedCustomExchangeRatesByUid returns all user dated exchange rate data models of user ordered by effective date, or only the models of the specified currency if currency is not empty
+func (s *UserCustomExchangeRatesService) GetAllDatedCustomExchangeRatesByUid(c core.Context, uid int64, currency string) ([]*models.UserDatedCustomExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	condition := "uid=? AND deleted_unix_time=?"
+	conditionParams := make([]any, 0, 3)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, 0)
+
+	if currency != "" {
+		condition = condition + " AND currency=?"
+		conditionParams = append(conditionParams, currency)
+	}
+
+	var datedCustomExchangeRates []*models.UserDatedCustomExchangeRate
+	err := s.UserDataDB(uid).NewSession(c).Where(condition, conditionParams...).OrderBy("currency asc, effective_date asc").Find(&datedCustomExchangeRates)
+
+	return datedCustomExchangeRates, err
+}
+
+// UpdateDatedCustomExchangeRate updates user dated exchange rate data model of the specified effective date to database
+func (s *UserCustomExchangeRatesService) UpdateDatedCustomExchangeRate(c core.Context, uid int64, currency string, effectiveDate string, rate string, baseCurrency string) (*models.UserDatedCustomExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	newDatedCustomExchangeRate, err := models.CreateUserDatedCustomExchangeRate(uid, currency, effectiveDate, rate, baseCurrency)
+
+	if err != nil || newDatedCustomExchangeRate.Rate <= 0 {
+		return nil, errs.ErrUserCustomExchangeRateInvalid
+	}
+
+	now := time.Now().Unix()
+	newDatedCustomExchangeRate.CreatedUnixTime = now
+	newDatedCustomExchangeRate.UpdatedUnixTime = now
+	newDatedCustomExchangeRate.DeletedUnixTime = 0
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updateOldExchangeRateModel := &models.UserDatedCustomExchangeRate{
+			DeletedUnixTime: now,
+		}
+
+		_, err := sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=? AND currency=? AND effective_date=?", uid, 0, currency, effectiveDate).Update(updateOldExchangeRateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(newDatedCustomExchangeRate)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newDatedCustomExchangeRate, nil
+}
+
+// DeleteDatedCustomExchangeRate deletes an existed user dated exchange rate data from database
+func (s *UserCustomExchangeRatesService) DeleteDatedCustomExchangeRate(c core.Context, uid int64, currency string, effectiveDate string) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.UserDatedCustomExchangeRate{
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=? AND currency=? AND effective_date=?", uid, 0, currency, effectiveDate).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrUserCustomExchangeRateNotFound
+		}
+
+		return err
+	})
+}
+
 // DeleteAllCustomExchangeRates deletes all existed user exchange rate data from database
 func (s *UserCustomExchangeRatesService) DeleteAllCustomExchangeRates(c core.Context, uid int64) error {
 	if uid <= 0 {
@@ -148,6 +235,16 @@ func (s *UserCustomExchangeRatesService) DeleteAllCustomExchangeRates(c core.Con
 			return err
 		}
 
+		updateDatedModel := &models.UserDatedCustomExchangeRate{
+			DeletedUnixTime: now,
+		}
+
+		_, err = sess.Cols("deleted_unix_time").Where("uid=? AND deleted_unix_time=?", uid, 0).Update(updateDatedModel)
+
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+// UserExternalAuthService represents user external authentication service
+type UserExternalAuthService struct {
+	ServiceUsingDB
+}
+
+// Initialize a user external authentication service singleton instance
+var (
+	UserExternalAuths = &UserExternalAuthService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetUserExternalAuthByExternalUserId returns the user external authentication model according to external authentication type and external user id
+func (s *UserExternalAuthService) GetUserExternalAuthByExternalUserId(c core.Context, externalAuthType models.UserExternalAuthType, externalUserId string) (*models.UserExternalAuth, error) {
+	if externalUserId == "" {
+		return nil, errs.ErrOIDCUserInfoInvalid
+	}
+
+	userExternalAuth := &models.UserExternalAuth{}
+	has, err := s.UserDB().NewSession(c).Where("external_auth_type=? AND external_user_id=?", externalAuthType, externalUserId).Get(userExternalAuth)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrExternalAuthUserNotLinked
+	}
+
+	return userExternalAuth, nil
+}
+
+// GetAllUserExternalAuthsByUid returns all user external authentication models of given user
+func (s *UserExternalAuthService) GetAllUserExternalAuthsByUid(c core.Context, uid int64) ([]*models.UserExternalAuth, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var userExternalAuths []*models.UserExternalAuth
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("external_auth_type asc").Find(&userExternalAuths)
+
+	return userExternalAuths, err
+}
+
+// CreateUserExternalAuth saves a new user external authentication model to database
+func (s *UserExternalAuthService) CreateUserExternalAuth(c core.Context, userExternalAuth *models.UserExternalAuth) error {
+	if userExternalAuth.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if userExternalAuth.ExternalUserId == "" {
+		return errs.ErrOIDCUserInfoInvalid
+	}
+
+	userExternalAuth.CreatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid").Where("external_auth_type=? AND external_user_id=?", userExternalAuth.ExternalAuthType, userExternalAuth.ExternalUserId).Exist(&models.UserExternalAuth{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrExternalAuthAlreadyLinked
+		}
+
+		exists, err = sess.Cols("uid").Where("uid=? AND external_auth_type=?", userExternalAuth.Uid, userExternalAuth.ExternalAuthType).Exist(&models.UserExternalAuth{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrUserExternalAuthAlreadyExists
+		}
+
+		_, err = sess.Insert(userExternalAuth)
+		return err
+	})
+}
+
+// DeleteUserExternalAuth deletes an existed user external authentication from database
+func (s *UserExternalAuthService) DeleteUserExternalAuth(c core.Context, uid int64, externalAuthType models.UserExternalAuthType) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("uid=? AND external_auth_type=?", uid, externalAuthType).Delete(&models.UserExternalAuth{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrUserExternalAuthNotFound
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// UserWebAuthnCredentialService represents user passkey (webauthn credential) service
+type UserWebAuthnCredentialService struct {
+	ServiceUsingDB
+}
+
+// Initialize a user passkey service singleton instance
+var (
+	UserWebAuthnCredentials = &UserWebAuthnCredentialService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetAllCredentialsByUid returns all passkeys of given user
+func (s *UserWebAuthnCredentialService) GetAllCredentialsByUid(c core.Context, uid int64) ([]*models.UserWebAuthnCredential, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var credentials []*models.UserWebAuthnCredential
+	err := s.UserDB().NewSession(c).Where("uid=?", uid).OrderBy("created_unix_time asc").Find(&credentials)
+
+	return credentials, err
+}
+
+// GetCredentialByCredentialId returns the passkey model according to user uid and passkey id
+func (s *UserWebAuthnCredentialService) GetCredentialByCredentialId(c core.Context, uid int64, credentialId int64) (*models.UserWebAuthnCredential, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if credentialId <= 0 {
+		return nil, errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	credential := &models.UserWebAuthnCredential{}
+	has, err := s.UserDB().NewSession(c).Where("uid=? AND credential_id=?", uid, credentialId).Get(credential)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrWebAuthnCredentialNotFound
+	}
+
+	return credential, nil
+}
+
+// ExistsCredential returns whether the given user has registered any passkey
+func (s *UserWebAuthnCredentialService) ExistsCredential(c core.Context, uid int64) (bool, error) {
+	if uid <= 0 {
+		return false, errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().NewSession(c).Cols("uid").Where("uid=?", uid).Exist(&models.UserWebAuthnCredential{})
+}
+
+// CreateCredential saves a new passkey model to database
+func (s *UserWebAuthnCredentialService) CreateCredential(c core.Context, credential *models.UserWebAuthnCredential) error {
+	if credential.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if credential.RawCredentialId == "" || len(credential.PublicKey) < 1 {
+		return errs.ErrWebAuthnCredentialInvalid
+	}
+
+	credential.CredentialId = s.getCredentialId()
+	credential.CreatedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		count, err := sess.Where("uid=?", credential.Uid).Count(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if count >= models.WebAuthnCredentialMaxCountPerUser {
+			return errs.ErrWebAuthnCredentialCountLimitReached
+		}
+
+		exists, err := sess.Cols("uid").Where("uid=? AND raw_credential_id=?", credential.Uid, credential.RawCredentialId).Exist(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrWebAuthnCredentialAlreadyExists
+		}
+
+		_, err = sess.Insert(credential)
+		return err
+	})
+}
+
+// ModifyCredentialName saves the new name of an existed passkey to database
+func (s *UserWebAuthnCredentialService) ModifyCredentialName(c core.Context, uid int64, credentialId int64, name string) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if credentialId <= 0 {
+		return errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	updateModel := &models.UserWebAuthnCredential{
+		Name: name,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.Cols("name").Where("uid=? AND credential_id=?", uid, credentialId).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrWebAuthnCredentialNotFound
+		}
+
+		return nil
+	})
+}
+
+// UpdateCredentialLastUsed saves the latest sign counter, flags and last used time of an existed passkey to database only if the sign counter is increased
+func (s *UserWebAuthnCredentialService) UpdateCredentialLastUsed(c core.Context, credential *models.UserWebAuthnCredential) error {
+	if credential.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if credential.CredentialId <= 0 {
+		return errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	credential.LastUsedUnixTime = time.Now().Unix()
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		sess = sess.Cols("sign_count", "flags", "last_used_unix_time").Where("uid=? AND credential_id=?", credential.Uid, credential.CredentialId)
+
+		// authenticators which do not support sign counter always return zero, otherwise the sign counter must be increased,
+		// and the condition makes concurrent logins with the same sign counter only succeed once
+		if credential.SignCount > 0 {
+			sess = sess.And("sign_count<?", credential.SignCount)
+		} else {
+			sess = sess.And("sign_count=?", 0)
+		}
+
+		updatedRows, err := sess.Update(credential)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrWebAuthnSignCountInvalid
+		}
+
+		return nil
+	})
+}
+
+// DeleteCredential deletes an existed passkey from database
+func (s *UserWebAuthnCredentialService) DeleteCredential(c core.Context, uid int64, credentialId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if credentialId <= 0 {
+		return errs.ErrWebAuthnCredentialIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("uid=? AND credential_id=?", uid, credentialId).Delete(&models.UserWebAuthnCredential{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrWebAuthnCredentialNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteAllCredentials deletes all existed passkeys of given user from database
+func (s *UserWebAuthnCredentialService) DeleteAllCredentials(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("uid=?", uid).Delete(&models.UserWebAuthnCredential{})
+		return err
+	})
+}
+
+// CreateChallenge saves the challenge of a new passkey ceremony to database and removes all expired challenges
+func (s *UserWebAuthnCredentialService) CreateChallenge(c core.Context, uid int64, purpose string, challenge string, expiredUnixTime int64) error {
+	if uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if challenge == "" || purpose == "" {
+		return errs.ErrWebAuthnSessionInvalid
+	}
+
+	now := time.Now().Unix()
+	challengeModel := &models.UserWebAuthnChallenge{
+		Challenge:       challenge,
+		Uid:             uid,
+		Purpose:         purpose,
+		CreatedUnixTime: now,
+		ExpiredUnixTime: expiredUnixTime,
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Where("expired_unix_time<?", now).Delete(&models.UserWebAuthnChallenge{})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Insert(challengeModel)
+		return err
+	})
+}
+
+// ConsumeChallenge deletes the challenge of a pending passkey ceremony from database, returns error if the challenge does not exist, has been used or has expired
+func (s *UserWebAuthnCredentialService) ConsumeChallenge(c core.Context, uid int64, purpose string, challenge string) error {
+	if uid < 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	if challenge == "" || purpose == "" {
+		return errs.ErrWebAuthnSessionInvalid
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.Where("challenge=? AND uid=? AND purpose=? AND expired_unix_time>=?", challenge, uid, purpose, time.Now().Unix()).Delete(&models.UserWebAuthnChallenge{})
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrWebAuthnSessionInvalid
+		}
+
+		return nil
+	})
+}
+
+func (s *UserWebAuthnCredentialService) getCredentialId() int64 {
+	nanoSeconds := time.Now().Nanosecond()
+	randomNumber, _ := utils.GetRandomInteger(math.MaxInt32)
+	credentialId := (int64(nanoSeconds) << 32) | int64(randomNumber)
+
+	return credentialId
+}
@@ -11,6 +11,7 @@ import (
 
 	"xorm.io/xorm"
 
+	"github.com/mayswind/ezbookkeeping/pkg/auth"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/datastore"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -61,6 +62,23 @@ var (
 func (s *UserService) GetUserByUsernameOrEmailAndPassword(c core.Context, loginname string, password string) (*models.User, int64, error) {
 	var user *models.User
 	var err error
+	var ldapErr error
+
+	if auth.Container.IsLDAPEnabled() {
+		user, err = s.getUserByLDAPAccount(c, loginname, password)
+
+		if err == nil {
+			return user, user.Uid, nil
+		} else if !auth.Container.CanFallbackToLocalPasswordAuth(err, false) {
+			return nil, 0, err
+		}
+
+		ldapErr = err
+
+		if err != errs.ErrLDAPUserNotFound {
+			log.Warnf(c, "[users.GetUserByUsernameOrEmailAndPassword] ldap authentication for \"%s\" failed, fallback to local password authentication, because %s", loginname, err.Error())
+		}
+	}
 
 	if utils.IsValidUsername(loginname) {
 		user, err = s.GetUserByUsername(c, loginname)
@@ -70,6 +88,10 @@ func (s *UserService) GetUserByUsernameOrEmailAndPassword(c core.Context, loginn
 		err = errs.ErrLoginNameInvalid
 	}
 
+	if (err == errs.ErrUserNotFound || (err == nil && user == nil)) && ldapErr != nil && ldapErr != errs.ErrLDAPUserNotFound {
+		return nil, 0, ldapErr
+	}
+
 	if err != nil {
 		return nil, 0, err
 	}
@@ -78,6 +100,20 @@ func (s *UserService) GetUserByUsernameOrEmailAndPassword(c core.Context, loginn
 		return nil, 0, errs.ErrUserNotFound
 	}
 
+	// the user linked to ldap user cannot login with local password after the ldap user is removed from the directory
+	if ldapErr == errs.ErrLDAPUserNotFound {
+		linkedToLDAP, err := s.UserDB().NewSession(c).Cols("uid").Where("uid=? AND external_auth_type=?", user.Uid, models.USER_EXTERNAL_AUTH_TYPE_LDAP).Exist(&models.UserExternalAuth{})
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if !auth.Container.CanFallbackToLocalPasswordAuth(ldapErr, linkedToLDAP) {
+			log.Warnf(c, "[users.GetUserByUsernameOrEmailAndPassword] user \"uid:%d\" is linked to ldap user but ldap user \"%s\" is not found", user.Uid, loginname)
+			return nil, user.Uid, ldapErr
+		}
+	}
+
 	if !s.IsPasswordEqualsUserPassword(password, user) {
 		return nil, user.Uid, errs.ErrUserPasswordWrong
 	}
@@ -85,6 +121,167 @@ func (s *UserService) GetUserByUsernameOrEmailAndPassword(c core.Context, loginn
 	return user, user.Uid, nil
 }
 
+// getUserByLDAPAccount verifies the login name and password by LDAP server and returns the linked user model
+func (s *UserService) getUserByLDAPAccount(c core.Context, loginname string, password string) (*models.User, error) {
+	userInfo, err := auth.Container.AuthenticateLDAPUser(c, loginname, password)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userInfo.Username == "" {
+		log.Warnf(c, "[users.getUserByLDAPAccount] ldap user \"%s\" does not have username attribute", userInfo.DN)
+		return nil, errs.ErrLDAPUserInfoInvalid
+	}
+
+	var user *models.User
+	userExternalAuth := &models.UserExternalAuth{}
+	has, err := s.UserDB().NewSession(c).Where("external_auth_type=? AND external_user_id=?", models.USER_EXTERNAL_AUTH_TYPE_LDAP, userInfo.Username).Get(userExternalAuth)
+
+	if err != nil {
+		return nil, err
+	} else if has {
+		user, err = s.GetUserById(c, userExternalAuth.Uid)
+
+		if err != nil {
+			log.Warnf(c, "[users.getUserByLDAPAccount] failed to get user \"uid:%d\" linked to ldap user \"%s\", because %s", userExternalAuth.Uid, userInfo.Username, err.Error())
+			return nil, err
+		}
+	}
+
+	if user == nil && s.CurrentConfig().LDAPLinkUserByUsername && utils.IsValidUsername(userInfo.Username) {
+		user, err = s.GetUserByUsername(c, userInfo.Username)
+
+		if err != nil && err != errs.ErrUserNotFound {
+			return nil, err
+		}
+
+		if user != nil {
+			err = s.createLDAPUserExternalAuth(c, user.Uid, userInfo)
+
+			if err != nil {
+				log.Warnf(c, "[users.getUserByLDAPAccount] failed to link ldap user \"%s\" to user \"uid:%d\", because %s", userInfo.Username, user.Uid, err.Error())
+				return nil, err
+			}
+
+			log.Infof(c, "[users.getUserByLDAPAccount] ldap user \"%s\" has been linked to user \"uid:%d\" by username", userInfo.Username, user.Uid)
+		}
+	}
+
+	if user == nil && s.CurrentConfig().LDAPEnableAutoCreateUser {
+		user, err = s.createLDAPUser(c, userInfo)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if user == nil {
+		log.Warnf(c, "[users.getUserByLDAPAccount] ldap user \"%s\" is not linked to any user", userInfo.Username)
+		return nil, errs.ErrExternalAuthUserNotLinked
+	}
+
+	featureRestriction, needUpdate := auth.Container.GetLDAPUserFeatureRestrictions(userInfo)
+
+	if needUpdate && featureRestriction != user.FeatureRestriction {
+		updateModel := &models.User{
+			FeatureRestriction: featureRestriction,
+			UpdatedUnixTime:    time.Now().Unix(),
+		}
+
+		_, err = s.UserDB().NewSession(c).Cols("feature_restriction", "updated_unix_time").Where("uid=? AND deleted=?", user.Uid, false).Update(updateModel)
+
+		if err != nil {
+			log.Errorf(c, "[users.getUserByLDAPAccount] failed to update feature restrictions of user \"uid:%d\", because %s", user.Uid, err.Error())
+			return nil, err
+		}
+
+		log.Infof(c, "[users.getUserByLDAPAccount] feature restrictions of user \"uid:%d\" have been updated to \"%s\" according to ldap groups", user.Uid, featureRestriction)
+		user.FeatureRestriction = featureRestriction
+	}
+
+	return user, nil
+}
+
+func (s *UserService) createLDAPUser(c core.Context, userInfo *auth.LDAPUserInfo) (*models.User, error) {
+	username := userInfo.Username
+	email := userInfo.Email
+	nickname := userInfo.Nickname
+
+	if len(username) > 32 || !utils.IsValidUsername(username) || email == "" || len(email) > 100 || !utils.IsValidEmail(email) {
+		log.Warnf(c, "[users.createLDAPUser] cannot create user for ldap user \"%s\", because username \"%s\" or email \"%s\" is invalid", userInfo.DN, username, email)
+		return nil, errs.ErrLDAPUserInfoInvalid
+	}
+
+	if nickname == "" || len(nickname) > 64 {
+		nickname = username
+	}
+
+	password, err := utils.GetRandomString(32)
+
+	if err != nil {
+		log.Errorf(c, "[users.createLDAPUser] failed to generate random password, because %s", err.Error())
+		return nil, errs.ErrSystemError
+	}
+
+	// the email address is maintained by the directory administrator, so it is considered as verified
+	user := &models.User{
+		Username:             username,
+		Email:                email,
+		Nickname:             nickname,
+		Password:             password,
+		Language:             s.CurrentConfig().LDAPDefaultLanguage,
+		DefaultCurrency:      s.CurrentConfig().LDAPDefaultCurrency,
+		FirstDayOfWeek:       core.WEEKDAY_SUNDAY,
+		FiscalYearStart:      core.FISCAL_YEAR_START_DEFAULT,
+		TransactionEditScope: models.TRANSACTION_EDIT_SCOPE_ALL,
+		FeatureRestriction:   s.CurrentConfig().DefaultFeatureRestrictions,
+		EmailVerified:        true,
+	}
+
+	err = s.CreateUser(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[users.createLDAPUser] failed to create user \"%s\" for ldap user \"%s\", because %s", username, userInfo.DN, err.Error())
+		return nil, err
+	}
+
+	log.Infof(c, "[users.createLDAPUser] user \"%s\" has been created for ldap user \"%s\", uid is %d", user.Username, userInfo.DN, user.Uid)
+
+	err = s.createLDAPUserExternalAuth(c, user.Uid, userInfo)
+
+	if err != nil {
+		log.Errorf(c, "[users.createLDAPUser] failed to link ldap user \"%s\" to new user \"uid:%d\", because %s", userInfo.Username, user.Uid, err.Error())
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *UserService) createLDAPUserExternalAuth(c core.Context, uid int64, userInfo *auth.LDAPUserInfo) error {
+	userExternalAuth := &models.UserExternalAuth{
+		Uid:              uid,
+		ExternalAuthType: models.USER_EXTERNAL_AUTH_TYPE_LDAP,
+		ExternalUserId:   userInfo.Username,
+		ExternalUsername: utils.SubString(userInfo.DN, 0, 255),
+		ExternalEmail:    utils.SubString(userInfo.Email, 0, 100),
+		CreatedUnixTime:  time.Now().Unix(),
+	}
+
+	return s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		exists, err := sess.Cols("uid").Where("uid=? AND external_auth_type=?", uid, models.USER_EXTERNAL_AUTH_TYPE_LDAP).Exist(&models.UserExternalAuth{})
+
+		if err != nil {
+			return err
+		} else if exists {
+			return errs.ErrUserExternalAuthAlreadyExists
+		}
+
+		_, err = sess.Insert(userExternalAuth)
+		return err
+	})
+}
+
 // GetUserById returns the user model according to user uid
 func (s *UserService) GetUserById(c core.Context, uid int64) (*models.User, error) {
 	if uid <= 0 {
@@ -2,6 +2,7 @@ package settings
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -151,13 +152,35 @@ const (
 	defaultPasswordResetTokenExpiredTime uint32 = 3600    // 60 minutes
 	defaultMaxFailuresPerIpPerMinute     uint32 = 5
 	defaultMaxFailuresPerUserPerMinute   uint32 = 5
+	defaultAuditEventRetentionDays       uint32 = 180
 
 	defaultTransactionPictureFileMaxSize uint32 = 10485760 // 10MB
 	defaultUserAvatarFileMaxSize         uint32 = 1048576  // 1MB
 
+	defaultOIDCProviderName    string = "OpenID Connect"
+	defaultOIDCScopes          string = "openid profile email"
+	defaultOIDCUsernameClaim   string = "preferred_username"
+	defaultOIDCRequestTimeout  uint32 = 10000 // 10 seconds
+	defaultOIDCDefaultCurrency string = "USD"
+	defaultOIDCDefaultLanguage string = "en"
+
+	defaultLDAPUserFilter        string = "(&(objectClass=person)(uid=%s))"
+	defaultLDAPUsernameAttribute string = "uid"
+	defaultLDAPEmailAttribute    string = "mail"
+	defaultLDAPNicknameAttribute string = "cn"
+	defaultLDAPGroupAttribute    string = "memberOf"
+	defaultLDAPRequestTimeout    uint32 = 10000 // 10 seconds
+	defaultLDAPDefaultCurrency   string = "USD"
+	defaultLDAPDefaultLanguage   string = "en"
+
 	defaultImportFileMaxSize uint32 = 10485760 // 10MB
 
-	defaultExchangeRatesDataRequestTimeout uint32 = 10000 // 10 seconds
+	defaultExchangeRatesDataRequestTimeout uint32 = 10000  // 10 seconds
+	defaultExchangeRatesStaleDataMaxAge    uint32 = 604800 // 7 days
+
+	defaultCryptoPricesDataSourceUrl          string = "https://api.coingecko.com/api/v3"
+	defaultCryptoPricesApiKeyHeader           string = "x-cg-demo-api-key"
+	defaultInvestmentPricesDataRequestTimeout uint32 = 10000 // 10 seconds
 )
 
 // DatabaseConfig represents the database setting config
@@ -293,8 +316,12 @@ type Config struct {
 	DuplicateSubmissionsIntervalDuration            time.Duration
 
 	// Cron
-	EnableRemoveExpiredTokens        bool
-	EnableCreateScheduledTransaction bool
+	EnableRemoveExpiredTokens               bool
+	EnableCreateScheduledTransaction        bool
+	EnableRebuildTransactionSuggestionModel bool
+	EnableRemoveExpiredAuditEvents          bool
+	EnableUpdateExchangeRatesHistory        bool
+	EnablePostFixedIncomeInterest           bool
 
 	// Secret
 	SecretKeyNoSet                        bool
@@ -311,6 +338,12 @@ type Config struct {
 	PasswordResetTokenExpiredTimeDuration time.Duration
 	MaxFailuresPerIpPerMinute             uint32
 	MaxFailuresPerUserPerMinute           uint32
+	AuditEventRetentionDays               uint32
+	EnableWebAuthn                        bool
+	EnableWebAuthnPasswordlessLogin       bool
+	WebAuthnRPId                          string
+	WebAuthnRPDisplayName                 string
+	WebAuthnRPOrigins                     []string
 	EnableRequestIdHeader                 bool
 
 	// User
@@ -326,6 +359,39 @@ type Config struct {
 	MaxAvatarFileSize                uint32
 	DefaultFeatureRestrictions       core.UserFeatureRestrictions
 
+	// Auth
+	EnableOIDCAuth           bool
+	OIDCProviderName         string
+	OIDCIssuerUrl            string
+	OIDCClientId             string
+	OIDCClientSecret         string
+	OIDCScopes               []string
+	OIDCUsernameClaim        string
+	OIDCRequestTimeout       uint32
+	OIDCEnableAutoCreateUser bool
+	OIDCLinkUserByEmail      bool
+	OIDCDefaultCurrency      string
+	OIDCDefaultLanguage      string
+
+	EnableLDAPAuth               bool
+	LDAPServerUrl                string
+	LDAPStartTLS                 bool
+	LDAPSkipTLSVerify            bool
+	LDAPBindDN                   string
+	LDAPBindPassword             string
+	LDAPBaseDN                   string
+	LDAPUserFilter               string
+	LDAPUsernameAttribute        string
+	LDAPEmailAttribute           string
+	LDAPNicknameAttribute        string
+	LDAPGroupAttribute           string
+	LDAPGroupFeatureRestrictions map[string]core.UserFeatureRestrictions
+	LDAPRequestTimeout           uint32
+	LDAPEnableAutoCreateUser     bool
+	LDAPLinkUserByUsername       bool
+	LDAPDefaultCurrency          string
+	LDAPDefaultLanguage          string
+
 	// Data
 	EnableDataExport  bool
 	EnableDataImport  bool
@@ -363,6 +429,17 @@ type Config struct {
 	ExchangeRatesRequestTimeoutExceedDefaultValue bool
 	ExchangeRatesProxy                            string
 	ExchangeRatesSkipTLSVerify                    bool
+	ExchangeRatesFallbackDataSources              []string
+	EnableExchangeRatesCache                      bool
+	ExchangeRatesStaleDataMaxAge                  uint32
+
+	// Investment
+	CryptoPricesDataSourceUrl      string
+	CryptoPricesApiKey             string
+	CryptoPricesApiKeyHeader       string
+	InvestmentPricesRequestTimeout uint32
+	InvestmentPricesProxy          string
+	InvestmentPricesSkipTLSVerify  bool
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -456,6 +533,12 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	err = loadAuthConfiguration(config, cfgFile, "auth")
+
+	if err != nil {
+		return nil, err
+	}
+
 	err = loadDataConfiguration(config, cfgFile, "data")
 
 	if err != nil {
@@ -486,6 +569,12 @@ func LoadConfiguration(configFilePath string) (*Config, error) {
 		return nil, err
 	}
 
+	err = loadInvestmentConfiguration(config, cfgFile, "investment")
+
+	if err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -794,6 +883,10 @@ func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sec
 func loadCronConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableRemoveExpiredTokens = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_tokens", false)
 	config.EnableCreateScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_create_scheduled_transaction", false)
+	config.EnableRebuildTransactionSuggestionModel = getConfigItemBoolValue(configFile, sectionName, "enable_rebuild_transaction_suggestion_model", false)
+	config.EnableRemoveExpiredAuditEvents = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_audit_events", false)
+	config.EnableUpdateExchangeRatesHistory = getConfigItemBoolValue(configFile, sectionName, "enable_update_exchange_rates_history", false)
+	config.EnablePostFixedIncomeInterest = getConfigItemBoolValue(configFile, sectionName, "enable_post_fixed_income_interest", false)
 
 	return nil
 }
@@ -844,8 +937,27 @@ func loadSecurityConfiguration(config *Config, configFile *ini.File, sectionName
 	config.MaxFailuresPerIpPerMinute = getConfigItemUint32Value(configFile, sectionName, "max_failures_per_ip_per_minute", defaultMaxFailuresPerIpPerMinute)
 	config.MaxFailuresPerUserPerMinute = getConfigItemUint32Value(configFile, sectionName, "max_failures_per_user_per_minute", defaultMaxFailuresPerUserPerMinute)
 
+	config.AuditEventRetentionDays = getConfigItemUint32Value(configFile, sectionName, "audit_event_retention_days", defaultAuditEventRetentionDays)
+
 	config.EnableRequestIdHeader = getConfigItemBoolValue(configFile, sectionName, "request_id_header", true)
 
+	config.EnableWebAuthn = getConfigItemBoolValue(configFile, sectionName, "enable_webauthn", false)
+	config.EnableWebAuthnPasswordlessLogin = getConfigItemBoolValue(configFile, sectionName, "enable_webauthn_passwordless_login", true)
+	config.WebAuthnRPId = getConfigItemStringValue(configFile, sectionName, "webauthn_rp_id", config.Domain)
+	config.WebAuthnRPDisplayName = getConfigItemStringValue(configFile, sectionName, "webauthn_rp_display_name", config.AppName)
+
+	webAuthnRPOrigins := getConfigItemStringValue(configFile, sectionName, "webauthn_rp_origins")
+
+	if webAuthnRPOrigins != "" {
+		config.WebAuthnRPOrigins = strings.Split(strings.ReplaceAll(webAuthnRPOrigins, " ", ""), ",")
+	} else if rootUrl, err := url.Parse(config.RootUrl); err == nil && rootUrl.Scheme != "" && rootUrl.Host != "" {
+		config.WebAuthnRPOrigins = []string{rootUrl.Scheme + "://" + rootUrl.Host}
+	}
+
+	if config.EnableWebAuthn && (config.WebAuthnRPId == "" || len(config.WebAuthnRPOrigins) < 1) {
+		return errs.ErrInvalidWebAuthnConfig
+	}
+
 	return nil
 }
 
@@ -875,6 +987,76 @@ func loadUserConfiguration(config *Config, configFile *ini.File, sectionName str
 	return nil
 }
 
+func loadAuthConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	config.EnableOIDCAuth = getConfigItemBoolValue(configFile, sectionName, "enable_oidc", false)
+	config.OIDCProviderName = getConfigItemStringValue(configFile, sectionName, "oidc_provider_name", defaultOIDCProviderName)
+	config.OIDCIssuerUrl = getConfigItemStringValue(configFile, sectionName, "oidc_issuer_url")
+	config.OIDCClientId = getConfigItemStringValue(configFile, sectionName, "oidc_client_id")
+	config.OIDCClientSecret = getConfigItemStringValue(configFile, sectionName, "oidc_client_secret")
+	config.OIDCScopes = strings.Fields(getConfigItemStringValue(configFile, sectionName, "oidc_scopes", defaultOIDCScopes))
+	config.OIDCUsernameClaim = getConfigItemStringValue(configFile, sectionName, "oidc_username_claim", defaultOIDCUsernameClaim)
+	config.OIDCRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "oidc_request_timeout", defaultOIDCRequestTimeout)
+	config.OIDCEnableAutoCreateUser = getConfigItemBoolValue(configFile, sectionName, "oidc_auto_create_user", false)
+	config.OIDCLinkUserByEmail = getConfigItemBoolValue(configFile, sectionName, "oidc_link_user_by_email", false)
+	config.OIDCDefaultCurrency = strings.ToUpper(getConfigItemStringValue(configFile, sectionName, "oidc_default_currency", defaultOIDCDefaultCurrency))
+	config.OIDCDefaultLanguage = getConfigItemStringValue(configFile, sectionName, "oidc_default_language", defaultOIDCDefaultLanguage)
+
+	if config.EnableOIDCAuth && (config.OIDCIssuerUrl == "" || config.OIDCClientId == "") {
+		return errs.ErrInvalidOIDCConfig
+	}
+
+	config.EnableLDAPAuth = getConfigItemBoolValue(configFile, sectionName, "enable_ldap", false)
+	config.LDAPServerUrl = getConfigItemStringValue(configFile, sectionName, "ldap_server_url")
+	config.LDAPStartTLS = getConfigItemBoolValue(configFile, sectionName, "ldap_start_tls", false)
+	config.LDAPSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "ldap_skip_tls_verify", false)
+	config.LDAPBindDN = getConfigItemStringValue(configFile, sectionName, "ldap_bind_dn")
+	config.LDAPBindPassword = getConfigItemStringValue(configFile, sectionName, "ldap_bind_password")
+	config.LDAPBaseDN = getConfigItemStringValue(configFile, sectionName, "ldap_base_dn")
+	config.LDAPUserFilter = getConfigItemStringValue(configFile, sectionName, "ldap_user_filter", defaultLDAPUserFilter)
+	config.LDAPUsernameAttribute = getConfigItemStringValue(configFile, sectionName, "ldap_username_attribute", defaultLDAPUsernameAttribute)
+	config.LDAPEmailAttribute = getConfigItemStringValue(configFile, sectionName, "ldap_email_attribute", defaultLDAPEmailAttribute)
+	config.LDAPNicknameAttribute = getConfigItemStringValue(configFile, sectionName, "ldap_nickname_attribute", defaultLDAPNicknameAttribute)
+	config.LDAPGroupAttribute = getConfigItemStringValue(configFile, sectionName, "ldap_group_attribute", defaultLDAPGroupAttribute)
+	config.LDAPGroupFeatureRestrictions = parseLDAPGroupFeatureRestrictions(getConfigItemStringValue(configFile, sectionName, "ldap_group_feature_restrictions"))
+	config.LDAPRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "ldap_request_timeout", defaultLDAPRequestTimeout)
+	config.LDAPEnableAutoCreateUser = getConfigItemBoolValue(configFile, sectionName, "ldap_auto_create_user", false)
+	config.LDAPLinkUserByUsername = getConfigItemBoolValue(configFile, sectionName, "ldap_link_user_by_username", false)
+	config.LDAPDefaultCurrency = strings.ToUpper(getConfigItemStringValue(configFile, sectionName, "ldap_default_currency", defaultLDAPDefaultCurrency))
+	config.LDAPDefaultLanguage = getConfigItemStringValue(configFile, sectionName, "ldap_default_language", defaultLDAPDefaultLanguage)
+
+	if config.EnableLDAPAuth && (config.LDAPServerUrl == "" || config.LDAPBaseDN == "" || !strings.Contains(config.LDAPUserFilter, "%s")) {
+		return errs.ErrInvalidLDAPConfig
+	}
+
+	return nil
+}
+
+func parseLDAPGroupFeatureRestrictions(groupFeatureRestrictions string) map[string]core.UserFeatureRestrictions {
+	result := make(map[string]core.UserFeatureRestrictions)
+
+	if groupFeatureRestrictions == "" {
+		return result
+	}
+
+	items := strings.Split(groupFeatureRestrictions, ";")
+
+	for i := 0; i < len(items); i++ {
+		item := strings.TrimSpace(items[i])
+		separatorIndex := strings.LastIndex(item, ":")
+
+		if separatorIndex <= 0 {
+			continue
+		}
+
+		group := strings.ToLower(strings.TrimSpace(item[:separatorIndex]))
+		restrictions := strings.ReplaceAll(item[separatorIndex+1:], " ", "")
+
+		result[group] = core.ParseUserFeatureRestrictions(restrictions)
+	}
+
+	return result
+}
+
 func loadDataConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableDataExport = getConfigItemBoolValue(configFile, sectionName, "enable_export", false)
 	config.EnableDataImport = getConfigItemBoolValue(configFile, sectionName, "enable_import", false)
@@ -964,29 +1146,32 @@ func loadMapConfiguration(config *Config, configFile *ini.File, sectionName stri
 func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
 
-	if dataSource == ReserveBankOfAustraliaDataSource ||
-		dataSource == BankOfCanadaDataSource ||
-		dataSource == CzechNationalBankDataSource ||
-		dataSource == DanmarksNationalbankDataSource ||
-		dataSource == EuroCentralBankDataSource ||
-		dataSource == NationalBankOfGeorgiaDataSource ||
-		dataSource == CentralBankOfHungaryDataSource ||
-		dataSource == BankOfIsraelDataSource ||
-		dataSource == CentralBankOfMyanmarDataSource ||
-		dataSource == NorgesBankDataSource ||
-		dataSource == NationalBankOfPolandDataSource ||
-		dataSource == NationalBankOfRomaniaDataSource ||
-		dataSource == BankOfRussiaDataSource ||
-		dataSource == SwissNationalBankDataSource ||
-		dataSource == NationalBankOfUkraineDataSource ||
-		dataSource == CentralBankOfUzbekistanDataSource ||
-		dataSource == InternationalMonetaryFundDataSource ||
-		dataSource == UserCustomExchangeRatesDataSource {
+	if isHttpExchangeRatesDataSource(dataSource) || dataSource == UserCustomExchangeRatesDataSource {
 		config.ExchangeRatesDataSource = dataSource
 	} else {
 		return errs.ErrInvalidExchangeRatesDataSource
 	}
 
+	fallbackDataSources := strings.Split(getConfigItemStringValue(configFile, sectionName, "fallback_data_sources"), ",")
+	config.ExchangeRatesFallbackDataSources = make([]string, 0, len(fallbackDataSources))
+
+	for i := 0; i < len(fallbackDataSources); i++ {
+		fallbackDataSource := strings.TrimSpace(fallbackDataSources[i])
+
+		if fallbackDataSource == "" || fallbackDataSource == config.ExchangeRatesDataSource {
+			continue
+		}
+
+		if !isHttpExchangeRatesDataSource(fallbackDataSource) {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		config.ExchangeRatesFallbackDataSources = append(config.ExchangeRatesFallbackDataSources, fallbackDataSource)
+	}
+
+	config.EnableExchangeRatesCache = getConfigItemBoolValue(configFile, sectionName, "enable_cache", true)
+	config.ExchangeRatesStaleDataMaxAge = getConfigItemUint32Value(configFile, sectionName, "stale_data_max_age", defaultExchangeRatesStaleDataMaxAge)
+
 	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
 	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
 
@@ -999,6 +1184,43 @@ func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectio
 	return nil
 }
 
+func loadInvestmentConfiguration(config *Config, configFile *ini.File, sectionName string) error {
+	config.CryptoPricesDataSourceUrl = strings.TrimRight(getConfigItemStringValue(configFile, sectionName, "crypto_prices_data_source_url", defaultCryptoPricesDataSourceUrl), "/")
+
+	if config.CryptoPricesDataSourceUrl == "" {
+		config.CryptoPricesDataSourceUrl = defaultCryptoPricesDataSourceUrl
+	}
+
+	config.CryptoPricesApiKey = getConfigItemStringValue(configFile, sectionName, "crypto_prices_api_key")
+	config.CryptoPricesApiKeyHeader = getConfigItemStringValue(configFile, sectionName, "crypto_prices_api_key_header", defaultCryptoPricesApiKeyHeader)
+
+	config.InvestmentPricesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.InvestmentPricesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultInvestmentPricesDataRequestTimeout)
+	config.InvestmentPricesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+
+	return nil
+}
+
+func isHttpExchangeRatesDataSource(dataSource string) bool {
+	return dataSource == ReserveBankOfAustraliaDataSource ||
+		dataSource == BankOfCanadaDataSource ||
+		dataSource == CzechNationalBankDataSource ||
+		dataSource == DanmarksNationalbankDataSource ||
+		dataSource == EuroCentralBankDataSource ||
+		dataSource == NationalBankOfGeorgiaDataSource ||
+		dataSource == CentralBankOfHungaryDataSource ||
+		dataSource == BankOfIsraelDataSource ||
+		dataSource == CentralBankOfMyanmarDataSource ||
+		dataSource == NorgesBankDataSource ||
+		dataSource == NationalBankOfPolandDataSource ||
+		dataSource == NationalBankOfRomaniaDataSource ||
+		dataSource == BankOfRussiaDataSource ||
+		dataSource == SwissNationalBankDataSource ||
+		dataSource == NationalBankOfUkraineDataSource ||
+		dataSource == CentralBankOfUzbekistanDataSource ||
+		dataSource == InternationalMonetaryFundDataSource
+}
+
 func getWorkingPath() (string, error) {
 	workingPath := os.Getenv(ebkWorkDirEnvName)
 
@@ -16,4 +16,8 @@ const (
 	UUID_TYPE_PICTURE               UuidType = 8
 	UUID_TYPE_INVESTMENT            UuidType = 9
 	UUID_TYPE_INVESTMENT_TRANSACTION UuidType = 10
+	UUID_TYPE_RULE                  UuidType = 11
+	UUID_TYPE_AUDIT_EVENT           UuidType = 12
+	UUID_TYPE_TRANSACTION_SPLIT     UuidType = 13
+	UUID_TYPE_PAYEE                 UuidType = 14
 )
@@ -2,6 +2,7 @@ export const BASE_API_URL_PATH: string = '/api';
 export const BASE_QRCODE_PATH: string = '/qrcode';
 export const BASE_PROXY_URL_PATH: string = '/proxy';
 export const BASE_AMAP_API_PROXY_URL_PATH: string = '/_AMapService';
+export const BASE_OAUTH2_URL_PATH: string = '/oauth2';
 
 export const DEFAULT_API_TIMEOUT: number = 10000; // 10s
 export const DEFAULT_UPLOAD_API_TIMEOUT: number = 30000; // 30s
@@ -180,15 +180,35 @@ export const SUPPORTED_IMPORT_FILE_CATEGORY_AND_TYPES: ImportFileCategoryAndType
     {
         categoryName: 'General Bank Statement Format',
         fileTypes: [
+            {
+                type: 'camt052',
+                name: 'Camt.052 Bank to Customer Account Report File',
+                extensions: '.xml'
+            },
             {
                 type: 'camt053',
                 name: 'Camt.053 Bank to Customer Statement File',
                 extensions: '.xml'
             },
+            {
+                type: 'camt054',
+                name: 'Camt.054 Bank to Customer Debit Credit Notification File',
+                extensions: '.xml'
+            },
+            {
+                type: 'camt_zip',
+                name: 'Camt Multiple Statements Zip File',
+                extensions: '.zip'
+            },
             {
                 type: 'mt940',
                 name: 'MT940 Consumer Statement Message File',
                 extensions: '.txt'
+            },
+            {
+                type: 'mt942',
+                name: 'MT942 Interim Transaction Report File',
+                extensions: '.txt'
             }
         ]
     },
@@ -262,6 +282,36 @@ export const SUPPORTED_IMPORT_FILE_CATEGORY_AND_TYPES: ImportFileCategoryAndType
                 name: 'Beancount Data File',
                 extensions: '.beancount'
             },
+            {
+                type: 'ynab_csv',
+                name: 'YNAB Register Export File',
+                extensions: '.csv'
+            },
+            {
+                type: 'actual_csv',
+                name: 'Actual Budget Transaction Export File',
+                extensions: '.csv'
+            },
+            {
+                type: 'monarch_csv',
+                name: 'Monarch Money Transaction Export File',
+                extensions: '.csv'
+            },
+            {
+                type: 'mint_csv',
+                name: 'Mint Transaction Export File',
+                extensions: '.csv'
+            },
+            {
+                type: 'mmex',
+                name: 'Money Manager Ex Database File',
+                extensions: '.mmb'
+            },
+            {
+                type: 'ledger',
+                name: 'Ledger / hledger Journal File',
+                extensions: '.ledger,.journal,.hledger,.dat,.zip'
+            },
             {
                 type: 'feidee_mymoney_csv',
                 name: 'Feidee MyMoney (App) Data Export File',
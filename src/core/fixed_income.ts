@@ -0,0 +1,35 @@
+import type { TypeAndName } from './base.ts';
+
+export class FixedIncomeFrequency implements TypeAndName {
+    private static readonly allInstances: FixedIncomeFrequency[] = [];
+    private static readonly allInstancesByType: Record<number, FixedIncomeFrequency> = {};
+
+    public static readonly Monthly = new FixedIncomeFrequency(1, 'Monthly', 1);
+    public static readonly Quarterly = new FixedIncomeFrequency(2, 'Quarterly', 3);
+    public static readonly SemiAnnually = new FixedIncomeFrequency(3, 'Semi-annually', 6);
+    public static readonly Annually = new FixedIncomeFrequency(4, 'Annually', 12);
+    public static readonly AtMaturity = new FixedIncomeFrequency(5, 'At Maturity', 0);
+
+    public static readonly Default = FixedIncomeFrequency.AtMaturity;
+
+    public readonly type: number;
+    public readonly name: string;
+    public readonly months: number;
+
+    private constructor(type: number, name: string, months: number) {
+        this.type = type;
+        this.name = name;
+        this.months = months;
+
+        FixedIncomeFrequency.allInstances.push(this);
+        FixedIncomeFrequency.allInstancesByType[type] = this;
+    }
+
+    public static values(): FixedIncomeFrequency[] {
+        return FixedIncomeFrequency.allInstances;
+    }
+
+    public static valueOf(type: number): FixedIncomeFrequency | undefined {
+        return FixedIncomeFrequency.allInstancesByType[type];
+    }
+}
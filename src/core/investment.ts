@@ -0,0 +1,38 @@
+import type { TypeAndName } from './base.ts';
+
+export class InvestmentAssetType implements TypeAndName {
+    private static readonly allInstances: InvestmentAssetType[] = [];
+    private static readonly allInstancesByType: Record<number, InvestmentAssetType> = {};
+
+    public static readonly Stock = new InvestmentAssetType(1, 'Stock', 4, 2);
+    public static readonly ETF = new InvestmentAssetType(2, 'ETF', 4, 2);
+    public static readonly MutualFund = new InvestmentAssetType(3, 'Mutual Fund', 4, 2);
+    public static readonly Cryptocurrency = new InvestmentAssetType(4, 'Cryptocurrency', 8, 8);
+    public static readonly PreciousMetal = new InvestmentAssetType(5, 'Precious Metal', 4, 2);
+    public static readonly Bond = new InvestmentAssetType(6, 'Bond', 4, 2);
+
+    public static readonly Default = InvestmentAssetType.Stock;
+
+    public readonly type: number;
+    public readonly name: string;
+    public readonly quantityPrecision: number;
+    public readonly pricePrecision: number;
+
+    private constructor(type: number, name: string, quantityPrecision: number, pricePrecision: number) {
+        this.type = type;
+        this.name = name;
+        this.quantityPrecision = quantityPrecision;
+        this.pricePrecision = pricePrecision;
+
+        InvestmentAssetType.allInstances.push(this);
+        InvestmentAssetType.allInstancesByType[type] = this;
+    }
+
+    public static values(): InvestmentAssetType[] {
+        return InvestmentAssetType.allInstances;
+    }
+
+    public static valueOf(type: number): InvestmentAssetType | undefined {
+        return InvestmentAssetType.allInstancesByType[type];
+    }
+}
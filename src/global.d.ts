@@ -24,9 +24,13 @@ interface Navigator {
 
 interface Credential {
     rawId: ArrayBuffer;
+    authenticatorAttachment?: string | null;
     response: {
         clientDataJSON: ArrayBuffer;
         attestationObject: ArrayBuffer;
+        authenticatorData: ArrayBuffer;
+        signature: ArrayBuffer;
         userHandle: ArrayBuffer;
+        getTransports?: () => string[];
     };
 }
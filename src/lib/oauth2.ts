@@ -0,0 +1,5 @@
+import services from './services.ts';
+
+export function getOIDCLoginUrl(platform: 'desktop' | 'mobile'): string {
+    return services.generateOIDCLoginUrl(platform);
+}
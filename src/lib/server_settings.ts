@@ -35,6 +35,22 @@ export function isMCPServerEnabled(): boolean {
     return getServerSetting('mcp') === 1;
 }
 
+export function isWebAuthnEnabled(): boolean {
+    return getServerSetting('wa') === 1;
+}
+
+export function isWebAuthnPasswordlessLoginEnabled(): boolean {
+    return getServerSetting('wapl') === 1;
+}
+
+export function isOIDCAuthEnabled(): boolean {
+    return !!getServerSetting('oidc');
+}
+
+export function getOIDCProviderName(): string {
+    return getServerSetting('oidc') as string;
+}
+
 export function getLoginPageTips(): Record<string, string>{
     return getServerSetting('lpt') as Record<string, string>;
 }
@@ -17,6 +17,7 @@ import {
     BASE_QRCODE_PATH,
     BASE_PROXY_URL_PATH,
     BASE_AMAP_API_PROXY_URL_PATH,
+    BASE_OAUTH2_URL_PATH,
     DEFAULT_API_TIMEOUT,
     DEFAULT_UPLOAD_API_TIMEOUT,
     DEFAULT_EXPORT_API_TIMEOUT,
@@ -47,11 +48,36 @@ import type {
     UserCustomExchangeRateUpdateRequest,
     UserCustomExchangeRateDeleteRequest,
     UserCustomExchangeRateUpdateResponse,
-    LatestExchangeRateResponse
+    LatestExchangeRateResponse,
+    HistoricalExchangeRateResponse
 } from '@/models/exchange_rate.ts';
+import type {
+    FixedIncomeTermSetRequest,
+    FixedIncomeTermDeleteRequest,
+    FixedIncomeTermInfoResponse,
+    FixedIncomeProjectionsResponse
+} from '@/models/fixed_income.ts';
+import type {
+    LoanTermSetRequest,
+    LoanTermDeleteRequest,
+    LoanWhatIfRequest,
+    LoanPaymentCreateRequest,
+    LoanTermInfoResponse,
+    LoanAmortizationScheduleResponse,
+    LoanWhatIfResponse,
+    LoanPaymentCreateResponse
+} from '@/models/loan.ts';
 import type {
     ForgetPasswordRequest
 } from '@/models/forget_password.ts';
+import type {
+    PayeeCreateRequest,
+    PayeeModifyRequest,
+    PayeeHideRequest,
+    PayeeMergeRequest,
+    PayeeDeleteRequest,
+    PayeeInfoResponse
+} from '@/models/payee.ts';
 import type {
     ImportTransactionResponsePageWrapper
 } from '@/models/imported_transaction.ts';
@@ -134,6 +160,24 @@ import type {
 import type {
     UserApplicationCloudSettingsUpdateRequest
 } from '@/models/user_app_cloud_setting.ts';
+import type {
+    UserOIDCLinkRequest,
+    UserOIDCLinkResponse,
+    UserExternalAuthInfoResponse
+} from '@/models/user_external_auth.ts';
+import type {
+    WebAuthnRegisterBeginResponse,
+    WebAuthnRegisterFinishRequest,
+    WebAuthnLoginBeginResponse,
+    WebAuthnLoginRequest,
+    WebAuthnCredentialModifyRequest,
+    WebAuthnCredentialDeleteRequest,
+    WebAuthnCredentialInfoResponse
+} from '@/models/user_webauthn_credential.ts';
+import type {
+    AuditEventListRequest,
+    AuditEventInfoPageWrapperResponse
+} from '@/models/audit_event.ts';
 
 import {
     getCurrentToken,
@@ -243,6 +287,33 @@ export default {
             }
         });
     },
+    beginWebAuthn2FA: ({ token }: { token: string }): ApiResponsePromise<WebAuthnLoginBeginResponse> => {
+        return axios.post<ApiResponse<WebAuthnLoginBeginResponse>>('2fa/webauthn/begin.json', {}, {
+            headers: {
+                Authorization: `Bearer ${token}`
+            }
+        });
+    },
+    authorizeWebAuthn2FA: ({ req, token }: { req: WebAuthnLoginRequest, token: string }): ApiResponsePromise<AuthResponse> => {
+        return axios.post<ApiResponse<AuthResponse>>('2fa/webauthn/authorize.json', req, {
+            headers: {
+                Authorization: `Bearer ${token}`
+            }
+        });
+    },
+    beginWebAuthnLogin: (): ApiResponsePromise<WebAuthnLoginBeginResponse> => {
+        return axios.post<ApiResponse<WebAuthnLoginBeginResponse>>('webauthn/begin.json');
+    },
+    authorizeWebAuthn: (req: WebAuthnLoginRequest): ApiResponsePromise<AuthResponse> => {
+        return axios.post<ApiResponse<AuthResponse>>('webauthn/authorize.json', req);
+    },
+    authorizeOIDC: ({ token }: { token: string }): ApiResponsePromise<AuthResponse> => {
+        return axios.post<ApiResponse<AuthResponse>>('oauth2/authorize.json', {}, {
+            headers: {
+                Authorization: `Bearer ${token}`
+            }
+        });
+    },
     register: (req: UserRegisterRequest): ApiResponsePromise<RegisterResponse> => {
         return axios.post<ApiResponse<RegisterResponse>>('register.json', req);
     },
@@ -325,6 +396,33 @@ export default {
     removeAvatar: (): ApiResponsePromise<UserProfileResponse> => {
         return axios.post<ApiResponse<UserProfileResponse>>('v1/users/avatar/remove.json');
     },
+    getUserExternalAuths: (): ApiResponsePromise<UserExternalAuthInfoResponse[]> => {
+        return axios.get<ApiResponse<UserExternalAuthInfoResponse[]>>('v1/users/external_auth/list.json');
+    },
+    linkOIDCExternalAuth: (req: UserOIDCLinkRequest): ApiResponsePromise<UserOIDCLinkResponse> => {
+        return axios.post<ApiResponse<UserOIDCLinkResponse>>('v1/users/external_auth/oidc/link.json', req);
+    },
+    unlinkOIDCExternalAuth: (): ApiResponsePromise<boolean> => {
+        return axios.post<ApiResponse<boolean>>('v1/users/external_auth/oidc/unlink.json');
+    },
+    getWebAuthnCredentials: (): ApiResponsePromise<WebAuthnCredentialInfoResponse[]> => {
+        return axios.get<ApiResponse<WebAuthnCredentialInfoResponse[]>>('v1/users/webauthn/list.json');
+    },
+    beginRegisterWebAuthnCredential: (): ApiResponsePromise<WebAuthnRegisterBeginResponse> => {
+        return axios.post<ApiResponse<WebAuthnRegisterBeginResponse>>('v1/users/webauthn/register/begin.json');
+    },
+    finishRegisterWebAuthnCredential: (req: WebAuthnRegisterFinishRequest): ApiResponsePromise<WebAuthnCredentialInfoResponse> => {
+        return axios.post<ApiResponse<WebAuthnCredentialInfoResponse>>('v1/users/webauthn/register/finish.json', req);
+    },
+    modifyWebAuthnCredential: (req: WebAuthnCredentialModifyRequest): ApiResponsePromise<boolean> => {
+        return axios.post<ApiResponse<boolean>>('v1/users/webauthn/modify.json', req);
+    },
+    deleteWebAuthnCredential: (req: WebAuthnCredentialDeleteRequest): ApiResponsePromise<boolean> => {
+        return axios.post<ApiResponse<boolean>>('v1/users/webauthn/delete.json', req);
+    },
+    getAuditEvents: (req: AuditEventListRequest): ApiResponsePromise<AuditEventInfoPageWrapperResponse> => {
+        return axios.get<ApiResponse<AuditEventInfoPageWrapperResponse>>(`v1/users/audit_events/list.json?page=${req.page}&count=${req.count}`);
+    },
     resendVerifyEmailByLoginedUser: (): ApiResponsePromise<boolean> => {
         return axios.post<ApiResponse<boolean>>('v1/users/verify_email/resend.json');
     },
@@ -374,6 +472,10 @@ export default {
             return axios.get<BlobPart>('v1/data/export.tsv?' + params, {
                 timeout: DEFAULT_EXPORT_API_TIMEOUT
             } as ApiRequestConfig);
+        } else if (fileType === 'ledger') {
+            return axios.get<BlobPart>('v1/data/export.ledger?' + params, {
+                timeout: DEFAULT_EXPORT_API_TIMEOUT
+            } as ApiRequestConfig);
         } else {
             return Promise.reject('Parameter Invalid');
         }
@@ -408,15 +510,51 @@ export default {
     deleteSubAccount: (req: AccountDeleteRequest): ApiResponsePromise<boolean> => {
         return axios.post<ApiResponse<boolean>>('v1/accounts/sub_account/delete.json', req);
     },
+    getFixedIncomeTerm: ({ accountId, investmentId }: { accountId?: string, investmentId?: string }): ApiResponsePromise<FixedIncomeTermInfoResponse> => {
+        if (investmentId) {
+            return axios.get<ApiResponse<FixedIncomeTermInfoResponse>>('v1/accounts/fixed_income/get.json?investmentId=' + investmentId);
+        }
+
+        return axios.get<ApiResponse<FixedIncomeTermInfoResponse>>('v1/accounts/fixed_income/get.json?accountId=' + accountId);
+    },
+    setFixedIncomeTerm: (req: FixedIncomeTermSetRequest): ApiResponsePromise<FixedIncomeTermInfoResponse> => {
+        return axios.post<ApiResponse<FixedIncomeTermInfoResponse>>('v1/accounts/fixed_income/set.json', req);
+    },
+    deleteFixedIncomeTerm: (req: FixedIncomeTermDeleteRequest): ApiResponsePromise<boolean> => {
+        return axios.post<ApiResponse<boolean>>('v1/accounts/fixed_income/delete.json', req);
+    },
+    getFixedIncomeProjections: ({ days }: { days?: number }): ApiResponsePromise<FixedIncomeProjectionsResponse> => {
+        return axios.get<ApiResponse<FixedIncomeProjectionsResponse>>('v1/accounts/fixed_income/projections.json' + (days ? `?days=${days}` : ''));
+    },
+    getLoanTerm: ({ accountId }: { accountId: string }): ApiResponsePromise<LoanTermInfoResponse> => {
+        return axios.get<ApiResponse<LoanTermInfoResponse>>('v1/accounts/loan/get.json?accountId=' + accountId);
+    },
+    setLoanTerm: (req: LoanTermSetRequest): ApiResponsePromise<LoanTermInfoResponse> => {
+        return axios.post<ApiResponse<LoanTermInfoResponse>>('v1/accounts/loan/set.json', req);
+    },
+    deleteLoanTerm: (req: LoanTermDeleteRequest): ApiResponsePromise<boolean> => {
+        return axios.post<ApiResponse<boolean>>('v1/accounts/loan/delete.json', req);
+    },
+    getLoanAmortizationSchedule: ({ accountId }: { accountId: string }): ApiResponsePromise<LoanAmortizationScheduleResponse> => {
+        return axios.get<ApiResponse<LoanAmortizationScheduleResponse>>('v1/accounts/loan/schedule.json?accountId=' + accountId);
+    },
+    getLoanWhatIf: (req: LoanWhatIfRequest): ApiResponsePromise<LoanWhatIfResponse> => {
+        return axios.post<ApiResponse<LoanWhatIfResponse>>('v1/accounts/loan/what_if.json', req);
+    },
+    addLoanPayment: (req: LoanPaymentCreateRequest): ApiResponsePromise<LoanPaymentCreateResponse> => {
+        return axios.post<ApiResponse<LoanPaymentCreateResponse>>('v1/accounts/loan/payment.json', req);
+    },
     getTransactions: (req: TransactionListByMaxTimeRequest): ApiResponsePromise<TransactionInfoPageWrapperResponse> => {
         const amountFilter = encodeURIComponent(req.amountFilter);
         const keyword = encodeURIComponent(req.keyword);
-        return axios.get<ApiResponse<TransactionInfoPageWrapperResponse>>(`v1/transactions/list.json?max_time=${req.maxTime}&min_time=${req.minTime}&type=${req.type}&category_ids=${req.categoryIds}&account_ids=${req.accountIds}&tag_ids=${req.tagIds}&tag_filter_type=${req.tagFilterType}&amount_filter=${amountFilter}&keyword=${keyword}&count=${req.count}&page=${req.page}&with_count=${req.withCount}&trim_account=true&trim_category=true&trim_tag=true`);
+        const query = req.query ? `&query=${encodeURIComponent(req.query)}` : '';
+        return axios.get<ApiResponse<TransactionInfoPageWrapperResponse>>(`v1/transactions/list.json?max_time=${req.maxTime}&min_time=${req.minTime}&type=${req.type}&category_ids=${req.categoryIds}&account_ids=${req.accountIds}&tag_ids=${req.tagIds}&tag_filter_type=${req.tagFilterType}&amount_filter=${amountFilter}&keyword=${keyword}${query}&count=${req.count}&page=${req.page}&with_count=${req.withCount}&trim_account=true&trim_category=true&trim_tag=true`);
     },
     getAllTransactionsByMonth: (req: TransactionListInMonthByPageRequest): ApiResponsePromise<TransactionInfoPageWrapperResponse2> => {
         const amountFilter = encodeURIComponent(req.amountFilter);
         const keyword = encodeURIComponent(req.keyword);
-        return axios.get<ApiResponse<TransactionInfoPageWrapperResponse2>>(`v1/transactions/list/by_month.json?year=${req.year}&month=${req.month}&type=${req.type}&category_ids=${req.categoryIds}&account_ids=${req.accountIds}&tag_ids=${req.tagIds}&tag_filter_type=${req.tagFilterType}&amount_filter=${amountFilter}&keyword=${keyword}&trim_account=true&trim_category=true&trim_tag=true`);
+        const query = req.query ? `&query=${encodeURIComponent(req.query)}` : '';
+        return axios.get<ApiResponse<TransactionInfoPageWrapperResponse2>>(`v1/transactions/list/by_month.json?year=${req.year}&month=${req.month}&type=${req.type}&category_ids=${req.categoryIds}&account_ids=${req.accountIds}&tag_ids=${req.tagIds}&tag_filter_type=${req.tagFilterType}&amount_filter=${amountFilter}&keyword=${keyword}${query}&trim_account=true&trim_category=true&trim_tag=true`);
     },
     getReconciliationStatements: (req: TransactionReconciliationStatementRequest): ApiResponsePromise<TransactionReconciliationStatementResponse> => {
         return axios.get<ApiResponse<TransactionReconciliationStatementResponse>>(`v1/transactions/reconciliation_statements.json?account_id=${req.accountId}&start_time=${req.startTime}&end_time=${req.endTime}`);
@@ -604,6 +742,27 @@ export default {
     deleteTransactionTag: (req: TransactionTagDeleteRequest): ApiResponsePromise<boolean> => {
         return axios.post<ApiResponse<boolean>>('v1/transaction/tags/delete.json', req);
     },
+    getAllPayees: (): ApiResponsePromise<PayeeInfoResponse[]> => {
+        return axios.get<ApiResponse<PayeeInfoResponse[]>>('v1/payees/list.json');
+    },
+    getPayee: ({ id }: { id: string }): ApiResponsePromise<PayeeInfoResponse> => {
+        return axios.get<ApiResponse<PayeeInfoResponse>>('v1/payees/get.json?id=' + id);
+    },
+    addPayee: (req: PayeeCreateRequest): ApiResponsePromise<PayeeInfoResponse> => {
+        return axios.post<ApiResponse<PayeeInfoResponse>>('v1/payees/add.json', req);
+    },
+    modifyPayee: (req: PayeeModifyRequest): ApiResponsePromise<PayeeInfoResponse> => {
+        return axios.post<ApiResponse<PayeeInfoResponse>>('v1/payees/modify.json', req);
+    },
+    hidePayee: (req: PayeeHideRequest): ApiResponsePromise<boolean> => {
+        return axios.post<ApiResponse<boolean>>('v1/payees/hide.json', req);
+    },
+    mergePayees: (req: PayeeMergeRequest): ApiResponsePromise<PayeeInfoResponse> => {
+        return axios.post<ApiResponse<PayeeInfoResponse>>('v1/payees/merge.json', req);
+    },
+    deletePayee: (req: PayeeDeleteRequest): ApiResponsePromise<boolean> => {
+        return axios.post<ApiResponse<boolean>>('v1/payees/delete.json', req);
+    },
     getAllTransactionTemplates: ({ templateType }: { templateType: number }): ApiResponsePromise<TransactionTemplateInfoResponse[]> => {
         return axios.get<ApiResponse<TransactionTemplateInfoResponse[]>>('v1/transaction/templates/list.json?templateType=' + templateType);
     },
@@ -631,6 +790,12 @@ export default {
             timeout: getExchangeRatesRequestTimeout() || DEFAULT_API_TIMEOUT
         } as ApiRequestConfig);
     },
+    getHistoricalExchangeRates: ({ date, ignoreError }: { date: string, ignoreError?: boolean }): ApiResponsePromise<HistoricalExchangeRateResponse> => {
+        return axios.get<ApiResponse<HistoricalExchangeRateResponse>>(`v1/exchange_rates/history.json?date=${date}`, {
+            ignoreError: !!ignoreError,
+            timeout: getExchangeRatesRequestTimeout() || DEFAULT_API_TIMEOUT
+        } as ApiRequestConfig);
+    },
     updateUserCustomExchangeRate: (req: UserCustomExchangeRateUpdateRequest): ApiResponsePromise<UserCustomExchangeRateUpdateResponse> => {
         return axios.post<ApiResponse<UserCustomExchangeRateUpdateResponse>>('v1/exchange_rates/user_custom/update.json', req);
     },
@@ -640,6 +805,9 @@ export default {
     getServerVersion: (): ApiResponsePromise<VersionInfo> => {
         return axios.get<ApiResponse<VersionInfo>>('v1/systems/version.json');
     },
+    generateOIDCLoginUrl: (platform: string): string => {
+        return `${getBasePath()}${BASE_OAUTH2_URL_PATH}/login?platform=${platform}`;
+    },
     generateQrCodeUrl: (qrCodeName: string): string => {
         return `${getBasePath()}${BASE_QRCODE_PATH}/${qrCodeName}.png`;
     },
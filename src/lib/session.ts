@@ -2,6 +2,7 @@ import uaParser from 'ua-parser-js';
 
 import {
     TOKEN_TYPE_MCP,
+    TOKEN_TYPE_PERSONAL_ACCESS,
     TOKEN_CLI_USER_AGENT,
     type TokenInfoResponse,
     SessionInfo
@@ -87,12 +88,15 @@ function parseDeviceInfo(uaInfo: UserAgentInfo): string {
 
 export function parseSessionInfo(token: TokenInfoResponse): SessionInfo {
     const isCreateForMCP = token.tokenType === TOKEN_TYPE_MCP;
+    const isPersonalAccessToken = token.tokenType === TOKEN_TYPE_PERSONAL_ACCESS;
     const isCreatedByCli = isSessionUserAgentCreatedByCli(token.userAgent);
     const uaInfo = parseUserAgent(token.userAgent);
     let deviceType = '';
 
     if (isCreateForMCP) {
         deviceType = 'mcp';
+    } else if (isPersonalAccessToken) {
+        deviceType = 'api';
     } else if (isCreatedByCli) {
         deviceType = 'cli';
     } else {
@@ -117,8 +121,9 @@ export function parseSessionInfo(token: TokenInfoResponse): SessionInfo {
         token.tokenId,
         token.isCurrent,
         deviceType,
-        isCreateForMCP || isCreatedByCli ? token.userAgent : parseDeviceInfo(uaInfo),
+        isPersonalAccessToken ? (token.name || '') : (isCreateForMCP || isCreatedByCli ? token.userAgent : parseDeviceInfo(uaInfo)),
         isCreatedByCli,
+        !!token.readOnly,
         token.lastSeen
     );
 }
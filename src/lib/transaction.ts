@@ -194,6 +194,7 @@ export function setTransactionModelByTransaction(transaction: Transaction, trans
 
         if (setContextData) {
             transaction.setGeoLocation(transaction2.geoLocation);
+            transaction.splits = transaction2.splits;
         }
     }
 }
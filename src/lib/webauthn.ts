@@ -2,6 +2,10 @@ import CBOR from 'cbor-js';
 
 import type { ApplicationLockState } from '@/core/setting.ts';
 import type { UserBasicInfo } from '@/models/user.ts';
+import type {
+    WebAuthnServerCredentialCreationOptions,
+    WebAuthnServerCredentialRequestOptions
+} from '@/models/user_webauthn_credential.ts';
 
 import {
     isFunction,
@@ -215,3 +219,111 @@ export function verifyWebAuthnCredential(userInfo: UserBasicInfo, credentialId:
         }
     });
 }
+
+function base64UrlEncode(arrayBuffer: ArrayBuffer): string {
+    return base64encode(arrayBuffer).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+}
+
+function base64UrlDecode(str: string): ArrayBuffer {
+    const base64 = str.replace(/-/g, '+').replace(/_/g, '/');
+    const paddedBase64 = base64 + '='.repeat((4 - base64.length % 4) % 4);
+    return stringToArrayBuffer(base64decode(paddedBase64));
+}
+
+function parseServerCredentialDescriptors(descriptors?: { id: string, type: string, transports?: string[] }[]): PublicKeyCredentialDescriptor[] | undefined {
+    if (!descriptors) {
+        return undefined;
+    }
+
+    return descriptors.map(descriptor => ({
+        id: base64UrlDecode(descriptor.id),
+        type: descriptor.type,
+        transports: descriptor.transports
+    }) as PublicKeyCredentialDescriptor);
+}
+
+export function createServerWebAuthnCredential(options: WebAuthnServerCredentialCreationOptions): Promise<string> {
+    if (!isWebAuthnSupported() || !navigator.credentials.create) {
+        return Promise.reject({
+            notSupported: true
+        });
+    }
+
+    const publicKeyCredentialCreationOptions: PublicKeyCredentialCreationOptions = Object.assign({}, options.publicKey, {
+        challenge: base64UrlDecode(options.publicKey.challenge),
+        user: Object.assign({}, options.publicKey.user, {
+            id: base64UrlDecode(options.publicKey.user.id)
+        }),
+        excludeCredentials: parseServerCredentialDescriptors(options.publicKey.excludeCredentials)
+    }) as PublicKeyCredentialCreationOptions;
+
+    logger.debug('webauthn server create options', publicKeyCredentialCreationOptions);
+
+    return navigator.credentials.create({
+        publicKey: publicKeyCredentialCreationOptions
+    }).then(rawCredential => {
+        logger.debug('webauthn server create raw response', rawCredential);
+
+        if (!rawCredential || !rawCredential.rawId || !rawCredential.response) {
+            return Promise.reject({
+                invalid: true
+            });
+        }
+
+        const transports: string[] = isFunction(rawCredential.response.getTransports) ? rawCredential.response.getTransports() : [];
+
+        return JSON.stringify({
+            id: rawCredential.id,
+            rawId: base64UrlEncode(rawCredential.rawId),
+            type: rawCredential.type,
+            authenticatorAttachment: rawCredential.authenticatorAttachment || undefined,
+            clientExtensionResults: {},
+            response: {
+                clientDataJSON: base64UrlEncode(rawCredential.response.clientDataJSON),
+                attestationObject: base64UrlEncode(rawCredential.response.attestationObject),
+                transports: transports
+            }
+        });
+    });
+}
+
+export function getServerWebAuthnAssertion(options: WebAuthnServerCredentialRequestOptions): Promise<string> {
+    if (!isWebAuthnSupported() || !navigator.credentials.get) {
+        return Promise.reject({
+            notSupported: true
+        });
+    }
+
+    const publicKeyCredentialRequestOptions: PublicKeyCredentialRequestOptions = Object.assign({}, options.publicKey, {
+        challenge: base64UrlDecode(options.publicKey.challenge),
+        allowCredentials: parseServerCredentialDescriptors(options.publicKey.allowCredentials)
+    }) as PublicKeyCredentialRequestOptions;
+
+    logger.debug('webauthn server get options', publicKeyCredentialRequestOptions);
+
+    return navigator.credentials.get({
+        publicKey: publicKeyCredentialRequestOptions
+    }).then(rawCredential => {
+        logger.debug('webauthn server get raw response', rawCredential);
+
+        if (!rawCredential || !rawCredential.rawId || !rawCredential.response) {
+            return Promise.reject({
+                invalid: true
+            });
+        }
+
+        return JSON.stringify({
+            id: rawCredential.id,
+            rawId: base64UrlEncode(rawCredential.rawId),
+            type: rawCredential.type,
+            authenticatorAttachment: rawCredential.authenticatorAttachment || undefined,
+            clientExtensionResults: {},
+            response: {
+                clientDataJSON: base64UrlEncode(rawCredential.response.clientDataJSON),
+                authenticatorData: base64UrlEncode(rawCredential.response.authenticatorData),
+                signature: base64UrlEncode(rawCredential.response.signature),
+                userHandle: rawCredential.response.userHandle ? base64UrlEncode(rawCredential.response.userHandle) : undefined
+            }
+        });
+    });
+}
@@ -0,0 +1,42 @@
+export const AUDIT_EVENT_TYPE_NAMES: Record<number, string> = {
+    1: 'Login',
+    2: 'Login Failed',
+    3: 'Two-Factor Verified',
+    4: 'Two-Factor Verify Failed',
+    5: 'Two-Factor Enabled',
+    6: 'Two-Factor Disabled',
+    7: 'Two-Factor Recovery Codes Regenerated',
+    8: 'Logout',
+    9: 'Token Generated',
+    10: 'Token Revoked',
+    11: 'All Tokens Revoked',
+    12: 'Data Exported',
+    13: 'All Data Cleared',
+    14: 'All Transactions Cleared',
+    15: 'Password Reset Requested',
+    16: 'Password Reset',
+    17: 'Passkey Registered',
+    18: 'Passkey Deleted',
+    19: 'External Account Linked',
+    20: 'External Account Unlinked'
+};
+
+export interface AuditEventListRequest {
+    readonly page: number;
+    readonly count: number;
+}
+
+export interface AuditEventInfoResponse {
+    readonly id: string;
+    readonly eventType: number;
+    readonly ipAddress: string;
+    readonly userAgent: string;
+    readonly requestId: string;
+    readonly detail?: string;
+    readonly createdAt: number;
+}
+
+export interface AuditEventInfoPageWrapperResponse {
+    readonly items: AuditEventInfoResponse[];
+    readonly totalCount: number;
+}
@@ -5,6 +5,7 @@ import type { UserBasicInfo } from './user.ts';
 export interface AuthResponse {
     readonly token: string;
     readonly need2FA: boolean;
+    readonly twoFactorMethods?: string[];
     readonly user?: UserBasicInfo;
     readonly applicationCloudSettings?: ApplicationCloudSetting[];
     readonly notificationContent?: string;
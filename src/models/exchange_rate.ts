@@ -22,6 +22,15 @@ export interface LatestExchangeRateResponse {
     updateTime: number;
     readonly baseCurrency: string;
     readonly exchangeRates: LatestExchangeRate[];
+    readonly stale?: boolean;
+}
+
+export interface HistoricalExchangeRateResponse {
+    readonly dataSource: string;
+    readonly date: string;
+    readonly updateTime: number;
+    readonly baseCurrency: string;
+    readonly exchangeRates: LatestExchangeRate[];
 }
 
 export interface LocalizedLatestExchangeRate {
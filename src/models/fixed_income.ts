@@ -0,0 +1,51 @@
+export interface FixedIncomeTermSetRequest {
+    readonly accountId?: string;
+    readonly investmentId?: string;
+    readonly principal: number;
+    readonly interestRate: string;
+    readonly compoundingFrequency: number;
+    readonly payoutFrequency: number;
+    readonly startDate: string;
+    readonly maturityDate: string;
+    readonly interestCategoryId: string;
+    readonly payoutAccountId: string;
+}
+
+export interface FixedIncomeTermDeleteRequest {
+    readonly accountId?: string;
+    readonly investmentId?: string;
+}
+
+export interface FixedIncomeTermInfoResponse {
+    readonly accountId: string;
+    readonly investmentId?: string;
+    readonly principal: number;
+    readonly interestRate: string;
+    readonly compoundingFrequency: number;
+    readonly payoutFrequency: number;
+    readonly startDate: string;
+    readonly maturityDate: string;
+    readonly interestCategoryId: string;
+    readonly payoutAccountId: string;
+    readonly lastPayoutDate: string;
+    readonly nextPayoutDate: string;
+    readonly projectedMaturityValue: number;
+    readonly projectedTotalInterest: number;
+}
+
+export interface FixedIncomeMaturityInfoResponse {
+    readonly accountId: string;
+    readonly investmentId?: string;
+    readonly accountName: string;
+    readonly currency: string;
+    readonly principal: number;
+    readonly interestRate: string;
+    readonly maturityDate: string;
+    readonly daysToMaturity: number;
+    readonly projectedMaturityValue: number;
+    readonly projectedTotalInterest: number;
+}
+
+export interface FixedIncomeProjectionsResponse {
+    readonly maturities: FixedIncomeMaturityInfoResponse[];
+}
@@ -1,6 +1,11 @@
 import { TransactionType } from '@/core/transaction.ts';
 
-import type { TransactionCreateRequest, TransactionGeoLocationResponse } from './transaction.ts';
+import type {
+    TransactionCreateRequest,
+    TransactionGeoLocationResponse,
+    TransactionSplitInfoResponse,
+    TransactionSplitRequest
+} from './transaction.ts';
 
 export class ImportTransaction implements ImportTransactionResponse {
     public type: number;
@@ -18,6 +23,9 @@ export class ImportTransaction implements ImportTransactionResponse {
     public destinationAmount: number;
     public tagIds: string[];
     public originalTagNames: string[];
+    public payeeId: string;
+    public originalPayeeName: string;
+    public splits?: TransactionSplitInfoResponse[];
     public comment: string;
     public geoLocation?: TransactionGeoLocationResponse;
 
@@ -44,6 +52,9 @@ export class ImportTransaction implements ImportTransactionResponse {
         this.destinationAmount = response.destinationAmount || 0;
         this.tagIds = response.tagIds;
         this.originalTagNames = response.originalTagNames;
+        this.payeeId = response.payeeId || '';
+        this.originalPayeeName = response.originalPayeeName || '';
+        this.splits = response.splits;
         this.comment = response.comment;
         this.geoLocation = response.geoLocation;
 
@@ -69,11 +80,38 @@ export class ImportTransaction implements ImportTransactionResponse {
             tagIds: this.tagIds,
             pictureIds: [],
             comment: this.comment,
+            payeeId: this.type !== TransactionType.ModifyBalance && this.payeeId && this.payeeId !== '0' ? this.payeeId : undefined,
+            payeeName: this.type !== TransactionType.ModifyBalance && (!this.payeeId || this.payeeId === '0') && this.originalPayeeName ? this.originalPayeeName : undefined,
             geoLocation: this.geoLocation,
+            splits: this.getSplitRequests(),
             clientSessionId: ''
         };
     }
 
+    private getSplitRequests(): TransactionSplitRequest[] | undefined {
+        if ((this.type !== TransactionType.Income && this.type !== TransactionType.Expense) || !this.splits || !this.splits.length) {
+            return undefined;
+        }
+
+        let totalAmount = 0;
+
+        for (const split of this.splits) {
+            totalAmount += split.amount;
+        }
+
+        // the split lines are dropped if the amount has been changed in the preview
+        if (totalAmount !== this.sourceAmount) {
+            return undefined;
+        }
+
+        return this.splits.map(split => ({
+            categoryId: split.categoryId,
+            amount: split.amount,
+            comment: split.comment,
+            tagIds: split.tagIds
+        }));
+    }
+
     public isTransactionValid(): boolean {
         if (this.type !== TransactionType.ModifyBalance && (!this.categoryId || this.categoryId === '0')) {
             return false;
@@ -119,6 +157,9 @@ export interface ImportTransactionResponse {
     readonly destinationAmount?: number;
     readonly tagIds: string[];
     readonly originalTagNames: string[];
+    readonly payeeId?: string;
+    readonly originalPayeeName?: string;
+    readonly splits?: TransactionSplitInfoResponse[];
     readonly comment: string;
     readonly geoLocation?: TransactionGeoLocationResponse;
 }
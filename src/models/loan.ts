@@ -0,0 +1,85 @@
+export interface LoanTermSetRequest {
+    readonly accountId: string;
+    readonly principal: number;
+    readonly interestRate: string;
+    readonly termMonths: number;
+    readonly startDate: string;
+    readonly paymentDay: number;
+    readonly extraMonthlyPayment: number;
+    readonly interestCategoryId: string;
+}
+
+export interface LoanTermDeleteRequest {
+    readonly accountId: string;
+}
+
+export interface LoanExtraPayment {
+    readonly date: string;
+    readonly amount: number;
+}
+
+export interface LoanWhatIfRequest {
+    readonly accountId: string;
+    readonly extraMonthlyPayment: number;
+    readonly extraPayments?: LoanExtraPayment[];
+}
+
+export interface LoanPaymentCreateRequest {
+    readonly accountId: string;
+    readonly sourceAccountId: string;
+    readonly transferCategoryId: string;
+    readonly amount: number;
+    readonly time: number;
+    readonly utcOffset: number;
+    readonly comment: string;
+    readonly clientSessionId: string;
+}
+
+export interface LoanTermInfoResponse {
+    readonly accountId: string;
+    readonly principal: number;
+    readonly interestRate: string;
+    readonly termMonths: number;
+    readonly startDate: string;
+    readonly paymentDay: number;
+    readonly extraMonthlyPayment: number;
+    readonly interestCategoryId: string;
+}
+
+export interface LoanAmortizationScheduleItem {
+    readonly index: number;
+    readonly paymentDate: string;
+    readonly payment: number;
+    readonly principal: number;
+    readonly interest: number;
+    readonly extraPayment: number;
+    readonly remainingBalance: number;
+}
+
+export interface LoanAmortizationSummary {
+    readonly monthlyPayment: number;
+    readonly paymentCount: number;
+    readonly payoffDate: string;
+    readonly totalInterest: number;
+    readonly totalPayment: number;
+}
+
+export interface LoanAmortizationScheduleResponse {
+    readonly term: LoanTermInfoResponse;
+    readonly summary: LoanAmortizationSummary;
+    readonly schedule: LoanAmortizationScheduleItem[];
+}
+
+export interface LoanWhatIfResponse {
+    readonly baseline: LoanAmortizationSummary;
+    readonly scenario: LoanAmortizationSummary;
+    readonly interestSaved: number;
+    readonly paymentsSaved: number;
+}
+
+export interface LoanPaymentCreateResponse {
+    readonly principal: number;
+    readonly interest: number;
+    readonly transferTransactionId: string;
+    readonly interestTransactionId: string;
+}
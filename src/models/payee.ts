@@ -0,0 +1,79 @@
+export class Payee implements PayeeInfoResponse {
+    public id: string;
+    public name: string;
+    public aliases: string[];
+    public hidden: boolean;
+
+    private constructor(id: string, name: string, aliases: string[], hidden: boolean) {
+        this.id = id;
+        this.name = name;
+        this.aliases = aliases;
+        this.hidden = hidden;
+    }
+
+    public toCreateRequest(): PayeeCreateRequest {
+        return {
+            name: this.name,
+            aliases: this.aliases
+        };
+    }
+
+    public toModifyRequest(): PayeeModifyRequest {
+        return {
+            id: this.id,
+            name: this.name,
+            aliases: this.aliases
+        };
+    }
+
+    public static of(payeeResponse: PayeeInfoResponse): Payee {
+        return new Payee(payeeResponse.id, payeeResponse.name, payeeResponse.aliases || [], payeeResponse.hidden);
+    }
+
+    public static ofMulti(payeeResponses: PayeeInfoResponse[]): Payee[] {
+        const payees: Payee[] = [];
+
+        for (const payeeResponse of payeeResponses) {
+            payees.push(Payee.of(payeeResponse));
+        }
+
+        return payees;
+    }
+
+    public static createNewPayee(name?: string): Payee {
+        return new Payee('', name || '', [], false);
+    }
+}
+
+export interface PayeeCreateRequest {
+    readonly name: string;
+    readonly aliases: string[];
+}
+
+export interface PayeeModifyRequest {
+    readonly id: string;
+    readonly name: string;
+    readonly aliases: string[];
+}
+
+export interface PayeeHideRequest {
+    readonly id: string;
+    readonly hidden: boolean;
+}
+
+export interface PayeeMergeRequest {
+    readonly id: string;
+    readonly fromIds: string[];
+    readonly keepAliases: boolean;
+}
+
+export interface PayeeDeleteRequest {
+    readonly id: string;
+}
+
+export interface PayeeInfoResponse {
+    readonly id: string;
+    readonly name: string;
+    readonly aliases: string[];
+    readonly hidden: boolean;
+}
@@ -3,11 +3,13 @@ import type { ApplicationCloudSetting } from '@/core/setting.ts';
 import type { UserBasicInfo } from './user.ts';
 
 export const TOKEN_TYPE_MCP: number = 5;
+export const TOKEN_TYPE_PERSONAL_ACCESS: number = 6;
 
 export const TOKEN_CLI_USER_AGENT: string = 'ezbookkeeping Cli';
 
 export interface TokenGenerateMCPRequest {
     readonly password: string;
+    readonly readOnly: boolean;
 }
 
 export interface TokenRevokeRequest {
@@ -30,6 +32,9 @@ export interface TokenRefreshResponse {
 export interface TokenInfoResponse {
     readonly tokenId: string;
     readonly tokenType: number;
+    readonly readOnly?: boolean;
+    readonly name?: string;
+    readonly permissions?: string[];
     readonly userAgent: string;
     readonly lastSeen: number;
     readonly isCurrent: boolean;
@@ -41,18 +46,20 @@ export class SessionInfo {
     public readonly deviceType: string;
     public readonly deviceInfo: string;
     public readonly createdByCli: boolean;
+    public readonly readOnly: boolean;
     public readonly lastSeen: number;
 
-    protected constructor(tokenId: string, isCurrent: boolean, deviceType: string, deviceInfo: string, createdByCli: boolean, lastSeen: number) {
+    protected constructor(tokenId: string, isCurrent: boolean, deviceType: string, deviceInfo: string, createdByCli: boolean, readOnly: boolean, lastSeen: number) {
         this.tokenId = tokenId;
         this.isCurrent = isCurrent;
         this.deviceType = deviceType;
         this.deviceInfo = deviceInfo;
         this.createdByCli = createdByCli;
+        this.readOnly = readOnly;
         this.lastSeen = lastSeen;
     }
 
-    public static of(tokenId: string, isCurrent: boolean, deviceType: string, deviceInfo: string, createdByCli: boolean, lastSeen: number): SessionInfo {
-        return new SessionInfo(tokenId, isCurrent, deviceType, deviceInfo, createdByCli, lastSeen);
+    public static of(tokenId: string, isCurrent: boolean, deviceType: string, deviceInfo: string, createdByCli: boolean, readOnly: boolean, lastSeen: number): SessionInfo {
+        return new SessionInfo(tokenId, isCurrent, deviceType, deviceInfo, createdByCli, readOnly, lastSeen);
     }
 }
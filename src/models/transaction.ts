@@ -26,6 +26,7 @@ export class Transaction implements TransactionInfoResponse {
     public tagIds: string[];
     public comment: string;
     public editable: boolean;
+    public splits?: TransactionSplitInfoResponse[];
 
     private _pictures?: TransactionPicture[];
     private _geoLocation?: TransactionGeoLocation;
@@ -226,6 +227,25 @@ export class Transaction implements TransactionInfoResponse {
         this._displayDayOfWeek = displayDayOfWeek;
     }
 
+    public getSplitRequests(): TransactionSplitRequest[] | undefined {
+        if (!this.splits) {
+            return undefined;
+        }
+
+        const splitRequests: TransactionSplitRequest[] = [];
+
+        for (const split of this.splits) {
+            splitRequests.push({
+                categoryId: split.categoryId,
+                amount: split.amount,
+                comment: split.comment,
+                tagIds: split.tagIds
+            });
+        }
+
+        return splitRequests;
+    }
+
     public toCreateRequest(clientSessionId: string, actualTime?: number): TransactionCreateRequest {
         return {
             type: this.type,
@@ -265,7 +285,8 @@ export class Transaction implements TransactionInfoResponse {
             tagIds: this.tagIds,
             pictureIds: this.getPictureIds(),
             comment: this.comment,
-            geoLocation: this.getNormalizedGeoLocation()
+            geoLocation: this.getNormalizedGeoLocation(),
+            splits: this.getSplitRequests()
         };
     }
 
@@ -359,6 +380,10 @@ export class Transaction implements TransactionInfoResponse {
             transaction.setLatitudeAndLongitude(transactionResponse.geoLocation.latitude, transactionResponse.geoLocation.longitude);
         }
 
+        if (transactionResponse.splits) {
+            transaction.splits = transactionResponse.splits;
+        }
+
         return transaction;
     }
 
@@ -455,6 +480,13 @@ export interface TransactionGeoLocationRequest {
     readonly longitude: number;
 }
 
+export interface TransactionSplitRequest {
+    readonly categoryId: string;
+    readonly amount: number;
+    readonly comment: string;
+    readonly tagIds: string[];
+}
+
 export interface TransactionCreateRequest {
     readonly type: number;
     readonly categoryId: string;
@@ -468,7 +500,10 @@ export interface TransactionCreateRequest {
     readonly tagIds: string[];
     readonly pictureIds: string[];
     readonly comment: string;
+    readonly payeeId?: string;
+    readonly payeeName?: string;
     readonly geoLocation?: TransactionGeoLocationRequest;
+    readonly splits?: TransactionSplitRequest[];
     readonly clientSessionId: string;
 }
 
@@ -485,7 +520,9 @@ export interface TransactionModifyRequest {
     readonly tagIds: string[];
     readonly pictureIds: string[];
     readonly comment: string;
+    readonly payeeId?: string;
     readonly geoLocation?: TransactionGeoLocationRequest;
+    readonly splits?: TransactionSplitRequest[];
 }
 
 export interface TransactionDeleteRequest {
@@ -508,8 +545,10 @@ export interface TransactionListByMaxTimeRequest {
     readonly accountIds: string;
     readonly tagIds: string;
     readonly tagFilterType: number;
+    readonly payeeIds?: string;
     readonly amountFilter: string;
     readonly keyword: string;
+    readonly query?: string;
 }
 
 export interface TransactionListInMonthByPageRequest {
@@ -520,8 +559,10 @@ export interface TransactionListInMonthByPageRequest {
     readonly accountIds: string;
     readonly tagIds: string;
     readonly tagFilterType: number;
+    readonly payeeIds?: string;
     readonly amountFilter: string;
     readonly keyword: string;
+    readonly query?: string;
 }
 
 export interface TransactionReconciliationStatementRequest {
@@ -532,6 +573,14 @@ export interface TransactionReconciliationStatementRequest {
 
 export type TransactionGeoLocationResponse = Coordinate;
 
+export interface TransactionSplitInfoResponse {
+    readonly id: string;
+    readonly categoryId: string;
+    readonly amount: number;
+    readonly comment: string;
+    readonly tagIds: string[];
+}
+
 export interface TransactionInfoResponse {
     readonly id: string;
     readonly timeSequenceId: string;
@@ -551,7 +600,9 @@ export interface TransactionInfoResponse {
     readonly tags?: TransactionTagInfoResponse[];
     readonly pictures?: TransactionPictureInfoBasicResponse[];
     readonly comment: string;
+    readonly payeeId?: string;
     readonly geoLocation?: TransactionGeoLocationResponse;
+    readonly splits?: TransactionSplitInfoResponse[];
     readonly editable: boolean;
 }
 
@@ -560,6 +611,8 @@ export interface TransactionStatisticRequest {
     readonly endTime: number;
     readonly tagIds: string;
     readonly tagFilterType: number;
+    readonly payeeIds?: string;
+    readonly groupByPayee?: boolean;
     readonly keyword: string;
     readonly useTransactionTimezone: boolean;
 }
@@ -572,6 +625,8 @@ export interface YearMonthRangeRequest {
 export interface TransactionStatisticTrendsRequest extends YearMonthRangeRequest {
     readonly tagIds: string;
     readonly tagFilterType: number;
+    readonly payeeIds?: string;
+    readonly groupByPayee?: boolean;
     readonly keyword: string;
     readonly useTransactionTimezone: boolean;
 }
@@ -689,18 +744,24 @@ export interface TransactionStatisticResponse {
     readonly startTime: number;
     readonly endTime: number;
     readonly items: TransactionStatisticResponseItem[];
+    readonly reportingCurrency?: string;
+    readonly unconvertedCurrencies?: string[];
 }
 
 export interface TransactionStatisticResponseItem {
     readonly categoryId: string;
     readonly accountId: string;
+    readonly payeeId?: string;
     readonly amount: number;
+    readonly convertedAmount?: number;
 }
 
 export interface TransactionStatisticTrendsResponseItem {
     readonly year: number;
     readonly month: number; // 1-based (1 = January, 12 = December)
     readonly items: TransactionStatisticResponseItem[];
+    readonly reportingCurrency?: string;
+    readonly unconvertedCurrencies?: string[];
 }
 
 export interface YearMonthDataItem extends Year1BasedMonth, Record<string, unknown> {}
@@ -757,6 +818,10 @@ export interface TransactionAmountsResponseItem {
     readonly startTime: number;
     readonly endTime: number;
     readonly amounts: TransactionAmountsResponseItemAmountInfo[];
+    readonly reportingCurrency?: string;
+    readonly convertedIncomeAmount?: number;
+    readonly convertedExpenseAmount?: number;
+    readonly unconvertedCurrencies?: string[];
 }
 
 export interface TransactionAmountsResponseItemAmountInfo {
@@ -0,0 +1,17 @@
+export const USER_EXTERNAL_AUTH_TYPE_OIDC: number = 1;
+export const USER_EXTERNAL_AUTH_TYPE_LDAP: number = 2;
+
+export interface UserOIDCLinkRequest {
+    readonly platform: string;
+}
+
+export interface UserOIDCLinkResponse {
+    readonly url: string;
+}
+
+export interface UserExternalAuthInfoResponse {
+    readonly externalAuthType: number;
+    readonly externalUsername: string;
+    readonly externalEmail: string;
+    readonly createdAt: number;
+}
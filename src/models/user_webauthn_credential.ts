@@ -0,0 +1,69 @@
+export const TWO_FACTOR_METHOD_PASSCODE: string = 'passcode';
+export const TWO_FACTOR_METHOD_WEBAUTHN: string = 'webauthn';
+
+export interface WebAuthnServerCredentialDescriptor {
+    readonly id: string;
+    readonly type: string;
+    readonly transports?: string[];
+}
+
+export interface WebAuthnServerCredentialCreationOptions {
+    readonly publicKey: {
+        readonly challenge: string;
+        readonly user: {
+            readonly id: string;
+            readonly name: string;
+            readonly displayName: string;
+        };
+        readonly excludeCredentials?: WebAuthnServerCredentialDescriptor[];
+        readonly [key: string]: unknown;
+    };
+}
+
+export interface WebAuthnServerCredentialRequestOptions {
+    readonly publicKey: {
+        readonly challenge: string;
+        readonly allowCredentials?: WebAuthnServerCredentialDescriptor[];
+        readonly [key: string]: unknown;
+    };
+}
+
+export interface WebAuthnRegisterBeginResponse {
+    readonly options: WebAuthnServerCredentialCreationOptions;
+    readonly session: string;
+}
+
+export interface WebAuthnRegisterFinishRequest {
+    readonly name: string;
+    readonly session: string;
+    readonly credential: unknown;
+}
+
+export interface WebAuthnLoginBeginResponse {
+    readonly options: WebAuthnServerCredentialRequestOptions;
+    readonly session: string;
+}
+
+export interface WebAuthnLoginRequest {
+    readonly session: string;
+    readonly credential: unknown;
+}
+
+export interface WebAuthnCredentialModifyRequest {
+    readonly id: string;
+    readonly name: string;
+}
+
+export interface WebAuthnCredentialDeleteRequest {
+    readonly id: string;
+    readonly password: string;
+}
+
+export interface WebAuthnCredentialInfoResponse {
+    readonly id: string;
+    readonly name: string;
+    readonly attestationFormat: string;
+    readonly backupEligible: boolean;
+    readonly createdAt: number;
+    readonly lastUsedAt?: number;
+}
@@ -210,7 +210,11 @@ const router = createRouter({
         {
             path: '/login',
             component: LoginPage,
-            beforeEnter: checkNotLogin
+            beforeEnter: checkNotLogin,
+            props: route => ({
+                oidcToken: route.query['oidcToken'],
+                oidcError: route.query['oidcError']
+            })
         },
         {
             path: '/signup',
@@ -36,6 +36,7 @@ import {
     clearCurrentSessionToken,
     clearCurrentTokenAndUserInfo
 } from '@/lib/userstate.ts';
+import { getServerWebAuthnAssertion } from '@/lib/webauthn.ts';
 import services, { type ApiResponsePromise } from '@/lib/services.ts';
 import logger from '@/lib/logger.ts';
 
@@ -126,6 +127,55 @@ export const useRootStore = defineStore('root', () => {
         });
     }
 
+    function authorizeOIDC({ token }: { token: string }): Promise<AuthResponse> {
+        return new Promise((resolve, reject) => {
+            services.authorizeOIDC({ token }).then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result || !data.result.token) {
+                    reject({ message: 'Unable to log in' });
+                    return;
+                }
+
+                if (data.result.need2FA) {
+                    resolve(data.result);
+                    return;
+                }
+
+                if (settingsStore.appSettings.applicationLock || hasUserAppLockState()) {
+                    const appLockState = getUserAppLockState();
+
+                    if (!appLockState || appLockState.username !== data.result.user?.username) {
+                        clearCurrentTokenAndUserInfo(true);
+                        settingsStore.setEnableApplicationLock(false);
+                        settingsStore.setEnableApplicationLockWebAuthn(false);
+                        clearWebAuthnConfig();
+                    }
+                }
+
+                settingsStore.setApplicationSettingsFromCloudSettings(data.result.applicationCloudSettings);
+
+                updateCurrentToken(data.result.token);
+
+                if (data.result.user && isObject(data.result.user)) {
+                    userStore.storeUserBasicInfo(data.result.user);
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to login via oidc', error);
+
+                if (error && error.processed) {
+                    reject(error);
+                } else if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else {
+                    reject({ message: 'Unable to log in' });
+                }
+            });
+        });
+    }
+
     function authorize2FA({ token, passcode, recoveryCode }: { token: string, passcode: string | null, recoveryCode: string | null }): Promise<AuthResponse> {
         return new Promise((resolve, reject) => {
             let promise: ApiResponsePromise<AuthResponse>;
@@ -187,6 +237,75 @@ export const useRootStore = defineStore('root', () => {
         });
     }
 
+    function authorizeByPasskey({ token }: { token?: string }): Promise<AuthResponse> {
+        return new Promise((resolve, reject) => {
+            const beginPromise = token ? services.beginWebAuthn2FA({ token }) : services.beginWebAuthnLogin();
+
+            beginPromise.then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result || !data.result.options || !data.result.session) {
+                    return Promise.reject({ message: 'Unable to verify' });
+                }
+
+                const session = data.result.session;
+
+                return getServerWebAuthnAssertion(data.result.options).then(credential => {
+                    const req = {
+                        session: session,
+                        credential: JSON.parse(credential)
+                    };
+
+                    return token ? services.authorizeWebAuthn2FA({ req, token }) : services.authorizeWebAuthn(req);
+                });
+            }).then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result || !data.result.token) {
+                    reject({ message: 'Unable to verify' });
+                    return;
+                }
+
+                if (settingsStore.appSettings.applicationLock || hasUserAppLockState()) {
+                    const appLockState = getUserAppLockState();
+
+                    if (!appLockState || appLockState.username !== data.result.user?.username) {
+                        clearCurrentTokenAndUserInfo(true);
+                        settingsStore.setEnableApplicationLock(false);
+                        settingsStore.setEnableApplicationLockWebAuthn(false);
+                        clearWebAuthnConfig();
+                    }
+                }
+
+                settingsStore.setApplicationSettingsFromCloudSettings(data.result.applicationCloudSettings);
+
+                updateCurrentToken(data.result.token);
+
+                if (data.result.user && isObject(data.result.user)) {
+                    userStore.storeUserBasicInfo(data.result.user);
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to verify passkey', error);
+
+                if (error && error.processed) {
+                    reject(error);
+                } else if (error && error.notSupported) {
+                    reject({ message: 'This device does not support passkey' });
+                } else if (error && error.name === 'NotAllowedError') {
+                    reject({ message: 'User has cancelled passkey verification' });
+                } else if (error && error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (error && error.message && !error.name) {
+                    reject(error);
+                } else {
+                    reject({ message: 'Unable to verify' });
+                }
+            });
+        });
+    }
+
     function register({ user, presetCategories }: { user: User, presetCategories?: LocalizedPresetCategory[] }): Promise<RegisterResponse> {
         return new Promise((resolve, reject) => {
             services.register(user.toRegisterRequest(presetCategories)).then(response => {
@@ -549,7 +668,9 @@ export const useRootStore = defineStore('root', () => {
         // functions
         setNotificationContent,
         authorize,
+        authorizeOIDC,
         authorize2FA,
+        authorizeByPasskey,
         register,
         lock,
         logout,
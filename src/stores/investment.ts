@@ -13,8 +13,10 @@ import { stockPriceService } from '@/services/stockPrice.ts';
 
 export interface Investment {
     investmentId: string;
+    assetType?: number;
     tickerSymbol: string;
     companyName?: string;
+    walletAddress?: string;
     sharesOwned: number;
     avgCostPerShare: number;
     totalInvested: number;
@@ -232,8 +234,10 @@ export const useInvestmentStore = defineStore('investments', () => {
     }
 
     async function addInvestment(investmentData: {
+        assetType?: number;
         tickerSymbol: string;
         companyName?: string;
+        walletAddress?: string;
         shares: number;
         pricePerShare: number;
         fees: number;
@@ -249,8 +253,10 @@ export const useInvestmentStore = defineStore('investments', () => {
 
             const newInvestment: Investment = {
                 investmentId: Date.now().toString(), // Simple ID generation
+                assetType: investmentData.assetType,
                 tickerSymbol: investmentData.tickerSymbol.toUpperCase(),
                 companyName: investmentData.companyName,
+                walletAddress: investmentData.walletAddress,
                 sharesOwned: investmentData.shares,
                 avgCostPerShare: avgCostPerShareInCents,
                 totalInvested: totalInvestedInCents,
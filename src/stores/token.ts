@@ -69,9 +69,9 @@ export const useTokensStore = defineStore('tokens', () => {
         });
     }
 
-    function generateMCPToken({ password }: { password: string }): Promise<TokenGenerateMCPResponse> {
+    function generateMCPToken({ password, readOnly }: { password: string, readOnly: boolean }): Promise<TokenGenerateMCPResponse> {
         return new Promise((resolve, reject) => {
-            services.generateMCPToken({ password }).then(response => {
+            services.generateMCPToken({ password, readOnly }).then(response => {
                 const data = response.data;
 
                 if (!data || !data.success || !data.result) {
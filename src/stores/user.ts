@@ -21,6 +21,19 @@ import type {
     DataStatisticsResponse
 } from '@/models/data_management.ts';
 
+import type {
+    UserOIDCLinkResponse,
+    UserExternalAuthInfoResponse
+} from '@/models/user_external_auth.ts';
+
+import type {
+    WebAuthnCredentialInfoResponse
+} from '@/models/user_webauthn_credential.ts';
+
+import type {
+    AuditEventInfoPageWrapperResponse
+} from '@/models/audit_event.ts';
+
 import {
     isObject,
     isString,
@@ -33,6 +46,10 @@ import {
     clearCurrentUserInfo
 } from '@/lib/userstate.ts';
 
+import {
+    createServerWebAuthnCredential
+} from '@/lib/webauthn.ts';
+
 import logger from '@/lib/logger.ts';
 import services from '@/lib/services.ts';
 
@@ -352,6 +369,228 @@ export const useUserStore = defineStore('user', () => {
         });
     }
 
+    function getUserExternalAuths(): Promise<UserExternalAuthInfoResponse[]> {
+        return new Promise((resolve, reject) => {
+            services.getUserExternalAuths().then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to retrieve linked external accounts' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to retrieve linked external accounts', error);
+
+                if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (!error.processed) {
+                    reject({ message: 'Unable to retrieve linked external accounts' });
+                } else {
+                    reject(error);
+                }
+            });
+        });
+    }
+
+    function linkOIDCExternalAuth(): Promise<UserOIDCLinkResponse> {
+        return new Promise((resolve, reject) => {
+            services.linkOIDCExternalAuth({
+                platform: 'desktop'
+            }).then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to link external account' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to link external account', error);
+
+                if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (!error.processed) {
+                    reject({ message: 'Unable to link external account' });
+                } else {
+                    reject(error);
+                }
+            });
+        });
+    }
+
+    function unlinkOIDCExternalAuth(): Promise<boolean> {
+        return new Promise((resolve, reject) => {
+            services.unlinkOIDCExternalAuth().then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to unlink external account' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to unlink external account', error);
+
+                if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (!error.processed) {
+                    reject({ message: 'Unable to unlink external account' });
+                } else {
+                    reject(error);
+                }
+            });
+        });
+    }
+
+    function getWebAuthnCredentials(): Promise<WebAuthnCredentialInfoResponse[]> {
+        return new Promise((resolve, reject) => {
+            services.getWebAuthnCredentials().then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to retrieve passkey list' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to retrieve passkey list', error);
+
+                if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (!error.processed) {
+                    reject({ message: 'Unable to retrieve passkey list' });
+                } else {
+                    reject(error);
+                }
+            });
+        });
+    }
+
+    function getAuditEvents({ page, count }: { page: number, count: number }): Promise<AuditEventInfoPageWrapperResponse> {
+        return new Promise((resolve, reject) => {
+            services.getAuditEvents({ page, count }).then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to retrieve security log' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to retrieve security log', error);
+
+                if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (!error.processed) {
+                    reject({ message: 'Unable to retrieve security log' });
+                } else {
+                    reject(error);
+                }
+            });
+        });
+    }
+
+    function registerWebAuthnCredential({ name }: { name: string }): Promise<WebAuthnCredentialInfoResponse> {
+        return new Promise((resolve, reject) => {
+            services.beginRegisterWebAuthnCredential().then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result || !data.result.options || !data.result.session) {
+                    return Promise.reject({ message: 'Unable to add passkey' });
+                }
+
+                const session = data.result.session;
+
+                return createServerWebAuthnCredential(data.result.options).then(credential => {
+                    return services.finishRegisterWebAuthnCredential({
+                        name: name,
+                        session: session,
+                        credential: JSON.parse(credential)
+                    });
+                });
+            }).then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to add passkey' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to add passkey', error);
+
+                if (error && error.processed) {
+                    reject(error);
+                } else if (error && error.notSupported) {
+                    reject({ message: 'This device does not support passkey' });
+                } else if (error && error.name === 'NotAllowedError') {
+                    reject({ message: 'User has cancelled passkey verification' });
+                } else if (error && error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else {
+                    reject({ message: 'Unable to add passkey' });
+                }
+            });
+        });
+    }
+
+    function modifyWebAuthnCredential({ id, name }: { id: string, name: string }): Promise<boolean> {
+        return new Promise((resolve, reject) => {
+            services.modifyWebAuthnCredential({ id, name }).then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to rename passkey' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to rename passkey', error);
+
+                if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (!error.processed) {
+                    reject({ message: 'Unable to rename passkey' });
+                } else {
+                    reject(error);
+                }
+            });
+        });
+    }
+
+    function deleteWebAuthnCredential({ id, password }: { id: string, password: string }): Promise<boolean> {
+        return new Promise((resolve, reject) => {
+            services.deleteWebAuthnCredential({ id, password }).then(response => {
+                const data = response.data;
+
+                if (!data || !data.success || !data.result) {
+                    reject({ message: 'Unable to delete passkey' });
+                    return;
+                }
+
+                resolve(data.result);
+            }).catch(error => {
+                logger.error('failed to delete passkey', error);
+
+                if (error.response && error.response.data && error.response.data.errorMessage) {
+                    reject({ error: error.response.data });
+                } else if (!error.processed) {
+                    reject({ message: 'Unable to delete passkey' });
+                } else {
+                    reject(error);
+                }
+            });
+        });
+    }
+
     function getUserDataStatistics(): Promise<DataStatisticsResponse> {
         return new Promise((resolve, reject) => {
             services.getUserDataStatistics().then(response => {
@@ -387,6 +626,9 @@ export const useUserStore = defineStore('user', () => {
                     } else if (fileType === 'tsv' && response.headers['content-type'] !== 'text/tab-separated-values') {
                         reject({ message: 'Unable to retrieve exported user data' });
                         return;
+                    } else if (fileType === 'ledger' && response.headers['content-type'] !== 'text/plain') {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
                     }
                 }
 
@@ -459,6 +701,14 @@ export const useUserStore = defineStore('user', () => {
         getUserApplicationCloudSettings,
         fullUpdateUserApplicationCloudSettings,
         disableUserApplicationCloudSettings,
+        getUserExternalAuths,
+        linkOIDCExternalAuth,
+        unlinkOIDCExternalAuth,
+        getWebAuthnCredentials,
+        registerWebAuthnCredential,
+        modifyWebAuthnCredential,
+        deleteWebAuthnCredential,
+        getAuditEvents,
         getUserDataStatistics,
         getExportedUserData,
         getUserAvatarUrl
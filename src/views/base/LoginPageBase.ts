@@ -7,8 +7,10 @@ import { useSettingsStore } from '@/stores/setting.ts';
 import { useExchangeRatesStore } from '@/stores/exchangeRates.ts';
 
 import type { AuthResponse } from '@/models/auth_response.ts';
+import { TWO_FACTOR_METHOD_PASSCODE, TWO_FACTOR_METHOD_WEBAUTHN } from '@/models/user_webauthn_credential.ts';
 
-import { getLoginPageTips } from '@/lib/server_settings.ts';
+import { getLoginPageTips, isWebAuthnPasswordlessLoginEnabled } from '@/lib/server_settings.ts';
+import { isWebAuthnSupported } from '@/lib/webauthn.ts';
 import { getClientDisplayVersion } from '@/lib/version.ts';
 import { setExpenseAndIncomeAmountColor } from '@/lib/ui/common.ts';
 
@@ -27,13 +29,19 @@ export function useLoginPageBase() {
     const backupCode = ref<string>('');
     const tempToken = ref<string>('');
     const twoFAVerifyType = ref<string>('passcode');
+    const twoFactorMethods = ref<string[]>([]);
 
     const logining = ref<boolean>(false);
     const verifying = ref<boolean>(false);
 
     const inputIsEmpty = computed<boolean>(() => !username.value || !password.value);
+    const isPasscode2FAAvailable = computed<boolean>(() => twoFactorMethods.value.length < 1 || twoFactorMethods.value.includes(TWO_FACTOR_METHOD_PASSCODE));
+    const isPasskey2FAAvailable = computed<boolean>(() => twoFactorMethods.value.includes(TWO_FACTOR_METHOD_WEBAUTHN) && isWebAuthnSupported());
+    const isPasskeyLoginAvailable = computed<boolean>(() => isWebAuthnPasswordlessLoginEnabled() && isWebAuthnSupported());
     const twoFAInputIsEmpty = computed<boolean>(() => {
-        if (twoFAVerifyType.value === 'backupcode') {
+        if (twoFAVerifyType.value === 'passkey') {
+            return false;
+        } else if (twoFAVerifyType.value === 'backupcode') {
             return !backupCode.value;
         } else {
             return !passcode.value;
@@ -42,6 +50,16 @@ export function useLoginPageBase() {
 
     const tips = computed<string>(() => getServerTipContent(getLoginPageTips()));
 
+    function setTwoFactorMethods(authResponse: AuthResponse): void {
+        twoFactorMethods.value = authResponse.twoFactorMethods || [];
+
+        if (!isPasscode2FAAvailable.value && isPasskey2FAAvailable.value) {
+            twoFAVerifyType.value = 'passkey';
+        } else {
+            twoFAVerifyType.value = 'passcode';
+        }
+    }
+
     function doAfterLogin(authResponse: AuthResponse): void {
         if (authResponse.user) {
             const localeDefaultSettings = setLanguage(authResponse.user.language);
@@ -69,13 +87,18 @@ export function useLoginPageBase() {
         backupCode,
         tempToken,
         twoFAVerifyType,
+        twoFactorMethods,
         logining,
         verifying,
         // computed states
         inputIsEmpty,
+        isPasscode2FAAvailable,
+        isPasskey2FAAvailable,
+        isPasskeyLoginAvailable,
         twoFAInputIsEmpty,
         tips,
         // functions
+        setTwoFactorMethods,
         doAfterLogin
     }
 }